@@ -0,0 +1,5 @@
+package v1
+
+// Hub marks v1 as the conversion hub for APIMAPI. Every other served version converts to and from v1,
+// which is also the storage version.
+func (*APIMAPI) Hub() {}
@@ -12,14 +12,109 @@ type APIMAPITarget struct {
 	Selector *metav1.LabelSelector `json:"selector,omitempty"`
 }
 
+// AdoptionPolicy controls how the operator treats an API that already exists in APIM
+// before the operator has ever imported it.
+// +kubebuilder:validation:Enum=Adopt;Fail;Overwrite
+type AdoptionPolicy string
+
+const (
+	// AdoptionPolicyAdopt reads the current settings of the existing API back into status
+	// without importing or modifying it.
+	AdoptionPolicyAdopt AdoptionPolicy = "Adopt"
+	// AdoptionPolicyFail refuses to touch the existing API and reports a failed condition.
+	AdoptionPolicyFail AdoptionPolicy = "Fail"
+	// AdoptionPolicyOverwrite imports over the existing API as if it had been created by the operator.
+	AdoptionPolicyOverwrite AdoptionPolicy = "Overwrite"
+)
+
+// DeletionPolicy controls what happens to the API in APIM when its APIMAPI is deleted.
+// +kubebuilder:validation:Enum=Delete;Orphan
+type DeletionPolicy string
+
+const (
+	// DeletionPolicyDelete deletes the API, its revisions and its product and tag links from APIM.
+	DeletionPolicyDelete DeletionPolicy = "Delete"
+	// DeletionPolicyOrphan leaves the API in APIM.
+	DeletionPolicyOrphan DeletionPolicy = "Orphan"
+)
+
+// APIMAPICanary configures canary rollouts. Each import of a changed API creates a new revision that is not
+// current, checks it through the gateway and only then makes it current. When the check fails, the previous
+// revision stays current.
+type APIMAPICanary struct {
+	// SmokeCheck is the HTTP probe sent to the new revision through the gateway.
+	SmokeCheck APIMAPISmokeCheck `json:"smokeCheck"`
+}
+
+// APIMAPISmokeCheck is an HTTP GET request sent to a revision through the gateway.
+type APIMAPISmokeCheck struct {
+	// Path is requested relative to the URL of the revision, e.g. "/health" requests
+	// https://<gateway><routePrefix>;rev=<n>/health.
+	// +kubebuilder:validation:XValidation:rule="self.startsWith('/')",message="path must start with '/'"
+	Path string `json:"path"`
+	// ExpectedStatus is the HTTP status the revision must answer with. Defaults to 200.
+	// +kubebuilder:validation:Minimum=100
+	// +kubebuilder:validation:Maximum=599
+	// +optional
+	ExpectedStatus int32 `json:"expectedStatus,omitempty"`
+	// Attempts is how often the probe is sent before the revision is rolled back. Defaults to 3.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=10
+	// +optional
+	Attempts int32 `json:"attempts,omitempty"`
+	// Timeout bounds each probe. Defaults to 10s.
+	// +optional
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+	// SubscriptionKeySecretRef selects a subscription key in a Secret of the APIMAPI's namespace. It is sent
+	// in the Ocp-Apim-Subscription-Key header, for APIs that require a subscription.
+	// +optional
+	SubscriptionKeySecretRef *SecretKeyRef `json:"subscriptionKeySecretRef,omitempty"`
+}
+
+// BackendColor names one of the two backends of a blue/green APIMAPI.
+// +kubebuilder:validation:Enum=Blue;Green
+type BackendColor string
+
+const (
+	// BackendBlue selects backends.blue.
+	BackendBlue BackendColor = "Blue"
+	// BackendGreen selects backends.green.
+	BackendGreen BackendColor = "Green"
+)
+
+// APIMAPIBackends declares the two backend URLs of a blue/green deployment.
+type APIMAPIBackends struct {
+	// Blue is the URL of the blue backend.
+	// +kubebuilder:validation:MinLength=1
+	Blue string `json:"blue"`
+	// Green is the URL of the green backend.
+	// +kubebuilder:validation:MinLength=1
+	Green string `json:"green"`
+}
+
 // APIMAPISpec defines the desired state of APIMAPI.
 // This spec contains the configuration needed to import and manage an API in Azure API Management.
+// +kubebuilder:validation:XValidation:rule="has(self.backends) == has(self.activeBackend)",message="backends and activeBackend must be set together"
+// +kubebuilder:validation:XValidation:rule="has(self.backends) != (has(self.serviceUrl) && size(self.serviceUrl) > 0)",message="exactly one of serviceUrl and backends must be set"
+// +kubebuilder:validation:XValidation:rule="has(self.versionSetId) == has(self.apiVersion)",message="versionSetId and apiVersion must be set together"
 type APIMAPISpec struct {
 	// ServiceURL is the backend service URL that APIM will proxy requests to.
+	// It is left empty when Backends declares blue/green backends.
+	// +optional
 	ServiceURL string `json:"serviceUrl"`
+	// Backends declares blue and green backend URLs. APIM proxies to the one ActiveBackend selects.
+	// +optional
+	Backends *APIMAPIBackends `json:"backends,omitempty"`
+	// ActiveBackend selects the backend of Backends that APIM proxies to. Switching it only patches the
+	// service URL of the API in APIM, so reverting the commit that switched it rolls the switch back.
+	// +optional
+	ActiveBackend BackendColor `json:"activeBackend,omitempty"`
 	// RoutePrefix is the base route path in APIM (e.g., "/myapi").
+	// The defaulting webhook sets it to /<metadata.name> when omitted.
+	// +kubebuilder:validation:XValidation:rule="self.startsWith('/')",message="routePrefix must start with '/'"
 	RoutePrefix string `json:"routePrefix"`
 	// OpenAPIDefinitionURL is the URL where the OpenAPI/Swagger definition can be fetched.
+	// The defaulting webhook sets it to <serviceUrl>/swagger/v1/swagger.json when omitted.
 	OpenAPIDefinitionURL string `json:"openApiDefinitionUrl"`
 	// Target optionally selects which ReplicaSets should trigger imports for this API.
 	// If omitted, the operator falls back to matching metadata.name with the
@@ -33,14 +128,112 @@ type APIMAPISpec struct {
 	TagIDs []string `json:"tagIds,omitempty"`
 	// APIMService is the name of the APIMService custom resource that references
 	// the Azure API Management service instance.
+	// It is immutable; moving an API to another APIM service requires deleting and recreating the APIMAPI.
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="apimService is immutable"
 	APIMService string `json:"apimService"`
 	// APIID is the unique identifier for the API in Azure APIM.
+	// It is immutable, since changing it would orphan the API imported under the old ID.
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="APIID is immutable"
 	APIID string `json:"APIID"`
 	// SubscriptionRequired controls whether a subscription key is required to access the API.
 	// If set to false, the API can be accessed without a subscription key.
 	// If not specified, defaults to true (subscription required).
 	// +kubebuilder:default=true
 	SubscriptionRequired bool `json:"subscriptionRequired"`
+	// AdoptionPolicy controls what happens when APIID already exists in APIM and the operator
+	// has not imported it before. Adopt reads the existing settings into status without importing,
+	// Fail sets a failed condition, and Overwrite imports over the existing API.
+	// +kubebuilder:default=Overwrite
+	// +optional
+	AdoptionPolicy AdoptionPolicy `json:"adoptionPolicy,omitempty"`
+	// DeletionPolicy controls whether deleting the APIMAPI deletes the API from APIM. Delete removes the API,
+	// its revisions and its product and tag links, but only if the operator imported it; Orphan leaves it.
+	// +kubebuilder:default=Delete
+	// +optional
+	DeletionPolicy DeletionPolicy `json:"deletionPolicy,omitempty"`
+	// ApprovalRequired enables two-phase deployments. The operator first writes a plan of the
+	// pending changes to status.pendingPlan and only applies it after the APIMAPI is annotated
+	// with apim.operator.io/approve set to the plan hash.
+	// +optional
+	ApprovalRequired bool `json:"approvalRequired,omitempty"`
+	// Owner is the Backstage entity reference of the team that owns the API, e.g. "group:default/payments".
+	// When set, the operator publishes the API's catalog metadata in backstage.apim.operator.io/* annotations.
+	// +optional
+	Owner string `json:"owner,omitempty"`
+	// Canary makes every import of a changed API a canary rollout: the definition is imported into a new
+	// revision, which becomes current only after its smoke check passed.
+	// +optional
+	Canary *APIMAPICanary `json:"canary,omitempty"`
+	// VersionSetID is the ID of the APIM version set the API is a version of, e.g. the versionSetId of an
+	// APIMApiVersionSet. The version set must exist in APIM before the API is imported.
+	// +optional
+	VersionSetID string `json:"versionSetId,omitempty"`
+	// APIVersion is the version of the API within its version set, e.g. "v2". With the Segment versioning
+	// scheme, clients call the version at <routePrefix>/<apiVersion>.
+	// +kubebuilder:validation:MaxLength=100
+	// +optional
+	APIVersion string `json:"apiVersion,omitempty"`
+}
+
+// APIMAPIPlan describes the changes that are waiting for approval before being applied to APIM.
+type APIMAPIPlan struct {
+	// Hash identifies the plan. Annotate the APIMAPI with apim.operator.io/approve=<hash> to apply it.
+	Hash string `json:"hash"`
+	// Changes lists the differences between the last applied state and the desired state.
+	Changes []string `json:"changes,omitempty"`
+	// CreatedAt is the timestamp when the plan was computed.
+	CreatedAt string `json:"createdAt,omitempty"`
+}
+
+// APIMAPIAdoptedState captures the settings of a pre-existing API that were read back from APIM
+// when the API was adopted instead of imported.
+type APIMAPIAdoptedState struct {
+	// AdoptedAt is the timestamp when the existing API settings were last read from APIM.
+	AdoptedAt string `json:"adoptedAt,omitempty"`
+	// DisplayName is the display name of the existing API.
+	DisplayName string `json:"displayName,omitempty"`
+	// Path is the route path of the existing API.
+	Path string `json:"path,omitempty"`
+	// ServiceURL is the backend service URL of the existing API.
+	ServiceURL string `json:"serviceUrl,omitempty"`
+	// SubscriptionRequired reports whether the existing API requires a subscription key.
+	SubscriptionRequired bool `json:"subscriptionRequired,omitempty"`
+	// APIRevision is the current revision of the existing API.
+	APIRevision string `json:"apiRevision,omitempty"`
+}
+
+// APIMAPIDeploymentRecord records one attempt to deploy the API to APIM.
+type APIMAPIDeploymentRecord struct {
+	// Timestamp is when the deployment attempt finished. Repeated identical failures only move it forward.
+	Timestamp string `json:"timestamp"`
+	// SpecHash is the hash of the desired APIM state that was deployed.
+	SpecHash string `json:"specHash,omitempty"`
+	// Revision is the APIM revision that was targeted, if any.
+	Revision string `json:"revision,omitempty"`
+	// Outcome is Succeeded, Failed or DryRun.
+	Outcome string `json:"outcome"`
+	// ReplicaSet is the ReplicaSet whose rollout triggered the deployment.
+	ReplicaSet string `json:"replicaSet,omitempty"`
+}
+
+// APIMAPIDocumentation is the documentation metadata of an imported OpenAPI definition. The description of
+// the API in APIM is info.description followed by a link to externalDocs; operation summaries become the
+// names of the operations in the developer portal.
+type APIMAPIDocumentation struct {
+	// Title is info.title of the definition.
+	// +optional
+	Title string `json:"title,omitempty"`
+	// ExternalDocsURL is the URL of externalDocs in the definition.
+	// +optional
+	ExternalDocsURL string `json:"externalDocsUrl,omitempty"`
+	// Operations is the number of operations in the definition.
+	// +optional
+	Operations int32 `json:"operations,omitempty"`
+	// UndocumentedOperations lists up to 20 operations without a summary, e.g. "GET /orders/{id}". The
+	// developer portal names them after their operationId or their path.
+	// +listType=atomic
+	// +optional
+	UndocumentedOperations []string `json:"undocumentedOperations,omitempty"`
 }
 
 // APIMAPIStatus defines the observed state of APIMAPI.
@@ -54,10 +247,83 @@ type APIMAPIStatus struct {
 	ApiHost string `json:"apiHost"`
 	// DeveloperPortalHost is the URL of the APIM developer portal.
 	DeveloperPortalHost string `json:"developerPortalHost"`
+	// DeveloperPortalURL is the page of this API in the APIM developer portal
+	// (e.g., "https://myapim.developer.azure-api.net/api-details#api=orders").
+	// +optional
+	DeveloperPortalURL string `json:"developerPortalUrl,omitempty"`
+	// DefinitionURL is the URL of the OpenAPI definition that was last imported.
+	DefinitionURL string `json:"definitionUrl,omitempty"`
+	// Documentation is the documentation metadata of the OpenAPI definition that was last imported.
+	// +optional
+	Documentation *APIMAPIDocumentation `json:"documentation,omitempty"`
+	// AzureResourceID is the full Azure Resource Manager ID of the API in APIM.
+	AzureResourceID string `json:"azureResourceId,omitempty"`
+	// TerraformImports lists the IDs Terraform imports the API and its product and tag links by.
+	// +listType=atomic
+	// +optional
+	TerraformImports []TerraformImport `json:"terraformImports,omitempty"`
+	// Adopted holds the settings read back from APIM when the API was adopted rather than imported.
+	Adopted *APIMAPIAdoptedState `json:"adopted,omitempty"`
+	// PendingPlan holds the plan awaiting approval when spec.approvalRequired is true.
+	PendingPlan *APIMAPIPlan `json:"pendingPlan,omitempty"`
+	// History lists the most recent deployments to APIM, oldest first. Its length is bounded by the
+	// operator's --status-history-limit flag.
+	// +listType=atomic
+	// +optional
+	History []APIMAPIDeploymentRecord `json:"history,omitempty"`
+	// Conditions represent the latest available observations of the API's state.
+	// +listType=map
+	// +listMapKey=type
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+	// ObservedGeneration is the generation of the APIMAPI that the Ready condition was last computed for.
+	// A lower value than metadata.generation means the latest spec has not been synced yet.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// Backend records the active blue/green backend and the one it replaced.
+	// +optional
+	Backend *APIMAPIBackendStatus `json:"backend,omitempty"`
+}
+
+// APIMAPIBackendStatus records the last blue/green backend switch that reached APIM.
+type APIMAPIBackendStatus struct {
+	// Active is the backend APIM proxies to.
+	Active BackendColor `json:"active,omitempty"`
+	// ServiceURL is the URL of the active backend.
+	ServiceURL string `json:"serviceUrl,omitempty"`
+	// Previous is the backend that was active before the last switch.
+	// +optional
+	Previous BackendColor `json:"previous,omitempty"`
+	// PreviousServiceURL is the URL of the previous backend.
+	// +optional
+	PreviousServiceURL string `json:"previousServiceUrl,omitempty"`
+	// SwitchedAt is the RFC 3339 timestamp of the last switch.
+	// +optional
+	SwitchedAt string `json:"switchedAt,omitempty"`
+}
+
+// ActiveServiceURL returns the backend URL APIM proxies to: the URL of the active backend when blue/green
+// backends are declared, and ServiceURL otherwise.
+func (s *APIMAPISpec) ActiveServiceURL() string {
+	if s.Backends == nil {
+		return s.ServiceURL
+	}
+	if s.ActiveBackend == BackendGreen {
+		return s.Backends.Green
+	}
+	return s.Backends.Blue
 }
 
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
+// +kubebuilder:resource:shortName=aapi,categories=apim
+// +kubebuilder:printcolumn:name="Phase",type=string,JSONPath=`.status.status`
+// +kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+// +kubebuilder:printcolumn:name="ApiHost",type=string,JSONPath=`.status.apiHost`
+// +kubebuilder:printcolumn:name="APIMService",type=string,JSONPath=`.spec.apimService`
+// +kubebuilder:printcolumn:name="API ID",type=string,JSONPath=`.spec.APIID`,priority=1
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+// +kubebuilder:storageversion
 
 // APIMAPI is the Schema for the apimapis API.
 // APIMAPI is a Kubernetes custom resource that represents an API in Azure API Management.
@@ -30,24 +30,90 @@ type APIMAPIDeploymentSpec struct {
 	// APIID is the unique identifier for the API in Azure APIM.
 	APIID string `json:"APIID"`
 	// Revision is an optional API revision number. If specified, a new revision will be created.
+	// APIM only accepts positive integers.
+	// +kubebuilder:validation:XValidation:rule="self.matches('^[1-9][0-9]*$')",message="revision must be a positive integer"
 	Revision string `json:"revision,omitempty"`
 	// SubscriptionRequired controls whether a subscription key is required to access the API.
 	// If set to false, the API can be accessed without a subscription key.
 	// If not specified, defaults to true (subscription required).
 	// +kubebuilder:default=true
 	SubscriptionRequired bool `json:"subscriptionRequired"`
+	// AdoptionPolicy controls how an API that already exists in APIM is handled on first import.
+	// +optional
+	AdoptionPolicy AdoptionPolicy `json:"adoptionPolicy,omitempty"`
+	// ApprovalRequired holds changes until the source APIMAPI approves the computed plan.
+	// +optional
+	ApprovalRequired bool `json:"approvalRequired,omitempty"`
+	// Canary configures canary rollouts, as copied from the APIMAPI.
+	// +optional
+	Canary *APIMAPICanary `json:"canary,omitempty"`
+	// VersionSetID is the ID of the APIM version set the API is imported into, as copied from the APIMAPI.
+	// +optional
+	VersionSetID string `json:"versionSetId,omitempty"`
+	// APIVersion is the version of the API within its version set, as copied from the APIMAPI.
+	// +optional
+	APIVersion string `json:"apiVersion,omitempty"`
+}
+
+// APIMAPIDeploymentAppliedState is a snapshot of the inputs that were last applied to APIM.
+// It is used to describe pending changes when approval is required.
+type APIMAPIDeploymentAppliedState struct {
+	// ServiceURL is the backend service URL that was applied.
+	ServiceURL string `json:"serviceUrl,omitempty"`
+	// RoutePrefix is the route prefix that was applied.
+	RoutePrefix string `json:"routePrefix,omitempty"`
+	// Revision is the API revision that was applied.
+	Revision string `json:"revision,omitempty"`
+	// SubscriptionRequired is the subscription requirement that was applied.
+	SubscriptionRequired bool `json:"subscriptionRequired,omitempty"`
+	// ProductIDs are the products the API was assigned to.
+	ProductIDs []string `json:"productIds,omitempty"`
+	// TagIDs are the tags that were applied to the API.
+	TagIDs []string `json:"tagIds,omitempty"`
+	// OpenAPIHash is the hash of the OpenAPI document that was imported.
+	OpenAPIHash string `json:"openApiHash,omitempty"`
+	// VersionSetID is the version set the API was imported into.
+	VersionSetID string `json:"versionSetId,omitempty"`
+	// APIVersion is the version the API was imported as.
+	APIVersion string `json:"apiVersion,omitempty"`
+}
+
+// APIMAPIDeploymentErrorDetails describes the most recent failed APIM call in the form Azure support asks for.
+type APIMAPIDeploymentErrorDetails struct {
+	// Step is the reconciliation step that failed, e.g. import, service_url or product_assignment.
+	Step string `json:"step,omitempty"`
+	// Reason classifies the failure, e.g. AuthFailed or Throttled. It matches the reason of the
+	// APIMAPI Ready condition and of the Warning Event recorded for the failure.
+	// +kubebuilder:validation:Enum=SpecFetchFailed;SpecInvalid;AuthFailed;Throttled;ConflictingPolicy;ModifiedOutsideOperator;AzureError;SmokeCheckFailed
+	// +optional
+	Reason string `json:"reason,omitempty"`
+	// HTTPStatus is the HTTP status code returned by the Azure management API.
+	// +optional
+	HTTPStatus int32 `json:"httpStatus,omitempty"`
+	// Code is the Azure error code, e.g. ValidationError.
+	// +optional
+	Code string `json:"code,omitempty"`
+	// RequestID is the x-ms-request-id of the failed request.
+	// +optional
+	RequestID string `json:"requestId,omitempty"`
+	// OccurredAt is the RFC 3339 timestamp of the failure.
+	OccurredAt string `json:"occurredAt,omitempty"`
 }
 
 // APIMAPIDeploymentStatus defines the observed state of APIMAPIDeployment.
 // This status tracks the deployment progress and result.
 type APIMAPIDeploymentStatus struct {
 	// Phase indicates the current reconciliation phase.
-	// Typical values are WaitingForMatch, WaitingForReadyPod, Importing, Succeeded, and Error.
+	// Typical values are WaitingForMatch, WaitingForReadyPod, Importing, Succeeded, NoChange, and Error.
 	Phase string `json:"phase,omitempty"`
 	// Message describes the current reconciliation state in a human-readable way.
 	Message string `json:"message,omitempty"`
 	// LastError contains the most recent reconciliation error, if any.
 	LastError string `json:"lastError,omitempty"`
+	// LastErrorDetails holds the HTTP status, Azure error code and request ID behind LastError.
+	// HTTPStatus, Code and RequestID are only set when Azure answered the failed call.
+	// +optional
+	LastErrorDetails *APIMAPIDeploymentErrorDetails `json:"lastErrorDetails,omitempty"`
 	// LastAttemptAt is the timestamp of the most recent reconciliation attempt.
 	LastAttemptAt string `json:"lastAttemptAt,omitempty"`
 	// ObservedGeneration is the APIMAPI generation that this deployment status reflects.
@@ -60,14 +126,54 @@ type APIMAPIDeploymentStatus struct {
 	DesiredHash string `json:"desiredHash,omitempty"`
 	// AppliedHash is the desired hash that was last successfully reconciled in APIM.
 	AppliedHash string `json:"appliedHash,omitempty"`
+	// AppliedRolloutHash is the pod template hash of the ReplicaSet rollout behind AppliedHash.
+	// Later rollouts with the same hash are skipped by the ReplicaSet watcher.
+	// +optional
+	AppliedRolloutHash string `json:"appliedRolloutHash,omitempty"`
+	// APIETag is the ETag of the API in APIM after the last successful deployment. The next import sends it
+	// in If-Match, so edits made in APIM in the meantime are detected instead of silently overwritten.
+	// +optional
+	APIETag string `json:"apiETag,omitempty"`
+	// OpenAPIFetchFailures counts the failed OpenAPI definition fetches in a row. The operator retries
+	// with backoff until it reaches the retry limit, then reports the deployment as failed.
+	// +optional
+	OpenAPIFetchFailures int32 `json:"openApiFetchFailures,omitempty"`
+	// AppliedState is a snapshot of the inputs behind AppliedHash.
+	AppliedState *APIMAPIDeploymentAppliedState `json:"appliedState,omitempty"`
 	// ImportedAt is the timestamp when the API was successfully imported into APIM.
 	ImportedAt string `json:"importedAt,omitempty"`
 	// Status indicates the current deployment status (e.g., "OK", "Error").
 	Status string `json:"status,omitempty"`
+	// Canary describes the last canary rollout.
+	// +optional
+	Canary *APIMAPIDeploymentCanaryStatus `json:"canary,omitempty"`
+}
+
+// APIMAPIDeploymentCanaryStatus describes the outcome of a canary rollout.
+type APIMAPIDeploymentCanaryStatus struct {
+	// Revision is the revision the rollout was imported into.
+	Revision string `json:"revision,omitempty"`
+	// Result is Promoted when the revision was made current, or RolledBack when its smoke check failed
+	// and the previous revision stayed current.
+	Result string `json:"result,omitempty"`
+	// Message describes the result of the smoke check.
+	Message string `json:"message,omitempty"`
+	// CheckedAt is the RFC 3339 timestamp of the smoke check.
+	CheckedAt string `json:"checkedAt,omitempty"`
+	// RolloutKey identifies the desired state and ReplicaSet rollout of a rolled back revision. The same
+	// rollout is not retried; a spec change or a new rollout is.
+	// +optional
+	RolloutKey string `json:"rolloutKey,omitempty"`
 }
 
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
+// +kubebuilder:resource:shortName=aapid,categories=apim
+// +kubebuilder:printcolumn:name="Phase",type=string,JSONPath=`.status.phase`
+// +kubebuilder:printcolumn:name="APIMService",type=string,JSONPath=`.spec.apimService`
+// +kubebuilder:printcolumn:name="API ID",type=string,JSONPath=`.spec.APIID`,priority=1
+// +kubebuilder:printcolumn:name="Message",type=string,JSONPath=`.status.message`,priority=1
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
 
 // APIMAPIDeployment is the Schema for the APIMAPIDeployments API
 type APIMAPIDeployment struct {
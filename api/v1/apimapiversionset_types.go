@@ -0,0 +1,121 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// APIMApiVersionSetSpec defines the desired state of APIMApiVersionSet.
+// A version set groups the versions of an API, e.g. v1 and v2, and defines how clients select a version.
+// APIMAPI resources join a set with versionSetId and apiVersion.
+// +kubebuilder:validation:XValidation:rule="self.versioningScheme != 'Header' || has(self.versionHeaderName)",message="versionHeaderName is required for the Header versioning scheme"
+// +kubebuilder:validation:XValidation:rule="self.versioningScheme != 'Query' || has(self.versionQueryName)",message="versionQueryName is required for the Query versioning scheme"
+type APIMApiVersionSetSpec struct {
+	// APIMService is the name of the APIMService custom resource
+	APIMService string `json:"apimService"`
+
+	// VersionSetID is the unique identifier for the version set in APIM
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=80
+	// +kubebuilder:validation:Pattern=`^[^*#&+:<>?]+$`
+	VersionSetID string `json:"versionSetId"`
+
+	// DisplayName is the name shown in the APIM UI and developer portal
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=100
+	DisplayName string `json:"displayName"`
+
+	// Description is shown in the APIM UI
+	// +optional
+	Description string `json:"description,omitempty"`
+
+	// VersioningScheme is where clients put the API version in requests: a path segment after the API path,
+	// a request header or a query parameter.
+	// +kubebuilder:validation:Enum=Segment;Header;Query
+	// +kubebuilder:default=Segment
+	// +optional
+	VersioningScheme string `json:"versioningScheme,omitempty"`
+
+	// VersionHeaderName is the request header holding the version, for the Header versioning scheme.
+	// +optional
+	VersionHeaderName string `json:"versionHeaderName,omitempty"`
+
+	// VersionQueryName is the query parameter holding the version, for the Query versioning scheme.
+	// +optional
+	VersionQueryName string `json:"versionQueryName,omitempty"`
+}
+
+// APIMApiVersionSetStatus defines the observed state of APIMApiVersionSet.
+type APIMApiVersionSetStatus struct {
+	// Phase indicates lifecycle state like "Created" or "Error"
+	Phase string `json:"phase,omitempty"`
+
+	// Message contains error details or status context
+	Message string `json:"message,omitempty"`
+
+	// AzureResourceID is the full Azure Resource Manager ID of the version set in APIM
+	AzureResourceID string `json:"azureResourceId,omitempty"`
+
+	// TerraformImports lists the IDs Terraform imports the version set by.
+	// +listType=atomic
+	// +optional
+	TerraformImports []TerraformImport `json:"terraformImports,omitempty"`
+
+	// Conditions represent the latest available observations of the version set's state.
+	// The Ready condition is True once the last sync to APIM succeeded.
+	// +listType=map
+	// +listMapKey=type
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+	// ObservedGeneration is the generation of the version set that the Ready condition was last computed for.
+	// A lower value than metadata.generation means the latest spec has not been synced yet.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:shortName=avs,categories=apim
+// +kubebuilder:printcolumn:name="Phase",type=string,JSONPath=`.status.phase`
+// +kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+// +kubebuilder:printcolumn:name="APIMService",type=string,JSONPath=`.spec.apimService`
+// +kubebuilder:printcolumn:name="Version Set ID",type=string,JSONPath=`.spec.versionSetId`,priority=1
+// +kubebuilder:printcolumn:name="Scheme",type=string,JSONPath=`.spec.versioningScheme`,priority=1
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// APIMApiVersionSet is the Schema for the apimapiversionsets API.
+type APIMApiVersionSet struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   APIMApiVersionSetSpec   `json:"spec,omitempty"`
+	Status APIMApiVersionSetStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// APIMApiVersionSetList contains a list of APIMApiVersionSet.
+type APIMApiVersionSetList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []APIMApiVersionSet `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&APIMApiVersionSet{}, &APIMApiVersionSetList{})
+}
@@ -0,0 +1,216 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// APIMBackendSpec defines the desired state of APIMBackend.
+// A backend is a named upstream service that policies route requests to with
+// <set-backend-service backend-id="..." />, instead of a service URL set on the API.
+type APIMBackendSpec struct {
+	// APIMService is the name of the APIMService custom resource
+	APIMService string `json:"apimService"`
+
+	// BackendID is the unique identifier for the backend in APIM, used as backend-id in policies
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=80
+	// +kubebuilder:validation:Pattern=`^[^*#&+:<>?]+$`
+	BackendID string `json:"backendId"`
+
+	// URL is the runtime URL of the backend
+	// +kubebuilder:validation:Pattern=`^https?://`
+	URL string `json:"url"`
+
+	// Protocol is the protocol of the backend
+	// +kubebuilder:validation:Enum=http;soap
+	// +kubebuilder:default=http
+	// +optional
+	Protocol string `json:"protocol,omitempty"`
+
+	// Title is the name shown in the APIM UI
+	// +optional
+	Title string `json:"title,omitempty"`
+
+	// Description is shown in the APIM UI
+	// +optional
+	Description string `json:"description,omitempty"`
+
+	// Credentials are sent to the backend with every request.
+	// +optional
+	Credentials *APIMBackendCredentials `json:"credentials,omitempty"`
+
+	// TLS controls how APIM validates the certificate of the backend.
+	// +optional
+	TLS *APIMBackendTLS `json:"tls,omitempty"`
+
+	// CircuitBreaker stops APIM from sending requests to a failing backend for a while.
+	// Requires the operator to use APIM API version 2024-05-01 or later.
+	// +optional
+	CircuitBreaker *APIMBackendCircuitBreaker `json:"circuitBreaker,omitempty"`
+}
+
+// APIMBackendCredentials are the credentials APIM presents to a backend. Values are stored in APIM as they are
+// written here, so reference secrets as named values, e.g. {{backend-key}}, instead of writing them inline.
+type APIMBackendCredentials struct {
+	// Header sets request headers, by name.
+	// +optional
+	Header map[string][]string `json:"header,omitempty"`
+
+	// Query sets query parameters, by name.
+	// +optional
+	Query map[string][]string `json:"query,omitempty"`
+
+	// Authorization sets the Authorization header.
+	// +optional
+	Authorization *APIMBackendAuthorization `json:"authorization,omitempty"`
+
+	// CertificateIDs are the resource IDs of client certificates of the APIM service that APIM presents to the
+	// backend.
+	// +listType=atomic
+	// +optional
+	CertificateIDs []string `json:"certificateIds,omitempty"`
+}
+
+// APIMBackendAuthorization is the Authorization header of backend requests.
+type APIMBackendAuthorization struct {
+	// Scheme is the authentication scheme, e.g. Basic or Bearer.
+	// +kubebuilder:validation:MinLength=1
+	Scheme string `json:"scheme"`
+
+	// Parameter is the value after the scheme.
+	// +kubebuilder:validation:MinLength=1
+	Parameter string `json:"parameter"`
+}
+
+// APIMBackendTLS controls the validation of the backend's certificate. Unset fields keep the APIM default, which
+// validates.
+type APIMBackendTLS struct {
+	// ValidateCertificateChain validates the certificate chain of the backend.
+	// +optional
+	ValidateCertificateChain *bool `json:"validateCertificateChain,omitempty"`
+
+	// ValidateCertificateName validates that the certificate matches the host name of the backend.
+	// +optional
+	ValidateCertificateName *bool `json:"validateCertificateName,omitempty"`
+}
+
+// APIMBackendCircuitBreaker trips the circuit when failureCount requests fail within failureInterval. While the
+// circuit is open, APIM answers requests for the backend with 503 without sending them.
+type APIMBackendCircuitBreaker struct {
+	// Name is the name of the rule in APIM.
+	// +kubebuilder:default=breaker
+	// +optional
+	Name string `json:"name,omitempty"`
+
+	// FailureCount is the number of failures that trips the circuit.
+	// +kubebuilder:validation:Minimum=1
+	FailureCount int32 `json:"failureCount"`
+
+	// FailureInterval is the window failures are counted in.
+	FailureInterval metav1.Duration `json:"failureInterval"`
+
+	// StatusCodeRanges are the backend response status codes counted as failures.
+	// +kubebuilder:validation:MinItems=1
+	// +listType=atomic
+	StatusCodeRanges []APIMBackendStatusCodeRange `json:"statusCodeRanges"`
+
+	// ErrorReasons are the gateway errors counted as failures, e.g. BackendConnectionFailure.
+	// +listType=atomic
+	// +optional
+	ErrorReasons []string `json:"errorReasons,omitempty"`
+
+	// TripDuration is how long the circuit stays open.
+	TripDuration metav1.Duration `json:"tripDuration"`
+
+	// AcceptRetryAfter keeps the circuit open for as long as the Retry-After header of the backend asks.
+	// +optional
+	AcceptRetryAfter bool `json:"acceptRetryAfter,omitempty"`
+}
+
+// APIMBackendStatusCodeRange is an inclusive range of HTTP status codes.
+// +kubebuilder:validation:XValidation:rule="self.min <= self.max",message="min must not be greater than max"
+type APIMBackendStatusCodeRange struct {
+	// +kubebuilder:validation:Minimum=200
+	// +kubebuilder:validation:Maximum=599
+	Min int32 `json:"min"`
+
+	// +kubebuilder:validation:Minimum=200
+	// +kubebuilder:validation:Maximum=599
+	Max int32 `json:"max"`
+}
+
+// APIMBackendStatus defines the observed state of APIMBackend.
+type APIMBackendStatus struct {
+	// Phase indicates lifecycle state like "Created" or "Error"
+	Phase string `json:"phase,omitempty"`
+
+	// Message contains error details or status context
+	Message string `json:"message,omitempty"`
+
+	// AzureResourceID is the full Azure Resource Manager ID of the backend in APIM
+	AzureResourceID string `json:"azureResourceId,omitempty"`
+
+	// TerraformImports lists the IDs Terraform imports the backend by.
+	// +listType=atomic
+	// +optional
+	TerraformImports []TerraformImport `json:"terraformImports,omitempty"`
+
+	// Conditions represent the latest available observations of the backend's state.
+	// The Ready condition is True once the last sync to APIM succeeded.
+	// +listType=map
+	// +listMapKey=type
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+	// ObservedGeneration is the generation of the backend that the Ready condition was last computed for.
+	// A lower value than metadata.generation means the latest spec has not been synced yet.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:shortName=abe,categories=apim
+// +kubebuilder:printcolumn:name="Phase",type=string,JSONPath=`.status.phase`
+// +kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+// +kubebuilder:printcolumn:name="APIMService",type=string,JSONPath=`.spec.apimService`
+// +kubebuilder:printcolumn:name="Backend ID",type=string,JSONPath=`.spec.backendId`,priority=1
+// +kubebuilder:printcolumn:name="URL",type=string,JSONPath=`.spec.url`,priority=1
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// APIMBackend is the Schema for the apimbackends API.
+type APIMBackend struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   APIMBackendSpec   `json:"spec,omitempty"`
+	Status APIMBackendStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// APIMBackendList contains a list of APIMBackend.
+type APIMBackendList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []APIMBackend `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&APIMBackend{}, &APIMBackendList{})
+}
@@ -0,0 +1,91 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// APIMClusterSpec defines the desired state of APIMCluster.
+// An APIMCluster registers a remote workload cluster with the operator in the management cluster. The
+// operator watches the APIMAPIs of the remote cluster and imports them into the APIMServices of the
+// management cluster, so Azure credentials are only needed in the management cluster.
+type APIMClusterSpec struct {
+	// KubeconfigSecretRef selects the key of a Secret in the operator namespace that holds a kubeconfig
+	// for the remote cluster. Its current context is used.
+	KubeconfigSecretRef SecretKeyRef `json:"kubeconfigSecretRef"`
+}
+
+// APIMClusterStatus defines the observed state of APIMCluster.
+type APIMClusterStatus struct {
+	// Phase indicates lifecycle state like "Connected" or "Error"
+	Phase string `json:"phase,omitempty"`
+
+	// Message contains error details or status context
+	Message string `json:"message,omitempty"`
+
+	// Server is the API server URL of the remote cluster, from the kubeconfig.
+	// +optional
+	Server string `json:"server,omitempty"`
+
+	// ConnectedAt is the RFC 3339 timestamp of the last time the operator connected to the remote cluster,
+	// e.g. after a restart or a changed kubeconfig.
+	// +optional
+	ConnectedAt string `json:"connectedAt,omitempty"`
+
+	// Conditions represent the latest available observations of the cluster's state.
+	// The Ready condition is True while the operator is connected to the remote cluster.
+	// +listType=map
+	// +listMapKey=type
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+	// ObservedGeneration is the generation of the cluster that the Ready condition was last computed for.
+	// A lower value than metadata.generation means the latest spec has not been synced yet.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:shortName=aclu,categories=apim
+// +kubebuilder:printcolumn:name="Phase",type=string,JSONPath=`.status.phase`
+// +kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+// +kubebuilder:printcolumn:name="Server",type=string,JSONPath=`.status.server`,priority=1
+// +kubebuilder:printcolumn:name="Connected",type=string,JSONPath=`.status.connectedAt`,priority=1
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// APIMCluster is the Schema for the apimclusters API.
+type APIMCluster struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   APIMClusterSpec   `json:"spec,omitempty"`
+	Status APIMClusterStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// APIMClusterList contains a list of APIMCluster.
+type APIMClusterList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []APIMCluster `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&APIMCluster{}, &APIMClusterList{})
+}
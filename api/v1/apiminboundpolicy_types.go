@@ -41,7 +41,54 @@ type APIMInboundPolicySpec struct {
 
 	// PolicyContent is the XML content of the policy to be applied.
 	// This should be a complete policy XML document including all sections (inbound, backend, outbound, on-error).
+	// +kubebuilder:validation:XValidation:rule="self.contains('<policies>')",message="policyContent must contain a <policies> element"
 	PolicyContent string `json:"policyContent"`
+
+	// TemplateValues makes policyContent a Go template: placeholders like ${{ .tenantId }} are replaced with
+	// these values when the policy is applied, so the same manifest works across environments. APIM named
+	// values keep their {{name}} syntax.
+	// +listType=map
+	// +listMapKey=name
+	// +optional
+	TemplateValues []APIMInboundPolicyTemplateValue `json:"templateValues,omitempty"`
+}
+
+// APIMInboundPolicyTemplateValue is a value placeholders in policyContent refer to by name. It comes from
+// exactly one source.
+// +kubebuilder:validation:XValidation:rule="(has(self.value) ? 1 : 0) + (has(self.configMapKeyRef) ? 1 : 0) + (has(self.secretKeyRef) ? 1 : 0) + (has(self.namedValue) ? 1 : 0) == 1",message="exactly one of value, configMapKeyRef, secretKeyRef and namedValue must be set"
+type APIMInboundPolicyTemplateValue struct {
+	// Name is the name placeholders use, as ${{ .name }}.
+	// +kubebuilder:validation:Pattern=`^[A-Za-z_][A-Za-z0-9_]*$`
+	Name string `json:"name"`
+
+	// Value is the inline value, e.g. set per environment by a Kustomize overlay.
+	// +optional
+	Value string `json:"value,omitempty"`
+
+	// ConfigMapKeyRef reads the value from a key of a ConfigMap in the namespace of the policy.
+	// +optional
+	ConfigMapKeyRef *ConfigMapKeyRef `json:"configMapKeyRef,omitempty"`
+
+	// SecretKeyRef reads the value from a key of a Secret in the namespace of the policy. The value is written
+	// into the policy and is visible to everyone who can read policies in APIM; use namedValue for secrets.
+	// +optional
+	SecretKeyRef *SecretKeyRef `json:"secretKeyRef,omitempty"`
+
+	// NamedValue is the name of an APIMNamedValue in the namespace of the policy. The placeholder is replaced
+	// with its {{displayName}} reference, which APIM resolves when it runs the policy.
+	// +optional
+	NamedValue string `json:"namedValue,omitempty"`
+}
+
+// ConfigMapKeyRef selects a key of a ConfigMap in the namespace of the referencing resource.
+type ConfigMapKeyRef struct {
+	// Name is the name of the ConfigMap.
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// Key is the key of the value in the ConfigMap.
+	// +kubebuilder:validation:MinLength=1
+	Key string `json:"key"`
 }
 
 // APIMInboundPolicyStatus defines the observed state of APIMInboundPolicy.
@@ -54,10 +101,39 @@ type APIMInboundPolicyStatus struct {
 
 	// Message contains error details or status context
 	Message string `json:"message,omitempty"`
+
+	// AzureResourceID is the full Azure Resource Manager ID of the policy in APIM
+	AzureResourceID string `json:"azureResourceId,omitempty"`
+
+	// TerraformImports lists the IDs Terraform imports the policy by.
+	// +listType=atomic
+	// +optional
+	TerraformImports []TerraformImport `json:"terraformImports,omitempty"`
+
+	// ETag is the ETag of the policy in APIM after the last successful write. The next write is
+	// conditional on it, so edits made in APIM in the meantime are detected.
+	ETag string `json:"etag,omitempty"`
+
+	// Conditions represent the latest available observations of the policy's state.
+	// The Ready condition is True once the last sync to APIM succeeded.
+	// +listType=map
+	// +listMapKey=type
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+	// ObservedGeneration is the generation of the policy that the Ready condition was last computed for.
+	// A lower value than metadata.generation means the latest spec has not been synced yet.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
 }
 
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
+// +kubebuilder:resource:shortName=apol,categories=apim
+// +kubebuilder:printcolumn:name="Phase",type=string,JSONPath=`.status.phase`
+// +kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+// +kubebuilder:printcolumn:name="APIMService",type=string,JSONPath=`.spec.apimService`
+// +kubebuilder:printcolumn:name="API ID",type=string,JSONPath=`.spec.apiId`,priority=1
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
 
 // APIMInboundPolicy is the Schema for the apiminboundpolicies API.
 type APIMInboundPolicy struct {
@@ -0,0 +1,158 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// APIMNamedValueSpec defines the desired state of APIMNamedValue.
+// The value comes from exactly one source: an inline value, a key of a Kubernetes Secret or an Azure Key
+// Vault secret that APIM reads with its own identity. The operator re-reads the source on a schedule, so a
+// rotated secret reaches APIM without anyone touching the resource.
+// +kubebuilder:validation:XValidation:rule="(has(self.value) ? 1 : 0) + (has(self.secretRef) ? 1 : 0) + (has(self.keyVault) ? 1 : 0) == 1",message="exactly one of value, secretRef and keyVault must be set"
+type APIMNamedValueSpec struct {
+	// APIMService is the name of the APIMService custom resource
+	APIMService string `json:"apimService"`
+
+	// NamedValueID is the unique identifier for the named value in APIM
+	// +kubebuilder:validation:MinLength=1
+	NamedValueID string `json:"namedValueId"`
+
+	// DisplayName is the name policies use to reference the value, as {{displayName}}. Defaults to the
+	// named value ID.
+	// +kubebuilder:validation:Pattern=`^[A-Za-z0-9-._]+$`
+	// +optional
+	DisplayName string `json:"displayName,omitempty"`
+
+	// Secret hides the value in the APIM UI and API. Values from secretRef and keyVault are always secret.
+	// +optional
+	Secret bool `json:"secret,omitempty"`
+
+	// Value is the inline value.
+	// +optional
+	Value string `json:"value,omitempty"`
+
+	// SecretRef reads the value from a key of a Secret in the namespace of the APIMNamedValue.
+	// +optional
+	SecretRef *SecretKeyRef `json:"secretRef,omitempty"`
+
+	// KeyVault makes APIM read the value from an Azure Key Vault secret.
+	// +optional
+	KeyVault *APIMNamedValueKeyVault `json:"keyVault,omitempty"`
+
+	// RefreshInterval is how often the source is read again and APIM updated when the value changed.
+	// +kubebuilder:default="1h"
+	// +optional
+	RefreshInterval *metav1.Duration `json:"refreshInterval,omitempty"`
+}
+
+// SecretKeyRef selects a key of a Kubernetes Secret in the namespace of the referencing resource.
+type SecretKeyRef struct {
+	// Name is the name of the Secret.
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// Key is the key of the value in the Secret.
+	// +kubebuilder:validation:MinLength=1
+	Key string `json:"key"`
+}
+
+// APIMNamedValueKeyVault references an Azure Key Vault secret.
+type APIMNamedValueKeyVault struct {
+	// SecretIdentifier is the URI of the secret, e.g. https://my-vault.vault.azure.net/secrets/backend-key.
+	// Leave out the version so APIM follows rotations.
+	// +kubebuilder:validation:MinLength=1
+	SecretIdentifier string `json:"secretIdentifier"`
+
+	// IdentityClientID is the client ID of a user-assigned identity of the APIM service that may read the
+	// secret. Empty uses the system-assigned identity.
+	// +optional
+	IdentityClientID string `json:"identityClientId,omitempty"`
+}
+
+// APIMNamedValueStatus defines the observed state of APIMNamedValue.
+type APIMNamedValueStatus struct {
+	// Phase indicates lifecycle state like "Created" or "Error"
+	Phase string `json:"phase,omitempty"`
+
+	// Message contains error details or status context
+	Message string `json:"message,omitempty"`
+
+	// AzureResourceID is the full Azure Resource Manager ID of the named value in APIM
+	AzureResourceID string `json:"azureResourceId,omitempty"`
+
+	// TerraformImports lists the IDs Terraform imports the named value by.
+	// +listType=atomic
+	// +optional
+	TerraformImports []TerraformImport `json:"terraformImports,omitempty"`
+
+	// ValueHash is a salted SHA-256 hash of the value last seen, used to detect rotations.
+	// +optional
+	ValueHash string `json:"valueHash,omitempty"`
+
+	// LastRefreshedAt is the RFC 3339 timestamp of the last time the source was read.
+	// +optional
+	LastRefreshedAt string `json:"lastRefreshedAt,omitempty"`
+
+	// ValueChangedAt is the RFC 3339 timestamp of the last time the value was first seen or found changed.
+	// +optional
+	ValueChangedAt string `json:"valueChangedAt,omitempty"`
+
+	// Conditions represent the latest available observations of the named value's state.
+	// The Ready condition is True once the named value is synced.
+	// +listType=map
+	// +listMapKey=type
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+	// ObservedGeneration is the generation of the named value that the Ready condition was last computed for.
+	// A lower value than metadata.generation means the latest spec has not been synced yet.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:shortName=anv,categories=apim
+// +kubebuilder:printcolumn:name="Phase",type=string,JSONPath=`.status.phase`
+// +kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+// +kubebuilder:printcolumn:name="APIMService",type=string,JSONPath=`.spec.apimService`
+// +kubebuilder:printcolumn:name="Named Value ID",type=string,JSONPath=`.spec.namedValueId`,priority=1
+// +kubebuilder:printcolumn:name="Changed",type=string,JSONPath=`.status.valueChangedAt`,priority=1
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// APIMNamedValue is the Schema for the apimnamedvalues API.
+type APIMNamedValue struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   APIMNamedValueSpec   `json:"spec,omitempty"`
+	Status APIMNamedValueStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// APIMNamedValueList contains a list of APIMNamedValue.
+type APIMNamedValueList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []APIMNamedValue `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&APIMNamedValue{}, &APIMNamedValueList{})
+}
@@ -12,16 +12,45 @@ type APIMProductSpec struct {
 	Published   bool   `json:"published,omitempty"`   // Whether the product should be published
 	APIMService string `json:"apimService"`           // API Management service name
 	APIID       string `json:"apiID,omitempty"`       // Optional API to associate with the product
+	// ApprovalRequired makes subscriptions requested in the developer portal wait for approval. The operator
+	// approves or rejects them when it runs with --subscription-approval; otherwise an administrator does.
+	// +optional
+	ApprovalRequired bool `json:"approvalRequired,omitempty"`
 }
 
 // APIMProductStatus defines the observed state
 type APIMProductStatus struct {
 	Phase   string `json:"phase,omitempty"`   // Status phase (e.g. Created, Error)
 	Message string `json:"message,omitempty"` // Status message or error description
+	// AzureResourceID is the full Azure Resource Manager ID of the product in APIM.
+	AzureResourceID string `json:"azureResourceId,omitempty"`
+	// TerraformImports lists the IDs Terraform imports the product by.
+	// +listType=atomic
+	// +optional
+	TerraformImports []TerraformImport `json:"terraformImports,omitempty"`
+	// ETag is the ETag of the product in APIM after the last successful write. The next write is
+	// conditional on it, so edits made in APIM in the meantime are detected.
+	ETag string `json:"etag,omitempty"`
+	// Conditions represent the latest available observations of the product's state.
+	// The Ready condition is True once the last sync to APIM succeeded.
+	// +listType=map
+	// +listMapKey=type
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+	// ObservedGeneration is the generation of the product that the Ready condition was last computed for.
+	// A lower value than metadata.generation means the latest spec has not been synced yet.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
 }
 
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
+// +kubebuilder:resource:shortName=aprod,categories=apim
+// +kubebuilder:printcolumn:name="Phase",type=string,JSONPath=`.status.phase`
+// +kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+// +kubebuilder:printcolumn:name="APIMService",type=string,JSONPath=`.spec.apimService`
+// +kubebuilder:printcolumn:name="Product ID",type=string,JSONPath=`.spec.productId`,priority=1
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
 
 // APIMProduct is the Schema for the apimproducts API
 type APIMProduct struct {
@@ -33,6 +33,199 @@ type APIMServiceSpec struct {
 	ResourceGroup string `json:"resourceGroup"`
 	// Subscription is the Azure subscription ID where the APIM service is deployed.
 	Subscription string `json:"subscription"`
+	// NamespaceQuota limits how many resources each namespace may create against this APIM service.
+	// It is enforced by the admission webhooks; without them it has no effect.
+	// +optional
+	NamespaceQuota *APIMServiceNamespaceQuota `json:"namespaceQuota,omitempty"`
+	// APICenter registers every API imported into this service in an Azure API Center instance,
+	// so the organization's API inventory stays complete without manual bookkeeping.
+	// +optional
+	APICenter *APIMServiceAPICenter `json:"apiCenter,omitempty"`
+	// PortalPublishing publishes the developer portal after the operator changed APIs or products of this
+	// service, so portal consumers see the latest imported definitions. Unset disables publishing.
+	// +optional
+	PortalPublishing *APIMServicePortalPublishing `json:"portalPublishing,omitempty"`
+	// TelemetryTags are attached to the traces, metrics and Kubernetes Events the operator produces for
+	// resources that target this service, so the telemetry of a shared operator can be split per environment.
+	// +optional
+	TelemetryTags *APIMServiceTelemetryTags `json:"telemetryTags,omitempty"`
+	// Notifications post the outcome of API imports into this service to webhooks, so application teams
+	// get feedback on their rollouts without watching the cluster.
+	// +listType=map
+	// +listMapKey=name
+	// +optional
+	Notifications []APIMServiceNotification `json:"notifications,omitempty"`
+	// DeploymentStatus reports the outcome of imports into this service back to the source repositories of
+	// APIMAPIs annotated with their repository and commit, so release dashboards show when a change went live.
+	// +optional
+	DeploymentStatus *APIMServiceDeploymentStatus `json:"deploymentStatus,omitempty"`
+	// Backup backs up the APIM service to an Azure Storage container on a schedule, so the gateway can be
+	// restored after a disaster. Unset disables backups.
+	// +optional
+	Backup *APIMServiceBackup `json:"backup,omitempty"`
+}
+
+// APIMServiceBackup schedules backups of an APIM service. The managed identity of the APIM service needs the
+// Storage Blob Data Contributor role on the storage account.
+type APIMServiceBackup struct {
+	// StorageAccount is the name of the storage account the backups are written to.
+	// +kubebuilder:validation:MinLength=3
+	StorageAccount string `json:"storageAccount"`
+	// Container is the blob container the backups are written to.
+	// +kubebuilder:validation:MinLength=3
+	Container string `json:"container"`
+	// Schedule is a cron expression with five fields, in UTC unless prefixed with CRON_TZ=<zone>, e.g.
+	// "0 2 * * *" for every night at 2:00.
+	// +kubebuilder:validation:MinLength=1
+	Schedule string `json:"schedule"`
+	// IdentityClientID is the client ID of a user-assigned managed identity of the APIM service that writes
+	// the backups. Empty uses the system-assigned identity of the service.
+	// +optional
+	IdentityClientID string `json:"identityClientId,omitempty"`
+}
+
+// APIMServiceBackupStatus is the state of the scheduled backups of an APIM service.
+type APIMServiceBackupStatus struct {
+	// Schedule is the schedule NextBackupAt was computed from.
+	// +optional
+	Schedule string `json:"schedule,omitempty"`
+	// NextBackupAt is the RFC 3339 timestamp the next backup is started at.
+	// +optional
+	NextBackupAt string `json:"nextBackupAt,omitempty"`
+	// LastSuccessfulBackupAt is the RFC 3339 timestamp the last successful backup was started at.
+	// +optional
+	LastSuccessfulBackupAt string `json:"lastSuccessfulBackupAt,omitempty"`
+	// LastSuccessfulBackupName is the blob name of the last successful backup.
+	// +optional
+	LastSuccessfulBackupName string `json:"lastSuccessfulBackupName,omitempty"`
+	// InProgress is the backup APIM is running, if any.
+	// +optional
+	InProgress *APIMServiceBackupOperation `json:"inProgress,omitempty"`
+	// LastError describes why the last backup failed. A successful backup clears it.
+	// +optional
+	LastError string `json:"lastError,omitempty"`
+}
+
+// APIMServiceBackupOperation is a backup APIM is running.
+type APIMServiceBackupOperation struct {
+	// Name is the blob name of the backup.
+	Name string `json:"name"`
+	// StartedAt is the RFC 3339 timestamp the backup was started at.
+	StartedAt string `json:"startedAt"`
+	// OperationURL is the Azure Resource Manager URL the progress of the backup is read from.
+	OperationURL string `json:"operationUrl"`
+}
+
+// APIMServiceDeploymentStatus reports import outcomes to GitHub Deployments or Azure DevOps.
+type APIMServiceDeploymentStatus struct {
+	// Provider is "github" to create GitHub Deployments or "azureDevOps" to post Azure Repos commit statuses.
+	// +kubebuilder:validation:Enum=github;azureDevOps
+	Provider string `json:"provider"`
+	// Environment is the name of the environment the APIs of this service are deployed to, e.g. "production".
+	// +kubebuilder:validation:MinLength=1
+	Environment string `json:"environment"`
+	// TokenSecretRef selects the key of a Secret in the operator namespace that holds the access token:
+	// a GitHub token that may write deployments, or an Azure DevOps personal access token with the Code (status) scope.
+	TokenSecretRef SecretKeyRef `json:"tokenSecretRef"`
+	// BaseURL is the API endpoint of GitHub Enterprise Server or Azure DevOps Server.
+	// Defaults to https://api.github.com or https://dev.azure.com.
+	// +kubebuilder:validation:Pattern=`^https?://`
+	// +optional
+	BaseURL string `json:"baseUrl,omitempty"`
+}
+
+// APIMServiceNotification posts import outcomes to a webhook.
+type APIMServiceNotification struct {
+	// Name identifies the notification in logs and Events.
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+	// Format is the message format: "webhook" posts a JSON document, "slack" a Slack incoming webhook
+	// message and "teams" an Adaptive Card for a Microsoft Teams workflow.
+	// +kubebuilder:validation:Enum=webhook;slack;teams
+	// +kubebuilder:default=webhook
+	// +optional
+	Format string `json:"format,omitempty"`
+	// URLSecretRef selects the key of a Secret in the operator namespace that holds the webhook URL.
+	// Slack and Teams webhook URLs grant anyone who knows them the right to post, so they are kept in a Secret.
+	URLSecretRef SecretKeyRef `json:"urlSecretRef"`
+	// On lists the outcomes that are posted. Defaults to both.
+	// +kubebuilder:validation:items:Enum=success;failure
+	// +optional
+	On []string `json:"on,omitempty"`
+}
+
+// APIMServiceTelemetryTags are the tags attached to the telemetry of an APIM service. Empty tags are left out.
+type APIMServiceTelemetryTags struct {
+	// Env is the environment of the service, e.g. "prod".
+	// +kubebuilder:validation:MaxLength=63
+	// +optional
+	Env string `json:"env,omitempty"`
+	// Team is the team that owns the service.
+	// +kubebuilder:validation:MaxLength=63
+	// +optional
+	Team string `json:"team,omitempty"`
+	// Service is the logical service name, e.g. "payments-gateway".
+	// +kubebuilder:validation:MaxLength=63
+	// +optional
+	Service string `json:"service,omitempty"`
+}
+
+// APIMServicePortalPublishing configures automatic developer portal publishing.
+type APIMServicePortalPublishing struct {
+	// Debounce is the quiet period after the last change before the portal is published,
+	// so a burst of imports results in a single publish.
+	// +kubebuilder:default="5m"
+	// +optional
+	Debounce *metav1.Duration `json:"debounce,omitempty"`
+}
+
+// APIMServiceAPICenter references the Azure API Center instance that imported APIs are registered in.
+// The operator identity needs the Azure API Center Service Contributor role on it.
+type APIMServiceAPICenter struct {
+	// Name is the name of the API Center service in Azure.
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+	// ResourceGroup is the Azure resource group of the API Center service. Defaults to the APIM service's.
+	// +optional
+	ResourceGroup string `json:"resourceGroup,omitempty"`
+	// Subscription is the Azure subscription ID of the API Center service. Defaults to the APIM service's.
+	// +optional
+	Subscription string `json:"subscription,omitempty"`
+	// Workspace is the API Center workspace the APIs are registered in.
+	// +kubebuilder:default=default
+	// +optional
+	Workspace string `json:"workspace,omitempty"`
+	// Environment is the API Center environment that represents this APIM service. If set, each API
+	// is also recorded as deployed to it, with the gateway URL as runtime URL. The environment must exist.
+	// +optional
+	Environment string `json:"environment,omitempty"`
+}
+
+// APIMServiceNamespaceQuota caps the number of operator resources a single namespace may point at one APIM service.
+// An unset limit means unlimited.
+type APIMServiceNamespaceQuota struct {
+	// MaxAPIs is the maximum number of APIMAPI resources per namespace.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MaxAPIs *int32 `json:"maxAPIs,omitempty"`
+	// MaxProducts is the maximum number of APIMProduct resources per namespace.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MaxProducts *int32 `json:"maxProducts,omitempty"`
+}
+
+// APIMServiceCapabilities are the features of the operator that the pricing tier of an APIM service supports.
+type APIMServiceCapabilities struct {
+	// DeveloperPortal is false for tiers without a developer portal, such as Consumption, which can't use
+	// portalPublishing.
+	DeveloperPortal bool `json:"developerPortal"`
+	// Backup is false for tiers that can't be backed up, such as Consumption and the v2 tiers.
+	Backup bool `json:"backup"`
+	// UnsupportedPolicies are the policy elements the tier rejects, e.g. quota-by-key. APIMInboundPolicies
+	// that use them are not sent to APIM.
+	// +listType=atomic
+	// +optional
+	UnsupportedPolicies []string `json:"unsupportedPolicies,omitempty"`
 }
 
 // APIMServiceStatus defines the observed state of APIMService.
@@ -40,10 +233,52 @@ type APIMServiceSpec struct {
 type APIMServiceStatus struct {
 	// Host is the hostname of the APIM service (e.g., "myapim.azure-api.net").
 	Host string `json:"host,omitempty"`
+	// DeveloperPortalHost is the hostname of the APIM developer portal.
+	// +optional
+	DeveloperPortalHost string `json:"developerPortalHost,omitempty"`
+	// HostsRefreshedAt is the RFC 3339 timestamp when the hostnames were last read from Azure.
+	// +optional
+	HostsRefreshedAt string `json:"hostsRefreshedAt,omitempty"`
+	// PortalPublishedAt is the RFC 3339 timestamp of the last developer portal publish by the operator.
+	// +optional
+	PortalPublishedAt string `json:"portalPublishedAt,omitempty"`
+	// PortalRevision is the portal revision created by the last publish.
+	// +optional
+	PortalRevision string `json:"portalRevision,omitempty"`
+	// SKU is the pricing tier of the APIM service as Azure reports it, e.g. "Developer", "Consumption"
+	// or "StandardV2". It is read together with the hostnames.
+	// +optional
+	SKU string `json:"sku,omitempty"`
+	// Capabilities are the features of the operator that the pricing tier supports. Resources that target
+	// this service and use an unsupported feature fail with the NotSupportedOnSKU reason instead of an Azure error.
+	// Unset until the SKU was read.
+	// +optional
+	Capabilities *APIMServiceCapabilities `json:"capabilities,omitempty"`
+	// Backup is the state of the scheduled backups, set while spec.backup is.
+	// +optional
+	Backup *APIMServiceBackupStatus `json:"backup,omitempty"`
+	// Conditions represent the latest available observations of the service's state.
+	// The Ready condition is True once the hostnames were read from Azure.
+	// +listType=map
+	// +listMapKey=type
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+	// ObservedGeneration is the generation of the service that the Ready condition was last computed for.
+	// A lower value than metadata.generation means the latest spec has not been synced yet.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
 }
 
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
+// +kubebuilder:resource:shortName=asvc,categories=apim
+// +kubebuilder:printcolumn:name="Service",type=string,JSONPath=`.spec.name`
+// +kubebuilder:printcolumn:name="Resource Group",type=string,JSONPath=`.spec.resourceGroup`
+// +kubebuilder:printcolumn:name="Host",type=string,JSONPath=`.status.host`
+// +kubebuilder:printcolumn:name="SKU",type=string,JSONPath=`.status.sku`
+// +kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+// +kubebuilder:printcolumn:name="Last Backup",type=string,JSONPath=`.status.backup.lastSuccessfulBackupAt`,priority=1
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
 
 // APIMService is the Schema for the apimservices API.
 type APIMService struct {
@@ -0,0 +1,137 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// APIMSubscriptionSpec defines the desired state of APIMSubscription.
+// The subscription is scoped to exactly one API, one product or all APIs, and its keys are written to
+// Secrets so in-cluster consumers of a gateway-fronted API don't have to manage keys by hand.
+// +kubebuilder:validation:XValidation:rule="(has(self.apiId) ? 1 : 0) + (has(self.productId) ? 1 : 0) + (has(self.allApis) && self.allApis ? 1 : 0) == 1",message="exactly one of apiId, productId and allApis must be set"
+type APIMSubscriptionSpec struct {
+	// APIMService is the name of the APIMService custom resource
+	APIMService string `json:"apimService"`
+
+	// SubscriptionID is the unique identifier for the subscription in APIM
+	// +kubebuilder:validation:MinLength=1
+	SubscriptionID string `json:"subscriptionId"`
+
+	// DisplayName is the name shown in the APIM UI. Defaults to the subscription ID.
+	// +optional
+	DisplayName string `json:"displayName,omitempty"`
+
+	// APIID scopes the subscription to a single API.
+	// +optional
+	APIID string `json:"apiId,omitempty"`
+
+	// ProductID scopes the subscription to a product and all of its APIs.
+	// +optional
+	ProductID string `json:"productId,omitempty"`
+
+	// AllAPIs scopes the subscription to every API of the APIM service.
+	// +optional
+	AllAPIs bool `json:"allApis,omitempty"`
+
+	// SecretName is the name of the Secrets the keys are written to. Defaults to the resource name.
+	// +optional
+	SecretName string `json:"secretName,omitempty"`
+
+	// TargetNamespaces are further namespaces the Secret is written to. The namespace of the
+	// APIMSubscription always gets a copy.
+	// +optional
+	TargetNamespaces []string `json:"targetNamespaces,omitempty"`
+
+	// RotationInterval regenerates one key per interval, alternating between the primary and secondary
+	// key. Unset disables rotation.
+	// +optional
+	RotationInterval *metav1.Duration `json:"rotationInterval,omitempty"`
+}
+
+// APIMSubscriptionStatus defines the observed state of APIMSubscription.
+type APIMSubscriptionStatus struct {
+	// Phase indicates lifecycle state like "Created" or "Error"
+	Phase string `json:"phase,omitempty"`
+
+	// Message contains error details or status context
+	Message string `json:"message,omitempty"`
+
+	// AzureResourceID is the full Azure Resource Manager ID of the subscription in APIM
+	AzureResourceID string `json:"azureResourceId,omitempty"`
+
+	// TerraformImports lists the IDs Terraform imports the subscription by.
+	// +listType=atomic
+	// +optional
+	TerraformImports []TerraformImport `json:"terraformImports,omitempty"`
+
+	// SecretNamespaces are the namespaces the Secret was last written to.
+	// +optional
+	SecretNamespaces []string `json:"secretNamespaces,omitempty"`
+
+	// ActiveKey is the key consumers should use: "primary" or "secondary". It is the key that was
+	// regenerated last, so the other one can be regenerated next without breaking consumers.
+	// +optional
+	ActiveKey string `json:"activeKey,omitempty"`
+
+	// KeyRotatedAt is the RFC 3339 timestamp of the last key regeneration.
+	// +optional
+	KeyRotatedAt string `json:"keyRotatedAt,omitempty"`
+
+	// Conditions represent the latest available observations of the subscription's state.
+	// The Ready condition is True once the subscription and its Secrets are synced.
+	// +listType=map
+	// +listMapKey=type
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+	// ObservedGeneration is the generation of the subscription that the Ready condition was last computed for.
+	// A lower value than metadata.generation means the latest spec has not been synced yet.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:shortName=asub,categories=apim
+// +kubebuilder:printcolumn:name="Phase",type=string,JSONPath=`.status.phase`
+// +kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+// +kubebuilder:printcolumn:name="APIMService",type=string,JSONPath=`.spec.apimService`
+// +kubebuilder:printcolumn:name="Subscription ID",type=string,JSONPath=`.spec.subscriptionId`,priority=1
+// +kubebuilder:printcolumn:name="Active Key",type=string,JSONPath=`.status.activeKey`,priority=1
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// APIMSubscription is the Schema for the apimsubscriptions API.
+type APIMSubscription struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   APIMSubscriptionSpec   `json:"spec,omitempty"`
+	Status APIMSubscriptionStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// APIMSubscriptionList contains a list of APIMSubscription.
+type APIMSubscriptionList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []APIMSubscription `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&APIMSubscription{}, &APIMSubscriptionList{})
+}
@@ -0,0 +1,47 @@
+package v1
+
+// Reasons reported when a managed resource fails to sync to Azure APIM. The same value is used as the
+// reason of the Ready condition, of Warning Events and in APIMAPIDeployment status.lastErrorDetails,
+// so automation can branch on it instead of parsing messages. New reasons may be added over time.
+const (
+	// ReasonSpecFetchFailed means the OpenAPI definition could not be fetched from its URL.
+	ReasonSpecFetchFailed = "SpecFetchFailed"
+	// ReasonSpecInvalid means the revision or the OpenAPI definition was rejected as invalid.
+	ReasonSpecInvalid = "SpecInvalid"
+	// ReasonAuthFailed means no Azure token could be obtained, or Azure refused the operator's identity.
+	ReasonAuthFailed = "AuthFailed"
+	// ReasonThrottled means Azure answered with 429 Too Many Requests.
+	ReasonThrottled = "Throttled"
+	// ReasonConflictingPolicy means APIM refused a policy because it conflicts with a concurrent change.
+	ReasonConflictingPolicy = "ConflictingPolicy"
+	// ReasonModifiedOutsideOperator means the APIM entity was changed or deleted outside the operator since
+	// it was last written, so the write was not sent. The next reconcile writes the declared state again.
+	ReasonModifiedOutsideOperator = "ModifiedOutsideOperator"
+	// ReasonAzureError means any other failed call to the Azure management API.
+	ReasonAzureError = "AzureError"
+	// ReasonSecretReadFailed means a Kubernetes Secret the resource references could not be read or lacks the key.
+	ReasonSecretReadFailed = "SecretReadFailed"
+	// ReasonTemplateFailed means a policy template could not be rendered, e.g. because a ConfigMap or
+	// APIMNamedValue it references is missing or a placeholder has no value.
+	ReasonTemplateFailed = "TemplateFailed"
+	// ReasonSecretWriteFailed means a Secret the operator manages in the cluster could not be written.
+	ReasonSecretWriteFailed = "SecretWriteFailed"
+	// ReasonSmokeCheckFailed means a canary revision failed its smoke check and was not made current.
+	ReasonSmokeCheckFailed = "SmokeCheckFailed"
+	// ReasonBreakingChange means a changed OpenAPI definition breaks the imported one and was not imported.
+	ReasonBreakingChange = "BreakingChange"
+	// ReasonClusterUnreachable means a remote cluster could not be reached or refused the operator's kubeconfig.
+	ReasonClusterUnreachable = "ClusterUnreachable"
+	// ReasonNotSupportedOnSKU means the resource uses a feature the pricing tier of its APIM service does not
+	// support, e.g. portal publishing on Consumption. It is retried once the service's SKU changes.
+	ReasonNotSupportedOnSKU = "NotSupportedOnSKU"
+	// ReasonNotSupportedOnAPIVersion means the resource uses a feature the APIM API version of the operator
+	// (--apim-api-version) does not define, e.g. a backend circuit breaker before 2024-05-01.
+	ReasonNotSupportedOnAPIVersion = "NotSupportedOnAPIVersion"
+
+	// ReasonSynced is the reason of a Ready condition that is True.
+	ReasonSynced = "Synced"
+	// ReasonProgressing is the reason of a Ready condition that is Unknown because the latest spec
+	// is still being synced.
+	ReasonProgressing = "Progressing"
+)
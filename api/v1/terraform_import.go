@@ -0,0 +1,15 @@
+package v1
+
+// TerraformImport identifies an Azure entity the operator manages the way Terraform imports it, so a mixed
+// Terraform and operator estate can settle which tool owns the entity without deriving ARM IDs by hand.
+type TerraformImport struct {
+	// Resource is the azurerm resource type of the entity, e.g. azurerm_api_management_api.
+	Resource string `json:"resource"`
+	// ID is the ID that terraform import expects for Resource. It differs from the ARM ID for some types,
+	// e.g. APIs carry their revision.
+	ID string `json:"id"`
+	// AzapiType is the type of an azapi_resource for the entity, e.g. Microsoft.ApiManagement/service/apis@2021-08-01.
+	AzapiType string `json:"azapiType"`
+	// AzapiID is the ID that terraform import expects for an azapi_resource of the entity: its ARM ID.
+	AzapiID string `json:"azapiId"`
+}
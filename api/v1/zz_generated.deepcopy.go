@@ -31,7 +31,7 @@ func (in *APIMAPI) DeepCopyInto(out *APIMAPI) {
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APIMAPI.
@@ -52,6 +52,67 @@ func (in *APIMAPI) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *APIMAPIAdoptedState) DeepCopyInto(out *APIMAPIAdoptedState) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APIMAPIAdoptedState.
+func (in *APIMAPIAdoptedState) DeepCopy() *APIMAPIAdoptedState {
+	if in == nil {
+		return nil
+	}
+	out := new(APIMAPIAdoptedState)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *APIMAPIBackendStatus) DeepCopyInto(out *APIMAPIBackendStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APIMAPIBackendStatus.
+func (in *APIMAPIBackendStatus) DeepCopy() *APIMAPIBackendStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(APIMAPIBackendStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *APIMAPIBackends) DeepCopyInto(out *APIMAPIBackends) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APIMAPIBackends.
+func (in *APIMAPIBackends) DeepCopy() *APIMAPIBackends {
+	if in == nil {
+		return nil
+	}
+	out := new(APIMAPIBackends)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *APIMAPICanary) DeepCopyInto(out *APIMAPICanary) {
+	*out = *in
+	in.SmokeCheck.DeepCopyInto(&out.SmokeCheck)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APIMAPICanary.
+func (in *APIMAPICanary) DeepCopy() *APIMAPICanary {
+	if in == nil {
+		return nil
+	}
+	out := new(APIMAPICanary)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *APIMAPIDeployment) DeepCopyInto(out *APIMAPIDeployment) {
 	*out = *in
@@ -79,6 +140,61 @@ func (in *APIMAPIDeployment) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *APIMAPIDeploymentAppliedState) DeepCopyInto(out *APIMAPIDeploymentAppliedState) {
+	*out = *in
+	if in.ProductIDs != nil {
+		in, out := &in.ProductIDs, &out.ProductIDs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.TagIDs != nil {
+		in, out := &in.TagIDs, &out.TagIDs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APIMAPIDeploymentAppliedState.
+func (in *APIMAPIDeploymentAppliedState) DeepCopy() *APIMAPIDeploymentAppliedState {
+	if in == nil {
+		return nil
+	}
+	out := new(APIMAPIDeploymentAppliedState)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *APIMAPIDeploymentCanaryStatus) DeepCopyInto(out *APIMAPIDeploymentCanaryStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APIMAPIDeploymentCanaryStatus.
+func (in *APIMAPIDeploymentCanaryStatus) DeepCopy() *APIMAPIDeploymentCanaryStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(APIMAPIDeploymentCanaryStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *APIMAPIDeploymentErrorDetails) DeepCopyInto(out *APIMAPIDeploymentErrorDetails) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APIMAPIDeploymentErrorDetails.
+func (in *APIMAPIDeploymentErrorDetails) DeepCopy() *APIMAPIDeploymentErrorDetails {
+	if in == nil {
+		return nil
+	}
+	out := new(APIMAPIDeploymentErrorDetails)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *APIMAPIDeploymentList) DeepCopyInto(out *APIMAPIDeploymentList) {
 	*out = *in
@@ -111,6 +227,21 @@ func (in *APIMAPIDeploymentList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *APIMAPIDeploymentRecord) DeepCopyInto(out *APIMAPIDeploymentRecord) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APIMAPIDeploymentRecord.
+func (in *APIMAPIDeploymentRecord) DeepCopy() *APIMAPIDeploymentRecord {
+	if in == nil {
+		return nil
+	}
+	out := new(APIMAPIDeploymentRecord)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *APIMAPIDeploymentSpec) DeepCopyInto(out *APIMAPIDeploymentSpec) {
 	*out = *in
@@ -124,6 +255,11 @@ func (in *APIMAPIDeploymentSpec) DeepCopyInto(out *APIMAPIDeploymentSpec) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.Canary != nil {
+		in, out := &in.Canary, &out.Canary
+		*out = new(APIMAPICanary)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APIMAPIDeploymentSpec.
@@ -139,11 +275,26 @@ func (in *APIMAPIDeploymentSpec) DeepCopy() *APIMAPIDeploymentSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *APIMAPIDeploymentStatus) DeepCopyInto(out *APIMAPIDeploymentStatus) {
 	*out = *in
+	if in.LastErrorDetails != nil {
+		in, out := &in.LastErrorDetails, &out.LastErrorDetails
+		*out = new(APIMAPIDeploymentErrorDetails)
+		**out = **in
+	}
 	if in.MatchedReplicaSets != nil {
 		in, out := &in.MatchedReplicaSets, &out.MatchedReplicaSets
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.AppliedState != nil {
+		in, out := &in.AppliedState, &out.AppliedState
+		*out = new(APIMAPIDeploymentAppliedState)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Canary != nil {
+		in, out := &in.Canary, &out.Canary
+		*out = new(APIMAPIDeploymentCanaryStatus)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APIMAPIDeploymentStatus.
@@ -156,6 +307,26 @@ func (in *APIMAPIDeploymentStatus) DeepCopy() *APIMAPIDeploymentStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *APIMAPIDocumentation) DeepCopyInto(out *APIMAPIDocumentation) {
+	*out = *in
+	if in.UndocumentedOperations != nil {
+		in, out := &in.UndocumentedOperations, &out.UndocumentedOperations
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APIMAPIDocumentation.
+func (in *APIMAPIDocumentation) DeepCopy() *APIMAPIDocumentation {
+	if in == nil {
+		return nil
+	}
+	out := new(APIMAPIDocumentation)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *APIMAPIList) DeepCopyInto(out *APIMAPIList) {
 	*out = *in
@@ -188,9 +359,59 @@ func (in *APIMAPIList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *APIMAPIPlan) DeepCopyInto(out *APIMAPIPlan) {
+	*out = *in
+	if in.Changes != nil {
+		in, out := &in.Changes, &out.Changes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APIMAPIPlan.
+func (in *APIMAPIPlan) DeepCopy() *APIMAPIPlan {
+	if in == nil {
+		return nil
+	}
+	out := new(APIMAPIPlan)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *APIMAPISmokeCheck) DeepCopyInto(out *APIMAPISmokeCheck) {
+	*out = *in
+	if in.Timeout != nil {
+		in, out := &in.Timeout, &out.Timeout
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.SubscriptionKeySecretRef != nil {
+		in, out := &in.SubscriptionKeySecretRef, &out.SubscriptionKeySecretRef
+		*out = new(SecretKeyRef)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APIMAPISmokeCheck.
+func (in *APIMAPISmokeCheck) DeepCopy() *APIMAPISmokeCheck {
+	if in == nil {
+		return nil
+	}
+	out := new(APIMAPISmokeCheck)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *APIMAPISpec) DeepCopyInto(out *APIMAPISpec) {
 	*out = *in
+	if in.Backends != nil {
+		in, out := &in.Backends, &out.Backends
+		*out = new(APIMAPIBackends)
+		**out = **in
+	}
 	if in.Target != nil {
 		in, out := &in.Target, &out.Target
 		*out = new(APIMAPITarget)
@@ -206,6 +427,11 @@ func (in *APIMAPISpec) DeepCopyInto(out *APIMAPISpec) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.Canary != nil {
+		in, out := &in.Canary, &out.Canary
+		*out = new(APIMAPICanary)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APIMAPISpec.
@@ -221,6 +447,43 @@ func (in *APIMAPISpec) DeepCopy() *APIMAPISpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *APIMAPIStatus) DeepCopyInto(out *APIMAPIStatus) {
 	*out = *in
+	if in.Documentation != nil {
+		in, out := &in.Documentation, &out.Documentation
+		*out = new(APIMAPIDocumentation)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TerraformImports != nil {
+		in, out := &in.TerraformImports, &out.TerraformImports
+		*out = make([]TerraformImport, len(*in))
+		copy(*out, *in)
+	}
+	if in.Adopted != nil {
+		in, out := &in.Adopted, &out.Adopted
+		*out = new(APIMAPIAdoptedState)
+		**out = **in
+	}
+	if in.PendingPlan != nil {
+		in, out := &in.PendingPlan, &out.PendingPlan
+		*out = new(APIMAPIPlan)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.History != nil {
+		in, out := &in.History, &out.History
+		*out = make([]APIMAPIDeploymentRecord, len(*in))
+		copy(*out, *in)
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Backend != nil {
+		in, out := &in.Backend, &out.Backend
+		*out = new(APIMAPIBackendStatus)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APIMAPIStatus.
@@ -254,26 +517,26 @@ func (in *APIMAPITarget) DeepCopy() *APIMAPITarget {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *APIMInboundPolicy) DeepCopyInto(out *APIMInboundPolicy) {
+func (in *APIMApiVersionSet) DeepCopyInto(out *APIMApiVersionSet) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	out.Spec = in.Spec
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APIMInboundPolicy.
-func (in *APIMInboundPolicy) DeepCopy() *APIMInboundPolicy {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APIMApiVersionSet.
+func (in *APIMApiVersionSet) DeepCopy() *APIMApiVersionSet {
 	if in == nil {
 		return nil
 	}
-	out := new(APIMInboundPolicy)
+	out := new(APIMApiVersionSet)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *APIMInboundPolicy) DeepCopyObject() runtime.Object {
+func (in *APIMApiVersionSet) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -281,31 +544,31 @@ func (in *APIMInboundPolicy) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *APIMInboundPolicyList) DeepCopyInto(out *APIMInboundPolicyList) {
+func (in *APIMApiVersionSetList) DeepCopyInto(out *APIMApiVersionSetList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]APIMInboundPolicy, len(*in))
+		*out = make([]APIMApiVersionSet, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APIMInboundPolicyList.
-func (in *APIMInboundPolicyList) DeepCopy() *APIMInboundPolicyList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APIMApiVersionSetList.
+func (in *APIMApiVersionSetList) DeepCopy() *APIMApiVersionSetList {
 	if in == nil {
 		return nil
 	}
-	out := new(APIMInboundPolicyList)
+	out := new(APIMApiVersionSetList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *APIMInboundPolicyList) DeepCopyObject() runtime.Object {
+func (in *APIMApiVersionSetList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -313,56 +576,68 @@ func (in *APIMInboundPolicyList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *APIMInboundPolicySpec) DeepCopyInto(out *APIMInboundPolicySpec) {
+func (in *APIMApiVersionSetSpec) DeepCopyInto(out *APIMApiVersionSetSpec) {
 	*out = *in
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APIMInboundPolicySpec.
-func (in *APIMInboundPolicySpec) DeepCopy() *APIMInboundPolicySpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APIMApiVersionSetSpec.
+func (in *APIMApiVersionSetSpec) DeepCopy() *APIMApiVersionSetSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(APIMInboundPolicySpec)
+	out := new(APIMApiVersionSetSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *APIMInboundPolicyStatus) DeepCopyInto(out *APIMInboundPolicyStatus) {
+func (in *APIMApiVersionSetStatus) DeepCopyInto(out *APIMApiVersionSetStatus) {
 	*out = *in
+	if in.TerraformImports != nil {
+		in, out := &in.TerraformImports, &out.TerraformImports
+		*out = make([]TerraformImport, len(*in))
+		copy(*out, *in)
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APIMInboundPolicyStatus.
-func (in *APIMInboundPolicyStatus) DeepCopy() *APIMInboundPolicyStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APIMApiVersionSetStatus.
+func (in *APIMApiVersionSetStatus) DeepCopy() *APIMApiVersionSetStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(APIMInboundPolicyStatus)
+	out := new(APIMApiVersionSetStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *APIMProduct) DeepCopyInto(out *APIMProduct) {
+func (in *APIMBackend) DeepCopyInto(out *APIMBackend) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Spec = in.Spec
-	out.Status = in.Status
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APIMProduct.
-func (in *APIMProduct) DeepCopy() *APIMProduct {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APIMBackend.
+func (in *APIMBackend) DeepCopy() *APIMBackend {
 	if in == nil {
 		return nil
 	}
-	out := new(APIMProduct)
+	out := new(APIMBackend)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *APIMProduct) DeepCopyObject() runtime.Object {
+func (in *APIMBackend) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -370,88 +645,130 @@ func (in *APIMProduct) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *APIMProductList) DeepCopyInto(out *APIMProductList) {
+func (in *APIMBackendAuthorization) DeepCopyInto(out *APIMBackendAuthorization) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ListMeta.DeepCopyInto(&out.ListMeta)
-	if in.Items != nil {
-		in, out := &in.Items, &out.Items
-		*out = make([]APIMProduct, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APIMProductList.
-func (in *APIMProductList) DeepCopy() *APIMProductList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APIMBackendAuthorization.
+func (in *APIMBackendAuthorization) DeepCopy() *APIMBackendAuthorization {
 	if in == nil {
 		return nil
 	}
-	out := new(APIMProductList)
+	out := new(APIMBackendAuthorization)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *APIMProductList) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
-	}
-	return nil
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *APIMProductSpec) DeepCopyInto(out *APIMProductSpec) {
+func (in *APIMBackendCircuitBreaker) DeepCopyInto(out *APIMBackendCircuitBreaker) {
 	*out = *in
+	out.FailureInterval = in.FailureInterval
+	if in.StatusCodeRanges != nil {
+		in, out := &in.StatusCodeRanges, &out.StatusCodeRanges
+		*out = make([]APIMBackendStatusCodeRange, len(*in))
+		copy(*out, *in)
+	}
+	if in.ErrorReasons != nil {
+		in, out := &in.ErrorReasons, &out.ErrorReasons
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	out.TripDuration = in.TripDuration
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APIMProductSpec.
-func (in *APIMProductSpec) DeepCopy() *APIMProductSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APIMBackendCircuitBreaker.
+func (in *APIMBackendCircuitBreaker) DeepCopy() *APIMBackendCircuitBreaker {
 	if in == nil {
 		return nil
 	}
-	out := new(APIMProductSpec)
+	out := new(APIMBackendCircuitBreaker)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *APIMProductStatus) DeepCopyInto(out *APIMProductStatus) {
+func (in *APIMBackendCredentials) DeepCopyInto(out *APIMBackendCredentials) {
 	*out = *in
+	if in.Header != nil {
+		in, out := &in.Header, &out.Header
+		*out = make(map[string][]string, len(*in))
+		for key, val := range *in {
+			var outVal []string
+			if val == nil {
+				(*out)[key] = nil
+			} else {
+				inVal := (*in)[key]
+				in, out := &inVal, &outVal
+				*out = make([]string, len(*in))
+				copy(*out, *in)
+			}
+			(*out)[key] = outVal
+		}
+	}
+	if in.Query != nil {
+		in, out := &in.Query, &out.Query
+		*out = make(map[string][]string, len(*in))
+		for key, val := range *in {
+			var outVal []string
+			if val == nil {
+				(*out)[key] = nil
+			} else {
+				inVal := (*in)[key]
+				in, out := &inVal, &outVal
+				*out = make([]string, len(*in))
+				copy(*out, *in)
+			}
+			(*out)[key] = outVal
+		}
+	}
+	if in.Authorization != nil {
+		in, out := &in.Authorization, &out.Authorization
+		*out = new(APIMBackendAuthorization)
+		**out = **in
+	}
+	if in.CertificateIDs != nil {
+		in, out := &in.CertificateIDs, &out.CertificateIDs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APIMProductStatus.
-func (in *APIMProductStatus) DeepCopy() *APIMProductStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APIMBackendCredentials.
+func (in *APIMBackendCredentials) DeepCopy() *APIMBackendCredentials {
 	if in == nil {
 		return nil
 	}
-	out := new(APIMProductStatus)
+	out := new(APIMBackendCredentials)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *APIMService) DeepCopyInto(out *APIMService) {
+func (in *APIMBackendList) DeepCopyInto(out *APIMBackendList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
-	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Spec = in.Spec
-	out.Status = in.Status
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]APIMBackend, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APIMService.
-func (in *APIMService) DeepCopy() *APIMService {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APIMBackendList.
+func (in *APIMBackendList) DeepCopy() *APIMBackendList {
 	if in == nil {
 		return nil
 	}
-	out := new(APIMService)
+	out := new(APIMBackendList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *APIMService) DeepCopyObject() runtime.Object {
+func (in *APIMBackendList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -459,10 +776,697 @@ func (in *APIMService) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *APIMServiceList) DeepCopyInto(out *APIMServiceList) {
+func (in *APIMBackendSpec) DeepCopyInto(out *APIMBackendSpec) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Credentials != nil {
+		in, out := &in.Credentials, &out.Credentials
+		*out = new(APIMBackendCredentials)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TLS != nil {
+		in, out := &in.TLS, &out.TLS
+		*out = new(APIMBackendTLS)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CircuitBreaker != nil {
+		in, out := &in.CircuitBreaker, &out.CircuitBreaker
+		*out = new(APIMBackendCircuitBreaker)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APIMBackendSpec.
+func (in *APIMBackendSpec) DeepCopy() *APIMBackendSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(APIMBackendSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *APIMBackendStatus) DeepCopyInto(out *APIMBackendStatus) {
+	*out = *in
+	if in.TerraformImports != nil {
+		in, out := &in.TerraformImports, &out.TerraformImports
+		*out = make([]TerraformImport, len(*in))
+		copy(*out, *in)
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APIMBackendStatus.
+func (in *APIMBackendStatus) DeepCopy() *APIMBackendStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(APIMBackendStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *APIMBackendStatusCodeRange) DeepCopyInto(out *APIMBackendStatusCodeRange) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APIMBackendStatusCodeRange.
+func (in *APIMBackendStatusCodeRange) DeepCopy() *APIMBackendStatusCodeRange {
+	if in == nil {
+		return nil
+	}
+	out := new(APIMBackendStatusCodeRange)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *APIMBackendTLS) DeepCopyInto(out *APIMBackendTLS) {
+	*out = *in
+	if in.ValidateCertificateChain != nil {
+		in, out := &in.ValidateCertificateChain, &out.ValidateCertificateChain
+		*out = new(bool)
+		**out = **in
+	}
+	if in.ValidateCertificateName != nil {
+		in, out := &in.ValidateCertificateName, &out.ValidateCertificateName
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APIMBackendTLS.
+func (in *APIMBackendTLS) DeepCopy() *APIMBackendTLS {
+	if in == nil {
+		return nil
+	}
+	out := new(APIMBackendTLS)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *APIMCluster) DeepCopyInto(out *APIMCluster) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APIMCluster.
+func (in *APIMCluster) DeepCopy() *APIMCluster {
+	if in == nil {
+		return nil
+	}
+	out := new(APIMCluster)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *APIMCluster) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *APIMClusterList) DeepCopyInto(out *APIMClusterList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]APIMCluster, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APIMClusterList.
+func (in *APIMClusterList) DeepCopy() *APIMClusterList {
+	if in == nil {
+		return nil
+	}
+	out := new(APIMClusterList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *APIMClusterList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *APIMClusterSpec) DeepCopyInto(out *APIMClusterSpec) {
+	*out = *in
+	out.KubeconfigSecretRef = in.KubeconfigSecretRef
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APIMClusterSpec.
+func (in *APIMClusterSpec) DeepCopy() *APIMClusterSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(APIMClusterSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *APIMClusterStatus) DeepCopyInto(out *APIMClusterStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APIMClusterStatus.
+func (in *APIMClusterStatus) DeepCopy() *APIMClusterStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(APIMClusterStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *APIMInboundPolicy) DeepCopyInto(out *APIMInboundPolicy) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APIMInboundPolicy.
+func (in *APIMInboundPolicy) DeepCopy() *APIMInboundPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(APIMInboundPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *APIMInboundPolicy) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *APIMInboundPolicyList) DeepCopyInto(out *APIMInboundPolicyList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]APIMInboundPolicy, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APIMInboundPolicyList.
+func (in *APIMInboundPolicyList) DeepCopy() *APIMInboundPolicyList {
+	if in == nil {
+		return nil
+	}
+	out := new(APIMInboundPolicyList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *APIMInboundPolicyList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *APIMInboundPolicySpec) DeepCopyInto(out *APIMInboundPolicySpec) {
+	*out = *in
+	if in.TemplateValues != nil {
+		in, out := &in.TemplateValues, &out.TemplateValues
+		*out = make([]APIMInboundPolicyTemplateValue, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APIMInboundPolicySpec.
+func (in *APIMInboundPolicySpec) DeepCopy() *APIMInboundPolicySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(APIMInboundPolicySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *APIMInboundPolicyStatus) DeepCopyInto(out *APIMInboundPolicyStatus) {
+	*out = *in
+	if in.TerraformImports != nil {
+		in, out := &in.TerraformImports, &out.TerraformImports
+		*out = make([]TerraformImport, len(*in))
+		copy(*out, *in)
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APIMInboundPolicyStatus.
+func (in *APIMInboundPolicyStatus) DeepCopy() *APIMInboundPolicyStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(APIMInboundPolicyStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *APIMInboundPolicyTemplateValue) DeepCopyInto(out *APIMInboundPolicyTemplateValue) {
+	*out = *in
+	if in.ConfigMapKeyRef != nil {
+		in, out := &in.ConfigMapKeyRef, &out.ConfigMapKeyRef
+		*out = new(ConfigMapKeyRef)
+		**out = **in
+	}
+	if in.SecretKeyRef != nil {
+		in, out := &in.SecretKeyRef, &out.SecretKeyRef
+		*out = new(SecretKeyRef)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APIMInboundPolicyTemplateValue.
+func (in *APIMInboundPolicyTemplateValue) DeepCopy() *APIMInboundPolicyTemplateValue {
+	if in == nil {
+		return nil
+	}
+	out := new(APIMInboundPolicyTemplateValue)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *APIMNamedValue) DeepCopyInto(out *APIMNamedValue) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APIMNamedValue.
+func (in *APIMNamedValue) DeepCopy() *APIMNamedValue {
+	if in == nil {
+		return nil
+	}
+	out := new(APIMNamedValue)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *APIMNamedValue) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *APIMNamedValueKeyVault) DeepCopyInto(out *APIMNamedValueKeyVault) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APIMNamedValueKeyVault.
+func (in *APIMNamedValueKeyVault) DeepCopy() *APIMNamedValueKeyVault {
+	if in == nil {
+		return nil
+	}
+	out := new(APIMNamedValueKeyVault)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *APIMNamedValueList) DeepCopyInto(out *APIMNamedValueList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]APIMNamedValue, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APIMNamedValueList.
+func (in *APIMNamedValueList) DeepCopy() *APIMNamedValueList {
+	if in == nil {
+		return nil
+	}
+	out := new(APIMNamedValueList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *APIMNamedValueList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *APIMNamedValueSpec) DeepCopyInto(out *APIMNamedValueSpec) {
+	*out = *in
+	if in.SecretRef != nil {
+		in, out := &in.SecretRef, &out.SecretRef
+		*out = new(SecretKeyRef)
+		**out = **in
+	}
+	if in.KeyVault != nil {
+		in, out := &in.KeyVault, &out.KeyVault
+		*out = new(APIMNamedValueKeyVault)
+		**out = **in
+	}
+	if in.RefreshInterval != nil {
+		in, out := &in.RefreshInterval, &out.RefreshInterval
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APIMNamedValueSpec.
+func (in *APIMNamedValueSpec) DeepCopy() *APIMNamedValueSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(APIMNamedValueSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *APIMNamedValueStatus) DeepCopyInto(out *APIMNamedValueStatus) {
+	*out = *in
+	if in.TerraformImports != nil {
+		in, out := &in.TerraformImports, &out.TerraformImports
+		*out = make([]TerraformImport, len(*in))
+		copy(*out, *in)
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APIMNamedValueStatus.
+func (in *APIMNamedValueStatus) DeepCopy() *APIMNamedValueStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(APIMNamedValueStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *APIMProduct) DeepCopyInto(out *APIMProduct) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APIMProduct.
+func (in *APIMProduct) DeepCopy() *APIMProduct {
+	if in == nil {
+		return nil
+	}
+	out := new(APIMProduct)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *APIMProduct) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *APIMProductList) DeepCopyInto(out *APIMProductList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]APIMProduct, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APIMProductList.
+func (in *APIMProductList) DeepCopy() *APIMProductList {
+	if in == nil {
+		return nil
+	}
+	out := new(APIMProductList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *APIMProductList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *APIMProductSpec) DeepCopyInto(out *APIMProductSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APIMProductSpec.
+func (in *APIMProductSpec) DeepCopy() *APIMProductSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(APIMProductSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *APIMProductStatus) DeepCopyInto(out *APIMProductStatus) {
+	*out = *in
+	if in.TerraformImports != nil {
+		in, out := &in.TerraformImports, &out.TerraformImports
+		*out = make([]TerraformImport, len(*in))
+		copy(*out, *in)
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APIMProductStatus.
+func (in *APIMProductStatus) DeepCopy() *APIMProductStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(APIMProductStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *APIMService) DeepCopyInto(out *APIMService) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APIMService.
+func (in *APIMService) DeepCopy() *APIMService {
+	if in == nil {
+		return nil
+	}
+	out := new(APIMService)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *APIMService) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *APIMServiceAPICenter) DeepCopyInto(out *APIMServiceAPICenter) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APIMServiceAPICenter.
+func (in *APIMServiceAPICenter) DeepCopy() *APIMServiceAPICenter {
+	if in == nil {
+		return nil
+	}
+	out := new(APIMServiceAPICenter)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *APIMServiceBackup) DeepCopyInto(out *APIMServiceBackup) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APIMServiceBackup.
+func (in *APIMServiceBackup) DeepCopy() *APIMServiceBackup {
+	if in == nil {
+		return nil
+	}
+	out := new(APIMServiceBackup)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *APIMServiceBackupOperation) DeepCopyInto(out *APIMServiceBackupOperation) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APIMServiceBackupOperation.
+func (in *APIMServiceBackupOperation) DeepCopy() *APIMServiceBackupOperation {
+	if in == nil {
+		return nil
+	}
+	out := new(APIMServiceBackupOperation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *APIMServiceBackupStatus) DeepCopyInto(out *APIMServiceBackupStatus) {
+	*out = *in
+	if in.InProgress != nil {
+		in, out := &in.InProgress, &out.InProgress
+		*out = new(APIMServiceBackupOperation)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APIMServiceBackupStatus.
+func (in *APIMServiceBackupStatus) DeepCopy() *APIMServiceBackupStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(APIMServiceBackupStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *APIMServiceCapabilities) DeepCopyInto(out *APIMServiceCapabilities) {
+	*out = *in
+	if in.UnsupportedPolicies != nil {
+		in, out := &in.UnsupportedPolicies, &out.UnsupportedPolicies
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APIMServiceCapabilities.
+func (in *APIMServiceCapabilities) DeepCopy() *APIMServiceCapabilities {
+	if in == nil {
+		return nil
+	}
+	out := new(APIMServiceCapabilities)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *APIMServiceDeploymentStatus) DeepCopyInto(out *APIMServiceDeploymentStatus) {
+	*out = *in
+	out.TokenSecretRef = in.TokenSecretRef
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APIMServiceDeploymentStatus.
+func (in *APIMServiceDeploymentStatus) DeepCopy() *APIMServiceDeploymentStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(APIMServiceDeploymentStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *APIMServiceList) DeepCopyInto(out *APIMServiceList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
 		*out = make([]APIMService, len(*in))
@@ -490,9 +1494,112 @@ func (in *APIMServiceList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *APIMServiceNamespaceQuota) DeepCopyInto(out *APIMServiceNamespaceQuota) {
+	*out = *in
+	if in.MaxAPIs != nil {
+		in, out := &in.MaxAPIs, &out.MaxAPIs
+		*out = new(int32)
+		**out = **in
+	}
+	if in.MaxProducts != nil {
+		in, out := &in.MaxProducts, &out.MaxProducts
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APIMServiceNamespaceQuota.
+func (in *APIMServiceNamespaceQuota) DeepCopy() *APIMServiceNamespaceQuota {
+	if in == nil {
+		return nil
+	}
+	out := new(APIMServiceNamespaceQuota)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *APIMServiceNotification) DeepCopyInto(out *APIMServiceNotification) {
+	*out = *in
+	out.URLSecretRef = in.URLSecretRef
+	if in.On != nil {
+		in, out := &in.On, &out.On
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APIMServiceNotification.
+func (in *APIMServiceNotification) DeepCopy() *APIMServiceNotification {
+	if in == nil {
+		return nil
+	}
+	out := new(APIMServiceNotification)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *APIMServicePortalPublishing) DeepCopyInto(out *APIMServicePortalPublishing) {
+	*out = *in
+	if in.Debounce != nil {
+		in, out := &in.Debounce, &out.Debounce
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APIMServicePortalPublishing.
+func (in *APIMServicePortalPublishing) DeepCopy() *APIMServicePortalPublishing {
+	if in == nil {
+		return nil
+	}
+	out := new(APIMServicePortalPublishing)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *APIMServiceSpec) DeepCopyInto(out *APIMServiceSpec) {
 	*out = *in
+	if in.NamespaceQuota != nil {
+		in, out := &in.NamespaceQuota, &out.NamespaceQuota
+		*out = new(APIMServiceNamespaceQuota)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.APICenter != nil {
+		in, out := &in.APICenter, &out.APICenter
+		*out = new(APIMServiceAPICenter)
+		**out = **in
+	}
+	if in.PortalPublishing != nil {
+		in, out := &in.PortalPublishing, &out.PortalPublishing
+		*out = new(APIMServicePortalPublishing)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TelemetryTags != nil {
+		in, out := &in.TelemetryTags, &out.TelemetryTags
+		*out = new(APIMServiceTelemetryTags)
+		**out = **in
+	}
+	if in.Notifications != nil {
+		in, out := &in.Notifications, &out.Notifications
+		*out = make([]APIMServiceNotification, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.DeploymentStatus != nil {
+		in, out := &in.DeploymentStatus, &out.DeploymentStatus
+		*out = new(APIMServiceDeploymentStatus)
+		**out = **in
+	}
+	if in.Backup != nil {
+		in, out := &in.Backup, &out.Backup
+		*out = new(APIMServiceBackup)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APIMServiceSpec.
@@ -508,6 +1615,23 @@ func (in *APIMServiceSpec) DeepCopy() *APIMServiceSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *APIMServiceStatus) DeepCopyInto(out *APIMServiceStatus) {
 	*out = *in
+	if in.Capabilities != nil {
+		in, out := &in.Capabilities, &out.Capabilities
+		*out = new(APIMServiceCapabilities)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Backup != nil {
+		in, out := &in.Backup, &out.Backup
+		*out = new(APIMServiceBackupStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APIMServiceStatus.
@@ -520,13 +1644,144 @@ func (in *APIMServiceStatus) DeepCopy() *APIMServiceStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *APIMServiceTelemetryTags) DeepCopyInto(out *APIMServiceTelemetryTags) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APIMServiceTelemetryTags.
+func (in *APIMServiceTelemetryTags) DeepCopy() *APIMServiceTelemetryTags {
+	if in == nil {
+		return nil
+	}
+	out := new(APIMServiceTelemetryTags)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *APIMSubscription) DeepCopyInto(out *APIMSubscription) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APIMSubscription.
+func (in *APIMSubscription) DeepCopy() *APIMSubscription {
+	if in == nil {
+		return nil
+	}
+	out := new(APIMSubscription)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *APIMSubscription) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *APIMSubscriptionList) DeepCopyInto(out *APIMSubscriptionList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]APIMSubscription, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APIMSubscriptionList.
+func (in *APIMSubscriptionList) DeepCopy() *APIMSubscriptionList {
+	if in == nil {
+		return nil
+	}
+	out := new(APIMSubscriptionList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *APIMSubscriptionList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *APIMSubscriptionSpec) DeepCopyInto(out *APIMSubscriptionSpec) {
+	*out = *in
+	if in.TargetNamespaces != nil {
+		in, out := &in.TargetNamespaces, &out.TargetNamespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.RotationInterval != nil {
+		in, out := &in.RotationInterval, &out.RotationInterval
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APIMSubscriptionSpec.
+func (in *APIMSubscriptionSpec) DeepCopy() *APIMSubscriptionSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(APIMSubscriptionSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *APIMSubscriptionStatus) DeepCopyInto(out *APIMSubscriptionStatus) {
+	*out = *in
+	if in.TerraformImports != nil {
+		in, out := &in.TerraformImports, &out.TerraformImports
+		*out = make([]TerraformImport, len(*in))
+		copy(*out, *in)
+	}
+	if in.SecretNamespaces != nil {
+		in, out := &in.SecretNamespaces, &out.SecretNamespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APIMSubscriptionStatus.
+func (in *APIMSubscriptionStatus) DeepCopy() *APIMSubscriptionStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(APIMSubscriptionStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *APIMTag) DeepCopyInto(out *APIMTag) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	out.Spec = in.Spec
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APIMTag.
@@ -597,6 +1852,18 @@ func (in *APIMTagSpec) DeepCopy() *APIMTagSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *APIMTagStatus) DeepCopyInto(out *APIMTagStatus) {
 	*out = *in
+	if in.TerraformImports != nil {
+		in, out := &in.TerraformImports, &out.TerraformImports
+		*out = make([]TerraformImport, len(*in))
+		copy(*out, *in)
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APIMTagStatus.
@@ -608,3 +1875,48 @@ func (in *APIMTagStatus) DeepCopy() *APIMTagStatus {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConfigMapKeyRef) DeepCopyInto(out *ConfigMapKeyRef) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigMapKeyRef.
+func (in *ConfigMapKeyRef) DeepCopy() *ConfigMapKeyRef {
+	if in == nil {
+		return nil
+	}
+	out := new(ConfigMapKeyRef)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecretKeyRef) DeepCopyInto(out *SecretKeyRef) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretKeyRef.
+func (in *SecretKeyRef) DeepCopy() *SecretKeyRef {
+	if in == nil {
+		return nil
+	}
+	out := new(SecretKeyRef)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TerraformImport) DeepCopyInto(out *TerraformImport) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TerraformImport.
+func (in *TerraformImport) DeepCopy() *TerraformImport {
+	if in == nil {
+		return nil
+	}
+	out := new(TerraformImport)
+	in.DeepCopyInto(out)
+	return out
+}
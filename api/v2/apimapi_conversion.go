@@ -0,0 +1,180 @@
+package v2
+
+import (
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+
+	apimv1 "github.com/hedinit/azure-apim-operator/api/v1"
+)
+
+// ConvertTo converts this v2 APIMAPI to the v1 hub version.
+// An unset subscriptionRequired becomes true, matching the v1 default.
+func (src *APIMAPI) ConvertTo(dstRaw conversion.Hub) error {
+	dst := dstRaw.(*apimv1.APIMAPI)
+	dst.ObjectMeta = src.ObjectMeta
+
+	subscriptionRequired := true
+	if src.Spec.SubscriptionRequired != nil {
+		subscriptionRequired = *src.Spec.SubscriptionRequired
+	}
+
+	dst.Spec = apimv1.APIMAPISpec{
+		ServiceURL:           src.Spec.ServiceURL,
+		RoutePrefix:          src.Spec.RoutePrefix,
+		OpenAPIDefinitionURL: src.Spec.OpenAPIDefinitionURL,
+		ProductIDs:           src.Spec.ProductIDs,
+		TagIDs:               src.Spec.TagIDs,
+		APIMService:          src.Spec.APIMService,
+		APIID:                src.Spec.APIID,
+		SubscriptionRequired: subscriptionRequired,
+		AdoptionPolicy:       apimv1.AdoptionPolicy(src.Spec.AdoptionPolicy),
+		DeletionPolicy:       apimv1.DeletionPolicy(src.Spec.DeletionPolicy),
+		ApprovalRequired:     src.Spec.ApprovalRequired,
+		Owner:                src.Spec.Owner,
+		ActiveBackend:        apimv1.BackendColor(src.Spec.ActiveBackend),
+		VersionSetID:         src.Spec.VersionSetID,
+		APIVersion:           src.Spec.APIVersion,
+	}
+	if src.Spec.Target != nil {
+		dst.Spec.Target = &apimv1.APIMAPITarget{Selector: src.Spec.Target.Selector}
+	}
+	if src.Spec.Backends != nil {
+		backends := apimv1.APIMAPIBackends(*src.Spec.Backends)
+		dst.Spec.Backends = &backends
+	}
+	if src.Spec.Canary != nil {
+		smokeCheck := src.Spec.Canary.SmokeCheck
+		dst.Spec.Canary = &apimv1.APIMAPICanary{SmokeCheck: apimv1.APIMAPISmokeCheck{
+			Path:           smokeCheck.Path,
+			ExpectedStatus: smokeCheck.ExpectedStatus,
+			Attempts:       smokeCheck.Attempts,
+			Timeout:        smokeCheck.Timeout,
+		}}
+		if ref := smokeCheck.SubscriptionKeySecretRef; ref != nil {
+			dst.Spec.Canary.SmokeCheck.SubscriptionKeySecretRef = &apimv1.SecretKeyRef{Name: ref.Name, Key: ref.Key}
+		}
+	}
+
+	dst.Status = apimv1.APIMAPIStatus{
+		ImportedAt:          src.Status.ImportedAt,
+		Status:              src.Status.Status,
+		ApiHost:             src.Status.APIHost,
+		DeveloperPortalHost: src.Status.DeveloperPortalHost,
+		DeveloperPortalURL:  src.Status.DeveloperPortalURL,
+		DefinitionURL:       src.Status.DefinitionURL,
+		AzureResourceID:     src.Status.AzureResourceID,
+		Conditions:          src.Status.Conditions,
+		ObservedGeneration:  src.Status.ObservedGeneration,
+	}
+	if src.Status.Documentation != nil {
+		documentation := apimv1.APIMAPIDocumentation(*src.Status.Documentation)
+		dst.Status.Documentation = &documentation
+	}
+	if src.Status.Adopted != nil {
+		adopted := apimv1.APIMAPIAdoptedState(*src.Status.Adopted)
+		dst.Status.Adopted = &adopted
+	}
+	if src.Status.PendingPlan != nil {
+		plan := apimv1.APIMAPIPlan(*src.Status.PendingPlan)
+		dst.Status.PendingPlan = &plan
+	}
+	if backend := src.Status.Backend; backend != nil {
+		dst.Status.Backend = &apimv1.APIMAPIBackendStatus{
+			Active:             apimv1.BackendColor(backend.Active),
+			ServiceURL:         backend.ServiceURL,
+			Previous:           apimv1.BackendColor(backend.Previous),
+			PreviousServiceURL: backend.PreviousServiceURL,
+			SwitchedAt:         backend.SwitchedAt,
+		}
+	}
+	for _, record := range src.Status.History {
+		dst.Status.History = append(dst.Status.History, apimv1.APIMAPIDeploymentRecord(record))
+	}
+	for _, hint := range src.Status.TerraformImports {
+		dst.Status.TerraformImports = append(dst.Status.TerraformImports, apimv1.TerraformImport(hint))
+	}
+	return nil
+}
+
+// ConvertFrom converts the v1 hub version to this v2 APIMAPI.
+func (dst *APIMAPI) ConvertFrom(srcRaw conversion.Hub) error {
+	src := srcRaw.(*apimv1.APIMAPI)
+	dst.ObjectMeta = src.ObjectMeta
+
+	subscriptionRequired := src.Spec.SubscriptionRequired
+	dst.Spec = APIMAPISpec{
+		ServiceURL:           src.Spec.ServiceURL,
+		RoutePrefix:          src.Spec.RoutePrefix,
+		OpenAPIDefinitionURL: src.Spec.OpenAPIDefinitionURL,
+		ProductIDs:           src.Spec.ProductIDs,
+		TagIDs:               src.Spec.TagIDs,
+		APIMService:          src.Spec.APIMService,
+		APIID:                src.Spec.APIID,
+		SubscriptionRequired: &subscriptionRequired,
+		AdoptionPolicy:       AdoptionPolicy(src.Spec.AdoptionPolicy),
+		DeletionPolicy:       DeletionPolicy(src.Spec.DeletionPolicy),
+		ApprovalRequired:     src.Spec.ApprovalRequired,
+		Owner:                src.Spec.Owner,
+		ActiveBackend:        BackendColor(src.Spec.ActiveBackend),
+		VersionSetID:         src.Spec.VersionSetID,
+		APIVersion:           src.Spec.APIVersion,
+	}
+	if src.Spec.Target != nil {
+		dst.Spec.Target = &APIMAPITarget{Selector: src.Spec.Target.Selector}
+	}
+	if src.Spec.Backends != nil {
+		backends := APIMAPIBackends(*src.Spec.Backends)
+		dst.Spec.Backends = &backends
+	}
+	if src.Spec.Canary != nil {
+		smokeCheck := src.Spec.Canary.SmokeCheck
+		dst.Spec.Canary = &APIMAPICanary{SmokeCheck: APIMAPISmokeCheck{
+			Path:           smokeCheck.Path,
+			ExpectedStatus: smokeCheck.ExpectedStatus,
+			Attempts:       smokeCheck.Attempts,
+			Timeout:        smokeCheck.Timeout,
+		}}
+		if ref := smokeCheck.SubscriptionKeySecretRef; ref != nil {
+			dst.Spec.Canary.SmokeCheck.SubscriptionKeySecretRef = &SecretKeyRef{Name: ref.Name, Key: ref.Key}
+		}
+	}
+
+	dst.Status = APIMAPIStatus{
+		ImportedAt:          src.Status.ImportedAt,
+		Status:              src.Status.Status,
+		APIHost:             src.Status.ApiHost,
+		DeveloperPortalHost: src.Status.DeveloperPortalHost,
+		DeveloperPortalURL:  src.Status.DeveloperPortalURL,
+		DefinitionURL:       src.Status.DefinitionURL,
+		AzureResourceID:     src.Status.AzureResourceID,
+		Conditions:          src.Status.Conditions,
+		ObservedGeneration:  src.Status.ObservedGeneration,
+	}
+	if src.Status.Documentation != nil {
+		documentation := APIMAPIDocumentation(*src.Status.Documentation)
+		dst.Status.Documentation = &documentation
+	}
+	if src.Status.Adopted != nil {
+		adopted := APIMAPIAdoptedState(*src.Status.Adopted)
+		dst.Status.Adopted = &adopted
+	}
+	if src.Status.PendingPlan != nil {
+		plan := APIMAPIPlan(*src.Status.PendingPlan)
+		dst.Status.PendingPlan = &plan
+	}
+	if backend := src.Status.Backend; backend != nil {
+		dst.Status.Backend = &APIMAPIBackendStatus{
+			Active:             BackendColor(backend.Active),
+			ServiceURL:         backend.ServiceURL,
+			Previous:           BackendColor(backend.Previous),
+			PreviousServiceURL: backend.PreviousServiceURL,
+			SwitchedAt:         backend.SwitchedAt,
+		}
+	}
+	for _, record := range src.Status.History {
+		dst.Status.History = append(dst.Status.History, APIMAPIDeploymentRecord(record))
+	}
+	for _, hint := range src.Status.TerraformImports {
+		dst.Status.TerraformImports = append(dst.Status.TerraformImports, TerraformImport(hint))
+	}
+	return nil
+}
@@ -0,0 +1,129 @@
+package v2
+
+import (
+	"reflect"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apimv1 "github.com/hedinit/azure-apim-operator/api/v1"
+)
+
+func TestConvertToDefaultsSubscriptionRequired(t *testing.T) {
+	notRequired := false
+	tests := []struct {
+		name                 string
+		subscriptionRequired *bool
+		want                 bool
+	}{
+		{name: "unset", subscriptionRequired: nil, want: true},
+		{name: "explicit false", subscriptionRequired: &notRequired, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			src := &APIMAPI{Spec: APIMAPISpec{APIID: "payment-api", SubscriptionRequired: tt.subscriptionRequired}}
+			dst := &apimv1.APIMAPI{}
+			if err := src.ConvertTo(dst); err != nil {
+				t.Fatalf("ConvertTo() error = %v", err)
+			}
+			if dst.Spec.SubscriptionRequired != tt.want {
+				t.Errorf("SubscriptionRequired = %t, want %t", dst.Spec.SubscriptionRequired, tt.want)
+			}
+			if dst.Spec.APIID != "payment-api" {
+				t.Errorf("APIID = %q, want %q", dst.Spec.APIID, "payment-api")
+			}
+		})
+	}
+}
+
+func TestConvertRoundTrip(t *testing.T) {
+	hub := &apimv1.APIMAPI{
+		ObjectMeta: metav1.ObjectMeta{Name: "payment-public", Namespace: "integrations"},
+		Spec: apimv1.APIMAPISpec{
+			APIID:                "payment-api",
+			APIMService:          "my-apim",
+			RoutePrefix:          "/payments",
+			Backends:             &apimv1.APIMAPIBackends{Blue: "https://payments-blue.example.com", Green: "https://payments-green.example.com"},
+			ActiveBackend:        apimv1.BackendGreen,
+			SubscriptionRequired: false,
+			ProductIDs:           []string{"integrations-product"},
+			AdoptionPolicy:       apimv1.AdoptionPolicyAdopt,
+			Owner:                "group:default/payments",
+			VersionSetID:         "payments",
+			APIVersion:           "v2",
+			Canary: &apimv1.APIMAPICanary{SmokeCheck: apimv1.APIMAPISmokeCheck{
+				Path:                     "/health",
+				SubscriptionKeySecretRef: &apimv1.SecretKeyRef{Name: "smoke-key", Key: "key"},
+			}},
+		},
+		Status: apimv1.APIMAPIStatus{
+			ApiHost:            "https://apim.azure-api.net/payments",
+			DefinitionURL:      "https://payments.internal.example.com/swagger/v1/swagger.json",
+			PendingPlan:        &apimv1.APIMAPIPlan{Hash: "abc", Changes: []string{"openApi: definition changed"}},
+			History:            []apimv1.APIMAPIDeploymentRecord{{Timestamp: "2026-01-12T09:30:00Z", SpecHash: "abc", Outcome: "Succeeded"}},
+			ObservedGeneration: 4,
+			Backend:            &apimv1.APIMAPIBackendStatus{Active: apimv1.BackendGreen, Previous: apimv1.BackendBlue},
+			DeveloperPortalURL: "https://apim.developer.azure-api.net/api-details#api=payment-api",
+			Documentation: &apimv1.APIMAPIDocumentation{
+				Title: "Payments", ExternalDocsURL: "https://docs.example.com/payments", Operations: 3,
+				UndocumentedOperations: []string{"DELETE /payments/{id}"},
+			},
+			TerraformImports: []apimv1.TerraformImport{{
+				Resource:  "azurerm_api_management_api",
+				ID:        "/subscriptions/sub/resourceGroups/rg/providers/Microsoft.ApiManagement/service/apim/apis/payment-api;rev=1",
+				AzapiType: "Microsoft.ApiManagement/service/apis@2021-08-01",
+				AzapiID:   "/subscriptions/sub/resourceGroups/rg/providers/Microsoft.ApiManagement/service/apim/apis/payment-api",
+			}},
+		},
+	}
+
+	spoke := &APIMAPI{}
+	if err := spoke.ConvertFrom(hub); err != nil {
+		t.Fatalf("ConvertFrom() error = %v", err)
+	}
+	if spoke.Spec.APIID != "payment-api" || spoke.Status.APIHost != hub.Status.ApiHost {
+		t.Fatalf("ConvertFrom() = %+v, want apiId and apiHost copied", spoke)
+	}
+	if spoke.Spec.SubscriptionRequired == nil || *spoke.Spec.SubscriptionRequired {
+		t.Fatalf("SubscriptionRequired = %v, want explicit false", spoke.Spec.SubscriptionRequired)
+	}
+
+	back := &apimv1.APIMAPI{}
+	if err := spoke.ConvertTo(back); err != nil {
+		t.Fatalf("ConvertTo() error = %v", err)
+	}
+	if back.Name != hub.Name || back.Spec.APIID != hub.Spec.APIID || back.Spec.SubscriptionRequired != hub.Spec.SubscriptionRequired ||
+		back.Spec.AdoptionPolicy != hub.Spec.AdoptionPolicy || back.Status.PendingPlan.Hash != "abc" ||
+		len(back.Status.History) != 1 || back.Status.History[0] != hub.Status.History[0] ||
+		back.Status.ObservedGeneration != hub.Status.ObservedGeneration ||
+		back.Spec.Owner != hub.Spec.Owner || back.Status.DefinitionURL != hub.Status.DefinitionURL ||
+		!reflect.DeepEqual(back.Spec.Canary, hub.Spec.Canary) || !reflect.DeepEqual(back.Spec.Backends, hub.Spec.Backends) ||
+		back.Spec.ActiveBackend != hub.Spec.ActiveBackend || back.Spec.VersionSetID != hub.Spec.VersionSetID ||
+		back.Spec.APIVersion != hub.Spec.APIVersion || !reflect.DeepEqual(back.Status.Backend, hub.Status.Backend) ||
+		!reflect.DeepEqual(back.Status.TerraformImports, hub.Status.TerraformImports) ||
+		back.Status.DeveloperPortalURL != hub.Status.DeveloperPortalURL ||
+		!reflect.DeepEqual(back.Status.Documentation, hub.Status.Documentation) {
+		t.Errorf("round trip = %+v, want %+v", back, hub)
+	}
+}
+
+func TestConvertRoundTripKeepsOrphanDeletionPolicy(t *testing.T) {
+	hub := &apimv1.APIMAPI{Spec: apimv1.APIMAPISpec{APIID: "payment-api", DeletionPolicy: apimv1.DeletionPolicyOrphan}}
+
+	spoke := &APIMAPI{}
+	if err := spoke.ConvertFrom(hub); err != nil {
+		t.Fatalf("ConvertFrom() error = %v", err)
+	}
+	if spoke.Spec.DeletionPolicy != "Orphan" {
+		t.Fatalf("DeletionPolicy = %q, want Orphan", spoke.Spec.DeletionPolicy)
+	}
+
+	back := &apimv1.APIMAPI{}
+	if err := spoke.ConvertTo(back); err != nil {
+		t.Fatalf("ConvertTo() error = %v", err)
+	}
+	if back.Spec.DeletionPolicy != apimv1.DeletionPolicyOrphan {
+		t.Errorf("DeletionPolicy after round trip = %q, want Orphan", back.Spec.DeletionPolicy)
+	}
+}
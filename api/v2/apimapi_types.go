@@ -0,0 +1,320 @@
+package v2
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// APIMAPITarget defines how an APIMAPI maps to workloads in the cluster.
+// When Selector is omitted, the legacy behavior is used and the APIMAPI name
+// must match the ReplicaSet app.kubernetes.io/name label.
+type APIMAPITarget struct {
+	// Selector matches ReplicaSets whose readiness events should trigger this API import.
+	Selector *metav1.LabelSelector `json:"selector,omitempty"`
+}
+
+// AdoptionPolicy controls how the operator treats an API that already exists in APIM
+// before the operator has ever imported it.
+// +kubebuilder:validation:Enum=Adopt;Fail;Overwrite
+type AdoptionPolicy string
+
+// DeletionPolicy controls what happens to the API in APIM when its APIMAPI is deleted.
+// +kubebuilder:validation:Enum=Delete;Orphan
+type DeletionPolicy string
+
+// APIMAPICanary configures canary rollouts: each import of a changed API creates a new revision, which is
+// made current only after its smoke check passed.
+type APIMAPICanary struct {
+	// SmokeCheck is the HTTP probe sent to the new revision through the gateway.
+	SmokeCheck APIMAPISmokeCheck `json:"smokeCheck"`
+}
+
+// APIMAPISmokeCheck is an HTTP GET request sent to a revision through the gateway.
+type APIMAPISmokeCheck struct {
+	// Path is requested relative to the URL of the revision, e.g. "/health".
+	// +kubebuilder:validation:XValidation:rule="self.startsWith('/')",message="path must start with '/'"
+	Path string `json:"path"`
+	// ExpectedStatus is the HTTP status the revision must answer with. Defaults to 200.
+	// +kubebuilder:validation:Minimum=100
+	// +kubebuilder:validation:Maximum=599
+	// +optional
+	ExpectedStatus int32 `json:"expectedStatus,omitempty"`
+	// Attempts is how often the probe is sent before the revision is rolled back. Defaults to 3.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=10
+	// +optional
+	Attempts int32 `json:"attempts,omitempty"`
+	// Timeout bounds each probe. Defaults to 10s.
+	// +optional
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+	// SubscriptionKeySecretRef selects a subscription key in a Secret of the APIMAPI's namespace.
+	// +optional
+	SubscriptionKeySecretRef *SecretKeyRef `json:"subscriptionKeySecretRef,omitempty"`
+}
+
+// SecretKeyRef selects a key of a Kubernetes Secret in the namespace of the referencing resource.
+type SecretKeyRef struct {
+	// Name is the name of the Secret.
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+	// Key is the key of the value in the Secret.
+	// +kubebuilder:validation:MinLength=1
+	Key string `json:"key"`
+}
+
+// BackendColor names one of the two backends of a blue/green APIMAPI.
+// +kubebuilder:validation:Enum=Blue;Green
+type BackendColor string
+
+// APIMAPIBackends declares the two backend URLs of a blue/green deployment.
+type APIMAPIBackends struct {
+	// Blue is the URL of the blue backend.
+	// +kubebuilder:validation:MinLength=1
+	Blue string `json:"blue"`
+	// Green is the URL of the green backend.
+	// +kubebuilder:validation:MinLength=1
+	Green string `json:"green"`
+}
+
+// APIMAPISpec defines the desired state of APIMAPI.
+// Compared to v1, APIID is serialized as apiId and SubscriptionRequired is a pointer,
+// so an unset value can be told apart from an explicit false.
+// +kubebuilder:validation:XValidation:rule="has(self.backends) == has(self.activeBackend)",message="backends and activeBackend must be set together"
+// +kubebuilder:validation:XValidation:rule="has(self.backends) != (has(self.serviceUrl) && size(self.serviceUrl) > 0)",message="exactly one of serviceUrl and backends must be set"
+// +kubebuilder:validation:XValidation:rule="has(self.versionSetId) == has(self.apiVersion)",message="versionSetId and apiVersion must be set together"
+type APIMAPISpec struct {
+	// ServiceURL is the backend service URL that APIM will proxy requests to.
+	// It is left empty when Backends declares blue/green backends.
+	// +optional
+	ServiceURL string `json:"serviceUrl"`
+	// Backends declares blue and green backend URLs. APIM proxies to the one ActiveBackend selects.
+	// +optional
+	Backends *APIMAPIBackends `json:"backends,omitempty"`
+	// ActiveBackend selects the backend of Backends that APIM proxies to.
+	// +optional
+	ActiveBackend BackendColor `json:"activeBackend,omitempty"`
+	// RoutePrefix is the base route path in APIM (e.g., "/myapi").
+	// The defaulting webhook sets it to /<metadata.name> when omitted.
+	// +kubebuilder:validation:XValidation:rule="self.startsWith('/')",message="routePrefix must start with '/'"
+	RoutePrefix string `json:"routePrefix"`
+	// OpenAPIDefinitionURL is the URL where the OpenAPI/Swagger definition can be fetched.
+	// The defaulting webhook sets it to <serviceUrl>/swagger/v1/swagger.json when omitted.
+	OpenAPIDefinitionURL string `json:"openApiDefinitionUrl"`
+	// Target optionally selects which ReplicaSets should trigger imports for this API.
+	// +optional
+	Target *APIMAPITarget `json:"target,omitempty"`
+	// ProductIDs is a list of product IDs to associate this API with in APIM.
+	// +optional
+	ProductIDs []string `json:"productIds,omitempty"`
+	// TagIDs is a list of tag IDs to apply to this API in APIM.
+	// +optional
+	TagIDs []string `json:"tagIds,omitempty"`
+	// APIMService is the name of the APIMService custom resource that references
+	// the Azure API Management service instance.
+	// It is immutable; moving an API to another APIM service requires deleting and recreating the APIMAPI.
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="apimService is immutable"
+	APIMService string `json:"apimService"`
+	// APIID is the unique identifier for the API in Azure APIM.
+	// It is immutable, since changing it would orphan the API imported under the old ID.
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="apiId is immutable"
+	APIID string `json:"apiId"`
+	// SubscriptionRequired controls whether a subscription key is required to access the API.
+	// If not specified, a subscription is required.
+	// +optional
+	SubscriptionRequired *bool `json:"subscriptionRequired,omitempty"`
+	// AdoptionPolicy controls what happens when the API already exists in APIM and the operator
+	// has not imported it before.
+	// +kubebuilder:default=Overwrite
+	// +optional
+	AdoptionPolicy AdoptionPolicy `json:"adoptionPolicy,omitempty"`
+	// DeletionPolicy controls whether deleting the APIMAPI deletes the API from APIM. Orphan leaves it.
+	// +kubebuilder:default=Delete
+	// +optional
+	DeletionPolicy DeletionPolicy `json:"deletionPolicy,omitempty"`
+	// ApprovalRequired enables two-phase deployments gated by the apim.operator.io/approve annotation.
+	// +optional
+	ApprovalRequired bool `json:"approvalRequired,omitempty"`
+	// Owner is the Backstage entity reference of the team that owns the API.
+	// +optional
+	Owner string `json:"owner,omitempty"`
+	// Canary makes every import of a changed API a canary rollout.
+	// +optional
+	Canary *APIMAPICanary `json:"canary,omitempty"`
+	// VersionSetID is the ID of the APIM version set the API is a version of.
+	// +optional
+	VersionSetID string `json:"versionSetId,omitempty"`
+	// APIVersion is the version of the API within its version set, e.g. "v2".
+	// +kubebuilder:validation:MaxLength=100
+	// +optional
+	APIVersion string `json:"apiVersion,omitempty"`
+}
+
+// APIMAPIPlan describes the changes that are waiting for approval before being applied to APIM.
+type APIMAPIPlan struct {
+	// Hash identifies the plan. Annotate the APIMAPI with apim.operator.io/approve=<hash> to apply it.
+	Hash string `json:"hash"`
+	// Changes lists the differences between the last applied state and the desired state.
+	Changes []string `json:"changes,omitempty"`
+	// CreatedAt is the timestamp when the plan was computed.
+	CreatedAt string `json:"createdAt,omitempty"`
+}
+
+// APIMAPIDeploymentRecord records one attempt to deploy the API to APIM.
+type APIMAPIDeploymentRecord struct {
+	// Timestamp is when the deployment attempt finished. Repeated identical failures only move it forward.
+	Timestamp string `json:"timestamp"`
+	// SpecHash is the hash of the desired APIM state that was deployed.
+	SpecHash string `json:"specHash,omitempty"`
+	// Revision is the APIM revision that was targeted, if any.
+	Revision string `json:"revision,omitempty"`
+	// Outcome is Succeeded, Failed or DryRun.
+	Outcome string `json:"outcome"`
+	// ReplicaSet is the ReplicaSet whose rollout triggered the deployment.
+	ReplicaSet string `json:"replicaSet,omitempty"`
+}
+
+// TerraformImport identifies an Azure entity the operator manages the way Terraform imports it.
+type TerraformImport struct {
+	// Resource is the azurerm resource type of the entity, e.g. azurerm_api_management_api.
+	Resource string `json:"resource"`
+	// ID is the ID that terraform import expects for Resource.
+	ID string `json:"id"`
+	// AzapiType is the type of an azapi_resource for the entity, e.g. Microsoft.ApiManagement/service/apis@2021-08-01.
+	AzapiType string `json:"azapiType"`
+	// AzapiID is the ID that terraform import expects for an azapi_resource of the entity: its ARM ID.
+	AzapiID string `json:"azapiId"`
+}
+
+// APIMAPIDocumentation is the documentation metadata of an imported OpenAPI definition.
+type APIMAPIDocumentation struct {
+	// Title is info.title of the definition.
+	// +optional
+	Title string `json:"title,omitempty"`
+	// ExternalDocsURL is the URL of externalDocs in the definition.
+	// +optional
+	ExternalDocsURL string `json:"externalDocsUrl,omitempty"`
+	// Operations is the number of operations in the definition.
+	// +optional
+	Operations int32 `json:"operations,omitempty"`
+	// UndocumentedOperations lists up to 20 operations without a summary, e.g. "GET /orders/{id}".
+	// +listType=atomic
+	// +optional
+	UndocumentedOperations []string `json:"undocumentedOperations,omitempty"`
+}
+
+// APIMAPIAdoptedState captures the settings of a pre-existing API that were read back from APIM
+// when the API was adopted instead of imported.
+type APIMAPIAdoptedState struct {
+	// AdoptedAt is the timestamp when the existing API settings were last read from APIM.
+	AdoptedAt string `json:"adoptedAt,omitempty"`
+	// DisplayName is the display name of the existing API.
+	DisplayName string `json:"displayName,omitempty"`
+	// Path is the route path of the existing API.
+	Path string `json:"path,omitempty"`
+	// ServiceURL is the backend service URL of the existing API.
+	ServiceURL string `json:"serviceUrl,omitempty"`
+	// SubscriptionRequired reports whether the existing API requires a subscription key.
+	SubscriptionRequired bool `json:"subscriptionRequired,omitempty"`
+	// APIRevision is the current revision of the existing API.
+	APIRevision string `json:"apiRevision,omitempty"`
+}
+
+// APIMAPIStatus defines the observed state of APIMAPI.
+type APIMAPIStatus struct {
+	// ImportedAt is the timestamp when the API was successfully imported into APIM.
+	ImportedAt string `json:"importedAt,omitempty"`
+	// Status indicates the current status of the API (e.g., "OK", "Error").
+	Status string `json:"status,omitempty"`
+	// APIHost is the full URL to access the API through APIM.
+	APIHost string `json:"apiHost,omitempty"`
+	// DeveloperPortalHost is the URL of the APIM developer portal.
+	DeveloperPortalHost string `json:"developerPortalHost,omitempty"`
+	// DeveloperPortalURL is the page of this API in the APIM developer portal.
+	// +optional
+	DeveloperPortalURL string `json:"developerPortalUrl,omitempty"`
+	// DefinitionURL is the URL of the OpenAPI definition that was last imported.
+	DefinitionURL string `json:"definitionUrl,omitempty"`
+	// Documentation is the documentation metadata of the OpenAPI definition that was last imported.
+	// +optional
+	Documentation *APIMAPIDocumentation `json:"documentation,omitempty"`
+	// AzureResourceID is the full Azure Resource Manager ID of the API in APIM.
+	AzureResourceID string `json:"azureResourceId,omitempty"`
+	// TerraformImports lists the IDs Terraform imports the API and its product and tag links by.
+	// +listType=atomic
+	// +optional
+	TerraformImports []TerraformImport `json:"terraformImports,omitempty"`
+	// Adopted holds the settings read back from APIM when the API was adopted rather than imported.
+	Adopted *APIMAPIAdoptedState `json:"adopted,omitempty"`
+	// PendingPlan holds the plan awaiting approval when spec.approvalRequired is true.
+	PendingPlan *APIMAPIPlan `json:"pendingPlan,omitempty"`
+	// History lists the most recent deployments to APIM, oldest first.
+	// +listType=atomic
+	// +optional
+	History []APIMAPIDeploymentRecord `json:"history,omitempty"`
+	// Conditions represent the latest available observations of the API's state.
+	// +listType=map
+	// +listMapKey=type
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+	// ObservedGeneration is the generation of the APIMAPI that the Ready condition was last computed for.
+	// A lower value than metadata.generation means the latest spec has not been synced yet.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// Backend records the active blue/green backend and the one it replaced.
+	// +optional
+	Backend *APIMAPIBackendStatus `json:"backend,omitempty"`
+}
+
+// APIMAPIBackendStatus records the last blue/green backend switch that reached APIM.
+type APIMAPIBackendStatus struct {
+	// Active is the backend APIM proxies to.
+	Active BackendColor `json:"active,omitempty"`
+	// ServiceURL is the URL of the active backend.
+	ServiceURL string `json:"serviceUrl,omitempty"`
+	// Previous is the backend that was active before the last switch.
+	// +optional
+	Previous BackendColor `json:"previous,omitempty"`
+	// PreviousServiceURL is the URL of the previous backend.
+	// +optional
+	PreviousServiceURL string `json:"previousServiceUrl,omitempty"`
+	// SwitchedAt is the RFC 3339 timestamp of the last switch.
+	// +optional
+	SwitchedAt string `json:"switchedAt,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:shortName=aapi,categories=apim
+// +kubebuilder:printcolumn:name="Phase",type=string,JSONPath=`.status.status`
+// +kubebuilder:printcolumn:name="ApiHost",type=string,JSONPath=`.status.apiHost`
+// +kubebuilder:printcolumn:name="APIMService",type=string,JSONPath=`.spec.apimService`
+// +kubebuilder:printcolumn:name="API ID",type=string,JSONPath=`.spec.apiId`,priority=1
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+// +kubebuilder:unservedversion
+
+// APIMAPI is the Schema for the apimapis API.
+// v2 is converted to and from v1 by the conversion webhook; v1 remains the storage version.
+// It is generated as unserved and only served by config/default, which also deploys the webhook,
+// so installs without the webhook can never store v2 objects without conversion.
+type APIMAPI struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Spec defines the desired state of the API in APIM.
+	Spec APIMAPISpec `json:"spec,omitempty"`
+	// Status reflects the observed state of the API in APIM.
+	Status APIMAPIStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// APIMAPIList contains a list of APIMAPI resources.
+type APIMAPIList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	// Items is the list of APIMAPI resources.
+	Items []APIMAPI `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&APIMAPI{}, &APIMAPIList{})
+}
@@ -0,0 +1,373 @@
+//go:build !ignore_autogenerated
+
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by controller-gen. DO NOT EDIT.
+
+package v2
+
+import (
+	"k8s.io/apimachinery/pkg/apis/meta/v1"
+	runtime "k8s.io/apimachinery/pkg/runtime"
+)
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *APIMAPI) DeepCopyInto(out *APIMAPI) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APIMAPI.
+func (in *APIMAPI) DeepCopy() *APIMAPI {
+	if in == nil {
+		return nil
+	}
+	out := new(APIMAPI)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *APIMAPI) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *APIMAPIAdoptedState) DeepCopyInto(out *APIMAPIAdoptedState) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APIMAPIAdoptedState.
+func (in *APIMAPIAdoptedState) DeepCopy() *APIMAPIAdoptedState {
+	if in == nil {
+		return nil
+	}
+	out := new(APIMAPIAdoptedState)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *APIMAPIBackendStatus) DeepCopyInto(out *APIMAPIBackendStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APIMAPIBackendStatus.
+func (in *APIMAPIBackendStatus) DeepCopy() *APIMAPIBackendStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(APIMAPIBackendStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *APIMAPIBackends) DeepCopyInto(out *APIMAPIBackends) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APIMAPIBackends.
+func (in *APIMAPIBackends) DeepCopy() *APIMAPIBackends {
+	if in == nil {
+		return nil
+	}
+	out := new(APIMAPIBackends)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *APIMAPICanary) DeepCopyInto(out *APIMAPICanary) {
+	*out = *in
+	in.SmokeCheck.DeepCopyInto(&out.SmokeCheck)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APIMAPICanary.
+func (in *APIMAPICanary) DeepCopy() *APIMAPICanary {
+	if in == nil {
+		return nil
+	}
+	out := new(APIMAPICanary)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *APIMAPIDeploymentRecord) DeepCopyInto(out *APIMAPIDeploymentRecord) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APIMAPIDeploymentRecord.
+func (in *APIMAPIDeploymentRecord) DeepCopy() *APIMAPIDeploymentRecord {
+	if in == nil {
+		return nil
+	}
+	out := new(APIMAPIDeploymentRecord)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *APIMAPIDocumentation) DeepCopyInto(out *APIMAPIDocumentation) {
+	*out = *in
+	if in.UndocumentedOperations != nil {
+		in, out := &in.UndocumentedOperations, &out.UndocumentedOperations
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APIMAPIDocumentation.
+func (in *APIMAPIDocumentation) DeepCopy() *APIMAPIDocumentation {
+	if in == nil {
+		return nil
+	}
+	out := new(APIMAPIDocumentation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *APIMAPIList) DeepCopyInto(out *APIMAPIList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]APIMAPI, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APIMAPIList.
+func (in *APIMAPIList) DeepCopy() *APIMAPIList {
+	if in == nil {
+		return nil
+	}
+	out := new(APIMAPIList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *APIMAPIList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *APIMAPIPlan) DeepCopyInto(out *APIMAPIPlan) {
+	*out = *in
+	if in.Changes != nil {
+		in, out := &in.Changes, &out.Changes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APIMAPIPlan.
+func (in *APIMAPIPlan) DeepCopy() *APIMAPIPlan {
+	if in == nil {
+		return nil
+	}
+	out := new(APIMAPIPlan)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *APIMAPISmokeCheck) DeepCopyInto(out *APIMAPISmokeCheck) {
+	*out = *in
+	if in.Timeout != nil {
+		in, out := &in.Timeout, &out.Timeout
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.SubscriptionKeySecretRef != nil {
+		in, out := &in.SubscriptionKeySecretRef, &out.SubscriptionKeySecretRef
+		*out = new(SecretKeyRef)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APIMAPISmokeCheck.
+func (in *APIMAPISmokeCheck) DeepCopy() *APIMAPISmokeCheck {
+	if in == nil {
+		return nil
+	}
+	out := new(APIMAPISmokeCheck)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *APIMAPISpec) DeepCopyInto(out *APIMAPISpec) {
+	*out = *in
+	if in.Backends != nil {
+		in, out := &in.Backends, &out.Backends
+		*out = new(APIMAPIBackends)
+		**out = **in
+	}
+	if in.Target != nil {
+		in, out := &in.Target, &out.Target
+		*out = new(APIMAPITarget)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ProductIDs != nil {
+		in, out := &in.ProductIDs, &out.ProductIDs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.TagIDs != nil {
+		in, out := &in.TagIDs, &out.TagIDs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.SubscriptionRequired != nil {
+		in, out := &in.SubscriptionRequired, &out.SubscriptionRequired
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Canary != nil {
+		in, out := &in.Canary, &out.Canary
+		*out = new(APIMAPICanary)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APIMAPISpec.
+func (in *APIMAPISpec) DeepCopy() *APIMAPISpec {
+	if in == nil {
+		return nil
+	}
+	out := new(APIMAPISpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *APIMAPIStatus) DeepCopyInto(out *APIMAPIStatus) {
+	*out = *in
+	if in.Documentation != nil {
+		in, out := &in.Documentation, &out.Documentation
+		*out = new(APIMAPIDocumentation)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TerraformImports != nil {
+		in, out := &in.TerraformImports, &out.TerraformImports
+		*out = make([]TerraformImport, len(*in))
+		copy(*out, *in)
+	}
+	if in.Adopted != nil {
+		in, out := &in.Adopted, &out.Adopted
+		*out = new(APIMAPIAdoptedState)
+		**out = **in
+	}
+	if in.PendingPlan != nil {
+		in, out := &in.PendingPlan, &out.PendingPlan
+		*out = new(APIMAPIPlan)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.History != nil {
+		in, out := &in.History, &out.History
+		*out = make([]APIMAPIDeploymentRecord, len(*in))
+		copy(*out, *in)
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Backend != nil {
+		in, out := &in.Backend, &out.Backend
+		*out = new(APIMAPIBackendStatus)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APIMAPIStatus.
+func (in *APIMAPIStatus) DeepCopy() *APIMAPIStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(APIMAPIStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *APIMAPITarget) DeepCopyInto(out *APIMAPITarget) {
+	*out = *in
+	if in.Selector != nil {
+		in, out := &in.Selector, &out.Selector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APIMAPITarget.
+func (in *APIMAPITarget) DeepCopy() *APIMAPITarget {
+	if in == nil {
+		return nil
+	}
+	out := new(APIMAPITarget)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecretKeyRef) DeepCopyInto(out *SecretKeyRef) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretKeyRef.
+func (in *SecretKeyRef) DeepCopy() *SecretKeyRef {
+	if in == nil {
+		return nil
+	}
+	out := new(SecretKeyRef)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TerraformImport) DeepCopyInto(out *TerraformImport) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TerraformImport.
+func (in *TerraformImport) DeepCopy() *TerraformImport {
+	if in == nil {
+		return nil
+	}
+	out := new(TerraformImport)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apimv1 "github.com/hedinit/azure-apim-operator/api/v1"
+	"github.com/hedinit/azure-apim-operator/internal/apim"
+	"github.com/hedinit/azure-apim-operator/internal/export"
+	"github.com/hedinit/azure-apim-operator/internal/identity"
+)
+
+// runDiff compares the resources that refer to an APIMService with the live APIM instance and prints one line
+// per difference. It returns errFailed when there are differences.
+func runDiff(ctx context.Context, args []string, stdout io.Writer) error {
+	var apimServiceName, apimServiceNamespace, namespace string
+	fs := flag.NewFlagSet("diff", flag.ContinueOnError)
+	fs.StringVar(&apimServiceName, "apimservice", "", "Name of the APIMService resource whose APIM instance is compared.")
+	fs.StringVar(&apimServiceNamespace, "apimservice-namespace", "azure-apim-operator-system",
+		"Namespace of the APIMService resource, i.e. the operator's namespace.")
+	fs.StringVar(&namespace, "namespace", "", "Only compare resources in this namespace. Defaults to all namespaces.")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if apimServiceName == "" {
+		return errors.New("--apimservice is required")
+	}
+
+	cfg, err := ctrl.GetConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load kubeconfig: %w", err)
+	}
+	c, err := client.New(cfg, client.Options{Scheme: scheme})
+	if err != nil {
+		return fmt.Errorf("failed to create Kubernetes client: %w", err)
+	}
+
+	var apimService apimv1.APIMService
+	key := client.ObjectKey{Name: apimServiceName, Namespace: apimServiceNamespace}
+	if err := c.Get(ctx, key, &apimService); err != nil {
+		return fmt.Errorf("failed to get APIMService %s/%s: %w", apimServiceNamespace, apimServiceName, err)
+	}
+	cluster, err := export.ClusterObjects(ctx, c, apimServiceName, namespace)
+	if err != nil {
+		return err
+	}
+
+	token, err := identity.GetManagementToken3(ctx, identity.AzurePublic)
+	if err != nil {
+		return fmt.Errorf("failed to acquire management token: %w", err)
+	}
+	live, err := export.Export(ctx, export.Options{
+		Service: apim.ExportConfig{
+			SubscriptionID: apimService.Spec.Subscription,
+			ResourceGroup:  apimService.Spec.ResourceGroup,
+			ServiceName:    apimService.Spec.Name,
+			BearerToken:    token,
+		},
+		APIMServiceName: apimServiceName,
+	})
+	if err != nil {
+		return err
+	}
+
+	diffs := export.Diff(cluster, live)
+	for _, d := range diffs {
+		fmt.Fprintln(stdout, d)
+	}
+	if len(diffs) > 0 {
+		return errFailed
+	}
+	return nil
+}
@@ -0,0 +1,101 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package main is apimctl, the command line companion of the operator. It runs the operator's APIM
+// and admission logic outside the cluster, so platform engineers can export, diff and validate
+// resources locally and in CI pipelines.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"go.uber.org/zap/zapcore"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+
+	apimv1 "github.com/hedinit/azure-apim-operator/api/v1"
+	apimv2 "github.com/hedinit/azure-apim-operator/api/v2"
+	"github.com/hedinit/azure-apim-operator/internal/export"
+)
+
+const usage = `apimctl runs the Azure APIM operator's logic outside the cluster.
+
+Usage:
+  apimctl export   [flags]  Write the resources of an existing APIM instance as YAML
+  apimctl diff     [flags]  Compare the resources in the cluster with the live APIM instance
+  apimctl validate [flags]  Check manifests against the operator's admission rules
+
+Run "apimctl <command> -h" for the flags of a command.
+`
+
+// errFailed reports that the command ran but found differences or invalid resources.
+// It exits with status 1, while other errors exit with status 2.
+var errFailed = errors.New("failed")
+
+// scheme holds the apim types, so manifests and cluster resources can be decoded.
+var scheme = runtime.NewScheme()
+
+func init() {
+	utilruntime.Must(apimv1.AddToScheme(scheme))
+	utilruntime.Must(apimv2.AddToScheme(scheme))
+}
+
+func main() {
+	// Only warnings and errors are logged, to stderr, so the output written to stdout can be piped.
+	ctrl.SetLogger(zap.New(zap.WriteTo(os.Stderr), zap.Level(zapcore.WarnLevel)))
+	os.Exit(run(context.Background(), os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// run dispatches to a command and maps its result to an exit status.
+func run(ctx context.Context, args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		fmt.Fprint(stderr, usage)
+		return 2
+	}
+
+	var err error
+	switch args[0] {
+	case "export":
+		err = export.Run(ctx, args[1:], stdout)
+	case "diff":
+		err = runDiff(ctx, args[1:], stdout)
+	case "validate":
+		err = runValidate(ctx, args[1:], stdout)
+	case "help", "-h", "--help":
+		fmt.Fprint(stdout, usage)
+		return 0
+	default:
+		fmt.Fprintf(stderr, "unknown command %q\n\n%s", args[0], usage)
+		return 2
+	}
+
+	switch {
+	case err == nil, errors.Is(err, flag.ErrHelp):
+		return 0
+	case errors.Is(err, errFailed):
+		return 1
+	default:
+		fmt.Fprintln(stderr, err)
+		return 2
+	}
+}
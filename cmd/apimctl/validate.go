@@ -0,0 +1,164 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+
+	apimv1 "github.com/hedinit/azure-apim-operator/api/v1"
+	webhookapimv1 "github.com/hedinit/azure-apim-operator/internal/webhook/v1"
+)
+
+// stringList is a repeatable string flag.
+type stringList []string
+
+func (l *stringList) String() string     { return strings.Join(*l, ",") }
+func (l *stringList) Set(v string) error { *l = append(*l, v); return nil }
+
+// runValidate checks the apim resources in manifest files against the admission webhooks, including the
+// policy XML checks, and prints the warnings and errors. Other kinds in the files are ignored.
+// It returns errFailed when a resource would be rejected.
+func runValidate(ctx context.Context, args []string, stdout io.Writer) error {
+	var files stringList
+	var defaultNamespace string
+	var opts webhookapimv1.APIMAPIWebhookOptions
+	fs := flag.NewFlagSet("validate", flag.ContinueOnError)
+	fs.Var(&files, "f", "Manifest file or directory of .yaml and .yml files. Repeatable; - reads standard input.")
+	fs.StringVar(&defaultNamespace, "namespace", "default", "Namespace of resources that don't set one.")
+	fs.StringVar(&opts.OperatorNamespace, "operator-namespace", "azure-apim-operator-system",
+		"Namespace where APIMService resources live.")
+	fs.BoolVar(&opts.RequireAPIIDNamespacePrefix, "require-apiid-namespace-prefix", false,
+		"Reject API IDs that don't start with \"<namespace>-\", like the operator flag of the same name.")
+	fs.BoolVar(&opts.RejectMissingAPIMService, "reject-missing-apimservice", false,
+		"Reject references to APIMServices that are not in the manifests instead of warning.")
+	fs.BoolVar(&opts.RequireHTTPSServiceURL, "require-https-service-url", false,
+		"Only admit https and wss serviceUrl values, like the operator flag of the same name.")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if len(files) == 0 {
+		return errors.New("at least one -f is required")
+	}
+
+	var objects []client.Object
+	for _, file := range files {
+		decoded, err := readManifests(file)
+		if err != nil {
+			return err
+		}
+		objects = append(objects, decoded...)
+	}
+	for _, obj := range objects {
+		if obj.GetNamespace() == "" {
+			obj.SetNamespace(defaultNamespace)
+		}
+	}
+
+	results, err := webhookapimv1.ValidateManifests(ctx, scheme, objects, opts)
+	if err != nil {
+		return err
+	}
+
+	failed := false
+	for _, result := range results {
+		gvks, _, _ := scheme.ObjectKinds(result.Object)
+		ref := fmt.Sprintf("%s %s/%s", gvks[0].Kind, result.Object.GetNamespace(), result.Object.GetName())
+		for _, warning := range result.Warnings {
+			fmt.Fprintf(stdout, "%s: warning: %s\n", ref, warning)
+		}
+		if result.Err != nil {
+			failed = true
+			fmt.Fprintf(stdout, "%s: %v\n", ref, result.Err)
+		}
+	}
+	fmt.Fprintf(stdout, "%d resources checked\n", len(results))
+	if failed {
+		return errFailed
+	}
+	return nil
+}
+
+// readManifests decodes the apim resources of a file, a directory of YAML files or standard input ("-").
+// v2 resources are converted to v1, which is what the webhooks validate.
+func readManifests(path string) ([]client.Object, error) {
+	if path == "-" {
+		return decodeManifests(os.Stdin, "standard input")
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		defer func() { _ = f.Close() }()
+		return decodeManifests(f, path)
+	}
+
+	var objects []client.Object
+	err = filepath.WalkDir(path, func(file string, d os.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		if ext := filepath.Ext(file); ext != ".yaml" && ext != ".yml" {
+			return nil
+		}
+		decoded, err := readManifests(file)
+		objects = append(objects, decoded...)
+		return err
+	})
+	return objects, err
+}
+
+func decodeManifests(r io.Reader, source string) ([]client.Object, error) {
+	decoder := serializer.NewCodecFactory(scheme).UniversalDeserializer()
+	reader := utilyaml.NewYAMLReader(bufio.NewReader(r))
+
+	var objects []client.Object
+	for {
+		doc, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return objects, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", source, err)
+		}
+		if strings.TrimSpace(string(doc)) == "" {
+			continue
+		}
+
+		obj, _, err := decoder.Decode(doc, nil, nil)
+		if runtime.IsNotRegisteredError(err) || runtime.IsMissingKind(err) {
+			continue // not an apim resource
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode %s: %w", source, err)
+		}
+
+		if convertible, ok := obj.(conversion.Convertible); ok {
+			var hub apimv1.APIMAPI
+			if err := convertible.ConvertTo(&hub); err != nil {
+				return nil, fmt.Errorf("failed to convert %s: %w", source, err)
+			}
+			obj = &hub
+		}
+		if clientObj, ok := obj.(client.Object); ok {
+			objects = append(objects, clientObj)
+		}
+	}
+}
@@ -20,21 +20,30 @@ limitations under the License.
 package main
 
 import (
+	"context"
 	"crypto/tls"
 	"flag"
+	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
+	"time"
 
 	// Import all Kubernetes client auth plugins (e.g. Azure, GCP, OIDC, etc.)
 	// to ensure that exec-entrypoint and run can make use of them.
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
 
+	uberzap "go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/client-go/discovery"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/ptr"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/certwatcher"
+	"sigs.k8s.io/controller-runtime/pkg/config"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 	"sigs.k8s.io/controller-runtime/pkg/metrics/filters"
@@ -42,7 +51,15 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 
 	apimv1 "github.com/hedinit/azure-apim-operator/api/v1"
+	apimv2 "github.com/hedinit/azure-apim-operator/api/v2"
+	"github.com/hedinit/azure-apim-operator/internal/apim"
+	"github.com/hedinit/azure-apim-operator/internal/cloudevents"
+	operatorconfig "github.com/hedinit/azure-apim-operator/internal/config"
 	"github.com/hedinit/azure-apim-operator/internal/controller"
+	"github.com/hedinit/azure-apim-operator/internal/export"
+	"github.com/hedinit/azure-apim-operator/internal/identity"
+	"github.com/hedinit/azure-apim-operator/internal/logger"
+	webhookapimv1 "github.com/hedinit/azure-apim-operator/internal/webhook/v1"
 	// +kubebuilder:scaffold:imports
 )
 
@@ -61,6 +78,7 @@ func init() {
 
 	// Register custom APIM API types (APIMAPI, APIMService, etc.)
 	utilruntime.Must(apimv1.AddToScheme(scheme))
+	utilruntime.Must(apimv2.AddToScheme(scheme))
 	// +kubebuilder:scaffold:scheme
 }
 
@@ -69,6 +87,16 @@ func init() {
 // the reconciliation loop.
 // nolint:gocyclo
 func main() {
+	// "manager export ..." writes the resources of an existing APIM instance as YAML instead of running the operator.
+	if len(os.Args) > 1 && os.Args[1] == "export" {
+		ctrl.SetLogger(zap.New(zap.WriteTo(os.Stderr)))
+		if err := export.Run(context.Background(), os.Args[2:], os.Stdout); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	// Command-line flags for configuring the operator
 	var metricsAddr string
 	var metricsCertPath, metricsCertName, metricsCertKey string
@@ -77,6 +105,36 @@ func main() {
 	var probeAddr string
 	var secureMetrics bool
 	var enableHTTP2 bool
+	var dryRun bool
+	var enablePriorityQueue bool
+	var highPriorityNamespaces string
+	var replicaSetDebounce time.Duration
+	var shardIndex, shardCount int
+	var azureReadinessCheck bool
+	var requireAPIIDNamespacePrefix bool
+	var rejectMissingAPIMService bool
+	var requireHTTPSServiceURL bool
+	var probeOpenAPIURLOnDryRun bool
+	var metricsAPIIDLabelLimit int
+	var statusHistoryLimit int
+	var logLevelFile string
+	var configFile string
+	var plainLogMessages bool
+	var eventBurst int
+	var eventRefillInterval time.Duration
+	var maxOpenAPIDefinitionBytes int64
+	var gzipOpenAPIImports bool
+	var breakingChangeDetection bool
+	var managementEndpoint string
+	var azureCloudName string
+	var apimAPIVersion string
+	var generateAPIMAPIsFromDeployments bool
+	var cloudEventsSinkURL, cloudEventsSource string
+	var cloudEventsSinkAzureAuth bool
+	var subscriptionApproval bool
+	var subscriptionApprovalDomains string
+	var subscriptionApprovalProducts string
+	var subscriptionApprovalReject bool
 	var tlsOpts []func(*tls.Config)
 	flag.StringVar(&metricsAddr, "metrics-bind-address", "0", "The address the metrics endpoint binds to. "+
 		"Use :8443 for HTTPS or :8080 for HTTP, or leave as 0 to disable the metrics service.")
@@ -95,6 +153,85 @@ func main() {
 	flag.StringVar(&metricsCertKey, "metrics-cert-key", "tls.key", "The name of the metrics server key file.")
 	flag.BoolVar(&enableHTTP2, "enable-http2", false,
 		"If set, HTTP/2 will be enabled for the metrics and webhook servers")
+	flag.BoolVar(&dryRun, "dry-run", false,
+		"If set, Azure-mutating calls are logged instead of sent and resource statuses are marked DryRun.")
+	flag.BoolVar(&enablePriorityQueue, "enable-priority-queue", false,
+		"If set, resources labeled apim.operator.io/priority=high are reconciled ahead of others when the queue is deep.")
+	flag.StringVar(&highPriorityNamespaces, "high-priority-namespaces", "",
+		"Comma-separated namespaces whose resources are reconciled ahead of others. Implies --enable-priority-queue.")
+	flag.DurationVar(&replicaSetDebounce, "replicaset-debounce", 10*time.Second,
+		"Quiet period after the last ReplicaSet change of an application before its API is imported. 0 disables it.")
+	flag.IntVar(&shardCount, "shard-count", 1,
+		"Number of operator shards. Namespaces are split between shards by a consistent hash.")
+	flag.IntVar(&shardIndex, "shard-index", 0,
+		"Index of the shard this instance reconciles, in [0, shard-count).")
+	flag.BoolVar(&azureReadinessCheck, "azure-readiness-check", false,
+		"If set, /readyz also verifies that an Azure management token can be acquired and ARM is reachable.")
+	flag.BoolVar(&requireAPIIDNamespacePrefix, "require-apiid-namespace-prefix", false,
+		"If set, the APIMAPI validating webhook rejects API IDs that don't start with \"<namespace>-\".")
+	flag.BoolVar(&rejectMissingAPIMService, "reject-missing-apimservice", false,
+		"If set, the validating webhooks reject references to APIMServices that don't exist instead of warning.")
+	flag.BoolVar(&requireHTTPSServiceURL, "require-https-service-url", false,
+		"If set, the APIMAPI validating webhook only admits https and wss serviceUrl values.")
+	flag.BoolVar(&probeOpenAPIURLOnDryRun, "probe-openapi-url-on-dry-run", false,
+		"If set, the APIMAPI validating webhook sends a HEAD request to openApiDefinitionUrl on server-side "+
+			"dry runs and warns if it is unreachable.")
+	flag.IntVar(&metricsAPIIDLabelLimit, "metrics-api-id-label-limit", 0,
+		"Number of distinct APIs that get their own api_id label on the import metrics. Further APIs are "+
+			"reported as \"other\". 0 disables the api_id label.")
+	flag.IntVar(&statusHistoryLimit, "status-history-limit", 10,
+		"Number of deployments kept in APIMAPI status.history. 0 disables the history.")
+	flag.StringVar(&logLevelFile, "log-level-file", "",
+		"Path to a file holding the log level (debug, info, warn, error or a positive integer), e.g. a mounted "+
+			"ConfigMap key. It is re-read on SIGHUP and every 30s and overrides --zap-log-level while present.")
+	flag.StringVar(&configFile, "config-file", "",
+		"Path to a YAML file with settings that are applied without a restart whenever it changes, e.g. a "+
+			"mounted ConfigMap key: logLevel, resyncInterval, azureRetryInterval and featureGates. Settings in "+
+			"the file override the corresponding flags.")
+	flag.BoolVar(&plainLogMessages, "plain-log-messages", false,
+		"If set, the emoji prefixes are stripped from log messages so they can be matched verbatim.")
+	flag.IntVar(&eventBurst, "event-burst", controller.DefaultEventBurst,
+		"Number of Kubernetes Events a resource can emit before further Events are rate limited.")
+	flag.DurationVar(&eventRefillInterval, "event-refill-interval", controller.DefaultEventRefillInterval,
+		"How often a rate-limited resource may emit one more Event.")
+	flag.Int64Var(&maxOpenAPIDefinitionBytes, "max-openapi-definition-bytes", controller.DefaultMaxOpenAPIDefinitionBytes,
+		"Size limit of a downloaded OpenAPI definition in bytes. Larger definitions fail the import.")
+	flag.BoolVar(&gzipOpenAPIImports, "gzip-openapi-imports", true,
+		"If set, large OpenAPI definitions are sent to APIM gzip-compressed, falling back to uncompressed "+
+			"requests when Azure rejects them.")
+	flag.BoolVar(&breakingChangeDetection, "breaking-change-detection", false,
+		"If set, a changed OpenAPI definition that removes paths or operations or adds required parameters is not "+
+			"imported until its APIMAPI is annotated with apim.operator.io/allow-breaking-changes.")
+	flag.StringVar(&managementEndpoint, "azure-management-endpoint", os.Getenv("AZURE_MANAGEMENT_ENDPOINT"),
+		"URL that Azure Management API requests are sent to instead of https://management.azure.com, e.g. a fake "+
+			"APIM server in end-to-end tests. Defaults to $AZURE_MANAGEMENT_ENDPOINT. With it set, a non-empty "+
+			"$AZURE_MANAGEMENT_TOKEN is sent as the bearer token instead of an Azure AD token.")
+	flag.StringVar(&azureCloudName, "azure-cloud", os.Getenv("AZURE_CLOUD"),
+		"Azure cloud the APIM services are in: AzurePublic, AzureUSGovernment or AzureChina. Selects the Azure "+
+			"Resource Manager endpoint and the Azure AD authority of tokens. Defaults to $AZURE_CLOUD, or AzurePublic.")
+	flag.StringVar(&apimAPIVersion, "apim-api-version", apim.DefaultAPIVersion,
+		"Microsoft.ApiManagement API version that APIM requests are sent with. One of "+
+			strings.Join(apim.SupportedAPIVersions, ", ")+".")
+	flag.BoolVar(&generateAPIMAPIsFromDeployments, "generate-apimapis-from-deployments", false,
+		"If set, APIMAPIs are generated from apim.operator.io/* annotations on Deployments.")
+	flag.StringVar(&cloudEventsSinkURL, "cloudevents-sink-url", "",
+		"HTTP endpoint, such as an Azure Event Grid topic, that API lifecycle events are posted to as CloudEvents. "+
+			"Empty disables them. An Event Grid access key is read from CLOUDEVENTS_SINK_ACCESS_KEY.")
+	flag.StringVar(&cloudEventsSource, "cloudevents-source", cloudevents.DefaultSource,
+		"The source attribute of published CloudEvents, e.g. the name of the cluster.")
+	flag.BoolVar(&cloudEventsSinkAzureAuth, "cloudevents-sink-azure-auth", false,
+		"If set, CloudEvents are posted with an Azure AD token of the operator's workload identity, "+
+			"as Event Grid accepts with the EventGrid Data Sender role.")
+	flag.BoolVar(&subscriptionApproval, "subscription-approval", false,
+		"If set, subscriptions waiting for approval on APIMProducts with approvalRequired are approved when the "+
+			"email domain of their owner is in --subscription-approval-domains.")
+	flag.StringVar(&subscriptionApprovalDomains, "subscription-approval-domains", "",
+		"Comma-separated email domains, e.g. contoso.com, whose owners' subscriptions are approved.")
+	flag.StringVar(&subscriptionApprovalProducts, "subscription-approval-products", "",
+		"Comma-separated product IDs whose subscriptions are approved or rejected. Empty means every product "+
+			"with approvalRequired.")
+	flag.BoolVar(&subscriptionApprovalReject, "subscription-approval-reject", false,
+		"If set, subscriptions of owners outside the allowed domains are rejected instead of left for an administrator.")
 
 	opts := zap.Options{
 		Development:     false,
@@ -103,16 +240,116 @@ func main() {
 	opts.BindFlags(flag.CommandLine)
 	flag.Parse()
 
-	// ctx := context.Background()
-	// shutdown := logger.InitTracer(ctx)
-	// defer func() {
-	// 	if err := shutdown(ctx); err != nil {
-	// 		setupLog.Error(err, "❌ Failed to shutdown tracer provider")
-	// 	}
-	// }()
+	// Keep the level in an AtomicLevel so it can be changed at runtime. The --zap-log-level flag
+	// already stores one; copies of an AtomicLevel share the same underlying level.
+	logLevel, ok := opts.Level.(uberzap.AtomicLevel)
+	if !ok {
+		logLevel = uberzap.NewAtomicLevelAt(zapcore.InfoLevel)
+		opts.Level = logLevel
+	}
 
 	// Initialize the logger with zap configuration
-	ctrl.SetLogger(zap.New(zap.UseFlagOptions(&opts)))
+	baseLogger := zap.New(zap.UseFlagOptions(&opts))
+	if plainLogMessages {
+		baseLogger = logger.Plain(baseLogger)
+	}
+	ctrl.SetLogger(baseLogger)
+
+	// SetupSignalHandler sets up signal handling for graceful shutdown (SIGTERM, SIGINT).
+	signalCtx := ctrl.SetupSignalHandler()
+	if logLevelFile != "" {
+		logger.WatchLevelFile(signalCtx, logLevel, logLevelFile, 30*time.Second)
+	}
+
+	// Reconciles and APIM calls are traced when OTEL_EXPORTER_OTLP_ENDPOINT is set; otherwise this is a no-op.
+	tracerCtx := context.Background()
+	shutdownTracer := logger.InitTracer(tracerCtx)
+	defer func() {
+		if err := shutdownTracer(tracerCtx); err != nil {
+			setupLog.Error(err, "❌ Failed to shutdown tracer provider")
+		}
+	}()
+
+	// In dry-run mode the operator still reads from Azure but never writes to it.
+	if dryRun {
+		setupLog.Info("🧪 Dry-run mode enabled; Azure-mutating calls will be logged but not sent")
+	}
+	apim.SetDryRun(dryRun)
+
+	// A fake management endpoint lets the whole import flow run without Azure; it accepts any token.
+	if managementEndpoint != "" {
+		setupLog.Info("🧪 Sending Azure Management API requests to another endpoint", "endpoint", managementEndpoint)
+		identity.SetStaticToken(os.Getenv("AZURE_MANAGEMENT_TOKEN"))
+	}
+
+	// Tokens are requested from the Azure AD of the cloud, and requests go to its Resource Manager unless a
+	// management endpoint was given.
+	azureCloud, err := identity.CloudByName(azureCloudName)
+	if err != nil {
+		setupLog.Error(err, "invalid Azure cloud")
+		os.Exit(1)
+	}
+	if azureCloud.Name != identity.AzurePublic.Name {
+		setupLog.Info("☁️ Using a sovereign Azure cloud", "cloud", azureCloud.Name,
+			"resourceManager", azureCloud.ResourceManagerEndpoint)
+	}
+	if managementEndpoint == "" {
+		managementEndpoint = azureCloud.ResourceManagerEndpoint
+	}
+
+	// High-priority namespaces and labeled resources are ordered ahead of dev/test churn in the work queues.
+	if highPriorityNamespaces != "" {
+		enablePriorityQueue = true
+		controller.SetHighPriorityNamespaces(strings.Split(highPriorityNamespaces, ","))
+	}
+	if enablePriorityQueue {
+		setupLog.Info("🚦 Priority queue enabled", "highPriorityNamespaces", highPriorityNamespaces)
+	}
+
+	// Per-API metric labels are opt-in and capped, since a shared operator may manage thousands of APIs.
+	controller.SetAPIIDMetricLabelLimit(metricsAPIIDLabelLimit)
+
+	if err := controller.SetStatusHistoryLimit(statusHistoryLimit); err != nil {
+		setupLog.Error(err, "invalid status history limit")
+		os.Exit(1)
+	}
+
+	// Definitions are buffered in memory while they are hashed and imported, so their size is capped.
+	if err := controller.SetMaxOpenAPIDefinitionBytes(maxOpenAPIDefinitionBytes); err != nil {
+		setupLog.Error(err, "invalid OpenAPI definition size limit")
+		os.Exit(1)
+	}
+	apim.SetRequestCompression(gzipOpenAPIImports)
+	controller.SetBreakingChangeDetection(breakingChangeDetection)
+
+	// Settings in the config file are reapplied when it changes, so they can be tuned without dropping the
+	// work queues. Settings removed from the file fall back to their flags.
+	if configFile != "" {
+		if err := operatorconfig.Watch(signalCtx, configFile, flagSettings(breakingChangeDetection, gzipOpenAPIImports),
+			settingsApplier(logLevel)); err != nil {
+			setupLog.Error(err, "unable to load the config file")
+			os.Exit(1)
+		}
+	}
+
+	// A flapping rollout retries every minute; aggregate and rate limit its Events per object.
+	eventBroadcaster, err := controller.NewEventBroadcaster(eventBurst, eventRefillInterval)
+	if err != nil {
+		setupLog.Error(err, "invalid event rate limit")
+		os.Exit(1)
+	}
+	defer eventBroadcaster.Shutdown()
+
+	// Each shard reconciles a disjoint set of namespaces and elects its own leader.
+	if err := controller.SetShard(shardIndex, shardCount); err != nil {
+		setupLog.Error(err, "invalid shard configuration")
+		os.Exit(1)
+	}
+	leaderElectionID := "50287eb5.operator.io"
+	if shardCount > 1 {
+		leaderElectionID = fmt.Sprintf("shard-%d-%s", shardIndex, leaderElectionID)
+		setupLog.Info("🧩 Sharding enabled", "shardIndex", shardIndex, "shardCount", shardCount)
+	}
 
 	// If the enable-http2 flag is false (the default), http/2 should be disabled
 	// due to its vulnerabilities. More specifically, disabling http/2 will
@@ -209,7 +446,12 @@ func main() {
 		WebhookServer:          webhookServer,
 		HealthProbeBindAddress: probeAddr,
 		LeaderElection:         enableLeaderElection,
-		LeaderElectionID:       "50287eb5.operator.io",
+		LeaderElectionID:       leaderElectionID,
+		Controller:             config.Controller{UsePriorityQueue: ptr.To(enablePriorityQueue)},
+		Cache:                  controller.CacheOptions(),
+		// The manager has no other way to take correlator options. It does not shut the broadcaster down,
+		// which is done above when main returns.
+		EventBroadcaster: eventBroadcaster, //nolint:staticcheck
 		// LeaderElectionReleaseOnCancel defines if the leader should step down voluntarily
 		// when the Manager ends. This requires the binary to immediately end when the
 		// Manager is stopped, otherwise, this setting is unsafe. Setting this significantly
@@ -227,11 +469,50 @@ func main() {
 		os.Exit(1)
 	}
 
+	// Warn about deprecated API groups and kinds that are still installed, since their resources
+	// never reach the operator. A failed check must not keep the operator from starting.
+	if dc, err := discovery.NewDiscoveryClientForConfig(mgr.GetConfig()); err != nil {
+		setupLog.Error(err, "unable to create discovery client for deprecation check")
+	} else if messages, err := controller.FindLegacyAPIs(dc); err != nil {
+		setupLog.Error(err, "unable to check for deprecated APIs")
+	} else {
+		for _, message := range messages {
+			setupLog.Info("WARNING: deprecated API in use", "detail", message)
+		}
+	}
+
+	// Share one Azure Management API token between all controllers and refresh it before it expires, instead
+	// of asking Azure AD on every reconcile.
+	var tokens identity.TokenProvider
+	if clientID, tenantID := os.Getenv("AZURE_CLIENT_ID"), os.Getenv("AZURE_TENANT_ID"); clientID != "" && tenantID != "" {
+		tokenCache, err := identity.NewTokenCache(clientID, tenantID, azureCloud)
+		if err != nil {
+			setupLog.Error(err, "unable to create Azure token cache")
+			os.Exit(1)
+		}
+		if err := mgr.Add(tokenCache); err != nil {
+			setupLog.Error(err, "unable to add Azure token cache to manager")
+			os.Exit(1)
+		}
+		tokens = tokenCache
+	}
+
+	// All controllers send their APIM requests through one client. Only api-versions whose payloads the
+	// contract tests cover are accepted.
+	apimClient, err := apim.NewClient(managementEndpoint, apimAPIVersion, tokens, nil)
+	if err != nil {
+		setupLog.Error(err, "invalid APIM client settings")
+		os.Exit(1)
+	}
+
 	// Register the APIMAPI controller to manage APIMAPI custom resources.
 	// This controller updates annotations with API host information for ArgoCD integration.
 	if err = (&controller.APIMAPIReconciler{
-		Client: mgr.GetClient(),
-		Scheme: mgr.GetScheme(),
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Recorder: mgr.GetEventRecorderFor("apimapi-controller"),
+		APIM:     apimClient,
+		Tokens:   tokens,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "APIMAPI")
 		os.Exit(1)
@@ -248,8 +529,13 @@ func main() {
 	// Register the APIMAPIDeployment controller to handle API deployments to Azure APIM.
 	// This controller imports OpenAPI definitions, configures service URLs, and assigns products/tags.
 	if err = (&controller.APIMAPIDeploymentReconciler{
-		Client: mgr.GetClient(),
-		Scheme: mgr.GetScheme(),
+		Client:             mgr.GetClient(),
+		APIReader:          mgr.GetAPIReader(),
+		Scheme:             mgr.GetScheme(),
+		Recorder:           mgr.GetEventRecorderFor("apimapideployment-controller"),
+		ReplicaSetDebounce: replicaSetDebounce,
+		APIM:               apimClient,
+		Tokens:             tokens,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "APIMAPIDeployment")
 		os.Exit(1)
@@ -257,17 +543,34 @@ func main() {
 	// Register the APIMService controller to manage APIMService custom resources.
 	// This controller provides information about Azure API Management service instances.
 	if err = (&controller.APIMServiceReconciler{
-		Client: mgr.GetClient(),
-		Scheme: mgr.GetScheme(),
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Recorder: mgr.GetEventRecorderFor("apimservice-controller"),
+		APIM:     apimClient,
+		Tokens:   tokens,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "APIMService")
 		os.Exit(1)
 	}
+	// Register the APIMService backup controller, which backs up services with spec.backup on their schedule.
+	if err = (&controller.APIMServiceBackupReconciler{
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Recorder: mgr.GetEventRecorderFor("apimservice-backup-controller"),
+		APIM:     apimClient,
+		Tokens:   tokens,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "APIMServiceBackup")
+		os.Exit(1)
+	}
 	// Register the APIMProduct controller to manage products in Azure APIM.
 	// Products are used to group and publish APIs with subscription requirements.
 	if err = (&controller.APIMProductReconciler{
-		Client: mgr.GetClient(),
-		Scheme: mgr.GetScheme(),
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Recorder: mgr.GetEventRecorderFor("apimproduct-controller"),
+		APIM:     apimClient,
+		Tokens:   tokens,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "APIMProduct")
 		os.Exit(1)
@@ -275,19 +578,196 @@ func main() {
 	// Register the APIMTag controller to manage tags in Azure APIM.
 	// Tags are used to categorize and organize APIs.
 	if err = (&controller.APIMTagReconciler{
-		Client: mgr.GetClient(),
-		Scheme: mgr.GetScheme(),
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Recorder: mgr.GetEventRecorderFor("apimtag-controller"),
+		APIM:     apimClient,
+		Tokens:   tokens,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "APIMTag")
 		os.Exit(1)
 	}
 	if err = (&controller.APIMInboundPolicyReconciler{
-		Client: mgr.GetClient(),
-		Scheme: mgr.GetScheme(),
+		Client:    mgr.GetClient(),
+		APIReader: mgr.GetAPIReader(),
+		Scheme:    mgr.GetScheme(),
+		Recorder:  mgr.GetEventRecorderFor("apiminboundpolicy-controller"),
+		APIM:      apimClient,
+		Tokens:    tokens,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "APIMInboundPolicy")
 		os.Exit(1)
 	}
+	// Register the APIMNamedValue controller to keep named values in sync with Secrets and Key Vault.
+	if err = (&controller.APIMNamedValueReconciler{
+		Client:    mgr.GetClient(),
+		APIReader: mgr.GetAPIReader(),
+		Scheme:    mgr.GetScheme(),
+		Recorder:  mgr.GetEventRecorderFor("apimnamedvalue-controller"),
+		APIM:      apimClient,
+		Tokens:    tokens,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "APIMNamedValue")
+		os.Exit(1)
+	}
+	// Register the APIMBackend controller to manage the backends policies route requests to.
+	if err = (&controller.APIMBackendReconciler{
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Recorder: mgr.GetEventRecorderFor("apimbackend-controller"),
+		APIM:     apimClient,
+		Tokens:   tokens,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "APIMBackend")
+		os.Exit(1)
+	}
+	// Register the APIMApiVersionSet controller to manage the version sets APIs are imported into.
+	if err = (&controller.APIMApiVersionSetReconciler{
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Recorder: mgr.GetEventRecorderFor("apimapiversionset-controller"),
+		APIM:     apimClient,
+		Tokens:   tokens,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "APIMApiVersionSet")
+		os.Exit(1)
+	}
+	// Register the APIMSubscription controller to manage subscriptions in Azure APIM.
+	// Subscription keys are written to Secrets in the namespaces of the consumers.
+	if err = (&controller.APIMSubscriptionReconciler{
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Recorder: mgr.GetEventRecorderFor("apimsubscription-controller"),
+		APIM:     apimClient,
+		Tokens:   tokens,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "APIMSubscription")
+		os.Exit(1)
+	}
+	// Register the APIMCluster controller, which reconciles the APIMAPIs of registered remote clusters.
+	if err = (&controller.APIMClusterReconciler{
+		Client:             mgr.GetClient(),
+		APIReader:          mgr.GetAPIReader(),
+		Scheme:             mgr.GetScheme(),
+		Recorder:           mgr.GetEventRecorderFor("apimcluster-controller"),
+		ReplicaSetDebounce: replicaSetDebounce,
+		APIM:               apimClient,
+		Tokens:             tokens,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "APIMCluster")
+		os.Exit(1)
+	}
+	// Generate APIMAPIs from annotated Deployments, for teams that don't want to write the custom resource.
+	if generateAPIMAPIsFromDeployments {
+		if err = (&controller.DeploymentAPIReconciler{
+			Client:   mgr.GetClient(),
+			Scheme:   mgr.GetScheme(),
+			Recorder: mgr.GetEventRecorderFor("deploymentapi-controller"),
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "DeploymentAPI")
+			os.Exit(1)
+		}
+	}
+	// Approve subscriptions requested in the developer portal by the email domain of their owner.
+	if subscriptionApproval {
+		rules, err := controller.NewSubscriptionApprovalRules(subscriptionApprovalDomains, subscriptionApprovalProducts,
+			subscriptionApprovalReject)
+		if err != nil {
+			setupLog.Error(err, "invalid subscription approval rules")
+			os.Exit(1)
+		}
+		if err = (&controller.SubscriptionApprovalReconciler{
+			Client:   mgr.GetClient(),
+			Scheme:   mgr.GetScheme(),
+			Recorder: mgr.GetEventRecorderFor("subscriptionapproval-controller"),
+			Rules:    rules,
+			APIM:     apimClient,
+			Tokens:   tokens,
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "SubscriptionApproval")
+			os.Exit(1)
+		}
+		setupLog.Info("✅ Approving product subscriptions", "domains", rules.AllowedDomains, "products", rules.Products,
+			"reject", rules.Reject)
+	}
+	// Report how many APIs differ from what was last applied to APIM, computed from the cache on each scrape.
+	if err = controller.RegisterDriftCollector(mgr.GetClient()); err != nil {
+		setupLog.Error(err, "unable to register drift metrics")
+		os.Exit(1)
+	}
+	// Export the Ready condition of every managed resource for condition-based alerting.
+	if err = controller.RegisterConditionCollector(mgr.GetClient()); err != nil {
+		setupLog.Error(err, "unable to register condition metrics")
+		os.Exit(1)
+	}
+	// Publish API lifecycle events for downstream automation. The sink runs with the controllers,
+	// so only the leader publishes.
+	if cloudEventsSinkURL != "" {
+		sinkOptions := cloudevents.Options{
+			URL:       cloudEventsSinkURL,
+			Source:    cloudEventsSource,
+			AccessKey: os.Getenv("CLOUDEVENTS_SINK_ACCESS_KEY"),
+		}
+		if cloudEventsSinkAzureAuth {
+			sinkOptions.Token, err = identity.NewTokenSource(os.Getenv("AZURE_CLIENT_ID"), os.Getenv("AZURE_TENANT_ID"),
+				azureCloud, cloudevents.EventGridScope)
+			if err != nil {
+				setupLog.Error(err, "unable to create CloudEvents sink credential")
+				os.Exit(1)
+			}
+		}
+		sink, err := cloudevents.NewSink(sinkOptions)
+		if err != nil {
+			setupLog.Error(err, "invalid CloudEvents sink")
+			os.Exit(1)
+		}
+		if err := mgr.Add(sink); err != nil {
+			setupLog.Error(err, "unable to add CloudEvents sink")
+			os.Exit(1)
+		}
+		controller.SetCloudEventSink(sink)
+		setupLog.Info("📣 Publishing API lifecycle events", "source", cloudEventsSource)
+	}
+	// Register the admission webhooks, including the APIMAPI v1 <-> v2 conversion webhook.
+	// Set ENABLE_WEBHOOKS=false to run without webhook certificates, e.g. locally or with the Helm chart
+	// without webhooks.enabled.
+	if os.Getenv("ENABLE_WEBHOOKS") != "false" {
+		operatorNamespace, err := controller.OperatorNamespace()
+		if err != nil {
+			setupLog.Error(err, "unable to determine operator namespace")
+			os.Exit(1)
+		}
+		if err = webhookapimv1.SetupAPIMAPIWebhookWithManager(mgr, webhookapimv1.APIMAPIWebhookOptions{
+			OperatorNamespace:           operatorNamespace,
+			RequireAPIIDNamespacePrefix: requireAPIIDNamespacePrefix,
+			RejectMissingAPIMService:    rejectMissingAPIMService,
+			RequireHTTPSServiceURL:      requireHTTPSServiceURL,
+			ProbeOpenAPIURLOnDryRun:     probeOpenAPIURLOnDryRun,
+		}); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "APIMAPI")
+			os.Exit(1)
+		}
+		if err = webhookapimv1.SetupAPIMProductWebhookWithManager(mgr, operatorNamespace); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "APIMProduct")
+			os.Exit(1)
+		}
+		if err = webhookapimv1.SetupAPIMTagWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "APIMTag")
+			os.Exit(1)
+		}
+		if err = webhookapimv1.SetupAPIMInboundPolicyWebhookWithManager(
+			mgr, operatorNamespace, rejectMissingAPIMService,
+		); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "APIMInboundPolicy")
+			os.Exit(1)
+		}
+		if err = webhookapimv1.SetupAPIMSubscriptionWebhookWithManager(
+			mgr, operatorNamespace, rejectMissingAPIMService,
+		); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "APIMSubscription")
+			os.Exit(1)
+		}
+	}
 
 	// +kubebuilder:scaffold:builder
 
@@ -318,12 +798,60 @@ func main() {
 		setupLog.Error(err, "unable to set up ready check")
 		os.Exit(1)
 	}
+	// Surface a broken federated credential or ARM outage before a deployment fails.
+	if azureReadinessCheck {
+		if err := mgr.AddReadyzCheck("azure", apim.NewReadinessCheck(apimClient, time.Minute)); err != nil {
+			setupLog.Error(err, "unable to set up Azure ready check")
+			os.Exit(1)
+		}
+	}
 
 	// Start the controller manager, which will begin the reconciliation loops for all registered controllers.
-	// SetupSignalHandler sets up signal handling for graceful shutdown (SIGTERM, SIGINT).
 	setupLog.Info("starting manager")
-	if err := mgr.Start(ctrl.SetupSignalHandler()); err != nil {
+	if err := mgr.Start(signalCtx); err != nil {
 		setupLog.Error(err, "problem running manager")
 		os.Exit(1)
 	}
 }
+
+// flagSettings returns the reloadable settings as the flags set them.
+func flagSettings(breakingChangeDetection, gzipOpenAPIImports bool) operatorconfig.Settings {
+	return operatorconfig.Settings{
+		ResyncInterval:     &metav1.Duration{Duration: controller.DefaultResyncInterval},
+		AzureRetryInterval: &metav1.Duration{Duration: controller.DefaultAzureRetryInterval},
+		FeatureGates: map[string]bool{
+			operatorconfig.FeatureBreakingChangeDetection: breakingChangeDetection,
+			operatorconfig.FeatureGzipOpenAPIImports:      gzipOpenAPIImports,
+		},
+	}
+}
+
+// settingsApplier returns a function that applies reloaded settings to the running operator. A log level
+// removed from the config file restores the level the operator started with; without one, the level is left
+// to --log-level-file.
+func settingsApplier(level uberzap.AtomicLevel) func(operatorconfig.Settings) {
+	startLevel := level.Level()
+	levelSet := false
+	var gzipImports *bool
+	return func(s operatorconfig.Settings) {
+		if s.LogLevel != "" {
+			// The settings were validated when they were loaded.
+			newLevel, _ := logger.ParseLevel(s.LogLevel)
+			level.SetLevel(newLevel)
+			levelSet = true
+		} else if levelSet {
+			level.SetLevel(startLevel)
+			levelSet = false
+		}
+		_ = controller.SetResyncInterval(s.ResyncInterval.Duration)
+		_ = controller.SetAzureRetryInterval(s.AzureRetryInterval.Duration)
+		controller.SetBreakingChangeDetection(s.FeatureGates[operatorconfig.FeatureBreakingChangeDetection])
+		// Setting compression again would also forget that Azure rejected compressed bodies.
+		if gzip := s.FeatureGates[operatorconfig.FeatureGzipOpenAPIImports]; gzipImports == nil || *gzipImports != gzip {
+			apim.SetRequestCompression(gzip)
+			gzipImports = &gzip
+		}
+		setupLog.Info("⚙️ Applied settings", "logLevel", level.Level().String(), "resyncInterval",
+			s.ResyncInterval.Duration, "azureRetryInterval", s.AzureRetryInterval.Duration, "featureGates", s.FeatureGates)
+	}
+}
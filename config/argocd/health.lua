@@ -0,0 +1,27 @@
+-- Health assessment of apim.operator.io resources for Argo CD.
+-- Every kind reports a Ready condition, which this script maps to an Argo CD health status.
+hs = {}
+if obj.status ~= nil and obj.status.conditions ~= nil then
+  for _, condition in ipairs(obj.status.conditions) do
+    if condition.type == "Ready" then
+      if condition.observedGeneration ~= nil and obj.metadata.generation ~= nil
+          and condition.observedGeneration < obj.metadata.generation then
+        hs.status = "Progressing"
+        hs.message = "Waiting for the operator to sync the latest spec"
+        return hs
+      end
+      if condition.status == "True" then
+        hs.status = "Healthy"
+      elseif condition.status == "False" then
+        hs.status = "Degraded"
+      else
+        hs.status = "Progressing"
+      end
+      hs.message = condition.message
+      return hs
+    end
+  end
+end
+hs.status = "Progressing"
+hs.message = "Waiting for the operator to report readiness"
+return hs
@@ -0,0 +1,222 @@
+// Package apim provides functions for interacting with Azure API Management (APIM) REST API.
+// This file contains the registration of imported APIs in an Azure API Center inventory.
+package apim
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"sigs.k8s.io/yaml"
+
+	"github.com/hedinit/azure-apim-operator/internal/redact"
+)
+
+// apiCenterAPIVersion is the Microsoft.ApiCenter management API version.
+const apiCenterAPIVersion = "2024-03-01"
+
+// APICenterConfig identifies the Azure API Center workspace that APIs are registered in.
+type APICenterConfig struct {
+	// SubscriptionID is the Azure subscription ID where the API Center service is located.
+	SubscriptionID string
+	// ResourceGroup is the Azure resource group where the API Center service is located.
+	ResourceGroup string
+	// ServiceName is the name of the API Center service.
+	ServiceName string
+	// Workspace is the API Center workspace, usually "default".
+	Workspace string
+	// BearerToken is the Azure AD authentication token for the Azure management API.
+	BearerToken string
+}
+
+// APICenterRegistration describes an API imported into APIM, as it is recorded in API Center.
+type APICenterRegistration struct {
+	// APIID is the API name in API Center. It is the APIM API ID, so both inventories line up.
+	APIID string
+	// Version is the API version name, derived from the APIM revision.
+	Version string
+	// OpenAPIContent is the definition that was imported into APIM.
+	OpenAPIContent []byte
+	// Owner is recorded as the contact of the API, if set.
+	Owner string
+	// DeveloperPortalURL is recorded as the external documentation of the API, if set.
+	DeveloperPortalURL string
+	// Environment is the API Center environment to record a deployment in. Empty skips the deployment.
+	Environment string
+	// GatewayURL is the runtime URL of the deployment.
+	GatewayURL string
+}
+
+// RegisterAPIInAPICenter creates or updates the API, its version and OpenAPI definition in API Center and,
+// if an environment is given, a deployment of the definition to that environment. Every call is idempotent,
+// so it is safe to register the same API after each import.
+func (c *Client) RegisterAPIInAPICenter(ctx context.Context, config APICenterConfig, registration APICenterRegistration) (err error) {
+	ctx, span := startSpan(ctx, "apim.RegisterAPIInAPICenter", config.ServiceName, registration.APIID)
+	defer func() { endSpan(span, err) }()
+
+	apiPath := fmt.Sprintf("/apis/%s", registration.APIID)
+	versionPath := fmt.Sprintf("%s/versions/%s", apiPath, registration.Version)
+	definitionPath := versionPath + "/definitions/openapi"
+
+	api := map[string]interface{}{
+		"title": registration.APIID,
+		"kind":  "rest",
+	}
+	if registration.Owner != "" {
+		api["contacts"] = []map[string]string{{"name": registration.Owner}}
+	}
+	if registration.DeveloperPortalURL != "" {
+		api["externalDocumentation"] = []map[string]string{{"title": "Developer portal", "url": registration.DeveloperPortalURL}}
+	}
+	if err := c.putAPICenterResource(ctx, config.BearerToken, c.workspaceURL(config, apiPath), api); err != nil {
+		return err
+	}
+
+	if err := c.putAPICenterResource(ctx, config.BearerToken, c.workspaceURL(config, versionPath), map[string]interface{}{
+		"title":          registration.Version,
+		"lifecycleStage": "production",
+	}); err != nil {
+		return err
+	}
+
+	if err := c.putAPICenterResource(ctx, config.BearerToken, c.workspaceURL(config, definitionPath), map[string]interface{}{
+		"title": "OpenAPI",
+	}); err != nil {
+		return err
+	}
+
+	if err := c.importAPICenterSpecification(ctx, config, definitionPath, registration); err != nil {
+		return err
+	}
+
+	if registration.Environment != "" {
+		deployment := map[string]interface{}{
+			"title":         registration.Environment,
+			"environmentId": fmt.Sprintf("/workspaces/%s/environments/%s", config.Workspace, registration.Environment),
+			"definitionId":  fmt.Sprintf("/workspaces/%s%s", config.Workspace, definitionPath),
+			"state":         "active",
+			"server":        map[string]interface{}{"runtimeUri": []string{registration.GatewayURL}},
+		}
+		if err := c.putAPICenterResource(ctx, config.BearerToken, c.workspaceURL(config, apiPath+"/deployments/"+registration.Environment), deployment); err != nil {
+			return err
+		}
+	}
+
+	logger.Info("✅ API registered in API Center",
+		"apiID", registration.APIID,
+		"apiCenter", config.ServiceName,
+		"version", registration.Version,
+		"environment", registration.Environment,
+	)
+	return nil
+}
+
+// workspaceURL returns the management URL of an entity below the API Center workspace, with the API version.
+func (c *Client) workspaceURL(config APICenterConfig, path string) string {
+	return fmt.Sprintf(
+		"%s/subscriptions/%s/resourceGroups/%s/providers/Microsoft.ApiCenter/services/%s/workspaces/%s%s?api-version=%s",
+		c.endpoint(),
+		config.SubscriptionID,
+		config.ResourceGroup,
+		config.ServiceName,
+		config.Workspace,
+		path,
+		apiCenterAPIVersion,
+	)
+}
+
+// putAPICenterResource creates or replaces an API Center entity with the given properties.
+func (c *Client) putAPICenterResource(ctx context.Context, bearerToken, url string, properties map[string]interface{}) error {
+	body, err := json.Marshal(map[string]interface{}{"properties": properties})
+	if err != nil {
+		return fmt.Errorf("failed to marshal API Center request: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to build API Center request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+bearerToken)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.doRequest(req)
+	if err != nil {
+		return fmt.Errorf("API Center request failed: %w", err)
+	}
+	defer func() {
+		if closeErr := resp.Body.Close(); closeErr != nil {
+			logger.Error(closeErr, "⚠️ Failed to close response body")
+		}
+	}()
+
+	respBody, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode >= 300 {
+		logger.Error(fmt.Errorf("status code: %d", resp.StatusCode), "❌ API Center request failed",
+			"url", url,
+			"status", resp.Status,
+			"body", redact.String(string(respBody)),
+		)
+		return newResponseError("failed to update API Center", resp, respBody)
+	}
+	return nil
+}
+
+// importAPICenterSpecification uploads the OpenAPI definition inline and waits for API Center to process it.
+func (c *Client) importAPICenterSpecification(ctx context.Context, config APICenterConfig, definitionPath string, registration APICenterRegistration) error {
+	name, version := openAPISpecification(registration.OpenAPIContent)
+	body, err := json.Marshal(map[string]interface{}{
+		"format":        "inline",
+		"value":         string(registration.OpenAPIContent),
+		"specification": map[string]string{"name": name, "version": version},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal API Center specification import: %w", err)
+	}
+
+	url := c.workspaceURL(config, definitionPath+"/importSpecification")
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to build API Center specification import: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+config.BearerToken)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.doRequest(req)
+	if err != nil {
+		return fmt.Errorf("API Center specification import failed: %w", err)
+	}
+	defer func() {
+		if closeErr := resp.Body.Close(); closeErr != nil {
+			logger.Error(closeErr, "⚠️ Failed to close response body")
+		}
+	}()
+
+	if resp.StatusCode == http.StatusAccepted {
+		return c.waitForAsyncImportCompletion(ctx, config.BearerToken, registration.APIID, resp)
+	}
+	respBody, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode >= 300 {
+		return newResponseError("failed to import specification into API Center", resp, respBody)
+	}
+	return nil
+}
+
+// openAPISpecification returns the API Center specification name and version of an OpenAPI definition in
+// JSON or YAML. Swagger 2.0 documents are reported as openapi 2.0; unreadable documents as openapi 3.0.0.
+func openAPISpecification(content []byte) (name, version string) {
+	var document struct {
+		OpenAPI string `json:"openapi"`
+		Swagger string `json:"swagger"`
+	}
+	if err := yaml.Unmarshal(content, &document); err == nil {
+		switch {
+		case document.OpenAPI != "":
+			return "openapi", document.OpenAPI
+		case document.Swagger != "":
+			return "openapi", document.Swagger
+		}
+	}
+	return "openapi", "3.0.0"
+}
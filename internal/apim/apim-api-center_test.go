@@ -0,0 +1,54 @@
+package apim
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestOpenAPISpecification(t *testing.T) {
+	tests := map[string]struct {
+		content string
+		version string
+	}{
+		"OpenAPI 3 JSON": {`{"openapi":"3.0.1","info":{"title":"orders"}}`, "3.0.1"},
+		"OpenAPI 3 YAML": {"openapi: 3.1.0\ninfo:\n  title: orders\n", "3.1.0"},
+		"Swagger 2":      {`{"swagger":"2.0"}`, "2.0"},
+		"unreadable":     {"<html>", "3.0.0"},
+	}
+	for name, tt := range tests {
+		if gotName, gotVersion := openAPISpecification([]byte(tt.content)); gotName != "openapi" || gotVersion != tt.version {
+			t.Errorf("%s: openAPISpecification() = %s %s, want openapi %s", name, gotName, gotVersion, tt.version)
+		}
+	}
+}
+
+func TestPutAPICenterResource(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Properties map[string]string `json:"properties"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("decode body: %v", err)
+		}
+		if r.Method != http.MethodPut || body.Properties["kind"] != "rest" {
+			t.Errorf("request = %s %v, want PUT with the properties wrapped", r.Method, body)
+		}
+		if r.URL.Query().Get("fail") != "" {
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte(`{"error":{"code":"AuthorizationFailed"}}`))
+		}
+	}))
+	defer server.Close()
+
+	properties := map[string]interface{}{"kind": "rest"}
+	if err := new(Client).putAPICenterResource(context.Background(), "token", server.URL, properties); err != nil {
+		t.Fatalf("putAPICenterResource() error = %v", err)
+	}
+	err := new(Client).putAPICenterResource(context.Background(), "token", server.URL+"?fail=1", properties)
+	if respErr, ok := err.(*ResponseError); !ok || respErr.Code != "AuthorizationFailed" {
+		t.Errorf("error = %v, want a 403 AuthorizationFailed ResponseError", err)
+	}
+}
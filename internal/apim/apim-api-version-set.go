@@ -0,0 +1,111 @@
+// Package apim provides functions for interacting with Azure API Management (APIM) REST API.
+// This file contains functions for managing API version sets in Azure APIM.
+package apim
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/hedinit/azure-apim-operator/internal/redact"
+)
+
+// APIMAPIVersionSetConfig holds the configuration for managing an API version set in Azure APIM.
+type APIMAPIVersionSetConfig struct {
+	// SubscriptionID is the Azure subscription ID where the APIM service is located.
+	SubscriptionID string
+	// ResourceGroup is the Azure resource group where the APIM service is located.
+	ResourceGroup string
+	// ServiceName is the name of the Azure API Management service instance.
+	ServiceName string
+	// VersionSetID is the identifier APIs join the version set by.
+	VersionSetID string
+	// DisplayName and Description are shown in the APIM UI and developer portal.
+	DisplayName string
+	Description string
+	// VersioningScheme is where clients put the version: Segment, Header or Query.
+	VersioningScheme string
+	// VersionHeaderName is the request header holding the version, for the Header scheme.
+	VersionHeaderName string
+	// VersionQueryName is the query parameter holding the version, for the Query scheme.
+	VersionQueryName string
+	// BearerToken is the Azure AD authentication token for the APIM management API.
+	BearerToken string
+}
+
+// UpsertAPIVersionSet creates or updates an API version set in Azure APIM. APIs join it by being imported
+// with its ID and a version.
+func (c *Client) UpsertAPIVersionSet(ctx context.Context, config APIMAPIVersionSetConfig) (err error) {
+	ctx, span := startSpan(ctx, "apim.UpsertAPIVersionSet", config.ServiceName, "")
+	defer func() { endSpan(span, err) }()
+
+	versionSetURL := fmt.Sprintf(
+		"%s/subscriptions/%s/resourceGroups/%s/providers/Microsoft.ApiManagement/service/%s/apiVersionSets/%s?api-version=%s",
+		c.endpoint(),
+		config.SubscriptionID,
+		config.ResourceGroup,
+		config.ServiceName,
+		config.VersionSetID,
+		c.apiVersion(),
+	)
+
+	properties := map[string]interface{}{
+		"displayName":      config.DisplayName,
+		"versioningScheme": config.VersioningScheme,
+	}
+	if config.Description != "" {
+		properties["description"] = config.Description
+	}
+	if config.VersionHeaderName != "" {
+		properties["versionHeaderName"] = config.VersionHeaderName
+	}
+	if config.VersionQueryName != "" {
+		properties["versionQueryName"] = config.VersionQueryName
+	}
+
+	body, err := json.Marshal(map[string]interface{}{"properties": properties})
+	if err != nil {
+		return fmt.Errorf("failed to marshal version set body: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, versionSetURL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to build version set request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+config.BearerToken)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("If-Match", "*")
+
+	logger.Info("🗂️ Upserting API version set",
+		"versionSetID", config.VersionSetID,
+		"url", versionSetURL,
+	)
+
+	resp, err := c.doRequest(req)
+	if err != nil {
+		return fmt.Errorf("version set request failed: %w", err)
+	}
+	defer func() {
+		if closeErr := resp.Body.Close(); closeErr != nil {
+			logger.Error(closeErr, "⚠️ Failed to close response body")
+		}
+	}()
+
+	respBody, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode >= 300 {
+		logger.Error(fmt.Errorf("status code: %d", resp.StatusCode), "❌ Failed to upsert API version set",
+			"status", resp.Status,
+			"body", redact.String(string(respBody)),
+		)
+		return newResponseError("failed to upsert API version set", resp, respBody)
+	}
+
+	logger.Info("✅ API version set upserted",
+		"versionSetID", config.VersionSetID,
+		"status", resp.Status,
+	)
+	return nil
+}
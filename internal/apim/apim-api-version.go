@@ -0,0 +1,34 @@
+// Package apim provides functions for interacting with Azure API Management (APIM) REST API.
+// This file contains the Microsoft.ApiManagement API versions requests can be sent with.
+package apim
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// DefaultAPIVersion is the Microsoft.ApiManagement API version requests are sent with when a Client has no
+// APIVersion.
+const DefaultAPIVersion = "2021-08-01"
+
+// SupportedAPIVersions are the Microsoft.ApiManagement API versions the request payloads are verified against
+// by the contract tests of the apimtest package. ValidateAPIVersion accepts only these.
+var SupportedAPIVersions = []string{DefaultAPIVersion, "2022-08-01", "2024-05-01"}
+
+// ValidateAPIVersion fails for a version that is not in SupportedAPIVersions. An empty version stands for
+// DefaultAPIVersion and is valid.
+func ValidateAPIVersion(version string) error {
+	if version != "" && !slices.Contains(SupportedAPIVersions, version) {
+		return fmt.Errorf("unsupported APIM API version %q: want one of %s", version, strings.Join(SupportedAPIVersions, ", "))
+	}
+	return nil
+}
+
+// apiVersion returns the API version the requests of c are sent with.
+func (c *Client) apiVersion() string {
+	if c == nil || c.APIVersion == "" {
+		return DefaultAPIVersion
+	}
+	return c.APIVersion
+}
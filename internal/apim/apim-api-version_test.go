@@ -0,0 +1,59 @@
+package apim
+
+import (
+	"net/url"
+	"testing"
+)
+
+func TestValidateAPIVersion(t *testing.T) {
+	for _, version := range append([]string{""}, SupportedAPIVersions...) {
+		if err := ValidateAPIVersion(version); err != nil {
+			t.Errorf("ValidateAPIVersion(%q) error = %v", version, err)
+		}
+	}
+	if err := ValidateAPIVersion("2019-12-01"); err == nil {
+		t.Error("ValidateAPIVersion() accepted an unsupported version")
+	}
+}
+
+func TestClientRequestURLs(t *testing.T) {
+	base, err := url.Parse("http://127.0.0.1:8080/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	export := ExportConfig{SubscriptionID: "sub", ResourceGroup: "rg", ServiceName: "apim"}
+	center := APICenterConfig{SubscriptionID: "sub", ResourceGroup: "rg", ServiceName: "center", Workspace: "default"}
+
+	tests := []struct {
+		name   string
+		client *Client
+		got    func(c *Client) string
+		want   string
+	}{
+		{
+			name:   "nil client",
+			client: nil,
+			got:    func(c *Client) string { return c.serviceURL(export, "/apis") },
+			want:   "https://management.azure.com/subscriptions/sub/resourceGroups/rg/providers/Microsoft.ApiManagement/service/apim/apis?api-version=2021-08-01",
+		},
+		{
+			name:   "base URL and api-version",
+			client: &Client{BaseURL: base, APIVersion: "2024-05-01"},
+			got:    func(c *Client) string { return c.serviceURL(export, "/apis") },
+			want:   "http://127.0.0.1:8080/subscriptions/sub/resourceGroups/rg/providers/Microsoft.ApiManagement/service/apim/apis?api-version=2024-05-01",
+		},
+		{
+			name:   "API Center keeps its version",
+			client: &Client{BaseURL: base, APIVersion: "2024-05-01"},
+			got:    func(c *Client) string { return c.workspaceURL(center, "/apis/orders") },
+			want:   "http://127.0.0.1:8080/subscriptions/sub/resourceGroups/rg/providers/Microsoft.ApiCenter/services/center/workspaces/default/apis/orders?api-version=" + apiCenterAPIVersion,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.got(tt.client); got != tt.want {
+				t.Errorf("URL = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
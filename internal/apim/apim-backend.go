@@ -0,0 +1,245 @@
+// Package apim provides functions for interacting with Azure API Management (APIM) REST API.
+// This file contains functions for managing backends in Azure APIM.
+package apim
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/hedinit/azure-apim-operator/internal/redact"
+)
+
+// circuitBreakerAPIVersion is the first supported API version that defines circuit breakers on backends.
+const circuitBreakerAPIVersion = "2024-05-01"
+
+// ErrCircuitBreakerNotSupported is returned by UpsertBackend for a circuit breaker when requests are sent with an
+// API version older than 2024-05-01, which would make APIM reject the backend.
+var ErrCircuitBreakerNotSupported = errors.New("backend circuit breakers need APIM API version " + circuitBreakerAPIVersion + " or later")
+
+// APIMBackendConfig holds the configuration for managing a backend in Azure APIM.
+type APIMBackendConfig struct {
+	// SubscriptionID is the Azure subscription ID where the APIM service is located.
+	SubscriptionID string
+	// ResourceGroup is the Azure resource group where the APIM service is located.
+	ResourceGroup string
+	// ServiceName is the name of the Azure API Management service instance.
+	ServiceName string
+	// BackendID is the identifier policies select the backend by.
+	BackendID string
+	// URL is the runtime URL of the backend.
+	URL string
+	// Protocol is the protocol of the backend, http or soap.
+	Protocol string
+	// Title and Description are shown in the APIM UI.
+	Title       string
+	Description string
+	// Credentials are sent to the backend with every request. Nil sends none.
+	Credentials *BackendCredentials
+	// ValidateCertificateChain and ValidateCertificateName control the TLS validation of the backend's
+	// certificate. Nil keeps the APIM default, which validates.
+	ValidateCertificateChain *bool
+	ValidateCertificateName  *bool
+	// CircuitBreaker stops requests to a failing backend. Nil disables it.
+	CircuitBreaker *BackendCircuitBreaker
+	// BearerToken is the Azure AD authentication token for the APIM management API.
+	BearerToken string
+}
+
+// BackendCredentials are the credentials APIM presents to a backend.
+type BackendCredentials struct {
+	// Header and Query hold the values of request headers and query parameters, by name.
+	Header map[string][]string
+	Query  map[string][]string
+	// AuthorizationScheme and AuthorizationParameter make up the Authorization header.
+	AuthorizationScheme    string
+	AuthorizationParameter string
+	// CertificateIDs are the resource IDs of client certificates of the APIM service.
+	CertificateIDs []string
+}
+
+// BackendCircuitBreaker is the circuit breaker rule of a backend. The circuit trips when FailureCount requests
+// fail within FailureInterval, and stays open for TripDuration.
+type BackendCircuitBreaker struct {
+	// Name is the name of the rule.
+	Name string
+	// FailureCount is the number of failures that trips the circuit.
+	FailureCount int32
+	// FailureInterval is the window failures are counted in.
+	FailureInterval time.Duration
+	// StatusCodeRanges are the response status codes counted as failures.
+	StatusCodeRanges []StatusCodeRange
+	// ErrorReasons are the gateway errors counted as failures.
+	ErrorReasons []string
+	// TripDuration is how long the circuit stays open.
+	TripDuration time.Duration
+	// AcceptRetryAfter keeps the circuit open for as long as the Retry-After header of the backend asks.
+	AcceptRetryAfter bool
+}
+
+// StatusCodeRange is an inclusive range of HTTP status codes.
+type StatusCodeRange struct {
+	Min int32
+	Max int32
+}
+
+// UpsertBackend creates or updates a backend in Azure APIM. Policies route requests to it with
+// <set-backend-service backend-id="..." />.
+func (c *Client) UpsertBackend(ctx context.Context, config APIMBackendConfig) (err error) {
+	ctx, span := startSpan(ctx, "apim.UpsertBackend", config.ServiceName, "")
+	defer func() { endSpan(span, err) }()
+
+	if config.CircuitBreaker != nil && c.apiVersion() < circuitBreakerAPIVersion {
+		return ErrCircuitBreakerNotSupported
+	}
+
+	backendURL := fmt.Sprintf(
+		"%s/subscriptions/%s/resourceGroups/%s/providers/Microsoft.ApiManagement/service/%s/backends/%s?api-version=%s",
+		c.endpoint(),
+		config.SubscriptionID,
+		config.ResourceGroup,
+		config.ServiceName,
+		config.BackendID,
+		c.apiVersion(),
+	)
+
+	body, err := json.Marshal(map[string]interface{}{"properties": backendProperties(config)})
+	if err != nil {
+		return fmt.Errorf("failed to marshal backend body: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, backendURL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to build backend request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+config.BearerToken)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("If-Match", "*")
+
+	logger.Info("🔌 Upserting backend",
+		"backendID", config.BackendID,
+		"url", backendURL,
+	)
+
+	resp, err := c.doRequest(req)
+	if err != nil {
+		return fmt.Errorf("backend request failed: %w", err)
+	}
+	defer func() {
+		if closeErr := resp.Body.Close(); closeErr != nil {
+			logger.Error(closeErr, "⚠️ Failed to close response body")
+		}
+	}()
+
+	// Credentials can hold secrets, so the response body is only logged redacted.
+	respBody, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode >= 300 {
+		logger.Error(fmt.Errorf("status code: %d", resp.StatusCode), "❌ Failed to upsert backend",
+			"status", resp.Status,
+			"body", redact.String(string(respBody)),
+		)
+		return newResponseError("failed to upsert backend", resp, respBody)
+	}
+
+	logger.Info("✅ Backend upserted",
+		"backendID", config.BackendID,
+		"status", resp.Status,
+	)
+	return nil
+}
+
+// backendProperties returns the properties of the backend body. Optional settings are left out when unset, so
+// APIM applies its defaults.
+func backendProperties(config APIMBackendConfig) map[string]interface{} {
+	properties := map[string]interface{}{
+		"url":      config.URL,
+		"protocol": config.Protocol,
+	}
+	if config.Title != "" {
+		properties["title"] = config.Title
+	}
+	if config.Description != "" {
+		properties["description"] = config.Description
+	}
+	if creds := config.Credentials; creds != nil {
+		credentials := map[string]interface{}{}
+		if len(creds.Header) > 0 {
+			credentials["header"] = creds.Header
+		}
+		if len(creds.Query) > 0 {
+			credentials["query"] = creds.Query
+		}
+		if creds.AuthorizationScheme != "" {
+			credentials["authorization"] = map[string]string{
+				"scheme":    creds.AuthorizationScheme,
+				"parameter": creds.AuthorizationParameter,
+			}
+		}
+		if len(creds.CertificateIDs) > 0 {
+			credentials["certificateIds"] = creds.CertificateIDs
+		}
+		properties["credentials"] = credentials
+	}
+	if config.ValidateCertificateChain != nil || config.ValidateCertificateName != nil {
+		tls := map[string]bool{}
+		if config.ValidateCertificateChain != nil {
+			tls["validateCertificateChain"] = *config.ValidateCertificateChain
+		}
+		if config.ValidateCertificateName != nil {
+			tls["validateCertificateName"] = *config.ValidateCertificateName
+		}
+		properties["tls"] = tls
+	}
+	if cb := config.CircuitBreaker; cb != nil {
+		statusCodeRanges := make([]map[string]int32, 0, len(cb.StatusCodeRanges))
+		for _, r := range cb.StatusCodeRanges {
+			statusCodeRanges = append(statusCodeRanges, map[string]int32{"min": r.Min, "max": r.Max})
+		}
+		failureCondition := map[string]interface{}{
+			"count":            cb.FailureCount,
+			"interval":         isoDuration(cb.FailureInterval),
+			"statusCodeRanges": statusCodeRanges,
+		}
+		if len(cb.ErrorReasons) > 0 {
+			failureCondition["errorReasons"] = cb.ErrorReasons
+		}
+		properties["circuitBreaker"] = map[string]interface{}{
+			"rules": []map[string]interface{}{{
+				"name":             cb.Name,
+				"failureCondition": failureCondition,
+				"tripDuration":     isoDuration(cb.TripDuration),
+				"acceptRetryAfter": cb.AcceptRetryAfter,
+			}},
+		}
+	}
+	return properties
+}
+
+// isoDuration formats d as an ISO 8601 duration such as PT1H30M, the format APIM expects for time spans.
+// Fractions of a second are dropped.
+func isoDuration(d time.Duration) string {
+	d = d.Truncate(time.Second)
+	if d <= 0 {
+		return "PT0S"
+	}
+	var b strings.Builder
+	b.WriteString("PT")
+	if h := d / time.Hour; h > 0 {
+		fmt.Fprintf(&b, "%dH", h)
+		d -= h * time.Hour
+	}
+	if m := d / time.Minute; m > 0 {
+		fmt.Fprintf(&b, "%dM", m)
+		d -= m * time.Minute
+	}
+	if s := d / time.Second; s > 0 {
+		fmt.Fprintf(&b, "%dS", s)
+	}
+	return b.String()
+}
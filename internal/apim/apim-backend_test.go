@@ -0,0 +1,42 @@
+package apim
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestIsoDuration(t *testing.T) {
+	tests := []struct {
+		d    time.Duration
+		want string
+	}{
+		{0, "PT0S"},
+		{30 * time.Second, "PT30S"},
+		{time.Minute, "PT1M"},
+		{90*time.Minute + 1500*time.Millisecond, "PT1H30M1S"},
+		{25 * time.Hour, "PT25H"},
+	}
+	for _, tt := range tests {
+		if got := isoDuration(tt.d); got != tt.want {
+			t.Errorf("isoDuration(%v) = %q, want %q", tt.d, got, tt.want)
+		}
+	}
+}
+
+func TestUpsertBackendCircuitBreakerAPIVersion(t *testing.T) {
+	config := APIMBackendConfig{
+		SubscriptionID: "sub", ResourceGroup: "rg", ServiceName: "apim-test", BearerToken: "token",
+		BackendID: "orders-backend", URL: "https://orders.internal.example.com", Protocol: "http",
+		CircuitBreaker: &BackendCircuitBreaker{Name: "breaker", FailureCount: 1, FailureInterval: time.Minute, TripDuration: time.Minute},
+	}
+
+	for _, version := range []string{"", "2022-08-01"} {
+		client := &Client{APIVersion: version}
+		// The check fails before a request is built, so no server is needed.
+		if err := client.UpsertBackend(context.Background(), config); !errors.Is(err, ErrCircuitBreakerNotSupported) {
+			t.Errorf("UpsertBackend() with API version %q error = %v, want ErrCircuitBreakerNotSupported", version, err)
+		}
+	}
+}
@@ -0,0 +1,163 @@
+// Package apim provides functions for interacting with Azure API Management (APIM) REST API.
+// This file contains the backup of an APIM service to Azure Storage.
+package apim
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/hedinit/azure-apim-operator/internal/redact"
+)
+
+// States of a backup operation, as returned by GetBackupState.
+const (
+	BackupInProgress = "InProgress"
+	BackupSucceeded  = "Succeeded"
+	BackupFailed     = "Failed"
+)
+
+// APIMBackupConfig holds the configuration for backing up an APIM service.
+type APIMBackupConfig struct {
+	// SubscriptionID is the Azure subscription ID where the APIM service is located.
+	SubscriptionID string
+	// ResourceGroup is the Azure resource group where the APIM service is located.
+	ResourceGroup string
+	// ServiceName is the name of the Azure API Management service instance.
+	ServiceName string
+	// BearerToken is the Azure AD authentication token for the APIM management API.
+	BearerToken string
+	// StorageAccount is the name of the storage account the backup is written to.
+	StorageAccount string
+	// Container is the blob container the backup is written to.
+	Container string
+	// BackupName is the name of the blob.
+	BackupName string
+	// IdentityClientID is the client ID of the user-assigned managed identity of the APIM service that writes
+	// the blob. Empty uses the system-assigned identity of the service.
+	IdentityClientID string
+}
+
+// StartBackup starts a backup of the APIM service to a blob and returns the URL its progress is read from
+// with GetBackupState. APIM backs up in the background, which takes up to an hour; an empty URL means the
+// backup already completed.
+func (c *Client) StartBackup(ctx context.Context, config APIMBackupConfig) (_ string, err error) {
+	ctx, span := startSpan(ctx, "apim.StartBackup", config.ServiceName, "")
+	defer func() { endSpan(span, err) }()
+
+	backupURL := fmt.Sprintf(
+		"%s/subscriptions/%s/resourceGroups/%s/providers/Microsoft.ApiManagement/service/%s/backup?api-version=%s",
+		c.endpoint(),
+		config.SubscriptionID,
+		config.ResourceGroup,
+		config.ServiceName,
+		c.apiVersion(),
+	)
+
+	backup := map[string]interface{}{
+		"storageAccount": config.StorageAccount,
+		"containerName":  config.Container,
+		"backupName":     config.BackupName,
+		"accessType":     "SystemAssignedManagedIdentity",
+	}
+	if config.IdentityClientID != "" {
+		backup["accessType"] = "UserAssignedManagedIdentity"
+		backup["clientId"] = config.IdentityClientID
+	}
+	body, err := json.Marshal(backup)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal backup body: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, backupURL, bytes.NewReader(body))
+	if err != nil {
+		return "", fmt.Errorf("failed to build backup request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+config.BearerToken)
+	req.Header.Set("Content-Type", "application/json")
+
+	logger.Info("💾 Starting APIM backup",
+		"serviceName", config.ServiceName,
+		"storageAccount", config.StorageAccount,
+		"container", config.Container,
+		"backupName", config.BackupName,
+	)
+
+	resp, err := c.doRequest(req)
+	if err != nil {
+		return "", fmt.Errorf("backup request failed: %w", err)
+	}
+	defer func() {
+		if closeErr := resp.Body.Close(); closeErr != nil {
+			logger.Error(closeErr, "⚠️ Failed to close response body")
+		}
+	}()
+
+	respBody, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode >= 300 {
+		logger.Error(fmt.Errorf("status code: %d", resp.StatusCode), "❌ Failed to start APIM backup",
+			"status", resp.Status,
+			"body", redact.String(string(respBody)),
+		)
+		return "", newResponseError("failed to start backup", resp, respBody)
+	}
+
+	operationURL := strings.TrimSpace(resp.Header.Get("Azure-AsyncOperation"))
+	if operationURL == "" {
+		operationURL = strings.TrimSpace(resp.Header.Get("Location"))
+	}
+	if strings.HasPrefix(operationURL, "/") {
+		operationURL = c.endpoint() + operationURL
+	}
+	if resp.StatusCode != http.StatusAccepted {
+		operationURL = ""
+	}
+	logger.Info("✅ APIM backup accepted", "serviceName", config.ServiceName, "backupName", config.BackupName, "status", resp.Status)
+	return operationURL, nil
+}
+
+// GetBackupState returns BackupInProgress, BackupSucceeded or BackupFailed for the backup operation at
+// operationURL. For a failed backup, the error describes the failure.
+func (c *Client) GetBackupState(ctx context.Context, bearerToken, operationURL string) (_ string, err error) {
+	ctx, span := startSpan(ctx, "apim.GetBackupState", "", "")
+	defer func() { endSpan(span, err) }()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, operationURL, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to build backup status request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+bearerToken)
+
+	resp, err := c.doRequest(req)
+	if err != nil {
+		return "", fmt.Errorf("backup status request failed: %w", err)
+	}
+	defer func() {
+		if closeErr := resp.Body.Close(); closeErr != nil {
+			logger.Error(closeErr, "⚠️ Failed to close response body")
+		}
+	}()
+
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode >= 300 {
+		return "", newResponseError("failed to get backup status", resp, body)
+	}
+
+	status := extractAsyncStatus(body)
+	switch strings.ToLower(status) {
+	case "succeeded":
+		return BackupSucceeded, nil
+	case "failed", "canceled", "cancelled":
+		return BackupFailed, fmt.Errorf("backup %s: %s", strings.ToLower(status), redact.String(string(body)))
+	case "":
+		// A Location poll answers 202 while the backup runs and 200 once it is done.
+		if resp.StatusCode != http.StatusAccepted {
+			return BackupSucceeded, nil
+		}
+	}
+	return BackupInProgress, nil
+}
@@ -0,0 +1,39 @@
+package apim
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestGetBackupState(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		body    string
+		want    string
+		wantErr bool
+	}{
+		{name: "accepted", status: http.StatusAccepted, want: BackupInProgress},
+		{name: "in progress", status: http.StatusOK, body: `{"status":"InProgress"}`, want: BackupInProgress},
+		{name: "succeeded", status: http.StatusOK, body: `{"status":"Succeeded"}`, want: BackupSucceeded},
+		{name: "location done", status: http.StatusOK, want: BackupSucceeded},
+		{name: "failed", status: http.StatusOK, body: `{"status":"Failed","error":{"code":"StorageAccessDenied"}}`, want: BackupFailed, wantErr: true},
+		{name: "poll error", status: http.StatusForbidden, body: `{"error":{"code":"AuthorizationFailed"}}`, want: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			state, err := new(Client).GetBackupState(context.Background(), "token", server.URL)
+			if state != tt.want || (err != nil) != tt.wantErr {
+				t.Errorf("GetBackupState() = %q, %v, want %q with error %v", state, err, tt.want, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,71 @@
+// Package apim provides functions for interacting with Azure API Management (APIM) REST API.
+// This file contains the Client that sends the requests.
+package apim
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/hedinit/azure-apim-operator/internal/identity"
+)
+
+// Client sends requests to the Azure Management API. Its request URLs are built from BaseURL and APIVersion.
+// The zero Client, and a nil *Client, send requests to the public https://management.azure.com endpoint with
+// DefaultAPIVersion. A Client must not be changed once it is in use; it is safe for concurrent use after that.
+type Client struct {
+	// BaseURL is the endpoint requests are sent to instead of https://management.azure.com, such as the
+	// Azure Resource Manager endpoint of a sovereign cloud or the fake of the apimtest package.
+	BaseURL *url.URL
+	// APIVersion is the Microsoft.ApiManagement api-version requests are sent with. Empty means
+	// DefaultAPIVersion. API Center requests and the readiness check keep their own versions.
+	APIVersion string
+	// Tokens provides the bearer token of requests whose config has no BearerToken. Nil leaves such requests
+	// without a token.
+	Tokens identity.TokenProvider
+	// HTTPClient sends the requests. Nil means http.DefaultClient.
+	HTTPClient *http.Client
+}
+
+// NewClient returns a Client that sends requests to baseURL, an absolute http or https URL, with apiVersion,
+// one of SupportedAPIVersions. An empty baseURL selects the public endpoint and an empty apiVersion
+// DefaultAPIVersion.
+func NewClient(baseURL, apiVersion string, tokens identity.TokenProvider, httpClient *http.Client) (*Client, error) {
+	if err := ValidateAPIVersion(apiVersion); err != nil {
+		return nil, err
+	}
+	c := &Client{APIVersion: apiVersion, Tokens: tokens, HTTPClient: httpClient}
+	if baseURL != "" {
+		u, err := parseEndpoint(baseURL)
+		if err != nil {
+			return nil, err
+		}
+		c.BaseURL = u
+	}
+	return c, nil
+}
+
+// httpClient returns the HTTP client requests are sent with.
+func (c *Client) httpClient() *http.Client {
+	if c != nil && c.HTTPClient != nil {
+		return c.HTTPClient
+	}
+	return http.DefaultClient
+}
+
+// authorize sets the Authorization header of req to a token of c.Tokens when req was built without a
+// bearer token.
+func (c *Client) authorize(req *http.Request) error {
+	if c == nil || c.Tokens == nil {
+		return nil
+	}
+	if auth := req.Header.Get("Authorization"); auth != "" && auth != "Bearer " {
+		return nil
+	}
+	token, err := c.Tokens.ManagementToken(req.Context())
+	if err != nil {
+		return fmt.Errorf("failed to get Azure management token: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	return nil
+}
@@ -0,0 +1,78 @@
+package apim
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// roundTripFunc is an http.RoundTripper that answers every request with a function.
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// fakeTokens is a TokenProvider that hands out one token.
+type fakeTokens string
+
+func (f fakeTokens) ManagementToken(context.Context) (string, error) {
+	return string(f), nil
+}
+
+func (f fakeTokens) Invalidate() {}
+
+func TestClient(t *testing.T) {
+	var got []*http.Request
+	client, err := NewClient("https://management.usgovcloudapi.net", "2024-05-01", fakeTokens("provided-token"), &http.Client{
+		Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+			got = append(got, req)
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Header:     http.Header{"Etag": []string{`"v1"`}},
+				Body:       io.NopCloser(strings.NewReader(`{"properties":{}}`)),
+				Request:    req,
+			}, nil
+		}),
+	})
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+
+	config := APIMDeploymentConfig{SubscriptionID: "sub", ResourceGroup: "rg", ServiceName: "apim-test", APIID: "orders"}
+	etag, exists, err := client.GetAPI(context.Background(), config)
+	if err != nil || !exists || etag != `"v1"` {
+		t.Fatalf("GetAPI() = %q, %v, %v, want the API", etag, exists, err)
+	}
+	config.BearerToken = "config-token"
+	if _, _, err := client.GetAPI(context.Background(), config); err != nil {
+		t.Fatalf("GetAPI() error = %v", err)
+	}
+
+	if len(got) != 2 {
+		t.Fatalf("sent %d requests, want 2", len(got))
+	}
+	if got[0].URL.Host != "management.usgovcloudapi.net" || got[0].URL.Query().Get("api-version") != "2024-05-01" {
+		t.Errorf("request URL = %s, want the base URL and api-version of the client", got[0].URL)
+	}
+	if auth := got[0].Header.Get("Authorization"); auth != "Bearer provided-token" {
+		t.Errorf("Authorization = %q without a token in the config, want the provided token", auth)
+	}
+	if auth := got[1].Header.Get("Authorization"); auth != "Bearer config-token" {
+		t.Errorf("Authorization = %q, want the token of the config", auth)
+	}
+}
+
+func TestNewClientInvalidBaseURL(t *testing.T) {
+	if _, err := NewClient("management.usgovcloudapi.net", "", nil, nil); err == nil {
+		t.Error("NewClient() error = nil, want an error for a URL without a scheme")
+	}
+}
+
+func TestNewClientUnsupportedAPIVersion(t *testing.T) {
+	if _, err := NewClient("", "2019-12-01", nil, nil); err == nil {
+		t.Error("NewClient() error = nil, want an error for an unsupported api-version")
+	}
+}
@@ -0,0 +1,109 @@
+// Package apim provides functions for interacting with Azure API Management (APIM) REST API.
+// This file contains the dry-run switch that suppresses Azure-mutating requests.
+package apim
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// dryRun holds whether Azure-mutating requests are suppressed. See SetDryRun.
+var dryRun atomic.Bool
+
+// SetDryRun enables or disables dry-run mode.
+// In dry-run mode every Azure-mutating request (anything other than GET or HEAD) is logged
+// with its method, URL and a digest of its body instead of being sent to Azure.
+// Read-only requests are still sent so that the operator can compare against the live APIM state.
+func SetDryRun(enabled bool) {
+	dryRun.Store(enabled)
+}
+
+// IsDryRun reports whether dry-run mode is enabled.
+func IsDryRun() bool {
+	return dryRun.Load()
+}
+
+// doRequest sends an Azure Management API request with the HTTP client of c inside a client span
+// that records the response status code and Azure request ID. The span is propagated in a W3C traceparent header,
+// so Azure-side telemetry that supports Trace Context can be correlated with the operator's trace.
+// When dry-run mode is enabled and the request would mutate Azure state, the request is
+// only logged and a synthetic 200 OK response with an empty body is returned.
+func (c *Client) doRequest(req *http.Request) (*http.Response, error) {
+	if err := c.authorize(req); err != nil {
+		return nil, err
+	}
+	ctx, span := tracer.Start(req.Context(), "HTTP "+req.Method,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(semconv.HTTPMethod(req.Method), semconv.HTTPURL(req.URL.String())),
+	)
+	req = req.WithContext(ctx)
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(req.Header))
+
+	start := time.Now()
+	resp, err := c.sendRequest(req)
+	observeRequest(req, resp, time.Since(start))
+	if resp != nil {
+		span.SetAttributes(semconv.HTTPStatusCode(resp.StatusCode))
+		if id := resp.Header.Get(RequestIDHeader); id != "" {
+			span.SetAttributes(AttrAzureRequestID.String(id))
+			recordRequestID(ctx, id)
+		}
+		if resp.StatusCode >= 400 {
+			span.SetStatus(codes.Error, resp.Status)
+		}
+	}
+	endSpan(span, err)
+	return resp, err
+}
+
+func (c *Client) sendRequest(req *http.Request) (*http.Response, error) {
+	if !IsDryRun() || req.Method == http.MethodGet || req.Method == http.MethodHead {
+		return c.httpClient().Do(req)
+	}
+
+	logger.Info("🧪 Dry-run: skipping Azure-mutating request",
+		"method", req.Method,
+		"url", req.URL.String(),
+		"bodySHA256", requestBodyDigest(req),
+	)
+
+	return &http.Response{
+		Status:     "200 OK (dry-run)",
+		StatusCode: http.StatusOK,
+		Header:     http.Header{},
+		Body:       io.NopCloser(strings.NewReader("")),
+		Request:    req,
+	}, nil
+}
+
+// requestBodyDigest returns the hex-encoded SHA-256 digest of the request body,
+// or an empty string when the request has no body.
+func requestBodyDigest(req *http.Request) string {
+	if req.GetBody == nil {
+		return ""
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return ""
+	}
+	defer func() {
+		_ = body.Close()
+	}()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, body); err != nil {
+		return ""
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
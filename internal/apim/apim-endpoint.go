@@ -0,0 +1,32 @@
+// Package apim provides functions for interacting with Azure API Management (APIM) REST API.
+// This file contains the endpoint Azure Management API request URLs are built with.
+package apim
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// defaultEndpoint is the public Azure Management API that requests are sent to when a Client has no BaseURL.
+const defaultEndpoint = "https://management.azure.com"
+
+// parseEndpoint parses a management endpoint, which must be an absolute http or https URL.
+func parseEndpoint(endpoint string) (*url.URL, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("invalid management endpoint %q: %w", endpoint, err)
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("invalid management endpoint %q: want an http or https URL", endpoint)
+	}
+	return u, nil
+}
+
+// endpoint returns the endpoint the request URLs of c start with, without a trailing slash.
+func (c *Client) endpoint() string {
+	if c == nil || c.BaseURL == nil {
+		return defaultEndpoint
+	}
+	return strings.TrimSuffix(c.BaseURL.String(), "/")
+}
@@ -0,0 +1,84 @@
+// Package apim provides functions for interacting with Azure API Management (APIM) REST API.
+// This file contains the error returned for failed Azure management API responses.
+package apim
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/hedinit/azure-apim-operator/internal/redact"
+)
+
+// ResponseError is returned when the Azure management API answers with an error status.
+// It keeps the details Azure support asks for: the HTTP status, the Azure error code and the request ID.
+type ResponseError struct {
+	// Operation describes the failed call, e.g. "failed to get API".
+	Operation string
+	// Status is the HTTP status line, e.g. "400 Bad Request".
+	Status string
+	// StatusCode is the HTTP status code.
+	StatusCode int
+	// Code is the error code from the Azure error body, e.g. "ValidationError", if present.
+	Code string
+	// RequestID is the x-ms-request-id response header, if present.
+	RequestID string
+	// Body is the redacted response body.
+	Body string
+	// RetryAfter is how long Azure asked to wait before retrying, from the Retry-After header that comes
+	// with throttled (429) and unavailable (503) responses. It is zero without one.
+	RetryAfter time.Duration
+}
+
+// Error returns the operation, status and response body.
+func (e *ResponseError) Error() string {
+	return fmt.Sprintf("%s: %s\n%s", e.Operation, e.Status, e.Body)
+}
+
+// newResponseError builds a ResponseError from an error response and its body.
+func newResponseError(operation string, resp *http.Response, body []byte) error {
+	return &ResponseError{
+		Operation:  operation,
+		Status:     resp.Status,
+		StatusCode: resp.StatusCode,
+		Code:       azureErrorCode(body),
+		RequestID:  resp.Header.Get(RequestIDHeader),
+		Body:       redact.String(string(body)),
+		RetryAfter: retryAfter(resp.Header.Get("Retry-After"), time.Now()),
+	}
+}
+
+// retryAfter parses a Retry-After header value, which is either a number of seconds or an HTTP date,
+// into the time to wait from now. Invalid and past values are zero.
+func retryAfter(value string, now time.Time) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return max(time.Duration(seconds)*time.Second, 0)
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		return max(date.Sub(now), 0)
+	}
+	return 0
+}
+
+// azureErrorCode returns the code of an Azure error body. ARM nests it as {"error": {"code": ...}},
+// while some APIM endpoints return {"code": ...}.
+func azureErrorCode(body []byte) string {
+	var parsed struct {
+		Code  string `json:"code"`
+		Error *struct {
+			Code string `json:"code"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal(body, &parsed); err != nil {
+		return ""
+	}
+	if parsed.Error != nil && parsed.Error.Code != "" {
+		return parsed.Error.Code
+	}
+	return parsed.Code
+}
@@ -0,0 +1,70 @@
+package apim
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestNewResponseError(t *testing.T) {
+	resp := &http.Response{
+		Status:     "400 Bad Request",
+		StatusCode: http.StatusBadRequest,
+		Header:     http.Header{},
+	}
+	resp.Header.Set(RequestIDHeader, "4f1c2d3e-request")
+	body := []byte(`{"error":{"code":"ValidationError","message":"One or more fields contain incorrect values."}}`)
+
+	err := fmt.Errorf("import: %w", newResponseError("APIM API failed", resp, body))
+
+	var respErr *ResponseError
+	if !errors.As(err, &respErr) {
+		t.Fatalf("errors.As() found no ResponseError in %v", err)
+	}
+	if respErr.StatusCode != http.StatusBadRequest || respErr.Code != "ValidationError" || respErr.RequestID != "4f1c2d3e-request" {
+		t.Errorf("ResponseError = %+v, want status 400, code ValidationError and the request ID", respErr)
+	}
+	if want := "APIM API failed: 400 Bad Request\n" + string(body); respErr.Error() != want {
+		t.Errorf("Error() = %q, want %q", respErr.Error(), want)
+	}
+}
+
+func TestAzureErrorCode(t *testing.T) {
+	tests := map[string]string{
+		`{"error":{"code":"ResourceNotFound"}}`: "ResourceNotFound",
+		`{"code":"ValidationError"}`:            "ValidationError",
+		`not json`:                              "",
+		``:                                      "",
+	}
+	for body, want := range tests {
+		if got := azureErrorCode([]byte(body)); got != want {
+			t.Errorf("azureErrorCode(%q) = %q, want %q", body, got, want)
+		}
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	now := time.Date(2025, 6, 2, 10, 0, 0, 0, time.UTC)
+	tests := map[string]time.Duration{
+		"":                              0,
+		"17":                            17 * time.Second,
+		"-5":                            0,
+		"Mon, 02 Jun 2025 10:00:30 GMT": 30 * time.Second,
+		"Mon, 02 Jun 2025 09:59:00 GMT": 0,
+		"soon":                          0,
+	}
+	for value, want := range tests {
+		if got := retryAfter(value, now); got != want {
+			t.Errorf("retryAfter(%q) = %v, want %v", value, got, want)
+		}
+	}
+
+	resp := &http.Response{Status: "429 Too Many Requests", StatusCode: http.StatusTooManyRequests, Header: http.Header{}}
+	resp.Header.Set("Retry-After", "7")
+	var respErr *ResponseError
+	if !errors.As(newResponseError("failed to assign tag", resp, nil), &respErr) || respErr.RetryAfter != 7*time.Second {
+		t.Errorf("newResponseError() = %+v, want RetryAfter 7s", respErr)
+	}
+}
@@ -0,0 +1,138 @@
+// Package apim provides functions for interacting with Azure API Management (APIM) REST API.
+// This file contains the helpers for conditional writes with If-Match.
+package apim
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// ifMatchAny is the If-Match value that matches any existing entity, or none.
+const ifMatchAny = "*"
+
+// driftHandlerKey is the context key of the handler set by WithDriftHandler.
+type driftHandlerKey struct{}
+
+// WithDriftHandler returns a context whose conditional writes call handler when they find that the entity
+// was changed outside the operator since its ETag was recorded. deleted reports that it was deleted.
+func WithDriftHandler(ctx context.Context, handler func(deleted bool)) context.Context {
+	return context.WithValue(ctx, driftHandlerKey{}, handler)
+}
+
+// normalizeETag formats an ETag response header for use in If-Match.
+// Azure APIM returns etags as "W/\"etag-value\"" or "\"etag-value\"", but only accepts the quoted strong form.
+func normalizeETag(etag string) string {
+	etag = strings.TrimSpace(etag)
+	if etag == "" {
+		return ""
+	}
+	etag = strings.TrimPrefix(etag, "W/")
+	etag = strings.TrimSpace(strings.Trim(etag, "\""))
+	return fmt.Sprintf(`"%s"`, etag)
+}
+
+// ErrModifiedOutsideOperator is wrapped by the error of a conditional write whose recorded ETag is stale:
+// the entity was changed or deleted in APIM since the operator last wrote it.
+var ErrModifiedOutsideOperator = errors.New("modified in APIM outside the operator")
+
+// IsPreconditionFailed reports whether err is an APIM answer to a conditional write whose ETag was stale,
+// because the entity was changed or deleted outside the operator.
+func IsPreconditionFailed(err error) bool {
+	var respErr *ResponseError
+	return errors.Is(err, ErrModifiedOutsideOperator) ||
+		errors.As(err, &respErr) && respErr.StatusCode == http.StatusPreconditionFailed
+}
+
+// getETag reads the current ETag of the APIM entity at url. It returns an empty ETag when the entity does not exist.
+func (c *Client) getETag(ctx context.Context, url, bearerToken string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to build request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+bearerToken)
+
+	resp, err := c.doRequest(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to call APIM API: %w", err)
+	}
+	defer func() {
+		if closeErr := resp.Body.Close(); closeErr != nil {
+			logger.Error(closeErr, "⚠️ Failed to close response body")
+		}
+	}()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return "", nil
+	}
+	if resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(resp.Body)
+		return "", newResponseError("failed to read entity tag", resp, body)
+	}
+	return normalizeETag(resp.Header.Get("ETag")), nil
+}
+
+// conditionalWrite sends the write built by newRequest with If-Match set to etag, or to "*" when no ETag
+// is known. A 412 answer means the entity was changed outside the operator since etag was recorded,
+// and a 404 that it was deleted. The change is reported to the drift handler and the write fails with
+// ErrModifiedOutsideOperator, unless overwrite is set: then the current ETag is read with current and
+// the write is retried once with it.
+// The response body is read and closed; the caller only inspects the status and headers.
+func (c *Client) conditionalWrite(
+	ctx context.Context,
+	etag string,
+	overwrite bool,
+	newRequest func(ifMatch string) (*http.Request, error),
+	current func(ctx context.Context) (string, error),
+) (*http.Response, []byte, error) {
+	resp, body, err := c.sendWrite(etag, newRequest)
+	if err != nil || etag == "" || etag == ifMatchAny {
+		return resp, body, err
+	}
+	if resp.StatusCode != http.StatusPreconditionFailed && resp.StatusCode != http.StatusNotFound {
+		return resp, body, nil
+	}
+
+	deleted := resp.StatusCode == http.StatusNotFound
+	if handler, ok := ctx.Value(driftHandlerKey{}).(func(bool)); ok {
+		handler(deleted)
+	}
+	if !overwrite {
+		logger.Info("⚠️ APIM entity was modified outside the operator; not overwriting it", "staleETag", etag, "deleted", deleted)
+		return resp, body, fmt.Errorf("%w: %w", ErrModifiedOutsideOperator, newResponseError("conditional write failed", resp, body))
+	}
+
+	logger.Info("⚠️ APIM entity was modified outside the operator; re-reading it before overwriting", "staleETag", etag, "deleted", deleted)
+	etag, err = current(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	return c.sendWrite(etag, newRequest)
+}
+
+// sendWrite sends one conditional write and reads its body.
+func (c *Client) sendWrite(etag string, newRequest func(ifMatch string) (*http.Request, error)) (*http.Response, []byte, error) {
+	if etag == "" {
+		etag = ifMatchAny
+	}
+	req, err := newRequest(etag)
+	if err != nil {
+		return nil, nil, err
+	}
+	req.Header.Set("If-Match", etag)
+
+	resp, err := c.doRequest(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer func() {
+		if closeErr := resp.Body.Close(); closeErr != nil {
+			logger.Error(closeErr, "⚠️ Failed to close response body")
+		}
+	}()
+	body, _ := io.ReadAll(resp.Body)
+	return resp, body, nil
+}
@@ -0,0 +1,105 @@
+package apim
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestNormalizeETag(t *testing.T) {
+	tests := map[string]string{
+		"":               "",
+		`"abc"`:          `"abc"`,
+		`W/"abc"`:        `"abc"`,
+		"abc":            `"abc"`,
+		` W/"abc" `:      `"abc"`,
+		`"AAAAAAAABcE="`: `"AAAAAAAABcE="`,
+	}
+	for in, want := range tests {
+		if got := normalizeETag(in); got != want {
+			t.Errorf("normalizeETag(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestConditionalWrite(t *testing.T) {
+	tests := []struct {
+		name        string
+		etag        string
+		overwrite   bool
+		current     string
+		wantStatus  int
+		wantErr     bool
+		wantIfMatch []string
+		wantDrift   string
+	}{
+		{name: "recorded etag still current", etag: `"v2"`, current: `"v2"`, wantStatus: http.StatusOK, wantIfMatch: []string{`"v2"`}},
+		{name: "modified outside the operator", etag: `"v1"`, current: `"v2"`, wantStatus: http.StatusPreconditionFailed, wantErr: true, wantIfMatch: []string{`"v1"`}, wantDrift: "modified"},
+		{name: "deleted outside the operator", etag: `"v1"`, current: "", wantStatus: http.StatusNotFound, wantErr: true, wantIfMatch: []string{`"v1"`}, wantDrift: "deleted"},
+		{name: "modified and overwritten", etag: `"v1"`, overwrite: true, current: `"v2"`, wantStatus: http.StatusOK, wantIfMatch: []string{`"v1"`, `"v2"`}, wantDrift: "modified"},
+		{name: "deleted and recreated", etag: `"v1"`, overwrite: true, current: "", wantStatus: http.StatusOK, wantIfMatch: []string{`"v1"`, "*"}, wantDrift: "deleted"},
+		{name: "no recorded etag", etag: "", current: `"v2"`, wantStatus: http.StatusOK, wantIfMatch: []string{"*"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var ifMatch []string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got := r.Header.Get("If-Match")
+				ifMatch = append(ifMatch, got)
+				switch {
+				case got == "*" || got == tt.current:
+					w.Header().Set("ETag", `W/"v3"`)
+					w.WriteHeader(http.StatusOK)
+				case tt.current == "":
+					w.WriteHeader(http.StatusNotFound)
+				default:
+					w.WriteHeader(http.StatusPreconditionFailed)
+				}
+			}))
+			defer server.Close()
+
+			var drift string
+			ctx := WithDriftHandler(context.Background(), func(deleted bool) {
+				drift = "modified"
+				if deleted {
+					drift = "deleted"
+				}
+			})
+			resp, _, err := new(Client).conditionalWrite(ctx, tt.etag, tt.overwrite, func(string) (*http.Request, error) {
+				return http.NewRequest(http.MethodPut, server.URL, nil)
+			}, func(context.Context) (string, error) {
+				return tt.current, nil
+			})
+			if tt.wantErr != (err != nil) || tt.wantErr && !IsPreconditionFailed(err) {
+				t.Fatalf("conditionalWrite() error = %v, wantErr %t", err, tt.wantErr)
+			}
+			if resp.StatusCode != tt.wantStatus {
+				t.Errorf("status = %d, want %d", resp.StatusCode, tt.wantStatus)
+			}
+			if fmt.Sprint(ifMatch) != fmt.Sprint(tt.wantIfMatch) {
+				t.Errorf("If-Match headers = %q, want %q", ifMatch, tt.wantIfMatch)
+			}
+			if drift != tt.wantDrift {
+				t.Errorf("drift = %q, want %q", drift, tt.wantDrift)
+			}
+			if got := normalizeETag(resp.Header.Get("ETag")); !tt.wantErr && got != `"v3"` {
+				t.Errorf("ETag = %q, want %q", got, `"v3"`)
+			}
+		})
+	}
+}
+
+func TestIsPreconditionFailed(t *testing.T) {
+	if !IsPreconditionFailed(fmt.Errorf("import: %w", &ResponseError{StatusCode: http.StatusPreconditionFailed})) {
+		t.Error("IsPreconditionFailed() = false for a wrapped 412")
+	}
+	if !IsPreconditionFailed(fmt.Errorf("import: %w: %w", ErrModifiedOutsideOperator, &ResponseError{StatusCode: http.StatusNotFound})) {
+		t.Error("IsPreconditionFailed() = false for an entity deleted outside the operator")
+	}
+	if IsPreconditionFailed(&ResponseError{StatusCode: http.StatusConflict}) {
+		t.Error("IsPreconditionFailed() = true for a 409")
+	}
+}
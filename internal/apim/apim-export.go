@@ -0,0 +1,229 @@
+// Package apim provides functions for interacting with Azure API Management (APIM) REST API.
+// This file contains the read-only listings used to export an existing APIM instance.
+package apim
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// ExportConfig identifies the APIM service instance that is read by the List functions.
+type ExportConfig struct {
+	// SubscriptionID is the Azure subscription ID where the APIM service is located.
+	SubscriptionID string
+	// ResourceGroup is the Azure resource group where the APIM service is located.
+	ResourceGroup string
+	// ServiceName is the name of the Azure API Management service instance.
+	ServiceName string
+	// BearerToken is the Azure AD authentication token for the APIM management API.
+	BearerToken string
+}
+
+// ProductDetails represents a product of an Azure APIM service.
+type ProductDetails struct {
+	ID         string `json:"id"`
+	Name       string `json:"name"`
+	Properties struct {
+		// DisplayName is the product name shown in the APIM UI.
+		DisplayName string `json:"displayName"`
+		// Description is the optional description of the product.
+		Description string `json:"description"`
+		// State is either "published" or "notPublished".
+		State string `json:"state"`
+	} `json:"properties"`
+}
+
+// TagDetails represents a tag of an Azure APIM service.
+type TagDetails struct {
+	ID         string `json:"id"`
+	Name       string `json:"name"`
+	Properties struct {
+		// DisplayName is the tag name shown in the APIM UI.
+		DisplayName string `json:"displayName"`
+	} `json:"properties"`
+}
+
+// ListAPIs returns the current revision of every API of the APIM service.
+// Non-current revisions, whose names carry a ";rev=" suffix, are skipped.
+func (c *Client) ListAPIs(ctx context.Context, config ExportConfig) (_ []APIDetails, err error) {
+	ctx, span := startSpan(ctx, "apim.ListAPIs", config.ServiceName, "")
+	defer func() { endSpan(span, err) }()
+
+	var apis []APIDetails
+	err = c.listPages(ctx, config.BearerToken, c.serviceURL(config, "/apis"), "failed to list APIs", func(raw json.RawMessage) error {
+		var api APIDetails
+		if err := json.Unmarshal(raw, &api); err != nil {
+			return err
+		}
+		if !strings.Contains(api.Name, ";rev=") {
+			apis = append(apis, api)
+		}
+		return nil
+	})
+	return apis, err
+}
+
+// ListProducts returns every product of the APIM service.
+func (c *Client) ListProducts(ctx context.Context, config ExportConfig) (_ []ProductDetails, err error) {
+	ctx, span := startSpan(ctx, "apim.ListProducts", config.ServiceName, "")
+	defer func() { endSpan(span, err) }()
+
+	var products []ProductDetails
+	err = c.listPages(ctx, config.BearerToken, c.serviceURL(config, "/products"), "failed to list products", func(raw json.RawMessage) error {
+		var product ProductDetails
+		if err := json.Unmarshal(raw, &product); err != nil {
+			return err
+		}
+		products = append(products, product)
+		return nil
+	})
+	return products, err
+}
+
+// ListTags returns every tag of the APIM service.
+func (c *Client) ListTags(ctx context.Context, config ExportConfig) (_ []TagDetails, err error) {
+	ctx, span := startSpan(ctx, "apim.ListTags", config.ServiceName, "")
+	defer func() { endSpan(span, err) }()
+
+	var tags []TagDetails
+	err = c.listPages(ctx, config.BearerToken, c.serviceURL(config, "/tags"), "failed to list tags", func(raw json.RawMessage) error {
+		var tag TagDetails
+		if err := json.Unmarshal(raw, &tag); err != nil {
+			return err
+		}
+		tags = append(tags, tag)
+		return nil
+	})
+	return tags, err
+}
+
+// ListAPIProductIDs returns the IDs of the products an API is assigned to.
+func (c *Client) ListAPIProductIDs(ctx context.Context, config ExportConfig, apiID string) (_ []string, err error) {
+	ctx, span := startSpan(ctx, "apim.ListAPIProductIDs", config.ServiceName, apiID)
+	defer func() { endSpan(span, err) }()
+
+	return c.listNames(ctx, config.BearerToken, c.serviceURL(config, "/apis/"+apiID+"/products"), "failed to list API products")
+}
+
+// ListAPITagIDs returns the IDs of the tags applied to an API.
+func (c *Client) ListAPITagIDs(ctx context.Context, config ExportConfig, apiID string) (_ []string, err error) {
+	ctx, span := startSpan(ctx, "apim.ListAPITagIDs", config.ServiceName, apiID)
+	defer func() { endSpan(span, err) }()
+
+	return c.listNames(ctx, config.BearerToken, c.serviceURL(config, "/apis/"+apiID+"/tags"), "failed to list API tags")
+}
+
+// GetAPIPolicy returns the XML of the API-level policy of an API.
+// It returns an empty string without error when the API has no policy.
+func (c *Client) GetAPIPolicy(ctx context.Context, config ExportConfig, apiID string) (_ string, err error) {
+	ctx, span := startSpan(ctx, "apim.GetAPIPolicy", config.ServiceName, apiID)
+	defer func() { endSpan(span, err) }()
+
+	url := c.serviceURL(config, "/apis/"+apiID+"/policies/policy") + "&format=rawxml"
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to build request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+config.BearerToken)
+
+	resp, err := c.doRequest(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to call APIM API: %w", err)
+	}
+	defer func() {
+		if closeErr := resp.Body.Close(); closeErr != nil {
+			logger.Error(closeErr, "⚠️ Failed to close response body", "apiID", apiID)
+		}
+	}()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return "", nil // API has no policy
+	}
+
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode >= 300 {
+		return "", newResponseError("failed to get API policy", resp, body)
+	}
+
+	var policy struct {
+		Properties struct {
+			Value string `json:"value"`
+		} `json:"properties"`
+	}
+	if err := json.Unmarshal(body, &policy); err != nil {
+		return "", fmt.Errorf("failed to parse policy response: %w", err)
+	}
+	return policy.Properties.Value, nil
+}
+
+// serviceURL returns the Azure Management API URL of a collection below the APIM service.
+func (c *Client) serviceURL(config ExportConfig, path string) string {
+	return fmt.Sprintf(
+		"%s/subscriptions/%s/resourceGroups/%s/providers/Microsoft.ApiManagement/service/%s%s?api-version=%s",
+		c.endpoint(),
+		config.SubscriptionID,
+		config.ResourceGroup,
+		config.ServiceName,
+		path,
+		c.apiVersion(),
+	)
+}
+
+// listNames returns the names of all entities of an Azure Management API collection.
+func (c *Client) listNames(ctx context.Context, bearerToken, url, operation string) ([]string, error) {
+	var names []string
+	err := c.listPages(ctx, bearerToken, url, operation, func(raw json.RawMessage) error {
+		var entity struct {
+			Name string `json:"name"`
+		}
+		if err := json.Unmarshal(raw, &entity); err != nil {
+			return err
+		}
+		names = append(names, entity.Name)
+		return nil
+	})
+	return names, err
+}
+
+// listPages calls visit for every entity of an Azure Management API collection,
+// following nextLink until the last page.
+func (c *Client) listPages(ctx context.Context, bearerToken, url, operation string, visit func(json.RawMessage) error) error {
+	for url != "" {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			return fmt.Errorf("failed to build request: %w", err)
+		}
+		req.Header.Set("Authorization", "Bearer "+bearerToken)
+
+		resp, err := c.doRequest(req)
+		if err != nil {
+			return fmt.Errorf("failed to call APIM API: %w", err)
+		}
+		body, _ := io.ReadAll(resp.Body)
+		if closeErr := resp.Body.Close(); closeErr != nil {
+			logger.Error(closeErr, "⚠️ Failed to close response body")
+		}
+		if resp.StatusCode >= 300 {
+			return newResponseError(operation, resp, body)
+		}
+
+		var page struct {
+			Value    []json.RawMessage `json:"value"`
+			NextLink string            `json:"nextLink"`
+		}
+		if err := json.Unmarshal(body, &page); err != nil {
+			return fmt.Errorf("failed to parse response: %w", err)
+		}
+		for _, raw := range page.Value {
+			if err := visit(raw); err != nil {
+				return fmt.Errorf("failed to parse response: %w", err)
+			}
+		}
+		url = page.NextLink
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+package apim
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestListPagesFollowsNextLink(t *testing.T) {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Authorization"); got != "Bearer token" {
+			t.Errorf("Authorization = %q, want %q", got, "Bearer token")
+		}
+		switch r.URL.Query().Get("page") {
+		case "":
+			fmt.Fprintf(w, `{"value":[{"name":"a"},{"name":"b"}],"nextLink":"%s?page=2"}`, server.URL)
+		case "2":
+			fmt.Fprint(w, `{"value":[{"name":"c"}]}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	names, err := new(Client).listNames(context.Background(), "token", server.URL, "failed to list")
+	if err != nil {
+		t.Fatalf("listNames() error = %v", err)
+	}
+	if fmt.Sprint(names) != "[a b c]" {
+		t.Errorf("names = %v, want [a b c]", names)
+	}
+}
+
+func TestListPagesReturnsResponseError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		fmt.Fprint(w, `{"error":{"code":"AuthorizationFailed"}}`)
+	}))
+	defer server.Close()
+
+	err := new(Client).listPages(context.Background(), "token", server.URL, "failed to list APIs", func(json.RawMessage) error {
+		t.Error("visit called for an error response")
+		return nil
+	})
+	respErr, ok := err.(*ResponseError)
+	if !ok {
+		t.Fatalf("error = %v, want *ResponseError", err)
+	}
+	if respErr.Code != "AuthorizationFailed" || respErr.StatusCode != http.StatusForbidden {
+		t.Errorf("error = %+v, want 403 AuthorizationFailed", respErr)
+	}
+}
@@ -0,0 +1,70 @@
+// Package apim provides functions for interacting with Azure API Management (APIM) REST API.
+// This file contains the gzip compression of large request bodies.
+package apim
+
+import (
+	"bytes"
+	"compress/gzip"
+	"net/http"
+	"strings"
+	"sync/atomic"
+)
+
+// gzipMinBytes is the smallest request body that is compressed. Smaller definitions upload quickly enough
+// that compressing them is not worth the CPU.
+const gzipMinBytes = 256 << 10
+
+var (
+	// gzipEnabled holds whether large request bodies are compressed. See SetRequestCompression.
+	gzipEnabled atomic.Bool
+	// gzipRejected is set once Azure rejects a compressed body, so later requests are sent uncompressed.
+	gzipRejected atomic.Bool
+)
+
+// SetRequestCompression enables or disables gzip compression of large OpenAPI imports.
+// Compressed bodies are sent with Content-Encoding: gzip. When Azure rejects one, the request is
+// retried uncompressed and compression stays off until the operator restarts.
+func SetRequestCompression(enabled bool) {
+	gzipEnabled.Store(enabled)
+	gzipRejected.Store(false)
+}
+
+// compressBody returns body gzipped together with its Content-Encoding, or body unchanged with an empty
+// encoding when compression is off, the body is small, or compressing it does not make it smaller.
+func compressBody(body []byte) ([]byte, string) {
+	if !gzipEnabled.Load() || gzipRejected.Load() || len(body) < gzipMinBytes {
+		return body, ""
+	}
+	var buf bytes.Buffer
+	buf.Grow(len(body) / 4)
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write(body); err != nil {
+		return body, ""
+	}
+	if err := zw.Close(); err != nil {
+		return body, ""
+	}
+	if buf.Len() >= len(body) {
+		return body, ""
+	}
+	return buf.Bytes(), "gzip"
+}
+
+// compressionRejected reports whether Azure refused a request because of its Content-Encoding.
+// Endpoints that do not accept compressed bodies answer 415, or 400 with an error about the encoding.
+func compressionRejected(resp *http.Response, body []byte) bool {
+	switch resp.StatusCode {
+	case http.StatusUnsupportedMediaType:
+		return true
+	case http.StatusBadRequest:
+		return strings.Contains(strings.ToLower(string(body)), "encoding")
+	}
+	return false
+}
+
+// rejectCompression turns compression off for the rest of the process.
+func rejectCompression(resp *http.Response) {
+	if !gzipRejected.Swap(true) {
+		logger.Info("⚠️ Azure rejected a gzip-compressed request; sending uncompressed bodies from now on", "status", resp.Status)
+	}
+}
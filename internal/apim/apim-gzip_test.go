@@ -0,0 +1,60 @@
+package apim
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestCompressBody(t *testing.T) {
+	defer SetRequestCompression(false)
+	large := []byte(strings.Repeat(`{"openapi":"3.0.1"}`, gzipMinBytes/10))
+	small := []byte(`{"openapi":"3.0.1"}`)
+
+	SetRequestCompression(false)
+	if _, encoding := compressBody(large); encoding != "" {
+		t.Errorf("compression disabled: encoding = %q, want none", encoding)
+	}
+
+	SetRequestCompression(true)
+	if _, encoding := compressBody(small); encoding != "" {
+		t.Errorf("small body: encoding = %q, want none", encoding)
+	}
+	compressed, encoding := compressBody(large)
+	if encoding != "gzip" || len(compressed) >= len(large) {
+		t.Fatalf("large body: encoding = %q, %d bytes from %d", encoding, len(compressed), len(large))
+	}
+	zr, err := gzip.NewReader(bytes.NewReader(compressed))
+	if err != nil {
+		t.Fatalf("gzip.NewReader() error = %v", err)
+	}
+	if got, _ := io.ReadAll(zr); !bytes.Equal(got, large) {
+		t.Error("compressed body does not decompress to the original")
+	}
+
+	rejectCompression(&http.Response{Status: "415 Unsupported Media Type"})
+	if _, encoding := compressBody(large); encoding != "" {
+		t.Errorf("after rejection: encoding = %q, want none", encoding)
+	}
+}
+
+func TestCompressionRejected(t *testing.T) {
+	tests := []struct {
+		status int
+		body   string
+		want   bool
+	}{
+		{status: http.StatusUnsupportedMediaType, want: true},
+		{status: http.StatusBadRequest, body: `{"error":{"code":"BadRequest","message":"Content-Encoding gzip is not supported"}}`, want: true},
+		{status: http.StatusBadRequest, body: `{"error":{"code":"ValidationError","message":"Parsing error"}}`, want: false},
+		{status: http.StatusOK, want: false},
+	}
+	for _, tt := range tests {
+		if got := compressionRejected(&http.Response{StatusCode: tt.status}, []byte(tt.body)); got != tt.want {
+			t.Errorf("compressionRejected(%d, %q) = %v, want %v", tt.status, tt.body, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,55 @@
+// Package apim provides functions for interacting with Azure API Management (APIM) REST API.
+// This file contains the metrics of the requests sent to the Azure Management API.
+package apim
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+// requestDuration measures the Azure Management API requests, by method, ARM resource type and status code.
+var requestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+	Name:    "apim_management_request_duration_seconds",
+	Help:    "Duration of Azure Management API requests in seconds, by method, resource type and status code.",
+	Buckets: prometheus.ExponentialBuckets(0.05, 2, 10),
+}, []string{"method", "resource_type", "code"})
+
+func init() {
+	metrics.Registry.MustRegister(requestDuration)
+}
+
+// observeRequest records a request that took duration. resp is nil if no response was received.
+func observeRequest(req *http.Request, resp *http.Response, duration time.Duration) {
+	code := "error"
+	if resp != nil {
+		code = strconv.Itoa(resp.StatusCode)
+	}
+	requestDuration.WithLabelValues(req.Method, resourceType(req.URL.Path), code).Observe(duration.Seconds())
+}
+
+// resourceType returns the collections of the ARM resource at path joined by "/", without the provider, e.g.
+// "service/apis/tags" for .../providers/Microsoft.ApiManagement/service/apim/apis/orders/tags/internal.
+// It keeps the label bounded while telling imports, assignments and lookups apart.
+func resourceType(path string) string {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	start := -1
+	for i, segment := range segments {
+		if strings.EqualFold(segment, "providers") {
+			start = i + 2
+		}
+	}
+	if start < 0 {
+		// Subscription-level requests, such as the readiness check listing subscriptions.
+		return segments[0]
+	}
+	var collections []string
+	for i := start; i < len(segments); i += 2 {
+		collections = append(collections, segments[i])
+	}
+	return strings.Join(collections, "/")
+}
@@ -0,0 +1,57 @@
+package apim
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	dto "github.com/prometheus/client_model/go"
+)
+
+func TestResourceType(t *testing.T) {
+	tests := map[string]string{
+		"/subscriptions/sub/resourceGroups/rg/providers/Microsoft.ApiManagement/service/apim":                         "service",
+		"/subscriptions/sub/resourceGroups/rg/providers/Microsoft.ApiManagement/service/apim/apis/orders;rev=2":       "service/apis",
+		"/subscriptions/sub/resourceGroups/rg/providers/Microsoft.ApiManagement/service/apim/apis/orders/tags/tag-1":  "service/apis/tags",
+		"/subscriptions/sub/resourceGroups/rg/providers/Microsoft.ApiManagement/service/apim/products/starter/apis/a": "service/products/apis",
+		"/subscriptions/sub/resourceGroups/rg/providers/Microsoft.ApiCenter/services/center/workspaces/default":       "services/workspaces",
+		"/subscriptions": "subscriptions",
+	}
+	for path, want := range tests {
+		if got := resourceType(path); got != want {
+			t.Errorf("resourceType(%s) = %q, want %q", path, got, want)
+		}
+	}
+}
+
+func TestRequestsAreMeasured(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	const path = "/subscriptions/sub/resourceGroups/rg/providers/Microsoft.ApiManagement/service/apim/tags/metrics-test"
+	before := testutil.CollectAndCount(requestDuration)
+	req, err := http.NewRequest(http.MethodPut, server.URL+path, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := new(Client).doRequest(req)
+	if err != nil {
+		t.Fatalf("doRequest() error = %v", err)
+	}
+	_ = resp.Body.Close()
+
+	if got := testutil.CollectAndCount(requestDuration); got != before+1 {
+		t.Errorf("series = %d, want a new series for the request", got)
+	}
+	var metric dto.Metric
+	if err := requestDuration.WithLabelValues(http.MethodPut, "service/tags", "429").(prometheus.Metric).Write(&metric); err != nil {
+		t.Fatal(err)
+	}
+	if got := metric.GetHistogram().GetSampleCount(); got != 1 {
+		t.Errorf("PUT service/tags 429 sample count = %d, want 1", got)
+	}
+}
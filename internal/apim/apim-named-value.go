@@ -0,0 +1,153 @@
+// Package apim provides functions for interacting with Azure API Management (APIM) REST API.
+// This file contains functions for managing named values in Azure APIM.
+package apim
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/hedinit/azure-apim-operator/internal/redact"
+)
+
+// APIMNamedValueConfig holds the configuration for managing a named value in Azure APIM.
+type APIMNamedValueConfig struct {
+	// SubscriptionID is the Azure subscription ID where the APIM service is located.
+	SubscriptionID string
+	// ResourceGroup is the Azure resource group where the APIM service is located.
+	ResourceGroup string
+	// ServiceName is the name of the Azure API Management service instance.
+	ServiceName string
+	// NamedValueID is the identifier of the named value.
+	NamedValueID string
+	// DisplayName is the name policies reference the value by.
+	DisplayName string
+	// Secret hides the value in the APIM UI and API.
+	Secret bool
+	// Value is the inline value. It is ignored when KeyVaultSecretIdentifier is set.
+	Value string
+	// KeyVaultSecretIdentifier is the URI of a Key Vault secret that APIM reads the value from.
+	KeyVaultSecretIdentifier string
+	// KeyVaultIdentityClientID is the user-assigned identity APIM reads the Key Vault secret with.
+	// Empty uses the system-assigned identity.
+	KeyVaultIdentityClientID string
+	// BearerToken is the Azure AD authentication token for the APIM management API.
+	BearerToken string
+}
+
+// UpsertNamedValue creates or updates a named value in Azure APIM and waits until APIM has stored it.
+func (c *Client) UpsertNamedValue(ctx context.Context, config APIMNamedValueConfig) (err error) {
+	ctx, span := startSpan(ctx, "apim.UpsertNamedValue", config.ServiceName, "")
+	defer func() { endSpan(span, err) }()
+
+	properties := map[string]interface{}{
+		"displayName": config.DisplayName,
+		"secret":      config.Secret,
+	}
+	if config.KeyVaultSecretIdentifier != "" {
+		keyVault := map[string]string{"secretIdentifier": config.KeyVaultSecretIdentifier}
+		if config.KeyVaultIdentityClientID != "" {
+			keyVault["identityClientId"] = config.KeyVaultIdentityClientID
+		}
+		properties["keyVault"] = keyVault
+	} else {
+		properties["value"] = config.Value
+	}
+	body, err := json.Marshal(map[string]interface{}{"properties": properties})
+	if err != nil {
+		return fmt.Errorf("failed to marshal named value body: %w", err)
+	}
+
+	logger.Info("🔐 Upserting named value", "namedValue", config.NamedValueID, "keyVault", config.KeyVaultSecretIdentifier != "")
+	if _, err := c.sendNamedValueRequest(ctx, config, http.MethodPut, "", body, "failed to upsert named value"); err != nil {
+		return err
+	}
+	logger.Info("✅ Named value upserted", "namedValue", config.NamedValueID)
+	return nil
+}
+
+// RefreshNamedValueSecret makes APIM read a Key Vault named value from Key Vault again, instead of waiting for
+// its own refresh, and waits until it has done so.
+func (c *Client) RefreshNamedValueSecret(ctx context.Context, config APIMNamedValueConfig) (err error) {
+	ctx, span := startSpan(ctx, "apim.RefreshNamedValueSecret", config.ServiceName, "")
+	defer func() { endSpan(span, err) }()
+
+	logger.Info("🔄 Refreshing named value from Key Vault", "namedValue", config.NamedValueID)
+	if _, err := c.sendNamedValueRequest(ctx, config, http.MethodPost, "/refreshSecret", nil, "failed to refresh named value"); err != nil {
+		return err
+	}
+	return nil
+}
+
+// GetNamedValueValue returns the current value of a named value in Azure APIM, including secret and Key Vault values.
+func (c *Client) GetNamedValueValue(ctx context.Context, config APIMNamedValueConfig) (_ string, err error) {
+	ctx, span := startSpan(ctx, "apim.GetNamedValueValue", config.ServiceName, "")
+	defer func() { endSpan(span, err) }()
+
+	// listValue is a POST, so it is suppressed in dry-run mode and returns no value.
+	respBody, err := c.sendNamedValueRequest(ctx, config, http.MethodPost, "/listValue", nil, "failed to read named value")
+	if err != nil {
+		return "", err
+	}
+	var value struct {
+		Value string `json:"value"`
+	}
+	if len(respBody) > 0 {
+		if err := json.Unmarshal(respBody, &value); err != nil {
+			return "", fmt.Errorf("failed to decode named value: %w", err)
+		}
+	}
+	return value.Value, nil
+}
+
+// sendNamedValueRequest sends a request to the named value or one of its actions and returns the response body.
+// Accepted requests are polled until they complete. Response bodies may hold secrets, so only error bodies are logged.
+func (c *Client) sendNamedValueRequest(ctx context.Context, config APIMNamedValueConfig, method, action string, body []byte, operation string) ([]byte, error) {
+	namedValueURL := fmt.Sprintf(
+		"%s/subscriptions/%s/resourceGroups/%s/providers/Microsoft.ApiManagement/service/%s/namedValues/%s%s?api-version=%s",
+		c.endpoint(),
+		config.SubscriptionID,
+		config.ResourceGroup,
+		config.ServiceName,
+		config.NamedValueID,
+		action,
+		c.apiVersion(),
+	)
+
+	req, err := http.NewRequestWithContext(ctx, method, namedValueURL, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to build named value request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+config.BearerToken)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.doRequest(req)
+	if err != nil {
+		return nil, fmt.Errorf("named value request failed: %w", err)
+	}
+	defer func() {
+		if closeErr := resp.Body.Close(); closeErr != nil {
+			logger.Error(closeErr, "⚠️ Failed to close response body")
+		}
+	}()
+
+	if resp.StatusCode == http.StatusAccepted {
+		return nil, c.waitForAsyncImportCompletion(ctx, config.BearerToken, config.NamedValueID, resp)
+	}
+	respBody, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode >= 300 {
+		logger.Error(fmt.Errorf("status code: %d", resp.StatusCode), "❌ Named value request failed",
+			"namedValue", config.NamedValueID,
+			"method", method,
+			"status", resp.Status,
+			"body", redact.String(string(respBody)),
+		)
+		return nil, newResponseError(operation, resp, respBody)
+	}
+	return respBody, nil
+}
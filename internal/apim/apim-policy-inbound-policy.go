@@ -9,6 +9,8 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+
+	"github.com/hedinit/azure-apim-operator/internal/redact"
 )
 
 // UpsertInboundPolicy creates or updates an inbound policy for an API or a specific operation in Azure APIM.
@@ -16,43 +18,24 @@ import (
 // The policy content should be a complete policy XML document including all sections.
 // If OperationID is provided, the policy will be applied to that specific operation (endpoint).
 // If OperationID is not provided, the policy will be applied to the entire API.
-func UpsertInboundPolicy(ctx context.Context, config APIMInboundPolicyConfig) error {
+// The write is conditional on config.ETag when set. It returns the ETag of the policy after the write.
+func (c *Client) UpsertInboundPolicy(ctx context.Context, config APIMInboundPolicyConfig) (_ string, err error) {
+	ctx, span := startSpan(ctx, "apim.UpsertInboundPolicy", config.ServiceName, config.APIID)
+	defer func() { endSpan(span, err) }()
+
 	// Skip if no API ID is provided.
 	if config.APIID == "" {
 		logger.Info("ℹ️ No API ID specified; skipping policy creation")
-		return nil
+		return "", nil
 	}
 
 	// Skip if no policy content is provided.
 	if config.PolicyContent == "" {
 		logger.Info("ℹ️ No policy content specified; skipping policy creation", "apiID", config.APIID)
-		return nil
+		return "", nil
 	}
 
-	// Build the Azure Management API URL for setting the policy.
-	// If OperationID is provided, apply to the specific operation.
-	// Otherwise, apply to the entire API.
-	var policyURL string
-	if config.OperationID != "" {
-		// Operation-level policy: /apis/{apiId}/operations/{operationId}/policies/policy
-		policyURL = fmt.Sprintf(
-			"https://management.azure.com/subscriptions/%s/resourceGroups/%s/providers/Microsoft.ApiManagement/service/%s/apis/%s/operations/%s/policies/policy?api-version=2021-08-01",
-			config.SubscriptionID,
-			config.ResourceGroup,
-			config.ServiceName,
-			config.APIID,
-			config.OperationID,
-		)
-	} else {
-		// API-level policy: /apis/{apiId}/policies/policy
-		policyURL = fmt.Sprintf(
-			"https://management.azure.com/subscriptions/%s/resourceGroups/%s/providers/Microsoft.ApiManagement/service/%s/apis/%s/policies/policy?api-version=2021-08-01",
-			config.SubscriptionID,
-			config.ResourceGroup,
-			config.ServiceName,
-			config.APIID,
-		)
-	}
+	policyURL := c.inboundPolicyURL(config)
 
 	// Construct the request body with the policy XML.
 	// Azure APIM expects the policy in a JSON structure with format and value.
@@ -65,18 +48,9 @@ func UpsertInboundPolicy(ctx context.Context, config APIMInboundPolicyConfig) er
 
 	bodyBytes, err := json.Marshal(policyBody)
 	if err != nil {
-		return fmt.Errorf("failed to marshal policy body: %w", err)
+		return "", fmt.Errorf("failed to marshal policy body: %w", err)
 	}
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodPut, policyURL, bytes.NewReader(bodyBytes))
-	if err != nil {
-		return fmt.Errorf("failed to build policy request: %w", err)
-	}
-
-	req.Header.Set("Authorization", "Bearer "+config.BearerToken)
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("If-Match", "*")
-
 	// Log the appropriate scope
 	if config.OperationID != "" {
 		logger.Info("📋 Upserting inbound policy for operation",
@@ -91,24 +65,28 @@ func UpsertInboundPolicy(ctx context.Context, config APIMInboundPolicyConfig) er
 		)
 	}
 
-	resp, err := http.DefaultClient.Do(req)
+	resp, respBody, err := c.conditionalWrite(ctx, config.ETag, config.OverwriteDrift, func(string) (*http.Request, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPut, policyURL, bytes.NewReader(bodyBytes))
+		if err != nil {
+			return nil, fmt.Errorf("failed to build policy request: %w", err)
+		}
+		req.Header.Set("Authorization", "Bearer "+config.BearerToken)
+		req.Header.Set("Content-Type", "application/json")
+		return req, nil
+	}, func(ctx context.Context) (string, error) {
+		return c.getETag(ctx, policyURL, config.BearerToken)
+	})
 	if err != nil {
-		return fmt.Errorf("policy request failed: %w", err)
+		return "", fmt.Errorf("policy request failed: %w", err)
 	}
-	defer func() {
-		if closeErr := resp.Body.Close(); closeErr != nil {
-			logger.Error(closeErr, "⚠️ Failed to close response body", "apiID", config.APIID)
-		}
-	}()
 
-	respBody, _ := io.ReadAll(resp.Body)
 	if resp.StatusCode >= 300 {
 		logger.Error(fmt.Errorf("status code: %d", resp.StatusCode), "❌ Failed to upsert inbound policy",
 			"apiID", config.APIID,
 			"status", resp.Status,
-			"body", string(respBody),
+			"body", redact.String(string(respBody)),
 		)
-		return fmt.Errorf("failed to upsert inbound policy: %s\n%s", resp.Status, string(respBody))
+		return "", newResponseError("failed to upsert inbound policy", resp, respBody)
 	}
 
 	// Log success with appropriate scope
@@ -125,9 +103,88 @@ func UpsertInboundPolicy(ctx context.Context, config APIMInboundPolicyConfig) er
 		)
 	}
 
+	return normalizeETag(resp.Header.Get("ETag")), nil
+}
+
+// DeleteInboundPolicy deletes the policy of an API, or of the operation when OperationID is set, from Azure APIM.
+// The API or operation then falls back to the policies of its product and the service.
+// A policy that doesn't exist, for example because its API was deleted, counts as deleted.
+func (c *Client) DeleteInboundPolicy(ctx context.Context, config APIMInboundPolicyConfig) (err error) {
+	ctx, span := startSpan(ctx, "apim.DeleteInboundPolicy", config.ServiceName, config.APIID)
+	defer func() { endSpan(span, err) }()
+
+	// Skip if no API ID is provided.
+	if config.APIID == "" {
+		logger.Info("ℹ️ No API ID specified; skipping policy deletion")
+		return nil
+	}
+
+	policyURL := c.inboundPolicyURL(config)
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, policyURL, nil)
+	if err != nil {
+		return fmt.Errorf("failed to build policy deletion request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+config.BearerToken)
+	req.Header.Set("If-Match", "*")
+
+	logger.Info("🗑️ Deleting inbound policy", "apiID", config.APIID, "operationID", config.OperationID, "url", policyURL)
+
+	resp, err := c.doRequest(req)
+	if err != nil {
+		return fmt.Errorf("policy deletion request failed: %w", err)
+	}
+	defer func() {
+		if closeErr := resp.Body.Close(); closeErr != nil {
+			logger.Error(closeErr, "⚠️ Failed to close response body", "apiID", config.APIID)
+		}
+	}()
+
+	respBody, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode == http.StatusNotFound {
+		logger.Info("ℹ️ Inbound policy not found, already deleted", "apiID", config.APIID, "operationID", config.OperationID)
+		return nil
+	}
+	if resp.StatusCode >= 300 {
+		logger.Error(fmt.Errorf("status code: %d", resp.StatusCode), "❌ Failed to delete inbound policy",
+			"apiID", config.APIID,
+			"status", resp.Status,
+			"body", redact.String(string(respBody)),
+		)
+		return newResponseError("failed to delete inbound policy", resp, respBody)
+	}
+
+	logger.Info("✅ Inbound policy deleted", "apiID", config.APIID, "operationID", config.OperationID, "status", resp.Status)
 	return nil
 }
 
+// inboundPolicyURL returns the management URL of the policy config applies to: the policy of the operation
+// when OperationID is set, and the policy of the entire API otherwise.
+func (c *Client) inboundPolicyURL(config APIMInboundPolicyConfig) string {
+	if config.OperationID != "" {
+		// Operation-level policy: /apis/{apiId}/operations/{operationId}/policies/policy
+		return fmt.Sprintf(
+			"%s/subscriptions/%s/resourceGroups/%s/providers/Microsoft.ApiManagement/service/%s/apis/%s/operations/%s/policies/policy?api-version=%s",
+			c.endpoint(),
+			config.SubscriptionID,
+			config.ResourceGroup,
+			config.ServiceName,
+			config.APIID,
+			config.OperationID,
+			c.apiVersion(),
+		)
+	}
+	// API-level policy: /apis/{apiId}/policies/policy
+	return fmt.Sprintf(
+		"%s/subscriptions/%s/resourceGroups/%s/providers/Microsoft.ApiManagement/service/%s/apis/%s/policies/policy?api-version=%s",
+		c.endpoint(),
+		config.SubscriptionID,
+		config.ResourceGroup,
+		config.ServiceName,
+		config.APIID,
+		c.apiVersion(),
+	)
+}
+
 // APIMInboundPolicyConfig contains the configuration needed to create or update an inbound policy in Azure APIM.
 // Inbound policies are used to control the inbound traffic to an API.
 type APIMInboundPolicyConfig struct {
@@ -147,4 +204,9 @@ type APIMInboundPolicyConfig struct {
 	BearerToken string
 	// PolicyContent is the XML content of the policy to be applied.
 	PolicyContent string
+	// ETag is the ETag of the policy recorded after the last write. When set, the upsert is conditional on it.
+	ETag string
+	// OverwriteDrift makes an upsert that finds the policy changed outside the operator overwrite it instead of
+	// failing with ErrModifiedOutsideOperator.
+	OverwriteDrift bool
 }
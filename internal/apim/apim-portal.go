@@ -0,0 +1,80 @@
+// Package apim provides functions for interacting with Azure API Management (APIM) REST API.
+// This file contains the publishing of the APIM developer portal.
+package apim
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/hedinit/azure-apim-operator/internal/redact"
+)
+
+// PublishDeveloperPortal publishes the developer portal by creating a portal revision that is marked current.
+// revisionID names the revision and must be unique within the service. APIM publishes the portal in the
+// background; the function returns once the publish was accepted.
+func (c *Client) PublishDeveloperPortal(ctx context.Context, config APIMDeploymentConfig, revisionID, description string) (err error) {
+	ctx, span := startSpan(ctx, "apim.PublishDeveloperPortal", config.ServiceName, "")
+	defer func() { endSpan(span, err) }()
+
+	revisionURL := fmt.Sprintf(
+		"%s/subscriptions/%s/resourceGroups/%s/providers/Microsoft.ApiManagement/service/%s/portalRevisions/%s?api-version=%s",
+		c.endpoint(),
+		config.SubscriptionID,
+		config.ResourceGroup,
+		config.ServiceName,
+		revisionID,
+		c.apiVersion(),
+	)
+
+	body, err := json.Marshal(map[string]interface{}{
+		"properties": map[string]interface{}{
+			"description": description,
+			"isCurrent":   true,
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal portal revision: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, revisionURL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to build portal revision request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+config.BearerToken)
+	req.Header.Set("Content-Type", "application/json")
+
+	logger.Info("📰 Publishing developer portal",
+		"serviceName", config.ServiceName,
+		"revision", revisionID,
+	)
+
+	resp, err := c.doRequest(req)
+	if err != nil {
+		return fmt.Errorf("portal revision request failed: %w", err)
+	}
+	defer func() {
+		if closeErr := resp.Body.Close(); closeErr != nil {
+			logger.Error(closeErr, "⚠️ Failed to close response body")
+		}
+	}()
+
+	respBody, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode >= 300 {
+		logger.Error(fmt.Errorf("status code: %d", resp.StatusCode), "❌ Failed to publish developer portal",
+			"status", resp.Status,
+			"body", redact.String(string(respBody)),
+		)
+		return newResponseError("failed to publish developer portal", resp, respBody)
+	}
+
+	logger.Info("✅ Developer portal publish accepted",
+		"serviceName", config.ServiceName,
+		"revision", revisionID,
+		"status", resp.Status,
+	)
+	return nil
+}
@@ -9,24 +9,32 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+
+	"github.com/hedinit/azure-apim-operator/internal/redact"
 )
 
 // UpsertProduct creates or updates a product in Azure APIM.
 // Products are used to group APIs and require subscriptions for access.
 // If the product already exists, it will be updated with the new configuration.
-func UpsertProduct(ctx context.Context, config APIMProductConfig) error {
+// The write is conditional on config.ETag when set. It returns the ETag of the product after the write.
+func (c *Client) UpsertProduct(ctx context.Context, config APIMProductConfig) (_ string, err error) {
+	ctx, span := startSpan(ctx, "apim.UpsertProduct", config.ServiceName, "")
+	defer func() { endSpan(span, err) }()
+
 	// Skip if no product ID is provided.
 	if config.ProductID == "" {
 		logger.Info("ℹ️ No product ID specified; skipping product creation")
-		return nil
+		return "", nil
 	}
 
 	productURL := fmt.Sprintf(
-		"https://management.azure.com/subscriptions/%s/resourceGroups/%s/providers/Microsoft.ApiManagement/service/%s/products/%s?api-version=2021-08-01",
+		"%s/subscriptions/%s/resourceGroups/%s/providers/Microsoft.ApiManagement/service/%s/products/%s?api-version=%s",
+		c.endpoint(),
 		config.SubscriptionID,
 		config.ResourceGroup,
 		config.ServiceName,
 		config.ProductID,
+		c.apiVersion(),
 	)
 
 	// Determine the product state based on the Published flag.
@@ -41,7 +49,7 @@ func UpsertProduct(ctx context.Context, config APIMProductConfig) error {
 			"displayName":          config.DisplayName,
 			"description":          config.Description,
 			"subscriptionRequired": true,
-			"approvalRequired":     false,
+			"approvalRequired":     config.ApprovalRequired,
 			"subscriptionsLimit":   1000,
 			"state":                state,
 		},
@@ -49,40 +57,35 @@ func UpsertProduct(ctx context.Context, config APIMProductConfig) error {
 
 	bodyBytes, err := json.Marshal(productBody)
 	if err != nil {
-		return fmt.Errorf("failed to marshal product body: %w", err)
-	}
-
-	req, err := http.NewRequestWithContext(ctx, http.MethodPut, productURL, bytes.NewReader(bodyBytes))
-	if err != nil {
-		return fmt.Errorf("failed to build product creation request: %w", err)
+		return "", fmt.Errorf("failed to marshal product body: %w", err)
 	}
 
-	req.Header.Set("Authorization", "Bearer "+config.BearerToken)
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("If-Match", "*")
-
 	logger.Info("📦 Creating or updating product",
 		"productId", config.ProductID,
 		"url", productURL,
 	)
 
-	resp, err := http.DefaultClient.Do(req)
+	resp, body, err := c.conditionalWrite(ctx, config.ETag, config.OverwriteDrift, func(string) (*http.Request, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPut, productURL, bytes.NewReader(bodyBytes))
+		if err != nil {
+			return nil, fmt.Errorf("failed to build product creation request: %w", err)
+		}
+		req.Header.Set("Authorization", "Bearer "+config.BearerToken)
+		req.Header.Set("Content-Type", "application/json")
+		return req, nil
+	}, func(ctx context.Context) (string, error) {
+		return c.getETag(ctx, productURL, config.BearerToken)
+	})
 	if err != nil {
-		return fmt.Errorf("product creation request failed: %w", err)
+		return "", fmt.Errorf("product creation request failed: %w", err)
 	}
-	defer func() {
-		if closeErr := resp.Body.Close(); closeErr != nil {
-			logger.Error(closeErr, "⚠️ Failed to close response body")
-		}
-	}()
 
-	body, _ := io.ReadAll(resp.Body)
 	if resp.StatusCode >= 300 {
 		logger.Error(fmt.Errorf("status code: %d", resp.StatusCode), "❌ Failed to create product",
 			"status", resp.Status,
-			"body", string(body),
+			"body", redact.String(string(body)),
 		)
-		return fmt.Errorf("failed to create product: %s\n%s", resp.Status, string(body))
+		return "", newResponseError("failed to create product", resp, body)
 	}
 
 	logger.Info("✅ Product created or already exists",
@@ -90,13 +93,16 @@ func UpsertProduct(ctx context.Context, config APIMProductConfig) error {
 		"status", resp.Status,
 	)
 
-	return nil
+	return normalizeETag(resp.Header.Get("ETag")), nil
 }
 
 // DeleteProduct deletes a product from Azure APIM.
 // Products are used to group APIs and require subscriptions for access.
 // This function removes the product from the APIM service.
-func DeleteProduct(ctx context.Context, config APIMProductConfig) error {
+func (c *Client) DeleteProduct(ctx context.Context, config APIMProductConfig) (err error) {
+	ctx, span := startSpan(ctx, "apim.DeleteProduct", config.ServiceName, "")
+	defer func() { endSpan(span, err) }()
+
 	// Skip if no product ID is provided.
 	if config.ProductID == "" {
 		logger.Info("ℹ️ No product ID specified; skipping product deletion")
@@ -104,11 +110,13 @@ func DeleteProduct(ctx context.Context, config APIMProductConfig) error {
 	}
 
 	productURL := fmt.Sprintf(
-		"https://management.azure.com/subscriptions/%s/resourceGroups/%s/providers/Microsoft.ApiManagement/service/%s/products/%s?api-version=2021-08-01",
+		"%s/subscriptions/%s/resourceGroups/%s/providers/Microsoft.ApiManagement/service/%s/products/%s?api-version=%s",
+		c.endpoint(),
 		config.SubscriptionID,
 		config.ResourceGroup,
 		config.ServiceName,
 		config.ProductID,
+		c.apiVersion(),
 	)
 
 	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, productURL, nil)
@@ -124,7 +132,7 @@ func DeleteProduct(ctx context.Context, config APIMProductConfig) error {
 		"url", productURL,
 	)
 
-	resp, err := http.DefaultClient.Do(req)
+	resp, err := c.doRequest(req)
 	if err != nil {
 		return fmt.Errorf("product deletion request failed: %w", err)
 	}
@@ -145,9 +153,9 @@ func DeleteProduct(ctx context.Context, config APIMProductConfig) error {
 	if resp.StatusCode >= 300 {
 		logger.Error(fmt.Errorf("status code: %d", resp.StatusCode), "❌ Failed to delete product",
 			"status", resp.Status,
-			"body", string(body),
+			"body", redact.String(string(body)),
 		)
-		return fmt.Errorf("failed to delete product: %s\n%s", resp.Status, string(body))
+		return newResponseError("failed to delete product", resp, body)
 	}
 
 	logger.Info("✅ Product deleted successfully",
@@ -161,7 +169,10 @@ func DeleteProduct(ctx context.Context, config APIMProductConfig) error {
 // AssignProductsToAPI associates an API with one or more products in Azure APIM.
 // Products are used to group APIs and require subscriptions for access.
 // This function assigns the API to all products specified in the config.
-func AssignProductsToAPI(ctx context.Context, config APIMDeploymentConfig) error {
+func (c *Client) AssignProductsToAPI(ctx context.Context, config APIMDeploymentConfig) (err error) {
+	ctx, span := startSpan(ctx, "apim.AssignProductsToAPI", config.ServiceName, config.APIID)
+	defer func() { endSpan(span, err) }()
+
 	// If no products are configured, skip the assignment.
 	if len(config.ProductIDs) == 0 {
 		logger.Info("ℹ️ No products configured for assignment; skipping")
@@ -171,12 +182,14 @@ func AssignProductsToAPI(ctx context.Context, config APIMDeploymentConfig) error
 	// Assign the API to each product in the list.
 	for _, productID := range config.ProductIDs {
 		productAssignURL := fmt.Sprintf(
-			"https://management.azure.com/subscriptions/%s/resourceGroups/%s/providers/Microsoft.ApiManagement/service/%s/products/%s/apis/%s?api-version=2021-08-01",
+			"%s/subscriptions/%s/resourceGroups/%s/providers/Microsoft.ApiManagement/service/%s/products/%s/apis/%s?api-version=%s",
+			c.endpoint(),
 			config.SubscriptionID,
 			config.ResourceGroup,
 			config.ServiceName,
 			productID,
 			config.APIID,
+			c.apiVersion(),
 		)
 
 		req, err := http.NewRequestWithContext(ctx, http.MethodPut, productAssignURL, nil)
@@ -192,7 +205,7 @@ func AssignProductsToAPI(ctx context.Context, config APIMDeploymentConfig) error
 			"url", productAssignURL,
 		)
 
-		resp, err := http.DefaultClient.Do(req)
+		resp, err := c.doRequest(req)
 		if err != nil {
 			return fmt.Errorf("product assign request failed for %s: %w", productID, err)
 		}
@@ -204,7 +217,7 @@ func AssignProductsToAPI(ctx context.Context, config APIMDeploymentConfig) error
 
 		body, _ := io.ReadAll(resp.Body)
 		if resp.StatusCode >= 300 {
-			return fmt.Errorf("assigning API to product %s failed: %s\n%s", productID, resp.Status, string(body))
+			return newResponseError(fmt.Sprintf("assigning API to product %s failed", productID), resp, body)
 		}
 
 		logger.Info("✅ API successfully assigned to product",
@@ -235,4 +248,12 @@ type APIMProductConfig struct {
 	BearerToken string
 	// Published indicates whether the product should be published and visible in the developer portal.
 	Published bool
+	// ApprovalRequired makes new subscriptions to the product wait in the "submitted" state until they are
+	// approved or rejected.
+	ApprovalRequired bool
+	// ETag is the ETag of the product recorded after the last write. When set, the upsert is conditional on it.
+	ETag string
+	// OverwriteDrift makes an upsert that finds the product changed outside the operator overwrite it instead
+	// of failing with ErrModifiedOutsideOperator.
+	OverwriteDrift bool
 }
@@ -0,0 +1,97 @@
+// Package apim provides functions for interacting with Azure API Management (APIM) REST API.
+// This file contains the readiness check that verifies Azure authentication and ARM connectivity.
+package apim
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/hedinit/azure-apim-operator/internal/identity"
+)
+
+// readinessProbePath is a cheap ARM read that only succeeds with a valid management token.
+const readinessProbePath = "/subscriptions?api-version=2020-01-01"
+
+// azureReadiness caches the result of the last Azure check so that frequent probes
+// do not request a token and call ARM every few seconds.
+type azureReadiness struct {
+	mu        sync.Mutex
+	ttl       time.Duration
+	checkedAt time.Time
+	lastErr   error
+	check     func(ctx context.Context) error
+}
+
+// NewReadinessCheck returns a readiness checker that fails when a management token cannot be
+// acquired from the Tokens of c, or with the workload identity in AZURE_CLIENT_ID and
+// AZURE_TENANT_ID when c has none, or when the Azure Resource Manager endpoint of c is
+// unreachable or rejects the token.
+// Results are cached for ttl. The returned function matches healthz.Checker.
+func NewReadinessCheck(c *Client, ttl time.Duration) func(*http.Request) error {
+	r := &azureReadiness{ttl: ttl, check: c.checkAzureConnectivity}
+	return r.Check
+}
+
+// Check runs the Azure check, or returns the cached result if it is younger than the TTL.
+func (r *azureReadiness) Check(req *http.Request) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !r.checkedAt.IsZero() && time.Since(r.checkedAt) < r.ttl {
+		return r.lastErr
+	}
+
+	r.lastErr = r.check(req.Context())
+	r.checkedAt = time.Now()
+	if r.lastErr != nil {
+		logger.Error(r.lastErr, "❌ Azure readiness check failed")
+	}
+	return r.lastErr
+}
+
+func (c *Client) checkAzureConnectivity(ctx context.Context) error {
+	clientID := os.Getenv("AZURE_CLIENT_ID")
+	tenantID := os.Getenv("AZURE_TENANT_ID")
+	if clientID == "" || tenantID == "" {
+		return fmt.Errorf("AZURE_CLIENT_ID or AZURE_TENANT_ID not set")
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	var token string
+	var err error
+	if c != nil && c.Tokens != nil {
+		token, err = c.Tokens.ManagementToken(ctx)
+	} else {
+		token, err = identity.GetManagementToken(ctx, clientID, tenantID, identity.AzurePublic)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to acquire Azure management token: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.endpoint()+readinessProbePath, nil)
+	if err != nil {
+		return fmt.Errorf("failed to build request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	resp, err := c.doRequest(req)
+	if err != nil {
+		return fmt.Errorf("azure resource manager is unreachable: %w", err)
+	}
+	defer func() {
+		if closeErr := resp.Body.Close(); closeErr != nil {
+			logger.Error(closeErr, "⚠️ Failed to close response body")
+		}
+	}()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("azure resource manager rejected the management token: %s", resp.Status)
+	}
+	return nil
+}
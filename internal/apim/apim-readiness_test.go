@@ -0,0 +1,37 @@
+package apim
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestReadinessCheckCachesResultForTTL(t *testing.T) {
+	calls := 0
+	checkErr := errors.New("token rejected")
+	r := &azureReadiness{ttl: time.Hour, check: func(context.Context) error {
+		calls++
+		return checkErr
+	}}
+	req := httptest.NewRequest("GET", "/readyz", nil)
+
+	for range 3 {
+		if err := r.Check(req); !errors.Is(err, checkErr) {
+			t.Fatalf("Check() = %v, want %v", err, checkErr)
+		}
+	}
+	if calls != 1 {
+		t.Fatalf("expected one Azure check within the TTL, got %d", calls)
+	}
+
+	r.checkedAt = time.Now().Add(-2 * time.Hour)
+	checkErr = nil
+	if err := r.Check(req); err != nil {
+		t.Fatalf("Check() after TTL = %v, want nil", err)
+	}
+	if calls != 2 {
+		t.Fatalf("expected the check to rerun after the TTL, got %d calls", calls)
+	}
+}
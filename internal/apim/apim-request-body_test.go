@@ -0,0 +1,234 @@
+package apim
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+// update rewrites the golden request bodies in testdata/requests with the bodies the functions send now.
+var update = flag.Bool("update", false, "rewrite the golden request bodies in testdata/requests")
+
+// TestRequestBodies pins the body of every request the package sends to APIM byte for byte, so that a change
+// to how a body is built, such as marshaling a struct instead of formatting a string, cannot change the JSON
+// Azure receives unnoticed. Run the test with -update after an intended change and review the diff.
+func TestRequestBodies(t *testing.T) {
+	var (
+		mu     sync.Mutex
+		bodies []string
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"error":{"code":"ResourceNotFound"}}`))
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		if !json.Valid(body) {
+			t.Errorf("%s %s sent invalid JSON: %s", r.Method, r.URL.Path, body)
+		}
+		mu.Lock()
+		bodies = append(bodies, r.Method+" "+string(body))
+		mu.Unlock()
+		w.Header().Set("ETag", `"etag"`)
+		_, _ = w.Write([]byte(`{"properties":{}}`))
+	}))
+	defer server.Close()
+	client, err := NewClient(server.URL, "", nil, nil)
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+
+	ctx := context.Background()
+	deployment := APIMDeploymentConfig{
+		SubscriptionID:       "sub",
+		ResourceGroup:        "rg",
+		ServiceName:          "apim-test",
+		APIID:                "orders",
+		RoutePrefix:          "/orders",
+		ServiceURL:           "https://orders.example.com/v1?region=eu&tier=gold",
+		BearerToken:          "token",
+		SubscriptionRequired: true,
+	}
+	revision := deployment
+	revision.Revision = "2"
+	versioned := deployment
+	versioned.VersionSetID = "orders"
+	versioned.APIVersion = "v2"
+	validate := false
+	definition := []byte(`{"openapi":"3.0.1","info":{"title":"Orders","version":"1"},"paths":{}}`)
+
+	tests := []struct {
+		name string
+		send func() error
+	}{
+		{"import", func() error {
+			_, err := client.ImportOpenAPIDefinitionToAPIM(ctx, deployment, definition)
+			return err
+		}},
+		{"import-revision", func() error {
+			_, err := client.ImportOpenAPIDefinitionToAPIM(ctx, revision, definition)
+			return err
+		}},
+		{"import-versioned", func() error {
+			_, err := client.ImportOpenAPIDefinitionToAPIM(ctx, versioned, definition)
+			return err
+		}},
+		{"patch-service-url", func() error {
+			_, err := client.AssignServiceUrlToApi(ctx, deployment)
+			return err
+		}},
+		{"patch-subscription-required", func() error {
+			_, err := client.SetSubscriptionRequired(ctx, deployment)
+			return err
+		}},
+		{"patch-description", func() error {
+			_, err := client.SetAPIDescription(ctx, deployment, "Manage **orders** & \"returns\".\n\n[Orders guide](https://docs.example.com/orders?lang=en)")
+			return err
+		}},
+		{"product", func() error {
+			_, err := client.UpsertProduct(ctx, APIMProductConfig{
+				SubscriptionID: "sub", ResourceGroup: "rg", ServiceName: "apim-test", BearerToken: "token",
+				ProductID: "starter", DisplayName: "Starter & <Trial>", Description: "Starter \"plan\"", Published: true,
+				ApprovalRequired: true,
+			})
+			return err
+		}},
+		{"product-unpublished", func() error {
+			_, err := client.UpsertProduct(ctx, APIMProductConfig{
+				SubscriptionID: "sub", ResourceGroup: "rg", ServiceName: "apim-test", BearerToken: "token",
+				ProductID: "internal", DisplayName: "Internal",
+			})
+			return err
+		}},
+		{"tag", func() error {
+			return client.UpsertTag(ctx, APIMTagConfig{
+				SubscriptionID: "sub", ResourceGroup: "rg", ServiceName: "apim-test", BearerToken: "token",
+				TagID: "internal", DisplayName: "Internal",
+			})
+		}},
+		{"api-version-set", func() error {
+			return client.UpsertAPIVersionSet(ctx, APIMAPIVersionSetConfig{
+				SubscriptionID: "sub", ResourceGroup: "rg", ServiceName: "apim-test", BearerToken: "token",
+				VersionSetID: "orders", DisplayName: "Orders & returns", VersioningScheme: "Header",
+				VersionHeaderName: "Api-Version",
+			})
+		}},
+		{"policy", func() error {
+			_, err := client.UpsertInboundPolicy(ctx, APIMInboundPolicyConfig{
+				SubscriptionID: "sub", ResourceGroup: "rg", ServiceName: "apim-test", BearerToken: "token",
+				APIID: "orders", PolicyContent: `<policies><inbound><set-header name="x-env" exists-action="override"><value>prod</value></set-header></inbound></policies>`,
+			})
+			return err
+		}},
+		{"policy-operation", func() error {
+			_, err := client.UpsertInboundPolicy(ctx, APIMInboundPolicyConfig{
+				SubscriptionID: "sub", ResourceGroup: "rg", ServiceName: "apim-test", BearerToken: "token",
+				APIID: "orders", OperationID: "getOrder", PolicyContent: "<policies><inbound><base /></inbound></policies>",
+			})
+			return err
+		}},
+		{"named-value", func() error {
+			return client.UpsertNamedValue(ctx, APIMNamedValueConfig{
+				SubscriptionID: "sub", ResourceGroup: "rg", ServiceName: "apim-test", BearerToken: "token",
+				NamedValueID: "backend-key", DisplayName: "backend-key", Secret: true, Value: "s3cr3t",
+			})
+		}},
+		{"named-value-key-vault", func() error {
+			return client.UpsertNamedValue(ctx, APIMNamedValueConfig{
+				SubscriptionID: "sub", ResourceGroup: "rg", ServiceName: "apim-test", BearerToken: "token",
+				NamedValueID: "vault-key", DisplayName: "vault-key", Secret: true,
+				KeyVaultSecretIdentifier: "https://vault.vault.azure.net/secrets/key", KeyVaultIdentityClientID: "client-id",
+			})
+		}},
+		{"backend", func() error {
+			return client.UpsertBackend(ctx, APIMBackendConfig{
+				SubscriptionID: "sub", ResourceGroup: "rg", ServiceName: "apim-test", BearerToken: "token",
+				BackendID: "orders-backend", URL: "https://orders.internal.example.com", Protocol: "http",
+				Title: "Orders", Description: "Orders & returns",
+				Credentials: &BackendCredentials{
+					Header:              map[string][]string{"x-api-key": {"{{backend-key}}"}},
+					Query:               map[string][]string{"tenant": {"contoso"}},
+					AuthorizationScheme: "Basic", AuthorizationParameter: "{{backend-basic}}",
+				},
+				ValidateCertificateName: &validate,
+			})
+		}},
+		{"backend-circuit-breaker", func() error {
+			circuitBreakerClient := &Client{BaseURL: client.BaseURL, APIVersion: "2024-05-01"}
+			return circuitBreakerClient.UpsertBackend(ctx, APIMBackendConfig{
+				SubscriptionID: "sub", ResourceGroup: "rg", ServiceName: "apim-test", BearerToken: "token",
+				BackendID: "orders-backend", URL: "https://orders.internal.example.com", Protocol: "http",
+				CircuitBreaker: &BackendCircuitBreaker{
+					Name: "breaker", FailureCount: 5, FailureInterval: time.Minute,
+					StatusCodeRanges: []StatusCodeRange{{Min: 500, Max: 599}}, ErrorReasons: []string{"Timeout"},
+					TripDuration: 90 * time.Second, AcceptRetryAfter: true,
+				},
+			})
+		}},
+		{"subscription", func() error {
+			return client.UpsertSubscription(ctx, APIMSubscriptionConfig{
+				SubscriptionID: "sub", ResourceGroup: "rg", ServiceName: "apim-test", BearerToken: "token",
+				Name: "orders-client", DisplayName: "Orders client", Scope: "/apis/orders",
+			})
+		}},
+		{"subscription-approve", func() error {
+			return client.SetSubscriptionState(ctx, APIMSubscriptionConfig{
+				SubscriptionID: "sub", ResourceGroup: "rg", ServiceName: "apim-test", BearerToken: "token",
+				Name: "portal-sub",
+			}, SubscriptionStateActive, "Approved: owner@contoso.com is in an allowed domain")
+		}},
+		{"backup", func() error {
+			_, err := client.StartBackup(ctx, APIMBackupConfig{
+				SubscriptionID: "sub", ResourceGroup: "rg", ServiceName: "apim-test", BearerToken: "token",
+				StorageAccount: "backups", Container: "apim", BackupName: "apim-test-20261017020000.apimbackup",
+				IdentityClientID: "client-id",
+			})
+			return err
+		}},
+		{"release", func() error { return client.ReleaseRevision(ctx, revision, "Release 2") }},
+		{"portal-revision", func() error {
+			return client.PublishDeveloperPortal(ctx, deployment, "20261016", "orders updated")
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mu.Lock()
+			bodies = nil
+			mu.Unlock()
+			if err := tt.send(); err != nil {
+				t.Fatalf("error = %v", err)
+			}
+
+			mu.Lock()
+			var got bytes.Buffer
+			for _, body := range bodies {
+				got.WriteString(body + "\n")
+			}
+			mu.Unlock()
+			golden := filepath.Join("testdata", "requests", tt.name+".golden")
+			if *update {
+				if err := os.WriteFile(golden, got.Bytes(), 0o644); err != nil {
+					t.Fatalf("writing %s: %v", golden, err)
+				}
+				return
+			}
+			want, err := os.ReadFile(golden)
+			if err != nil {
+				t.Fatalf("reading %s: %v (run the test with -update to record it)", golden, err)
+			}
+			if !bytes.Equal(got.Bytes(), want) {
+				t.Errorf("request bodies differ from %s:\ngot:\n%s\nwant:\n%s", golden, got.Bytes(), want)
+			}
+		})
+	}
+}
@@ -0,0 +1,54 @@
+// Package apim provides functions for interacting with Azure API Management (APIM) REST API.
+// This file tracks the Azure request IDs of APIM calls so failures can be correlated with Azure support.
+package apim
+
+import (
+	"context"
+	"sync"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+const (
+	// RequestIDHeader is the response header in which Azure Resource Manager returns the ID of a request.
+	RequestIDHeader = "x-ms-request-id"
+	// AttrAzureRequestID is the span attribute holding the Azure request ID of an HTTP call.
+	AttrAzureRequestID = attribute.Key("azure.request_id")
+)
+
+type requestIDRecorderKey struct{}
+
+// requestIDRecorder holds the request ID of the most recent Azure response.
+type requestIDRecorder struct {
+	mu sync.Mutex
+	id string
+}
+
+// WithRequestIDRecorder returns a context in which the Azure request ID of every APIM call is recorded.
+// LastRequestID then returns the ID of the most recent call made with the context.
+func WithRequestIDRecorder(ctx context.Context) context.Context {
+	return context.WithValue(ctx, requestIDRecorderKey{}, &requestIDRecorder{})
+}
+
+// LastRequestID returns the Azure request ID of the most recent APIM call made with ctx,
+// or "" if ctx has no recorder or no call returned one.
+func LastRequestID(ctx context.Context) string {
+	recorder, ok := ctx.Value(requestIDRecorderKey{}).(*requestIDRecorder)
+	if !ok {
+		return ""
+	}
+	recorder.mu.Lock()
+	defer recorder.mu.Unlock()
+	return recorder.id
+}
+
+// recordRequestID stores id in the recorder of ctx, if any.
+func recordRequestID(ctx context.Context, id string) {
+	recorder, ok := ctx.Value(requestIDRecorderKey{}).(*requestIDRecorder)
+	if !ok {
+		return
+	}
+	recorder.mu.Lock()
+	defer recorder.mu.Unlock()
+	recorder.id = id
+}
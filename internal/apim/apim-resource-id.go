@@ -0,0 +1,75 @@
+// Package apim provides functions for interacting with Azure API Management (APIM) REST API.
+// This file contains helpers for building Azure Resource Manager (ARM) resource IDs of APIM entities.
+package apim
+
+import "fmt"
+
+// ServiceResourceID returns the full ARM resource ID of an Azure APIM service instance.
+func ServiceResourceID(subscriptionID, resourceGroup, serviceName string) string {
+	return fmt.Sprintf(
+		"/subscriptions/%s/resourceGroups/%s/providers/Microsoft.ApiManagement/service/%s",
+		subscriptionID,
+		resourceGroup,
+		serviceName,
+	)
+}
+
+// APIResourceID returns the full ARM resource ID of an API in Azure APIM.
+func APIResourceID(subscriptionID, resourceGroup, serviceName, apiID string) string {
+	return fmt.Sprintf("%s/apis/%s", ServiceResourceID(subscriptionID, resourceGroup, serviceName), apiID)
+}
+
+// ProductResourceID returns the full ARM resource ID of a product in Azure APIM.
+func ProductResourceID(subscriptionID, resourceGroup, serviceName, productID string) string {
+	return fmt.Sprintf("%s/products/%s", ServiceResourceID(subscriptionID, resourceGroup, serviceName), productID)
+}
+
+// TagResourceID returns the full ARM resource ID of a tag in Azure APIM.
+func TagResourceID(subscriptionID, resourceGroup, serviceName, tagID string) string {
+	return fmt.Sprintf("%s/tags/%s", ServiceResourceID(subscriptionID, resourceGroup, serviceName), tagID)
+}
+
+// PolicyResourceID returns the full ARM resource ID of an API or operation policy in Azure APIM.
+// If operationID is empty, the ID of the API-level policy is returned.
+func PolicyResourceID(subscriptionID, resourceGroup, serviceName, apiID, operationID string) string {
+	apiResourceID := APIResourceID(subscriptionID, resourceGroup, serviceName, apiID)
+	if operationID != "" {
+		return fmt.Sprintf("%s/operations/%s/policies/policy", apiResourceID, operationID)
+	}
+	return fmt.Sprintf("%s/policies/policy", apiResourceID)
+}
+
+// SubscriptionResourceID returns the full ARM resource ID of a subscription in Azure APIM.
+func SubscriptionResourceID(subscriptionID, resourceGroup, serviceName, name string) string {
+	return fmt.Sprintf("%s/subscriptions/%s", ServiceResourceID(subscriptionID, resourceGroup, serviceName), name)
+}
+
+// NamedValueResourceID returns the full ARM resource ID of a named value in Azure APIM.
+func NamedValueResourceID(subscriptionID, resourceGroup, serviceName, namedValueID string) string {
+	return fmt.Sprintf("%s/namedValues/%s", ServiceResourceID(subscriptionID, resourceGroup, serviceName), namedValueID)
+}
+
+// OperationResourceID returns the full ARM resource ID of an operation of an API in Azure APIM.
+func OperationResourceID(subscriptionID, resourceGroup, serviceName, apiID, operationID string) string {
+	return fmt.Sprintf("%s/operations/%s", APIResourceID(subscriptionID, resourceGroup, serviceName, apiID), operationID)
+}
+
+// ProductAPIResourceID returns the full ARM resource ID of the link between a product and an API in Azure APIM.
+func ProductAPIResourceID(subscriptionID, resourceGroup, serviceName, productID, apiID string) string {
+	return fmt.Sprintf("%s/apis/%s", ProductResourceID(subscriptionID, resourceGroup, serviceName, productID), apiID)
+}
+
+// APITagResourceID returns the full ARM resource ID of the link between an API and a tag in Azure APIM.
+func APITagResourceID(subscriptionID, resourceGroup, serviceName, apiID, tagID string) string {
+	return fmt.Sprintf("%s/tags/%s", APIResourceID(subscriptionID, resourceGroup, serviceName, apiID), tagID)
+}
+
+// BackendResourceID returns the full ARM resource ID of a backend in Azure APIM.
+func BackendResourceID(subscriptionID, resourceGroup, serviceName, backendID string) string {
+	return fmt.Sprintf("%s/backends/%s", ServiceResourceID(subscriptionID, resourceGroup, serviceName), backendID)
+}
+
+// APIVersionSetResourceID returns the full ARM resource ID of an API version set in Azure APIM.
+func APIVersionSetResourceID(subscriptionID, resourceGroup, serviceName, versionSetID string) string {
+	return fmt.Sprintf("%s/apiVersionSets/%s", ServiceResourceID(subscriptionID, resourceGroup, serviceName), versionSetID)
+}
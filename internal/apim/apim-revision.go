@@ -0,0 +1,122 @@
+// Package apim provides functions for interacting with Azure API Management (APIM) REST API.
+// This file contains functions for creating and releasing API revisions.
+package apim
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/hedinit/azure-apim-operator/internal/redact"
+)
+
+// RevisionAPIID returns the ID APIM addresses a revision of an API by, "<apiID>;rev=<revision>".
+func RevisionAPIID(apiID, revision string) string {
+	return fmt.Sprintf("%s;rev=%s", apiID, revision)
+}
+
+// NextRevision returns the number after the highest existing revision of the API of config. It returns
+// an empty string when the API doesn't exist yet, since its first import creates revision 1 as the
+// current revision.
+func (c *Client) NextRevision(ctx context.Context, config APIMDeploymentConfig) (string, error) {
+	revisions, err := c.GetAPIRevisions(ctx, config)
+	var respErr *ResponseError
+	if errors.As(err, &respErr) && respErr.StatusCode == http.StatusNotFound {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	highest := highestRevision(revisions)
+	if highest == 0 {
+		return "", nil
+	}
+	return strconv.Itoa(highest + 1), nil
+}
+
+// highestRevision returns the highest revision number in revisions, or 0 when there is none.
+func highestRevision(revisions []APIRevision) int {
+	highest := 0
+	for _, revision := range revisions {
+		if n, err := strconv.Atoi(revision.Properties.ApiRevision); err == nil && n > highest {
+			highest = n
+		}
+	}
+	return highest
+}
+
+// ReleaseRevision makes config.Revision the current revision of the API by creating a release for it.
+// Requests without a revision in their path then reach the released revision.
+func (c *Client) ReleaseRevision(ctx context.Context, config APIMDeploymentConfig, notes string) (err error) {
+	ctx, span := startSpan(ctx, "apim.ReleaseRevision", config.ServiceName, config.APIID)
+	defer func() { endSpan(span, err) }()
+
+	releaseURL := fmt.Sprintf(
+		"%s/subscriptions/%s/resourceGroups/%s/providers/Microsoft.ApiManagement/service/%s/apis/%s/releases/rev-%s?api-version=%s",
+		c.endpoint(),
+		config.SubscriptionID,
+		config.ResourceGroup,
+		config.ServiceName,
+		config.APIID,
+		config.Revision,
+		c.apiVersion(),
+	)
+
+	releaseBody := map[string]interface{}{
+		"properties": map[string]interface{}{
+			"apiId": APIResourceID(config.SubscriptionID, config.ResourceGroup, config.ServiceName, RevisionAPIID(config.APIID, config.Revision)),
+			"notes": notes,
+		},
+	}
+	bodyBytes, err := json.Marshal(releaseBody)
+	if err != nil {
+		return fmt.Errorf("failed to marshal release body: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, releaseURL, bytes.NewReader(bodyBytes))
+	if err != nil {
+		return fmt.Errorf("failed to build release request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+config.BearerToken)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("If-Match", "*")
+
+	logger.Info("🚀 Releasing API revision",
+		"apiID", config.APIID,
+		"revision", config.Revision,
+		"url", releaseURL,
+	)
+
+	resp, err := c.doRequest(req)
+	if err != nil {
+		return fmt.Errorf("release request failed: %w", err)
+	}
+	defer func() {
+		if closeErr := resp.Body.Close(); closeErr != nil {
+			logger.Error(closeErr, "⚠️ Failed to close response body", "apiID", config.APIID)
+		}
+	}()
+
+	respBody, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode >= 300 {
+		logger.Error(fmt.Errorf("status code: %d", resp.StatusCode), "❌ Failed to release API revision",
+			"apiID", config.APIID,
+			"revision", config.Revision,
+			"status", resp.Status,
+			"body", redact.String(string(respBody)),
+		)
+		return newResponseError("failed to release API revision", resp, respBody)
+	}
+
+	logger.Info("✅ API revision is current",
+		"apiID", config.APIID,
+		"revision", config.Revision,
+		"status", resp.Status,
+	)
+	return nil
+}
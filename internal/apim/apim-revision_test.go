@@ -0,0 +1,34 @@
+package apim
+
+import "testing"
+
+func TestHighestRevision(t *testing.T) {
+	revision := func(number string) APIRevision {
+		var r APIRevision
+		r.Properties.ApiRevision = number
+		return r
+	}
+	tests := []struct {
+		name      string
+		revisions []APIRevision
+		want      int
+	}{
+		{name: "no revisions", want: 0},
+		{name: "single revision", revisions: []APIRevision{revision("1")}, want: 1},
+		{name: "unordered", revisions: []APIRevision{revision("2"), revision("10"), revision("3")}, want: 10},
+		{name: "malformed revisions are ignored", revisions: []APIRevision{revision("x"), revision("4")}, want: 4},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := highestRevision(tt.revisions); got != tt.want {
+				t.Errorf("highestRevision() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRevisionAPIID(t *testing.T) {
+	if got := RevisionAPIID("orders", "3"); got != "orders;rev=3" {
+		t.Errorf("RevisionAPIID() = %q, want orders;rev=3", got)
+	}
+}
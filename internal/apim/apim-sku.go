@@ -0,0 +1,75 @@
+package apim
+
+// This file describes what the pricing tiers of APIM support, so unsupported features fail before Azure
+// rejects them with an opaque error.
+
+import (
+	"encoding/xml"
+	"slices"
+	"strings"
+)
+
+// SKU names of APIM services as Azure reports them in sku.name.
+const (
+	SKUDeveloper   = "Developer"
+	SKUBasic       = "Basic"
+	SKUStandard    = "Standard"
+	SKUPremium     = "Premium"
+	SKUIsolated    = "Isolated"
+	SKUConsumption = "Consumption"
+	SKUBasicV2     = "BasicV2"
+	SKUStandardV2  = "StandardV2"
+	SKUPremiumV2   = "PremiumV2"
+)
+
+// Capabilities are the features of the operator that depend on the pricing tier of the APIM service.
+type Capabilities struct {
+	// DeveloperPortal is false for tiers without a developer portal, which can't be published.
+	DeveloperPortal bool
+	// Backup is false for tiers that can't be backed up.
+	Backup bool
+	// UnsupportedPolicies are the policy elements the tier rejects, sorted.
+	UnsupportedPolicies []string
+}
+
+// skuCapabilities lists the tiers that lack a feature the operator uses. Tiers that are not listed,
+// including ones Azure adds later, support everything.
+var skuCapabilities = map[string]Capabilities{
+	SKUConsumption: {UnsupportedPolicies: []string{"quota-by-key", "rate-limit-by-key"}},
+	SKUBasicV2:     {DeveloperPortal: true, UnsupportedPolicies: []string{"quota-by-key"}},
+	SKUStandardV2:  {DeveloperPortal: true, UnsupportedPolicies: []string{"quota-by-key"}},
+	SKUPremiumV2:   {DeveloperPortal: true, UnsupportedPolicies: []string{"quota-by-key"}},
+}
+
+// SKUCapabilities returns what the operator can use on an APIM service of the sku.
+func SKUCapabilities(sku string) Capabilities {
+	if capabilities, ok := skuCapabilities[sku]; ok {
+		capabilities.UnsupportedPolicies = slices.Clone(capabilities.UnsupportedPolicies)
+		return capabilities
+	}
+	return Capabilities{DeveloperPortal: true, Backup: true}
+}
+
+// UnsupportedPolicyElements returns the elements of the policy document content that are in unsupported,
+// sorted and without duplicates. Content that is not well-formed XML is left to APIM to reject, so only the
+// elements before the first syntax error are reported.
+func UnsupportedPolicyElements(content string, unsupported []string) []string {
+	if len(unsupported) == 0 {
+		return nil
+	}
+	var found []string
+	decoder := xml.NewDecoder(strings.NewReader(content))
+	decoder.Strict = false
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			break
+		}
+		if start, ok := token.(xml.StartElement); ok && slices.Contains(unsupported, start.Name.Local) &&
+			!slices.Contains(found, start.Name.Local) {
+			found = append(found, start.Name.Local)
+		}
+	}
+	slices.Sort(found)
+	return found
+}
@@ -0,0 +1,75 @@
+package apim
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSKUCapabilities(t *testing.T) {
+	tests := []struct {
+		sku  string
+		want Capabilities
+	}{
+		{sku: SKUDeveloper, want: Capabilities{DeveloperPortal: true, Backup: true}},
+		{sku: SKUPremium, want: Capabilities{DeveloperPortal: true, Backup: true}},
+		{sku: SKUConsumption, want: Capabilities{UnsupportedPolicies: []string{"quota-by-key", "rate-limit-by-key"}}},
+		{sku: SKUBasicV2, want: Capabilities{DeveloperPortal: true, UnsupportedPolicies: []string{"quota-by-key"}}},
+		{sku: SKUStandardV2, want: Capabilities{DeveloperPortal: true, UnsupportedPolicies: []string{"quota-by-key"}}},
+		{sku: "FutureTier", want: Capabilities{DeveloperPortal: true, Backup: true}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.sku, func(t *testing.T) {
+			if got := SKUCapabilities(tt.sku); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SKUCapabilities(%q) = %+v, want %+v", tt.sku, got, tt.want)
+			}
+		})
+	}
+
+	// The table is shared, so callers must not be able to change it.
+	SKUCapabilities(SKUConsumption).UnsupportedPolicies[0] = "changed"
+	if got := SKUCapabilities(SKUConsumption).UnsupportedPolicies[0]; got != "quota-by-key" {
+		t.Errorf("SKUCapabilities() returned the shared table, first policy is now %q", got)
+	}
+}
+
+func TestUnsupportedPolicyElements(t *testing.T) {
+	unsupported := []string{"quota-by-key", "rate-limit-by-key"}
+	tests := []struct {
+		name    string
+		content string
+		want    []string
+	}{
+		{
+			name:    "supported",
+			content: `<policies><inbound><base /><rate-limit calls="10" renewal-period="60" /></inbound></policies>`,
+		},
+		{
+			name: "unsupported",
+			content: `<policies><inbound><rate-limit-by-key calls="10" renewal-period="60" counter-key="@(context.Request.IpAddress)" />` +
+				`<quota-by-key calls="100" renewal-period="3600" counter-key="a" /><quota-by-key calls="1" renewal-period="1" counter-key="b" /></inbound></policies>`,
+			want: []string{"quota-by-key", "rate-limit-by-key"},
+		},
+		{
+			name:    "mentioned in an expression",
+			content: `<policies><inbound><set-header name="x-note"><value>quota-by-key</value></set-header></inbound></policies>`,
+		},
+		{
+			name:    "malformed after the element",
+			content: `<policies><inbound><quota-by-key calls="1" renewal-period="1" counter-key="a" /><broken`,
+			want:    []string{"quota-by-key"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := UnsupportedPolicyElements(tt.content, unsupported); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("UnsupportedPolicyElements() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if got := UnsupportedPolicyElements(`<policies><inbound><quota-by-key /></inbound></policies>`, nil); got != nil {
+		t.Errorf("UnsupportedPolicyElements() without unsupported elements = %v, want none", got)
+	}
+}
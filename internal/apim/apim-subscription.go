@@ -0,0 +1,283 @@
+// Package apim provides functions for interacting with Azure API Management (APIM) REST API.
+// This file contains functions for managing subscriptions and their keys in Azure APIM.
+package apim
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/hedinit/azure-apim-operator/internal/redact"
+)
+
+// APIMSubscriptionConfig holds the configuration for managing a subscription in Azure APIM.
+type APIMSubscriptionConfig struct {
+	// SubscriptionID is the Azure subscription ID where the APIM service is located.
+	SubscriptionID string
+	// ResourceGroup is the Azure resource group where the APIM service is located.
+	ResourceGroup string
+	// ServiceName is the name of the Azure API Management service instance.
+	ServiceName string
+	// Name is the identifier of the APIM subscription.
+	Name string
+	// DisplayName is the friendly name shown in the APIM UI.
+	DisplayName string
+	// Scope is the API or product the subscription grants access to, e.g. "/apis/orders" or "/products/starter",
+	// or "/apis" for all APIs.
+	Scope string
+	// BearerToken is the Azure AD authentication token for the APIM management API.
+	BearerToken string
+}
+
+// SubscriptionKeys are the two keys of an APIM subscription.
+type SubscriptionKeys struct {
+	PrimaryKey   string `json:"primaryKey"`
+	SecondaryKey string `json:"secondaryKey"`
+}
+
+// Subscription states an approval moves a submitted subscription to.
+const (
+	SubscriptionStateActive   = "active"
+	SubscriptionStateRejected = "rejected"
+)
+
+// PendingSubscription is a subscription to a product that waits for approval.
+type PendingSubscription struct {
+	// Name is the identifier of the subscription.
+	Name string
+	// DisplayName is the name the subscriber gave the subscription.
+	DisplayName string
+	// OwnerID is the name of the APIM user that requested the subscription, or "" if it has no owner.
+	OwnerID string
+}
+
+// UpsertSubscription creates or updates an active subscription in Azure APIM.
+// APIM generates the keys of a new subscription; existing keys are kept.
+func (c *Client) UpsertSubscription(ctx context.Context, config APIMSubscriptionConfig) (err error) {
+	ctx, span := startSpan(ctx, "apim.UpsertSubscription", config.ServiceName, "")
+	defer func() { endSpan(span, err) }()
+
+	body, err := json.Marshal(map[string]interface{}{
+		"properties": map[string]interface{}{
+			"scope":       config.Scope,
+			"displayName": config.DisplayName,
+			"state":       "active",
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal subscription body: %w", err)
+	}
+
+	logger.Info("🔑 Upserting subscription", "subscription", config.Name, "scope", config.Scope)
+	if _, err := c.sendSubscriptionRequest(ctx, config, http.MethodPut, "", body, "failed to upsert subscription"); err != nil {
+		return err
+	}
+	logger.Info("✅ Subscription upserted", "subscription", config.Name)
+	return nil
+}
+
+// ListSubscriptionKeys returns the current keys of a subscription in Azure APIM.
+func (c *Client) ListSubscriptionKeys(ctx context.Context, config APIMSubscriptionConfig) (_ SubscriptionKeys, err error) {
+	ctx, span := startSpan(ctx, "apim.ListSubscriptionKeys", config.ServiceName, "")
+	defer func() { endSpan(span, err) }()
+
+	// listSecrets is a POST, so it is suppressed in dry-run mode and returns no keys.
+	respBody, err := c.sendSubscriptionRequest(ctx, config, http.MethodPost, "/listSecrets", nil, "failed to list subscription keys")
+	if err != nil {
+		return SubscriptionKeys{}, err
+	}
+	var keys SubscriptionKeys
+	if len(respBody) > 0 {
+		if err := json.Unmarshal(respBody, &keys); err != nil {
+			return SubscriptionKeys{}, fmt.Errorf("failed to decode subscription keys: %w", err)
+		}
+	}
+	return keys, nil
+}
+
+// RegenerateSubscriptionKey replaces the primary or the secondary key of a subscription in Azure APIM.
+func (c *Client) RegenerateSubscriptionKey(ctx context.Context, config APIMSubscriptionConfig, primary bool) (err error) {
+	ctx, span := startSpan(ctx, "apim.RegenerateSubscriptionKey", config.ServiceName, "")
+	defer func() { endSpan(span, err) }()
+
+	action := "/regenerateSecondaryKey"
+	if primary {
+		action = "/regeneratePrimaryKey"
+	}
+	logger.Info("🔄 Regenerating subscription key", "subscription", config.Name, "primary", primary)
+	if _, err := c.sendSubscriptionRequest(ctx, config, http.MethodPost, action, nil, "failed to regenerate subscription key"); err != nil {
+		return err
+	}
+	logger.Info("✅ Subscription key regenerated", "subscription", config.Name, "primary", primary)
+	return nil
+}
+
+// DeleteSubscription deletes a subscription from Azure APIM. A subscription that doesn't exist is not an error.
+func (c *Client) DeleteSubscription(ctx context.Context, config APIMSubscriptionConfig) (err error) {
+	ctx, span := startSpan(ctx, "apim.DeleteSubscription", config.ServiceName, "")
+	defer func() { endSpan(span, err) }()
+
+	logger.Info("🗑️ Deleting subscription", "subscription", config.Name)
+	_, err = c.sendSubscriptionRequest(ctx, config, http.MethodDelete, "", nil, "failed to delete subscription")
+	var respErr *ResponseError
+	if errors.As(err, &respErr) && respErr.StatusCode == http.StatusNotFound {
+		logger.Info("ℹ️ Subscription not found, already deleted", "subscription", config.Name)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	logger.Info("✅ Subscription deleted", "subscription", config.Name)
+	return nil
+}
+
+// ListPendingSubscriptions returns the subscriptions to a product that are in the "submitted" state.
+func (c *Client) ListPendingSubscriptions(ctx context.Context, config ExportConfig, productID string) (_ []PendingSubscription, err error) {
+	ctx, span := startSpan(ctx, "apim.ListPendingSubscriptions", config.ServiceName, "")
+	defer func() { endSpan(span, err) }()
+
+	listURL := c.serviceURL(config, "/products/"+productID+"/subscriptions") + "&$filter=" + url.QueryEscape("state eq 'submitted'")
+	var pending []PendingSubscription
+	err = c.listPages(ctx, config.BearerToken, listURL, "failed to list product subscriptions", func(raw json.RawMessage) error {
+		var subscription struct {
+			Name       string `json:"name"`
+			Properties struct {
+				DisplayName string `json:"displayName"`
+				OwnerID     string `json:"ownerId"`
+				State       string `json:"state"`
+			} `json:"properties"`
+		}
+		if err := json.Unmarshal(raw, &subscription); err != nil {
+			return err
+		}
+		if subscription.Properties.State != "submitted" {
+			return nil
+		}
+		owner := ""
+		if i := strings.LastIndex(subscription.Properties.OwnerID, "/users/"); i >= 0 {
+			owner = subscription.Properties.OwnerID[i+len("/users/"):]
+		}
+		pending = append(pending, PendingSubscription{
+			Name:        subscription.Name,
+			DisplayName: subscription.Properties.DisplayName,
+			OwnerID:     owner,
+		})
+		return nil
+	})
+	return pending, err
+}
+
+// GetUserEmail returns the email address of the APIM user userID.
+func (c *Client) GetUserEmail(ctx context.Context, config ExportConfig, userID string) (_ string, err error) {
+	ctx, span := startSpan(ctx, "apim.GetUserEmail", config.ServiceName, "")
+	defer func() { endSpan(span, err) }()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.serviceURL(config, "/users/"+userID), nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to build request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+config.BearerToken)
+
+	resp, err := c.doRequest(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to call APIM API: %w", err)
+	}
+	defer func() {
+		if closeErr := resp.Body.Close(); closeErr != nil {
+			logger.Error(closeErr, "⚠️ Failed to close response body")
+		}
+	}()
+
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode >= 300 {
+		return "", newResponseError("failed to get user", resp, body)
+	}
+	var user struct {
+		Properties struct {
+			Email string `json:"email"`
+		} `json:"properties"`
+	}
+	if err := json.Unmarshal(body, &user); err != nil {
+		return "", fmt.Errorf("failed to parse user response: %w", err)
+	}
+	return user.Properties.Email, nil
+}
+
+// SetSubscriptionState approves a submitted subscription with SubscriptionStateActive or rejects it with
+// SubscriptionStateRejected. The comment is shown to the subscriber.
+func (c *Client) SetSubscriptionState(ctx context.Context, config APIMSubscriptionConfig, state, comment string) (err error) {
+	ctx, span := startSpan(ctx, "apim.SetSubscriptionState", config.ServiceName, "")
+	defer func() { endSpan(span, err) }()
+
+	body, err := json.Marshal(map[string]interface{}{
+		"properties": map[string]interface{}{
+			"state":        state,
+			"stateComment": comment,
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal subscription body: %w", err)
+	}
+
+	logger.Info("📝 Setting subscription state", "subscription", config.Name, "state", state)
+	if _, err := c.sendSubscriptionRequest(ctx, config, http.MethodPatch, "", body, "failed to set subscription state"); err != nil {
+		return err
+	}
+	logger.Info("✅ Subscription state set", "subscription", config.Name, "state", state)
+	return nil
+}
+
+// sendSubscriptionRequest sends a request to the subscription or one of its actions and returns the response body.
+// Response bodies may hold keys, so only error bodies are logged.
+func (c *Client) sendSubscriptionRequest(ctx context.Context, config APIMSubscriptionConfig, method, action string, body []byte, operation string) ([]byte, error) {
+	subscriptionURL := fmt.Sprintf(
+		"%s/subscriptions/%s/resourceGroups/%s/providers/Microsoft.ApiManagement/service/%s/subscriptions/%s%s?api-version=%s",
+		c.endpoint(),
+		config.SubscriptionID,
+		config.ResourceGroup,
+		config.ServiceName,
+		config.Name,
+		action,
+		c.apiVersion(),
+	)
+
+	req, err := http.NewRequestWithContext(ctx, method, subscriptionURL, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to build subscription request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+config.BearerToken)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if method == http.MethodDelete || method == http.MethodPatch {
+		req.Header.Set("If-Match", "*")
+	}
+
+	resp, err := c.doRequest(req)
+	if err != nil {
+		return nil, fmt.Errorf("subscription request failed: %w", err)
+	}
+	defer func() {
+		if closeErr := resp.Body.Close(); closeErr != nil {
+			logger.Error(closeErr, "⚠️ Failed to close response body")
+		}
+	}()
+
+	respBody, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode >= 300 {
+		logger.Error(fmt.Errorf("status code: %d", resp.StatusCode), "❌ Subscription request failed",
+			"subscription", config.Name,
+			"method", method,
+			"status", resp.Status,
+			"body", redact.String(string(respBody)),
+		)
+		return nil, newResponseError(operation, resp, respBody)
+	}
+	return respBody, nil
+}
@@ -9,18 +9,25 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+
+	"github.com/hedinit/azure-apim-operator/internal/redact"
 )
 
 // UpsertTag creates or updates a tag in Azure APIM.
 // Tags are used to categorize and organize APIs for easier management and discovery.
 // If the tag already exists, it will be updated with the new display name.
-func UpsertTag(ctx context.Context, config APIMTagConfig) error {
+func (c *Client) UpsertTag(ctx context.Context, config APIMTagConfig) (err error) {
+	ctx, span := startSpan(ctx, "apim.UpsertTag", config.ServiceName, "")
+	defer func() { endSpan(span, err) }()
+
 	tagURL := fmt.Sprintf(
-		"https://management.azure.com/subscriptions/%s/resourceGroups/%s/providers/Microsoft.ApiManagement/service/%s/tags/%s?api-version=2021-08-01",
+		"%s/subscriptions/%s/resourceGroups/%s/providers/Microsoft.ApiManagement/service/%s/tags/%s?api-version=%s",
+		c.endpoint(),
 		config.SubscriptionID,
 		config.ResourceGroup,
 		config.ServiceName,
 		config.TagID,
+		c.apiVersion(),
 	)
 
 	tagBody := map[string]interface{}{
@@ -48,7 +55,7 @@ func UpsertTag(ctx context.Context, config APIMTagConfig) error {
 		"url", tagURL,
 	)
 
-	resp, err := http.DefaultClient.Do(req)
+	resp, err := c.doRequest(req)
 	if err != nil {
 		return fmt.Errorf("tag request failed: %w", err)
 	}
@@ -62,9 +69,9 @@ func UpsertTag(ctx context.Context, config APIMTagConfig) error {
 	if resp.StatusCode >= 300 {
 		logger.Error(fmt.Errorf("status code: %d", resp.StatusCode), "❌ Failed to upsert tag",
 			"status", resp.Status,
-			"body", string(respBody),
+			"body", redact.String(string(respBody)),
 		)
-		return fmt.Errorf("failed to upsert tag: %s\n%s", resp.Status, string(respBody))
+		return newResponseError("failed to upsert tag", resp, respBody)
 	}
 
 	logger.Info("✅ Tag upserted",
@@ -78,7 +85,10 @@ func UpsertTag(ctx context.Context, config APIMTagConfig) error {
 // AssignTagsToAPI applies one or more tags to an API in Azure APIM.
 // Tags help organize and categorize APIs for better management and discovery.
 // This function assigns all tags specified in the config to the API.
-func AssignTagsToAPI(ctx context.Context, config APIMDeploymentConfig) error {
+func (c *Client) AssignTagsToAPI(ctx context.Context, config APIMDeploymentConfig) (err error) {
+	ctx, span := startSpan(ctx, "apim.AssignTagsToAPI", config.ServiceName, config.APIID)
+	defer func() { endSpan(span, err) }()
+
 	// If no tags are configured, skip the assignment.
 	if len(config.TagIDs) == 0 {
 		logger.Info("ℹ️ No tags configured for assignment; skipping")
@@ -88,12 +98,14 @@ func AssignTagsToAPI(ctx context.Context, config APIMDeploymentConfig) error {
 	// Assign each tag to the API.
 	for _, tagID := range config.TagIDs {
 		tagAssignURL := fmt.Sprintf(
-			"https://management.azure.com/subscriptions/%s/resourceGroups/%s/providers/Microsoft.ApiManagement/service/%s/apis/%s/tags/%s?api-version=2021-08-01",
+			"%s/subscriptions/%s/resourceGroups/%s/providers/Microsoft.ApiManagement/service/%s/apis/%s/tags/%s?api-version=%s",
+			c.endpoint(),
 			config.SubscriptionID,
 			config.ResourceGroup,
 			config.ServiceName,
 			config.APIID,
 			tagID,
+			c.apiVersion(),
 		)
 
 		req, err := http.NewRequestWithContext(ctx, http.MethodPut, tagAssignURL, nil)
@@ -109,7 +121,7 @@ func AssignTagsToAPI(ctx context.Context, config APIMDeploymentConfig) error {
 			"url", tagAssignURL,
 		)
 
-		resp, err := http.DefaultClient.Do(req)
+		resp, err := c.doRequest(req)
 		if err != nil {
 			return fmt.Errorf("tag assign request failed for %s: %w", tagID, err)
 		}
@@ -125,9 +137,9 @@ func AssignTagsToAPI(ctx context.Context, config APIMDeploymentConfig) error {
 				"apiID", config.APIID,
 				"tagID", tagID,
 				"status", resp.Status,
-				"body", string(body),
+				"body", redact.String(string(body)),
 			)
-			return fmt.Errorf("assigning tag to API %s failed: %s\n%s", tagID, resp.Status, string(body))
+			return newResponseError(fmt.Sprintf("assigning tag to API %s failed", tagID), resp, body)
 		}
 
 		logger.Info("✅ Tag successfully assigned to API",
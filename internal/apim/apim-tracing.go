@@ -0,0 +1,46 @@
+// Package apim provides functions for interacting with Azure API Management (APIM) REST API.
+// This file contains the OpenTelemetry instrumentation shared by the APIM calls.
+package apim
+
+import (
+	"context"
+	"errors"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/hedinit/azure-apim-operator/internal/redact"
+)
+
+const (
+	// AttrAPIID is the span attribute holding the APIM API ID.
+	AttrAPIID = attribute.Key("apim.api_id")
+	// AttrAPIMService is the span attribute holding the APIM service name.
+	AttrAPIMService = attribute.Key("apim.service")
+)
+
+// tracer creates the spans of APIM operations. It uses the global tracer provider,
+// so spans are only exported once logger.InitTracer has configured one.
+var tracer = otel.Tracer("github.com/hedinit/azure-apim-operator/internal/apim")
+
+// startSpan starts a span for an APIM operation on serviceName. apiID is omitted when empty,
+// e.g. for product and tag upserts. Callers must end the span with endSpan.
+func startSpan(ctx context.Context, name, serviceName, apiID string) (context.Context, trace.Span) {
+	attrs := []attribute.KeyValue{AttrAPIMService.String(serviceName)}
+	if apiID != "" {
+		attrs = append(attrs, AttrAPIID.String(apiID))
+	}
+	return tracer.Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// endSpan marks the span as failed with the redacted message of err, if set, and ends it.
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		message := redact.String(err.Error())
+		span.RecordError(errors.New(message))
+		span.SetStatus(codes.Error, message)
+	}
+	span.End()
+}
@@ -0,0 +1,78 @@
+package apim
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+)
+
+func TestAPIMCallsAreTraced(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+
+	var traceparent string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceparent = r.Header.Get("traceparent")
+		w.Header().Set(RequestIDHeader, "4f1c2d3e-request")
+		w.WriteHeader(http.StatusConflict)
+	}))
+	defer server.Close()
+
+	ctx, span := startSpan(WithRequestIDRecorder(context.Background()), "apim.Test", "my-apim", "payment-api")
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+	if err != nil {
+		t.Fatalf("NewRequestWithContext() error = %v", err)
+	}
+	resp, err := new(Client).doRequest(req)
+	if err != nil {
+		t.Fatalf("doRequest() error = %v", err)
+	}
+	_ = resp.Body.Close()
+	endSpan(span, nil)
+
+	if got := LastRequestID(ctx); got != "4f1c2d3e-request" {
+		t.Errorf("LastRequestID() = %q, want the x-ms-request-id of the response", got)
+	}
+
+	spans := recorder.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("got %d spans, want the HTTP span and its parent", len(spans))
+	}
+	httpSpan, parent := spans[0], spans[1]
+
+	if httpSpan.Parent().SpanID() != parent.SpanContext().SpanID() {
+		t.Errorf("HTTP span is not a child of the operation span")
+	}
+	wantTraceparent := fmt.Sprintf("00-%s-%s-01", httpSpan.SpanContext().TraceID(), httpSpan.SpanContext().SpanID())
+	if traceparent != wantTraceparent {
+		t.Errorf("traceparent header = %q, want %q", traceparent, wantTraceparent)
+	}
+	if httpSpan.Status().Code != codes.Error {
+		t.Errorf("HTTP span status = %v, want Error for a 409", httpSpan.Status().Code)
+	}
+	attrs := map[string]any{}
+	for _, kv := range append(httpSpan.Attributes(), parent.Attributes()...) {
+		attrs[string(kv.Key)] = kv.Value.AsInterface()
+	}
+	want := map[string]any{
+		string(semconv.HTTPStatusCodeKey): int64(http.StatusConflict),
+		string(AttrAPIMService):           "my-apim",
+		string(AttrAPIID):                 "payment-api",
+		string(AttrAzureRequestID):        "4f1c2d3e-request",
+	}
+	for key, value := range want {
+		if attrs[key] != value {
+			t.Errorf("attribute %s = %v, want %v", key, attrs[key], value)
+		}
+	}
+}
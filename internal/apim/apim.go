@@ -14,6 +14,8 @@ import (
 	"time"
 
 	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/hedinit/azure-apim-operator/internal/redact"
 )
 
 // logger is the logger instance for APIM operations.
@@ -21,13 +23,18 @@ var logger = ctrl.Log.WithName("apim")
 
 // GetAPI retrieves an existing API from Azure APIM to get its etag.
 // This is used to properly update existing APIs with the correct If-Match header.
-func GetAPI(ctx context.Context, config APIMDeploymentConfig) (etag string, exists bool, err error) {
+func (c *Client) GetAPI(ctx context.Context, config APIMDeploymentConfig) (etag string, exists bool, err error) {
+	ctx, span := startSpan(ctx, "apim.GetAPI", config.ServiceName, config.APIID)
+	defer func() { endSpan(span, err) }()
+
 	url := fmt.Sprintf(
-		"https://management.azure.com/subscriptions/%s/resourceGroups/%s/providers/Microsoft.ApiManagement/service/%s/apis/%s?api-version=2021-08-01",
+		"%s/subscriptions/%s/resourceGroups/%s/providers/Microsoft.ApiManagement/service/%s/apis/%s?api-version=%s",
+		c.endpoint(),
 		config.SubscriptionID,
 		config.ResourceGroup,
 		config.ServiceName,
 		config.APIID,
+		c.apiVersion(),
 	)
 
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
@@ -37,7 +44,7 @@ func GetAPI(ctx context.Context, config APIMDeploymentConfig) (etag string, exis
 
 	req.Header.Set("Authorization", "Bearer "+config.BearerToken)
 
-	resp, err := http.DefaultClient.Do(req)
+	resp, err := c.doRequest(req)
 	if err != nil {
 		return "", false, fmt.Errorf("failed to call APIM API: %w", err)
 	}
@@ -53,46 +60,91 @@ func GetAPI(ctx context.Context, config APIMDeploymentConfig) (etag string, exis
 
 	if resp.StatusCode >= 300 {
 		body, _ := io.ReadAll(resp.Body)
-		return "", false, fmt.Errorf("failed to get API: %s\n%s", resp.Status, string(body))
+		return "", false, newResponseError("failed to get API", resp, body)
 	}
 
-	// Get etag from response header
-	// Azure APIM returns etags in format: "W/\"etag-value\"" or "\"etag-value\""
-	etag = resp.Header.Get("ETag")
-	if etag != "" {
-		// Remove W/ prefix if present (weak etag)
-		etag = strings.TrimPrefix(etag, "W/")
-		// Remove quotes if present
-		etag = strings.Trim(etag, "\"")
-		// Remove any remaining whitespace
-		etag = strings.TrimSpace(etag)
-		// Format etag with quotes for use in If-Match header (Azure APIM requirement)
-		etag = fmt.Sprintf(`"%s"`, etag)
+	return normalizeETag(resp.Header.Get("ETag")), true, nil
+}
+
+// GetAPIDetails retrieves the current settings of an existing API from Azure APIM.
+// It returns nil details without error when the API does not exist.
+func (c *Client) GetAPIDetails(ctx context.Context, config APIMDeploymentConfig) (_ *APIDetails, err error) {
+	ctx, span := startSpan(ctx, "apim.GetAPIDetails", config.ServiceName, config.APIID)
+	defer func() { endSpan(span, err) }()
+
+	url := fmt.Sprintf(
+		"%s/subscriptions/%s/resourceGroups/%s/providers/Microsoft.ApiManagement/service/%s/apis/%s?api-version=%s",
+		c.endpoint(),
+		config.SubscriptionID,
+		config.ResourceGroup,
+		config.ServiceName,
+		config.APIID,
+		c.apiVersion(),
+	)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build request: %w", err)
 	}
 
-	return etag, true, nil
+	req.Header.Set("Authorization", "Bearer "+config.BearerToken)
+
+	resp, err := c.doRequest(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call APIM API: %w", err)
+	}
+	defer func() {
+		if closeErr := resp.Body.Close(); closeErr != nil {
+			logger.Error(closeErr, "⚠️ Failed to close response body", "apiID", config.APIID)
+		}
+	}()
+
+	if resp.StatusCode == 404 {
+		return nil, nil // API doesn't exist
+	}
+
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode >= 300 {
+		return nil, newResponseError("failed to get API", resp, body)
+	}
+
+	var details APIDetails
+	if err := json.Unmarshal(body, &details); err != nil {
+		return nil, fmt.Errorf("failed to parse API response: %w", err)
+	}
+
+	return &details, nil
 }
 
 // ImportOpenAPIDefinitionToAPIM imports an OpenAPI/Swagger definition into Azure API Management.
 // It creates or updates an API in APIM with the provided OpenAPI content, route prefix, and optional revision.
 // The function uses the Azure Management API to perform the import operation.
-// For updates, it properly handles the If-Match header to ensure existing APIs are updated correctly.
-func ImportOpenAPIDefinitionToAPIM(ctx context.Context, apimParams APIMDeploymentConfig, openApiContent []byte) error {
+// Updates are conditional on the API's ETag, so edits made in APIM since the last deployment are detected.
+// It returns the ETag of the imported API, or an empty string if APIM did not report one.
+func (c *Client) ImportOpenAPIDefinitionToAPIM(ctx context.Context, apimParams APIMDeploymentConfig, openApiContent []byte) (_ string, err error) {
+	ctx, span := startSpan(ctx, "apim.ImportOpenAPIDefinitionToAPIM", apimParams.ServiceName, apimParams.APIID)
+	defer func() { endSpan(span, err) }()
+
 	// Construct the API ID, including revision if specified.
 	// APIM uses the format "apiId;rev=revisionNumber" for revisions.
 	apiID := apimParams.APIID
 	if apimParams.Revision != "" {
-		apiID = fmt.Sprintf("%s;rev=%s", apimParams.APIID, apimParams.Revision)
+		apiID = RevisionAPIID(apimParams.APIID, apimParams.Revision)
 	}
 
-	// Check if API exists and get etag for proper update handling
-	// For updates, we use the actual etag; for creates, we use "*"
-	var etag string
-	if apimParams.Revision == "" {
-		existingEtag, exists, err := GetAPI(ctx, apimParams)
+	// Pick the If-Match value: revisions are always new, so they use "*". Updates use the ETag recorded
+	// after the last deployment, or read the API to learn its ETag when none was recorded.
+	etag := ifMatchAny
+	switch {
+	case apimParams.Revision != "":
+		logger.Info("📝 Creating new revision", "apiID", apimParams.APIID, "revision", apimParams.Revision)
+	case apimParams.ETag != "":
+		etag = apimParams.ETag
+		logger.Info("🔍 Updating API with recorded etag", "apiID", apimParams.APIID, "etag", etag)
+	default:
+		existingEtag, exists, err := c.GetAPI(ctx, apimParams)
 		if err != nil {
 			logger.Error(err, "⚠️ Failed to check if API exists, will use If-Match: *", "apiID", apimParams.APIID)
-			etag = "*"
 		} else if exists {
 			if existingEtag != "" {
 				// Use the actual etag for conditional update
@@ -100,83 +152,101 @@ func ImportOpenAPIDefinitionToAPIM(ctx context.Context, apimParams APIMDeploymen
 				logger.Info("🔍 Found existing API, will update with etag", "apiID", apimParams.APIID, "etag", etag)
 			} else {
 				// Fallback to unconditional update if no etag
-				etag = "*"
 				logger.Info("🔍 Found existing API but no etag, using If-Match: *", "apiID", apimParams.APIID)
 			}
 		} else {
-			// API doesn't exist, use "*" for create
-			etag = "*"
 			logger.Info("🆕 API does not exist, will create", "apiID", apimParams.APIID)
 		}
-	} else {
-		// Revisions are always new, use "*"
-		etag = "*"
-		logger.Info("📝 Creating new revision", "apiID", apimParams.APIID, "revision", apimParams.Revision)
 	}
 
 	// Build the Azure Management API URL for importing the API.
 	importURL := fmt.Sprintf(
-		"https://management.azure.com/subscriptions/%s/resourceGroups/%s/providers/Microsoft.ApiManagement/service/%s/apis/%s?api-version=2021-08-01",
+		"%s/subscriptions/%s/resourceGroups/%s/providers/Microsoft.ApiManagement/service/%s/apis/%s?api-version=%s",
+		c.endpoint(),
 		apimParams.SubscriptionID,
 		apimParams.ResourceGroup,
 		apimParams.ServiceName,
 		apiID,
+		c.apiVersion(),
 	)
-	req, err := http.NewRequestWithContext(ctx, http.MethodPut, importURL, bytes.NewReader(openApiContent))
-	if err != nil {
-		logger.Error(err, "❌ Failed to build APIM request", "apiID", apimParams.APIID)
-		return fmt.Errorf("failed to build request: %w", err)
-	}
 
-	req.Header.Set("Content-Type", "application/vnd.oai.openapi+json")
-	req.Header.Set("Authorization", "Bearer "+apimParams.BearerToken)
-	// Set If-Match header for conditional updates (etag) or unconditional updates (*)
-	// GetAPI already formats the etag with quotes, so we can use it directly
-	req.Header.Set("If-Match", etag)
+	// Copying and redacting a large definition for the log costs more memory than the import itself,
+	// so the content is only logged at debug level.
+	if debug := logger.V(1); debug.Enabled() {
+		debug.Info("📄 Swagger content", "apiID", apimParams.APIID, "content", redact.String(strings.TrimSpace(string(openApiContent))))
+	}
 
-	q := req.URL.Query()
-	q.Set("import", "true")
-	q.Set("path", apimParams.RoutePrefix)
-	if apimParams.Revision != "" {
-		q.Set("createRevision", "true")
+	// The raw import cannot say which version of a version set the API is, so versioned APIs are imported
+	// with the definition wrapped in a JSON body that carries the version.
+	content, contentType := openApiContent, "application/vnd.oai.openapi+json"
+	if apimParams.VersionSetID != "" {
+		content, err = versionedImportBody(apimParams, openApiContent)
+		if err != nil {
+			return "", err
+		}
+		contentType = "application/json"
 	}
-	req.URL.RawQuery = q.Encode()
 
-	logger.Info("📤 Sending request to APIM",
-		"method", req.Method,
-		"url", req.URL.String(),
-		"apiID", apimParams.APIID,
-		"routePrefix", apimParams.RoutePrefix,
-		"ifMatch", etag,
-		"contentType", "application/vnd.oai.openapi+json",
-	)
+	payload, contentEncoding := compressBody(content)
+	write := func(ifMatch string) (*http.Request, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPut, importURL, bytes.NewReader(payload))
+		if err != nil {
+			return nil, fmt.Errorf("failed to build request: %w", err)
+		}
 
-	logger.Info("📄 Swagger content", "apiID", apimParams.APIID, "content", strings.TrimSpace(string(openApiContent)))
+		req.Header.Set("Content-Type", contentType)
+		if contentEncoding != "" {
+			req.Header.Set("Content-Encoding", contentEncoding)
+		}
+		req.Header.Set("Authorization", "Bearer "+apimParams.BearerToken)
 
-	resp, err := http.DefaultClient.Do(req)
+		q := req.URL.Query()
+		q.Set("import", "true")
+		q.Set("path", apimParams.RoutePrefix)
+		if apimParams.Revision != "" {
+			q.Set("createRevision", "true")
+		}
+		req.URL.RawQuery = q.Encode()
+
+		logger.Info("📤 Sending request to APIM",
+			"method", req.Method,
+			"url", req.URL.String(),
+			"apiID", apimParams.APIID,
+			"routePrefix", apimParams.RoutePrefix,
+			"ifMatch", ifMatch,
+			"contentType", contentType,
+			"contentEncoding", contentEncoding,
+			"bytes", len(payload),
+		)
+		return req, nil
+	}
+	currentETag := func(ctx context.Context) (string, error) {
+		current, _, err := c.GetAPI(ctx, apimParams)
+		return current, err
+	}
+
+	resp, body, err := c.conditionalWrite(ctx, etag, apimParams.OverwriteDrift, write, currentETag)
+	if err == nil && contentEncoding != "" && compressionRejected(resp, body) {
+		rejectCompression(resp)
+		payload, contentEncoding = content, ""
+		resp, body, err = c.conditionalWrite(ctx, etag, apimParams.OverwriteDrift, write, currentETag)
+	}
 	if err != nil {
 		logger.Error(err, "❌ Failed to send request to APIM", "apiID", apimParams.APIID)
-		return fmt.Errorf("failed to call APIM API: %w", err)
+		return "", fmt.Errorf("failed to call APIM API: %w", err)
 	}
-	defer func() {
-		if closeErr := resp.Body.Close(); closeErr != nil {
-			logger.Error(closeErr, "⚠️ Failed to close response body", "apiID", apimParams.APIID)
-		}
-	}()
-
-	body, _ := io.ReadAll(resp.Body)
 
 	if resp.StatusCode >= 300 {
-		logger.Error(fmt.Errorf("status code: %d", resp.StatusCode), "❌ APIM API returned error", "apiID", apimParams.APIID, "status", resp.Status, "body", string(body))
-		return fmt.Errorf("APIM API failed: %s\n%s", resp.Status, string(body))
+		logger.Error(fmt.Errorf("status code: %d", resp.StatusCode), "❌ APIM API returned error", "apiID", apimParams.APIID, "status", resp.Status, "body", redact.String(string(body)))
+		return "", newResponseError("APIM API failed", resp, body)
 	}
 
 	// Azure APIM may return 202 (Accepted) for asynchronous import operations.
 	// Poll completion explicitly so we don't report success while the import later fails.
 	if resp.StatusCode == http.StatusAccepted {
-		if err := waitForAsyncImportCompletion(ctx, apimParams.BearerToken, apimParams.APIID, resp); err != nil {
+		if err := c.waitForAsyncImportCompletion(ctx, apimParams.BearerToken, apimParams.APIID, resp); err != nil {
 			logger.Error(err, "❌ APIM async import did not complete successfully", "apiID", apimParams.APIID)
-			return err
+			return "", err
 		}
 	}
 
@@ -186,12 +256,31 @@ func ImportOpenAPIDefinitionToAPIM(ctx context.Context, apimParams APIMDeploymen
 		"statusCode", resp.StatusCode,
 	)
 
-	return nil
+	return normalizeETag(resp.Header.Get("ETag")), nil
+}
+
+// versionedImportBody returns the import body of an API that is version APIVersion of the version set
+// VersionSetID. APIM creates the API in the version set, so it does not conflict with the other versions
+// sharing its path.
+func versionedImportBody(apimParams APIMDeploymentConfig, openApiContent []byte) ([]byte, error) {
+	body, err := json.Marshal(map[string]interface{}{
+		"properties": map[string]string{
+			"format":          "openapi+json",
+			"value":           string(openApiContent),
+			"path":            strings.TrimPrefix(apimParams.RoutePrefix, "/"),
+			"apiVersion":      apimParams.APIVersion,
+			"apiVersionSetId": APIVersionSetResourceID(apimParams.SubscriptionID, apimParams.ResourceGroup, apimParams.ServiceName, apimParams.VersionSetID),
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal versioned import body: %w", err)
+	}
+	return body, nil
 }
 
 // waitForAsyncImportCompletion polls Azure APIM long-running operation URLs until completion.
 // APIM may return either Azure-AsyncOperation or Location headers on 202 responses.
-func waitForAsyncImportCompletion(ctx context.Context, bearerToken string, apiID string, initialResp *http.Response) error {
+func (c *Client) waitForAsyncImportCompletion(ctx context.Context, bearerToken string, apiID string, initialResp *http.Response) error {
 	pollURL := strings.TrimSpace(initialResp.Header.Get("Azure-AsyncOperation"))
 	if pollURL == "" {
 		pollURL = strings.TrimSpace(initialResp.Header.Get("Location"))
@@ -202,7 +291,7 @@ func waitForAsyncImportCompletion(ctx context.Context, bearerToken string, apiID
 	}
 
 	if strings.HasPrefix(pollURL, "/") {
-		pollURL = "https://management.azure.com" + pollURL
+		pollURL = c.endpoint() + pollURL
 	}
 
 	logger.Info("⏳ Polling APIM async import status", "apiID", apiID, "pollURL", pollURL)
@@ -224,7 +313,7 @@ func waitForAsyncImportCompletion(ctx context.Context, bearerToken string, apiID
 			}
 			req.Header.Set("Authorization", "Bearer "+bearerToken)
 
-			resp, err := http.DefaultClient.Do(req)
+			resp, err := c.doRequest(req)
 			if err != nil {
 				return fmt.Errorf("poll async operation: %w", err)
 			}
@@ -244,7 +333,7 @@ func waitForAsyncImportCompletion(ctx context.Context, bearerToken string, apiID
 			// If poll endpoint returns a terminal non-202 status and no status field,
 			// treat 2xx as success and non-2xx as failure.
 			if resp.StatusCode >= 300 && resp.StatusCode != http.StatusAccepted {
-				return fmt.Errorf("async poll failed: %s\n%s", resp.Status, string(body))
+				return newResponseError("async poll failed", resp, body)
 			}
 
 			status := extractAsyncStatus(body)
@@ -253,7 +342,7 @@ func waitForAsyncImportCompletion(ctx context.Context, bearerToken string, apiID
 				logger.Info("✅ APIM async import completed", "apiID", apiID, "pollURL", pollURL)
 				return nil
 			case "failed", "canceled", "cancelled":
-				return fmt.Errorf("async import reported status=%s body=%s", status, string(body))
+				return fmt.Errorf("async import reported status=%s body=%s", status, redact.String(string(body)))
 			case "inprogress", "running", "":
 				// If there's no status field and status code is terminal success, consider done.
 				if status == "" && resp.StatusCode != http.StatusAccepted {
@@ -287,13 +376,19 @@ func extractAsyncStatus(body []byte) string {
 
 // AssignServiceUrlToApi updates the backend service URL for an existing API in Azure APIM.
 // This is used to point an API to a different backend service without re-importing the OpenAPI definition.
-func AssignServiceUrlToApi(ctx context.Context, config APIMDeploymentConfig) error {
+// It returns the ETag of the API after the update.
+func (c *Client) AssignServiceUrlToApi(ctx context.Context, config APIMDeploymentConfig) (_ string, err error) {
+	ctx, span := startSpan(ctx, "apim.AssignServiceUrlToApi", config.ServiceName, config.APIID)
+	defer func() { endSpan(span, err) }()
+
 	patchURL := fmt.Sprintf(
-		"https://management.azure.com/subscriptions/%s/resourceGroups/%s/providers/Microsoft.ApiManagement/service/%s/apis/%s?api-version=2021-08-01",
+		"%s/subscriptions/%s/resourceGroups/%s/providers/Microsoft.ApiManagement/service/%s/apis/%s?api-version=%s",
+		c.endpoint(),
 		config.SubscriptionID,
 		config.ResourceGroup,
 		config.ServiceName,
 		config.APIID,
+		c.apiVersion(),
 	)
 
 	body := fmt.Sprintf(`{"properties":{"serviceUrl":"%s"}}`, config.ServiceURL)
@@ -308,15 +403,15 @@ func AssignServiceUrlToApi(ctx context.Context, config APIMDeploymentConfig) err
 
 	req, err := http.NewRequestWithContext(ctx, http.MethodPatch, patchURL, strings.NewReader(body))
 	if err != nil {
-		return fmt.Errorf("building PATCH request: %w", err)
+		return "", fmt.Errorf("building PATCH request: %w", err)
 	}
 
 	req.Header.Set("Authorization", "Bearer "+config.BearerToken)
 	req.Header.Set("Content-Type", "application/json")
 
-	resp, err := http.DefaultClient.Do(req)
+	resp, err := c.doRequest(req)
 	if err != nil {
-		return fmt.Errorf("patch request failed: %w", err)
+		return "", fmt.Errorf("patch request failed: %w", err)
 	}
 	defer func() {
 		if closeErr := resp.Body.Close(); closeErr != nil {
@@ -330,9 +425,9 @@ func AssignServiceUrlToApi(ctx context.Context, config APIMDeploymentConfig) err
 		logger.Error(errMsg, "❌ PATCH returned error",
 			"apiID", config.APIID,
 			"status", resp.Status,
-			"body", string(respBody),
+			"body", redact.String(string(respBody)),
 		)
-		return fmt.Errorf("serviceUrl patch failed: %s\n%s", resp.Status, string(respBody))
+		return "", newResponseError("serviceUrl patch failed", resp, respBody)
 	}
 
 	logger.Info("✅ Successfully patched serviceUrl",
@@ -341,23 +436,29 @@ func AssignServiceUrlToApi(ctx context.Context, config APIMDeploymentConfig) err
 		"serviceUrl", config.ServiceURL,
 	)
 
-	return nil
+	return normalizeETag(resp.Header.Get("ETag")), nil
 }
 
 // SetSubscriptionRequired updates the subscription requirement setting for an existing API in Azure APIM.
 // This controls whether a subscription key is required to access the API.
-func SetSubscriptionRequired(ctx context.Context, config APIMDeploymentConfig) error {
+// It returns the ETag of the API after the update.
+func (c *Client) SetSubscriptionRequired(ctx context.Context, config APIMDeploymentConfig) (_ string, err error) {
+	ctx, span := startSpan(ctx, "apim.SetSubscriptionRequired", config.ServiceName, config.APIID)
+	defer func() { endSpan(span, err) }()
+
 	logger.Info("🔍 SetSubscriptionRequired called",
 		"apiID", config.APIID,
 		"subscriptionRequired", config.SubscriptionRequired,
 	)
 
 	patchURL := fmt.Sprintf(
-		"https://management.azure.com/subscriptions/%s/resourceGroups/%s/providers/Microsoft.ApiManagement/service/%s/apis/%s?api-version=2021-08-01",
+		"%s/subscriptions/%s/resourceGroups/%s/providers/Microsoft.ApiManagement/service/%s/apis/%s?api-version=%s",
+		c.endpoint(),
 		config.SubscriptionID,
 		config.ResourceGroup,
 		config.ServiceName,
 		config.APIID,
+		c.apiVersion(),
 	)
 
 	// Build the JSON body with the subscriptionRequired property
@@ -373,15 +474,15 @@ func SetSubscriptionRequired(ctx context.Context, config APIMDeploymentConfig) e
 
 	req, err := http.NewRequestWithContext(ctx, http.MethodPatch, patchURL, strings.NewReader(body))
 	if err != nil {
-		return fmt.Errorf("building PATCH request: %w", err)
+		return "", fmt.Errorf("building PATCH request: %w", err)
 	}
 
 	req.Header.Set("Authorization", "Bearer "+config.BearerToken)
 	req.Header.Set("Content-Type", "application/json")
 
-	resp, err := http.DefaultClient.Do(req)
+	resp, err := c.doRequest(req)
 	if err != nil {
-		return fmt.Errorf("patch request failed: %w", err)
+		return "", fmt.Errorf("patch request failed: %w", err)
 	}
 	defer func() {
 		if closeErr := resp.Body.Close(); closeErr != nil {
@@ -395,9 +496,9 @@ func SetSubscriptionRequired(ctx context.Context, config APIMDeploymentConfig) e
 		logger.Error(errMsg, "❌ PATCH returned error",
 			"apiID", config.APIID,
 			"status", resp.Status,
-			"body", string(respBody),
+			"body", redact.String(string(respBody)),
 		)
-		return fmt.Errorf("subscriptionRequired patch failed: %s\n%s", resp.Status, string(respBody))
+		return "", newResponseError("subscriptionRequired patch failed", resp, respBody)
 	}
 
 	logger.Info("✅ Successfully patched subscriptionRequired",
@@ -406,18 +507,139 @@ func SetSubscriptionRequired(ctx context.Context, config APIMDeploymentConfig) e
 		"subscriptionRequired", config.SubscriptionRequired,
 	)
 
+	return normalizeETag(resp.Header.Get("ETag")), nil
+}
+
+// SetAPIDescription sets the description of the API, which the developer portal shows as Markdown. The import
+// sets it to info.description; this adds what APIM has no field for, such as a link to external docs.
+// It returns the ETag of the API after the change.
+func (c *Client) SetAPIDescription(ctx context.Context, config APIMDeploymentConfig, description string) (_ string, err error) {
+	ctx, span := startSpan(ctx, "apim.SetAPIDescription", config.ServiceName, config.APIID)
+	defer func() { endSpan(span, err) }()
+
+	patchURL := fmt.Sprintf(
+		"%s/subscriptions/%s/resourceGroups/%s/providers/Microsoft.ApiManagement/service/%s/apis/%s?api-version=%s",
+		c.endpoint(),
+		config.SubscriptionID,
+		config.ResourceGroup,
+		config.ServiceName,
+		config.APIID,
+		c.apiVersion(),
+	)
+
+	body, err := json.Marshal(map[string]interface{}{
+		"properties": map[string]string{"description": description},
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal API description: %w", err)
+	}
+
+	logger.Info("🔧 Patching APIM API description",
+		"method", http.MethodPatch,
+		"url", patchURL,
+		"apiID", config.APIID,
+	)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPatch, patchURL, bytes.NewReader(body))
+	if err != nil {
+		return "", fmt.Errorf("building PATCH request: %w", err)
+	}
+
+	req.Header.Set("Authorization", "Bearer "+config.BearerToken)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.doRequest(req)
+	if err != nil {
+		return "", fmt.Errorf("patch request failed: %w", err)
+	}
+	defer func() {
+		if closeErr := resp.Body.Close(); closeErr != nil {
+			logger.Error(closeErr, "⚠️ Failed to close response body", "apiID", config.APIID)
+		}
+	}()
+
+	respBody, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode >= 300 {
+		logger.Error(fmt.Errorf("status code: %d", resp.StatusCode), "❌ PATCH returned error",
+			"apiID", config.APIID,
+			"status", resp.Status,
+			"body", redact.String(string(respBody)),
+		)
+		return "", newResponseError("description patch failed", resp, respBody)
+	}
+
+	logger.Info("✅ Successfully patched API description", "apiID", config.APIID, "status", resp.Status)
+
+	return normalizeETag(resp.Header.Get("ETag")), nil
+}
+
+// DeleteAPI deletes an API and all of its revisions from Azure APIM. APIM removes the links of the API to
+// products and tags along with it. An API that doesn't exist is not an error.
+func (c *Client) DeleteAPI(ctx context.Context, config APIMDeploymentConfig) (err error) {
+	ctx, span := startSpan(ctx, "apim.DeleteAPI", config.ServiceName, config.APIID)
+	defer func() { endSpan(span, err) }()
+
+	deleteURL := fmt.Sprintf(
+		"%s/subscriptions/%s/resourceGroups/%s/providers/Microsoft.ApiManagement/service/%s/apis/%s?deleteRevisions=true&api-version=%s",
+		c.endpoint(),
+		config.SubscriptionID,
+		config.ResourceGroup,
+		config.ServiceName,
+		config.APIID,
+		c.apiVersion(),
+	)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, deleteURL, nil)
+	if err != nil {
+		return fmt.Errorf("failed to build API deletion request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+config.BearerToken)
+	req.Header.Set("If-Match", "*")
+
+	logger.Info("🗑️ Deleting API", "apiID", config.APIID, "url", deleteURL)
+
+	resp, err := c.doRequest(req)
+	if err != nil {
+		return fmt.Errorf("API deletion request failed: %w", err)
+	}
+	defer func() {
+		if closeErr := resp.Body.Close(); closeErr != nil {
+			logger.Error(closeErr, "⚠️ Failed to close response body", "apiID", config.APIID)
+		}
+	}()
+
+	respBody, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode == http.StatusNotFound {
+		logger.Info("ℹ️ API not found, already deleted", "apiID", config.APIID)
+		return nil
+	}
+	if resp.StatusCode >= 300 {
+		logger.Error(fmt.Errorf("status code: %d", resp.StatusCode), "❌ Failed to delete API",
+			"apiID", config.APIID,
+			"status", resp.Status,
+			"body", redact.String(string(respBody)),
+		)
+		return newResponseError("failed to delete API", resp, respBody)
+	}
+
+	logger.Info("✅ API deleted", "apiID", config.APIID, "status", resp.Status)
 	return nil
 }
 
 // GetAPIRevisions retrieves all revisions for an API from Azure APIM.
 // API revisions allow you to version APIs and test changes before making them current.
-func GetAPIRevisions(ctx context.Context, config APIMDeploymentConfig) ([]APIRevision, error) {
+func (c *Client) GetAPIRevisions(ctx context.Context, config APIMDeploymentConfig) (_ []APIRevision, err error) {
+	ctx, span := startSpan(ctx, "apim.GetAPIRevisions", config.ServiceName, config.APIID)
+	defer func() { endSpan(span, err) }()
+
 	url := fmt.Sprintf(
-		"https://management.azure.com/subscriptions/%s/resourceGroups/%s/providers/Microsoft.ApiManagement/service/%s/apis/%s/revisions?api-version=2021-08-01",
+		"%s/subscriptions/%s/resourceGroups/%s/providers/Microsoft.ApiManagement/service/%s/apis/%s/revisions?api-version=%s",
+		c.endpoint(),
 		config.SubscriptionID,
 		config.ResourceGroup,
 		config.ServiceName,
 		config.APIID,
+		c.apiVersion(),
 	)
 
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
@@ -433,7 +655,7 @@ func GetAPIRevisions(ctx context.Context, config APIMDeploymentConfig) ([]APIRev
 		"url", url,
 	)
 
-	resp, err := http.DefaultClient.Do(req)
+	resp, err := c.doRequest(req)
 	if err != nil {
 		logger.Error(err, "❌ Failed to request API revisions", "apiID", config.APIID)
 		return nil, fmt.Errorf("failed to call APIM API: %w", err)
@@ -450,9 +672,9 @@ func GetAPIRevisions(ctx context.Context, config APIMDeploymentConfig) ([]APIRev
 		logger.Error(fmt.Errorf("status code: %d", resp.StatusCode), "❌ Failed to get API revisions",
 			"apiID", config.APIID,
 			"status", resp.Status,
-			"body", string(body),
+			"body", redact.String(string(body)),
 		)
-		return nil, fmt.Errorf("failed to get API revisions: %s\n%s", resp.Status, string(body))
+		return nil, newResponseError("failed to get API revisions", resp, body)
 	}
 
 	var result APIRevisionListResponse
@@ -469,26 +691,47 @@ func GetAPIRevisions(ctx context.Context, config APIMDeploymentConfig) ([]APIRev
 	return result.Value, nil
 }
 
+// APIMServiceDetails describes an Azure APIM service instance.
+type APIMServiceDetails struct {
+	// Host is the API gateway hostname (Proxy).
+	Host string
+	// DeveloperPortalHost is the developer portal hostname.
+	DeveloperPortalHost string
+	// SKU is the pricing tier, e.g. "Developer" or "Consumption"; see SKUCapabilities.
+	SKU string
+}
+
 // GetAPIMServiceDetails retrieves hostname information for an Azure APIM service instance.
 // It returns the API gateway hostname (Proxy) and the developer portal hostname.
 // This information is used to construct full URLs for accessing APIs through APIM.
-func GetAPIMServiceDetails(ctx context.Context, config APIMDeploymentConfig) (apiHost, developerPortalHost string, err error) {
+func (c *Client) GetAPIMServiceDetails(ctx context.Context, config APIMDeploymentConfig) (apiHost, developerPortalHost string, err error) {
+	details, err := c.GetAPIMService(ctx, config)
+	return details.Host, details.DeveloperPortalHost, err
+}
+
+// GetAPIMService retrieves the hostnames and the pricing tier of an Azure APIM service instance.
+func (c *Client) GetAPIMService(ctx context.Context, config APIMDeploymentConfig) (details APIMServiceDetails, err error) {
+	ctx, span := startSpan(ctx, "apim.GetAPIMServiceDetails", config.ServiceName, "")
+	defer func() { endSpan(span, err) }()
+
 	url := fmt.Sprintf(
-		"https://management.azure.com/subscriptions/%s/resourceGroups/%s/providers/Microsoft.ApiManagement/service/%s?api-version=2021-08-01",
+		"%s/subscriptions/%s/resourceGroups/%s/providers/Microsoft.ApiManagement/service/%s?api-version=%s",
+		c.endpoint(),
 		config.SubscriptionID,
 		config.ResourceGroup,
 		config.ServiceName,
+		c.apiVersion(),
 	)
 
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 	if err != nil {
-		return "", "", fmt.Errorf("building request for APIM service details: %w", err)
+		return details, fmt.Errorf("building request for APIM service details: %w", err)
 	}
 	req.Header.Set("Authorization", "Bearer "+config.BearerToken)
 
-	resp, err := http.DefaultClient.Do(req)
+	resp, err := c.doRequest(req)
 	if err != nil {
-		return "", "", fmt.Errorf("request to get APIM service details failed: %w", err)
+		return details, fmt.Errorf("request to get APIM service details failed: %w", err)
 	}
 	defer func() {
 		if closeErr := resp.Body.Close(); closeErr != nil {
@@ -498,10 +741,13 @@ func GetAPIMServiceDetails(ctx context.Context, config APIMDeploymentConfig) (ap
 
 	body, _ := io.ReadAll(resp.Body)
 	if resp.StatusCode >= 300 {
-		return "", "", fmt.Errorf("failed to get APIM service details: %s\n%s", resp.Status, string(body))
+		return details, newResponseError("failed to get APIM service details", resp, body)
 	}
 
 	var serviceInfo struct {
+		SKU struct {
+			Name string `json:"name"`
+		} `json:"sku"`
 		Properties struct {
 			HostnameConfigurations []struct {
 				Type     string `json:"type"`
@@ -511,7 +757,7 @@ func GetAPIMServiceDetails(ctx context.Context, config APIMDeploymentConfig) (ap
 	}
 
 	if err := json.Unmarshal(body, &serviceInfo); err != nil {
-		return "", "", fmt.Errorf("failed to parse service response: %w", err)
+		return details, fmt.Errorf("failed to parse service response: %w", err)
 	}
 
 	// Extract hostnames from the service configuration.
@@ -520,14 +766,15 @@ func GetAPIMServiceDetails(ctx context.Context, config APIMDeploymentConfig) (ap
 		switch cfg.Type {
 		case "Proxy":
 			// Proxy hostname is used for API gateway access.
-			apiHost = cfg.HostName
+			details.Host = cfg.HostName
 		case "DeveloperPortal":
 			// Developer portal hostname is used for the developer portal UI.
-			developerPortalHost = cfg.HostName
+			details.DeveloperPortalHost = cfg.HostName
 		}
 	}
 
-	return apiHost, developerPortalHost, nil
+	details.SKU = serviceInfo.SKU.Name
+	return details, nil
 }
 
 // APIRevision represents a single API revision in Azure APIM.
@@ -543,6 +790,24 @@ type APIRevision struct {
 	} `json:"properties"`
 }
 
+// APIDetails represents the settings of an existing API in Azure APIM.
+type APIDetails struct {
+	ID         string `json:"id"`
+	Name       string `json:"name"`
+	Properties struct {
+		// DisplayName is the API name shown in the APIM UI.
+		DisplayName string `json:"displayName"`
+		// Path is the route path of the API relative to the gateway host.
+		Path string `json:"path"`
+		// ServiceURL is the backend service URL the API proxies to.
+		ServiceURL string `json:"serviceUrl"`
+		// SubscriptionRequired indicates whether a subscription key is required.
+		SubscriptionRequired bool `json:"subscriptionRequired"`
+		// ApiRevision is the current revision number of the API.
+		ApiRevision string `json:"apiRevision"`
+	} `json:"properties"`
+}
+
 // APIRevisionListResponse is the response structure from the Azure Management API
 // when querying for API revisions.
 type APIRevisionListResponse struct {
@@ -563,7 +828,9 @@ type APIMDeploymentConfig struct {
 	APIID string
 	// RoutePrefix is the base route path in APIM (e.g., "/myapi").
 	RoutePrefix string
-	// Product is a legacy field for a single product association (deprecated, use ProductIDs).
+	// Product is a legacy field for a single product association. It is ignored.
+	//
+	// Deprecated: use ProductIDs. Product will be removed together with the v1 API.
 	Product string
 	// ServiceURL is the backend service URL that APIM will proxy requests to.
 	ServiceURL string
@@ -578,4 +845,14 @@ type APIMDeploymentConfig struct {
 	// SubscriptionRequired controls whether a subscription key is required to access the API.
 	// Defaults to true (subscription required). If set to false, subscription is disabled.
 	SubscriptionRequired bool
+	// VersionSetID is the ID of the version set the API is imported into. Empty imports an unversioned API.
+	VersionSetID string
+	// APIVersion is the version of the API within the version set VersionSetID.
+	APIVersion string
+	// ETag is the ETag of the API recorded after the last deployment. When set, the import sends it in
+	// If-Match instead of reading the API first, so edits made in APIM in the meantime are detected.
+	ETag string
+	// OverwriteDrift makes an import that finds the API changed outside the operator overwrite it instead of
+	// failing with ErrModifiedOutsideOperator.
+	OverwriteDrift bool
 }
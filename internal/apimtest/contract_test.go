@@ -0,0 +1,281 @@
+package apimtest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/hedinit/azure-apim-operator/internal/apim"
+)
+
+// update rewrites the golden files of TestContract with the requests the client sends now.
+var update = flag.Bool("update", false, "rewrite the golden files in testdata/contract")
+
+// TestContract runs the operations the operator performs against the fake once per supported api-version. It
+// compares the requests with the golden requests recorded for the version in testdata/contract/<version>.golden
+// and checks every property sent against the properties the version defines for the resource type, listed in
+// testdata/contract/<version>.json. A field renamed or dropped by a newer api-version fails the property check.
+func TestContract(t *testing.T) {
+	for _, version := range apim.SupportedAPIVersions {
+		t.Run(version, func(t *testing.T) {
+			fake := Start(t)
+			client := fake.Client()
+			client.APIVersion = version
+			runContractWorkflow(t, client)
+			requests := fake.Requests()
+
+			for _, req := range requests {
+				if got := req.Query.Get("api-version"); got != version {
+					t.Errorf("%s %s sent api-version %q, want %q", req.Method, req.Path, got, version)
+				}
+			}
+			checkProperties(t, version, requests)
+			checkGolden(t, version, requests)
+		})
+	}
+}
+
+// runContractWorkflow performs every operation whose payload the contract covers.
+func runContractWorkflow(t *testing.T, client *apim.Client) {
+	t.Helper()
+	ctx := context.Background()
+	const token = "fake-token"
+	config := apim.APIMDeploymentConfig{
+		SubscriptionID:       "sub",
+		ResourceGroup:        "rg",
+		ServiceName:          "apim-test",
+		APIID:                "orders",
+		RoutePrefix:          "/orders",
+		ServiceURL:           "https://orders.example.com",
+		BearerToken:          token,
+		ProductIDs:           []string{"starter"},
+		TagIDs:               []string{"internal"},
+		SubscriptionRequired: true,
+	}
+	definition := []byte(`{"openapi":"3.0.1","info":{"title":"Orders","version":"1"},"paths":{}}`)
+
+	steps := []struct {
+		name string
+		run  func() error
+	}{
+		{"UpsertProduct", func() error {
+			_, err := client.UpsertProduct(ctx, apim.APIMProductConfig{
+				SubscriptionID: "sub", ResourceGroup: "rg", ServiceName: "apim-test", BearerToken: token,
+				ProductID: "starter", DisplayName: "Starter", Description: "Starter plan", Published: true,
+			})
+			return err
+		}},
+		{"UpsertTag", func() error {
+			return client.UpsertTag(ctx, apim.APIMTagConfig{
+				SubscriptionID: "sub", ResourceGroup: "rg", ServiceName: "apim-test", BearerToken: token,
+				TagID: "internal", DisplayName: "Internal",
+			})
+		}},
+		{"UpsertNamedValue", func() error {
+			return client.UpsertNamedValue(ctx, apim.APIMNamedValueConfig{
+				SubscriptionID: "sub", ResourceGroup: "rg", ServiceName: "apim-test", BearerToken: token,
+				NamedValueID: "backend-key", DisplayName: "backend-key", Secret: true, Value: "s3cr3t",
+			})
+		}},
+		{"UpsertNamedValue from Key Vault", func() error {
+			return client.UpsertNamedValue(ctx, apim.APIMNamedValueConfig{
+				SubscriptionID: "sub", ResourceGroup: "rg", ServiceName: "apim-test", BearerToken: token,
+				NamedValueID: "vault-key", DisplayName: "vault-key", Secret: true,
+				KeyVaultSecretIdentifier: "https://vault.vault.azure.net/secrets/key",
+				KeyVaultIdentityClientID: "client-id",
+			})
+		}},
+		{"UpsertBackend", func() error {
+			return client.UpsertBackend(ctx, apim.APIMBackendConfig{
+				SubscriptionID: "sub", ResourceGroup: "rg", ServiceName: "apim-test", BearerToken: token,
+				BackendID: "orders-backend", URL: "https://orders.internal.example.com", Protocol: "http",
+				Credentials: &apim.BackendCredentials{Header: map[string][]string{"x-api-key": {"{{backend-key}}"}}},
+			})
+		}},
+		{"UpsertAPIVersionSet", func() error {
+			return client.UpsertAPIVersionSet(ctx, apim.APIMAPIVersionSetConfig{
+				SubscriptionID: "sub", ResourceGroup: "rg", ServiceName: "apim-test", BearerToken: token,
+				VersionSetID: "orders", DisplayName: "Orders", VersioningScheme: "Segment",
+			})
+		}},
+		{"ImportOpenAPIDefinitionToAPIM", func() error {
+			_, err := client.ImportOpenAPIDefinitionToAPIM(ctx, config, definition)
+			return err
+		}},
+		{"AssignServiceUrlToApi", func() error {
+			_, err := client.AssignServiceUrlToApi(ctx, config)
+			return err
+		}},
+		{"SetSubscriptionRequired", func() error {
+			_, err := client.SetSubscriptionRequired(ctx, config)
+			return err
+		}},
+		{"SetAPIDescription", func() error {
+			_, err := client.SetAPIDescription(ctx, config, "Orders\n\n[Orders guide](https://docs.example.com/orders)")
+			return err
+		}},
+		{"AssignProductsToAPI", func() error { return client.AssignProductsToAPI(ctx, config) }},
+		{"AssignTagsToAPI", func() error { return client.AssignTagsToAPI(ctx, config) }},
+		{"UpsertInboundPolicy", func() error {
+			_, err := client.UpsertInboundPolicy(ctx, apim.APIMInboundPolicyConfig{
+				SubscriptionID: "sub", ResourceGroup: "rg", ServiceName: "apim-test", BearerToken: token,
+				APIID: "orders", PolicyContent: "<policies><inbound><base /></inbound></policies>",
+			})
+			return err
+		}},
+		{"UpsertSubscription", func() error {
+			return client.UpsertSubscription(ctx, apim.APIMSubscriptionConfig{
+				SubscriptionID: "sub", ResourceGroup: "rg", ServiceName: "apim-test", BearerToken: token,
+				Name: "orders-client", DisplayName: "Orders client", Scope: "/apis/orders",
+			})
+		}},
+		{"ListPendingSubscriptions", func() error {
+			_, err := client.ListPendingSubscriptions(ctx, apim.ExportConfig{
+				SubscriptionID: "sub", ResourceGroup: "rg", ServiceName: "apim-test", BearerToken: token,
+			}, "starter")
+			return err
+		}},
+		{"SetSubscriptionState", func() error {
+			return client.SetSubscriptionState(ctx, apim.APIMSubscriptionConfig{
+				SubscriptionID: "sub", ResourceGroup: "rg", ServiceName: "apim-test", BearerToken: token,
+				Name: "orders-client",
+			}, apim.SubscriptionStateRejected, "contract test")
+		}},
+		{"StartBackup", func() error {
+			_, err := client.StartBackup(ctx, apim.APIMBackupConfig{
+				SubscriptionID: "sub", ResourceGroup: "rg", ServiceName: "apim-test", BearerToken: token,
+				StorageAccount: "backups", Container: "apim", BackupName: "contract.apimbackup",
+			})
+			return err
+		}},
+		{"import revision 2", func() error {
+			revision := config
+			revision.Revision = "2"
+			_, err := client.ImportOpenAPIDefinitionToAPIM(ctx, revision, definition)
+			return err
+		}},
+		{"ReleaseRevision", func() error {
+			release := config
+			release.Revision = "2"
+			return client.ReleaseRevision(ctx, release, "contract test")
+		}},
+		{"import version v2", func() error {
+			versioned := config
+			versioned.APIID = "orders-v2"
+			versioned.VersionSetID = "orders"
+			versioned.APIVersion = "v2"
+			_, err := client.ImportOpenAPIDefinitionToAPIM(ctx, versioned, definition)
+			return err
+		}},
+		{"DeleteInboundPolicy", func() error {
+			return client.DeleteInboundPolicy(ctx, apim.APIMInboundPolicyConfig{
+				SubscriptionID: "sub", ResourceGroup: "rg", ServiceName: "apim-test", BearerToken: token,
+				APIID: "orders",
+			})
+		}},
+		{"DeleteAPI", func() error { return client.DeleteAPI(ctx, config) }},
+	}
+	for _, step := range steps {
+		if err := step.run(); err != nil {
+			t.Fatalf("%s: error = %v", step.name, err)
+		}
+	}
+}
+
+// checkProperties fails for every property of a request body that the api-version does not define for the
+// resource type the request is sent to.
+func checkProperties(t *testing.T, version string, requests []Request) {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("testdata", "contract", version+".json"))
+	if err != nil {
+		t.Fatalf("reading the properties of api-version %s: %v", version, err)
+	}
+	var defined map[string][]string
+	if err := json.Unmarshal(data, &defined); err != nil {
+		t.Fatalf("parsing the properties of api-version %s: %v", version, err)
+	}
+
+	for _, req := range requests {
+		var body struct {
+			Properties map[string]json.RawMessage `json:"properties"`
+		}
+		if len(req.Body) == 0 || json.Unmarshal(req.Body, &body) != nil || len(body.Properties) == 0 {
+			continue
+		}
+		resourceType := resourceType(req.Path)
+		properties, ok := defined[resourceType]
+		if !ok {
+			t.Errorf("%s %s: api-version %s lists no properties for resource type %q", req.Method, req.Path, version, resourceType)
+			continue
+		}
+		for name := range body.Properties {
+			if !slices.Contains(properties, name) {
+				t.Errorf("%s %s: api-version %s does not define property %q of %q", req.Method, req.Path, version, name, resourceType)
+			}
+		}
+	}
+}
+
+// resourceType returns the collections of the child resource of the APIM service at path joined by "/", e.g.
+// "apis/policies" for .../service/apim-test/apis/orders/policies/policy.
+func resourceType(path string) string {
+	segments := strings.Split(strings.TrimPrefix(path, "/"), "/")
+	var collections []string
+	for i := serviceSegments; i < len(segments); i += 2 {
+		collections = append(collections, segments[i])
+	}
+	return strings.Join(collections, "/")
+}
+
+// checkGolden compares the requests with the golden file of the api-version, or rewrites it with -update.
+func checkGolden(t *testing.T, version string, requests []Request) {
+	t.Helper()
+	var got bytes.Buffer
+	for _, req := range requests {
+		fmt.Fprintf(&got, "%s %s?%s\n", req.Method, req.Path, req.Query.Encode())
+		if len(req.Body) > 0 {
+			got.Write(canonicalBody(req.Body))
+			got.WriteByte('\n')
+		}
+		got.WriteByte('\n')
+	}
+
+	golden := filepath.Join("testdata", "contract", version+".golden")
+	if *update {
+		if err := os.WriteFile(golden, got.Bytes(), 0o644); err != nil {
+			t.Fatalf("writing %s: %v", golden, err)
+		}
+		return
+	}
+	want, err := os.ReadFile(golden)
+	if err != nil {
+		t.Fatalf("reading %s: %v (run the test with -update to record it)", golden, err)
+	}
+	if !bytes.Equal(got.Bytes(), want) {
+		t.Errorf("the requests differ from %s; run the test with -update and review the diff.\ngot:\n%s", golden, got.String())
+	}
+}
+
+// canonicalBody returns a JSON body indented and with its keys sorted, so that golden files diff cleanly.
+// Other bodies are returned as they are.
+func canonicalBody(body []byte) []byte {
+	var value interface{}
+	if err := json.Unmarshal(body, &value); err != nil {
+		return body
+	}
+	var canonical bytes.Buffer
+	encoder := json.NewEncoder(&canonical)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(value); err != nil {
+		return body
+	}
+	return bytes.TrimSuffix(canonical.Bytes(), []byte("\n"))
+}
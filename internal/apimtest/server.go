@@ -0,0 +1,569 @@
+// Package apimtest provides a fake Azure Management API for API Management, so the operator's APIM calls can
+// be exercised in tests and end-to-end runs without Azure. The fake keeps every resource it is sent in memory,
+// keyed by its ARM path, and answers like ARM does for the requests the operator makes: conditional PUTs with
+// ETags, PATCH, DELETE, collection listings, OpenAPI imports, revisions, backups and the listSecrets and
+// listValue actions. It accepts any bearer token, unless a Fault is injected to reject it or to throttle requests.
+package apimtest
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/hedinit/azure-apim-operator/internal/apim"
+)
+
+// serviceSegments is the number of path segments up to the APIM service:
+// subscriptions/{sub}/resourceGroups/{rg}/providers/Microsoft.ApiManagement/service/{name}.
+const serviceSegments = 8
+
+// Request is a request the fake received.
+type Request struct {
+	Method string
+	// Path is the ARM path of the request, without the query.
+	Path  string
+	Query url.Values
+	// Token is the bearer token the request was sent with.
+	Token string
+	// Body is the request body, decompressed.
+	Body []byte
+}
+
+// Fault makes the fake answer the requests it matches with an Azure error instead of serving them, e.g. 401
+// for an expired token or 429 with Retry-After when throttled. Empty selector fields match any request.
+type Fault struct {
+	// Method is the HTTP method of the requests to fail.
+	Method string
+	// PathSuffix is the end of the ARM path of the requests to fail, e.g. "/tags/internal".
+	PathSuffix string
+	// Token is the bearer token of the requests to fail.
+	Token string
+	// Status is the HTTP status of the error response.
+	Status int
+	// Code is the Azure error code of the error response.
+	Code string
+	// RetryAfter is sent as the Retry-After header, if set.
+	RetryAfter string
+	// Times is how many requests the fault fails. Zero fails every matching request.
+	Times int
+}
+
+func (f *Fault) matches(req Request) bool {
+	return (f.Method == "" || f.Method == req.Method) &&
+		(f.PathSuffix == "" || strings.HasSuffix(req.Path, f.PathSuffix)) &&
+		(f.Token == "" || f.Token == req.Token)
+}
+
+type resource struct {
+	path       string
+	properties map[string]interface{}
+	etag       string
+	// definition is the imported OpenAPI definition of an API.
+	definition []byte
+}
+
+// Server is a fake Azure Management API. Its zero value is not usable; create one with NewServer.
+type Server struct {
+	mu        sync.Mutex
+	resources map[string]*resource
+	requests  []Request
+	faults    []*Fault
+	version   int
+	sku       string
+	// url is where Start serves the fake.
+	url *url.URL
+}
+
+// NewServer returns an empty fake. Serve it with net/http, or use Start in tests.
+func NewServer() *Server {
+	return &Server{resources: map[string]*resource{}}
+}
+
+// TB is the part of testing.TB that Start uses. GinkgoT() implements it too.
+type TB interface {
+	Helper()
+	Fatalf(format string, args ...interface{})
+	Cleanup(func())
+}
+
+// Start serves a new fake on a local port for the duration of t. Client returns an apim.Client that sends its
+// requests to it.
+func Start(t TB) *Server {
+	t.Helper()
+	s := NewServer()
+	server := httptest.NewServer(s)
+	u, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatalf("parse fake URL: %v", err)
+	}
+	s.url = u
+	t.Cleanup(server.Close)
+	return s
+}
+
+// Client returns an apim.Client that sends its requests to the fake. It must only be called on a Server
+// returned by Start.
+func (s *Server) Client() *apim.Client {
+	return &apim.Client{BaseURL: s.url}
+}
+
+// SetSKU sets the pricing tier the fake reports for the APIM service, "Developer" by default.
+func (s *Server) SetSKU(sku string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sku = sku
+}
+
+// Put stores a resource at path as if it was created outside the operator, like a subscription requested in the
+// developer portal.
+func (s *Server) Put(path string, properties map[string]interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.resources[key(path)] = &resource{path: path, properties: properties, etag: s.nextETag()}
+}
+
+// Inject makes the fake fail the requests f matches from now on. Faults are checked in the order they were injected.
+func (s *Server) Inject(f Fault) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.faults = append(s.faults, &f)
+}
+
+// fault returns the first injected fault that matches req and counts req against it.
+func (s *Server) fault(req Request) *Fault {
+	for i, f := range s.faults {
+		if !f.matches(req) {
+			continue
+		}
+		if f.Times > 0 {
+			f.Times--
+			if f.Times == 0 {
+				s.faults = append(s.faults[:i:i], s.faults[i+1:]...)
+			}
+		}
+		return f
+	}
+	return nil
+}
+
+// Requests returns the requests received so far, in order.
+func (s *Server) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Request(nil), s.requests...)
+}
+
+// Properties returns a copy of the properties of the resource at path, and whether it exists.
+func (s *Server) Properties(path string) (map[string]interface{}, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	res, ok := s.resources[key(path)]
+	if !ok {
+		return nil, false
+	}
+	properties := make(map[string]interface{}, len(res.properties))
+	for k, v := range res.properties {
+		properties[k] = v
+	}
+	return properties, true
+}
+
+// Definition returns the OpenAPI definition last imported into the API at path, or nil.
+func (s *Server) Definition(path string) []byte {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if res, ok := s.resources[key(path)]; ok {
+		return res.definition
+	}
+	return nil
+}
+
+// ServeHTTP answers an Azure Management API request.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	path := strings.TrimSuffix(r.URL.Path, "/")
+	body, bodyErr := readBody(r)
+	req := Request{Method: r.Method, Path: path, Query: r.URL.Query(), Token: strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer "), Body: body}
+	s.requests = append(s.requests, req)
+	if f := s.fault(req); f != nil {
+		if f.RetryAfter != "" {
+			w.Header().Set("Retry-After", f.RetryAfter)
+		}
+		writeError(w, f.Status, f.Code, "injected fault")
+		return
+	}
+
+	// The readiness check lists the subscriptions the identity can see.
+	if path == "/subscriptions" && r.Method == http.MethodGet {
+		writeJSON(w, http.StatusOK, map[string]interface{}{"value": []interface{}{}})
+		return
+	}
+	segments := strings.Split(strings.TrimPrefix(path, "/"), "/")
+	if len(segments) < serviceSegments || !strings.EqualFold(segments[0], "subscriptions") ||
+		!strings.EqualFold(segments[6], "service") {
+		writeError(w, http.StatusNotFound, "InvalidResourceType", "the fake only serves API Management services")
+		return
+	}
+
+	if bodyErr != nil {
+		writeError(w, http.StatusBadRequest, "InvalidRequestContent", bodyErr.Error())
+		return
+	}
+
+	switch {
+	case len(segments) == serviceSegments && r.Method == http.MethodGet:
+		s.getService(w, path, segments[7])
+	case len(segments) == serviceSegments+1 && r.Method == http.MethodPost && strings.EqualFold(segments[serviceSegments], "backup"):
+		s.backup(w, r, path, body)
+	case r.Method == http.MethodPost:
+		s.action(w, path)
+	case len(segments)%2 == 1 && r.Method == http.MethodGet:
+		s.list(w, path, segments)
+	case r.Method == http.MethodGet || r.Method == http.MethodHead:
+		s.get(w, path)
+	case r.Method == http.MethodPut:
+		s.put(w, r, path, body)
+	case r.Method == http.MethodPatch:
+		s.patch(w, r, path, body)
+	case len(segments) == serviceSegments+2 && r.Method == http.MethodDelete && strings.EqualFold(segments[serviceSegments], "apis"):
+		s.deleteAPI(w, path, segments)
+	case r.Method == http.MethodDelete:
+		s.delete(w, path)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "MethodNotAllowed", r.Method+" is not supported")
+	}
+}
+
+// getService describes the APIM service with the default gateway and developer portal host names.
+func (s *Server) getService(w http.ResponseWriter, path, name string) {
+	sku := s.sku
+	if sku == "" {
+		sku = apim.SKUDeveloper
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"id":   path,
+		"name": name,
+		"sku":  map[string]interface{}{"name": sku, "capacity": 1},
+		"properties": map[string]interface{}{
+			"gatewayUrl": "https://" + name + ".azure-api.net",
+			"hostnameConfigurations": []interface{}{
+				map[string]interface{}{"type": "Proxy", "hostName": name + ".azure-api.net"},
+				map[string]interface{}{"type": "DeveloperPortal", "hostName": name + ".developer.azure-api.net"},
+			},
+		},
+	})
+}
+
+func (s *Server) get(w http.ResponseWriter, path string) {
+	res, ok := s.resources[key(path)]
+	if !ok {
+		writeError(w, http.StatusNotFound, "ResourceNotFound", path+" was not found")
+		return
+	}
+	w.Header().Set("ETag", quote(res.etag))
+	writeJSON(w, http.StatusOK, res.document())
+}
+
+// list returns the direct children of a collection path. The products of an API are the products it is
+// linked to, its revisions are the API and its ";rev=" siblings, and the subscriptions of a product are the
+// subscriptions scoped to it.
+func (s *Server) list(w http.ResponseWriter, path string, segments []string) {
+	var items []*resource
+	last := strings.ToLower(segments[len(segments)-1])
+	switch {
+	case len(segments) == serviceSegments+3 && last == "products":
+		// APIM keeps the links between products and APIs under the products; an API's products are read from them.
+		productsPrefix := key("/"+strings.Join(segments[:serviceSegments], "/")) + "/products/"
+		apiID := strings.ToLower(segments[serviceSegments+1])
+		for k, res := range s.resources {
+			link := strings.Split(strings.TrimPrefix(k, productsPrefix), "/")
+			if strings.HasPrefix(k, productsPrefix) && len(link) == 3 && link[1] == "apis" && link[2] == apiID {
+				productPath := res.path[:len(productsPrefix)+len(link[0])]
+				items = append(items, &resource{path: productPath, properties: map[string]interface{}{}})
+			}
+		}
+	case len(segments) == serviceSegments+3 && last == "subscriptions":
+		// The subscriptions of a product are the subscriptions of the service scoped to it.
+		subscriptionsPrefix := key("/"+strings.Join(segments[:serviceSegments], "/")) + "/subscriptions/"
+		scope := "/products/" + strings.ToLower(segments[serviceSegments+1])
+		for k, res := range s.resources {
+			subscriptionScope, _ := res.properties["scope"].(string)
+			if strings.HasPrefix(k, subscriptionsPrefix) && strings.HasSuffix(strings.ToLower(subscriptionScope), scope) {
+				items = append(items, res)
+			}
+		}
+	case len(segments) == serviceSegments+3 && last == "revisions":
+		apisPrefix := key("/"+strings.Join(segments[:serviceSegments+1], "/")) + "/"
+		apiID := strings.ToLower(segments[serviceSegments+1])
+		for k, res := range s.resources {
+			name := strings.TrimPrefix(k, apisPrefix)
+			if strings.HasPrefix(k, apisPrefix) && (name == apiID || strings.HasPrefix(name, apiID+";rev=")) {
+				items = append(items, res)
+			}
+		}
+	default:
+		prefix := key(path) + "/"
+		for k, res := range s.resources {
+			if strings.HasPrefix(k, prefix) && !strings.Contains(strings.TrimPrefix(k, prefix), "/") {
+				items = append(items, res)
+			}
+		}
+	}
+	sort.Slice(items, func(i, j int) bool { return items[i].path < items[j].path })
+	values := make([]interface{}, 0, len(items))
+	for _, res := range items {
+		values = append(values, res.document())
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"value": values})
+}
+
+// put creates or replaces a resource, honoring If-Match and If-None-Match. A PUT with import=true imports the
+// OpenAPI definition in its body into an API, or the definition in properties.value of a JSON body.
+func (s *Server) put(w http.ResponseWriter, r *http.Request, path string, body []byte) {
+	existing, exists := s.resources[key(path)]
+	if !s.preconditionsMet(w, r, existing, exists) {
+		return
+	}
+
+	res := &resource{path: path, properties: map[string]interface{}{}}
+	if r.URL.Query().Get("import") == "true" {
+		res.definition = body
+		if exists {
+			for k, v := range existing.properties {
+				res.properties[k] = v
+			}
+		}
+		// A JSON body wraps the definition together with the version of the API in a version set.
+		var versioned struct {
+			Properties map[string]interface{} `json:"properties"`
+		}
+		if r.Header.Get("Content-Type") == "application/json" {
+			if err := json.Unmarshal(body, &versioned); err != nil {
+				writeError(w, http.StatusBadRequest, "InvalidRequestContent", err.Error())
+				return
+			}
+			value, _ := versioned.Properties["value"].(string)
+			res.definition = []byte(value)
+			for _, k := range []string{"apiVersion", "apiVersionSetId"} {
+				res.properties[k] = versioned.Properties[k]
+			}
+		}
+		name := lastSegment(path)
+		revision := "1"
+		if _, rev, ok := strings.Cut(name, ";rev="); ok {
+			revision = rev
+		}
+		res.properties["displayName"] = strings.SplitN(name, ";", 2)[0]
+		res.properties["path"] = strings.TrimPrefix(r.URL.Query().Get("path"), "/")
+		res.properties["apiRevision"] = revision
+		res.properties["isCurrent"] = revision == "1" && !strings.Contains(name, ";rev=")
+		if _, ok := res.properties["subscriptionRequired"]; !ok {
+			res.properties["subscriptionRequired"] = true
+		}
+	} else if len(bytes.TrimSpace(body)) > 0 {
+		var document struct {
+			Properties map[string]interface{} `json:"properties"`
+		}
+		if err := json.Unmarshal(body, &document); err != nil {
+			writeError(w, http.StatusBadRequest, "InvalidRequestContent", err.Error())
+			return
+		}
+		if document.Properties != nil {
+			res.properties = document.Properties
+		}
+	}
+	res.etag = s.nextETag()
+	s.resources[key(path)] = res
+
+	status := http.StatusCreated
+	if exists {
+		status = http.StatusOK
+	}
+	w.Header().Set("ETag", quote(res.etag))
+	writeJSON(w, status, res.document())
+}
+
+// patch merges the properties in the body into an existing resource.
+func (s *Server) patch(w http.ResponseWriter, r *http.Request, path string, body []byte) {
+	res, exists := s.resources[key(path)]
+	if !exists {
+		writeError(w, http.StatusNotFound, "ResourceNotFound", path+" was not found")
+		return
+	}
+	if !s.preconditionsMet(w, r, res, exists) {
+		return
+	}
+	var document struct {
+		Properties map[string]interface{} `json:"properties"`
+	}
+	if err := json.Unmarshal(body, &document); err != nil {
+		writeError(w, http.StatusBadRequest, "InvalidRequestContent", err.Error())
+		return
+	}
+	for k, v := range document.Properties {
+		res.properties[k] = v
+	}
+	res.etag = s.nextETag()
+	w.Header().Set("ETag", quote(res.etag))
+	writeJSON(w, http.StatusOK, res.document())
+}
+
+// delete removes a resource and everything below it, like deleting an API removes its policies.
+func (s *Server) delete(w http.ResponseWriter, path string) {
+	k := key(path)
+	if _, exists := s.resources[k]; !exists {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	for other := range s.resources {
+		if other == k || strings.HasPrefix(other, k+"/") {
+			delete(s.resources, other)
+		}
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+// deleteAPI deletes an API like APIM does with deleteRevisions=true: together with its revisions and the
+// links of products to it, which are kept below the products.
+func (s *Server) deleteAPI(w http.ResponseWriter, path string, segments []string) {
+	servicePrefix := key("/"+strings.Join(segments[:serviceSegments], "/")) + "/"
+	apiID := strings.ToLower(segments[serviceSegments+1])
+	for k := range s.resources {
+		rest := strings.Split(strings.TrimPrefix(k, servicePrefix), "/")
+		if !strings.HasPrefix(k, servicePrefix) || len(rest) < 2 {
+			continue
+		}
+		revision := rest[0] == "apis" && strings.HasPrefix(rest[1], apiID+";rev=")
+		productLink := len(rest) == 4 && rest[0] == "products" && rest[2] == "apis" && rest[3] == apiID
+		if revision || productLink {
+			delete(s.resources, k)
+		}
+	}
+	s.delete(w, path)
+}
+
+// backup accepts a backup of the service. The backup completes at once: its operation result is a resource
+// below the service that holds the backup request, so tests can read what was backed up where.
+func (s *Server) backup(w http.ResponseWriter, r *http.Request, path string, body []byte) {
+	var properties map[string]interface{}
+	if err := json.Unmarshal(body, &properties); err != nil {
+		writeError(w, http.StatusBadRequest, "InvalidRequestContent", err.Error())
+		return
+	}
+	operationPath := strings.TrimSuffix(path, "/"+lastSegment(path)) + "/operationResults/backup-" + strconv.Itoa(s.version+1)
+	s.resources[key(operationPath)] = &resource{path: operationPath, properties: properties, etag: s.nextETag()}
+	w.Header().Set("Location", requestOrigin(r)+operationPath+"?api-version=2021-08-01")
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// requestOrigin returns the scheme and host r was sent to, so the URLs the fake answers with point back at it.
+func requestOrigin(r *http.Request) string {
+	if r.TLS != nil {
+		return "https://" + r.Host
+	}
+	return "http://" + r.Host
+}
+
+// action answers the POST actions the operator uses. listSecrets returns the keys of a subscription, listValue
+// the value of a named value; other actions, such as regenerating a key, only need the resource to exist.
+func (s *Server) action(w http.ResponseWriter, path string) {
+	target, action := path[:strings.LastIndex(path, "/")], lastSegment(path)
+	res, exists := s.resources[key(target)]
+	if !exists {
+		writeError(w, http.StatusNotFound, "ResourceNotFound", target+" was not found")
+		return
+	}
+	switch action {
+	case "listSecrets":
+		secrets := map[string]interface{}{"primaryKey": "fake-primary-key", "secondaryKey": "fake-secondary-key"}
+		for _, name := range []string{"primaryKey", "secondaryKey"} {
+			if v, ok := res.properties[name]; ok {
+				secrets[name] = v
+			}
+		}
+		writeJSON(w, http.StatusOK, secrets)
+	case "listValue":
+		writeJSON(w, http.StatusOK, map[string]interface{}{"value": res.properties["value"]})
+	default:
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// preconditionsMet checks If-Match and If-None-Match against the resource and writes 412 when they fail.
+func (s *Server) preconditionsMet(w http.ResponseWriter, r *http.Request, res *resource, exists bool) bool {
+	ifMatch := r.Header.Get("If-Match")
+	switch {
+	case ifMatch != "" && ifMatch != "*" && (!exists || strings.Trim(ifMatch, `"`) != res.etag):
+		writeError(w, http.StatusPreconditionFailed, "PreconditionFailed", "the ETag does not match")
+		return false
+	case r.Header.Get("If-None-Match") == "*" && exists:
+		writeError(w, http.StatusPreconditionFailed, "PreconditionFailed", res.path+" already exists")
+		return false
+	}
+	return true
+}
+
+func (s *Server) nextETag() string {
+	s.version++
+	return "etag-" + strconv.Itoa(s.version)
+}
+
+// document is the ARM representation of the resource.
+func (res *resource) document() map[string]interface{} {
+	return map[string]interface{}{
+		"id":         res.path,
+		"name":       lastSegment(res.path),
+		"properties": res.properties,
+	}
+}
+
+// readBody reads the request body, decompressing gzip-encoded definitions.
+func readBody(r *http.Request) ([]byte, error) {
+	if r.Body == nil {
+		return nil, nil
+	}
+	body, err := io.ReadAll(r.Body)
+	if err != nil || r.Header.Get("Content-Encoding") != "gzip" {
+		return body, err
+	}
+	reader, err := gzip.NewReader(bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("invalid gzip body: %w", err)
+	}
+	return io.ReadAll(reader)
+}
+
+// key identifies a resource: ARM paths are case-insensitive.
+func key(path string) string {
+	return strings.ToLower(strings.TrimSuffix(path, "/"))
+}
+
+func lastSegment(path string) string {
+	return path[strings.LastIndex(path, "/")+1:]
+}
+
+func quote(etag string) string {
+	return `"` + etag + `"`
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// writeError writes an ARM error response.
+func writeError(w http.ResponseWriter, status int, code, message string) {
+	writeJSON(w, status, map[string]interface{}{"error": map[string]string{"code": code, "message": message}})
+}
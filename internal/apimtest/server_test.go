@@ -0,0 +1,344 @@
+package apimtest
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hedinit/azure-apim-operator/internal/apim"
+)
+
+const apiPath = "/subscriptions/sub/resourceGroups/rg/providers/Microsoft.ApiManagement/service/apim-test/apis/orders"
+
+func TestImportAndAssign(t *testing.T) {
+	fake := Start(t)
+	client := fake.Client()
+	ctx := context.Background()
+	config := apim.APIMDeploymentConfig{
+		SubscriptionID: "sub",
+		ResourceGroup:  "rg",
+		ServiceName:    "apim-test",
+		APIID:          "orders",
+		RoutePrefix:    "/orders",
+		ServiceURL:     "https://orders.example.com",
+		BearerToken:    "fake-token",
+		ProductIDs:     []string{"starter"},
+		TagIDs:         []string{"internal"},
+	}
+	definition := []byte(`{"openapi":"3.0.1","info":{"title":"Orders","version":"1"},"paths":{}}`)
+
+	etag, err := client.ImportOpenAPIDefinitionToAPIM(ctx, config, definition)
+	if err != nil {
+		t.Fatalf("ImportOpenAPIDefinitionToAPIM() error = %v", err)
+	}
+	if etag == "" {
+		t.Error("ImportOpenAPIDefinitionToAPIM() returned no ETag")
+	}
+	if _, err := client.AssignServiceUrlToApi(ctx, config); err != nil {
+		t.Fatalf("AssignServiceUrlToApi() error = %v", err)
+	}
+	if err := client.AssignProductsToAPI(ctx, config); err != nil {
+		t.Fatalf("AssignProductsToAPI() error = %v", err)
+	}
+	if err := client.AssignTagsToAPI(ctx, config); err != nil {
+		t.Fatalf("AssignTagsToAPI() error = %v", err)
+	}
+
+	if got := string(fake.Definition(apiPath)); got != string(definition) {
+		t.Errorf("Definition() = %q, want the imported definition", got)
+	}
+	details, err := client.GetAPIDetails(ctx, config)
+	if err != nil {
+		t.Fatalf("GetAPIDetails() error = %v", err)
+	}
+	if details.Properties.Path != "orders" || details.Properties.ServiceURL != "https://orders.example.com" ||
+		details.Properties.ApiRevision != "1" {
+		t.Errorf("GetAPIDetails() = %+v, want path orders, the service URL and revision 1", details.Properties)
+	}
+
+	export := apim.ExportConfig{SubscriptionID: "sub", ResourceGroup: "rg", ServiceName: "apim-test", BearerToken: "fake-token"}
+	products, err := client.ListAPIProductIDs(ctx, export, "orders")
+	if err != nil || !reflect.DeepEqual(products, []string{"starter"}) {
+		t.Errorf("ListAPIProductIDs() = %v, %v, want [starter]", products, err)
+	}
+	tags, err := client.ListAPITagIDs(ctx, export, "orders")
+	if err != nil || !reflect.DeepEqual(tags, []string{"internal"}) {
+		t.Errorf("ListAPITagIDs() = %v, %v, want [internal]", tags, err)
+	}
+	revisions, err := client.GetAPIRevisions(ctx, config)
+	if err != nil || len(revisions) != 1 || !revisions[0].Properties.IsCurrent {
+		t.Errorf("GetAPIRevisions() = %+v, %v, want the current revision", revisions, err)
+	}
+
+	// A stale ETag is rejected with 412, and the import fails without overwriting the API.
+	config.ETag = "stale"
+	before := len(fake.Requests())
+	if _, err := client.ImportOpenAPIDefinitionToAPIM(ctx, config, definition); !apim.IsPreconditionFailed(err) {
+		t.Fatalf("import with a stale ETag: error = %v, want a precondition failure", err)
+	}
+	if methods := requestMethods(fake.Requests()[before:]); !reflect.DeepEqual(methods, []string{http.MethodPut}) {
+		t.Errorf("import with a stale ETag sent %v, want a single PUT", methods)
+	}
+
+	// With OverwriteDrift, the import reads the current ETag and retries.
+	config.OverwriteDrift = true
+	before = len(fake.Requests())
+	if _, err := client.ImportOpenAPIDefinitionToAPIM(ctx, config, definition); err != nil {
+		t.Fatalf("overwriting import with a stale ETag: error = %v", err)
+	}
+	if methods, want := requestMethods(fake.Requests()[before:]), []string{http.MethodPut, http.MethodGet, http.MethodPut}; !reflect.DeepEqual(methods, want) {
+		t.Errorf("overwriting import with a stale ETag sent %v, want %v", methods, want)
+	}
+}
+
+func requestMethods(requests []Request) []string {
+	methods := make([]string, 0, len(requests))
+	for _, req := range requests {
+		methods = append(methods, req.Method)
+	}
+	return methods
+}
+
+func TestImportVersion(t *testing.T) {
+	fake := Start(t)
+	client := fake.Client()
+	ctx := context.Background()
+	config := apim.APIMDeploymentConfig{
+		SubscriptionID: "sub",
+		ResourceGroup:  "rg",
+		ServiceName:    "apim-test",
+		APIID:          "orders",
+		RoutePrefix:    "/orders",
+		BearerToken:    "fake-token",
+		VersionSetID:   "orders",
+		APIVersion:     "v2",
+	}
+	definition := []byte(`{"openapi":"3.0.1","info":{"title":"Orders","version":"2"},"paths":{}}`)
+
+	if _, err := client.ImportOpenAPIDefinitionToAPIM(ctx, config, definition); err != nil {
+		t.Fatalf("ImportOpenAPIDefinitionToAPIM() error = %v", err)
+	}
+	if got := string(fake.Definition(apiPath)); got != string(definition) {
+		t.Errorf("Definition() = %q, want the imported definition", got)
+	}
+	properties, _ := fake.Properties(apiPath)
+	versionSetID, _ := properties["apiVersionSetId"].(string)
+	if properties["apiVersion"] != "v2" || !strings.HasSuffix(versionSetID, "/apiVersionSets/orders") {
+		t.Errorf("API properties = %v, want version v2 of the version set orders", properties)
+	}
+}
+
+func TestDeleteAPI(t *testing.T) {
+	fake := Start(t)
+	client := fake.Client()
+	ctx := context.Background()
+	config := apim.APIMDeploymentConfig{
+		SubscriptionID: "sub",
+		ResourceGroup:  "rg",
+		ServiceName:    "apim-test",
+		APIID:          "orders",
+		RoutePrefix:    "/orders",
+		BearerToken:    "fake-token",
+		ProductIDs:     []string{"starter"},
+		TagIDs:         []string{"internal"},
+	}
+	definition := []byte(`{"openapi":"3.0.1","info":{"title":"Orders","version":"1"},"paths":{}}`)
+	if _, err := client.ImportOpenAPIDefinitionToAPIM(ctx, config, definition); err != nil {
+		t.Fatalf("ImportOpenAPIDefinitionToAPIM() error = %v", err)
+	}
+	revision := config
+	revision.Revision = "2"
+	if _, err := client.ImportOpenAPIDefinitionToAPIM(ctx, revision, definition); err != nil {
+		t.Fatalf("import revision 2: error = %v", err)
+	}
+	if err := client.AssignProductsToAPI(ctx, config); err != nil {
+		t.Fatalf("AssignProductsToAPI() error = %v", err)
+	}
+	if err := client.AssignTagsToAPI(ctx, config); err != nil {
+		t.Fatalf("AssignTagsToAPI() error = %v", err)
+	}
+
+	if err := client.DeleteAPI(ctx, config); err != nil {
+		t.Fatalf("DeleteAPI() error = %v", err)
+	}
+	for _, path := range []string{apiPath, apiPath + ";rev=2", apiPath + "/tags/internal", strings.TrimSuffix(apiPath, "/apis/orders") + "/products/starter/apis/orders"} {
+		if _, ok := fake.Properties(path); ok {
+			t.Errorf("%s still exists after DeleteAPI()", path)
+		}
+	}
+	if err := client.DeleteAPI(ctx, config); err != nil {
+		t.Errorf("DeleteAPI() of a deleted API error = %v, want nil", err)
+	}
+}
+
+func TestDeleteInboundPolicy(t *testing.T) {
+	fake := Start(t)
+	client := fake.Client()
+	ctx := context.Background()
+	config := apim.APIMInboundPolicyConfig{
+		SubscriptionID: "sub",
+		ResourceGroup:  "rg",
+		ServiceName:    "apim-test",
+		APIID:          "orders",
+		OperationID:    "get-order",
+		BearerToken:    "fake-token",
+		PolicyContent:  "<policies><inbound><base /></inbound></policies>",
+	}
+	if _, err := client.UpsertInboundPolicy(ctx, config); err != nil {
+		t.Fatalf("UpsertInboundPolicy() error = %v", err)
+	}
+	apiPolicy := config
+	apiPolicy.OperationID = ""
+	if _, err := client.UpsertInboundPolicy(ctx, apiPolicy); err != nil {
+		t.Fatalf("UpsertInboundPolicy() of the API error = %v", err)
+	}
+
+	if err := client.DeleteInboundPolicy(ctx, config); err != nil {
+		t.Fatalf("DeleteInboundPolicy() error = %v", err)
+	}
+	if _, ok := fake.Properties(apiPath + "/operations/get-order/policies/policy"); ok {
+		t.Error("operation policy still exists after DeleteInboundPolicy()")
+	}
+	if _, ok := fake.Properties(apiPath + "/policies/policy"); !ok {
+		t.Error("API policy was deleted together with the operation policy")
+	}
+	if err := client.DeleteInboundPolicy(ctx, config); err != nil {
+		t.Errorf("DeleteInboundPolicy() of a deleted policy error = %v, want nil", err)
+	}
+}
+
+func TestFaults(t *testing.T) {
+	fake := Start(t)
+	client := fake.Client()
+	ctx := context.Background()
+	config := apim.APIMDeploymentConfig{
+		SubscriptionID: "sub",
+		ResourceGroup:  "rg",
+		ServiceName:    "apim-test",
+		APIID:          "orders",
+		RoutePrefix:    "/orders",
+		BearerToken:    "expired-token",
+		TagIDs:         []string{"internal"},
+	}
+	definition := []byte(`{"openapi":"3.0.1","info":{"title":"Orders","version":"1"},"paths":{}}`)
+	fake.Inject(Fault{Token: "expired-token", Status: http.StatusUnauthorized, Code: "ExpiredAuthenticationToken"})
+	fake.Inject(Fault{Method: http.MethodPut, PathSuffix: "/tags/internal", Status: http.StatusTooManyRequests, RetryAfter: "7", Times: 1})
+
+	var respErr *apim.ResponseError
+	_, err := client.ImportOpenAPIDefinitionToAPIM(ctx, config, definition)
+	if !errors.As(err, &respErr) || respErr.StatusCode != http.StatusUnauthorized || respErr.Code != "ExpiredAuthenticationToken" {
+		t.Fatalf("import with an expired token: error = %v, want 401 ExpiredAuthenticationToken", err)
+	}
+
+	config.BearerToken = "fresh-token"
+	if _, err := client.ImportOpenAPIDefinitionToAPIM(ctx, config, definition); err != nil {
+		t.Fatalf("import with a fresh token: error = %v", err)
+	}
+	err = client.AssignTagsToAPI(ctx, config)
+	if !errors.As(err, &respErr) || respErr.StatusCode != http.StatusTooManyRequests || respErr.RetryAfter != 7*time.Second {
+		t.Fatalf("throttled tag assignment: error = %v, want 429 with Retry-After 7s", err)
+	}
+	if err := client.AssignTagsToAPI(ctx, config); err != nil {
+		t.Fatalf("tag assignment after the throttling: error = %v", err)
+	}
+	if _, ok := fake.Properties(apiPath + "/tags/internal"); !ok {
+		t.Error("the API is not tagged after the retry")
+	}
+	if last := fake.Requests()[len(fake.Requests())-1]; last.Token != "fresh-token" {
+		t.Errorf("last request token = %q, want fresh-token", last.Token)
+	}
+}
+
+func TestServiceSKU(t *testing.T) {
+	fake := Start(t)
+	client := fake.Client()
+	ctx := context.Background()
+	config := apim.APIMDeploymentConfig{SubscriptionID: "sub", ResourceGroup: "rg", ServiceName: "apim-test", BearerToken: "fake-token"}
+
+	details, err := client.GetAPIMService(ctx, config)
+	if err != nil {
+		t.Fatalf("GetAPIMService() error = %v", err)
+	}
+	want := apim.APIMServiceDetails{Host: "apim-test.azure-api.net", DeveloperPortalHost: "apim-test.developer.azure-api.net", SKU: apim.SKUDeveloper}
+	if details != want {
+		t.Errorf("GetAPIMService() = %+v, want %+v", details, want)
+	}
+
+	fake.SetSKU(apim.SKUConsumption)
+	if details, err := client.GetAPIMService(ctx, config); err != nil || details.SKU != apim.SKUConsumption {
+		t.Errorf("GetAPIMService() = %+v, %v, want the Consumption SKU", details, err)
+	}
+}
+
+func TestSubscriptionApproval(t *testing.T) {
+	fake := Start(t)
+	client := fake.Client()
+	ctx := context.Background()
+	const servicePath = "/subscriptions/sub/resourceGroups/rg/providers/Microsoft.ApiManagement/service/apim-test"
+	fake.Put(servicePath+"/users/alice", map[string]interface{}{"email": "alice@contoso.com"})
+	fake.Put(servicePath+"/subscriptions/alice-gold", map[string]interface{}{
+		"displayName": "Alice", "scope": servicePath + "/products/gold", "state": "submitted", "ownerId": servicePath + "/users/alice",
+	})
+	fake.Put(servicePath+"/subscriptions/bob-gold", map[string]interface{}{
+		"displayName": "Bob", "scope": servicePath + "/products/gold", "state": "active", "ownerId": servicePath + "/users/bob",
+	})
+	fake.Put(servicePath+"/subscriptions/carol-starter", map[string]interface{}{
+		"displayName": "Carol", "scope": servicePath + "/products/starter", "state": "submitted",
+	})
+
+	config := apim.ExportConfig{SubscriptionID: "sub", ResourceGroup: "rg", ServiceName: "apim-test", BearerToken: "fake-token"}
+	pending, err := client.ListPendingSubscriptions(ctx, config, "gold")
+	if err != nil {
+		t.Fatalf("ListPendingSubscriptions() error = %v", err)
+	}
+	want := []apim.PendingSubscription{{Name: "alice-gold", DisplayName: "Alice", OwnerID: "alice"}}
+	if !reflect.DeepEqual(pending, want) {
+		t.Errorf("ListPendingSubscriptions() = %+v, want %+v", pending, want)
+	}
+	if email, err := client.GetUserEmail(ctx, config, "alice"); err != nil || email != "alice@contoso.com" {
+		t.Errorf("GetUserEmail() = %q, %v, want alice@contoso.com", email, err)
+	}
+
+	err = client.SetSubscriptionState(ctx, apim.APIMSubscriptionConfig{
+		SubscriptionID: "sub", ResourceGroup: "rg", ServiceName: "apim-test", BearerToken: "fake-token", Name: "alice-gold",
+	}, apim.SubscriptionStateActive, "approved")
+	if err != nil {
+		t.Fatalf("SetSubscriptionState() error = %v", err)
+	}
+	if properties, _ := fake.Properties(servicePath + "/subscriptions/alice-gold"); properties["state"] != "active" {
+		t.Errorf("state = %v, want active", properties["state"])
+	}
+	if pending, err := client.ListPendingSubscriptions(ctx, config, "gold"); err != nil || len(pending) != 0 {
+		t.Errorf("ListPendingSubscriptions() = %+v, %v, want none after the approval", pending, err)
+	}
+}
+
+func TestBackup(t *testing.T) {
+	fake := Start(t)
+	client := fake.Client()
+	ctx := context.Background()
+	operationURL, err := client.StartBackup(ctx, apim.APIMBackupConfig{
+		SubscriptionID: "sub", ResourceGroup: "rg", ServiceName: "apim-test", BearerToken: "fake-token",
+		StorageAccount: "backups", Container: "apim", BackupName: "nightly.apimbackup",
+	})
+	if err != nil {
+		t.Fatalf("StartBackup() error = %v", err)
+	}
+	if !strings.HasPrefix(operationURL, client.BaseURL.String()+"/subscriptions/sub/") {
+		t.Fatalf("StartBackup() = %q, want an operation URL below the subscription", operationURL)
+	}
+	if state, err := client.GetBackupState(ctx, "fake-token", operationURL); err != nil || state != apim.BackupSucceeded {
+		t.Errorf("GetBackupState() = %q, %v, want Succeeded", state, err)
+	}
+
+	u, _ := url.Parse(operationURL)
+	properties, ok := fake.Properties(u.Path)
+	if !ok || properties["backupName"] != "nightly.apimbackup" || properties["accessType"] != "SystemAssignedManagedIdentity" {
+		t.Errorf("backup = %v, want nightly.apimbackup with the system-assigned identity", properties)
+	}
+}
@@ -0,0 +1,224 @@
+// Package cloudevents publishes lifecycle events of managed APIs as CloudEvents to an HTTP sink, such as an
+// Azure Event Grid topic, so automation like docs generation or contract tests can react to gateway changes.
+package cloudevents
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/apimachinery/pkg/util/uuid"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+// Event types. They follow the reverse-DNS naming of the API group apim.operator.io.
+const (
+	// TypeAPIImported is emitted after an API was imported into APIM.
+	TypeAPIImported = "io.operator.apim.APIImported"
+	// TypeAPIDeleted is emitted when an applied API was found deleted from APIM.
+	TypeAPIDeleted = "io.operator.apim.APIDeleted"
+	// TypePolicyApplied is emitted after an inbound policy was written to APIM.
+	TypePolicyApplied = "io.operator.apim.PolicyApplied"
+	// TypeDriftDetected is emitted when an entity was changed or deleted in APIM outside the operator.
+	TypeDriftDetected = "io.operator.apim.DriftDetected"
+)
+
+const (
+	// EventGridScope is the Azure AD scope of tokens for publishing to Azure Event Grid.
+	EventGridScope = "https://eventgrid.azure.net/.default"
+
+	// DefaultSource is the source of events when none is configured.
+	DefaultSource = "/azure-apim-operator"
+
+	// queueSize is the number of events buffered while the sink is slow or down.
+	queueSize = 1000
+	// maxAttempts is the number of times an event is posted before it is given up.
+	maxAttempts = 3
+
+	resultSent    = "sent"
+	resultFailed  = "failed"
+	resultDropped = "dropped"
+)
+
+// eventsTotal counts events by type and delivery result.
+var eventsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Name: "apim_cloudevents_total",
+	Help: "Number of CloudEvents published to the configured sink, by type and result.",
+}, []string{"type", "result"})
+
+func init() {
+	metrics.Registry.MustRegister(eventsTotal)
+}
+
+// Event is a CloudEvents 1.0 event in the structured JSON format.
+type Event struct {
+	SpecVersion     string `json:"specversion"`
+	ID              string `json:"id"`
+	Source          string `json:"source"`
+	Type            string `json:"type"`
+	Subject         string `json:"subject,omitempty"`
+	Time            string `json:"time"`
+	DataContentType string `json:"datacontenttype"`
+	Data            Data   `json:"data"`
+}
+
+// Data is the payload of an event. Fields that don't apply to an event are left out.
+type Data struct {
+	// APIMService is the name of the targeted APIMService.
+	APIMService string `json:"apimService"`
+	// Kind, Namespace and Name identify the resource the event is about.
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	// APIID, OperationID and ProductID identify the entity in APIM.
+	APIID       string `json:"apiId,omitempty"`
+	OperationID string `json:"operationId,omitempty"`
+	ProductID   string `json:"productId,omitempty"`
+	// Revision is the imported API revision.
+	Revision string `json:"revision,omitempty"`
+	// GatewayURL is the URL of the API on the gateway.
+	GatewayURL string `json:"gatewayUrl,omitempty"`
+	// DefinitionURL is the URL the imported OpenAPI definition was downloaded from.
+	DefinitionURL string `json:"definitionUrl,omitempty"`
+	// AzureResourceID is the ARM resource ID of the entity.
+	AzureResourceID string `json:"azureResourceId,omitempty"`
+	// Change is "modified" or "deleted" for DriftDetected events.
+	Change string `json:"change,omitempty"`
+}
+
+// Options configure a Sink.
+type Options struct {
+	// URL is the HTTP endpoint events are posted to, e.g. an Event Grid topic endpoint.
+	URL string
+	// Source is the CloudEvents source attribute. Defaults to DefaultSource.
+	Source string
+	// AccessKey is sent in the aeg-sas-key header, as Event Grid expects for key authentication.
+	AccessKey string
+	// Token returns a bearer token for each request, e.g. for Event Grid with Azure AD authentication.
+	Token func(ctx context.Context) (string, error)
+}
+
+// Sink publishes events in the background. Emit only queues them, so a slow or unavailable sink never
+// holds up a reconcile. A nil *Sink discards events.
+type Sink struct {
+	opts       Options
+	queue      chan Event
+	httpClient *http.Client
+	retryDelay time.Duration
+}
+
+// NewSink returns a Sink that posts to opts.URL. It delivers events once it is started, e.g. by adding
+// it to the manager.
+func NewSink(opts Options) (*Sink, error) {
+	endpoint, err := url.Parse(opts.URL)
+	if err != nil || (endpoint.Scheme != "http" && endpoint.Scheme != "https") || endpoint.Host == "" {
+		return nil, errors.New("CloudEvents sink URL must be an absolute http or https URL")
+	}
+	if opts.Source == "" {
+		opts.Source = DefaultSource
+	}
+	return &Sink{
+		opts:       opts,
+		queue:      make(chan Event, queueSize),
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+		retryDelay: time.Second,
+	}, nil
+}
+
+// Emit queues an event of eventType about subject. When the queue is full, the event is dropped and
+// counted in apim_cloudevents_total.
+func (s *Sink) Emit(eventType, subject string, data Data) {
+	if s == nil {
+		return
+	}
+	event := Event{
+		SpecVersion:     "1.0",
+		ID:              string(uuid.NewUUID()),
+		Source:          s.opts.Source,
+		Type:            eventType,
+		Subject:         subject,
+		Time:            time.Now().UTC().Format(time.RFC3339Nano),
+		DataContentType: "application/json",
+		Data:            data,
+	}
+	select {
+	case s.queue <- event:
+	default:
+		eventsTotal.WithLabelValues(eventType, resultDropped).Inc()
+		ctrl.Log.WithName("cloudevents").Info("⚠️ CloudEvents queue full; dropping event", "type", eventType, "subject", subject)
+	}
+}
+
+// Start delivers queued events until ctx is done. It implements manager.Runnable.
+func (s *Sink) Start(ctx context.Context) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case event := <-s.queue:
+			s.deliver(ctx, event)
+		}
+	}
+}
+
+// deliver posts event, retrying failed attempts with a growing delay, and counts the result.
+func (s *Sink) deliver(ctx context.Context, event Event) {
+	var err error
+	for attempt := 1; attempt <= maxAttempts; attempt++ {
+		if err = s.send(ctx, event); err == nil {
+			eventsTotal.WithLabelValues(event.Type, resultSent).Inc()
+			return
+		}
+		if attempt < maxAttempts {
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(time.Duration(attempt) * s.retryDelay):
+			}
+		}
+	}
+	eventsTotal.WithLabelValues(event.Type, resultFailed).Inc()
+	ctrl.Log.WithName("cloudevents").Error(err, "❌ Failed to publish CloudEvent", "type", event.Type, "subject", event.Subject, "id", event.ID)
+}
+
+// send posts event once.
+func (s *Sink) send(ctx context.Context, event Event) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("marshal event: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.opts.URL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("build event request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/cloudevents+json; charset=utf-8")
+	if s.opts.AccessKey != "" {
+		req.Header.Set("aeg-sas-key", s.opts.AccessKey)
+	}
+	if s.opts.Token != nil {
+		token, err := s.opts.Token(ctx)
+		if err != nil {
+			return fmt.Errorf("get sink token: %w", err)
+		}
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("post event: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	_, _ = io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("post event: sink answered %s", resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+package cloudevents
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestNewSink(t *testing.T) {
+	for _, sinkURL := range []string{"", "topic.eventgrid.azure.net/api/events", "ftp://example.com"} {
+		if _, err := NewSink(Options{URL: sinkURL}); err == nil {
+			t.Errorf("NewSink(%q) accepted an invalid URL", sinkURL)
+		}
+	}
+	sink, err := NewSink(Options{URL: "https://topic.westeurope-1.eventgrid.azure.net/api/events"})
+	if err != nil {
+		t.Fatalf("NewSink() error = %v", err)
+	}
+	if sink.opts.Source != DefaultSource {
+		t.Errorf("source = %q, want %q", sink.opts.Source, DefaultSource)
+	}
+}
+
+func TestSinkDeliversEvents(t *testing.T) {
+	received := make(chan *http.Request, 1)
+	var events []Event
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		var event Event
+		if err := json.NewDecoder(r.Body).Decode(&event); err != nil {
+			t.Errorf("decode event: %v", err)
+		}
+		events = append(events, event)
+		received <- r
+	}))
+	defer server.Close()
+
+	sink, err := NewSink(Options{
+		URL:       server.URL,
+		Source:    "/clusters/prod",
+		AccessKey: "key",
+		Token:     func(context.Context) (string, error) { return "token", nil },
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sink.retryDelay = time.Millisecond
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() { _ = sink.Start(ctx) }()
+
+	sink.Emit(TypeAPIImported, "apimservices/apim-prod/apis/orders", Data{APIMService: "apim-prod", Kind: "APIMAPI", APIID: "orders"})
+
+	select {
+	case r := <-received:
+		if got := r.Header.Get("Content-Type"); got != "application/cloudevents+json; charset=utf-8" {
+			t.Errorf("Content-Type = %q", got)
+		}
+		if r.Header.Get("aeg-sas-key") != "key" || r.Header.Get("Authorization") != "Bearer token" {
+			t.Errorf("headers = %v, want the access key and bearer token", r.Header)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("event was not delivered")
+	}
+	if attempts != 2 {
+		t.Errorf("attempts = %d, want a retry after the failed post", attempts)
+	}
+	event := events[0]
+	if event.SpecVersion != "1.0" || event.Type != TypeAPIImported || event.Source != "/clusters/prod" || event.ID == "" {
+		t.Errorf("event = %+v, want a CloudEvents 1.0 APIImported event", event)
+	}
+	if event.Subject != "apimservices/apim-prod/apis/orders" || event.Data.APIID != "orders" {
+		t.Errorf("event = %+v, want the subject and data", event)
+	}
+}
+
+func TestNilSinkDiscardsEvents(t *testing.T) {
+	var sink *Sink
+	sink.Emit(TypeDriftDetected, "apimservices/apim-prod/apis/orders", Data{})
+}
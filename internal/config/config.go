@@ -0,0 +1,186 @@
+// Package config loads the operator settings that can be changed without a restart from a file, typically a
+// mounted ConfigMap key, and reloads them when the file changes.
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/yaml"
+
+	"github.com/hedinit/azure-apim-operator/internal/logger"
+)
+
+// Feature gates that can be switched in the settings file.
+const (
+	// FeatureBreakingChangeDetection blocks imports of definitions with breaking changes, like
+	// --breaking-change-detection.
+	FeatureBreakingChangeDetection = "BreakingChangeDetection"
+	// FeatureGzipOpenAPIImports compresses large OpenAPI imports, like --gzip-openapi-imports.
+	FeatureGzipOpenAPIImports = "GzipOpenAPIImports"
+)
+
+// FeatureGates are the names of the feature gates the settings file accepts.
+var FeatureGates = []string{FeatureBreakingChangeDetection, FeatureGzipOpenAPIImports}
+
+// Settings are the operator settings that can be changed while the manager runs.
+type Settings struct {
+	// LogLevel is debug, info, warn, error or a positive integer for more verbose debug output.
+	LogLevel string `json:"logLevel,omitempty"`
+	// ResyncInterval is how often APIMServices read their hostnames from Azure again.
+	ResyncInterval *metav1.Duration `json:"resyncInterval,omitempty"`
+	// AzureRetryInterval is how long a failed call to Azure waits to be retried when Azure did not send
+	// Retry-After.
+	AzureRetryInterval *metav1.Duration `json:"azureRetryInterval,omitempty"`
+	// FeatureGates switches features by name, see FeatureGates.
+	FeatureGates map[string]bool `json:"featureGates,omitempty"`
+}
+
+// Validate returns an error for a setting the operator can't apply.
+func (s Settings) Validate() error {
+	var errs []error
+	if s.LogLevel != "" {
+		if _, err := logger.ParseLevel(s.LogLevel); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if s.ResyncInterval != nil && s.ResyncInterval.Duration <= 0 {
+		errs = append(errs, fmt.Errorf("resyncInterval must be positive, got %s", s.ResyncInterval.Duration))
+	}
+	if s.AzureRetryInterval != nil && s.AzureRetryInterval.Duration <= 0 {
+		errs = append(errs, fmt.Errorf("azureRetryInterval must be positive, got %s", s.AzureRetryInterval.Duration))
+	}
+	for _, gate := range slices.Sorted(maps.Keys(s.FeatureGates)) {
+		if !slices.Contains(FeatureGates, gate) {
+			errs = append(errs, fmt.Errorf("unknown feature gate %q: want one of %s", gate, strings.Join(FeatureGates, ", ")))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Override returns s with every setting that is set in override replaced. Feature gates are overridden
+// one by one.
+func (s Settings) Override(override Settings) Settings {
+	merged := s
+	if override.LogLevel != "" {
+		merged.LogLevel = override.LogLevel
+	}
+	if override.ResyncInterval != nil {
+		merged.ResyncInterval = override.ResyncInterval
+	}
+	if override.AzureRetryInterval != nil {
+		merged.AzureRetryInterval = override.AzureRetryInterval
+	}
+	merged.FeatureGates = maps.Clone(s.FeatureGates)
+	if merged.FeatureGates == nil && len(override.FeatureGates) > 0 {
+		merged.FeatureGates = map[string]bool{}
+	}
+	maps.Copy(merged.FeatureGates, override.FeatureGates)
+	return merged
+}
+
+// Load reads the settings in path, in YAML or JSON. A missing file holds no settings.
+func Load(path string) (Settings, error) {
+	var settings Settings
+	content, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return settings, nil
+	}
+	if err != nil {
+		return settings, fmt.Errorf("read settings file: %w", err)
+	}
+	if err := yaml.UnmarshalStrict(content, &settings); err != nil {
+		return settings, fmt.Errorf("parse settings file %s: %w", path, err)
+	}
+	if err := settings.Validate(); err != nil {
+		return settings, fmt.Errorf("invalid settings file %s: %w", path, err)
+	}
+	return settings, nil
+}
+
+// Watch applies defaults overridden by the settings in path, and applies them again whenever the file changes
+// until ctx is done. It watches the directory of path, so the atomic symlink swap the kubelet uses to update
+// a mounted ConfigMap is seen as well; SIGHUP forces a reload. A file that can't be loaded is logged and leaves
+// the applied settings unchanged. Watch returns an error if the file can't be loaded or watched at startup.
+func Watch(ctx context.Context, path string, defaults Settings, apply func(Settings)) error {
+	log := ctrl.Log.WithName("config")
+
+	settings, err := Load(path)
+	if err != nil {
+		return err
+	}
+	applied := defaults.Override(settings)
+	apply(applied)
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("watch settings file: %w", err)
+	}
+	if err := watcher.Add(filepath.Dir(path)); err != nil {
+		_ = watcher.Close()
+		return fmt.Errorf("watch settings file: %w", err)
+	}
+
+	reload := func() {
+		settings, err := Load(path)
+		if err != nil {
+			log.Error(err, "Keeping the current settings")
+			return
+		}
+		next := defaults.Override(settings)
+		if reflect.DeepEqual(next, applied) {
+			return
+		}
+		log.Info("Applying changed settings", "path", path)
+		applied = next
+		apply(applied)
+	}
+
+	hangup := make(chan os.Signal, 1)
+	signal.Notify(hangup, syscall.SIGHUP)
+
+	go func() {
+		defer signal.Stop(hangup)
+		defer func() { _ = watcher.Close() }()
+		// A ConfigMap update creates, renames and removes several files at once; reload once they settle.
+		debounce := time.NewTimer(0)
+		<-debounce.C
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-hangup:
+				reload()
+			case event, ok := <-watcher.Events:
+				if !ok {
+					return
+				}
+				if event.Has(fsnotify.Chmod) {
+					continue
+				}
+				debounce.Reset(100 * time.Millisecond)
+			case <-debounce.C:
+				reload()
+			case err, ok := <-watcher.Errors:
+				if !ok {
+					return
+				}
+				log.Error(err, "Failed to watch the settings file", "path", path)
+			}
+		}
+	}()
+	return nil
+}
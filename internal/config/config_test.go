@@ -0,0 +1,208 @@
+package config
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestLoad(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    Settings
+		wantErr string
+	}{
+		{
+			name: "all settings",
+			content: "logLevel: debug\nresyncInterval: 30m\nazureRetryInterval: 15s\n" +
+				"featureGates:\n  BreakingChangeDetection: true\n",
+			want: Settings{
+				LogLevel:           "debug",
+				ResyncInterval:     &metav1.Duration{Duration: 30 * time.Minute},
+				AzureRetryInterval: &metav1.Duration{Duration: 15 * time.Second},
+				FeatureGates:       map[string]bool{FeatureBreakingChangeDetection: true},
+			},
+		},
+		{name: "empty file", content: ""},
+		{name: "unknown setting", content: "resync: 30m\n", wantErr: "unknown field"},
+		{name: "invalid log level", content: "logLevel: verbose\n", wantErr: "invalid settings file"},
+		{name: "zero interval", content: "resyncInterval: 0s\n", wantErr: "resyncInterval must be positive"},
+		{name: "unknown feature gate", content: "featureGates:\n  Teleport: true\n", wantErr: `unknown feature gate "Teleport"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "settings.yaml")
+			if err := os.WriteFile(path, []byte(tt.content), 0o600); err != nil {
+				t.Fatalf("WriteFile() error = %v", err)
+			}
+			got, err := Load(path)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Load() error = %v, want it to contain %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Load() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Load() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadMissingFile(t *testing.T) {
+	got, err := Load(filepath.Join(t.TempDir(), "missing.yaml"))
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, Settings{}) {
+		t.Errorf("Load() = %+v, want no settings", got)
+	}
+}
+
+func TestOverride(t *testing.T) {
+	defaults := Settings{
+		ResyncInterval:     &metav1.Duration{Duration: time.Hour},
+		AzureRetryInterval: &metav1.Duration{Duration: time.Minute},
+		FeatureGates:       map[string]bool{FeatureBreakingChangeDetection: true, FeatureGzipOpenAPIImports: false},
+	}
+	got := defaults.Override(Settings{
+		LogLevel:       "warn",
+		ResyncInterval: &metav1.Duration{Duration: 10 * time.Minute},
+		FeatureGates:   map[string]bool{FeatureGzipOpenAPIImports: true},
+	})
+
+	want := Settings{
+		LogLevel:           "warn",
+		ResyncInterval:     &metav1.Duration{Duration: 10 * time.Minute},
+		AzureRetryInterval: &metav1.Duration{Duration: time.Minute},
+		FeatureGates:       map[string]bool{FeatureBreakingChangeDetection: true, FeatureGzipOpenAPIImports: true},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Override() = %+v, want %+v", got, want)
+	}
+	if defaults.FeatureGates[FeatureGzipOpenAPIImports] {
+		t.Error("Override() changed the feature gates of the defaults")
+	}
+}
+
+// recorder collects the settings Watch applies.
+type recorder struct {
+	mu      sync.Mutex
+	applied []Settings
+}
+
+func (r *recorder) apply(s Settings) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.applied = append(r.applied, s)
+}
+
+// waitFor fails the test unless the last applied settings satisfy ok within a second.
+func (r *recorder) waitFor(t *testing.T, what string, ok func(Settings) bool) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for {
+		r.mu.Lock()
+		last := r.applied[len(r.applied)-1]
+		r.mu.Unlock()
+		if ok(last) {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("applied settings %+v, want %s", last, what)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestWatch(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "settings.yaml")
+	if err := os.WriteFile(path, []byte("logLevel: info\n"), 0o600); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var r recorder
+	defaults := Settings{ResyncInterval: &metav1.Duration{Duration: time.Hour}}
+	if err := Watch(ctx, path, defaults, r.apply); err != nil {
+		t.Fatalf("Watch() error = %v", err)
+	}
+	r.waitFor(t, "the settings in the file at startup", func(s Settings) bool {
+		return s.LogLevel == "info" && s.ResyncInterval.Duration == time.Hour
+	})
+
+	if err := os.WriteFile(path, []byte("logLevel: debug\nresyncInterval: 5m\n"), 0o600); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+	r.waitFor(t, "the changed settings", func(s Settings) bool {
+		return s.LogLevel == "debug" && s.ResyncInterval.Duration == 5*time.Minute
+	})
+
+	if err := os.WriteFile(path, []byte("logLevel: verbose\n"), 0o600); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+	time.Sleep(300 * time.Millisecond)
+	r.waitFor(t, "the last valid settings after an invalid change", func(s Settings) bool {
+		return s.LogLevel == "debug"
+	})
+
+	if err := os.Remove(path); err != nil {
+		t.Fatalf("Remove() error = %v", err)
+	}
+	r.waitFor(t, "the defaults once the file is removed", func(s Settings) bool {
+		return s.LogLevel == "" && s.ResyncInterval.Duration == time.Hour
+	})
+}
+
+// TestWatchConfigMapUpdate swaps the ..data symlink the way the kubelet updates a mounted ConfigMap.
+func TestWatchConfigMapUpdate(t *testing.T) {
+	dir := t.TempDir()
+	writeVersion := func(name, content string) {
+		t.Helper()
+		if err := os.Mkdir(filepath.Join(dir, name), 0o700); err != nil {
+			t.Fatalf("Mkdir() error = %v", err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, name, "settings.yaml"), []byte(content), 0o600); err != nil {
+			t.Fatalf("WriteFile() error = %v", err)
+		}
+		if err := os.Symlink(name, filepath.Join(dir, "..data_tmp")); err != nil {
+			t.Fatalf("Symlink() error = %v", err)
+		}
+		if err := os.Rename(filepath.Join(dir, "..data_tmp"), filepath.Join(dir, "..data")); err != nil {
+			t.Fatalf("Rename() error = %v", err)
+		}
+	}
+	writeVersion("..v1", "featureGates:\n  GzipOpenAPIImports: false\n")
+	path := filepath.Join(dir, "settings.yaml")
+	if err := os.Symlink(filepath.Join("..data", "settings.yaml"), path); err != nil {
+		t.Fatalf("Symlink() error = %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var r recorder
+	if err := Watch(ctx, path, Settings{}, r.apply); err != nil {
+		t.Fatalf("Watch() error = %v", err)
+	}
+	r.waitFor(t, "gzip imports off at startup", func(s Settings) bool {
+		return !s.FeatureGates[FeatureGzipOpenAPIImports]
+	})
+
+	writeVersion("..v2", "featureGates:\n  GzipOpenAPIImports: true\n")
+	r.waitFor(t, "gzip imports on after the update", func(s Settings) bool {
+		return s.FeatureGates[FeatureGzipOpenAPIImports]
+	})
+}
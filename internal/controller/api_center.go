@@ -0,0 +1,37 @@
+package controller
+
+import (
+	apimv1 "github.com/hedinit/azure-apim-operator/api/v1"
+	"github.com/hedinit/azure-apim-operator/internal/apim"
+)
+
+// apiCenterConfig returns the API Center workspace an APIM service registers its APIs in,
+// falling back to the service's own subscription and resource group.
+func apiCenterConfig(apimService *apimv1.APIMService, token string) apim.APICenterConfig {
+	apiCenter := apimService.Spec.APICenter
+	config := apim.APICenterConfig{
+		SubscriptionID: apiCenter.Subscription,
+		ResourceGroup:  apiCenter.ResourceGroup,
+		ServiceName:    apiCenter.Name,
+		Workspace:      apiCenter.Workspace,
+		BearerToken:    token,
+	}
+	if config.SubscriptionID == "" {
+		config.SubscriptionID = apimService.Spec.Subscription
+	}
+	if config.ResourceGroup == "" {
+		config.ResourceGroup = apimService.Spec.ResourceGroup
+	}
+	if config.Workspace == "" {
+		config.Workspace = "default"
+	}
+	return config
+}
+
+// apiCenterVersion names the API Center version of an APIM revision: "v1" for the first or unset revision.
+func apiCenterVersion(revision string) string {
+	if revision == "" {
+		return "v1"
+	}
+	return "v" + revision
+}
@@ -0,0 +1,34 @@
+package controller
+
+import (
+	"testing"
+
+	apimv1 "github.com/hedinit/azure-apim-operator/api/v1"
+)
+
+func TestAPICenterConfigDefaults(t *testing.T) {
+	service := &apimv1.APIMService{Spec: apimv1.APIMServiceSpec{
+		Name:          "apim-prod",
+		ResourceGroup: "rg-apim",
+		Subscription:  "sub-apim",
+		APICenter:     &apimv1.APIMServiceAPICenter{Name: "inventory"},
+	}}
+	config := apiCenterConfig(service, "token")
+	if config.SubscriptionID != "sub-apim" || config.ResourceGroup != "rg-apim" || config.Workspace != "default" ||
+		config.ServiceName != "inventory" || config.BearerToken != "token" {
+		t.Errorf("apiCenterConfig() = %+v, want the APIM service location and the default workspace", config)
+	}
+
+	service.Spec.APICenter = &apimv1.APIMServiceAPICenter{Name: "inventory", ResourceGroup: "rg-governance", Subscription: "sub-governance", Workspace: "platform"}
+	config = apiCenterConfig(service, "token")
+	if config.SubscriptionID != "sub-governance" || config.ResourceGroup != "rg-governance" || config.Workspace != "platform" {
+		t.Errorf("apiCenterConfig() = %+v, want the explicit location", config)
+	}
+
+	if got := apiCenterVersion(""); got != "v1" {
+		t.Errorf(`apiCenterVersion("") = %q, want v1`, got)
+	}
+	if got := apiCenterVersion("3"); got != "v3" {
+		t.Errorf(`apiCenterVersion("3") = %q, want v3`, got)
+	}
+}
@@ -0,0 +1,56 @@
+package controller
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apimv1 "github.com/hedinit/azure-apim-operator/api/v1"
+)
+
+// apimFieldManager is the server-side apply field manager for writes the operator owns on APIMAPI objects.
+const apimFieldManager = "azure-apim-operator"
+
+// newAPIMAPIApplyConfiguration returns an apply configuration that identifies apimApi and carries no fields.
+// Unstructured is used so that required spec fields are never sent as zero values and claimed by the operator.
+func newAPIMAPIApplyConfiguration(apimApi *apimv1.APIMAPI) *unstructured.Unstructured {
+	u := &unstructured.Unstructured{}
+	u.SetGroupVersionKind(apimv1.GroupVersion.WithKind("APIMAPI"))
+	u.SetNamespace(apimApi.Namespace)
+	u.SetName(apimApi.Name)
+	return u
+}
+
+// applyAPIMAPIAnnotations sets the annotations the operator owns on the APIMAPI with server-side apply.
+// Only these annotations are owned by the operator, so concurrent edits by users or GitOps tools never conflict.
+// Owned annotations missing from annotations are removed.
+func applyAPIMAPIAnnotations(ctx context.Context, c client.Client, apimApi *apimv1.APIMAPI, annotations map[string]string) error {
+	u := newAPIMAPIApplyConfiguration(apimApi)
+	u.SetAnnotations(annotations)
+	if err := c.Patch(ctx, u, client.Apply, client.FieldOwner(apimFieldManager), client.ForceOwnership); err != nil {
+		return err
+	}
+
+	apimApi.Annotations = u.GetAnnotations()
+	apimApi.ResourceVersion = u.GetResourceVersion()
+	return nil
+}
+
+// applyAPIMAPIStatus writes the status of apimApi with server-side apply. The operator owns the whole
+// status, so the write is idempotent and does not depend on the resourceVersion it was computed from.
+func applyAPIMAPIStatus(ctx context.Context, c client.Client, apimApi *apimv1.APIMAPI) error {
+	status, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&apimApi.Status)
+	if err != nil {
+		return err
+	}
+
+	u := newAPIMAPIApplyConfiguration(apimApi)
+	u.Object["status"] = status
+	if err := c.Status().Patch(ctx, u, client.Apply, client.FieldOwner(apimFieldManager), client.ForceOwnership); err != nil {
+		return err
+	}
+	apimApi.ResourceVersion = u.GetResourceVersion()
+	return nil
+}
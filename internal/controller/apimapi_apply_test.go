@@ -0,0 +1,31 @@
+package controller
+
+import (
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apimv1 "github.com/hedinit/azure-apim-operator/api/v1"
+)
+
+func TestAPIMAPIApplyConfigurationCarriesOnlyIdentity(t *testing.T) {
+	apimApi := &apimv1.APIMAPI{
+		ObjectMeta: metav1.ObjectMeta{Name: "payments", Namespace: "integrations", ResourceVersion: "42"},
+		Spec:       apimv1.APIMAPISpec{APIID: "payments-api", SubscriptionRequired: true},
+	}
+
+	u := newAPIMAPIApplyConfiguration(apimApi)
+
+	if u.GetAPIVersion() != apimv1.GroupVersion.String() || u.GetKind() != "APIMAPI" {
+		t.Fatalf("unexpected type %s/%s", u.GetAPIVersion(), u.GetKind())
+	}
+	if u.GetName() != "payments" || u.GetNamespace() != "integrations" {
+		t.Fatalf("unexpected identity %s/%s", u.GetNamespace(), u.GetName())
+	}
+	if _, ok := u.Object["spec"]; ok {
+		t.Fatalf("apply configuration must not carry spec fields")
+	}
+	if u.GetResourceVersion() != "" {
+		t.Fatalf("apply configuration must not pin a resourceVersion")
+	}
+}
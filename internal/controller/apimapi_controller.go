@@ -20,25 +20,41 @@ import (
 	"context"
 
 	apimv1 "github.com/hedinit/azure-apim-operator/api/v1"
+	"github.com/hedinit/azure-apim-operator/internal/apim"
+	"github.com/hedinit/azure-apim-operator/internal/identity"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
 )
 
 // APIMAPIReconciler reconciles APIMAPI custom resources.
 // This controller manages the lifecycle of APIs in Azure API Management by updating
-// annotations with API host information for integration with tools like ArgoCD.
-// It only processes update events, not create or delete events.
+// annotations with API host information for integration with tools like ArgoCD, and deletes
+// the API from APIM when an APIMAPI with deletionPolicy Delete is deleted.
 type APIMAPIReconciler struct {
 	client.Client
+	// Hub is the client of the management cluster when the reconciler runs for a remote cluster
+	// registered with an APIMCluster. Nil means Client is the management cluster.
+	Hub    client.Client
 	Scheme *runtime.Scheme
+	// Recorder records Warning Events for APIs that couldn't be deleted. Nil disables them.
+	Recorder record.EventRecorder
+	// APIM sends the Azure Management API requests. Nil sends them to the public endpoint.
+	APIM *apim.Client
+	// Tokens provides the Azure Management API token shared by all reconcilers. Nil asks Azure AD on
+	// every reconcile.
+	Tokens identity.TokenProvider
 }
 
 // +kubebuilder:rbac:groups=apim.operator.io,resources=apimapis,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=apim.operator.io,resources=apimapis/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=apim.operator.io,resources=apimapis/finalizers,verbs=update
+// +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
 
 func (r *APIMAPIReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
 	var logger = ctrl.Log.WithName("apimapi_controller")
@@ -50,10 +66,19 @@ func (r *APIMAPIReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ct
 		logger.Info("ℹ️ Unable to fetch APIMAPI")
 		return ctrl.Result{}, client.IgnoreNotFound(err)
 	}
+	setSpanAPIAttributes(ctx, apimApi.Spec.APIMService, apimApi.Spec.APIID)
 
 	logger.Info("🔍 Fetched APIMAPI resource", "name", apimApi.Name, "apiID", apimApi.Spec.APIID)
 
-	deployment, err := ensureAPIMAPIDeployment(ctx, r.Client, &apimApi)
+	if !apimApi.DeletionTimestamp.IsZero() {
+		return r.finalize(ctx, &apimApi)
+	}
+	if err := r.syncFinalizer(ctx, &apimApi); err != nil {
+		logger.Error(err, "❌ Failed to patch APIMAPI finalizers", "apiID", apimApi.Spec.APIID)
+		return ctrl.Result{}, err
+	}
+
+	deployment, err := ensureAPIMAPIDeployment(ctx, r.Client, hubClient(r.Client, r.Hub), &apimApi)
 	if err != nil {
 		logger.Error(err, "❌ Failed to ensure APIMAPIDeployment", "name", apimApi.Name, "apiID", apimApi.Spec.APIID)
 		return ctrl.Result{}, err
@@ -71,16 +96,13 @@ func (r *APIMAPIReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ct
 		logger.Info("ℹ️ Annotations were nil, initializing map", "apiID", apimApi.Spec.APIID)
 	}
 
-	// Update the ArgoCD external link annotation with the API host URL.
-	// This allows ArgoCD to display a link to the API in its UI.
-	// Use Patch to update only annotations without touching spec or status fields.
-	desiredExternalLink := apimApi.Status.ApiHost
-	if apimApi.Annotations["link.argocd.argoproj.io/external-link"] != desiredExternalLink {
-		annotationPatch := client.MergeFrom(apimApi.DeepCopy())
-		apimApi.Annotations["link.argocd.argoproj.io/external-link"] = desiredExternalLink
-
-		if err := r.Patch(ctx, &apimApi, annotationPatch); err != nil {
-			logger.Error(err, "❌ Failed to patch APIMAPI with external link annotations", "apiID", apimApi.Spec.APIID)
+	// Update the ArgoCD external link annotation with the API host URL, and the Backstage catalog annotations
+	// of APIs with an owner. This allows ArgoCD to display a link to the API in its UI.
+	// Use server-side apply so only these annotations are owned by the operator and concurrent edits don't conflict.
+	desiredAnnotations := desiredAPIMAPIAnnotations(&apimApi)
+	if !apimAPIAnnotationsInSync(&apimApi, desiredAnnotations) {
+		if err := applyAPIMAPIAnnotations(ctx, r.Client, &apimApi, desiredAnnotations); err != nil {
+			logger.Error(err, "❌ Failed to patch APIMAPI annotations", "apiID", apimApi.Spec.APIID)
 			return ctrl.Result{}, err
 		}
 	}
@@ -93,7 +115,7 @@ func (r *APIMAPIReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ct
 		"apiID", apimApi.Spec.APIID,
 		"apimService", apimApi.Spec.APIMService,
 		"routePrefix", apimApi.Spec.RoutePrefix,
-		"serviceUrl", apimApi.Spec.ServiceURL,
+		"serviceUrl", apimApi.Spec.ActiveServiceURL(),
 		"openApiDefinitionUrl", apimApi.Spec.OpenAPIDefinitionURL,
 		"subscriptionRequired", apimApi.Spec.SubscriptionRequired,
 		"productIds", apimApi.Spec.ProductIDs,
@@ -102,7 +124,8 @@ func (r *APIMAPIReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ct
 		"developerPortalHost", apimApi.Status.DeveloperPortalHost,
 		"status", apimApi.Status.Status,
 		"importedAt", apimApi.Status.ImportedAt,
-		"externalLinkAnnotation", apimApi.Annotations["link.argocd.argoproj.io/external-link"],
+		"externalLinkAnnotation", apimApi.Annotations[argoCDExternalLinkAnnotation],
+		"owner", apimApi.Spec.Owner,
 	)
 
 	logger.Info("✅ Successfully reconciled APIMAPI", "name", apimApi.Name, "apiID", apimApi.Spec.APIID)
@@ -111,22 +134,37 @@ func (r *APIMAPIReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ct
 }
 
 func (r *APIMAPIReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &apimv1.APIMAPI{}, apimAPIServiceField, indexAPIMAPIService); err != nil {
+		return err
+	}
+
+	// Watch via the priority-aware handler instead of For so high-priority resources jump the queue.
+	// Spec changes of an APIMService, such as a new resource group, are handed down to its APIMAPIs' deployments.
+	// The shard check of those APIMAPIs happens in the map function, since the APIMService lives in the operator namespace.
 	return ctrl.NewControllerManagedBy(mgr).
-		For(&apimv1.APIMAPI{}).
-		WithEventFilter(predicate.Funcs{
-			CreateFunc: func(e event.CreateEvent) bool {
-				return true
-			},
-			UpdateFunc: func(e event.UpdateEvent) bool {
-				return true
-			},
-			DeleteFunc: func(e event.DeleteEvent) bool {
-				return false
-			},
-			GenericFunc: func(e event.GenericEvent) bool {
-				return false
-			},
-		}).
+		Watches(&apimv1.APIMAPI{}, priorityEnqueueHandler(), builder.WithPredicates(apimAPIPredicate(), shardPredicate())).
+		Watches(&apimv1.APIMService{}, handler.EnqueueRequestsFromMapFunc(apimAPIsForService(mgr.GetClient())),
+			builder.WithPredicates(predicate.GenerationChangedPredicate{})).
 		Named("apimapi").
-		Complete(r)
+		Complete(withTracing("APIMAPI", r))
+}
+
+// apimAPIPredicate selects the APIMAPI events the APIMAPI controller reconciles. Deletes are seen as the
+// update that sets the deletion timestamp while the finalizer holds the APIMAPI; the owned
+// APIMAPIDeployment is left to garbage collection.
+func apimAPIPredicate() predicate.Predicate {
+	return predicate.Funcs{
+		CreateFunc: func(e event.CreateEvent) bool {
+			return true
+		},
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			return true
+		},
+		DeleteFunc: func(e event.DeleteEvent) bool {
+			return false
+		},
+		GenericFunc: func(e event.GenericEvent) bool {
+			return false
+		},
+	}
 }
@@ -106,6 +106,9 @@ var _ = Describe("APIMAPI Controller", func() {
 		resource := &apimv1.APIMAPI{}
 		err = k8sClient.Get(ctx, typeNamespacedName, resource)
 		if err == nil {
+			// No API was imported, so the cleanup finalizer has nothing to delete from APIM.
+			resource.Finalizers = nil
+			Expect(k8sClient.Update(ctx, resource)).To(Succeed())
 			Expect(k8sClient.Delete(ctx, resource)).To(Succeed())
 		}
 
@@ -117,6 +120,37 @@ var _ = Describe("APIMAPI Controller", func() {
 		}
 	})
 
+	Context("When validating the schema", func() {
+		It("should reject a routePrefix without a leading slash", func() {
+			api := &apimv1.APIMAPI{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, api)).To(Succeed())
+			api.Spec.RoutePrefix = "test-api"
+			err := k8sClient.Update(ctx, api)
+			Expect(errors.IsInvalid(err)).To(BeTrue())
+			Expect(err.Error()).To(ContainSubstring("routePrefix must start with '/'"))
+		})
+	})
+
+	Context("When updating immutable fields", func() {
+		It("should reject changing APIID", func() {
+			api := &apimv1.APIMAPI{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, api)).To(Succeed())
+			api.Spec.APIID = "renamed-api-id"
+			err := k8sClient.Update(ctx, api)
+			Expect(errors.IsInvalid(err)).To(BeTrue())
+			Expect(err.Error()).To(ContainSubstring("APIID is immutable"))
+		})
+
+		It("should reject changing apimService", func() {
+			api := &apimv1.APIMAPI{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, api)).To(Succeed())
+			api.Spec.APIMService = "other-apim-service"
+			err := k8sClient.Update(ctx, api)
+			Expect(errors.IsInvalid(err)).To(BeTrue())
+			Expect(err.Error()).To(ContainSubstring("apimService is immutable"))
+		})
+	})
+
 	Context("When reconciling a resource", func() {
 		It("should update ArgoCD external link annotation when status has ApiHost", func() {
 			By("reconciling the resource")
@@ -161,6 +195,7 @@ var _ = Describe("APIMAPI Controller", func() {
 				Spec: apimv1.APIMAPISpec{
 					APIID:       "test-api-id-2",
 					APIMService: "test-apim-service",
+					RoutePrefix: "/test-api-2",
 				},
 			}
 			Expect(k8sClient.Create(ctx, api)).To(Succeed())
@@ -0,0 +1,108 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	apimv1 "github.com/hedinit/azure-apim-operator/api/v1"
+	"github.com/hedinit/azure-apim-operator/internal/apim"
+)
+
+const (
+	// apimAPIFinalizer keeps an APIMAPI with deletionPolicy Delete until its API is deleted from APIM.
+	apimAPIFinalizer = "apim.operator.io/api-cleanup"
+
+	// eventReasonAPIDeletionFailed is the Warning Event reason for an API that couldn't be deleted from APIM.
+	eventReasonAPIDeletionFailed = "APIDeletionFailed"
+)
+
+// deletesAPI reports whether deleting api deletes its API from APIM.
+func deletesAPI(api *apimv1.APIMAPI) bool {
+	return api.Spec.DeletionPolicy != apimv1.DeletionPolicyOrphan
+}
+
+// syncFinalizer adds the finalizer to apimApi if deleting it deletes its API, and removes it otherwise.
+func (r *APIMAPIReconciler) syncFinalizer(ctx context.Context, apimApi *apimv1.APIMAPI) error {
+	if deletesAPI(apimApi) == controllerutil.ContainsFinalizer(apimApi, apimAPIFinalizer) {
+		return nil
+	}
+	patch := client.MergeFrom(apimApi.DeepCopy())
+	if deletesAPI(apimApi) {
+		controllerutil.AddFinalizer(apimApi, apimAPIFinalizer)
+	} else {
+		controllerutil.RemoveFinalizer(apimApi, apimAPIFinalizer)
+	}
+	return r.Patch(ctx, apimApi, patch)
+}
+
+// finalize deletes the API of a deleted APIMAPI from APIM and removes the finalizer. APIs the operator never
+// imported, such as adopted ones, are left in APIM. A failed deletion keeps the finalizer and is retried.
+func (r *APIMAPIReconciler) finalize(ctx context.Context, apimApi *apimv1.APIMAPI) (ctrl.Result, error) {
+	logger := ctrl.Log.WithName("apimapi_controller")
+	if !controllerutil.ContainsFinalizer(apimApi, apimAPIFinalizer) {
+		return ctrl.Result{}, nil
+	}
+
+	if apimApi.Status.ImportedAt == "" {
+		logger.Info("ℹ️ API was never imported by the operator, leaving it in APIM", "apiID", apimApi.Spec.APIID)
+		return ctrl.Result{}, r.removeFinalizer(ctx, apimApi)
+	}
+
+	operatorNamespace, err := getOperatorNamespace()
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("get operator namespace: %w", err)
+	}
+	var apimService apimv1.APIMService
+	if err := hubClient(r.Client, r.Hub).Get(ctx, client.ObjectKey{Name: apimApi.Spec.APIMService, Namespace: operatorNamespace}, &apimService); err != nil {
+		if errors.IsNotFound(err) {
+			// Without the service there is nothing to delete the API from.
+			logger.Info("ℹ️ APIMService not found, leaving the API", "apiID", apimApi.Spec.APIID, "apimService", apimApi.Spec.APIMService)
+			return ctrl.Result{}, r.removeFinalizer(ctx, apimApi)
+		}
+		return ctrl.Result{}, err
+	}
+
+	clientID := os.Getenv("AZURE_CLIENT_ID")
+	tenantID := os.Getenv("AZURE_TENANT_ID")
+	if clientID == "" || tenantID == "" {
+		logger.Error(fmt.Errorf("missing identity env vars"), "❌ AZURE_CLIENT_ID or AZURE_TENANT_ID not set")
+		recordWarningEvent(r.Recorder, apimApi, eventReasonAPIDeletionFailed, "Failed to delete API from APIM: missing AZURE_CLIENT_ID or AZURE_TENANT_ID")
+		return ctrl.Result{RequeueAfter: 30 * time.Second}, nil
+	}
+	token, err := managementToken(ctx, r.Tokens, clientID, tenantID)
+	if err != nil {
+		logger.Error(err, "❌ Failed to get Azure token")
+		recordWarningEvent(r.Recorder, apimApi, eventReasonAPIDeletionFailed, failureMessage("Failed to delete API from APIM", err))
+		return ctrl.Result{RequeueAfter: 30 * time.Second}, nil
+	}
+
+	err = r.APIM.DeleteAPI(ctx, apim.APIMDeploymentConfig{
+		SubscriptionID: apimService.Spec.Subscription,
+		ResourceGroup:  apimService.Spec.ResourceGroup,
+		ServiceName:    apimApi.Spec.APIMService,
+		APIID:          apimApi.Spec.APIID,
+		BearerToken:    token,
+	})
+	if err != nil {
+		logger.Error(err, "❌ Failed to delete API from APIM", "apiID", apimApi.Spec.APIID)
+		invalidateRejectedToken(r.Tokens, err)
+		recordWarningEvent(r.Recorder, apimApi, eventReasonAPIDeletionFailed, failureMessage("Failed to delete API from APIM", err))
+		return ctrl.Result{RequeueAfter: 30 * time.Second}, nil
+	}
+	logger.Info("🗑️ Deleted API from APIM", "apiID", apimApi.Spec.APIID, "apimService", apimApi.Spec.APIMService)
+	return ctrl.Result{}, r.removeFinalizer(ctx, apimApi)
+}
+
+// removeFinalizer removes the finalizer of apimApi, so Kubernetes deletes it.
+func (r *APIMAPIReconciler) removeFinalizer(ctx context.Context, apimApi *apimv1.APIMAPI) error {
+	patch := client.MergeFrom(apimApi.DeepCopy())
+	controllerutil.RemoveFinalizer(apimApi, apimAPIFinalizer)
+	return r.Patch(ctx, apimApi, patch)
+}
@@ -0,0 +1,150 @@
+package controller
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	apimv1 "github.com/hedinit/azure-apim-operator/api/v1"
+	"github.com/hedinit/azure-apim-operator/internal/apimtest"
+	"github.com/hedinit/azure-apim-operator/internal/identity"
+)
+
+func TestAPIMAPIFinalize(t *testing.T) {
+	const servicePath = "/subscriptions/sub/resourceGroups/rg/providers/Microsoft.ApiManagement/service/apim-test"
+	tests := []struct {
+		name       string
+		importedAt string
+		wantAPI    bool
+	}{
+		{name: "imported API is deleted", importedAt: "2026-01-12T10:00:00Z", wantAPI: false},
+		{name: "API never imported is left", wantAPI: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fakeAPIM := apimtest.Start(t)
+			t.Setenv("AZURE_CLIENT_ID", "test-client-id")
+			t.Setenv("AZURE_TENANT_ID", "test-tenant-id")
+			identity.SetStaticToken("test-token")
+			t.Cleanup(func() { identity.SetStaticToken("") })
+			fakeAPIM.Put(servicePath+"/apis/orders", map[string]interface{}{"path": "orders"})
+			fakeAPIM.Put(servicePath+"/products/starter/apis/orders", map[string]interface{}{})
+
+			scheme := runtime.NewScheme()
+			if err := apimv1.AddToScheme(scheme); err != nil {
+				t.Fatal(err)
+			}
+			now := metav1.NewTime(time.Now())
+			api := &apimv1.APIMAPI{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "orders", Namespace: "default", Finalizers: []string{apimAPIFinalizer}, DeletionTimestamp: &now,
+				},
+				Spec:   apimv1.APIMAPISpec{APIID: "orders", APIMService: "apim-test", RoutePrefix: "/orders"},
+				Status: apimv1.APIMAPIStatus{ImportedAt: tt.importedAt},
+			}
+			service := &apimv1.APIMService{
+				ObjectMeta: metav1.ObjectMeta{Name: "apim-test", Namespace: "default"},
+				Spec:       apimv1.APIMServiceSpec{Subscription: "sub", ResourceGroup: "rg"},
+			}
+			r := &APIMAPIReconciler{
+				APIM:     fakeAPIM.Client(),
+				Client:   fake.NewClientBuilder().WithScheme(scheme).WithObjects(api, service).Build(),
+				Scheme:   scheme,
+				Recorder: record.NewFakeRecorder(10),
+			}
+
+			key := types.NamespacedName{Name: "orders", Namespace: "default"}
+			if _, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: key}); err != nil {
+				t.Fatalf("Reconcile() error = %v", err)
+			}
+			if err := r.Get(context.Background(), key, &apimv1.APIMAPI{}); !apierrors.IsNotFound(err) {
+				t.Errorf("Get() error = %v, want the APIMAPI deleted once the finalizer is removed", err)
+			}
+			if _, ok := fakeAPIM.Properties(servicePath + "/apis/orders"); ok != tt.wantAPI {
+				t.Errorf("API exists in APIM = %v, want %v", ok, tt.wantAPI)
+			}
+			if _, ok := fakeAPIM.Properties(servicePath + "/products/starter/apis/orders"); ok != tt.wantAPI {
+				t.Errorf("product link exists in APIM = %v, want %v", ok, tt.wantAPI)
+			}
+		})
+	}
+}
+
+func TestAPIMAPIFinalizeFailure(t *testing.T) {
+	fakeAPIM := apimtest.Start(t)
+	t.Setenv("AZURE_CLIENT_ID", "test-client-id")
+	t.Setenv("AZURE_TENANT_ID", "test-tenant-id")
+	identity.SetStaticToken("test-token")
+	t.Cleanup(func() { identity.SetStaticToken("") })
+	fakeAPIM.Inject(apimtest.Fault{Method: http.MethodDelete, PathSuffix: "/apis/orders", Status: http.StatusForbidden, Code: "AuthorizationFailed"})
+
+	scheme := runtime.NewScheme()
+	if err := apimv1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	now := metav1.NewTime(time.Now())
+	api := &apimv1.APIMAPI{
+		ObjectMeta: metav1.ObjectMeta{Name: "orders", Namespace: "default", Finalizers: []string{apimAPIFinalizer}, DeletionTimestamp: &now},
+		Spec:       apimv1.APIMAPISpec{APIID: "orders", APIMService: "apim-test", RoutePrefix: "/orders"},
+		Status:     apimv1.APIMAPIStatus{ImportedAt: "2026-01-12T10:00:00Z"},
+	}
+	service := &apimv1.APIMService{
+		ObjectMeta: metav1.ObjectMeta{Name: "apim-test", Namespace: "default"},
+		Spec:       apimv1.APIMServiceSpec{Subscription: "sub", ResourceGroup: "rg"},
+	}
+	recorder := record.NewFakeRecorder(10)
+	r := &APIMAPIReconciler{
+		APIM:     fakeAPIM.Client(),
+		Client:   fake.NewClientBuilder().WithScheme(scheme).WithObjects(api, service).Build(),
+		Scheme:   scheme,
+		Recorder: recorder,
+	}
+
+	key := types.NamespacedName{Name: "orders", Namespace: "default"}
+	result, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: key})
+	if err != nil || result.RequeueAfter == 0 {
+		t.Fatalf("Reconcile() = %+v, %v, want a requeue", result, err)
+	}
+	var got apimv1.APIMAPI
+	if err := r.Get(context.Background(), key, &got); err != nil || !controllerutil.ContainsFinalizer(&got, apimAPIFinalizer) {
+		t.Errorf("Get() = %v, %v, want the APIMAPI kept with its finalizer", got.Finalizers, err)
+	}
+	if event := <-recorder.Events; !strings.HasPrefix(event, "Warning APIDeletionFailed") {
+		t.Errorf("event = %q, want APIDeletionFailed", event)
+	}
+}
+
+func TestAPIMAPISyncFinalizer(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := apimv1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	api := &apimv1.APIMAPI{ObjectMeta: metav1.ObjectMeta{Name: "orders", Namespace: "default"}}
+	r := &APIMAPIReconciler{Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(api).Build(), Scheme: scheme}
+
+	if err := r.syncFinalizer(context.Background(), api); err != nil {
+		t.Fatalf("syncFinalizer() error = %v", err)
+	}
+	if !controllerutil.ContainsFinalizer(api, apimAPIFinalizer) {
+		t.Errorf("finalizers = %v, want %s without a deletionPolicy", api.Finalizers, apimAPIFinalizer)
+	}
+
+	api.Spec.DeletionPolicy = apimv1.DeletionPolicyOrphan
+	if err := r.syncFinalizer(context.Background(), api); err != nil {
+		t.Fatalf("syncFinalizer() error = %v", err)
+	}
+	if controllerutil.ContainsFinalizer(api, apimAPIFinalizer) {
+		t.Errorf("finalizers = %v, want none with deletionPolicy Orphan", api.Finalizers)
+	}
+}
@@ -0,0 +1,90 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	apimv1 "github.com/hedinit/azure-apim-operator/api/v1"
+	"github.com/hedinit/azure-apim-operator/internal/notify"
+)
+
+const (
+	deploymentOutcomeSucceeded = "Succeeded"
+	deploymentOutcomeFailed    = "Failed"
+	deploymentOutcomeDryRun    = "DryRun"
+
+	// defaultStatusHistoryLimit is the number of deployments kept in APIMAPI status.history.
+	defaultStatusHistoryLimit = 10
+)
+
+// statusHistoryLimit bounds APIMAPI status.history. See SetStatusHistoryLimit.
+var statusHistoryLimit = defaultStatusHistoryLimit
+
+// SetStatusHistoryLimit sets how many deployments are kept in APIMAPI status.history.
+// Zero disables the history. It must be called before the manager starts.
+func SetStatusHistoryLimit(limit int) error {
+	if limit < 0 {
+		return fmt.Errorf("status history limit must not be negative, got %d", limit)
+	}
+	statusHistoryLimit = limit
+	return nil
+}
+
+// newDeploymentRecord describes the current deployment attempt with the given outcome.
+func newDeploymentRecord(deployment *apimv1.APIMAPIDeployment, outcome string) apimv1.APIMAPIDeploymentRecord {
+	return apimv1.APIMAPIDeploymentRecord{
+		Timestamp:  time.Now().UTC().Format(time.RFC3339),
+		SpecHash:   deployment.Status.DesiredHash,
+		Revision:   deployment.Spec.Revision,
+		Outcome:    outcome,
+		ReplicaSet: deployment.Annotations[apimDeploymentReplicaSetAnnotation],
+	}
+}
+
+// appendDeploymentRecord adds record to history and drops the oldest entries beyond limit.
+// A failure that repeats the last entry only updates its timestamp, so retries of a broken
+// deployment don't push earlier deployments out of the history.
+func appendDeploymentRecord(history []apimv1.APIMAPIDeploymentRecord, record apimv1.APIMAPIDeploymentRecord, limit int) []apimv1.APIMAPIDeploymentRecord {
+	if limit <= 0 {
+		return nil
+	}
+	if n := len(history); n > 0 && record.Outcome == deploymentOutcomeFailed {
+		last := history[n-1]
+		last.Timestamp = record.Timestamp
+		if last == record {
+			history = append([]apimv1.APIMAPIDeploymentRecord(nil), history...)
+			history[n-1] = record
+			return history
+		}
+	}
+	history = append(append([]apimv1.APIMAPIDeploymentRecord(nil), history...), record)
+	if len(history) > limit {
+		history = history[len(history)-limit:]
+	}
+	return history
+}
+
+// recordDeploymentHistory adds the current deployment attempt to the APIMAPI status history in memory.
+// Callers persist it with the next applyAPIMAPIStatus.
+func recordDeploymentHistory(apimApi *apimv1.APIMAPI, deployment *apimv1.APIMAPIDeployment, outcome string) {
+	apimApi.Status.History = appendDeploymentRecord(apimApi.Status.History, newDeploymentRecord(deployment, outcome), statusHistoryLimit)
+}
+
+// recordFailedDeployment marks the APIMAPI not Ready for a failed import step, adds the attempt to its
+// status history and applies both. message summarizes the step for the condition and the Event.
+// The failure is also posted to the notifications of the APIMService.
+func (r *APIMAPIDeploymentReconciler) recordFailedDeployment(
+	ctx context.Context,
+	apimApi *apimv1.APIMAPI,
+	deployment *apimv1.APIMAPIDeployment,
+	step, message string,
+	err error,
+) {
+	if statusHistoryLimit > 0 {
+		recordDeploymentHistory(apimApi, deployment, deploymentOutcomeFailed)
+	}
+	invalidateRejectedToken(r.Tokens, err)
+	r.markNotReady(ctx, apimApi, failureReason(step, err), failureMessage(message, err))
+	r.notifyImport(ctx, apimApi, deployment, notify.ResultFailure, step, err)
+}
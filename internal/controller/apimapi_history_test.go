@@ -0,0 +1,37 @@
+package controller
+
+import (
+	"testing"
+
+	apimv1 "github.com/hedinit/azure-apim-operator/api/v1"
+)
+
+func TestAppendDeploymentRecord(t *testing.T) {
+	record := func(timestamp, hash, outcome string) apimv1.APIMAPIDeploymentRecord {
+		return apimv1.APIMAPIDeploymentRecord{Timestamp: timestamp, SpecHash: hash, Outcome: outcome, ReplicaSet: "payments-7d9f"}
+	}
+
+	var history []apimv1.APIMAPIDeploymentRecord
+	history = appendDeploymentRecord(history, record("t1", "a", deploymentOutcomeSucceeded), 3)
+	history = appendDeploymentRecord(history, record("t2", "b", deploymentOutcomeFailed), 3)
+	history = appendDeploymentRecord(history, record("t3", "b", deploymentOutcomeFailed), 3)
+	if len(history) != 2 || history[1].Timestamp != "t3" {
+		t.Fatalf("history = %+v, want a repeated failure to update the last entry", history)
+	}
+
+	history = appendDeploymentRecord(history, record("t4", "b", deploymentOutcomeSucceeded), 3)
+	history = appendDeploymentRecord(history, record("t5", "c", deploymentOutcomeSucceeded), 3)
+	if len(history) != 3 || history[0].SpecHash != "b" || history[2].SpecHash != "c" {
+		t.Fatalf("history = %+v, want the 3 most recent deployments, oldest first", history)
+	}
+
+	if got := appendDeploymentRecord(history, record("t6", "d", deploymentOutcomeSucceeded), 0); got != nil {
+		t.Errorf("appendDeploymentRecord() with limit 0 = %+v, want nil", got)
+	}
+}
+
+func TestSetStatusHistoryLimitRejectsNegative(t *testing.T) {
+	if err := SetStatusHistoryLimit(-1); err == nil {
+		t.Error("SetStatusHistoryLimit(-1) error = nil, want error")
+	}
+}
@@ -0,0 +1,60 @@
+package controller
+
+import (
+	"context"
+
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	apimv1 "github.com/hedinit/azure-apim-operator/api/v1"
+)
+
+// apimAPIServiceField indexes APIMAPIs by spec.apimService, so the APIMAPIs of an APIMService can be
+// listed from the cache without filtering every APIMAPI in the cluster.
+const apimAPIServiceField = "spec.apimService"
+
+// indexAPIMAPIService is the field indexer for apimAPIServiceField.
+func indexAPIMAPIService(obj client.Object) []string {
+	apimApi, ok := obj.(*apimv1.APIMAPI)
+	if !ok || apimApi.Spec.APIMService == "" {
+		return nil
+	}
+	return []string{apimApi.Spec.APIMService}
+}
+
+// listAPIMAPIsForService lists the APIMAPIs in all namespaces that reference the named APIMService.
+// c must be backed by a cache with the apimAPIServiceField index.
+func listAPIMAPIsForService(ctx context.Context, c client.Reader, service string) ([]apimv1.APIMAPI, error) {
+	var apis apimv1.APIMAPIList
+	if err := c.List(ctx, &apis, client.MatchingFields{apimAPIServiceField: service}); err != nil {
+		return nil, err
+	}
+	return apis.Items, nil
+}
+
+// apimAPIsForService maps an APIMService to the APIMAPIs referencing it that this shard owns.
+// APIMAPIs reference APIMServices in the operator namespace by name, so services elsewhere map to nothing.
+func apimAPIsForService(c client.Reader) func(context.Context, client.Object) []reconcile.Request {
+	return func(ctx context.Context, obj client.Object) []reconcile.Request {
+		logger := ctrl.Log.WithName("apimapi_controller")
+
+		operatorNamespace, err := getOperatorNamespace()
+		if err != nil || obj.GetNamespace() != operatorNamespace {
+			return nil
+		}
+		apis, err := listAPIMAPIsForService(ctx, c, obj.GetName())
+		if err != nil {
+			logger.Error(err, "❌ Failed to list APIMAPIs of APIMService", "apimService", obj.GetName())
+			return nil
+		}
+
+		requests := make([]reconcile.Request, 0, len(apis))
+		for i := range apis {
+			if ownsNamespace(apis[i].Namespace) {
+				requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&apis[i])})
+			}
+		}
+		return requests
+	}
+}
@@ -0,0 +1,53 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	apimv1 "github.com/hedinit/azure-apim-operator/api/v1"
+)
+
+func TestAPIMAPIsForService(t *testing.T) {
+	t.Setenv("OPERATOR_NAMESPACE", "apim-operator")
+
+	apimApi := func(namespace, name, service string) client.Object {
+		return &apimv1.APIMAPI{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+			Spec:       apimv1.APIMAPISpec{APIMService: service},
+		}
+	}
+	scheme := runtime.NewScheme()
+	if err := apimv1.AddToScheme(scheme); err != nil {
+		t.Fatalf("AddToScheme() error = %v", err)
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).
+		WithIndex(&apimv1.APIMAPI{}, apimAPIServiceField, indexAPIMAPIService).
+		WithObjects(
+			apimApi("payments", "orders", "apim-prod"),
+			apimApi("shipping", "tracking", "apim-prod"),
+			apimApi("payments", "refunds", "apim-test"),
+		).Build()
+	mapFunc := apimAPIsForService(c)
+
+	service := func(namespace string) *apimv1.APIMService {
+		return &apimv1.APIMService{ObjectMeta: metav1.ObjectMeta{Name: "apim-prod", Namespace: namespace}}
+	}
+
+	requests := mapFunc(context.Background(), service("apim-operator"))
+	got := map[string]bool{}
+	for _, req := range requests {
+		got[req.String()] = true
+	}
+	if len(requests) != 2 || !got["payments/orders"] || !got["shipping/tracking"] {
+		t.Errorf("apimAPIsForService() = %v, want the two APIMAPIs of apim-prod", requests)
+	}
+
+	if requests := mapFunc(context.Background(), service("payments")); len(requests) != 0 {
+		t.Errorf("apimAPIsForService() = %v for an APIMService outside the operator namespace, want none", requests)
+	}
+}
@@ -0,0 +1,134 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	apimv1 "github.com/hedinit/azure-apim-operator/api/v1"
+	"github.com/hedinit/azure-apim-operator/internal/apim"
+)
+
+const (
+	apimDeploymentPhaseAdopted = "Adopted"
+
+	conditionTypeAdopted          = "Adopted"
+	conditionReasonAdoptedAPI     = "AdoptedExistingAPI"
+	conditionReasonAdoptionRefuse = "AdoptionRefused"
+)
+
+// requiresAdoptionCheck reports whether the deployment must look for a pre-existing API in APIM
+// before importing. Only first imports are checked; once the operator has applied the API it owns it.
+func requiresAdoptionCheck(deployment *apimv1.APIMAPIDeployment) bool {
+	if deployment.Status.AppliedHash != "" || deployment.Spec.Revision != "" {
+		return false
+	}
+	policy := deployment.Spec.AdoptionPolicy
+	return policy == apimv1.AdoptionPolicyAdopt || policy == apimv1.AdoptionPolicyFail
+}
+
+// applyAdoptionPolicy handles an API that already exists in APIM before the operator ever imported it.
+// With Adopt, the existing settings are read back into the APIMAPI status without importing.
+// With Fail, the APIMAPI gets a failed Adopted condition and nothing is sent to APIM.
+func (r *APIMAPIDeploymentReconciler) applyAdoptionPolicy(
+	ctx context.Context,
+	deployment *apimv1.APIMAPIDeployment,
+	statusBatch *deploymentStatusBatch,
+	apimApi *apimv1.APIMAPI,
+	apimService *apimv1.APIMService,
+	config apim.APIMDeploymentConfig,
+	existing *apim.APIDetails,
+	setStatus func(*apimv1.APIMAPIDeploymentStatus),
+) (ctrl.Result, error) {
+	logger := ctrl.Log.WithName("apimapideployment_controller")
+	resourceID := apim.APIResourceID(config.SubscriptionID, config.ResourceGroup, config.ServiceName, config.APIID)
+
+	if deployment.Spec.AdoptionPolicy == apimv1.AdoptionPolicyFail {
+		message := fmt.Sprintf("API %q already exists in APIM and adoptionPolicy is Fail", config.APIID)
+		logger.Info("🛑 Refusing to overwrite existing API", "apiID", config.APIID)
+
+		apimApi.Status.Status = phaseError
+		apimApi.Status.AzureResourceID = resourceID
+		apimeta.SetStatusCondition(&apimApi.Status.Conditions, metav1.Condition{
+			Type:               conditionTypeAdopted,
+			Status:             metav1.ConditionFalse,
+			Reason:             conditionReasonAdoptionRefuse,
+			Message:            message,
+			ObservedGeneration: apimApi.Generation,
+		})
+		setReadyCondition(apimApi, metav1.ConditionFalse, conditionReasonAdoptionRefuse, message)
+		if err := applyAPIMAPIStatus(ctx, r.Client, apimApi); err != nil {
+			logger.Error(err, "⚠️ Failed to patch APIMAPI status", "apiID", config.APIID)
+			return ctrl.Result{}, err
+		}
+
+		statusBatch.update(func(status *apimv1.APIMAPIDeploymentStatus) {
+			setStatus(status)
+			status.Phase = phaseError
+			status.Status = phaseError
+			status.Message = message
+			status.LastError = message
+		})
+		return ctrl.Result{}, nil
+	}
+
+	apiHost, developerPortalHost, err := resolveAPIMServiceHosts(ctx, r.APIM, apimService, config)
+	if err != nil {
+		logger.Error(err, "⚠️ Failed to fetch APIM details", "apiID", config.APIID)
+		statusBatch.update(func(status *apimv1.APIMAPIDeploymentStatus) {
+			setStatus(status)
+			status.Phase = phaseError
+			status.Status = phaseError
+			status.Message = "Failed to fetch APIM service details"
+			status.LastError = err.Error()
+		})
+		return ctrl.Result{}, err
+	}
+
+	adoptedAt := time.Now().UTC().Format(time.RFC3339)
+	apimApi.Status.Status = apimDeploymentPhaseAdopted
+	apimApi.Status.ApiHost = fmt.Sprintf("https://%s/%s", apiHost, existing.Properties.Path)
+	apimApi.Status.DeveloperPortalHost = fmt.Sprintf("https://%s", developerPortalHost)
+	apimApi.Status.AzureResourceID = resourceID
+	apimApi.Status.TerraformImports = apiTerraformImports(config.SubscriptionID, config.ResourceGroup, config.ServiceName, config.APIID,
+		currentAPIRevision(apimApi, existing.Properties.ApiRevision), nil, nil)
+	apimApi.Status.Adopted = &apimv1.APIMAPIAdoptedState{
+		AdoptedAt:            adoptedAt,
+		DisplayName:          existing.Properties.DisplayName,
+		Path:                 existing.Properties.Path,
+		ServiceURL:           existing.Properties.ServiceURL,
+		SubscriptionRequired: existing.Properties.SubscriptionRequired,
+		APIRevision:          existing.Properties.ApiRevision,
+	}
+	apimeta.SetStatusCondition(&apimApi.Status.Conditions, metav1.Condition{
+		Type:               conditionTypeAdopted,
+		Status:             metav1.ConditionTrue,
+		Reason:             conditionReasonAdoptedAPI,
+		Message:            "Existing API settings were read from APIM without importing",
+		ObservedGeneration: apimApi.Generation,
+	})
+	setReadyCondition(apimApi, metav1.ConditionTrue, conditionReasonAdoptedAPI, "Existing API was adopted from APIM")
+	if err := applyAPIMAPIStatus(ctx, r.Client, apimApi); err != nil {
+		logger.Error(err, "⚠️ Failed to patch APIMAPI status", "apiID", config.APIID)
+		return ctrl.Result{}, err
+	}
+
+	statusBatch.update(func(status *apimv1.APIMAPIDeploymentStatus) {
+		setStatus(status)
+		status.Phase = apimDeploymentPhaseAdopted
+		status.Status = "OK"
+		status.Message = "Adopted existing API without importing; set adoptionPolicy to Overwrite to manage it"
+		status.LastError = ""
+	})
+
+	logger.Info("🤝 Adopted existing API from APIM",
+		"apiID", config.APIID,
+		"path", existing.Properties.Path,
+		"serviceUrl", existing.Properties.ServiceURL,
+	)
+	return ctrl.Result{}, nil
+}
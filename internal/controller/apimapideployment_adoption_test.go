@@ -0,0 +1,59 @@
+package controller
+
+import (
+	"testing"
+
+	apimv1 "github.com/hedinit/azure-apim-operator/api/v1"
+)
+
+func TestRequiresAdoptionCheck(t *testing.T) {
+	tests := []struct {
+		name       string
+		deployment apimv1.APIMAPIDeployment
+		want       bool
+	}{
+		{
+			name:       "overwrite policy skips the check",
+			deployment: apimv1.APIMAPIDeployment{Spec: apimv1.APIMAPIDeploymentSpec{AdoptionPolicy: apimv1.AdoptionPolicyOverwrite}},
+			want:       false,
+		},
+		{
+			name:       "empty policy behaves like overwrite",
+			deployment: apimv1.APIMAPIDeployment{},
+			want:       false,
+		},
+		{
+			name:       "adopt policy checks on first import",
+			deployment: apimv1.APIMAPIDeployment{Spec: apimv1.APIMAPIDeploymentSpec{AdoptionPolicy: apimv1.AdoptionPolicyAdopt}},
+			want:       true,
+		},
+		{
+			name:       "fail policy checks on first import",
+			deployment: apimv1.APIMAPIDeployment{Spec: apimv1.APIMAPIDeploymentSpec{AdoptionPolicy: apimv1.AdoptionPolicyFail}},
+			want:       true,
+		},
+		{
+			name: "already applied APIs are owned by the operator",
+			deployment: apimv1.APIMAPIDeployment{
+				Spec:   apimv1.APIMAPIDeploymentSpec{AdoptionPolicy: apimv1.AdoptionPolicyFail},
+				Status: apimv1.APIMAPIDeploymentStatus{AppliedHash: "abc"},
+			},
+			want: false,
+		},
+		{
+			name: "revisions are never adopted",
+			deployment: apimv1.APIMAPIDeployment{
+				Spec: apimv1.APIMAPIDeploymentSpec{AdoptionPolicy: apimv1.AdoptionPolicyAdopt, Revision: "2"},
+			},
+			want: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := requiresAdoptionCheck(&tt.deployment); got != tt.want {
+				t.Fatalf("requiresAdoptionCheck() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,41 @@
+package controller
+
+import (
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+
+	apimv1 "github.com/hedinit/azure-apim-operator/api/v1"
+)
+
+// isBackendSwitch reports whether the service URL is the only input that changed since the last import, as when
+// the active blue/green backend is switched. Such a change is applied with a single PATCH of the service URL
+// instead of a re-import, so the API moves to the new backend in one step.
+func isBackendSwitch(applied, desired *apimv1.APIMAPIDeploymentAppliedState) bool {
+	if applied == nil || applied.ServiceURL == desired.ServiceURL {
+		return false
+	}
+	switched := *applied
+	switched.ServiceURL = desired.ServiceURL
+	return equality.Semantic.DeepEqual(&switched, desired)
+}
+
+// recordActiveBackend updates status.backend of apimApi once serviceURL reached APIM. When the active backend
+// changed, the one it replaced is kept in previous and previousServiceUrl.
+func recordActiveBackend(apimApi *apimv1.APIMAPI, serviceURL string, now time.Time) {
+	if apimApi.Spec.Backends == nil {
+		apimApi.Status.Backend = nil
+		return
+	}
+	current := apimApi.Status.Backend
+	if current != nil && current.Active == apimApi.Spec.ActiveBackend && current.ServiceURL == serviceURL {
+		return
+	}
+	next := &apimv1.APIMAPIBackendStatus{Active: apimApi.Spec.ActiveBackend, ServiceURL: serviceURL}
+	if current != nil {
+		next.Previous = current.Active
+		next.PreviousServiceURL = current.ServiceURL
+		next.SwitchedAt = now.UTC().Format(time.RFC3339)
+	}
+	apimApi.Status.Backend = next
+}
@@ -0,0 +1,82 @@
+package controller
+
+import (
+	"testing"
+	"time"
+
+	apimv1 "github.com/hedinit/azure-apim-operator/api/v1"
+)
+
+func TestIsBackendSwitch(t *testing.T) {
+	applied := &apimv1.APIMAPIDeploymentAppliedState{
+		ServiceURL:  "https://blue.example.com",
+		RoutePrefix: "/orders",
+		ProductIDs:  []string{"public"},
+		OpenAPIHash: "abc",
+	}
+	tests := []struct {
+		name    string
+		applied *apimv1.APIMAPIDeploymentAppliedState
+		mutate  func(*apimv1.APIMAPIDeploymentAppliedState)
+		want    bool
+	}{
+		{name: "service URL only", applied: applied, mutate: func(s *apimv1.APIMAPIDeploymentAppliedState) { s.ServiceURL = "https://green.example.com" }, want: true},
+		{name: "unchanged", applied: applied, mutate: func(*apimv1.APIMAPIDeploymentAppliedState) {}},
+		{name: "service URL and definition", applied: applied, mutate: func(s *apimv1.APIMAPIDeploymentAppliedState) {
+			s.ServiceURL = "https://green.example.com"
+			s.OpenAPIHash = "def"
+		}},
+		{name: "service URL and products", applied: applied, mutate: func(s *apimv1.APIMAPIDeploymentAppliedState) {
+			s.ServiceURL = "https://green.example.com"
+			s.ProductIDs = nil
+		}},
+		{name: "never applied", mutate: func(s *apimv1.APIMAPIDeploymentAppliedState) { s.ServiceURL = "https://green.example.com" }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			desired := *applied
+			desired.ProductIDs = append([]string(nil), applied.ProductIDs...)
+			tt.mutate(&desired)
+			if got := isBackendSwitch(tt.applied, &desired); got != tt.want {
+				t.Errorf("isBackendSwitch() = %t, want %t", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRecordActiveBackend(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	apimApi := &apimv1.APIMAPI{Spec: apimv1.APIMAPISpec{
+		Backends:      &apimv1.APIMAPIBackends{Blue: "https://blue.example.com", Green: "https://green.example.com"},
+		ActiveBackend: apimv1.BackendBlue,
+	}}
+
+	recordActiveBackend(apimApi, "https://blue.example.com", now)
+	if got := apimApi.Status.Backend; got == nil || got.Active != apimv1.BackendBlue || got.Previous != "" || got.SwitchedAt != "" {
+		t.Fatalf("first import: status.backend = %+v, want Blue without previous", got)
+	}
+
+	apimApi.Spec.ActiveBackend = apimv1.BackendGreen
+	recordActiveBackend(apimApi, "https://green.example.com", now)
+	want := apimv1.APIMAPIBackendStatus{
+		Active:             apimv1.BackendGreen,
+		ServiceURL:         "https://green.example.com",
+		Previous:           apimv1.BackendBlue,
+		PreviousServiceURL: "https://blue.example.com",
+		SwitchedAt:         "2026-03-01T12:00:00Z",
+	}
+	if got := apimApi.Status.Backend; got == nil || *got != want {
+		t.Fatalf("switch: status.backend = %+v, want %+v", got, want)
+	}
+
+	recordActiveBackend(apimApi, "https://green.example.com", now.Add(time.Hour))
+	if got := apimApi.Status.Backend; *got != want {
+		t.Errorf("re-import: status.backend = %+v, want it unchanged", got)
+	}
+
+	apimApi.Spec.Backends, apimApi.Spec.ActiveBackend = nil, ""
+	recordActiveBackend(apimApi, "https://orders.example.com", now)
+	if apimApi.Status.Backend != nil {
+		t.Errorf("without backends: status.backend = %+v, want nil", apimApi.Status.Backend)
+	}
+}
@@ -0,0 +1,189 @@
+package controller
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"sync/atomic"
+
+	corev1 "k8s.io/api/core/v1"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apimv1 "github.com/hedinit/azure-apim-operator/api/v1"
+	"github.com/hedinit/azure-apim-operator/internal/openapi"
+)
+
+const (
+	// apimAllowBreakingChangesAnnotation on an APIMAPI lets the definition with the given OpenAPI hash be
+	// imported although it breaks the last imported one.
+	apimAllowBreakingChangesAnnotation = "apim.operator.io/allow-breaking-changes"
+	conditionTypeBreakingChange        = "BreakingChange"
+
+	// importedOpenAPIKey is the binaryData key of the gzip-compressed definition in the copy ConfigMap.
+	importedOpenAPIKey = "openapi.gz"
+	// importedOpenAPIHashAnnotation on the copy ConfigMap holds the OpenAPI hash of the stored definition.
+	importedOpenAPIHashAnnotation = "apim.operator.io/openapi-hash"
+	// maxImportedOpenAPIBytes keeps the compressed copy below the 1 MiB size limit of a ConfigMap.
+	maxImportedOpenAPIBytes = 900 * 1024
+)
+
+// breakingChangeDetection enables the breaking-change gate. See SetBreakingChangeDetection.
+var breakingChangeDetection atomic.Bool
+
+// SetBreakingChangeDetection sets whether a changed OpenAPI definition is compared with the last imported
+// one, which is then kept in a ConfigMap next to each APIMAPIDeployment, and blocked when it removes paths
+// or operations or adds required parameters. It can be changed while the manager runs; deployments imported
+// while it was off have no definition to compare with until their next import.
+func SetBreakingChangeDetection(enabled bool) {
+	breakingChangeDetection.Store(enabled)
+}
+
+// importedOpenAPIName is the name of the ConfigMap that keeps the last imported definition of deployment.
+func importedOpenAPIName(deployment *apimv1.APIMAPIDeployment) string {
+	return deployment.Name + "-openapi"
+}
+
+// breakingChangesAllowed reports whether the APIMAPI allows importing the definition with openAPIHash
+// despite its breaking changes.
+func breakingChangesAllowed(apimApi *apimv1.APIMAPI, openAPIHash string) bool {
+	return apimApi.Annotations[apimAllowBreakingChangesAnnotation] == openAPIHash
+}
+
+// requiresBreakingChangeCheck reports whether the definition with openAPIHash replaces a different
+// definition that was imported before.
+func requiresBreakingChangeCheck(deployment *apimv1.APIMAPIDeployment, openAPIHash string) bool {
+	applied := deployment.Status.AppliedState
+	return breakingChangeDetection.Load() && applied != nil && applied.OpenAPIHash != "" && applied.OpenAPIHash != openAPIHash
+}
+
+// breakingChanges lists the breaking changes of content against the last imported definition of deployment.
+// Without a stored copy of that definition, or when either one can't be parsed, nothing is reported, so the
+// gate never blocks an import it can't reason about.
+func (r *APIMAPIDeploymentReconciler) breakingChanges(ctx context.Context, deployment *apimv1.APIMAPIDeployment, content []byte) []string {
+	logger := ctrl.Log.WithName("apimapideployment_controller")
+	previous, err := r.loadImportedOpenAPI(ctx, deployment)
+	if err != nil {
+		logger.Error(err, "⚠️ Failed to load last imported OpenAPI definition; skipping breaking-change check", "apiID", deployment.Spec.APIID)
+		return nil
+	}
+	if previous == nil {
+		logger.Info("ℹ️ No copy of the last imported OpenAPI definition; skipping breaking-change check", "apiID", deployment.Spec.APIID)
+		return nil
+	}
+	changes, err := openapi.BreakingChanges(previous, content)
+	if err != nil {
+		logger.Error(err, "⚠️ Failed to compare OpenAPI definitions; skipping breaking-change check", "apiID", deployment.Spec.APIID)
+		return nil
+	}
+	return changes
+}
+
+// loadImportedOpenAPI returns the stored copy of the last imported definition of deployment, or nil when
+// there is none or it is not the definition in status.appliedState.
+func (r *APIMAPIDeploymentReconciler) loadImportedOpenAPI(ctx context.Context, deployment *apimv1.APIMAPIDeployment) ([]byte, error) {
+	// The ConfigMap lives next to the deployment, so the API reader of the management cluster only applies to its own deployments.
+	reader := client.Reader(r.Client)
+	if r.APIReader != nil && r.Hub == nil {
+		reader = r.APIReader
+	}
+	var configMap corev1.ConfigMap
+	if err := reader.Get(ctx, client.ObjectKey{Name: importedOpenAPIName(deployment), Namespace: deployment.Namespace}, &configMap); err != nil {
+		return nil, client.IgnoreNotFound(err)
+	}
+	if configMap.Annotations[importedOpenAPIHashAnnotation] != deployment.Status.AppliedState.OpenAPIHash {
+		return nil, nil
+	}
+	compressed, ok := configMap.BinaryData[importedOpenAPIKey]
+	if !ok {
+		return nil, nil
+	}
+	zr, err := gzip.NewReader(bytes.NewReader(compressed))
+	if err != nil {
+		return nil, fmt.Errorf("decompress ConfigMap %s: %w", configMap.Name, err)
+	}
+	content, err := io.ReadAll(zr)
+	if err != nil {
+		return nil, fmt.Errorf("decompress ConfigMap %s: %w", configMap.Name, err)
+	}
+	return content, nil
+}
+
+// storeImportedOpenAPI keeps content, the definition with openAPIHash that was just imported, in a ConfigMap
+// owned by deployment, so the next changed definition can be compared with it. A definition too large for
+// a ConfigMap is not stored, which disables the check for its successor.
+func (r *APIMAPIDeploymentReconciler) storeImportedOpenAPI(ctx context.Context, deployment *apimv1.APIMAPIDeployment, content []byte, openAPIHash string) error {
+	var compressed bytes.Buffer
+	zw := gzip.NewWriter(&compressed)
+	if _, err := zw.Write(content); err != nil {
+		return err
+	}
+	if err := zw.Close(); err != nil {
+		return err
+	}
+	if compressed.Len() > maxImportedOpenAPIBytes {
+		ctrl.Log.WithName("apimapideployment_controller").Info("ℹ️ OpenAPI definition too large to keep for the breaking-change check",
+			"apiID", deployment.Spec.APIID, "compressedBytes", compressed.Len())
+		return nil
+	}
+	configMap := &corev1.ConfigMap{
+		TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "ConfigMap"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            importedOpenAPIName(deployment),
+			Namespace:       deployment.Namespace,
+			Labels:          map[string]string{"app.kubernetes.io/managed-by": "azure-apim-operator"},
+			Annotations:     map[string]string{importedOpenAPIHashAnnotation: openAPIHash},
+			OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(deployment, apimv1.GroupVersion.WithKind("APIMAPIDeployment"))},
+		},
+		BinaryData: map[string][]byte{importedOpenAPIKey: compressed.Bytes()},
+	}
+	if err := r.Patch(ctx, configMap, client.Apply, client.FieldOwner(apimFieldManager), client.ForceOwnership); err != nil {
+		return fmt.Errorf("apply ConfigMap %s: %w", configMap.Name, err)
+	}
+	return nil
+}
+
+// blockBreakingChanges stops the import of a definition with breaking changes until the APIMAPI allows it.
+// The APIMAPI gets a True BreakingChange condition listing the changes and is no longer Ready.
+func (r *APIMAPIDeploymentReconciler) blockBreakingChanges(
+	ctx context.Context,
+	deployment *apimv1.APIMAPIDeployment,
+	statusBatch *deploymentStatusBatch,
+	apimApi *apimv1.APIMAPI,
+	openAPIHash string,
+	changes []string,
+	setStatus func(*apimv1.APIMAPIDeploymentStatus),
+) (ctrl.Result, error) {
+	ctrl.Log.WithName("apimapideployment_controller").Info("🛑 OpenAPI definition has breaking changes; import blocked",
+		"apiID", deployment.Spec.APIID, "openAPIHash", openAPIHash, "changes", changes)
+
+	message := fmt.Sprintf("OpenAPI definition %s breaks the imported one: %s; annotate APIMAPI %s with %s=%s to import it",
+		openAPIHash, strings.Join(changes, "; "), apimApi.Name, apimAllowBreakingChangesAnnotation, openAPIHash)
+	apimeta.SetStatusCondition(&apimApi.Status.Conditions, metav1.Condition{
+		Type:               conditionTypeBreakingChange,
+		Status:             metav1.ConditionTrue,
+		Reason:             apimv1.ReasonBreakingChange,
+		Message:            message,
+		ObservedGeneration: apimApi.Generation,
+	})
+	r.markNotReady(ctx, apimApi, apimv1.ReasonBreakingChange, message)
+
+	statusBatch.update(func(status *apimv1.APIMAPIDeploymentStatus) {
+		setStatus(status)
+		status.Phase = phaseError
+		status.Status = phaseError
+		status.Message = message
+		status.LastError = message
+	})
+	return ctrl.Result{}, nil
+}
+
+// clearBreakingChange removes the BreakingChange condition once a definition was imported.
+func clearBreakingChange(apimApi *apimv1.APIMAPI) {
+	apimeta.RemoveStatusCondition(&apimApi.Status.Conditions, conditionTypeBreakingChange)
+}
@@ -0,0 +1,98 @@
+package controller
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	apimv1 "github.com/hedinit/azure-apim-operator/api/v1"
+)
+
+func TestRequiresBreakingChangeCheck(t *testing.T) {
+	defer SetBreakingChangeDetection(false)
+	deployment := &apimv1.APIMAPIDeployment{Status: apimv1.APIMAPIDeploymentStatus{
+		AppliedState: &apimv1.APIMAPIDeploymentAppliedState{OpenAPIHash: "abc"},
+	}}
+
+	if requiresBreakingChangeCheck(deployment, "def") {
+		t.Error("disabled: requiresBreakingChangeCheck() = true, want false")
+	}
+	SetBreakingChangeDetection(true)
+	if !requiresBreakingChangeCheck(deployment, "def") {
+		t.Error("changed definition: requiresBreakingChangeCheck() = false, want true")
+	}
+	if requiresBreakingChangeCheck(deployment, "abc") {
+		t.Error("unchanged definition: requiresBreakingChangeCheck() = true, want false")
+	}
+	if requiresBreakingChangeCheck(&apimv1.APIMAPIDeployment{}, "def") {
+		t.Error("first import: requiresBreakingChangeCheck() = true, want false")
+	}
+}
+
+func TestBreakingChangesAllowed(t *testing.T) {
+	apimApi := &apimv1.APIMAPI{ObjectMeta: metav1.ObjectMeta{
+		Annotations: map[string]string{apimAllowBreakingChangesAnnotation: "def"},
+	}}
+	if !breakingChangesAllowed(apimApi, "def") {
+		t.Error("breakingChangesAllowed(def) = false, want true")
+	}
+	if breakingChangesAllowed(apimApi, "ghi") {
+		t.Error("breakingChangesAllowed(ghi) = true, want false")
+	}
+}
+
+func TestBreakingChangesAgainstStoredCopy(t *testing.T) {
+	var compressed bytes.Buffer
+	zw := gzip.NewWriter(&compressed)
+	if _, err := zw.Write([]byte(`{"openapi": "3.0.1", "paths": {"/orders": {"get": {}, "delete": {}}}}`)); err != nil {
+		t.Fatal(err)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(&corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "orders-openapi",
+			Namespace:   "team-a",
+			Annotations: map[string]string{importedOpenAPIHashAnnotation: "abc"},
+		},
+		BinaryData: map[string][]byte{importedOpenAPIKey: compressed.Bytes()},
+	}).Build()
+	r := &APIMAPIDeploymentReconciler{Client: c, APIReader: c}
+	current := []byte(`{"openapi": "3.0.1", "paths": {"/orders": {"get": {}}}}`)
+
+	tests := []struct {
+		name        string
+		namespace   string
+		appliedHash string
+		want        int
+	}{
+		{name: "stored copy of the applied definition", namespace: "team-a", appliedHash: "abc", want: 1},
+		{name: "stored copy of another definition", namespace: "team-a", appliedHash: "xyz"},
+		{name: "no stored copy", namespace: "team-b", appliedHash: "abc"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			deployment := &apimv1.APIMAPIDeployment{
+				ObjectMeta: metav1.ObjectMeta{Name: "orders", Namespace: tt.namespace},
+				Status: apimv1.APIMAPIDeploymentStatus{
+					AppliedState: &apimv1.APIMAPIDeploymentAppliedState{OpenAPIHash: tt.appliedHash},
+				},
+			}
+			if got := r.breakingChanges(context.Background(), deployment, current); len(got) != tt.want {
+				t.Errorf("breakingChanges() = %q, want %d changes", got, tt.want)
+			}
+		})
+	}
+}
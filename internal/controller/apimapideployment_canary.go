@@ -0,0 +1,133 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apimv1 "github.com/hedinit/azure-apim-operator/api/v1"
+)
+
+const (
+	// Values of status.canary.result.
+	canaryResultPromoted   = "Promoted"
+	canaryResultRolledBack = "RolledBack"
+
+	defaultSmokeCheckStatus   = http.StatusOK
+	defaultSmokeCheckAttempts = 3
+	defaultSmokeCheckTimeout  = 10 * time.Second
+
+	// subscriptionKeyHeader carries the subscription key of smoke checks of APIs that require a subscription.
+	subscriptionKeyHeader = "Ocp-Apim-Subscription-Key"
+)
+
+// smokeCheckRetryDelay is the pause between failed smoke check attempts.
+var smokeCheckRetryDelay = 5 * time.Second
+
+// canaryRolloutKey identifies the rollout of desiredHash by the ReplicaSet rollout that signaled the deployment.
+func canaryRolloutKey(deployment *apimv1.APIMAPIDeployment, desiredHash string) string {
+	return desiredHash + "/" + deployment.Annotations[apimDeploymentRolloutHashAnnotation]
+}
+
+// isRolledBackCanary reports whether the canary of the rollout with rolloutKey already failed its smoke check.
+// Importing it again would only add another failing revision.
+func isRolledBackCanary(deployment *apimv1.APIMAPIDeployment, rolloutKey string) bool {
+	canary := deployment.Status.Canary
+	return deployment.Spec.Canary != nil && canary != nil && canary.Result == canaryResultRolledBack && canary.RolloutKey == rolloutKey
+}
+
+// smokeCheckURL returns the URL of path below revision of the API with routePrefix on the gateway apiHost.
+func smokeCheckURL(apiHost, routePrefix, revision, path string) string {
+	return fmt.Sprintf("https://%s%s;rev=%s%s", apiHost, routePrefix, revision, path)
+}
+
+// runSmokeCheck requests url until it answers with the expected status of check or the attempts run out.
+func runSmokeCheck(ctx context.Context, url string, check *apimv1.APIMAPISmokeCheck, subscriptionKey string) error {
+	expected := defaultSmokeCheckStatus
+	if check.ExpectedStatus != 0 {
+		expected = int(check.ExpectedStatus)
+	}
+	attempts := defaultSmokeCheckAttempts
+	if check.Attempts > 0 {
+		attempts = int(check.Attempts)
+	}
+	httpClient := &http.Client{Timeout: defaultSmokeCheckTimeout}
+	if check.Timeout != nil && check.Timeout.Duration > 0 {
+		httpClient.Timeout = check.Timeout.Duration
+	}
+
+	var err error
+	for attempt := 1; attempt <= attempts; attempt++ {
+		if err = probeRevision(ctx, httpClient, url, expected, subscriptionKey); err == nil {
+			return nil
+		}
+		if attempt < attempts {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(smokeCheckRetryDelay):
+			}
+		}
+	}
+	return fmt.Errorf("smoke check failed after %d attempts: %w", attempts, err)
+}
+
+// probeRevision sends one smoke check request.
+func probeRevision(ctx context.Context, httpClient *http.Client, url string, expected int, subscriptionKey string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return fmt.Errorf("build smoke check request: %w", err)
+	}
+	if subscriptionKey != "" {
+		req.Header.Set(subscriptionKeyHeader, subscriptionKey)
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	_, _ = io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode != expected {
+		return fmt.Errorf("GET %s answered %s, want %d", url, resp.Status, expected)
+	}
+	return nil
+}
+
+// smokeCheckSubscriptionKey reads the subscription key of the smoke check of deployment, if it has one.
+func (r *APIMAPIDeploymentReconciler) smokeCheckSubscriptionKey(ctx context.Context, deployment *apimv1.APIMAPIDeployment) (string, error) {
+	ref := deployment.Spec.Canary.SmokeCheck.SubscriptionKeySecretRef
+	if ref == nil {
+		return "", nil
+	}
+	// The Secret lives next to the APIMAPI, so the API reader of the management cluster only applies to its own APIMAPIs.
+	reader := client.Reader(r.Client)
+	if r.APIReader != nil && r.Hub == nil {
+		reader = r.APIReader
+	}
+	var secret corev1.Secret
+	if err := reader.Get(ctx, client.ObjectKey{Name: ref.Name, Namespace: deployment.Namespace}, &secret); err != nil {
+		return "", fmt.Errorf("read subscription key Secret %s: %w", ref.Name, err)
+	}
+	key := strings.TrimSpace(string(secret.Data[ref.Key]))
+	if key == "" {
+		return "", fmt.Errorf("key %q of Secret %s is empty", ref.Key, ref.Name)
+	}
+	return key, nil
+}
+
+// checkCanary runs the smoke check of the canary revision of deployment on the gateway apiHost.
+func (r *APIMAPIDeploymentReconciler) checkCanary(ctx context.Context, deployment *apimv1.APIMAPIDeployment, apiHost string) error {
+	subscriptionKey, err := r.smokeCheckSubscriptionKey(ctx, deployment)
+	if err != nil {
+		return err
+	}
+	check := &deployment.Spec.Canary.SmokeCheck
+	url := smokeCheckURL(apiHost, deployment.Spec.RoutePrefix, deployment.Spec.Revision, check.Path)
+	return runSmokeCheck(ctx, url, check, subscriptionKey)
+}
@@ -0,0 +1,116 @@
+package controller
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	apimv1 "github.com/hedinit/azure-apim-operator/api/v1"
+)
+
+func TestSmokeCheckURL(t *testing.T) {
+	got := smokeCheckURL("contoso.azure-api.net", "/orders", "4", "/health")
+	if want := "https://contoso.azure-api.net/orders;rev=4/health"; got != want {
+		t.Errorf("smokeCheckURL() = %q, want %q", got, want)
+	}
+}
+
+func TestRunSmokeCheck(t *testing.T) {
+	smokeCheckRetryDelay = time.Millisecond
+	t.Cleanup(func() { smokeCheckRetryDelay = 5 * time.Second })
+
+	tests := []struct {
+		name      string
+		check     apimv1.APIMAPISmokeCheck
+		answers   []int
+		wantErr   bool
+		wantCalls int
+	}{
+		{name: "passes", answers: []int{http.StatusOK}, wantCalls: 1},
+		{name: "passes after a retry", answers: []int{http.StatusBadGateway, http.StatusOK}, wantCalls: 2},
+		{name: "fails after all attempts", answers: []int{http.StatusBadGateway}, wantErr: true, wantCalls: defaultSmokeCheckAttempts},
+		{name: "custom status and attempts", check: apimv1.APIMAPISmokeCheck{ExpectedStatus: http.StatusNoContent, Attempts: 1},
+			answers: []int{http.StatusOK}, wantErr: true, wantCalls: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if got := r.Header.Get(subscriptionKeyHeader); got != "key-1" {
+					t.Errorf("subscription key header = %q, want key-1", got)
+				}
+				w.WriteHeader(tt.answers[min(calls, len(tt.answers)-1)])
+				calls++
+			}))
+			defer server.Close()
+
+			err := runSmokeCheck(context.Background(), server.URL+"/health", &tt.check, "key-1")
+			if (err != nil) != tt.wantErr {
+				t.Errorf("runSmokeCheck() error = %v, wantErr %t", err, tt.wantErr)
+			}
+			if calls != tt.wantCalls {
+				t.Errorf("runSmokeCheck() sent %d requests, want %d", calls, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestIsRolledBackCanary(t *testing.T) {
+	deployment := &apimv1.APIMAPIDeployment{
+		ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{apimDeploymentRolloutHashAnnotation: "rollout-1"}},
+		Spec:       apimv1.APIMAPIDeploymentSpec{Canary: &apimv1.APIMAPICanary{SmokeCheck: apimv1.APIMAPISmokeCheck{Path: "/health"}}},
+	}
+	key := canaryRolloutKey(deployment, "desired-1")
+	deployment.Status.Canary = &apimv1.APIMAPIDeploymentCanaryStatus{Revision: "3", Result: canaryResultRolledBack, RolloutKey: key}
+
+	if !isRolledBackCanary(deployment, key) {
+		t.Error("isRolledBackCanary() = false for the rolled back rollout")
+	}
+	if isRolledBackCanary(deployment, canaryRolloutKey(deployment, "desired-2")) {
+		t.Error("isRolledBackCanary() = true after a spec change")
+	}
+	deployment.Annotations[apimDeploymentRolloutHashAnnotation] = "rollout-2"
+	if isRolledBackCanary(deployment, canaryRolloutKey(deployment, "desired-1")) {
+		t.Error("isRolledBackCanary() = true for a new rollout")
+	}
+	deployment.Status.Canary.Result = canaryResultPromoted
+	if isRolledBackCanary(deployment, key) {
+		t.Error("isRolledBackCanary() = true for a promoted canary")
+	}
+}
+
+func TestSmokeCheckSubscriptionKey(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "smoke", Namespace: "team-a"},
+		Data:       map[string][]byte{"key": []byte("key-1\n")},
+	}).Build()
+	r := &APIMAPIDeploymentReconciler{Client: c, APIReader: c}
+	deployment := &apimv1.APIMAPIDeployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "orders", Namespace: "team-a"},
+		Spec: apimv1.APIMAPIDeploymentSpec{Canary: &apimv1.APIMAPICanary{SmokeCheck: apimv1.APIMAPISmokeCheck{
+			Path:                     "/health",
+			SubscriptionKeySecretRef: &apimv1.SecretKeyRef{Name: "smoke", Key: "key"},
+		}}},
+	}
+
+	key, err := r.smokeCheckSubscriptionKey(context.Background(), deployment)
+	if err != nil || key != "key-1" {
+		t.Errorf("smokeCheckSubscriptionKey() = %q, %v, want key-1", key, err)
+	}
+	deployment.Spec.Canary.SmokeCheck.SubscriptionKeySecretRef.Key = "missing"
+	if _, err := r.smokeCheckSubscriptionKey(context.Background(), deployment); err == nil {
+		t.Error("smokeCheckSubscriptionKey() error = nil for a missing key")
+	}
+}
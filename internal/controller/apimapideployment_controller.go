@@ -19,13 +19,19 @@ package controller
 import (
 	"context"
 	"fmt"
-	"io"
 	"net/http"
 	"os"
+	"sync"
 	"time"
 
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+	"go.opentelemetry.io/otel/trace"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/event"
@@ -33,7 +39,11 @@ import (
 
 	apimv1 "github.com/hedinit/azure-apim-operator/api/v1"
 	"github.com/hedinit/azure-apim-operator/internal/apim"
+	"github.com/hedinit/azure-apim-operator/internal/cloudevents"
 	"github.com/hedinit/azure-apim-operator/internal/identity"
+	"github.com/hedinit/azure-apim-operator/internal/notify"
+	"github.com/hedinit/azure-apim-operator/internal/openapi"
+	"github.com/hedinit/azure-apim-operator/internal/redact"
 )
 
 // APIMAPIDeploymentReconciler reconciles APIMAPIDeployment custom resources.
@@ -47,12 +57,36 @@ import (
 // 7. Persisting deployment status so reconciliation progress is inspectable
 type APIMAPIDeploymentReconciler struct {
 	client.Client
-	Scheme *runtime.Scheme
+	// Hub is the client of the management cluster when the reconciler runs for a remote cluster
+	// registered with an APIMCluster. Nil means Client is the management cluster.
+	Hub client.Client
+	// APIReader reads the webhook URL Secrets of notifications directly from the API server, so the
+	// operator doesn't cache every Secret of the cluster.
+	APIReader client.Reader
+	Scheme    *runtime.Scheme
+	// Recorder records Events on the APIMAPI of a failed deployment. Nil disables them.
+	Recorder record.EventRecorder
+	// APIM sends the Azure Management API requests. Nil sends them to the public endpoint.
+	APIM *apim.Client
+	// Tokens provides the Azure Management API token shared by all reconcilers. Nil asks Azure AD on
+	// every reconcile.
+	Tokens identity.TokenProvider
+
+	// ReplicaSetDebounce is the quiet period after the last ReplicaSet signal before an import starts.
+	// Zero disables debouncing.
+	ReplicaSetDebounce time.Duration
+
+	// verifiedSinceStartup holds the UIDs of deployments already checked against Azure in this process.
+	verifiedSinceStartup sync.Map
+	// openAPIValidators holds the *openAPIValidators of each deployment's last downloaded definition, by UID.
+	openAPIValidators sync.Map
 }
 
 // +kubebuilder:rbac:groups=apim.operator.io,resources=apimapideployments,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=apim.operator.io,resources=apimapideployments/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=apim.operator.io,resources=apimapideployments/finalizers,verbs=update
+// +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
+// +kubebuilder:rbac:groups="",resources=configmaps,verbs=get;create;patch
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
@@ -63,15 +97,30 @@ type APIMAPIDeploymentReconciler struct {
 //
 // For more details, check Reconcile and its Result here:
 // - https://pkg.go.dev/sigs.k8s.io/controller-runtime@v0.20.4/pkg/reconcile
-func (r *APIMAPIDeploymentReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+func (r *APIMAPIDeploymentReconciler) Reconcile(ctx context.Context, req ctrl.Request) (result ctrl.Result, err error) {
 	logger := ctrl.Log.WithName("apimapideployment_controller")
 
 	// Fetch the APIMAPIDeployment resource that triggered this reconciliation.
 	var deployment apimv1.APIMAPIDeployment
 	if err := r.Get(ctx, req.NamespacedName, &deployment); err != nil {
 		logger.Info("ℹ️ Unable to fetch APIMAPIDeployment")
+		if apierrors.IsNotFound(err) {
+			forgetSyncMetrics(kindAPIMAPIDeployment, req.Namespace, req.Name)
+		}
 		return ctrl.Result{}, client.IgnoreNotFound(err)
 	}
+	setSpanAPIAttributes(ctx, deployment.Spec.APIMService, deployment.Spec.APIID)
+
+	// Status changes are collected during the pass and patched once when it returns.
+	statusBatch := newDeploymentStatusBatch(r.Client, &deployment)
+	defer func() {
+		if statusErr := statusBatch.flush(ctx); statusErr != nil {
+			logger.Error(statusErr, "❌ Failed to patch APIMAPIDeployment status", "apiID", deployment.Spec.APIID)
+			if err == nil {
+				result, err = ctrl.Result{}, statusErr
+			}
+		}
+	}()
 	logger.Info("🧩 Loaded APIMAPIDeployment",
 		"name", deployment.Name,
 		"namespace", deployment.Namespace,
@@ -98,13 +147,50 @@ func (r *APIMAPIDeploymentReconciler) Reconcile(ctx context.Context, req ctrl.Re
 		logger.Error(err, "❌ Failed to get APIMAPI", "apiID", deployment.Spec.APIID, "apimApiName", apimAPIName)
 		return ctrl.Result{}, err
 	}
+	if !apimApi.DeletionTimestamp.IsZero() {
+		// The APIMAPI controller is deleting the API from APIM; importing it again would orphan it.
+		logger.Info("🗑️ APIMAPI is being deleted, skipping import", "apiID", deployment.Spec.APIID, "apimApiName", apimAPIName)
+		return ctrl.Result{}, nil
+	}
 	logger.Info("🔗 Found APIMAPI for deployment", "apimapi", apimApi.Name, "status", apimApi.Status.Status, "apiID", deployment.Spec.APIID, "apimApiName", apimAPIName)
+	r.markProgressing(ctx, &apimApi)
+
+	// Reject malformed revisions before calling APIM, which only answers them with a generic 400.
+	// Resources created before the CRD validated revisions can still carry one.
+	if err := validateRevision(deployment.Spec.Revision); err != nil {
+		logger.Info("🛑 Invalid revision", "apiID", deployment.Spec.APIID, "revision", deployment.Spec.Revision)
+		r.markNotReady(ctx, &apimApi, apimv1.ReasonSpecInvalid, failureMessage("Invalid revision", err))
+		statusBatch.update(func(status *apimv1.APIMAPIDeploymentStatus) {
+			status.Phase = phaseError
+			status.Status = phaseError
+			status.Message = "Invalid revision"
+			status.LastError = err.Error()
+			status.LastErrorDetails = &apimv1.APIMAPIDeploymentErrorDetails{
+				Reason:     apimv1.ReasonSpecInvalid,
+				OccurredAt: time.Now().UTC().Format(time.RFC3339),
+			}
+		})
+		// A spec change triggers the next reconcile; retrying the same revision cannot succeed.
+		return ctrl.Result{}, nil
+	}
+
+	// Coalesce rapid ReplicaSet transitions of a rolling update into one import of the final state.
+	if wait := replicaSetDebounceRemaining(&deployment, r.ReplicaSetDebounce, time.Now()); wait > 0 {
+		statusBatch.update(func(status *apimv1.APIMAPIDeploymentStatus) {
+			status.Phase = apimDeploymentPhaseDebouncing
+			status.Status = apimDeploymentStatusPending
+			status.Message = "Waiting for ReplicaSet changes to settle before importing"
+			status.LastError = ""
+		})
+		logger.Info("⏱️ Debouncing ReplicaSet signal", "apiID", deployment.Spec.APIID, "wait", wait)
+		return ctrl.Result{RequeueAfter: wait}, nil
+	}
 
 	attemptTime := time.Now().UTC().Format(time.RFC3339)
 	matchedReplicaSets, err := findMatchingReplicaSetsForAPIMAPI(ctx, r.Client, &apimApi)
 	if err != nil {
 		logger.Error(err, "❌ Failed to match ReplicaSets for APIMAPI", "apiID", deployment.Spec.APIID, "apimApiName", apimAPIName)
-		if statusErr := updateAPIMAPIDeploymentStatus(ctx, r.Client, &deployment, func(status *apimv1.APIMAPIDeploymentStatus) {
+		statusBatch.update(func(status *apimv1.APIMAPIDeploymentStatus) {
 			status.Phase = phaseError
 			status.Status = phaseError
 			status.Message = "Failed to resolve matching ReplicaSets"
@@ -112,16 +198,14 @@ func (r *APIMAPIDeploymentReconciler) Reconcile(ctx context.Context, req ctrl.Re
 			status.LastAttemptAt = attemptTime
 			status.ObservedGeneration = apimApi.Generation
 			status.MatchedReplicaSets = nil
-		}); statusErr != nil {
-			return ctrl.Result{}, statusErr
-		}
+		})
 		return ctrl.Result{}, err
 	}
 
 	matchedReplicaSetNames := matchedReplicaSetNames(matchedReplicaSets)
 	if len(matchedReplicaSets) == 0 {
 		message := fmt.Sprintf("Selector matched 0 ReplicaSets in namespace %s", deployment.Namespace)
-		if statusErr := updateAPIMAPIDeploymentStatus(ctx, r.Client, &deployment, func(status *apimv1.APIMAPIDeploymentStatus) {
+		statusBatch.update(func(status *apimv1.APIMAPIDeploymentStatus) {
 			status.Phase = apimDeploymentPhaseWaitingForMatch
 			status.Status = apimDeploymentStatusPending
 			status.Message = message
@@ -129,9 +213,7 @@ func (r *APIMAPIDeploymentReconciler) Reconcile(ctx context.Context, req ctrl.Re
 			status.LastAttemptAt = attemptTime
 			status.ObservedGeneration = apimApi.Generation
 			status.MatchedReplicaSets = nil
-		}); statusErr != nil {
-			return ctrl.Result{}, statusErr
-		}
+		})
 		logger.Info("⏳ Waiting for selector match", "apiID", deployment.Spec.APIID, "apimApiName", apimAPIName)
 		return ctrl.Result{}, nil
 	}
@@ -139,7 +221,7 @@ func (r *APIMAPIDeploymentReconciler) Reconcile(ctx context.Context, req ctrl.Re
 	readyPod, err := findReadyPodForReplicaSets(ctx, r.Client, matchedReplicaSets)
 	if err != nil {
 		logger.Error(err, "❌ Failed to inspect matched ReplicaSet pods", "apiID", deployment.Spec.APIID, "apimApiName", apimAPIName)
-		if statusErr := updateAPIMAPIDeploymentStatus(ctx, r.Client, &deployment, func(status *apimv1.APIMAPIDeploymentStatus) {
+		statusBatch.update(func(status *apimv1.APIMAPIDeploymentStatus) {
 			status.Phase = phaseError
 			status.Status = phaseError
 			status.Message = "Failed to inspect matched ReplicaSet pods"
@@ -147,15 +229,13 @@ func (r *APIMAPIDeploymentReconciler) Reconcile(ctx context.Context, req ctrl.Re
 			status.LastAttemptAt = attemptTime
 			status.ObservedGeneration = apimApi.Generation
 			status.MatchedReplicaSets = matchedReplicaSetNames
-		}); statusErr != nil {
-			return ctrl.Result{}, statusErr
-		}
+		})
 		return ctrl.Result{}, err
 	}
 
 	if readyPod == nil {
 		message := fmt.Sprintf("Matched ReplicaSets %v but no ready pods were found yet", matchedReplicaSetNames)
-		if statusErr := updateAPIMAPIDeploymentStatus(ctx, r.Client, &deployment, func(status *apimv1.APIMAPIDeploymentStatus) {
+		statusBatch.update(func(status *apimv1.APIMAPIDeploymentStatus) {
 			status.Phase = apimDeploymentPhaseWaitingForReadyPod
 			status.Status = apimDeploymentStatusPending
 			status.Message = message
@@ -163,9 +243,7 @@ func (r *APIMAPIDeploymentReconciler) Reconcile(ctx context.Context, req ctrl.Re
 			status.LastAttemptAt = attemptTime
 			status.ObservedGeneration = apimApi.Generation
 			status.MatchedReplicaSets = matchedReplicaSetNames
-		}); statusErr != nil {
-			return ctrl.Result{}, statusErr
-		}
+		})
 		logger.Info("⏳ Waiting for ready pod", "apiID", deployment.Spec.APIID, "matchedReplicaSets", matchedReplicaSetNames)
 		return ctrl.Result{}, nil
 	}
@@ -173,7 +251,7 @@ func (r *APIMAPIDeploymentReconciler) Reconcile(ctx context.Context, req ctrl.Re
 	operatorNamespace, err := getOperatorNamespace()
 	if err != nil {
 		logger.Error(err, "❌ Failed to get operator namespace", "apiID", deployment.Spec.APIID)
-		if statusErr := updateAPIMAPIDeploymentStatus(ctx, r.Client, &deployment, func(status *apimv1.APIMAPIDeploymentStatus) {
+		statusBatch.update(func(status *apimv1.APIMAPIDeploymentStatus) {
 			status.Phase = phaseError
 			status.Status = phaseError
 			status.Message = "Failed to resolve operator namespace"
@@ -181,20 +259,18 @@ func (r *APIMAPIDeploymentReconciler) Reconcile(ctx context.Context, req ctrl.Re
 			status.LastAttemptAt = attemptTime
 			status.ObservedGeneration = apimApi.Generation
 			status.MatchedReplicaSets = matchedReplicaSetNames
-		}); statusErr != nil {
-			return ctrl.Result{}, statusErr
-		}
+		})
 		return ctrl.Result{}, err
 	}
 
 	var apimService apimv1.APIMService
-	if err := r.Get(ctx, client.ObjectKey{Name: deployment.Spec.APIMService, Namespace: operatorNamespace}, &apimService); err != nil {
+	if err := hubClient(r.Client, r.Hub).Get(ctx, client.ObjectKey{Name: deployment.Spec.APIMService, Namespace: operatorNamespace}, &apimService); err != nil {
 		message := fmt.Sprintf("Referenced APIMService %q was not found in namespace %s", deployment.Spec.APIMService, operatorNamespace)
 		if !apierrors.IsNotFound(err) {
 			message = "Failed to fetch referenced APIMService"
 		}
 		logger.Error(err, "❌ Failed to get APIMService", "apiID", deployment.Spec.APIID, "apimService", deployment.Spec.APIMService)
-		if statusErr := updateAPIMAPIDeploymentStatus(ctx, r.Client, &deployment, func(status *apimv1.APIMAPIDeploymentStatus) {
+		statusBatch.update(func(status *apimv1.APIMAPIDeploymentStatus) {
 			status.Phase = phaseError
 			status.Status = phaseError
 			status.Message = message
@@ -202,11 +278,10 @@ func (r *APIMAPIDeploymentReconciler) Reconcile(ctx context.Context, req ctrl.Re
 			status.LastAttemptAt = attemptTime
 			status.ObservedGeneration = apimApi.Generation
 			status.MatchedReplicaSets = matchedReplicaSetNames
-		}); statusErr != nil {
-			return ctrl.Result{}, statusErr
-		}
+		})
 		return ctrl.Result{RequeueAfter: 60 * time.Second}, nil
 	}
+	observeAPIMService(ctx, &apimService)
 
 	if deployment.Spec.Subscription != apimService.Spec.Subscription || deployment.Spec.ResourceGroup != apimService.Spec.ResourceGroup {
 		specPatch := client.MergeFrom(deployment.DeepCopy())
@@ -221,7 +296,7 @@ func (r *APIMAPIDeploymentReconciler) Reconcile(ctx context.Context, req ctrl.Re
 	// Step 1: Fetch the OpenAPI definition from the specified URL.
 	// This uses retry logic to handle transient network failures.
 	openApiURL := deployment.Spec.OpenAPIDefinitionURL
-	logger.Info("📡 Fetching OpenAPI definition", "url", openApiURL, "apiID", deployment.Spec.APIID)
+	logger.Info("📡 Fetching OpenAPI definition", "url", redact.String(openApiURL), "apiID", deployment.Spec.APIID)
 	// resp, err := http.Get(openApiURL)
 	// if err != nil {
 	// 	logger.Error(err, "❌ Failed to fetch OpenAPI definition")
@@ -235,28 +310,56 @@ func (r *APIMAPIDeploymentReconciler) Reconcile(ctx context.Context, req ctrl.Re
 	// 	return ctrl.Result{}, err
 	// }
 
-	// Fetch the OpenAPI definition with retry logic to handle transient failures.
-	openApiContent, err := fetchOpenAPIDefinitionWithRetry(openApiURL, 5)
+	// Fetch the OpenAPI definition. Transient failures are retried by requeueing with backoff;
+	// the number of failed attempts is kept in the status.
+	// Ask the server whether the definition changed when it is the only input that could have; a 304
+	// then proves APIM is in sync without downloading the definition.
+	validators := r.conditionalFetchValidators(&deployment, apimService.Spec.Subscription, apimService.Spec.ResourceGroup)
+	fetched, err := fetchOpenAPIDefinition(ctx, openApiURL, validators)
 	if err != nil {
+		failures := deployment.Status.OpenAPIFetchFailures + 1
+		if retryAfter, retry := openAPIFetchRetryAfter(failures); retry {
+			logger.Error(err, "⚠️ Failed to fetch OpenAPI definition; will retry", "apiID", deployment.Spec.APIID,
+				"attempt", failures, "maxAttempts", openAPIFetchMaxAttempts, "retryAfter", retryAfter)
+			statusBatch.update(func(status *apimv1.APIMAPIDeploymentStatus) {
+				status.Message = fmt.Sprintf("Failed to fetch OpenAPI definition (attempt %d of %d); retrying in %s",
+					failures, openAPIFetchMaxAttempts, retryAfter)
+				status.LastError = err.Error()
+				status.OpenAPIFetchFailures = failures
+				status.LastAttemptAt = attemptTime
+			})
+			return ctrl.Result{RequeueAfter: retryAfter}, nil
+		}
+
 		logger.Error(err, "❌ Failed to fetch OpenAPI definition after retries", "apiID", deployment.Spec.APIID)
-		if statusErr := updateAPIMAPIDeploymentStatus(ctx, r.Client, &deployment, func(status *apimv1.APIMAPIDeploymentStatus) {
+		r.markNotReady(ctx, &apimApi, apimv1.ReasonSpecFetchFailed, failureMessage("Failed to fetch OpenAPI definition", err))
+		statusBatch.update(func(status *apimv1.APIMAPIDeploymentStatus) {
 			status.Phase = phaseError
 			status.Status = phaseError
 			status.Message = "Failed to fetch OpenAPI definition after retries"
 			status.LastError = err.Error()
+			status.OpenAPIFetchFailures = 0
 			status.LastAttemptAt = attemptTime
 			status.ObservedGeneration = apimApi.Generation
 			status.MatchedReplicaSets = matchedReplicaSetNames
-		}); statusErr != nil {
-			return ctrl.Result{}, statusErr
-		}
+		})
 		return ctrl.Result{RequeueAfter: 60 * time.Second}, nil
 	}
-	openAPIHash := sha256Hex(openApiContent)
+	statusBatch.update(func(status *apimv1.APIMAPIDeploymentStatus) {
+		status.OpenAPIFetchFailures = 0
+	})
+	openApiContent := fetched.Content
+	var openAPIHash string
+	if fetched.NotModified {
+		openAPIHash = validators.Hash
+	} else {
+		openAPIHash = fetched.Hash
+		r.rememberOpenAPIValidators(&deployment, fetched, openAPIHash)
+	}
 	desiredHash, err := buildDesiredAPIMStateHash(&deployment.Spec, apimService.Spec.Subscription, apimService.Spec.ResourceGroup, openAPIHash)
 	if err != nil {
 		logger.Error(err, "❌ Failed to build desired APIM state hash", "apiID", deployment.Spec.APIID)
-		if statusErr := updateAPIMAPIDeploymentStatus(ctx, r.Client, &deployment, func(status *apimv1.APIMAPIDeploymentStatus) {
+		statusBatch.update(func(status *apimv1.APIMAPIDeploymentStatus) {
 			status.Phase = phaseError
 			status.Status = phaseError
 			status.Message = "Failed to hash desired APIM state"
@@ -265,36 +368,94 @@ func (r *APIMAPIDeploymentReconciler) Reconcile(ctx context.Context, req ctrl.Re
 			status.ObservedGeneration = apimApi.Generation
 			status.MatchedReplicaSets = matchedReplicaSetNames
 			status.OpenAPIHash = openAPIHash
-		}); statusErr != nil {
-			return ctrl.Result{}, statusErr
-		}
+		})
 		return ctrl.Result{}, err
 	}
-	logger.Info("📥 OpenAPI definition downloaded",
-		"bytes", len(openApiContent),
-		"url", openApiURL,
-		"apiID", deployment.Spec.APIID,
-	)
+	if fetched.NotModified {
+		logger.Info("📥 OpenAPI definition not modified since last download", "url", redact.String(openApiURL), "apiID", deployment.Spec.APIID)
+	} else {
+		logger.Info("📥 OpenAPI definition downloaded",
+			"bytes", len(openApiContent),
+			"url", redact.String(openApiURL),
+			"apiID", deployment.Spec.APIID,
+		)
+	}
 
-	if deployment.Status.AppliedHash == desiredHash {
-		if statusErr := updateAPIMAPIDeploymentStatus(ctx, r.Client, &deployment, func(status *apimv1.APIMAPIDeploymentStatus) {
+	// After a restart, an unchanged hash does not prove APIM still matches: the API may have been
+	// deleted in Azure while the operator was down. Check each applied API once per process.
+	inSync := deployment.Status.AppliedHash == desiredHash
+	if inSync && r.needsStartupVerification(&deployment) {
+		exists, err := r.apiExistsInAPIM(ctx, &deployment)
+		switch {
+		case err != nil:
+			logger.Error(err, "⚠️ Failed to verify API in APIM after startup; trusting applied hash", "apiID", deployment.Spec.APIID)
+		case !exists:
+			logger.Info("🩹 API missing in APIM since last import; re-importing", "apiID", deployment.Spec.APIID)
+			if !apim.IsDryRun() {
+				cloudEventSink.Emit(cloudevents.TypeAPIDeleted, apiEventSubject(deployment.Spec.APIMService, deployment.Spec.APIID), apiEventData(&apimApi, &deployment))
+			}
+			inSync = false
+			r.markStartupVerified(&deployment)
+		default:
+			r.markStartupVerified(&deployment)
+		}
+	}
+
+	if inSync {
+		if err := r.markSynced(ctx, &apimApi); err != nil {
+			logger.Error(err, "⚠️ Failed to patch APIMAPI status", "apiID", deployment.Spec.APIID)
+			return ctrl.Result{}, err
+		}
+		statusBatch.update(func(status *apimv1.APIMAPIDeploymentStatus) {
 			status.Phase = apimDeploymentPhaseSucceeded
 			status.Status = "OK"
 			status.Message = "No changes detected; APIM is already in sync"
 			status.LastError = ""
+			status.AppliedRolloutHash = deployment.Annotations[apimDeploymentRolloutHashAnnotation]
 			status.LastAttemptAt = attemptTime
 			status.ObservedGeneration = apimApi.Generation
 			status.MatchedReplicaSets = matchedReplicaSetNames
 			status.OpenAPIHash = openAPIHash
 			status.DesiredHash = desiredHash
-		}); statusErr != nil {
-			return ctrl.Result{}, statusErr
-		}
+		})
 		logger.Info("✅ APIM already in sync; skipping import", "apiID", deployment.Spec.APIID, "desiredHash", desiredHash)
 		return ctrl.Result{}, nil
 	}
 
-	if statusErr := updateAPIMAPIDeploymentStatus(ctx, r.Client, &deployment, func(status *apimv1.APIMAPIDeploymentStatus) {
+	// A rolled back canary stays rolled back until a spec change or a new ReplicaSet rollout, since importing
+	// the same rollout again would only add another failing revision.
+	if isRolledBackCanary(&deployment, canaryRolloutKey(&deployment, desiredHash)) {
+		logger.Info("⏸️ Canary of this rollout was rolled back; waiting for a change", "apiID", deployment.Spec.APIID, "revision", deployment.Status.Canary.Revision)
+		return ctrl.Result{}, nil
+	}
+
+	// A changed definition is compared with the last imported one and held back while it breaks existing
+	// clients, unless the APIMAPI allows importing exactly this definition.
+	if requiresBreakingChangeCheck(&deployment, openAPIHash) && !breakingChangesAllowed(&apimApi, openAPIHash) {
+		if changes := r.breakingChanges(ctx, &deployment, openApiContent); len(changes) > 0 {
+			return r.blockBreakingChanges(ctx, &deployment, statusBatch, &apimApi, openAPIHash, changes, func(status *apimv1.APIMAPIDeploymentStatus) {
+				status.LastAttemptAt = attemptTime
+				status.ObservedGeneration = apimApi.Generation
+				status.MatchedReplicaSets = matchedReplicaSetNames
+				status.OpenAPIHash = openAPIHash
+				status.DesiredHash = desiredHash
+			})
+		}
+	}
+
+	// Two-phase mode: publish a plan of the pending changes and wait until the APIMAPI
+	// approves exactly this desired state before touching APIM.
+	if deployment.Spec.ApprovalRequired && !isPlanApproved(&apimApi, desiredHash) {
+		return r.publishPlan(ctx, &deployment, statusBatch, &apimApi, openAPIHash, desiredHash, func(status *apimv1.APIMAPIDeploymentStatus) {
+			status.LastAttemptAt = attemptTime
+			status.ObservedGeneration = apimApi.Generation
+			status.MatchedReplicaSets = matchedReplicaSetNames
+			status.OpenAPIHash = openAPIHash
+			status.DesiredHash = desiredHash
+		})
+	}
+
+	statusBatch.update(func(status *apimv1.APIMAPIDeploymentStatus) {
 		status.Phase = apimDeploymentPhaseImporting
 		status.Status = apimDeploymentStatusPending
 		status.Message = "Reconciling desired API state in APIM"
@@ -304,47 +465,57 @@ func (r *APIMAPIDeploymentReconciler) Reconcile(ctx context.Context, req ctrl.Re
 		status.MatchedReplicaSets = matchedReplicaSetNames
 		status.OpenAPIHash = openAPIHash
 		status.DesiredHash = desiredHash
-	}); statusErr != nil {
-		return ctrl.Result{}, statusErr
+	})
+	// The import can take minutes, so the Importing phase is written before APIM is called.
+	if err := statusBatch.flush(ctx); err != nil {
+		return ctrl.Result{}, err
 	}
 
+	// The import duration metric covers every APIM-facing step from here on. The request ID
+	// recorder lets import events name the Azure request of the last APIM call.
+	importStarted := time.Now()
+	ctx = apim.WithRequestIDRecorder(ctx)
+
 	// Step 2: Acquire an Azure management token for authenticating with the APIM Management API.
 	// The token is obtained using workload identity credentials.
 	clientID := os.Getenv("AZURE_CLIENT_ID")
 	tenantID := os.Getenv("AZURE_TENANT_ID")
 	if clientID == "" || tenantID == "" {
-		logger.Error(fmt.Errorf("missing identity env vars"), "❌ AZURE_CLIENT_ID or AZURE_TENANT_ID not set", "apiID", deployment.Spec.APIID)
-		if statusErr := updateAPIMAPIDeploymentStatus(ctx, r.Client, &deployment, func(status *apimv1.APIMAPIDeploymentStatus) {
+		identityErr := fmt.Errorf("missing identity env vars")
+		logger.Error(identityErr, "❌ AZURE_CLIENT_ID or AZURE_TENANT_ID not set", "apiID", deployment.Spec.APIID)
+		recordImportFailure(ctx, &deployment, importReasonMissingIdentity, identityErr)
+		r.recordFailedDeployment(ctx, &apimApi, &deployment, importReasonMissingIdentity, "AZURE_CLIENT_ID or AZURE_TENANT_ID not set", identityErr)
+		statusBatch.update(func(status *apimv1.APIMAPIDeploymentStatus) {
 			status.Phase = phaseError
 			status.Status = phaseError
 			status.Message = "AZURE_CLIENT_ID or AZURE_TENANT_ID not set"
 			status.LastError = "missing AZURE_CLIENT_ID or AZURE_TENANT_ID"
+			status.LastErrorDetails = newLastErrorDetails(importReasonMissingIdentity, identityErr)
 			status.LastAttemptAt = attemptTime
 			status.ObservedGeneration = apimApi.Generation
 			status.MatchedReplicaSets = matchedReplicaSetNames
 			status.OpenAPIHash = openAPIHash
 			status.DesiredHash = desiredHash
-		}); statusErr != nil {
-			return ctrl.Result{}, statusErr
-		}
+		})
 		return ctrl.Result{RequeueAfter: 30 * time.Second}, nil
 	}
-	token, err := identity.GetManagementToken(ctx, clientID, tenantID)
+	token, err := managementToken(ctx, r.Tokens, clientID, tenantID)
 	if err != nil {
 		logger.Error(err, "❌ Failed to get Azure token", "apiID", deployment.Spec.APIID)
-		if statusErr := updateAPIMAPIDeploymentStatus(ctx, r.Client, &deployment, func(status *apimv1.APIMAPIDeploymentStatus) {
+		recordImportFailure(ctx, &deployment, importReasonToken, err)
+		r.recordFailedDeployment(ctx, &apimApi, &deployment, importReasonToken, errMsgFailedToGetAzureToken, err)
+		statusBatch.update(func(status *apimv1.APIMAPIDeploymentStatus) {
 			status.Phase = phaseError
 			status.Status = phaseError
 			status.Message = errMsgFailedToGetAzureToken
 			status.LastError = err.Error()
+			status.LastErrorDetails = newLastErrorDetails(importReasonToken, err)
 			status.LastAttemptAt = attemptTime
 			status.ObservedGeneration = apimApi.Generation
 			status.MatchedReplicaSets = matchedReplicaSetNames
 			status.OpenAPIHash = openAPIHash
 			status.DesiredHash = desiredHash
-		}); statusErr != nil {
-			return ctrl.Result{}, statusErr
-		}
+		})
 		return ctrl.Result{RequeueAfter: 30 * time.Second}, nil
 	}
 	logger.Info("🔐 Obtained Azure AD token for APIM call", "apiID", deployment.Spec.APIID)
@@ -362,6 +533,10 @@ func (r *APIMAPIDeploymentReconciler) Reconcile(ctx context.Context, req ctrl.Re
 		ProductIDs:           deployment.Spec.ProductIDs,
 		TagIDs:               deployment.Spec.TagIDs,
 		SubscriptionRequired: deployment.Spec.SubscriptionRequired,
+		VersionSetID:         deployment.Spec.VersionSetID,
+		APIVersion:           deployment.Spec.APIVersion,
+		ETag:                 deployment.Status.APIETag,
+		OverwriteDrift:       overwritesDrift(&apimApi),
 	}
 	logger.Info("🛠️ Built APIM deployment config",
 		"apiID", config.APIID,
@@ -370,160 +545,437 @@ func (r *APIMAPIDeploymentReconciler) Reconcile(ctx context.Context, req ctrl.Re
 		"serviceName", config.ServiceName,
 		"routePrefix", config.RoutePrefix,
 		"revision", config.Revision,
+		"apiVersion", config.APIVersion,
 		"productCount", len(config.ProductIDs),
 		"tagCount", len(config.TagIDs),
 		"subscriptionRequired", config.SubscriptionRequired,
 	)
 
+	// Step 3b: Honor the adoption policy when the API already exists in APIM but this
+	// operator has never imported it, so brownfield APIs are not silently overwritten.
+	if requiresAdoptionCheck(&deployment) {
+		existing, err := r.APIM.GetAPIDetails(ctx, config)
+		if err != nil {
+			logger.Error(err, "🚫 Failed to check for existing API", "apiID", deployment.Spec.APIID)
+			recordImportFailure(ctx, &deployment, importReasonAdoptionCheck, err)
+			r.recordFailedDeployment(ctx, &apimApi, &deployment, importReasonAdoptionCheck, "Failed to check for existing API in APIM", err)
+			statusBatch.update(func(status *apimv1.APIMAPIDeploymentStatus) {
+				status.Phase = phaseError
+				status.Status = phaseError
+				status.Message = "Failed to check for existing API in APIM"
+				status.LastError = err.Error()
+				status.LastErrorDetails = newLastErrorDetails(importReasonAdoptionCheck, err)
+				status.LastAttemptAt = attemptTime
+				status.ObservedGeneration = apimApi.Generation
+				status.MatchedReplicaSets = matchedReplicaSetNames
+				status.OpenAPIHash = openAPIHash
+				status.DesiredHash = desiredHash
+			})
+			return ctrl.Result{RequeueAfter: azureRetryDelay(err)}, nil
+		}
+		if existing != nil {
+			return r.applyAdoptionPolicy(ctx, &deployment, statusBatch, &apimApi, &apimService, config, existing, func(status *apimv1.APIMAPIDeploymentStatus) {
+				status.LastAttemptAt = attemptTime
+				status.ObservedGeneration = apimApi.Generation
+				status.MatchedReplicaSets = matchedReplicaSetNames
+				status.OpenAPIHash = openAPIHash
+				status.DesiredHash = desiredHash
+			})
+		}
+	}
+
+	// A change of only the service URL, such as a blue/green backend switch, is applied by the service URL
+	// patch of step 5 alone: re-importing the unchanged definition first would briefly reset the backend.
+	backendSwitch := isBackendSwitch(deployment.Status.AppliedState, buildAppliedState(&deployment.Spec, openAPIHash))
+
+	// Step 3c: A canary rollout is imported into a new revision, which stays behind the current revision until
+	// its smoke check passed. The first import of an API has no current revision to protect.
+	if deployment.Spec.Canary != nil && !backendSwitch {
+		revision, err := r.APIM.NextRevision(ctx, config)
+		if err != nil {
+			logger.Error(err, "🚫 Failed to list API revisions", "apiID", deployment.Spec.APIID)
+			recordImportFailure(ctx, &deployment, importReasonRevision, err)
+			r.recordFailedDeployment(ctx, &apimApi, &deployment, importReasonRevision, "Failed to list API revisions in APIM", err)
+			statusBatch.update(func(status *apimv1.APIMAPIDeploymentStatus) {
+				status.Phase = phaseError
+				status.Status = phaseError
+				status.Message = "Failed to list API revisions in APIM"
+				status.LastError = err.Error()
+				status.LastErrorDetails = newLastErrorDetails(importReasonRevision, err)
+				status.LastAttemptAt = attemptTime
+				status.ObservedGeneration = apimApi.Generation
+				status.MatchedReplicaSets = matchedReplicaSetNames
+				status.OpenAPIHash = openAPIHash
+				status.DesiredHash = desiredHash
+			})
+			return ctrl.Result{RequeueAfter: azureRetryDelay(err)}, nil
+		}
+		config.Revision = revision
+		// The deployment names the canary revision in history, events and API Center from here on.
+		deployment.Spec.Revision = revision
+		if revision != "" {
+			logger.Info("🐤 Importing canary revision", "apiID", deployment.Spec.APIID, "revision", revision)
+		}
+	}
+	canary := deployment.Spec.Canary != nil && config.Revision != ""
+
+	// Backend settings belong to a revision, so those of a canary are written to the canary revision.
+	revisionConfig := config
+	if canary {
+		revisionConfig.APIID = apim.RevisionAPIID(config.APIID, config.Revision)
+	}
+
 	// Step 4: Import the OpenAPI definition into Azure APIM.
 	// This creates or updates the API in APIM with the provided specification.
-	if err := apim.ImportOpenAPIDefinitionToAPIM(ctx, config, openApiContent); err != nil {
-		logger.Error(err, "🚫 Failed to import API", "apiID", deployment.Spec.APIID)
-		if statusErr := updateAPIMAPIDeploymentStatus(ctx, r.Client, &deployment, func(status *apimv1.APIMAPIDeploymentStatus) {
-			status.Phase = phaseError
-			status.Status = phaseError
-			status.Message = "Failed to import API into APIM"
-			status.LastError = err.Error()
-			status.LastAttemptAt = attemptTime
-			status.ObservedGeneration = apimApi.Generation
-			status.MatchedReplicaSets = matchedReplicaSetNames
-			status.OpenAPIHash = openAPIHash
-			status.DesiredHash = desiredHash
-		}); statusErr != nil {
-			return ctrl.Result{}, statusErr
+	// An API changed in APIM since the last import is reported as drift and fails the import, unless the
+	// APIMAPI asks for such changes to be overwritten.
+	importETag := deployment.Status.APIETag
+	if backendSwitch {
+		logger.Info("🔀 Only the service URL changed; switching backend without re-import", "apiID", deployment.Spec.APIID, "serviceUrl", config.ServiceURL)
+	} else {
+		importCtx := withDriftEvents(ctx, apiEventSubject(config.ServiceName, config.APIID), apiEventData(&apimApi, &deployment))
+		importETag, err = r.APIM.ImportOpenAPIDefinitionToAPIM(importCtx, config, openApiContent)
+		if err != nil {
+			logger.Error(err, "🚫 Failed to import API", "apiID", deployment.Spec.APIID)
+			recordImportFailure(ctx, &deployment, importReasonImport, err)
+			r.recordFailedDeployment(ctx, &apimApi, &deployment, importReasonImport, "Failed to import API into APIM", err)
+			statusBatch.update(func(status *apimv1.APIMAPIDeploymentStatus) {
+				status.Phase = phaseError
+				status.Status = phaseError
+				status.Message = "Failed to import API into APIM"
+				status.LastError = err.Error()
+				status.LastErrorDetails = newLastErrorDetails(importReasonImport, err)
+				status.LastAttemptAt = attemptTime
+				status.ObservedGeneration = apimApi.Generation
+				status.MatchedReplicaSets = matchedReplicaSetNames
+				status.OpenAPIHash = openAPIHash
+				status.DesiredHash = desiredHash
+				if apim.IsPreconditionFailed(err) {
+					// Forget the stale ETag so the next reconcile imports the declared API over the edit.
+					status.APIETag = ""
+				}
+			})
+			if apim.IsPreconditionFailed(err) {
+				return ctrl.Result{}, nil
+			}
+			return ctrl.Result{RequeueAfter: azureRetryDelay(err)}, nil
 		}
-		return ctrl.Result{RequeueAfter: 60 * time.Second}, nil
+		logger.Info("✅ API imported to APIM", "apiID", deployment.Spec.APIID)
+	}
+
+	// The documentation metadata is read from the definition that was just imported. A definition without
+	// one, as after a 304 answer, keeps the documentation recorded with the last import.
+	var documentation *apimv1.APIMAPIDocumentation
+	var descriptionPatch string
+	if !backendSwitch && len(openApiContent) > 0 {
+		if docs, err := openapi.ReadDocumentation(openApiContent); err != nil {
+			logger.Info("⚠️ Failed to read the documentation of the OpenAPI definition", "apiID", deployment.Spec.APIID, "error", err.Error())
+		} else {
+			documentation = documentationStatus(docs)
+			descriptionPatch = apiDescriptionPatch(docs)
+		}
+	}
+
+	// Steps 5-8 only depend on the imported API, not on each other, so they run concurrently.
+	// Steps 5, 6 and 6b write the API itself and run one after another, so apiETag ends up with the ETag
+	// of the last write.
+	apiETag := importETag
+	setAPIETag := func(etag string) {
+		if etag != "" {
+			apiETag = etag
+		}
+	}
+	apiSteps := []deploymentStep{
+		// Step 5: Update the backend service URL for the API.
+		// This points the API to the correct backend service endpoint.
+		{
+			reason:  importReasonServiceURL,
+			message: "Failed to patch service URL in APIM",
+			run: func(ctx context.Context) error {
+				etag, err := r.APIM.AssignServiceUrlToApi(ctx, revisionConfig)
+				if err != nil {
+					return err
+				}
+				setAPIETag(etag)
+				logger.Info("✅ Service URL patched in APIM", "apiID", deployment.Spec.APIID)
+				return nil
+			},
+		},
+		// Step 6: Update the subscription requirement setting for the API.
+		// This controls whether a subscription key is required to access the API.
+		// Defaults to true (subscription required) if not explicitly set to false.
+		{
+			reason:  importReasonSubscriptionRequired,
+			message: "Failed to patch subscription requirement in APIM",
+			run: func(ctx context.Context) error {
+				etag, err := r.APIM.SetSubscriptionRequired(ctx, revisionConfig)
+				if err != nil {
+					return err
+				}
+				setAPIETag(etag)
+				logger.Info("✅ Subscription requirement patched in APIM", "apiID", deployment.Spec.APIID, "subscriptionRequired", config.SubscriptionRequired)
+				return nil
+			},
+		},
+	}
+
+	// Step 6b: Add what APIM has no field for, such as a link to the external docs, to the API description.
+	if descriptionPatch != "" {
+		apiSteps = append(apiSteps, deploymentStep{
+			reason:  importReasonDocumentation,
+			message: "Failed to patch API description in APIM",
+			run: func(ctx context.Context) error {
+				etag, err := r.APIM.SetAPIDescription(ctx, revisionConfig, descriptionPatch)
+				if err != nil {
+					return err
+				}
+				setAPIETag(etag)
+				logger.Info("✅ API description patched in APIM", "apiID", deployment.Spec.APIID)
+				return nil
+			},
+		})
+	}
+
+	steps := []deploymentStep{sequentialSteps(apiSteps...)}
+
+	// Step 7: Assign the API to all configured products (if any).
+	// Products are used to group APIs and require subscriptions for access.
+	if len(config.ProductIDs) > 0 {
+		steps = append(steps, deploymentStep{
+			reason:  importReasonProductAssignment,
+			message: "Failed to assign API to products",
+			run: func(ctx context.Context) error {
+				if err := r.APIM.AssignProductsToAPI(ctx, config); err != nil {
+					return err
+				}
+				logger.Info("✅ API assigned to products", "apiID", config.APIID, "productIDs", config.ProductIDs)
+				return nil
+			},
+		})
+	} else {
+		logger.Info("ℹ️ No product IDs configured; skipping product assignment", "apiID", deployment.Spec.APIID)
 	}
-	logger.Info("✅ API imported to APIM", "apiID", deployment.Spec.APIID)
 
-	// Step 5: Update the backend service URL for the API.
-	// This points the API to the correct backend service endpoint.
-	if err := apim.AssignServiceUrlToApi(ctx, config); err != nil {
-		logger.Error(err, "🚫 Failed to patch service URL", "apiID", deployment.Spec.APIID)
-		if statusErr := updateAPIMAPIDeploymentStatus(ctx, r.Client, &deployment, func(status *apimv1.APIMAPIDeploymentStatus) {
+	// Step 8: Assign the API to all configured tags (if any).
+	// Tags help organize and categorize APIs for better management.
+	if len(config.TagIDs) > 0 {
+		steps = append(steps, deploymentStep{
+			reason:  importReasonTagAssignment,
+			message: "Failed to assign API to tags",
+			run: func(ctx context.Context) error {
+				if err := r.APIM.AssignTagsToAPI(ctx, config); err != nil {
+					return err
+				}
+				logger.Info("✅ API assigned to tags", "apiID", config.APIID, "tagIDs", config.TagIDs)
+				return nil
+			},
+		})
+	} else {
+		logger.Info("ℹ️ No tag IDs configured; skipping tag assignment", "apiID", deployment.Spec.APIID)
+	}
+
+	if failure := runDeploymentSteps(ctx, steps); failure != nil {
+		step, message, err := failure.step.reason, failure.step.message, failure.err
+		logger.Error(err, "🚫 "+message, "apiID", deployment.Spec.APIID, "step", step)
+		switch step {
+		case importReasonProductAssignment:
+			recordAssignmentFailure(&deployment, assignmentKindProduct)
+		case importReasonTagAssignment:
+			recordAssignmentFailure(&deployment, assignmentKindTag)
+		}
+		recordImportFailure(ctx, &deployment, step, err)
+		r.recordFailedDeployment(ctx, &apimApi, &deployment, step, message, err)
+		statusBatch.update(func(status *apimv1.APIMAPIDeploymentStatus) {
 			status.Phase = phaseError
 			status.Status = phaseError
-			status.Message = "Failed to patch service URL in APIM"
+			status.Message = message
 			status.LastError = err.Error()
+			status.LastErrorDetails = newLastErrorDetails(step, err)
 			status.LastAttemptAt = attemptTime
 			status.ObservedGeneration = apimApi.Generation
 			status.MatchedReplicaSets = matchedReplicaSetNames
 			status.OpenAPIHash = openAPIHash
 			status.DesiredHash = desiredHash
-		}); statusErr != nil {
-			return ctrl.Result{}, statusErr
-		}
-		return ctrl.Result{RequeueAfter: 60 * time.Second}, nil
+		})
+		return ctrl.Result{RequeueAfter: azureRetryDelay(err)}, nil
 	}
-	logger.Info("✅ Service URL patched in APIM", "apiID", deployment.Spec.APIID)
 
-	// Step 6: Update the subscription requirement setting for the API.
-	// This controls whether a subscription key is required to access the API.
-	// Defaults to true (subscription required) if not explicitly set to false.
-	subscriptionRequired := config.SubscriptionRequired
-	if err := apim.SetSubscriptionRequired(ctx, config); err != nil {
-		logger.Error(err, "🚫 Failed to patch subscription requirement", "apiID", deployment.Spec.APIID)
-		if statusErr := updateAPIMAPIDeploymentStatus(ctx, r.Client, &deployment, func(status *apimv1.APIMAPIDeploymentStatus) {
+	// Step 9: Resolve the APIM service hostnames and update the APIMAPI status.
+	// This provides the full URLs for accessing the API through APIM.
+	apiHost, developerPortalHost, err := resolveAPIMServiceHosts(ctx, r.APIM, &apimService, config)
+	if err != nil {
+		logger.Error(err, "⚠️ Failed to fetch APIM details", "apiID", deployment.Spec.APIID)
+		recordImportFailure(ctx, &deployment, importReasonServiceDetails, err)
+		r.recordFailedDeployment(ctx, &apimApi, &deployment, importReasonServiceDetails, "Failed to fetch APIM service details", err)
+		statusBatch.update(func(status *apimv1.APIMAPIDeploymentStatus) {
 			status.Phase = phaseError
 			status.Status = phaseError
-			status.Message = "Failed to patch subscription requirement in APIM"
+			status.Message = "Failed to fetch APIM service details"
 			status.LastError = err.Error()
+			status.LastErrorDetails = newLastErrorDetails(importReasonServiceDetails, err)
 			status.LastAttemptAt = attemptTime
 			status.ObservedGeneration = apimApi.Generation
 			status.MatchedReplicaSets = matchedReplicaSetNames
 			status.OpenAPIHash = openAPIHash
 			status.DesiredHash = desiredHash
-		}); statusErr != nil {
-			return ctrl.Result{}, statusErr
-		}
-		return ctrl.Result{RequeueAfter: 60 * time.Second}, nil
+		})
+		return ctrl.Result{}, err
 	}
-	logger.Info("✅ Subscription requirement patched in APIM", "apiID", deployment.Spec.APIID, "subscriptionRequired", subscriptionRequired)
 
-	// Step 7: Assign the API to all configured products (if any).
-	// Products are used to group APIs and require subscriptions for access.
-	if len(config.ProductIDs) > 0 {
-		if err := apim.AssignProductsToAPI(ctx, config); err != nil {
-			logger.Error(err, "🚫 Failed to assign API to products", "apiID", deployment.Spec.APIID, "productIDs", config.ProductIDs)
-			if statusErr := updateAPIMAPIDeploymentStatus(ctx, r.Client, &deployment, func(status *apimv1.APIMAPIDeploymentStatus) {
+	// Step 9b: Make a canary revision current once its smoke check passed through the gateway. When it fails,
+	// the previous revision stays current and the canary revision is left in APIM for inspection.
+	if canary && !apim.IsDryRun() {
+		checkedAt := time.Now().UTC().Format(time.RFC3339)
+		rolloutKey := canaryRolloutKey(&deployment, desiredHash)
+		if err := r.checkCanary(ctx, &deployment, apiHost); err != nil {
+			message := fmt.Sprintf("Smoke check of revision %s failed; the previous revision stays current", config.Revision)
+			logger.Error(err, "🐤 "+message, "apiID", deployment.Spec.APIID, "revision", config.Revision)
+			recordImportFailure(ctx, &deployment, importReasonSmokeCheck, err)
+			r.recordFailedDeployment(ctx, &apimApi, &deployment, importReasonSmokeCheck, message, err)
+			statusBatch.update(func(status *apimv1.APIMAPIDeploymentStatus) {
 				status.Phase = phaseError
 				status.Status = phaseError
-				status.Message = "Failed to assign API to products"
+				status.Message = message
 				status.LastError = err.Error()
+				status.LastErrorDetails = newLastErrorDetails(importReasonSmokeCheck, err)
 				status.LastAttemptAt = attemptTime
 				status.ObservedGeneration = apimApi.Generation
 				status.MatchedReplicaSets = matchedReplicaSetNames
 				status.OpenAPIHash = openAPIHash
 				status.DesiredHash = desiredHash
-			}); statusErr != nil {
-				return ctrl.Result{}, statusErr
-			}
-			return ctrl.Result{RequeueAfter: 60 * time.Second}, nil
+				status.Canary = &apimv1.APIMAPIDeploymentCanaryStatus{
+					Revision:   config.Revision,
+					Result:     canaryResultRolledBack,
+					Message:    err.Error(),
+					CheckedAt:  checkedAt,
+					RolloutKey: rolloutKey,
+				}
+			})
+			return ctrl.Result{}, nil
 		}
-		logger.Info("✅ API assigned to products", "apiID", config.APIID, "productIDs", config.ProductIDs)
-	} else {
-		logger.Info("ℹ️ No product IDs configured; skipping product assignment", "apiID", deployment.Spec.APIID)
+		notes := fmt.Sprintf("Released by azure-apim-operator after the smoke check of %s passed", deployment.Spec.Canary.SmokeCheck.Path)
+		if err := r.APIM.ReleaseRevision(ctx, config, notes); err != nil {
+			logger.Error(err, "🚫 Failed to make canary revision current", "apiID", deployment.Spec.APIID, "revision", config.Revision)
+			recordImportFailure(ctx, &deployment, importReasonRelease, err)
+			r.recordFailedDeployment(ctx, &apimApi, &deployment, importReasonRelease, "Failed to make canary revision current", err)
+			statusBatch.update(func(status *apimv1.APIMAPIDeploymentStatus) {
+				status.Phase = phaseError
+				status.Status = phaseError
+				status.Message = "Failed to make canary revision current"
+				status.LastError = err.Error()
+				status.LastErrorDetails = newLastErrorDetails(importReasonRelease, err)
+				status.LastAttemptAt = attemptTime
+				status.ObservedGeneration = apimApi.Generation
+				status.MatchedReplicaSets = matchedReplicaSetNames
+				status.OpenAPIHash = openAPIHash
+				status.DesiredHash = desiredHash
+			})
+			return ctrl.Result{RequeueAfter: azureRetryDelay(err)}, nil
+		}
+		logger.Info("🐤 Canary revision passed its smoke check and is current", "apiID", deployment.Spec.APIID, "revision", config.Revision)
+		statusBatch.update(func(status *apimv1.APIMAPIDeploymentStatus) {
+			status.Canary = &apimv1.APIMAPIDeploymentCanaryStatus{
+				Revision:   config.Revision,
+				Result:     canaryResultPromoted,
+				Message:    "Smoke check passed",
+				CheckedAt:  checkedAt,
+				RolloutKey: rolloutKey,
+			}
+		})
+		// The release changed the current revision, so the ETag of the canary revision no longer applies.
+		apiETag = ""
 	}
 
-	// Step 8: Assign the API to all configured tags (if any).
-	// Tags help organize and categorize APIs for better management.
-	if len(config.TagIDs) > 0 {
-		if err := apim.AssignTagsToAPI(ctx, config); err != nil {
-			logger.Error(err, "🚫 Failed to assign API to tags", "apiID", deployment.Spec.APIID, "tagIDs", config.TagIDs)
-			if statusErr := updateAPIMAPIDeploymentStatus(ctx, r.Client, &deployment, func(status *apimv1.APIMAPIDeploymentStatus) {
+	// Step 10: Register the API in Azure API Center if the APIM service references one. A failure is
+	// retried like any other step, so the inventory can't silently fall behind the gateway.
+	if apimService.Spec.APICenter != nil {
+		registration := apim.APICenterRegistration{
+			APIID:              deployment.Spec.APIID,
+			Version:            apiCenterVersion(deployment.Spec.Revision),
+			OpenAPIContent:     openApiContent,
+			Owner:              apimApi.Spec.Owner,
+			DeveloperPortalURL: developerPortalAPIURL(developerPortalHost, deployment.Spec.APIID),
+			Environment:        apimService.Spec.APICenter.Environment,
+			GatewayURL:         fmt.Sprintf("https://%s%s", apiHost, deployment.Spec.RoutePrefix),
+		}
+		if err := r.APIM.RegisterAPIInAPICenter(ctx, apiCenterConfig(&apimService, token), registration); err != nil {
+			logger.Error(err, "🚫 Failed to register API in API Center", "apiID", deployment.Spec.APIID, "apiCenter", apimService.Spec.APICenter.Name)
+			recordImportFailure(ctx, &deployment, importReasonAPICenter, err)
+			r.recordFailedDeployment(ctx, &apimApi, &deployment, importReasonAPICenter, "Failed to register API in API Center", err)
+			statusBatch.update(func(status *apimv1.APIMAPIDeploymentStatus) {
 				status.Phase = phaseError
 				status.Status = phaseError
-				status.Message = "Failed to assign API to tags"
+				status.Message = "Failed to register API in API Center"
 				status.LastError = err.Error()
+				status.LastErrorDetails = newLastErrorDetails(importReasonAPICenter, err)
 				status.LastAttemptAt = attemptTime
 				status.ObservedGeneration = apimApi.Generation
 				status.MatchedReplicaSets = matchedReplicaSetNames
 				status.OpenAPIHash = openAPIHash
 				status.DesiredHash = desiredHash
-			}); statusErr != nil {
-				return ctrl.Result{}, statusErr
-			}
-			return ctrl.Result{RequeueAfter: 60 * time.Second}, nil
+			})
+			return ctrl.Result{RequeueAfter: azureRetryDelay(err)}, nil
 		}
-		logger.Info("✅ API assigned to tags", "apiID", config.APIID, "tagIDs", config.TagIDs)
-	} else {
-		logger.Info("ℹ️ No tag IDs configured; skipping tag assignment", "apiID", deployment.Spec.APIID)
 	}
 
-	// Step 9: Fetch APIM service host details and update the APIMAPI status.
-	// This provides the full URLs for accessing the API through APIM.
-	apiHost, developerPortalHost, err := apim.GetAPIMServiceDetails(ctx, config)
-	if err != nil {
-		logger.Error(err, "⚠️ Failed to fetch APIM details", "apiID", deployment.Spec.APIID)
-		if statusErr := updateAPIMAPIDeploymentStatus(ctx, r.Client, &deployment, func(status *apimv1.APIMAPIDeploymentStatus) {
-			status.Phase = phaseError
-			status.Status = phaseError
-			status.Message = "Failed to fetch APIM service details"
-			status.LastError = err.Error()
+	// In dry-run mode nothing was sent to APIM, so mark the statuses as DryRun and leave
+	// AppliedHash untouched so the import runs for real once writes are enabled.
+	if apim.IsDryRun() {
+		apimApi.Status.Status = phaseDryRun
+		recordImportSuccess(ctx, &deployment, true, importStarted)
+		recordDeploymentHistory(&apimApi, &deployment, deploymentOutcomeDryRun)
+		setReadyCondition(&apimApi, metav1.ConditionTrue, apimv1.ReasonSynced, "Dry-run: Azure-mutating calls were logged but not sent to APIM")
+		if err := applyAPIMAPIStatus(ctx, r.Client, &apimApi); err != nil {
+			logger.Error(err, "⚠️ Failed to patch APIMAPI status", "apiID", deployment.Spec.APIID)
+			return ctrl.Result{}, err
+		}
+		statusBatch.update(func(status *apimv1.APIMAPIDeploymentStatus) {
+			status.Phase = phaseDryRun
+			status.Status = phaseDryRun
+			status.Message = "Dry-run: Azure-mutating calls were logged but not sent to APIM"
+			status.LastError = ""
 			status.LastAttemptAt = attemptTime
 			status.ObservedGeneration = apimApi.Generation
 			status.MatchedReplicaSets = matchedReplicaSetNames
 			status.OpenAPIHash = openAPIHash
 			status.DesiredHash = desiredHash
-		}); statusErr != nil {
-			return ctrl.Result{}, statusErr
-		}
-		return ctrl.Result{}, err
+		})
+		logger.Info("🧪 Dry-run reconcile completed", "apiID", deployment.Spec.APIID, "desiredHash", desiredHash)
+		return ctrl.Result{}, nil
 	}
 
 	// Update the APIMAPI status with deployment information.
-	// Use Patch to update only status without touching spec fields (like subscriptionRequired).
-	statusPatch := client.MergeFrom(apimApi.DeepCopy())
+	// Server-side apply sends only the status, so spec fields (like subscriptionRequired) are never touched.
+	recordImportSuccess(ctx, &deployment, false, importStarted)
 	apimApi.Status.ImportedAt = time.Now().Format(time.RFC3339)
 	apimApi.Status.Status = "OK"
 	apimApi.Status.ApiHost = fmt.Sprintf("https://%s%s", apiHost, deployment.Spec.RoutePrefix)
 	apimApi.Status.DeveloperPortalHost = fmt.Sprintf("https://%s", developerPortalHost)
+	apimApi.Status.DeveloperPortalURL = developerPortalAPIURL(developerPortalHost, config.APIID)
+	apimApi.Status.DefinitionURL = deployment.Spec.OpenAPIDefinitionURL
+	if documentation != nil {
+		apimApi.Status.Documentation = documentation
+	}
+	apimApi.Status.AzureResourceID = apim.APIResourceID(config.SubscriptionID, config.ResourceGroup, config.ServiceName, config.APIID)
+	apimApi.Status.TerraformImports = apiTerraformImports(config.SubscriptionID, config.ResourceGroup, config.ServiceName, config.APIID,
+		currentAPIRevision(&apimApi, config.Revision), deployment.Spec.ProductIDs, deployment.Spec.TagIDs)
+	apimApi.Status.PendingPlan = nil
+	recordActiveBackend(&apimApi, deployment.Spec.ServiceURL, time.Now())
+	clearBreakingChange(&apimApi)
+	recordDeploymentHistory(&apimApi, &deployment, deploymentOutcomeSucceeded)
+	setReadyCondition(&apimApi, metav1.ConditionTrue, apimv1.ReasonSynced, "API is imported and configured in APIM")
 
-	if err := r.Status().Patch(ctx, &apimApi, statusPatch); err != nil {
+	if err := applyAPIMAPIStatus(ctx, r.Client, &apimApi); err != nil {
 		logger.Error(err, "⚠️ Failed to patch APIMAPI status", "apiID", deployment.Spec.APIID)
 		return ctrl.Result{}, err
 	}
-	if statusErr := updateAPIMAPIDeploymentStatus(ctx, r.Client, &deployment, func(status *apimv1.APIMAPIDeploymentStatus) {
+	// Without a copy the next changed definition is imported unchecked, so a failed write doesn't fail the import.
+	if breakingChangeDetection.Load() && len(openApiContent) > 0 {
+		if err := r.storeImportedOpenAPI(ctx, &deployment, openApiContent, openAPIHash); err != nil {
+			logger.Error(err, "⚠️ Failed to keep imported OpenAPI definition for the breaking-change check", "apiID", deployment.Spec.APIID)
+		}
+	}
+	statusBatch.update(func(status *apimv1.APIMAPIDeploymentStatus) {
 		status.Phase = apimDeploymentPhaseSucceeded
 		status.Status = "OK"
 		status.Message = "Successfully reconciled API in APIM"
@@ -534,28 +986,38 @@ func (r *APIMAPIDeploymentReconciler) Reconcile(ctx context.Context, req ctrl.Re
 		status.OpenAPIHash = openAPIHash
 		status.DesiredHash = desiredHash
 		status.AppliedHash = desiredHash
+		status.AppliedRolloutHash = deployment.Annotations[apimDeploymentRolloutHashAnnotation]
+		status.APIETag = apiETag
+		status.AppliedState = buildAppliedState(&deployment.Spec, openAPIHash)
 		status.ImportedAt = time.Now().UTC().Format(time.RFC3339)
-	}); statusErr != nil {
-		return ctrl.Result{}, statusErr
-	}
+	})
 	logger.Info("📝 APIMAPI status patched after import",
 		"name", apimApi.Name,
 		"apiID", deployment.Spec.APIID,
 		"apiHost", apimApi.Status.ApiHost,
 		"developerPortalHost", apimApi.Status.DeveloperPortalHost,
+		"azureResourceId", apimApi.Status.AzureResourceID,
 		"subscriptionRequired", apimApi.Spec.SubscriptionRequired,
 	)
+	// A failed signal only delays the portal until the next change, so it doesn't fail the import.
+	if err := signalPortalContentChanged(ctx, hubClient(r.Client, r.Hub), &apimService); err != nil {
+		logger.Error(err, "⚠️ Failed to signal developer portal publish", "apiID", deployment.Spec.APIID, "apimService", apimService.Name)
+	}
+	r.notifyImport(ctx, &apimApi, &deployment, notify.ResultSuccess, "", nil)
+	cloudEventSink.Emit(cloudevents.TypeAPIImported, apiEventSubject(config.ServiceName, config.APIID), apiEventData(&apimApi, &deployment))
 
 	return ctrl.Result{}, nil
 }
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *APIMAPIDeploymentReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	// Watch via the priority-aware handler instead of For so high-priority resources jump the queue.
 	return ctrl.NewControllerManagedBy(mgr).
-		For(&apimv1.APIMAPIDeployment{}).
+		Watches(&apimv1.APIMAPIDeployment{}, priorityEnqueueHandler()).
 		WithEventFilter(apimAPIDeploymentPredicate()).
+		WithEventFilter(shardPredicate()).
 		Named("apimapideployment").
-		Complete(r)
+		Complete(withTracing("APIMAPIDeployment", r))
 }
 
 func apimAPIDeploymentPredicate() predicate.Predicate {
@@ -583,6 +1045,14 @@ func apimAPIDeploymentPredicate() predicate.Predicate {
 				return true
 			}
 
+			if oldAnnotations[apimApprovalAnnotation] != newAnnotations[apimApprovalAnnotation] {
+				return true
+			}
+
+			if oldAnnotations[apimAllowBreakingChangesAnnotation] != newAnnotations[apimAllowBreakingChangesAnnotation] {
+				return true
+			}
+
 			return false
 		},
 		DeleteFunc: func(e event.DeleteEvent) bool {
@@ -594,40 +1064,53 @@ func apimAPIDeploymentPredicate() predicate.Predicate {
 	}
 }
 
-// fetchOpenAPIDefinitionWithRetry fetches an OpenAPI definition from a URL with exponential backoff retry logic.
-// It attempts to fetch the definition up to maxRetries times, with increasing delays between attempts
-// (2s, 4s, 8s, 16s, 32s) to handle transient network failures or temporary service unavailability.
-func fetchOpenAPIDefinitionWithRetry(url string, maxRetries int) ([]byte, error) {
-	var lastErr error
-
-	for i := 0; i < maxRetries; i++ {
-		resp, err := http.Get(url)
-		if err != nil {
-			lastErr = fmt.Errorf("GET error: %w", err)
-		} else {
-			body, readErr := io.ReadAll(resp.Body)
-			closeErr := resp.Body.Close()
-
-			if readErr != nil {
-				lastErr = fmt.Errorf("read body error: %w", readErr)
-			} else if resp.StatusCode >= 200 && resp.StatusCode < 300 {
-				if closeErr != nil {
-					return nil, fmt.Errorf("close response body: %w", closeErr)
-				}
-				return body, nil
-			} else {
-				if closeErr != nil {
-					lastErr = fmt.Errorf("unexpected status: %s\nbody: %s (close error: %v)", resp.Status, string(body), closeErr)
-				} else {
-					lastErr = fmt.Errorf("unexpected status: %s\nbody: %s", resp.Status, string(body))
-				}
-			}
-		}
+// fetchOpenAPIDefinition makes one attempt to fetch an OpenAPI definition from a URL.
+// Failed attempts are retried by requeueing the deployment, see openAPIFetchRetryAfter, so the worker
+// never sleeps inside Reconcile.
+// The request carries a W3C traceparent header, so the backend's telemetry joins the reconcile trace.
+// With validators, the request is conditional and a 304 answer is returned as NotModified.
+func fetchOpenAPIDefinition(
+	ctx context.Context,
+	url string,
+	validators *openAPIValidators,
+) (_ openAPIFetchResult, err error) {
+	ctx, span := tracer.Start(ctx, "fetchOpenAPIDefinition", trace.WithAttributes(semconv.HTTPURL(redact.String(url))))
+	defer func() {
+		recordSpanError(span, err)
+		span.End()
+	}()
 
-		// Exponential backoff: wait 2^attempt seconds before retrying.
-		// This gives transient failures time to resolve while avoiding excessive retries.
-		time.Sleep(time.Duration(2<<i) * time.Second) // 2s, 4s, 8s, 16s, 32s
+	resp, err := getWithTraceContext(ctx, url, validators)
+	if err != nil {
+		return openAPIFetchResult{}, fmt.Errorf("GET error: %s", redact.String(err.Error()))
+	}
+	result, err := readOpenAPIResponse(resp, validators != nil)
+	if closeErr := resp.Body.Close(); closeErr != nil && err == nil {
+		return openAPIFetchResult{}, fmt.Errorf("close response body: %w", closeErr)
 	}
+	return result, err
+}
 
-	return nil, fmt.Errorf("openapi fetch failed after %d attempts: %w", maxRetries, lastErr)
+// getWithTraceContext sends a GET request that propagates the trace context of ctx.
+// Non-nil validators make it a conditional GET.
+func getWithTraceContext(ctx context.Context, url string, validators *openAPIValidators) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	if validators != nil {
+		if validators.ETag != "" {
+			req.Header.Set("If-None-Match", validators.ETag)
+		}
+		if validators.LastModified != "" {
+			req.Header.Set("If-Modified-Since", validators.LastModified)
+		}
+	}
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(req.Header))
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	trace.SpanFromContext(ctx).SetAttributes(semconv.HTTPStatusCode(resp.StatusCode))
+	return resp, nil
 }
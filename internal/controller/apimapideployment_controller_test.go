@@ -21,6 +21,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"slices"
 	"time"
 
 	. "github.com/onsi/ginkgo/v2"
@@ -31,9 +32,13 @@ import (
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	apimv1 "github.com/hedinit/azure-apim-operator/api/v1"
+	"github.com/hedinit/azure-apim-operator/internal/apim"
+	"github.com/hedinit/azure-apim-operator/internal/apimtest"
+	"github.com/hedinit/azure-apim-operator/internal/identity"
 )
 
 var _ = Describe("APIMAPIDeployment Controller", func() {
@@ -83,6 +88,7 @@ var _ = Describe("APIMAPIDeployment Controller", func() {
 				Spec: apimv1.APIMAPISpec{
 					APIID:       "test-api-id",
 					APIMService: apimServiceName,
+					RoutePrefix: "/test-api",
 				},
 			}
 			Expect(k8sClient.Create(ctx, apimAPI)).To(Succeed())
@@ -353,7 +359,7 @@ var _ = Describe("APIMAPIDeployment Controller", func() {
 			Expect(k8sClient.Update(ctx, deployment)).To(Succeed())
 
 			By("precomputing and storing the applied hash")
-			openAPIContent := []byte(`{"openapi":"3.0.0","info":{"title":"test","version":"1.0.0"},"paths":{}}`)
+			openAPIContent := []byte(openAPIDocument)
 			openAPIHash := sha256Hex(openAPIContent)
 			desiredHash, err := buildDesiredAPIMStateHash(&deployment.Spec, deployment.Spec.Subscription, deployment.Spec.ResourceGroup, openAPIHash)
 			Expect(err).NotTo(HaveOccurred())
@@ -388,16 +394,196 @@ var _ = Describe("APIMAPIDeployment Controller", func() {
 			Expect(updatedDeployment.Status.Message).To(ContainSubstring("No changes detected"))
 			Expect(updatedDeployment.Status.AppliedHash).To(Equal(desiredHash))
 		})
+
+		It("should import the API into APIM and report success", func() {
+			By("serving a fake APIM management endpoint and a local OpenAPI document")
+			fakeAPIM := apimtest.Start(GinkgoT())
+			server := newOpenAPIServer()
+			defer server.Close()
+
+			By("creating a matching ready ReplicaSet")
+			rs := createReplicaSet(ctx, "test-import-replicaset", map[string]string{"app.kubernetes.io/name": resourceName}, map[string]string{"app": resourceName}, 1)
+			createReadyPodForReplicaSet(ctx, rs, "test-import-pod")
+
+			By("pointing the deployment at the local OpenAPI document with a product and a tag")
+			deployment := &apimv1.APIMAPIDeployment{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, deployment)).To(Succeed())
+			deployment.Spec.OpenAPIDefinitionURL = server.URL
+			deployment.Spec.ProductIDs = []string{"starter"}
+			deployment.Spec.TagIDs = []string{"internal"}
+			Expect(k8sClient.Update(ctx, deployment)).To(Succeed())
+
+			By("setting Azure credentials that the fake accepts")
+			restoreIdentityEnv := setAzureIdentityEnvVars()
+			defer restoreIdentityEnv()
+
+			By("reconciling the resource")
+			controllerReconciler := &APIMAPIDeploymentReconciler{
+				APIM:   fakeAPIM.Client(),
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+			}
+
+			result, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result).To(Equal(reconcile.Result{}))
+
+			By("verifying that the definition was imported at the route prefix")
+			apiPath := apim.APIResourceID("test-subscription-id", "test-rg", apimServiceName, "test-api-id")
+			Expect(string(fakeAPIM.Definition(apiPath))).To(Equal(openAPIDocument))
+			api, ok := fakeAPIM.Properties(apiPath)
+			Expect(ok).To(BeTrue())
+			Expect(api).To(HaveKeyWithValue("path", "test-api"))
+
+			By("verifying that the service URL and subscription requirement were set")
+			Expect(api).To(HaveKeyWithValue("serviceUrl", "https://example.com/api"))
+			Expect(api).To(HaveKeyWithValue("subscriptionRequired", true))
+
+			By("verifying that the API was added to the product and tagged")
+			servicePath := apim.ServiceResourceID("test-subscription-id", "test-rg", apimServiceName)
+			_, ok = fakeAPIM.Properties(servicePath + "/products/starter/apis/test-api-id")
+			Expect(ok).To(BeTrue())
+			_, ok = fakeAPIM.Properties(apiPath + "/tags/internal")
+			Expect(ok).To(BeTrue())
+
+			By("verifying that the deployment is kept and reports success")
+			updatedDeployment := &apimv1.APIMAPIDeployment{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, updatedDeployment)).To(Succeed())
+			Expect(updatedDeployment.Status.Phase).To(Equal(apimDeploymentPhaseSucceeded))
+			Expect(updatedDeployment.Status.Status).To(Equal("OK"))
+			Expect(updatedDeployment.Status.LastError).To(BeEmpty())
+			Expect(updatedDeployment.Status.AppliedHash).NotTo(BeEmpty())
+			Expect(updatedDeployment.Status.AppliedHash).To(Equal(updatedDeployment.Status.DesiredHash))
+			Expect(updatedDeployment.Status.APIETag).NotTo(BeEmpty())
+			Expect(updatedDeployment.Status.MatchedReplicaSets).To(ConsistOf(rs.Name))
+
+			By("verifying that the APIMAPI status points at the gateway")
+			apimAPI := &apimv1.APIMAPI{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, apimAPI)).To(Succeed())
+			Expect(apimAPI.Status.Status).To(Equal("OK"))
+			Expect(apimAPI.Status.ApiHost).To(Equal("https://" + apimServiceName + ".azure-api.net/test-api"))
+			Expect(apimAPI.Status.AzureResourceID).To(Equal(apiPath))
+			ready := apimeta.FindStatusCondition(apimAPI.Status.Conditions, conditionTypeReady)
+			Expect(ready).NotTo(BeNil())
+			Expect(ready.Status).To(Equal(metav1.ConditionTrue))
+			Expect(ready.Reason).To(Equal(apimv1.ReasonSynced))
+		})
+
+		It("should get a new token and honor Retry-After until the import converges", func() {
+			By("serving a fake APIM that rejects an expired token and throttles the tag assignment once")
+			fakeAPIM := apimtest.Start(GinkgoT())
+			fakeAPIM.Inject(apimtest.Fault{Token: "expired-token", Status: http.StatusUnauthorized, Code: "ExpiredAuthenticationToken"})
+			fakeAPIM.Inject(apimtest.Fault{Method: http.MethodPut, PathSuffix: "/tags/internal",
+				Status: http.StatusTooManyRequests, Code: "TooManyRequests", RetryAfter: "7", Times: 1})
+			server := newOpenAPIServer()
+			defer server.Close()
+
+			By("creating a matching ready ReplicaSet")
+			rs := createReplicaSet(ctx, "test-fault-replicaset", map[string]string{"app.kubernetes.io/name": resourceName}, map[string]string{"app": resourceName}, 1)
+			createReadyPodForReplicaSet(ctx, rs, "test-fault-pod")
+
+			By("pointing the deployment at the local OpenAPI document with a tag")
+			deployment := &apimv1.APIMAPIDeployment{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, deployment)).To(Succeed())
+			deployment.Spec.OpenAPIDefinitionURL = server.URL
+			deployment.Spec.TagIDs = []string{"internal"}
+			Expect(k8sClient.Update(ctx, deployment)).To(Succeed())
+
+			restoreIdentityEnv := setAzureIdentityEnvVars()
+			defer restoreIdentityEnv()
+			tokens := &rotatingTokens{tokens: []string{"expired-token", "fresh-token"}}
+			controllerReconciler := &APIMAPIDeploymentReconciler{
+				APIM:   fakeAPIM.Client(),
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+				Tokens: tokens,
+			}
+			reconcileOnce := func() (reconcile.Result, *apimv1.APIMAPIDeployment) {
+				result, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+				Expect(err).NotTo(HaveOccurred())
+				updatedDeployment := &apimv1.APIMAPIDeployment{}
+				Expect(k8sClient.Get(ctx, typeNamespacedName, updatedDeployment)).To(Succeed())
+				return result, updatedDeployment
+			}
+
+			By("reconciling while the cached token is expired")
+			result, updatedDeployment := reconcileOnce()
+			Expect(result.RequeueAfter).To(Equal(DefaultAzureRetryInterval))
+			Expect(updatedDeployment.Status.Phase).To(Equal(phaseError))
+			Expect(updatedDeployment.Status.LastErrorDetails).NotTo(BeNil())
+			Expect(updatedDeployment.Status.LastErrorDetails.Reason).To(Equal(apimv1.ReasonAuthFailed))
+			Expect(updatedDeployment.Status.LastErrorDetails.Code).To(Equal("ExpiredAuthenticationToken"))
+			Expect(tokens.invalidations).To(Equal(1), "the rejected token must be invalidated")
+
+			By("retrying with a new token while APIM throttles the tag assignment")
+			result, updatedDeployment = reconcileOnce()
+			Expect(result.RequeueAfter).To(Equal(7 * time.Second))
+			Expect(updatedDeployment.Status.Phase).To(Equal(phaseError))
+			Expect(updatedDeployment.Status.LastErrorDetails.Reason).To(Equal(apimv1.ReasonThrottled))
+			Expect(updatedDeployment.Status.LastErrorDetails.HTTPStatus).To(BeEquivalentTo(http.StatusTooManyRequests))
+			Expect(tokens.invalidations).To(Equal(1), "throttling must not invalidate the token")
+
+			By("retrying after the throttling")
+			result, updatedDeployment = reconcileOnce()
+			Expect(result).To(Equal(reconcile.Result{}))
+			Expect(updatedDeployment.Status.Phase).To(Equal(apimDeploymentPhaseSucceeded))
+			Expect(updatedDeployment.Status.AppliedHash).To(Equal(updatedDeployment.Status.DesiredHash))
+			apiPath := apim.APIResourceID("test-subscription-id", "test-rg", apimServiceName, "test-api-id")
+			_, ok := fakeAPIM.Properties(apiPath + "/tags/internal")
+			Expect(ok).To(BeTrue())
+
+			By("verifying that no request after the first attempt used the expired token")
+			requests := fakeAPIM.Requests()
+			firstFresh := slices.IndexFunc(requests, func(req apimtest.Request) bool { return req.Token == "fresh-token" })
+			Expect(firstFresh).To(BeNumerically(">", 0))
+			for _, req := range requests[firstFresh:] {
+				Expect(req.Token).To(Equal("fresh-token"))
+			}
+		})
 	})
 })
 
+// rotatingTokens is a TokenProvider that keeps handing out the same token until it is invalidated, like a
+// TokenCache, and then moves on to the next one.
+type rotatingTokens struct {
+	tokens        []string
+	invalidations int
+}
+
+func (r *rotatingTokens) ManagementToken(context.Context) (string, error) {
+	return r.tokens[min(r.invalidations, len(r.tokens)-1)], nil
+}
+
+func (r *rotatingTokens) Invalidate() {
+	r.invalidations++
+}
+
+// openAPIDocument is the document newOpenAPIServer serves.
+const openAPIDocument = `{"openapi":"3.0.0","info":{"title":"test","version":"1.0.0"},"paths":{}}`
+
 func newOpenAPIServer() *httptest.Server {
 	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Content-Type", "application/json")
-		_, _ = w.Write([]byte(`{"openapi":"3.0.0","info":{"title":"test","version":"1.0.0"},"paths":{}}`))
+		_, _ = w.Write([]byte(openAPIDocument))
 	}))
 }
 
+// setAzureIdentityEnvVars sets dummy Azure credentials and a static management token, so the reconciler
+// authenticates without Azure AD. Tests that call it talk to the fake of the apimtest package.
+func setAzureIdentityEnvVars() func() {
+	restore := unsetAzureIdentityEnvVars()
+	_ = os.Setenv("AZURE_CLIENT_ID", "test-client-id")
+	_ = os.Setenv("AZURE_TENANT_ID", "test-tenant-id")
+	identity.SetStaticToken("test-token")
+
+	return func() {
+		identity.SetStaticToken("")
+		restore()
+	}
+}
+
 func unsetAzureIdentityEnvVars() func() {
 	originalClientID := os.Getenv("AZURE_CLIENT_ID")
 	originalTenantID := os.Getenv("AZURE_TENANT_ID")
@@ -0,0 +1,34 @@
+package controller
+
+import (
+	"time"
+
+	apimv1 "github.com/hedinit/azure-apim-operator/api/v1"
+)
+
+const apimDeploymentPhaseDebouncing = "Debouncing"
+
+// replicaSetDebounceRemaining returns how long the deployment should wait before importing so that
+// a burst of ReplicaSet transitions during a rolling update results in a single import.
+// Every ReplicaSet signal refreshes the signal annotation, so the window restarts on each transition.
+// It returns zero when debouncing is disabled, no signal was recorded or the window has elapsed.
+func replicaSetDebounceRemaining(deployment *apimv1.APIMAPIDeployment, window time.Duration, now time.Time) time.Duration {
+	if window <= 0 {
+		return 0
+	}
+	signal, ok := deployment.Annotations[apimDeploymentSignalAnnotation]
+	if !ok {
+		return 0
+	}
+	signaledAt, err := time.Parse(time.RFC3339Nano, signal)
+	if err != nil {
+		return 0
+	}
+
+	remaining := signaledAt.Add(window).Sub(now)
+	if remaining <= 0 {
+		return 0
+	}
+	// Signals written by a replica with a skewed clock wait at most one window.
+	return min(remaining, window)
+}
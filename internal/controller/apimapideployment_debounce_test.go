@@ -0,0 +1,43 @@
+package controller
+
+import (
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apimv1 "github.com/hedinit/azure-apim-operator/api/v1"
+)
+
+func TestReplicaSetDebounceRemaining(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	window := 10 * time.Second
+
+	withSignal := func(signal string) *apimv1.APIMAPIDeployment {
+		return &apimv1.APIMAPIDeployment{ObjectMeta: metav1.ObjectMeta{
+			Annotations: map[string]string{apimDeploymentSignalAnnotation: signal},
+		}}
+	}
+
+	tests := []struct {
+		name       string
+		deployment *apimv1.APIMAPIDeployment
+		window     time.Duration
+		want       time.Duration
+	}{
+		{name: "disabled", deployment: withSignal(now.Format(time.RFC3339Nano)), window: 0, want: 0},
+		{name: "no signal", deployment: &apimv1.APIMAPIDeployment{}, window: window, want: 0},
+		{name: "unparsable signal", deployment: withSignal("yesterday"), window: window, want: 0},
+		{name: "recent signal", deployment: withSignal(now.Add(-4 * time.Second).Format(time.RFC3339Nano)), window: window, want: 6 * time.Second},
+		{name: "settled signal", deployment: withSignal(now.Add(-time.Minute).Format(time.RFC3339Nano)), window: window, want: 0},
+		{name: "future signal", deployment: withSignal(now.Add(time.Hour).Format(time.RFC3339Nano)), window: window, want: window},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := replicaSetDebounceRemaining(tt.deployment, tt.window, now); got != tt.want {
+				t.Fatalf("replicaSetDebounceRemaining() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
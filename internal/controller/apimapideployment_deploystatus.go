@@ -0,0 +1,78 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apimv1 "github.com/hedinit/azure-apim-operator/api/v1"
+	"github.com/hedinit/azure-apim-operator/internal/deploystatus"
+	"github.com/hedinit/azure-apim-operator/internal/notify"
+)
+
+const (
+	// apimSourceRepositoryAnnotation and apimSourceCommitAnnotation on an APIMAPI name the repository and
+	// commit its current spec was built from. CI sets them, so the deployment can be reported back.
+	apimSourceRepositoryAnnotation = "apim.operator.io/source-repository"
+	apimSourceCommitAnnotation     = "apim.operator.io/source-commit"
+
+	// eventReasonDeploymentStatusFailed is the Warning Event reason for an import outcome that couldn't be reported.
+	eventReasonDeploymentStatusFailed = "DeploymentStatusFailed"
+)
+
+// reportDeploymentStatus reports outcome to the source repository of apimApi, as configured in the
+// deploymentStatus of apimService. APIMAPIs without source annotations are skipped. A report that fails
+// is recorded as a Warning Event; it never fails the import.
+func (r *APIMAPIDeploymentReconciler) reportDeploymentStatus(
+	ctx context.Context,
+	reader client.Reader,
+	namespace string,
+	apimService *apimv1.APIMService,
+	apimApi *apimv1.APIMAPI,
+	outcome notify.ImportOutcome,
+) {
+	config := apimService.Spec.DeploymentStatus
+	repository := apimApi.Annotations[apimSourceRepositoryAnnotation]
+	commit := apimApi.Annotations[apimSourceCommitAnnotation]
+	if config == nil || repository == "" || commit == "" {
+		return
+	}
+
+	deployment := deploystatus.Deployment{
+		Repository:     repository,
+		Commit:         commit,
+		Environment:    config.Environment,
+		State:          deploystatus.StateSuccess,
+		Description:    fmt.Sprintf("API %s is live on %s", outcome.APIID, apimService.Name),
+		EnvironmentURL: outcome.GatewayURL,
+	}
+	if outcome.Result == notify.ResultFailure {
+		deployment.State = deploystatus.StateFailure
+		deployment.Description = fmt.Sprintf("Import of API %s into %s failed at step %s: %s", outcome.APIID, apimService.Name, outcome.Step, outcome.Error)
+	}
+
+	err := sendDeploymentStatus(ctx, reader, namespace, config, deployment)
+	if err != nil {
+		ctrl.Log.WithName("apimapideployment_controller").Error(err, "⚠️ Failed to report deployment status",
+			"apiID", outcome.APIID, "repository", repository, "commit", commit)
+		recordWarningEvent(r.Recorder, apimApi, eventReasonDeploymentStatusFailed,
+			fmt.Sprintf("Failed to report deployment of %s@%s to %s: %v", repository, commit, config.Provider, err))
+	}
+}
+
+// sendDeploymentStatus reads the access token of config from its Secret and reports deployment.
+func sendDeploymentStatus(ctx context.Context, reader client.Reader, namespace string, config *apimv1.APIMServiceDeploymentStatus, deployment deploystatus.Deployment) error {
+	var secret corev1.Secret
+	if err := reader.Get(ctx, client.ObjectKey{Name: config.TokenSecretRef.Name, Namespace: namespace}, &secret); err != nil {
+		return fmt.Errorf("read token Secret %s: %w", config.TokenSecretRef.Name, err)
+	}
+	token := strings.TrimSpace(string(secret.Data[config.TokenSecretRef.Key]))
+	if token == "" {
+		return fmt.Errorf("key %q of Secret %s is empty", config.TokenSecretRef.Key, config.TokenSecretRef.Name)
+	}
+	return deploystatus.Report(ctx, config.Provider, config.BaseURL, token, deployment)
+}
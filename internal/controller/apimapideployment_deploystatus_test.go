@@ -0,0 +1,93 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	apimv1 "github.com/hedinit/azure-apim-operator/api/v1"
+	"github.com/hedinit/azure-apim-operator/internal/notify"
+)
+
+func TestNotifyImportReportsDeploymentStatus(t *testing.T) {
+	var states []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/deployments") {
+			_, _ = w.Write([]byte(`{"id": 7}`))
+			return
+		}
+		var status map[string]string
+		if err := json.NewDecoder(r.Body).Decode(&status); err != nil {
+			t.Errorf("decode deployment status: %v", err)
+		}
+		states = append(states, status["state"])
+	}))
+	defer server.Close()
+
+	t.Setenv("OPERATOR_NAMESPACE", "apim-system")
+	scheme := runtime.NewScheme()
+	if err := apimv1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	if err := corev1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	svc := &apimv1.APIMService{
+		ObjectMeta: metav1.ObjectMeta{Name: "apim-prod", Namespace: "apim-system"},
+		Spec: apimv1.APIMServiceSpec{DeploymentStatus: &apimv1.APIMServiceDeploymentStatus{
+			Provider:       "github",
+			Environment:    "production",
+			TokenSecretRef: apimv1.SecretKeyRef{Name: "github", Key: "token"},
+			BaseURL:        server.URL,
+		}},
+	}
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "github", Namespace: "apim-system"},
+		Data:       map[string][]byte{"token": []byte("gh-token")},
+	}
+	recorder := record.NewFakeRecorder(1)
+	r := &APIMAPIDeploymentReconciler{
+		Client:   fake.NewClientBuilder().WithScheme(scheme).WithObjects(svc, secret).Build(),
+		Recorder: recorder,
+	}
+	apimApi := &apimv1.APIMAPI{ObjectMeta: metav1.ObjectMeta{Name: "orders", Namespace: "shop"}}
+	deployment := &apimv1.APIMAPIDeployment{Spec: apimv1.APIMAPIDeploymentSpec{APIMService: "apim-prod", APIID: "shop-orders"}}
+	ctx := context.Background()
+
+	// Without source annotations nothing is reported.
+	r.notifyImport(ctx, apimApi, deployment, notify.ResultSuccess, "", nil)
+	if len(states) != 0 {
+		t.Fatalf("reported %v without source annotations, want nothing", states)
+	}
+
+	apimApi.Annotations = map[string]string{
+		apimSourceRepositoryAnnotation: "contoso/orders",
+		apimSourceCommitAnnotation:     "abc123",
+	}
+	r.notifyImport(ctx, apimApi, deployment, notify.ResultSuccess, "", nil)
+	r.notifyImport(ctx, apimApi, deployment, notify.ResultFailure, importReasonImport, errors.New("definition rejected"))
+	if want := []string{"success", "failure"}; strings.Join(states, ",") != strings.Join(want, ",") {
+		t.Errorf("reported states %v, want %v", states, want)
+	}
+
+	apimApi.Annotations[apimSourceRepositoryAnnotation] = "orders"
+	r.notifyImport(ctx, apimApi, deployment, notify.ResultSuccess, "", nil)
+	select {
+	case event := <-recorder.Events:
+		if !strings.Contains(event, eventReasonDeploymentStatusFailed) {
+			t.Errorf("event = %q, want %s", event, eventReasonDeploymentStatusFailed)
+		}
+	default:
+		t.Error("no Warning Event for a deployment status that couldn't be reported")
+	}
+}
@@ -0,0 +1,48 @@
+package controller
+
+import (
+	"fmt"
+	"net/url"
+
+	apimv1 "github.com/hedinit/azure-apim-operator/api/v1"
+	"github.com/hedinit/azure-apim-operator/internal/openapi"
+)
+
+// maxUndocumentedOperations bounds status.documentation.undocumentedOperations, so a large definition
+// without summaries can't bloat the APIMAPI.
+const maxUndocumentedOperations = 20
+
+// developerPortalAPIURL returns the page of the API apiID in the developer portal at developerPortalHost,
+// or "" if the service has no developer portal.
+func developerPortalAPIURL(developerPortalHost, apiID string) string {
+	if developerPortalHost == "" {
+		return ""
+	}
+	return fmt.Sprintf("https://%s/api-details#api=%s", developerPortalHost, url.QueryEscape(apiID))
+}
+
+// apiDescriptionPatch returns the description to set on the imported API, or "" when the description the
+// import set from info.description is complete.
+func apiDescriptionPatch(docs openapi.Documentation) string {
+	if description := docs.APIDescription(); description != docs.Description {
+		return description
+	}
+	return ""
+}
+
+// documentationStatus returns the documentation metadata of docs for the APIMAPI status.
+func documentationStatus(docs openapi.Documentation) *apimv1.APIMAPIDocumentation {
+	status := &apimv1.APIMAPIDocumentation{
+		Title:      docs.Title,
+		Operations: int32(len(docs.Operations)),
+	}
+	if docs.ExternalDocs != nil {
+		status.ExternalDocsURL = docs.ExternalDocs.URL
+	}
+	undocumented := docs.Undocumented()
+	if len(undocumented) > maxUndocumentedOperations {
+		undocumented = undocumented[:maxUndocumentedOperations]
+	}
+	status.UndocumentedOperations = undocumented
+	return status
+}
@@ -0,0 +1,43 @@
+package controller
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hedinit/azure-apim-operator/internal/openapi"
+)
+
+func TestDeveloperPortalAPIURL(t *testing.T) {
+	if got, want := developerPortalAPIURL("contoso.developer.azure-api.net", "orders"), "https://contoso.developer.azure-api.net/api-details#api=orders"; got != want {
+		t.Errorf("developerPortalAPIURL() = %q, want %q", got, want)
+	}
+	if got := developerPortalAPIURL("", "orders"); got != "" {
+		t.Errorf("developerPortalAPIURL() without a developer portal = %q, want none", got)
+	}
+}
+
+func TestAPIDescriptionPatch(t *testing.T) {
+	if got := apiDescriptionPatch(openapi.Documentation{Description: "Orders"}); got != "" {
+		t.Errorf("apiDescriptionPatch() without external docs = %q, want none", got)
+	}
+	docs := openapi.Documentation{Description: "Orders", ExternalDocs: &openapi.ExternalDocs{URL: "https://docs.example.com"}}
+	if got, want := apiDescriptionPatch(docs), "Orders\n\n[Further documentation](https://docs.example.com)"; got != want {
+		t.Errorf("apiDescriptionPatch() = %q, want %q", got, want)
+	}
+}
+
+func TestDocumentationStatus(t *testing.T) {
+	docs := openapi.Documentation{Title: "Orders", ExternalDocs: &openapi.ExternalDocs{URL: "https://docs.example.com"}}
+	for i := range 25 {
+		docs.Operations = append(docs.Operations, openapi.OperationDoc{Method: "GET", Path: fmt.Sprintf("/orders/%02d", i)})
+	}
+	docs.Operations = append(docs.Operations, openapi.OperationDoc{Method: "POST", Path: "/orders", Summary: "Create an order"})
+
+	got := documentationStatus(docs)
+	if got.Title != "Orders" || got.ExternalDocsURL != "https://docs.example.com" || got.Operations != 26 {
+		t.Errorf("documentationStatus() = %+v, want the title, the external docs and 26 operations", got)
+	}
+	if len(got.UndocumentedOperations) != maxUndocumentedOperations || got.UndocumentedOperations[0] != "GET /orders/00" {
+		t.Errorf("undocumentedOperations = %v, want the first %d", got.UndocumentedOperations, maxUndocumentedOperations)
+	}
+}
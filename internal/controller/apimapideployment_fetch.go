@@ -0,0 +1,152 @@
+package controller
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	apimv1 "github.com/hedinit/azure-apim-operator/api/v1"
+	"github.com/hedinit/azure-apim-operator/internal/redact"
+)
+
+const (
+	// DefaultMaxOpenAPIDefinitionBytes is the default size limit of a downloaded OpenAPI definition.
+	DefaultMaxOpenAPIDefinitionBytes = 32 << 20
+
+	// openAPIErrorBodyLimit bounds how much of an error response is read into the fetch error.
+	openAPIErrorBodyLimit = 4 << 10
+
+	// openAPIFetchMaxAttempts is how many failed fetches in a row mark a deployment as failed.
+	openAPIFetchMaxAttempts = 5
+	// openAPIFetchRetryBase is the delay after the first failed fetch. It doubles with each further failure.
+	openAPIFetchRetryBase = 2 * time.Second
+)
+
+// maxOpenAPIDefinitionBytes is the size limit of a downloaded OpenAPI definition. See SetMaxOpenAPIDefinitionBytes.
+var maxOpenAPIDefinitionBytes int64 = DefaultMaxOpenAPIDefinitionBytes
+
+// SetMaxOpenAPIDefinitionBytes sets the size limit of a downloaded OpenAPI definition. Larger definitions
+// fail the fetch instead of being buffered. It must be called before the manager starts.
+func SetMaxOpenAPIDefinitionBytes(limit int64) error {
+	if limit < 1 {
+		return fmt.Errorf("OpenAPI definition size limit must be positive, got %d", limit)
+	}
+	maxOpenAPIDefinitionBytes = limit
+	return nil
+}
+
+// openAPIValidators are the cache validators of a fetched OpenAPI definition, used to ask its server
+// whether the definition changed since.
+type openAPIValidators struct {
+	// URL is the definition URL the validators were returned for.
+	URL string
+	// ETag and LastModified are the ETag and Last-Modified response headers.
+	ETag         string
+	LastModified string
+	// Hash is the hash of the definition the validators describe.
+	Hash string
+}
+
+// openAPIFetchResult is the outcome of fetching an OpenAPI definition.
+type openAPIFetchResult struct {
+	// Content is the definition. It is empty when NotModified is set.
+	Content []byte
+	// Hash is the hex SHA-256 of Content.
+	Hash string
+	// ETag and LastModified are the validators returned with Content, if any.
+	ETag         string
+	LastModified string
+	// NotModified is set when the server answered a conditional GET with 304.
+	NotModified bool
+}
+
+// conditionalFetchValidators returns the validators to send with the next fetch of the deployment's
+// definition, or nil when it has to be downloaded unconditionally. A 304 only helps when the definition
+// is the one thing that could have changed since the last import: otherwise the import needs the content.
+// The same holds for deployments not yet verified against APIM since startup, which may need a re-import.
+func (r *APIMAPIDeploymentReconciler) conditionalFetchValidators(
+	deployment *apimv1.APIMAPIDeployment,
+	subscription, resourceGroup string,
+) *openAPIValidators {
+	value, ok := r.openAPIValidators.Load(deployment.UID)
+	if !ok {
+		return nil
+	}
+	validators := value.(*openAPIValidators)
+	if validators.URL != deployment.Spec.OpenAPIDefinitionURL || deployment.Status.AppliedHash == "" ||
+		r.needsStartupVerification(deployment) {
+		return nil
+	}
+	hash, err := buildDesiredAPIMStateHash(&deployment.Spec, subscription, resourceGroup, validators.Hash)
+	if err != nil || hash != deployment.Status.AppliedHash {
+		return nil
+	}
+	return validators
+}
+
+// rememberOpenAPIValidators keeps the validators of a downloaded definition for the next conditional GET.
+// Servers that return neither an ETag nor Last-Modified are always fetched in full.
+func (r *APIMAPIDeploymentReconciler) rememberOpenAPIValidators(deployment *apimv1.APIMAPIDeployment, result openAPIFetchResult, hash string) {
+	if result.ETag == "" && result.LastModified == "" {
+		r.openAPIValidators.Delete(deployment.UID)
+		return
+	}
+	r.openAPIValidators.Store(deployment.UID, &openAPIValidators{
+		URL:          deployment.Spec.OpenAPIDefinitionURL,
+		ETag:         result.ETag,
+		LastModified: result.LastModified,
+		Hash:         hash,
+	})
+}
+
+// openAPIFetchRetryAfter returns how long to wait before fetching again after the given number of failed
+// fetches in a row (2s, 4s, 8s, 16s), and false once openAPIFetchMaxAttempts is reached.
+func openAPIFetchRetryAfter(failures int32) (time.Duration, bool) {
+	if failures < 1 || failures >= openAPIFetchMaxAttempts {
+		return 0, false
+	}
+	return openAPIFetchRetryBase << (failures - 1), true
+}
+
+// readOpenAPIResponse reads the answer to an OpenAPI definition fetch. The definition is read once,
+// hashed while it is buffered, and rejected as soon as it exceeds maxOpenAPIDefinitionBytes. It is not
+// streamed into the import: its hash decides whether APIM is called at all. See docs/architecture.md.
+// A 304 is only expected when the request was conditional.
+func readOpenAPIResponse(resp *http.Response, conditional bool) (openAPIFetchResult, error) {
+	if resp.StatusCode == http.StatusNotModified && conditional {
+		return openAPIFetchResult{NotModified: true}, nil
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, openAPIErrorBodyLimit))
+		return openAPIFetchResult{}, fmt.Errorf("unexpected status: %s\nbody: %s", resp.Status, redact.String(string(body)))
+	}
+
+	limit := maxOpenAPIDefinitionBytes
+	if resp.ContentLength > limit {
+		return openAPIFetchResult{}, fmt.Errorf("OpenAPI definition is %d bytes, more than the limit of %d bytes",
+			resp.ContentLength, limit)
+	}
+	var content bytes.Buffer
+	if resp.ContentLength > 0 {
+		content.Grow(int(resp.ContentLength))
+	}
+	hash := sha256.New()
+	n, err := io.Copy(io.MultiWriter(&content, hash), io.LimitReader(resp.Body, limit+1))
+	if err != nil {
+		return openAPIFetchResult{}, fmt.Errorf("read body error: %w", err)
+	}
+	if n > limit {
+		return openAPIFetchResult{}, fmt.Errorf("OpenAPI definition is more than the limit of %d bytes", limit)
+	}
+
+	return openAPIFetchResult{
+		Content:      content.Bytes(),
+		Hash:         hex.EncodeToString(hash.Sum(nil)),
+		ETag:         resp.Header.Get("ETag"),
+		LastModified: resp.Header.Get("Last-Modified"),
+	}, nil
+}
@@ -0,0 +1,134 @@
+package controller
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apimv1 "github.com/hedinit/azure-apim-operator/api/v1"
+)
+
+func TestFetchOpenAPIDefinitionConditionalGet(t *testing.T) {
+	const etag = `"v1"`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-None-Match") == etag {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", etag)
+		w.Header().Set("Last-Modified", "Mon, 12 Jan 2026 09:30:00 GMT")
+		_, _ = w.Write([]byte(`{"openapi":"3.0.0"}`))
+	}))
+	defer server.Close()
+
+	fetched, err := fetchOpenAPIDefinition(context.Background(), server.URL, nil)
+	if err != nil {
+		t.Fatalf("unconditional fetch: %v", err)
+	}
+	if fetched.NotModified || string(fetched.Content) != `{"openapi":"3.0.0"}` ||
+		fetched.ETag != etag || fetched.LastModified != "Mon, 12 Jan 2026 09:30:00 GMT" {
+		t.Fatalf("unconditional fetch = %+v, want the content and its validators", fetched)
+	}
+
+	fetched, err = fetchOpenAPIDefinition(context.Background(), server.URL, &openAPIValidators{ETag: etag})
+	if err != nil {
+		t.Fatalf("conditional fetch: %v", err)
+	}
+	if !fetched.NotModified || fetched.Content != nil {
+		t.Fatalf("conditional fetch = %+v, want NotModified without content", fetched)
+	}
+}
+
+func TestConditionalFetchValidators(t *testing.T) {
+	const url = "https://orders.example.com/swagger.json"
+	spec := apimv1.APIMAPIDeploymentSpec{APIID: "orders", APIMService: "apim", OpenAPIDefinitionURL: url}
+	appliedHash, err := buildDesiredAPIMStateHash(&spec, "sub", "rg", "content-hash")
+	if err != nil {
+		t.Fatal(err)
+	}
+	deployment := &apimv1.APIMAPIDeployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "orders", UID: "uid-1"},
+		Spec:       spec,
+		Status:     apimv1.APIMAPIDeploymentStatus{AppliedHash: appliedHash},
+	}
+
+	r := &APIMAPIDeploymentReconciler{}
+	r.rememberOpenAPIValidators(deployment, openAPIFetchResult{ETag: `"v1"`}, "content-hash")
+	if r.conditionalFetchValidators(deployment, "sub", "rg") != nil {
+		t.Error("expected an unconditional fetch before the deployment is verified since startup")
+	}
+
+	r.markStartupVerified(deployment)
+	if v := r.conditionalFetchValidators(deployment, "sub", "rg"); v == nil || v.ETag != `"v1"` {
+		t.Errorf("conditionalFetchValidators() = %+v, want the remembered ETag", v)
+	}
+
+	changed := deployment.DeepCopy()
+	changed.Spec.ServiceURL = "https://orders-v2.internal"
+	if r.conditionalFetchValidators(changed, "sub", "rg") != nil {
+		t.Error("expected an unconditional fetch when another input changed, since the import needs the content")
+	}
+
+	r.rememberOpenAPIValidators(deployment, openAPIFetchResult{}, "content-hash")
+	if r.conditionalFetchValidators(deployment, "sub", "rg") != nil {
+		t.Error("expected no conditional fetch when the server returned no validators")
+	}
+}
+
+func TestOpenAPIFetchRetryAfter(t *testing.T) {
+	tests := []struct {
+		failures  int32
+		want      time.Duration
+		wantRetry bool
+	}{
+		{failures: 0, want: 0, wantRetry: false},
+		{failures: 1, want: 2 * time.Second, wantRetry: true},
+		{failures: 2, want: 4 * time.Second, wantRetry: true},
+		{failures: 4, want: 16 * time.Second, wantRetry: true},
+		{failures: openAPIFetchMaxAttempts, want: 0, wantRetry: false},
+	}
+	for _, tt := range tests {
+		got, retry := openAPIFetchRetryAfter(tt.failures)
+		if got != tt.want || retry != tt.wantRetry {
+			t.Errorf("openAPIFetchRetryAfter(%d) = %s, %t; want %s, %t", tt.failures, got, retry, tt.want, tt.wantRetry)
+		}
+	}
+}
+
+func TestReadOpenAPIResponseSizeLimit(t *testing.T) {
+	defer func(limit int64) { maxOpenAPIDefinitionBytes = limit }(maxOpenAPIDefinitionBytes)
+	if err := SetMaxOpenAPIDefinitionBytes(8); err != nil {
+		t.Fatal(err)
+	}
+
+	response := func(body string, contentLength int64) *http.Response {
+		return &http.Response{
+			StatusCode:    http.StatusOK,
+			ContentLength: contentLength,
+			Body:          io.NopCloser(strings.NewReader(body)),
+		}
+	}
+
+	fetched, err := readOpenAPIResponse(response(`{"a":1}`, 7), false)
+	if err != nil {
+		t.Fatalf("definition within the limit: %v", err)
+	}
+	if string(fetched.Content) != `{"a":1}` || fetched.Hash != sha256Hex(fetched.Content) {
+		t.Errorf("readOpenAPIResponse() = %+v, want the content and its hash", fetched)
+	}
+	if _, err := readOpenAPIResponse(response(`{"a":100}`, 9), false); err == nil {
+		t.Error("definition with a Content-Length over the limit was accepted")
+	}
+	if _, err := readOpenAPIResponse(response(`{"a":100}`, -1), false); err == nil {
+		t.Error("definition of unknown length over the limit was accepted")
+	}
+	if err := SetMaxOpenAPIDefinitionBytes(0); err == nil {
+		t.Error("SetMaxOpenAPIDefinitionBytes(0) succeeded, want an error")
+	}
+}
@@ -0,0 +1,101 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apimv1 "github.com/hedinit/azure-apim-operator/api/v1"
+	"github.com/hedinit/azure-apim-operator/internal/apim"
+	"github.com/hedinit/azure-apim-operator/internal/notify"
+	"github.com/hedinit/azure-apim-operator/internal/redact"
+)
+
+// eventReasonNotificationFailed is the Warning Event reason for an import outcome that couldn't be posted.
+const eventReasonNotificationFailed = "NotificationFailed"
+
+// +kubebuilder:rbac:groups="",resources=secrets,verbs=get
+
+// notifyImport posts the outcome of an import to the notifications of the targeted APIMService and
+// reports it to the source repository of the APIMAPI, see reportDeploymentStatus.
+// result is notify.ResultSuccess or notify.ResultFailure; step and err describe a failure.
+// A failure that repeats the failed step of the previous attempt is not posted again, so a broken
+// deployment that is retried every minute posts once. Notifications that can't be posted are
+// recorded as Warning Events; they never fail the import. Dry runs are not posted.
+func (r *APIMAPIDeploymentReconciler) notifyImport(
+	ctx context.Context,
+	apimApi *apimv1.APIMAPI,
+	deployment *apimv1.APIMAPIDeployment,
+	result, step string,
+	err error,
+) {
+	if apim.IsDryRun() {
+		return
+	}
+	if result == notify.ResultFailure && deployment.Status.LastErrorDetails != nil && deployment.Status.LastErrorDetails.Step == step {
+		return
+	}
+	operatorNamespace, nsErr := getOperatorNamespace()
+	if nsErr != nil {
+		return
+	}
+	var apimService apimv1.APIMService
+	hub := hubClient(r.Client, r.Hub)
+	if getErr := hub.Get(ctx, client.ObjectKey{Name: deployment.Spec.APIMService, Namespace: operatorNamespace}, &apimService); getErr != nil {
+		return
+	}
+	if len(apimService.Spec.Notifications) == 0 && apimService.Spec.DeploymentStatus == nil {
+		return
+	}
+
+	outcome := notify.ImportOutcome{
+		Result:      result,
+		APIMService: apimService.Name,
+		Namespace:   apimApi.Namespace,
+		Name:        apimApi.Name,
+		APIID:       deployment.Spec.APIID,
+		GatewayURL:  apimApi.Status.ApiHost,
+		Time:        time.Now().UTC().Format(time.RFC3339),
+	}
+	if result == notify.ResultFailure {
+		outcome.Step = step
+		outcome.Reason = failureReason(step, err)
+		outcome.Error, _, _ = strings.Cut(redact.String(err.Error()), "\n")
+	}
+
+	reader := client.Reader(hub)
+	if r.APIReader != nil {
+		reader = r.APIReader
+	}
+	logger := ctrl.Log.WithName("apimapideployment_controller")
+	for _, target := range apimService.Spec.Notifications {
+		if len(target.On) > 0 && !slices.Contains(target.On, result) {
+			continue
+		}
+		if sendErr := sendNotification(ctx, reader, operatorNamespace, target, outcome); sendErr != nil {
+			logger.Error(sendErr, "⚠️ Failed to post import notification", "apiID", deployment.Spec.APIID, "notification", target.Name)
+			recordWarningEvent(r.Recorder, apimApi, eventReasonNotificationFailed,
+				fmt.Sprintf("Failed to post notification %q of APIMService %s: %v", target.Name, apimService.Name, sendErr))
+		}
+	}
+	r.reportDeploymentStatus(ctx, reader, operatorNamespace, &apimService, apimApi, outcome)
+}
+
+// sendNotification reads the webhook URL of target from its Secret and posts outcome to it.
+func sendNotification(ctx context.Context, reader client.Reader, namespace string, target apimv1.APIMServiceNotification, outcome notify.ImportOutcome) error {
+	var secret corev1.Secret
+	if err := reader.Get(ctx, client.ObjectKey{Name: target.URLSecretRef.Name, Namespace: namespace}, &secret); err != nil {
+		return fmt.Errorf("read URL Secret %s: %w", target.URLSecretRef.Name, err)
+	}
+	url := strings.TrimSpace(string(secret.Data[target.URLSecretRef.Key]))
+	if url == "" {
+		return fmt.Errorf("key %q of Secret %s is empty", target.URLSecretRef.Key, target.URLSecretRef.Name)
+	}
+	return notify.Send(ctx, target.Format, url, outcome)
+}
@@ -0,0 +1,89 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	apimv1 "github.com/hedinit/azure-apim-operator/api/v1"
+	"github.com/hedinit/azure-apim-operator/internal/notify"
+)
+
+func TestNotifyImport(t *testing.T) {
+	var posted []notify.ImportOutcome
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var outcome notify.ImportOutcome
+		if err := json.NewDecoder(r.Body).Decode(&outcome); err != nil {
+			t.Errorf("decode notification: %v", err)
+		}
+		posted = append(posted, outcome)
+	}))
+	defer server.Close()
+
+	t.Setenv("OPERATOR_NAMESPACE", "apim-system")
+	scheme := runtime.NewScheme()
+	if err := apimv1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	if err := corev1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	svc := &apimv1.APIMService{
+		ObjectMeta: metav1.ObjectMeta{Name: "apim-prod", Namespace: "apim-system"},
+		Spec: apimv1.APIMServiceSpec{Notifications: []apimv1.APIMServiceNotification{
+			{Name: "failures", URLSecretRef: apimv1.SecretKeyRef{Name: "hooks", Key: "url"}, On: []string{notify.ResultFailure}},
+			{Name: "broken", URLSecretRef: apimv1.SecretKeyRef{Name: "hooks", Key: "missing"}, On: []string{notify.ResultSuccess}},
+		}},
+	}
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "hooks", Namespace: "apim-system"},
+		Data:       map[string][]byte{"url": []byte(server.URL)},
+	}
+	recorder := record.NewFakeRecorder(1)
+	r := &APIMAPIDeploymentReconciler{
+		Client:   fake.NewClientBuilder().WithScheme(scheme).WithObjects(svc, secret).Build(),
+		Recorder: recorder,
+	}
+	apimApi := &apimv1.APIMAPI{ObjectMeta: metav1.ObjectMeta{Name: "orders", Namespace: "shop"}}
+	deployment := &apimv1.APIMAPIDeployment{Spec: apimv1.APIMAPIDeploymentSpec{APIMService: "apim-prod", APIID: "shop-orders"}}
+	ctx := context.Background()
+
+	r.notifyImport(ctx, apimApi, deployment, notify.ResultFailure, importReasonImport, errors.New("definition rejected\nbody"))
+	if len(posted) != 1 {
+		t.Fatalf("posted %d notifications, want 1", len(posted))
+	}
+	if got := posted[0]; got.APIID != "shop-orders" || got.Step != importReasonImport || got.Error != "definition rejected" {
+		t.Errorf("posted %+v, want the failed import step and the first error line", got)
+	}
+
+	// A retry that fails at the same step is not posted again.
+	deployment.Status.LastErrorDetails = &apimv1.APIMAPIDeploymentErrorDetails{Step: importReasonImport}
+	r.notifyImport(ctx, apimApi, deployment, notify.ResultFailure, importReasonImport, errors.New("definition rejected"))
+	if len(posted) != 1 {
+		t.Errorf("posted %d notifications after a repeated failure, want 1", len(posted))
+	}
+
+	// Success only goes to the second notification, whose Secret key is missing.
+	r.notifyImport(ctx, apimApi, deployment, notify.ResultSuccess, "", nil)
+	if len(posted) != 1 {
+		t.Errorf("posted %d notifications after success, want 1", len(posted))
+	}
+	select {
+	case event := <-recorder.Events:
+		if !strings.Contains(event, eventReasonNotificationFailed) {
+			t.Errorf("event = %q, want %s", event, eventReasonNotificationFailed)
+		}
+	default:
+		t.Error("no Warning Event for a notification that couldn't be posted")
+	}
+}
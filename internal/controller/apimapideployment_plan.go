@@ -0,0 +1,160 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	apimv1 "github.com/hedinit/azure-apim-operator/api/v1"
+)
+
+const (
+	apimDeploymentPhaseAwaitingApproval = "AwaitingApproval"
+	apimAPIStatusPendingApproval        = "PendingApproval"
+	apimApprovalAnnotation              = "apim.operator.io/approve"
+)
+
+// isPlanApproved reports whether the APIMAPI approves applying the desired state identified by planHash.
+func isPlanApproved(apimApi *apimv1.APIMAPI, planHash string) bool {
+	return apimApi.Annotations[apimApprovalAnnotation] == planHash
+}
+
+// buildAppliedState snapshots the deployment inputs that make up the desired APIM state.
+func buildAppliedState(spec *apimv1.APIMAPIDeploymentSpec, openAPIHash string) *apimv1.APIMAPIDeploymentAppliedState {
+	productIDs := append([]string(nil), spec.ProductIDs...)
+	tagIDs := append([]string(nil), spec.TagIDs...)
+	sort.Strings(productIDs)
+	sort.Strings(tagIDs)
+
+	return &apimv1.APIMAPIDeploymentAppliedState{
+		ServiceURL:           spec.ServiceURL,
+		RoutePrefix:          spec.RoutePrefix,
+		Revision:             spec.Revision,
+		SubscriptionRequired: spec.SubscriptionRequired,
+		ProductIDs:           productIDs,
+		TagIDs:               tagIDs,
+		OpenAPIHash:          openAPIHash,
+		VersionSetID:         spec.VersionSetID,
+		APIVersion:           spec.APIVersion,
+	}
+}
+
+// computePlanChanges describes the differences between the last applied state and the desired state
+// in a human-readable form suitable for review before approval.
+func computePlanChanges(applied, desired *apimv1.APIMAPIDeploymentAppliedState) []string {
+	if applied == nil {
+		return []string{"import API (no previously applied state recorded)"}
+	}
+
+	var changes []string
+	if applied.OpenAPIHash != desired.OpenAPIHash {
+		changes = append(changes, "openApi: definition changed")
+	}
+	if applied.ServiceURL != desired.ServiceURL {
+		changes = append(changes, fmt.Sprintf("serviceUrl: %q -> %q", applied.ServiceURL, desired.ServiceURL))
+	}
+	if applied.RoutePrefix != desired.RoutePrefix {
+		changes = append(changes, fmt.Sprintf("routePrefix: %q -> %q", applied.RoutePrefix, desired.RoutePrefix))
+	}
+	if applied.Revision != desired.Revision {
+		changes = append(changes, fmt.Sprintf("revision: %q -> %q", applied.Revision, desired.Revision))
+	}
+	if applied.SubscriptionRequired != desired.SubscriptionRequired {
+		changes = append(changes, fmt.Sprintf("subscriptionRequired: %t -> %t", applied.SubscriptionRequired, desired.SubscriptionRequired))
+	}
+	if applied.VersionSetID != desired.VersionSetID {
+		changes = append(changes, fmt.Sprintf("versionSetId: %q -> %q", applied.VersionSetID, desired.VersionSetID))
+	}
+	if applied.APIVersion != desired.APIVersion {
+		changes = append(changes, fmt.Sprintf("apiVersion: %q -> %q", applied.APIVersion, desired.APIVersion))
+	}
+	changes = append(changes, describeSetChanges("products", applied.ProductIDs, desired.ProductIDs)...)
+	changes = append(changes, describeSetChanges("tags", applied.TagIDs, desired.TagIDs)...)
+
+	if len(changes) == 0 {
+		changes = append(changes, "APIM target changed")
+	}
+	return changes
+}
+
+// describeSetChanges lists the IDs added to and removed from a set.
+func describeSetChanges(kind string, applied, desired []string) []string {
+	appliedSet := make(map[string]struct{}, len(applied))
+	for _, id := range applied {
+		appliedSet[id] = struct{}{}
+	}
+	desiredSet := make(map[string]struct{}, len(desired))
+	for _, id := range desired {
+		desiredSet[id] = struct{}{}
+	}
+
+	var added, removed []string
+	for _, id := range desired {
+		if _, ok := appliedSet[id]; !ok {
+			added = append(added, id)
+		}
+	}
+	for _, id := range applied {
+		if _, ok := desiredSet[id]; !ok {
+			removed = append(removed, id)
+		}
+	}
+	sort.Strings(added)
+	sort.Strings(removed)
+
+	var changes []string
+	if len(added) > 0 {
+		changes = append(changes, fmt.Sprintf("%s added: %s", kind, strings.Join(added, ", ")))
+	}
+	if len(removed) > 0 {
+		changes = append(changes, fmt.Sprintf("%s removed: %s", kind, strings.Join(removed, ", ")))
+	}
+	return changes
+}
+
+// publishPlan writes the pending plan to the APIMAPI status and parks the deployment until the plan is approved.
+func (r *APIMAPIDeploymentReconciler) publishPlan(
+	ctx context.Context,
+	deployment *apimv1.APIMAPIDeployment,
+	statusBatch *deploymentStatusBatch,
+	apimApi *apimv1.APIMAPI,
+	openAPIHash string,
+	desiredHash string,
+	setStatus func(*apimv1.APIMAPIDeploymentStatus),
+) (ctrl.Result, error) {
+	logger := ctrl.Log.WithName("apimapideployment_controller")
+
+	plan := &apimv1.APIMAPIPlan{
+		Hash:      desiredHash,
+		Changes:   computePlanChanges(deployment.Status.AppliedState, buildAppliedState(&deployment.Spec, openAPIHash)),
+		CreatedAt: time.Now().UTC().Format(time.RFC3339),
+	}
+	// Keep the original timestamp while the same plan is pending so repeated reconciles don't churn status.
+	if current := apimApi.Status.PendingPlan; current != nil && current.Hash == plan.Hash {
+		plan.CreatedAt = current.CreatedAt
+	}
+
+	apimApi.Status.Status = apimAPIStatusPendingApproval
+	apimApi.Status.PendingPlan = plan
+	if err := applyAPIMAPIStatus(ctx, r.Client, apimApi); err != nil {
+		logger.Error(err, "⚠️ Failed to patch APIMAPI status", "apiID", deployment.Spec.APIID)
+		return ctrl.Result{}, err
+	}
+
+	message := fmt.Sprintf("Plan %s awaits approval; annotate APIMAPI %s with %s=%s to apply it",
+		desiredHash, apimApi.Name, apimApprovalAnnotation, desiredHash)
+	statusBatch.update(func(status *apimv1.APIMAPIDeploymentStatus) {
+		setStatus(status)
+		status.Phase = apimDeploymentPhaseAwaitingApproval
+		status.Status = apimDeploymentStatusPending
+		status.Message = message
+		status.LastError = ""
+	})
+
+	logger.Info("📝 Plan awaiting approval", "apiID", deployment.Spec.APIID, "planHash", desiredHash, "changes", plan.Changes)
+	return ctrl.Result{}, nil
+}
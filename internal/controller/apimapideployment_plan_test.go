@@ -0,0 +1,94 @@
+package controller
+
+import (
+	"reflect"
+	"testing"
+
+	apimv1 "github.com/hedinit/azure-apim-operator/api/v1"
+)
+
+func TestComputePlanChanges(t *testing.T) {
+	applied := &apimv1.APIMAPIDeploymentAppliedState{
+		ServiceURL:           "https://old.example.com",
+		RoutePrefix:          "/payments",
+		SubscriptionRequired: true,
+		ProductIDs:           []string{"gold", "silver"},
+		TagIDs:               []string{"payments"},
+		OpenAPIHash:          "aaa",
+	}
+
+	tests := []struct {
+		name    string
+		applied *apimv1.APIMAPIDeploymentAppliedState
+		desired apimv1.APIMAPIDeploymentAppliedState
+		want    []string
+	}{
+		{
+			name:    "first import",
+			applied: nil,
+			desired: *applied,
+			want:    []string{"import API (no previously applied state recorded)"},
+		},
+		{
+			name:    "nothing visible changed",
+			applied: applied,
+			desired: *applied,
+			want:    []string{"APIM target changed"},
+		},
+		{
+			name:    "field and set changes",
+			applied: applied,
+			desired: apimv1.APIMAPIDeploymentAppliedState{
+				ServiceURL:           "https://new.example.com",
+				RoutePrefix:          "/payments",
+				SubscriptionRequired: false,
+				ProductIDs:           []string{"bronze", "gold"},
+				TagIDs:               []string{"payments"},
+				OpenAPIHash:          "bbb",
+			},
+			want: []string{
+				"openApi: definition changed",
+				`serviceUrl: "https://old.example.com" -> "https://new.example.com"`,
+				"subscriptionRequired: true -> false",
+				"products added: bronze",
+				"products removed: silver",
+			},
+		},
+		{
+			name:    "moved into a version set",
+			applied: applied,
+			desired: func() apimv1.APIMAPIDeploymentAppliedState {
+				desired := *applied
+				desired.VersionSetID = "payments"
+				desired.APIVersion = "v2"
+				return desired
+			}(),
+			want: []string{
+				`versionSetId: "" -> "payments"`,
+				`apiVersion: "" -> "v2"`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			desired := tt.desired
+			got := computePlanChanges(tt.applied, &desired)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("computePlanChanges() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsPlanApproved(t *testing.T) {
+	apimApi := &apimv1.APIMAPI{}
+	apimApi.Annotations = map[string]string{apimApprovalAnnotation: "abc"}
+
+	if !isPlanApproved(apimApi, "abc") {
+		t.Fatalf("expected plan abc to be approved")
+	}
+	if isPlanApproved(apimApi, "def") {
+		t.Fatalf("expected plan def to need a fresh approval")
+	}
+}
@@ -0,0 +1,56 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"k8s.io/apimachinery/pkg/types"
+
+	apimv1 "github.com/hedinit/azure-apim-operator/api/v1"
+	"github.com/hedinit/azure-apim-operator/internal/apim"
+)
+
+// needsStartupVerification reports whether the deployment has not been checked against Azure since the
+// operator started. The informer's initial list enqueues every APIMAPIDeployment on startup, so each one
+// is compared against APIM exactly once per process even when its desired hash is unchanged.
+func (r *APIMAPIDeploymentReconciler) needsStartupVerification(deployment *apimv1.APIMAPIDeployment) bool {
+	_, verified := r.verifiedSinceStartup.Load(startupVerificationKey(deployment))
+	return !verified
+}
+
+// markStartupVerified records that the deployment was checked against Azure in this process.
+func (r *APIMAPIDeploymentReconciler) markStartupVerified(deployment *apimv1.APIMAPIDeployment) {
+	r.verifiedSinceStartup.Store(startupVerificationKey(deployment), struct{}{})
+}
+
+func startupVerificationKey(deployment *apimv1.APIMAPIDeployment) types.UID {
+	return deployment.UID
+}
+
+// apiExistsInAPIM reports whether the API of an already-applied deployment still exists in APIM.
+// It is used to converge APIs that were changed or deleted in Azure while the operator was down.
+func (r *APIMAPIDeploymentReconciler) apiExistsInAPIM(ctx context.Context, deployment *apimv1.APIMAPIDeployment) (bool, error) {
+	clientID := os.Getenv("AZURE_CLIENT_ID")
+	tenantID := os.Getenv("AZURE_TENANT_ID")
+	if clientID == "" || tenantID == "" {
+		return false, fmt.Errorf("missing AZURE_CLIENT_ID or AZURE_TENANT_ID")
+	}
+	token, err := managementToken(ctx, r.Tokens, clientID, tenantID)
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", errMsgFailedToGetAzureToken, err)
+	}
+
+	existing, err := r.APIM.GetAPIDetails(ctx, apim.APIMDeploymentConfig{
+		SubscriptionID: deployment.Spec.Subscription,
+		ResourceGroup:  deployment.Spec.ResourceGroup,
+		ServiceName:    deployment.Spec.APIMService,
+		APIID:          deployment.Spec.APIID,
+		BearerToken:    token,
+	})
+	if err != nil {
+		invalidateRejectedToken(r.Tokens, err)
+		return false, err
+	}
+	return existing != nil, nil
+}
@@ -0,0 +1,26 @@
+package controller
+
+import (
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apimv1 "github.com/hedinit/azure-apim-operator/api/v1"
+)
+
+func TestStartupVerificationHappensOncePerDeployment(t *testing.T) {
+	r := &APIMAPIDeploymentReconciler{}
+	first := &apimv1.APIMAPIDeployment{ObjectMeta: metav1.ObjectMeta{Name: "payments", UID: "uid-1"}}
+	recreated := &apimv1.APIMAPIDeployment{ObjectMeta: metav1.ObjectMeta{Name: "payments", UID: "uid-2"}}
+
+	if !r.needsStartupVerification(first) {
+		t.Fatalf("expected a fresh reconciler to verify the deployment")
+	}
+	r.markStartupVerified(first)
+	if r.needsStartupVerification(first) {
+		t.Fatalf("expected the deployment to be verified only once")
+	}
+	if !r.needsStartupVerification(recreated) {
+		t.Fatalf("expected a recreated deployment with a new UID to be verified again")
+	}
+}
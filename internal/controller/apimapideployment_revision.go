@@ -0,0 +1,17 @@
+package controller
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// revisionPattern matches the positive integer revision numbers APIM accepts in ";rev=<n>".
+var revisionPattern = regexp.MustCompile(`^[1-9][0-9]*$`)
+
+// validateRevision reports why a revision would be rejected by APIM. An empty revision means the current revision.
+func validateRevision(revision string) error {
+	if revision == "" || revisionPattern.MatchString(revision) {
+		return nil
+	}
+	return fmt.Errorf("revision %q must be a positive integer such as \"2\"", revision)
+}
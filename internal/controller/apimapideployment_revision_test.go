@@ -0,0 +1,25 @@
+package controller
+
+import "testing"
+
+func TestValidateRevision(t *testing.T) {
+	tests := []struct {
+		revision  string
+		wantError bool
+	}{
+		{revision: ""},
+		{revision: "1"},
+		{revision: "12"},
+		{revision: "0", wantError: true},
+		{revision: "01", wantError: true},
+		{revision: "-1", wantError: true},
+		{revision: "v2", wantError: true},
+		{revision: "2 ", wantError: true},
+	}
+
+	for _, tt := range tests {
+		if err := validateRevision(tt.revision); tt.wantError != (err != nil) {
+			t.Errorf("validateRevision(%q) error = %v, wantError %t", tt.revision, err, tt.wantError)
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	appsv1 "k8s.io/api/apps/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apimv1 "github.com/hedinit/azure-apim-operator/api/v1"
+)
+
+const (
+	// apimDeploymentPhaseNoChange marks a deployment whose latest rollout was skipped because it runs the
+	// same pod template as the last successful import.
+	apimDeploymentPhaseNoChange = "NoChange"
+
+	// apimDeploymentRolloutHashAnnotation holds the pod template hash of the ReplicaSet that last signaled the deployment.
+	apimDeploymentRolloutHashAnnotation = "apim.operator.io/rollout-hash"
+
+	// podTemplateHashLabel is added to the pod template of every ReplicaSet, so it never matches between two of them.
+	podTemplateHashLabel = "pod-template-hash"
+)
+
+// replicaSetRolloutHash hashes the pod template of a ReplicaSet, leaving out the label the Deployment controller
+// adds. The kubectl.kubernetes.io/restartedAt annotation is kept: kubectl rollout restart of a mutable image tag
+// with imagePullPolicy Always ships new code without another change to the template, so a restart is always
+// reconciled, and the conditional GET of the definition decides whether APIM is called.
+// ReplicaSets read from the manager cache have no pod template; their hash was recorded by transformReplicaSet.
+func replicaSetRolloutHash(rs *appsv1.ReplicaSet) (string, error) {
+	if hash, ok := rs.Annotations[cachedRolloutHashAnnotation]; ok {
+		return hash, nil
+	}
+	template := rs.Spec.Template.DeepCopy()
+	delete(template.Labels, podTemplateHashLabel)
+	payload, err := json.Marshal(template)
+	if err != nil {
+		return "", fmt.Errorf("marshal pod template of ReplicaSet %s: %w", rs.Name, err)
+	}
+	return sha256Hex(payload), nil
+}
+
+// isUnchangedRollout reports whether a rollout with rolloutHash would repeat the last successful import
+// of the deployment. apimApi must be the APIMAPI the deployment was just synced from: a newer generation
+// means its spec changed, which always needs a reconcile.
+func isUnchangedRollout(deployment *apimv1.APIMAPIDeployment, apimApi *apimv1.APIMAPI, rolloutHash string) bool {
+	status := &deployment.Status
+	if status.Phase != apimDeploymentPhaseSucceeded && status.Phase != apimDeploymentPhaseNoChange {
+		return false
+	}
+	return rolloutHash != "" &&
+		status.AppliedRolloutHash == rolloutHash &&
+		status.AppliedHash != "" &&
+		status.AppliedHash == status.DesiredHash &&
+		status.ObservedGeneration == apimApi.Generation
+}
+
+// recordNoChange marks a deployment whose rollout was skipped by the ReplicaSet watcher.
+func recordNoChange(ctx context.Context, c client.Client, deployment *apimv1.APIMAPIDeployment, replicaSetName string) error {
+	return updateAPIMAPIDeploymentStatus(ctx, c, deployment, func(status *apimv1.APIMAPIDeploymentStatus) {
+		status.Phase = apimDeploymentPhaseNoChange
+		status.Status = "OK"
+		status.Message = fmt.Sprintf("ReplicaSet %s runs the same pod template as the last import; skipped", replicaSetName)
+		status.LastError = ""
+	})
+}
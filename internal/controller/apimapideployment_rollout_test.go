@@ -0,0 +1,132 @@
+package controller
+
+import (
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apimv1 "github.com/hedinit/azure-apim-operator/api/v1"
+)
+
+func TestReplicaSetRolloutHash(t *testing.T) {
+	replicaSet := func(templateHash, restartedAt, image string) *appsv1.ReplicaSet {
+		rs := &appsv1.ReplicaSet{ObjectMeta: metav1.ObjectMeta{Name: "web-" + templateHash}}
+		rs.Spec.Template.Labels = map[string]string{"app": "web", podTemplateHashLabel: templateHash}
+		if restartedAt != "" {
+			rs.Spec.Template.Annotations = map[string]string{"kubectl.kubernetes.io/restartedAt": restartedAt}
+		}
+		rs.Spec.Template.Spec.Containers = []corev1.Container{{Name: "web", Image: image}}
+		return rs
+	}
+	hash := func(rs *appsv1.ReplicaSet) string {
+		t.Helper()
+		h, err := replicaSetRolloutHash(rs)
+		if err != nil {
+			t.Fatalf("replicaSetRolloutHash() error = %v", err)
+		}
+		return h
+	}
+
+	base := hash(replicaSet("aaa", "", "web:1"))
+	if got := hash(replicaSet("bbb", "", "web:1")); got != base {
+		t.Errorf("pod-template-hash changed the rollout hash: %s != %s", got, base)
+	}
+	// A restart of web:latest with imagePullPolicy Always may run new code from the same template.
+	if got := hash(replicaSet("bbb", "2025-01-01T12:00:00Z", "web:1")); got == base {
+		t.Error("kubectl rollout restart kept the rollout hash")
+	}
+	if got := hash(replicaSet("ccc", "", "web:2")); got == base {
+		t.Error("new image kept the rollout hash")
+	}
+
+	rs := replicaSet("aaa", "", "web:1")
+	hash(rs)
+	if rs.Spec.Template.Labels[podTemplateHashLabel] != "aaa" {
+		t.Error("replicaSetRolloutHash() modified the ReplicaSet")
+	}
+}
+
+func TestIsUnchangedRollout(t *testing.T) {
+	apimApi := &apimv1.APIMAPI{ObjectMeta: metav1.ObjectMeta{Generation: 3}}
+	synced := func(mutate func(*apimv1.APIMAPIDeploymentStatus)) *apimv1.APIMAPIDeployment {
+		deployment := &apimv1.APIMAPIDeployment{Status: apimv1.APIMAPIDeploymentStatus{
+			Phase:              apimDeploymentPhaseSucceeded,
+			AppliedRolloutHash: "r1",
+			DesiredHash:        "d1",
+			AppliedHash:        "d1",
+			ObservedGeneration: 3,
+		}}
+		if mutate != nil {
+			mutate(&deployment.Status)
+		}
+		return deployment
+	}
+
+	tests := []struct {
+		name        string
+		deployment  *apimv1.APIMAPIDeployment
+		rolloutHash string
+		want        bool
+	}{
+		{name: "unchanged", deployment: synced(nil), rolloutHash: "r1", want: true},
+		{name: "already skipped", deployment: synced(func(s *apimv1.APIMAPIDeploymentStatus) {
+			s.Phase = apimDeploymentPhaseNoChange
+		}), rolloutHash: "r1", want: true},
+		{name: "new pod template", deployment: synced(nil), rolloutHash: "r2", want: false},
+		{name: "no rollout hash", deployment: synced(func(s *apimv1.APIMAPIDeploymentStatus) {
+			s.AppliedRolloutHash = ""
+		}), rolloutHash: "", want: false},
+		{name: "last import failed", deployment: synced(func(s *apimv1.APIMAPIDeploymentStatus) {
+			s.Phase = phaseError
+		}), rolloutHash: "r1", want: false},
+		{name: "desired state not applied", deployment: synced(func(s *apimv1.APIMAPIDeploymentStatus) {
+			s.DesiredHash = "d2"
+		}), rolloutHash: "r1", want: false},
+		{name: "APIMAPI spec changed", deployment: synced(func(s *apimv1.APIMAPIDeploymentStatus) {
+			s.ObservedGeneration = 2
+		}), rolloutHash: "r1", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isUnchangedRollout(tt.deployment, apimApi, tt.rolloutHash); got != tt.want {
+				t.Fatalf("isUnchangedRollout() = %t, want %t", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRestartOfUnchangedTemplateIsReconciled(t *testing.T) {
+	rs := &appsv1.ReplicaSet{ObjectMeta: metav1.ObjectMeta{Name: "web-aaa"}}
+	rs.Spec.Template.Labels = map[string]string{"app": "web", podTemplateHashLabel: "aaa"}
+	rs.Spec.Template.Spec.Containers = []corev1.Container{{
+		Name: "web", Image: "web:latest", ImagePullPolicy: corev1.PullAlways,
+	}}
+	applied, err := replicaSetRolloutHash(rs)
+	if err != nil {
+		t.Fatalf("replicaSetRolloutHash() error = %v", err)
+	}
+
+	restarted := rs.DeepCopy()
+	restarted.Name = "web-bbb"
+	restarted.Spec.Template.Labels[podTemplateHashLabel] = "bbb"
+	restarted.Spec.Template.Annotations = map[string]string{"kubectl.kubernetes.io/restartedAt": "2026-03-01T12:00:00Z"}
+	rolloutHash, err := replicaSetRolloutHash(restarted)
+	if err != nil {
+		t.Fatalf("replicaSetRolloutHash() error = %v", err)
+	}
+
+	apimApi := &apimv1.APIMAPI{ObjectMeta: metav1.ObjectMeta{Generation: 1}}
+	deployment := &apimv1.APIMAPIDeployment{Status: apimv1.APIMAPIDeploymentStatus{
+		Phase:              apimDeploymentPhaseSucceeded,
+		AppliedRolloutHash: applied,
+		DesiredHash:        "d1",
+		AppliedHash:        "d1",
+		ObservedGeneration: 1,
+	}}
+	if isUnchangedRollout(deployment, apimApi, rolloutHash) {
+		t.Error("isUnchangedRollout() = true for kubectl rollout restart, want the definition to be fetched again")
+	}
+}
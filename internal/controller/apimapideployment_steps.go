@@ -0,0 +1,64 @@
+package controller
+
+import (
+	"context"
+	"errors"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// deploymentStepConcurrency bounds how many configuration steps of one deployment call APIM at once.
+const deploymentStepConcurrency = 4
+
+// deploymentStep is an APIM call that configures an imported API independently of the other steps.
+type deploymentStep struct {
+	// reason names the step in the import metrics and in status.lastErrorDetails.
+	reason string
+	// message describes a failure of the step in the deployment status.
+	message string
+	run     func(ctx context.Context) error
+}
+
+// deploymentStepFailure is the step whose failure ended runDeploymentSteps, with its error.
+type deploymentStepFailure struct {
+	step deploymentStep
+	err  error
+}
+
+func (f *deploymentStepFailure) Error() string { return f.err.Error() }
+
+// runDeploymentSteps runs steps concurrently, at most deploymentStepConcurrency at a time. The first
+// failure cancels the steps still running and is returned; nil means every step succeeded.
+func runDeploymentSteps(ctx context.Context, steps []deploymentStep) *deploymentStepFailure {
+	g, ctx := errgroup.WithContext(ctx)
+	g.SetLimit(deploymentStepConcurrency)
+	for _, step := range steps {
+		g.Go(func() error {
+			if err := step.run(ctx); err != nil {
+				var failure *deploymentStepFailure
+				if errors.As(err, &failure) {
+					return failure
+				}
+				return &deploymentStepFailure{step: step, err: err}
+			}
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return err.(*deploymentStepFailure)
+	}
+	return nil
+}
+
+// sequentialSteps returns a step that runs steps one after another. Writes to the same APIM entity go
+// through it, so the ETag of the last write is the entity's ETag. A failure names the step that failed.
+func sequentialSteps(steps ...deploymentStep) deploymentStep {
+	return deploymentStep{run: func(ctx context.Context) error {
+		for _, step := range steps {
+			if err := step.run(ctx); err != nil {
+				return &deploymentStepFailure{step: step, err: err}
+			}
+		}
+		return nil
+	}}
+}
@@ -0,0 +1,82 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestRunDeploymentStepsRunsConcurrently(t *testing.T) {
+	// Each step waits until all have started, so this only returns if they run at the same time.
+	var started sync.WaitGroup
+	started.Add(deploymentStepConcurrency)
+	steps := make([]deploymentStep, deploymentStepConcurrency)
+	for i := range steps {
+		steps[i] = deploymentStep{reason: "step", run: func(ctx context.Context) error {
+			started.Done()
+			started.Wait()
+			return nil
+		}}
+	}
+
+	done := make(chan *deploymentStepFailure)
+	go func() { done <- runDeploymentSteps(context.Background(), steps) }()
+	select {
+	case failure := <-done:
+		if failure != nil {
+			t.Fatalf("runDeploymentSteps() = %v, want nil", failure)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("runDeploymentSteps() did not run the steps concurrently")
+	}
+}
+
+func TestRunDeploymentStepsReportsFailedStep(t *testing.T) {
+	tagErr := errors.New("tag assignment failed")
+	steps := []deploymentStep{
+		{reason: importReasonServiceURL, run: func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		}},
+		{reason: importReasonTagAssignment, message: "Failed to assign API to tags", run: func(context.Context) error {
+			return tagErr
+		}},
+	}
+
+	failure := runDeploymentSteps(context.Background(), steps)
+	if failure == nil {
+		t.Fatal("runDeploymentSteps() = nil, want the tag assignment failure")
+	}
+	if failure.step.reason != importReasonTagAssignment || !errors.Is(failure.err, tagErr) {
+		t.Errorf("runDeploymentSteps() = step %q, error %v, want the tag assignment and its error", failure.step.reason, failure.err)
+	}
+}
+
+func TestSequentialStepsRunInOrder(t *testing.T) {
+	descriptionErr := errors.New("description patch failed")
+	var ran []string
+	record := func(reason string, err error) deploymentStep {
+		return deploymentStep{reason: reason, run: func(context.Context) error {
+			ran = append(ran, reason)
+			return err
+		}}
+	}
+	steps := []deploymentStep{sequentialSteps(
+		record(importReasonServiceURL, nil),
+		record(importReasonSubscriptionRequired, nil),
+		record(importReasonDocumentation, descriptionErr),
+		record(importReasonTagAssignment, nil),
+	)}
+
+	failure := runDeploymentSteps(context.Background(), steps)
+	if failure == nil || failure.step.reason != importReasonDocumentation || !errors.Is(failure.err, descriptionErr) {
+		t.Fatalf("runDeploymentSteps() = %v, want the description failure", failure)
+	}
+	want := []string{importReasonServiceURL, importReasonSubscriptionRequired, importReasonDocumentation}
+	if !slices.Equal(ran, want) {
+		t.Errorf("steps ran = %v, want %v", ran, want)
+	}
+}
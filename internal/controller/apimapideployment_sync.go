@@ -5,11 +5,14 @@ import (
 	"crypto/sha256"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"sort"
 	"time"
 
 	apimv1 "github.com/hedinit/azure-apim-operator/api/v1"
+	"github.com/hedinit/azure-apim-operator/internal/apim"
+	"github.com/hedinit/azure-apim-operator/internal/redact"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/equality"
@@ -40,9 +43,14 @@ type apimDeploymentHashInput struct {
 	ProductIDs           []string `json:"productIds,omitempty"`
 	TagIDs               []string `json:"tagIds,omitempty"`
 	OpenAPIHash          string   `json:"openApiHash"`
+	// Version set fields are omitted when unset, so the hashes of unversioned APIs stay the same.
+	VersionSetID string `json:"versionSetId,omitempty"`
+	APIVersion   string `json:"apiVersion,omitempty"`
 }
 
-func ensureAPIMAPIDeployment(ctx context.Context, c client.Client, apimAPI *apimv1.APIMAPI) (*apimv1.APIMAPIDeployment, error) {
+// ensureAPIMAPIDeployment creates or updates the APIMAPIDeployment of apimAPI with c. The APIMService is
+// read with hub, which differs from c for APIMAPIs of remote clusters.
+func ensureAPIMAPIDeployment(ctx context.Context, c client.Client, hub client.Reader, apimAPI *apimv1.APIMAPI) (*apimv1.APIMAPIDeployment, error) {
 	key := client.ObjectKey{Name: apimAPI.Name, Namespace: apimAPI.Namespace}
 	deployment := &apimv1.APIMAPIDeployment{}
 	getErr := c.Get(ctx, key, deployment)
@@ -50,13 +58,13 @@ func ensureAPIMAPIDeployment(ctx context.Context, c client.Client, apimAPI *apim
 		return nil, getErr
 	}
 
-	subscription, resourceGroup, err := resolveAPIMServiceLocation(ctx, c, apimAPI.Spec.APIMService, deployment.Spec.Subscription, deployment.Spec.ResourceGroup)
+	subscription, resourceGroup, err := resolveAPIMServiceLocation(ctx, hub, apimAPI.Spec.APIMService, deployment.Spec.Subscription, deployment.Spec.ResourceGroup)
 	if err != nil {
 		return nil, err
 	}
 
 	desiredSpec := apimv1.APIMAPIDeploymentSpec{
-		ServiceURL:           apimAPI.Spec.ServiceURL,
+		ServiceURL:           apimAPI.Spec.ActiveServiceURL(),
 		RoutePrefix:          apimAPI.Spec.RoutePrefix,
 		OpenAPIDefinitionURL: apimAPI.Spec.OpenAPIDefinitionURL,
 		ProductIDs:           append([]string(nil), apimAPI.Spec.ProductIDs...),
@@ -67,15 +75,24 @@ func ensureAPIMAPIDeployment(ctx context.Context, c client.Client, apimAPI *apim
 		ResourceGroup:        resourceGroup,
 		APIID:                apimAPI.Spec.APIID,
 		SubscriptionRequired: apimAPI.Spec.SubscriptionRequired,
+		AdoptionPolicy:       apimAPI.Spec.AdoptionPolicy,
+		ApprovalRequired:     apimAPI.Spec.ApprovalRequired,
+		Canary:               apimAPI.Spec.Canary.DeepCopy(),
+		VersionSetID:         apimAPI.Spec.VersionSetID,
+		APIVersion:           apimAPI.Spec.APIVersion,
 	}
 	desiredOwnerReferences := []metav1.OwnerReference{*metav1.NewControllerRef(apimAPI, apimv1.GroupVersion.WithKind("APIMAPI"))}
 
 	if apierrors.IsNotFound(getErr) {
+		annotations := syncMetadataKey(nil, apimAPI.Annotations, apimApprovalAnnotation)
+		annotations = syncMetadataKey(annotations, apimAPI.Annotations, apimAllowBreakingChangesAnnotation)
 		deployment = &apimv1.APIMAPIDeployment{
 			ObjectMeta: metav1.ObjectMeta{
 				Name:            apimAPI.Name,
 				Namespace:       apimAPI.Namespace,
 				OwnerReferences: desiredOwnerReferences,
+				Annotations:     annotations,
+				Labels:          syncMetadataKey(nil, apimAPI.Labels, priorityLabel),
 			},
 			Spec: desiredSpec,
 		}
@@ -88,7 +105,13 @@ func ensureAPIMAPIDeployment(ctx context.Context, c client.Client, apimAPI *apim
 	updated := deployment.DeepCopy()
 	updated.Spec = desiredSpec
 	updated.OwnerReferences = desiredOwnerReferences
-	if equality.Semantic.DeepEqual(deployment.Spec, updated.Spec) && equality.Semantic.DeepEqual(deployment.OwnerReferences, updated.OwnerReferences) {
+	updated.Annotations = syncMetadataKey(updated.Annotations, apimAPI.Annotations, apimApprovalAnnotation)
+	updated.Annotations = syncMetadataKey(updated.Annotations, apimAPI.Annotations, apimAllowBreakingChangesAnnotation)
+	updated.Labels = syncMetadataKey(updated.Labels, apimAPI.Labels, priorityLabel)
+	if equality.Semantic.DeepEqual(deployment.Spec, updated.Spec) &&
+		equality.Semantic.DeepEqual(deployment.OwnerReferences, updated.OwnerReferences) &&
+		equality.Semantic.DeepEqual(deployment.Annotations, updated.Annotations) &&
+		equality.Semantic.DeepEqual(deployment.Labels, updated.Labels) {
 		return deployment, nil
 	}
 	if err := c.Patch(ctx, updated, client.MergeFrom(deployment)); err != nil {
@@ -96,16 +119,34 @@ func ensureAPIMAPIDeployment(ctx context.Context, c client.Client, apimAPI *apim
 	}
 	deployment.Spec = updated.Spec
 	deployment.OwnerReferences = updated.OwnerReferences
+	deployment.Annotations = updated.Annotations
+	deployment.Labels = updated.Labels
 	return deployment, nil
 }
 
-func touchAPIMAPIDeployment(ctx context.Context, c client.Client, deployment *apimv1.APIMAPIDeployment, replicaSetName string) error {
+// syncMetadataKey mirrors a single annotation or label from the APIMAPI onto the deployment metadata,
+// e.g. so that approving a plan triggers a deployment reconcile and priority follows the APIMAPI.
+func syncMetadataKey(target, source map[string]string, key string) map[string]string {
+	value, ok := source[key]
+	if !ok {
+		delete(target, key)
+		return target
+	}
+	if target == nil {
+		target = map[string]string{}
+	}
+	target[key] = value
+	return target
+}
+
+func touchAPIMAPIDeployment(ctx context.Context, c client.Client, deployment *apimv1.APIMAPIDeployment, replicaSetName, rolloutHash string) error {
 	updated := deployment.DeepCopy()
 	if updated.Annotations == nil {
 		updated.Annotations = map[string]string{}
 	}
 	updated.Annotations[apimDeploymentSignalAnnotation] = time.Now().UTC().Format(time.RFC3339Nano)
 	updated.Annotations[apimDeploymentReplicaSetAnnotation] = replicaSetName
+	updated.Annotations[apimDeploymentRolloutHashAnnotation] = rolloutHash
 	if equality.Semantic.DeepEqual(deployment.Annotations, updated.Annotations) {
 		return nil
 	}
@@ -116,9 +157,10 @@ func touchAPIMAPIDeployment(ctx context.Context, c client.Client, deployment *ap
 	return nil
 }
 
+// updateAPIMAPIDeploymentStatus applies mutate to a copy of the deployment status and patches it if anything changed.
 func updateAPIMAPIDeploymentStatus(ctx context.Context, c client.Client, deployment *apimv1.APIMAPIDeployment, mutate func(*apimv1.APIMAPIDeploymentStatus)) error {
 	updated := deployment.DeepCopy()
-	mutate(&updated.Status)
+	mutateAPIMAPIDeploymentStatus(&updated.Status, mutate)
 	if equality.Semantic.DeepEqual(deployment.Status, updated.Status) {
 		return nil
 	}
@@ -129,6 +171,69 @@ func updateAPIMAPIDeploymentStatus(ctx context.Context, c client.Client, deploym
 	return nil
 }
 
+// mutateAPIMAPIDeploymentStatus applies mutate to status.
+// Message and LastError often quote Azure error bodies, so they are redacted before they are stored.
+// LastErrorDetails is dropped together with LastError.
+func mutateAPIMAPIDeploymentStatus(status *apimv1.APIMAPIDeploymentStatus, mutate func(*apimv1.APIMAPIDeploymentStatus)) {
+	mutate(status)
+	status.Message = redact.String(status.Message)
+	status.LastError = redact.String(status.LastError)
+	if status.LastError == "" {
+		status.LastErrorDetails = nil
+	}
+}
+
+// deploymentStatusBatch collects the status changes of one APIMAPIDeployment reconcile, so a pass that
+// moves through several phases patches the status once instead of once per phase.
+type deploymentStatusBatch struct {
+	client     client.Client
+	deployment *apimv1.APIMAPIDeployment
+	// written is the status as last written, which the pending changes are diffed against.
+	written apimv1.APIMAPIDeploymentStatus
+}
+
+// newDeploymentStatusBatch starts collecting status changes of deployment.
+func newDeploymentStatusBatch(c client.Client, deployment *apimv1.APIMAPIDeployment) *deploymentStatusBatch {
+	return &deploymentStatusBatch{client: c, deployment: deployment, written: *deployment.Status.DeepCopy()}
+}
+
+// update applies mutate to the deployment status in memory. The change is written by the next flush.
+func (b *deploymentStatusBatch) update(mutate func(*apimv1.APIMAPIDeploymentStatus)) {
+	mutateAPIMAPIDeploymentStatus(&b.deployment.Status, mutate)
+}
+
+// flush patches the status changes collected since the last flush, if there are any.
+func (b *deploymentStatusBatch) flush(ctx context.Context) error {
+	if equality.Semantic.DeepEqual(b.written, b.deployment.Status) {
+		return nil
+	}
+	base := b.deployment.DeepCopy()
+	base.Status = b.written
+	updated := b.deployment.DeepCopy()
+	if err := b.client.Status().Patch(ctx, updated, client.MergeFrom(base)); err != nil {
+		return err
+	}
+	b.written = *b.deployment.Status.DeepCopy()
+	return nil
+}
+
+// newLastErrorDetails describes a failure at step for status.lastErrorDetails. The HTTP status,
+// Azure error code and request ID are taken from err when it wraps an apim.ResponseError.
+func newLastErrorDetails(step string, err error) *apimv1.APIMAPIDeploymentErrorDetails {
+	details := &apimv1.APIMAPIDeploymentErrorDetails{
+		Step:       step,
+		Reason:     failureReason(step, err),
+		OccurredAt: time.Now().UTC().Format(time.RFC3339),
+	}
+	var respErr *apim.ResponseError
+	if errors.As(err, &respErr) {
+		details.HTTPStatus = int32(respErr.StatusCode)
+		details.Code = respErr.Code
+		details.RequestID = respErr.RequestID
+	}
+	return details
+}
+
 func buildDesiredAPIMStateHash(spec *apimv1.APIMAPIDeploymentSpec, subscription string, resourceGroup string, openAPIHash string) (string, error) {
 	productIDs := append([]string(nil), spec.ProductIDs...)
 	tagIDs := append([]string(nil), spec.TagIDs...)
@@ -147,6 +252,8 @@ func buildDesiredAPIMStateHash(spec *apimv1.APIMAPIDeploymentSpec, subscription
 		ProductIDs:           productIDs,
 		TagIDs:               tagIDs,
 		OpenAPIHash:          openAPIHash,
+		VersionSetID:         spec.VersionSetID,
+		APIVersion:           spec.APIVersion,
 	}
 
 	encoded, err := json.Marshal(payload)
@@ -189,33 +296,34 @@ func findMatchingReplicaSetsForAPIMAPI(ctx context.Context, c client.Client, api
 	return matches, nil
 }
 
+// findReadyPodForReplicaSets returns a running, ready pod owned by one of the replica sets, or nil.
+// Pods are listed with each replica set's own selector, which includes its pod-template-hash, so the
+// lookup only copies the pods of these replica sets instead of every pod in the namespace.
 func findReadyPodForReplicaSets(ctx context.Context, c client.Client, replicaSets []appsv1.ReplicaSet) (*corev1.Pod, error) {
-	if len(replicaSets) == 0 {
-		return nil, nil
-	}
-
-	replicaSetNames := make(map[string]struct{}, len(replicaSets))
-	namespace := replicaSets[0].Namespace
-	for _, replicaSet := range replicaSets {
-		replicaSetNames[replicaSet.Name] = struct{}{}
-	}
-
-	var podList corev1.PodList
-	if err := c.List(ctx, &podList, client.InNamespace(namespace)); err != nil {
-		return nil, err
-	}
-
-	for _, pod := range podList.Items {
-		if pod.Status.Phase != corev1.PodRunning || !isPodReady(&pod) {
+	for i := range replicaSets {
+		replicaSet := &replicaSets[i]
+		selector, err := metav1.LabelSelectorAsSelector(replicaSet.Spec.Selector)
+		if err != nil {
+			return nil, fmt.Errorf("invalid selector of ReplicaSet %s: %w", replicaSet.Name, err)
+		}
+		// An empty selector matches every pod; the API server rejects such replica sets anyway.
+		if selector.Empty() {
 			continue
 		}
-		for _, ref := range pod.OwnerReferences {
-			if ref.Kind != "ReplicaSet" {
+
+		var podList corev1.PodList
+		if err := c.List(ctx, &podList, client.InNamespace(replicaSet.Namespace), client.MatchingLabelsSelector{Selector: selector}); err != nil {
+			return nil, err
+		}
+		for _, pod := range podList.Items {
+			if pod.Status.Phase != corev1.PodRunning || !isPodReady(&pod) {
 				continue
 			}
-			if _, ok := replicaSetNames[ref.Name]; ok {
-				podCopy := pod
-				return &podCopy, nil
+			for _, ref := range pod.OwnerReferences {
+				if ref.Kind == "ReplicaSet" && ref.Name == replicaSet.Name {
+					podCopy := pod
+					return &podCopy, nil
+				}
 			}
 		}
 	}
@@ -240,7 +348,7 @@ func matchedReplicaSetNames(replicaSets []appsv1.ReplicaSet) []string {
 	return names
 }
 
-func resolveAPIMServiceLocation(ctx context.Context, c client.Client, apimServiceName string, currentSubscription string, currentResourceGroup string) (string, string, error) {
+func resolveAPIMServiceLocation(ctx context.Context, c client.Reader, apimServiceName string, currentSubscription string, currentResourceGroup string) (string, string, error) {
 	operatorNamespace, err := getOperatorNamespace()
 	if err != nil {
 		return currentSubscription, currentResourceGroup, err
@@ -0,0 +1,139 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	apimv1 "github.com/hedinit/azure-apim-operator/api/v1"
+	"github.com/hedinit/azure-apim-operator/internal/apim"
+)
+
+func TestNewLastErrorDetails(t *testing.T) {
+	respErr := &apim.ResponseError{StatusCode: 409, Code: "Conflict", RequestID: "4f1c2d3e-request"}
+	details := newLastErrorDetails(importReasonProductAssignment, fmt.Errorf("assign: %w", respErr))
+	if details.Step != importReasonProductAssignment || details.HTTPStatus != 409 ||
+		details.Code != "Conflict" || details.RequestID != "4f1c2d3e-request" || details.Reason != apimv1.ReasonAzureError {
+		t.Errorf("newLastErrorDetails() = %+v, want the step and the Azure response details", details)
+	}
+	if details.OccurredAt == "" {
+		t.Error("newLastErrorDetails() did not set OccurredAt")
+	}
+
+	details = newLastErrorDetails(importReasonToken, errors.New("token expired"))
+	if details.Step != importReasonToken || details.HTTPStatus != 0 || details.RequestID != "" || details.Reason != apimv1.ReasonAuthFailed {
+		t.Errorf("newLastErrorDetails() = %+v, want only the step for errors without an Azure response", details)
+	}
+}
+
+func TestFindReadyPodForReplicaSets(t *testing.T) {
+	replicaSet := func(name, hash string) *appsv1.ReplicaSet {
+		labels := map[string]string{"app": "orders", podTemplateHashLabel: hash}
+		return &appsv1.ReplicaSet{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "shop", UID: types.UID("uid-" + hash)},
+			Spec:       appsv1.ReplicaSetSpec{Selector: &metav1.LabelSelector{MatchLabels: labels}},
+		}
+	}
+	pod := func(name string, rs *appsv1.ReplicaSet, ready bool) client.Object {
+		status := corev1.ConditionFalse
+		if ready {
+			status = corev1.ConditionTrue
+		}
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            name,
+				Namespace:       rs.Namespace,
+				Labels:          rs.Spec.Selector.MatchLabels,
+				OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(rs, appsv1.SchemeGroupVersion.WithKind("ReplicaSet"))},
+			},
+			Status: corev1.PodStatus{
+				Phase:      corev1.PodRunning,
+				Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: status}},
+			},
+		}
+	}
+
+	oldRS, newRS := replicaSet("orders-old", "aaa"), replicaSet("orders-new", "bbb")
+	c := fake.NewClientBuilder().WithScheme(clientgoscheme.Scheme).WithObjects(
+		pod("orders-old-1", oldRS, true),
+		pod("orders-new-1", newRS, false),
+		pod("orders-new-2", newRS, true),
+	).Build()
+
+	got, err := findReadyPodForReplicaSets(context.Background(), c, []appsv1.ReplicaSet{*newRS})
+	if err != nil {
+		t.Fatalf("findReadyPodForReplicaSets() error = %v", err)
+	}
+	if got == nil || got.Name != "orders-new-2" {
+		t.Fatalf("findReadyPodForReplicaSets() = %v, want the ready pod of the new ReplicaSet", got)
+	}
+
+	got, err = findReadyPodForReplicaSets(context.Background(), c, []appsv1.ReplicaSet{*replicaSet("orders-next", "ccc")})
+	if err != nil || got != nil {
+		t.Fatalf("findReadyPodForReplicaSets() = %v, %v; want no pod for a ReplicaSet without pods", got, err)
+	}
+}
+
+func TestDeploymentStatusBatch(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := apimv1.AddToScheme(scheme); err != nil {
+		t.Fatalf("AddToScheme() error = %v", err)
+	}
+	deployment := &apimv1.APIMAPIDeployment{ObjectMeta: metav1.ObjectMeta{Name: "orders", Namespace: "shop"}}
+	patches := 0
+	c := fake.NewClientBuilder().WithScheme(scheme).
+		WithObjects(deployment).
+		WithStatusSubresource(deployment).
+		WithInterceptorFuncs(interceptor.Funcs{
+			SubResourcePatch: func(ctx context.Context, c client.Client, subResourceName string, obj client.Object, patch client.Patch, opts ...client.SubResourcePatchOption) error {
+				patches++
+				return c.SubResource(subResourceName).Patch(ctx, obj, patch, opts...)
+			},
+		}).
+		Build()
+	ctx := context.Background()
+	if err := c.Get(ctx, client.ObjectKeyFromObject(deployment), deployment); err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+
+	batch := newDeploymentStatusBatch(c, deployment)
+	batch.update(func(status *apimv1.APIMAPIDeploymentStatus) {
+		status.Phase = phaseError
+		status.LastError = "fetch failed"
+	})
+	batch.update(func(status *apimv1.APIMAPIDeploymentStatus) {
+		status.Phase = apimDeploymentPhaseSucceeded
+		status.LastError = ""
+	})
+	if patches != 0 {
+		t.Fatalf("update() patched the status %d times, want no patch before flush", patches)
+	}
+	if err := batch.flush(ctx); err != nil {
+		t.Fatalf("flush() error = %v", err)
+	}
+	if err := batch.flush(ctx); err != nil {
+		t.Fatalf("flush() error = %v", err)
+	}
+	if patches != 1 {
+		t.Errorf("status patched %d times, want 1", patches)
+	}
+
+	var stored apimv1.APIMAPIDeployment
+	if err := c.Get(ctx, client.ObjectKeyFromObject(deployment), &stored); err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if stored.Status.Phase != apimDeploymentPhaseSucceeded || stored.Status.LastError != "" {
+		t.Errorf("stored status = %+v, want the last phase of the batch", stored.Status)
+	}
+}
@@ -0,0 +1,175 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	apimv1 "github.com/hedinit/azure-apim-operator/api/v1"
+	"github.com/hedinit/azure-apim-operator/internal/apim"
+	"github.com/hedinit/azure-apim-operator/internal/identity"
+	"github.com/hedinit/azure-apim-operator/internal/redact"
+)
+
+// APIMApiVersionSetReconciler reconciles APIMApiVersionSet custom resources.
+// This controller manages version sets in Azure API Management, which APIMAPI resources join with
+// versionSetId and apiVersion.
+type APIMApiVersionSetReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+	// Recorder records Warning Events for failed syncs. Nil disables them.
+	Recorder record.EventRecorder
+	// APIM sends the Azure Management API requests. Nil sends them to the public endpoint.
+	APIM *apim.Client
+	// Tokens provides the Azure Management API token shared by all reconcilers. Nil asks Azure AD on
+	// every reconcile.
+	Tokens identity.TokenProvider
+}
+
+// +kubebuilder:rbac:groups=apim.operator.io,resources=apimapiversionsets,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=apim.operator.io,resources=apimapiversionsets/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=apim.operator.io,resources=apimapiversionsets/finalizers,verbs=update
+
+// Reconcile creates or updates the version set in APIM whenever the spec of the APIMApiVersionSet changes.
+func (r *APIMApiVersionSetReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	var versionSet apimv1.APIMApiVersionSet
+	if err := r.Get(ctx, req.NamespacedName, &versionSet); err != nil {
+		if apierrors.IsNotFound(err) {
+			logger.Info("🧹 APIMApiVersionSet deleted, skipping", "name", req.NamespacedName)
+			forgetSyncMetrics(kindAPIMApiVersionSet, req.Namespace, req.Name)
+			return ctrl.Result{}, nil
+		}
+		logger.Error(err, "❌ Failed to get APIMApiVersionSet")
+		return ctrl.Result{}, err
+	}
+	setSpanAPIAttributes(ctx, versionSet.Spec.APIMService, "")
+
+	operatorNamespace, err := getOperatorNamespace()
+	if err != nil {
+		logger.Error(err, "❌ Failed to get operator namespace")
+		return ctrl.Result{}, fmt.Errorf("get operator namespace: %w", err)
+	}
+
+	var apimService apimv1.APIMService
+	if err := r.Get(ctx, client.ObjectKey{Name: versionSet.Spec.APIMService, Namespace: operatorNamespace}, &apimService); err != nil {
+		logger.Error(err, "❌ Failed to get APIMService", "name", versionSet.Spec.APIMService)
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	observeAPIMService(ctx, &apimService)
+
+	clientID := os.Getenv("AZURE_CLIENT_ID")
+	tenantID := os.Getenv("AZURE_TENANT_ID")
+	if clientID == "" || tenantID == "" {
+		logger.Error(fmt.Errorf("missing identity env vars"), "❌ AZURE_CLIENT_ID or AZURE_TENANT_ID not set")
+		r.markFailed(ctx, &versionSet, apimv1.ReasonAuthFailed, "missing AZURE_CLIENT_ID or AZURE_TENANT_ID", nil)
+		return ctrl.Result{RequeueAfter: 30 * time.Second}, nil
+	}
+	token, err := managementToken(ctx, r.Tokens, clientID, tenantID)
+	if err != nil {
+		logger.Error(err, "❌ Failed to get Azure token")
+		r.markFailed(ctx, &versionSet, apimv1.ReasonAuthFailed, errMsgFailedToGetAzureToken, err)
+		return ctrl.Result{RequeueAfter: 30 * time.Second}, nil
+	}
+
+	cfg := apim.APIMAPIVersionSetConfig{
+		SubscriptionID:    apimService.Spec.Subscription,
+		ResourceGroup:     apimService.Spec.ResourceGroup,
+		ServiceName:       versionSet.Spec.APIMService,
+		VersionSetID:      versionSet.Spec.VersionSetID,
+		DisplayName:       versionSet.Spec.DisplayName,
+		Description:       versionSet.Spec.Description,
+		VersioningScheme:  versionSet.Spec.VersioningScheme,
+		VersionHeaderName: versionSet.Spec.VersionHeaderName,
+		VersionQueryName:  versionSet.Spec.VersionQueryName,
+		BearerToken:       token,
+	}
+	if cfg.VersioningScheme == "" {
+		cfg.VersioningScheme = "Segment"
+	}
+	if err := r.APIM.UpsertAPIVersionSet(ctx, cfg); err != nil {
+		logger.Error(err, "❌ Failed to upsert APIM version set", "versionSetID", cfg.VersionSetID)
+		r.markFailed(ctx, &versionSet, azureFailureReason(err), "Failed to upsert version set in APIM", err)
+		return ctrl.Result{RequeueAfter: 30 * time.Second}, nil
+	}
+
+	statusPatch := client.MergeFrom(versionSet.DeepCopy())
+	versionSet.Status.Phase = phaseCreated
+	versionSet.Status.Message = "Version set created or updated"
+	versionSet.Status.AzureResourceID = apim.APIVersionSetResourceID(cfg.SubscriptionID, cfg.ResourceGroup, cfg.ServiceName, cfg.VersionSetID)
+	versionSet.Status.TerraformImports = resourceTerraformImports("azurerm_api_management_api_version_set", "Microsoft.ApiManagement/service/apiVersionSets", versionSet.Status.AzureResourceID)
+	setReady(&versionSet.Status.Conditions, &versionSet.Status.ObservedGeneration, versionSet.Generation, metav1.ConditionTrue, apimv1.ReasonSynced, "Version set is synced to APIM")
+	if apim.IsDryRun() {
+		versionSet.Status.Phase = phaseDryRun
+		versionSet.Status.Message = "Dry-run: version set changes were logged but not sent to APIM"
+		setReady(&versionSet.Status.Conditions, &versionSet.Status.ObservedGeneration, versionSet.Generation, metav1.ConditionTrue, apimv1.ReasonSynced, versionSet.Status.Message)
+	}
+	recordSyncSuccess(kindAPIMApiVersionSet, &versionSet)
+	if err := r.Status().Patch(ctx, &versionSet, statusPatch); err != nil {
+		logger.Error(err, "❌ Failed to patch APIMApiVersionSet status")
+		return ctrl.Result{}, err
+	}
+	logger.Info("✅ Successfully upserted APIM version set", "versionSetID", cfg.VersionSetID)
+
+	return ctrl.Result{}, nil
+}
+
+// markFailed sets the Error phase and a False Ready condition, records a Warning Event and patches the status.
+func (r *APIMApiVersionSetReconciler) markFailed(ctx context.Context, versionSet *apimv1.APIMApiVersionSet, reason, message string, err error) {
+	statusPatch := client.MergeFrom(versionSet.DeepCopy())
+	versionSet.Status.Phase = phaseError
+	versionSet.Status.Message = message
+	if err != nil {
+		versionSet.Status.Message = redact.String(err.Error())
+		message = failureMessage(message, err)
+		invalidateRejectedToken(r.Tokens, err)
+	}
+	recordSyncFailure(kindAPIMApiVersionSet, versionSet)
+	warnNotReady(r.Recorder, versionSet, &versionSet.Status.Conditions, &versionSet.Status.ObservedGeneration, reason, message)
+	if patchErr := r.Status().Patch(ctx, versionSet, statusPatch); patchErr != nil {
+		log.FromContext(ctx).Error(patchErr, "❌ Failed to patch APIMApiVersionSet status")
+	}
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *APIMApiVersionSetReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&apimv1.APIMApiVersionSet{}).
+		WithEventFilter(predicate.Funcs{
+			CreateFunc:  func(e event.CreateEvent) bool { return true },
+			UpdateFunc:  func(e event.UpdateEvent) bool { return e.ObjectOld.GetGeneration() != e.ObjectNew.GetGeneration() },
+			DeleteFunc:  func(e event.DeleteEvent) bool { return false },
+			GenericFunc: func(e event.GenericEvent) bool { return false },
+		}).
+		WithEventFilter(shardPredicate()).
+		Named("apimapiversionset").
+		Complete(withTracing("APIMApiVersionSet", r))
+}
@@ -0,0 +1,73 @@
+package controller
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	apimv1 "github.com/hedinit/azure-apim-operator/api/v1"
+	"github.com/hedinit/azure-apim-operator/internal/apimtest"
+	"github.com/hedinit/azure-apim-operator/internal/identity"
+)
+
+func TestAPIMApiVersionSetReconcile(t *testing.T) {
+	const versionSetPath = "/subscriptions/sub/resourceGroups/rg/providers/Microsoft.ApiManagement/service/apim-test/apiVersionSets/orders"
+	fakeAPIM := apimtest.Start(t)
+	t.Setenv("AZURE_CLIENT_ID", "test-client-id")
+	t.Setenv("AZURE_TENANT_ID", "test-tenant-id")
+	identity.SetStaticToken("test-token")
+	t.Cleanup(func() { identity.SetStaticToken("") })
+
+	scheme := runtime.NewScheme()
+	if err := apimv1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	versionSet := &apimv1.APIMApiVersionSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "orders", Namespace: "default", Generation: 1},
+		Spec: apimv1.APIMApiVersionSetSpec{
+			APIMService:       "apim-test",
+			VersionSetID:      "orders",
+			DisplayName:       "Orders",
+			VersioningScheme:  "Header",
+			VersionHeaderName: "Api-Version",
+		},
+	}
+	service := &apimv1.APIMService{
+		ObjectMeta: metav1.ObjectMeta{Name: "apim-test", Namespace: "default"},
+		Spec:       apimv1.APIMServiceSpec{Subscription: "sub", ResourceGroup: "rg"},
+	}
+	r := &APIMApiVersionSetReconciler{
+		APIM:     fakeAPIM.Client(),
+		Client:   fake.NewClientBuilder().WithScheme(scheme).WithObjects(versionSet, service).WithStatusSubresource(versionSet).Build(),
+		Scheme:   scheme,
+		Recorder: record.NewFakeRecorder(10),
+	}
+
+	key := types.NamespacedName{Name: "orders", Namespace: "default"}
+	result, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: key})
+	if err != nil || result.RequeueAfter != 0 {
+		t.Fatalf("Reconcile() = %+v, %v, want no requeue", result, err)
+	}
+	var got apimv1.APIMApiVersionSet
+	if err := r.Get(context.Background(), key, &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.Status.Phase != phaseCreated || len(got.Status.Conditions) != 1 || got.Status.Conditions[0].Reason != apimv1.ReasonSynced {
+		t.Errorf("status = %s %+v, want phase %s with reason %s", got.Status.Phase, got.Status.Conditions, phaseCreated, apimv1.ReasonSynced)
+	}
+	if !strings.HasSuffix(got.Status.AzureResourceID, "/apiVersionSets/orders") {
+		t.Errorf("status.azureResourceId = %q", got.Status.AzureResourceID)
+	}
+
+	properties, ok := fakeAPIM.Properties(versionSetPath)
+	if !ok || properties["versioningScheme"] != "Header" || properties["versionHeaderName"] != "Api-Version" {
+		t.Errorf("APIM version set = %v, want the Header scheme with header Api-Version", properties)
+	}
+}
@@ -0,0 +1,217 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	apimv1 "github.com/hedinit/azure-apim-operator/api/v1"
+	"github.com/hedinit/azure-apim-operator/internal/apim"
+	"github.com/hedinit/azure-apim-operator/internal/identity"
+	"github.com/hedinit/azure-apim-operator/internal/redact"
+)
+
+// APIMBackendReconciler reconciles APIMBackend custom resources.
+// This controller manages backends in Azure API Management, which policies route requests to by ID with
+// set-backend-service instead of the service URL of the API.
+type APIMBackendReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+	// Recorder records Warning Events for failed syncs. Nil disables them.
+	Recorder record.EventRecorder
+	// APIM sends the Azure Management API requests. Nil sends them to the public endpoint.
+	APIM *apim.Client
+	// Tokens provides the Azure Management API token shared by all reconcilers. Nil asks Azure AD on
+	// every reconcile.
+	Tokens identity.TokenProvider
+}
+
+// +kubebuilder:rbac:groups=apim.operator.io,resources=apimbackends,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=apim.operator.io,resources=apimbackends/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=apim.operator.io,resources=apimbackends/finalizers,verbs=update
+
+// Reconcile creates or updates the backend in APIM whenever the spec of the APIMBackend changes.
+func (r *APIMBackendReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	var backend apimv1.APIMBackend
+	if err := r.Get(ctx, req.NamespacedName, &backend); err != nil {
+		if apierrors.IsNotFound(err) {
+			logger.Info("🧹 APIMBackend deleted, skipping", "name", req.NamespacedName)
+			forgetSyncMetrics(kindAPIMBackend, req.Namespace, req.Name)
+			return ctrl.Result{}, nil
+		}
+		logger.Error(err, "❌ Failed to get APIMBackend")
+		return ctrl.Result{}, err
+	}
+	setSpanAPIAttributes(ctx, backend.Spec.APIMService, "")
+
+	operatorNamespace, err := getOperatorNamespace()
+	if err != nil {
+		logger.Error(err, "❌ Failed to get operator namespace")
+		return ctrl.Result{}, fmt.Errorf("get operator namespace: %w", err)
+	}
+
+	var apimService apimv1.APIMService
+	if err := r.Get(ctx, client.ObjectKey{Name: backend.Spec.APIMService, Namespace: operatorNamespace}, &apimService); err != nil {
+		logger.Error(err, "❌ Failed to get APIMService", "name", backend.Spec.APIMService)
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	observeAPIMService(ctx, &apimService)
+
+	clientID := os.Getenv("AZURE_CLIENT_ID")
+	tenantID := os.Getenv("AZURE_TENANT_ID")
+	if clientID == "" || tenantID == "" {
+		logger.Error(fmt.Errorf("missing identity env vars"), "❌ AZURE_CLIENT_ID or AZURE_TENANT_ID not set")
+		r.markFailed(ctx, &backend, apimv1.ReasonAuthFailed, "missing AZURE_CLIENT_ID or AZURE_TENANT_ID", nil)
+		return ctrl.Result{RequeueAfter: 30 * time.Second}, nil
+	}
+	token, err := managementToken(ctx, r.Tokens, clientID, tenantID)
+	if err != nil {
+		logger.Error(err, "❌ Failed to get Azure token")
+		r.markFailed(ctx, &backend, apimv1.ReasonAuthFailed, errMsgFailedToGetAzureToken, err)
+		return ctrl.Result{RequeueAfter: 30 * time.Second}, nil
+	}
+
+	cfg := backendConfig(&backend.Spec)
+	cfg.SubscriptionID = apimService.Spec.Subscription
+	cfg.ResourceGroup = apimService.Spec.ResourceGroup
+	cfg.BearerToken = token
+	if err := r.APIM.UpsertBackend(ctx, cfg); err != nil {
+		if errors.Is(err, apim.ErrCircuitBreakerNotSupported) {
+			// Retrying doesn't help until the operator runs with a newer --apim-api-version.
+			r.markFailed(ctx, &backend, apimv1.ReasonNotSupportedOnAPIVersion, "Circuit breaker not supported", err)
+			return ctrl.Result{}, nil
+		}
+		logger.Error(err, "❌ Failed to upsert APIM backend", "backendID", cfg.BackendID)
+		r.markFailed(ctx, &backend, azureFailureReason(err), "Failed to upsert backend in APIM", err)
+		return ctrl.Result{RequeueAfter: 30 * time.Second}, nil
+	}
+
+	statusPatch := client.MergeFrom(backend.DeepCopy())
+	backend.Status.Phase = phaseCreated
+	backend.Status.Message = "Backend created or updated"
+	backend.Status.AzureResourceID = apim.BackendResourceID(cfg.SubscriptionID, cfg.ResourceGroup, cfg.ServiceName, cfg.BackendID)
+	backend.Status.TerraformImports = resourceTerraformImports("azurerm_api_management_backend", "Microsoft.ApiManagement/service/backends", backend.Status.AzureResourceID)
+	setReady(&backend.Status.Conditions, &backend.Status.ObservedGeneration, backend.Generation, metav1.ConditionTrue, apimv1.ReasonSynced, "Backend is synced to APIM")
+	if apim.IsDryRun() {
+		backend.Status.Phase = phaseDryRun
+		backend.Status.Message = "Dry-run: backend changes were logged but not sent to APIM"
+		setReady(&backend.Status.Conditions, &backend.Status.ObservedGeneration, backend.Generation, metav1.ConditionTrue, apimv1.ReasonSynced, backend.Status.Message)
+	}
+	recordSyncSuccess(kindAPIMBackend, &backend)
+	if err := r.Status().Patch(ctx, &backend, statusPatch); err != nil {
+		logger.Error(err, "❌ Failed to patch APIMBackend status")
+		return ctrl.Result{}, err
+	}
+	logger.Info("✅ Successfully upserted APIM backend", "backendID", cfg.BackendID)
+
+	return ctrl.Result{}, nil
+}
+
+// markFailed sets the Error phase and a False Ready condition, records a Warning Event and patches the status.
+func (r *APIMBackendReconciler) markFailed(ctx context.Context, backend *apimv1.APIMBackend, reason, message string, err error) {
+	statusPatch := client.MergeFrom(backend.DeepCopy())
+	backend.Status.Phase = phaseError
+	backend.Status.Message = message
+	if err != nil {
+		backend.Status.Message = redact.String(err.Error())
+		message = failureMessage(message, err)
+		invalidateRejectedToken(r.Tokens, err)
+	}
+	recordSyncFailure(kindAPIMBackend, backend)
+	warnNotReady(r.Recorder, backend, &backend.Status.Conditions, &backend.Status.ObservedGeneration, reason, message)
+	if patchErr := r.Status().Patch(ctx, backend, statusPatch); patchErr != nil {
+		log.FromContext(ctx).Error(patchErr, "❌ Failed to patch APIMBackend status")
+	}
+}
+
+// backendConfig returns the APIM configuration of a backend spec, without the APIM service location and token.
+func backendConfig(spec *apimv1.APIMBackendSpec) apim.APIMBackendConfig {
+	cfg := apim.APIMBackendConfig{
+		ServiceName: spec.APIMService,
+		BackendID:   spec.BackendID,
+		URL:         spec.URL,
+		Protocol:    spec.Protocol,
+		Title:       spec.Title,
+		Description: spec.Description,
+	}
+	if cfg.Protocol == "" {
+		cfg.Protocol = "http"
+	}
+	if creds := spec.Credentials; creds != nil {
+		cfg.Credentials = &apim.BackendCredentials{
+			Header:         creds.Header,
+			Query:          creds.Query,
+			CertificateIDs: creds.CertificateIDs,
+		}
+		if auth := creds.Authorization; auth != nil {
+			cfg.Credentials.AuthorizationScheme = auth.Scheme
+			cfg.Credentials.AuthorizationParameter = auth.Parameter
+		}
+	}
+	if tls := spec.TLS; tls != nil {
+		cfg.ValidateCertificateChain = tls.ValidateCertificateChain
+		cfg.ValidateCertificateName = tls.ValidateCertificateName
+	}
+	if cb := spec.CircuitBreaker; cb != nil {
+		cfg.CircuitBreaker = &apim.BackendCircuitBreaker{
+			Name:             cb.Name,
+			FailureCount:     cb.FailureCount,
+			FailureInterval:  cb.FailureInterval.Duration,
+			ErrorReasons:     cb.ErrorReasons,
+			TripDuration:     cb.TripDuration.Duration,
+			AcceptRetryAfter: cb.AcceptRetryAfter,
+		}
+		if cfg.CircuitBreaker.Name == "" {
+			cfg.CircuitBreaker.Name = "breaker"
+		}
+		for _, r := range cb.StatusCodeRanges {
+			cfg.CircuitBreaker.StatusCodeRanges = append(cfg.CircuitBreaker.StatusCodeRanges, apim.StatusCodeRange{Min: r.Min, Max: r.Max})
+		}
+	}
+	return cfg
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *APIMBackendReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&apimv1.APIMBackend{}).
+		WithEventFilter(predicate.Funcs{
+			CreateFunc:  func(e event.CreateEvent) bool { return true },
+			UpdateFunc:  func(e event.UpdateEvent) bool { return e.ObjectOld.GetGeneration() != e.ObjectNew.GetGeneration() },
+			DeleteFunc:  func(e event.DeleteEvent) bool { return false },
+			GenericFunc: func(e event.GenericEvent) bool { return false },
+		}).
+		WithEventFilter(shardPredicate()).
+		Named("apimbackend").
+		Complete(withTracing("APIMBackend", r))
+}
@@ -0,0 +1,148 @@
+package controller
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	apimv1 "github.com/hedinit/azure-apim-operator/api/v1"
+	"github.com/hedinit/azure-apim-operator/internal/apim"
+	"github.com/hedinit/azure-apim-operator/internal/apimtest"
+	"github.com/hedinit/azure-apim-operator/internal/identity"
+)
+
+func TestAPIMBackendReconcile(t *testing.T) {
+	const backendPath = "/subscriptions/sub/resourceGroups/rg/providers/Microsoft.ApiManagement/service/apim-test/backends/orders-backend"
+	breaker := &apimv1.APIMBackendCircuitBreaker{
+		FailureCount:     5,
+		FailureInterval:  metav1.Duration{Duration: time.Minute},
+		StatusCodeRanges: []apimv1.APIMBackendStatusCodeRange{{Min: 500, Max: 599}},
+		TripDuration:     metav1.Duration{Duration: 30 * time.Second},
+	}
+	tests := []struct {
+		name           string
+		apiVersion     string
+		circuitBreaker *apimv1.APIMBackendCircuitBreaker
+		wantPhase      string
+		wantReason     string
+	}{
+		{name: "backend is upserted", wantPhase: phaseCreated, wantReason: apimv1.ReasonSynced},
+		{name: "circuit breaker is upserted", apiVersion: "2024-05-01", circuitBreaker: breaker, wantPhase: phaseCreated, wantReason: apimv1.ReasonSynced},
+		{name: "circuit breaker needs a newer API version", circuitBreaker: breaker, wantPhase: phaseError, wantReason: apimv1.ReasonNotSupportedOnAPIVersion},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fakeAPIM := apimtest.Start(t)
+			t.Setenv("AZURE_CLIENT_ID", "test-client-id")
+			t.Setenv("AZURE_TENANT_ID", "test-tenant-id")
+			identity.SetStaticToken("test-token")
+			t.Cleanup(func() { identity.SetStaticToken("") })
+			apimClient := fakeAPIM.Client()
+			apimClient.APIVersion = tt.apiVersion
+
+			scheme := runtime.NewScheme()
+			if err := apimv1.AddToScheme(scheme); err != nil {
+				t.Fatal(err)
+			}
+			backend := &apimv1.APIMBackend{
+				ObjectMeta: metav1.ObjectMeta{Name: "orders-backend", Namespace: "default", Generation: 1},
+				Spec: apimv1.APIMBackendSpec{
+					APIMService:    "apim-test",
+					BackendID:      "orders-backend",
+					URL:            "https://orders.internal.example.com",
+					Credentials:    &apimv1.APIMBackendCredentials{Header: map[string][]string{"x-api-key": {"{{backend-key}}"}}},
+					CircuitBreaker: tt.circuitBreaker,
+				},
+			}
+			service := &apimv1.APIMService{
+				ObjectMeta: metav1.ObjectMeta{Name: "apim-test", Namespace: "default"},
+				Spec:       apimv1.APIMServiceSpec{Subscription: "sub", ResourceGroup: "rg"},
+			}
+			r := &APIMBackendReconciler{
+				APIM:     apimClient,
+				Client:   fake.NewClientBuilder().WithScheme(scheme).WithObjects(backend, service).WithStatusSubresource(backend).Build(),
+				Scheme:   scheme,
+				Recorder: record.NewFakeRecorder(10),
+			}
+
+			key := types.NamespacedName{Name: "orders-backend", Namespace: "default"}
+			result, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: key})
+			if err != nil || result.RequeueAfter != 0 {
+				t.Fatalf("Reconcile() = %+v, %v, want no requeue", result, err)
+			}
+			var got apimv1.APIMBackend
+			if err := r.Get(context.Background(), key, &got); err != nil {
+				t.Fatal(err)
+			}
+			if got.Status.Phase != tt.wantPhase || len(got.Status.Conditions) != 1 || got.Status.Conditions[0].Reason != tt.wantReason {
+				t.Errorf("status = %s %+v, want phase %s with reason %s", got.Status.Phase, got.Status.Conditions, tt.wantPhase, tt.wantReason)
+			}
+
+			properties, ok := fakeAPIM.Properties(backendPath)
+			if tt.wantPhase == phaseError {
+				if ok {
+					t.Errorf("backend was written to APIM, want nothing sent")
+				}
+				return
+			}
+			if !ok || properties["url"] != "https://orders.internal.example.com" || properties["protocol"] != "http" {
+				t.Fatalf("APIM backend = %v, want the URL and the default protocol http", properties)
+			}
+			if _, ok := properties["circuitBreaker"]; ok != (tt.circuitBreaker != nil) {
+				t.Errorf("APIM backend circuitBreaker sent = %v, want %v", ok, tt.circuitBreaker != nil)
+			}
+			if !strings.HasSuffix(got.Status.AzureResourceID, "/backends/orders-backend") {
+				t.Errorf("status.azureResourceId = %q", got.Status.AzureResourceID)
+			}
+		})
+	}
+}
+
+func TestBackendConfig(t *testing.T) {
+	chain := false
+	spec := &apimv1.APIMBackendSpec{
+		APIMService: "apim-test",
+		BackendID:   "orders-backend",
+		URL:         "https://orders.internal.example.com",
+		Protocol:    "soap",
+		Credentials: &apimv1.APIMBackendCredentials{
+			Authorization: &apimv1.APIMBackendAuthorization{Scheme: "Bearer", Parameter: "{{orders-token}}"},
+		},
+		TLS: &apimv1.APIMBackendTLS{ValidateCertificateChain: &chain},
+		CircuitBreaker: &apimv1.APIMBackendCircuitBreaker{
+			FailureCount:     3,
+			FailureInterval:  metav1.Duration{Duration: time.Minute},
+			StatusCodeRanges: []apimv1.APIMBackendStatusCodeRange{{Min: 500, Max: 503}},
+			TripDuration:     metav1.Duration{Duration: time.Hour},
+		},
+	}
+
+	got := backendConfig(spec)
+	want := apim.APIMBackendConfig{
+		ServiceName:              "apim-test",
+		BackendID:                "orders-backend",
+		URL:                      "https://orders.internal.example.com",
+		Protocol:                 "soap",
+		Credentials:              &apim.BackendCredentials{AuthorizationScheme: "Bearer", AuthorizationParameter: "{{orders-token}}"},
+		ValidateCertificateChain: &chain,
+		CircuitBreaker: &apim.BackendCircuitBreaker{
+			Name:             "breaker",
+			FailureCount:     3,
+			FailureInterval:  time.Minute,
+			StatusCodeRanges: []apim.StatusCodeRange{{Min: 500, Max: 503}},
+			TripDuration:     time.Hour,
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("backendConfig() = %+v, want %+v", got, want)
+	}
+}
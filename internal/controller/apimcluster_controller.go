@@ -0,0 +1,360 @@
+package controller
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sync"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/cluster"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	apimv1 "github.com/hedinit/azure-apim-operator/api/v1"
+	"github.com/hedinit/azure-apim-operator/internal/apim"
+	"github.com/hedinit/azure-apim-operator/internal/identity"
+	"github.com/hedinit/azure-apim-operator/internal/redact"
+)
+
+const (
+	// phaseConnected is the phase of an APIMCluster whose APIMAPIs are watched.
+	phaseConnected = "Connected"
+
+	// apimClusterResyncInterval is how often the connection to a remote cluster is checked.
+	apimClusterResyncInterval = 5 * time.Minute
+)
+
+// spokeCluster is a connection to a remote cluster and the controllers that reconcile its APIMAPIs.
+type spokeCluster struct {
+	// kubeconfigHash identifies the kubeconfig the connection was made with.
+	kubeconfigHash string
+	cancel         context.CancelFunc
+}
+
+// APIMClusterReconciler reconciles APIMCluster custom resources.
+// For every APIMCluster it runs the APIMAPI, APIMAPIDeployment and ReplicaSetWatcher controllers against
+// the remote cluster, while APIMServices are read from the management cluster.
+type APIMClusterReconciler struct {
+	client.Client
+	// APIReader reads kubeconfig Secrets directly from the API server, so the operator doesn't cache
+	// every Secret of the cluster.
+	APIReader client.Reader
+	Scheme    *runtime.Scheme
+	// Recorder records Warning Events for failed connections. Nil disables them.
+	Recorder record.EventRecorder
+
+	// ReplicaSetDebounce is passed to the APIMAPIDeployment controllers of remote clusters.
+	ReplicaSetDebounce time.Duration
+	// APIM and Tokens are passed to the controllers of remote clusters.
+	APIM   *apim.Client
+	Tokens identity.TokenProvider
+
+	mgr    ctrl.Manager
+	mu     sync.Mutex
+	spokes map[string]*spokeCluster
+}
+
+// +kubebuilder:rbac:groups=apim.operator.io,resources=apimclusters,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=apim.operator.io,resources=apimclusters/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=apim.operator.io,resources=apimclusters/finalizers,verbs=update
+// +kubebuilder:rbac:groups="",resources=secrets,verbs=get
+
+// Reconcile connects to the remote cluster of an APIMCluster and starts its controllers. A changed
+// kubeconfig restarts them; a deleted APIMCluster stops them. The connection is checked again every
+// few minutes, so a revoked credential or an unreachable API server shows in the Ready condition.
+func (r *APIMClusterReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	var apimCluster apimv1.APIMCluster
+	if err := r.Get(ctx, req.NamespacedName, &apimCluster); err != nil {
+		if errors.IsNotFound(err) {
+			logger.Info("🧹 APIMCluster deleted, disconnecting", "name", req.NamespacedName)
+			r.stopSpoke(req.Name)
+			forgetSyncMetrics(kindAPIMCluster, req.Namespace, req.Name)
+			return ctrl.Result{}, nil
+		}
+		logger.Error(err, "❌ Failed to get APIMCluster")
+		return ctrl.Result{}, err
+	}
+
+	ref := apimCluster.Spec.KubeconfigSecretRef
+	var secret corev1.Secret
+	if err := r.APIReader.Get(ctx, client.ObjectKey{Name: ref.Name, Namespace: apimCluster.Namespace}, &secret); err != nil {
+		logger.Error(err, "❌ Failed to read kubeconfig Secret", "secret", ref.Name)
+		r.markFailed(ctx, &apimCluster, apimv1.ReasonSecretReadFailed, fmt.Sprintf("Failed to read Secret %s", ref.Name), err)
+		return ctrl.Result{RequeueAfter: time.Minute}, nil
+	}
+	kubeconfig, ok := secret.Data[ref.Key]
+	if !ok {
+		r.markFailed(ctx, &apimCluster, apimv1.ReasonSecretReadFailed, fmt.Sprintf("Secret %s has no key %s", ref.Name, ref.Key), nil)
+		return ctrl.Result{RequeueAfter: time.Minute}, nil
+	}
+	restConfig, err := clientcmd.RESTConfigFromKubeConfig(kubeconfig)
+	if err != nil {
+		logger.Error(err, "❌ Invalid kubeconfig", "secret", ref.Name)
+		r.markFailed(ctx, &apimCluster, apimv1.ReasonSecretReadFailed, fmt.Sprintf("Secret %s holds no valid kubeconfig", ref.Name), err)
+		return ctrl.Result{RequeueAfter: time.Minute}, nil
+	}
+
+	// Listing APIMAPIs checks reachability, credentials and the installed CRD in one call.
+	if err := r.checkConnection(ctx, restConfig); err != nil {
+		logger.Error(err, "❌ Remote cluster is unreachable", "server", restConfig.Host)
+		r.markFailed(ctx, &apimCluster, apimv1.ReasonClusterUnreachable, "Failed to list APIMAPIs of the remote cluster", err)
+		return ctrl.Result{RequeueAfter: time.Minute}, nil
+	}
+
+	statusPatch := client.MergeFrom(apimCluster.DeepCopy())
+	if hash := kubeconfigHash(kubeconfig); !r.spokeRunning(apimCluster.Name, hash) {
+		if err := r.startSpoke(apimCluster.Name, hash, restConfig); err != nil {
+			logger.Error(err, "❌ Failed to start controllers for the remote cluster", "server", restConfig.Host)
+			r.markFailed(ctx, &apimCluster, apimv1.ReasonClusterUnreachable, "Failed to start controllers for the remote cluster", err)
+			return ctrl.Result{RequeueAfter: time.Minute}, nil
+		}
+		logger.Info("🔗 Connected to remote cluster", "server", restConfig.Host)
+		apimCluster.Status.ConnectedAt = time.Now().UTC().Format(time.RFC3339)
+	}
+	apimCluster.Status.Phase = phaseConnected
+	apimCluster.Status.Message = "APIMAPIs of the remote cluster are reconciled"
+	apimCluster.Status.Server = restConfig.Host
+	setReady(&apimCluster.Status.Conditions, &apimCluster.Status.ObservedGeneration, apimCluster.Generation,
+		metav1.ConditionTrue, apimv1.ReasonSynced, apimCluster.Status.Message)
+	recordSyncSuccess(kindAPIMCluster, &apimCluster)
+	if err := r.Status().Patch(ctx, &apimCluster, statusPatch); err != nil {
+		logger.Error(err, "❌ Failed to patch APIMCluster status")
+		return ctrl.Result{}, err
+	}
+
+	return ctrl.Result{RequeueAfter: apimClusterResyncInterval}, nil
+}
+
+// markFailed sets the Error phase and a False Ready condition, records a Warning Event and patches the status.
+// The controllers of a cluster keep running, since they recover by themselves when the cluster is reachable again.
+func (r *APIMClusterReconciler) markFailed(ctx context.Context, apimCluster *apimv1.APIMCluster, reason, message string, err error) {
+	statusPatch := client.MergeFrom(apimCluster.DeepCopy())
+	apimCluster.Status.Phase = phaseError
+	apimCluster.Status.Message = message
+	if err != nil {
+		apimCluster.Status.Message = redact.String(err.Error())
+		message = failureMessage(message, err)
+	}
+	recordSyncFailure(kindAPIMCluster, apimCluster)
+	warnNotReady(r.Recorder, apimCluster, &apimCluster.Status.Conditions, &apimCluster.Status.ObservedGeneration, reason, message)
+	if patchErr := r.Status().Patch(ctx, apimCluster, statusPatch); patchErr != nil {
+		log.FromContext(ctx).Error(patchErr, "❌ Failed to patch APIMCluster status")
+	}
+}
+
+// checkConnection lists one APIMAPI of the remote cluster.
+func (r *APIMClusterReconciler) checkConnection(ctx context.Context, restConfig *rest.Config) error {
+	remote, err := client.New(restConfig, client.Options{Scheme: r.Scheme})
+	if err != nil {
+		return fmt.Errorf("create client: %w", err)
+	}
+	var apis apimv1.APIMAPIList
+	if err := remote.List(ctx, &apis, client.Limit(1)); err != nil {
+		return fmt.Errorf("list APIMAPIs: %w", err)
+	}
+	return nil
+}
+
+// spokeRunning reports whether the controllers of cluster name run with the kubeconfig of hash.
+func (r *APIMClusterReconciler) spokeRunning(name, hash string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	spoke, ok := r.spokes[name]
+	return ok && spoke.kubeconfigHash == hash
+}
+
+// startSpoke connects to the remote cluster name and starts its controllers, stopping those of a
+// previous kubeconfig. They run until stopSpoke, independent of the reconcile that started them.
+func (r *APIMClusterReconciler) startSpoke(name, hash string, restConfig *rest.Config) error {
+	r.stopSpoke(name)
+
+	remote, err := cluster.New(restConfig, func(o *cluster.Options) {
+		o.Scheme = r.Scheme
+	})
+	if err != nil {
+		return fmt.Errorf("create cluster: %w", err)
+	}
+	controllers, err := r.spokeControllers(name, remote)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	spoke := &spokeCluster{kubeconfigHash: hash, cancel: cancel}
+	r.mu.Lock()
+	r.spokes[name] = spoke
+	r.mu.Unlock()
+
+	logger := ctrl.Log.WithName("apimcluster_controller").WithValues("cluster", name)
+	go func() {
+		if err := remote.Start(ctx); err != nil && ctx.Err() == nil {
+			logger.Error(err, "❌ Cache of remote cluster stopped")
+			r.forgetSpoke(name, spoke)
+		}
+	}()
+	for _, c := range controllers {
+		go func() {
+			// A controller whose cache doesn't sync stops; forgetting the spoke starts it again on the next resync.
+			if err := c.Start(ctx); err != nil && ctx.Err() == nil {
+				logger.Error(err, "❌ Controller of remote cluster stopped")
+				r.forgetSpoke(name, spoke)
+			}
+		}()
+	}
+	return nil
+}
+
+// spokeControllers returns the controllers that reconcile the APIMAPIs of the remote cluster name. Their
+// clients are those of the remote cluster, except for APIMServices, which are read from the management cluster.
+// Unlike in the management cluster, every namespace of the remote cluster is reconciled by the shard that
+// owns the APIMCluster.
+func (r *APIMClusterReconciler) spokeControllers(name string, remote cluster.Cluster) ([]controller.Controller, error) {
+	remoteClient := remote.GetClient()
+	watches := []struct {
+		name       string
+		reconciler reconcile.Reconciler
+		object     client.Object
+		predicate  predicate.Predicate
+	}{
+		{
+			name: "apimapi",
+			reconciler: withTracing("APIMAPI", &APIMAPIReconciler{
+				Client:   remoteClient,
+				Hub:      r.Client,
+				Scheme:   r.Scheme,
+				Recorder: remote.GetEventRecorderFor("apimapi-controller"),
+				APIM:     r.APIM,
+				Tokens:   r.Tokens,
+			}),
+			object:    &apimv1.APIMAPI{},
+			predicate: apimAPIPredicate(),
+		},
+		{
+			name: "apimapideployment",
+			reconciler: withTracing("APIMAPIDeployment", &APIMAPIDeploymentReconciler{
+				Client:             remoteClient,
+				Hub:                r.Client,
+				APIReader:          r.APIReader,
+				Scheme:             r.Scheme,
+				Recorder:           remote.GetEventRecorderFor("apimapideployment-controller"),
+				ReplicaSetDebounce: r.ReplicaSetDebounce,
+				APIM:               r.APIM,
+				Tokens:             r.Tokens,
+			}),
+			object:    &apimv1.APIMAPIDeployment{},
+			predicate: apimAPIDeploymentPredicate(),
+		},
+		{
+			name:       "replicasetwatcher",
+			reconciler: withTracing("ReplicaSetWatcher", &ReplicaSetWatcherReconciler{Client: remoteClient, Hub: r.Client, Scheme: r.Scheme}),
+			object:     &appsv1.ReplicaSet{},
+			predicate:  replicaSetReadyPredicate(),
+		},
+	}
+
+	controllers := make([]controller.Controller, 0, len(watches))
+	for _, w := range watches {
+		c, err := controller.NewUnmanaged(spokeControllerName(w.name, name), r.mgr, controller.Options{
+			Reconciler: w.reconciler,
+			// Controller names must be unique per process, which a reconnect with a new kubeconfig isn't.
+			SkipNameValidation: ptr.To(true),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("create %s controller: %w", w.name, err)
+		}
+		if err := c.Watch(source.Kind(remote.GetCache(), w.object, priorityEnqueueHandler(), w.predicate)); err != nil {
+			return nil, fmt.Errorf("watch %s: %w", w.name, err)
+		}
+		controllers = append(controllers, c)
+	}
+	return controllers, nil
+}
+
+// stopSpoke stops the controllers of the remote cluster name, if they run.
+func (r *APIMClusterReconciler) stopSpoke(name string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if spoke, ok := r.spokes[name]; ok {
+		spoke.cancel()
+		delete(r.spokes, name)
+	}
+}
+
+// forgetSpoke stops spoke if it still is the connection of cluster name.
+func (r *APIMClusterReconciler) forgetSpoke(name string, spoke *spokeCluster) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.spokes[name] == spoke {
+		spoke.cancel()
+		delete(r.spokes, name)
+	}
+}
+
+// stopAllSpokes stops the controllers of every remote cluster.
+func (r *APIMClusterReconciler) stopAllSpokes() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for name, spoke := range r.spokes {
+		spoke.cancel()
+		delete(r.spokes, name)
+	}
+}
+
+// spokeControllerName returns the name of a controller of a remote cluster, as used in logs and metrics.
+func spokeControllerName(controllerName, clusterName string) string {
+	return controllerName + "-" + clusterName
+}
+
+// kubeconfigHash returns the hash of a kubeconfig, which tells whether it changed without keeping it.
+func kubeconfigHash(kubeconfig []byte) string {
+	sum := sha256.Sum256(kubeconfig)
+	return hex.EncodeToString(sum[:])
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *APIMClusterReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	r.mgr = mgr
+	r.spokes = map[string]*spokeCluster{}
+	// The controllers of remote clusters run outside the manager, so they are stopped when it stops,
+	// or when this replica loses the leader election.
+	if err := mgr.Add(manager.RunnableFunc(func(ctx context.Context) error {
+		<-ctx.Done()
+		r.stopAllSpokes()
+		return nil
+	})); err != nil {
+		return err
+	}
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&apimv1.APIMCluster{}).
+		WithEventFilter(predicate.Funcs{
+			CreateFunc: func(e event.CreateEvent) bool { return true },
+			UpdateFunc: func(e event.UpdateEvent) bool {
+				return e.ObjectOld.GetGeneration() != e.ObjectNew.GetGeneration()
+			},
+			DeleteFunc:  func(e event.DeleteEvent) bool { return true },
+			GenericFunc: func(e event.GenericEvent) bool { return false },
+		}).
+		WithEventFilter(shardPredicate()).
+		Named("apimcluster").
+		Complete(withTracing("APIMCluster", r))
+}
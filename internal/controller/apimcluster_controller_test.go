@@ -0,0 +1,107 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	apimv1 "github.com/hedinit/azure-apim-operator/api/v1"
+)
+
+func TestEnsureAPIMAPIDeploymentReadsServiceFromHub(t *testing.T) {
+	t.Setenv("OPERATOR_NAMESPACE", "apim-system")
+	scheme := runtime.NewScheme()
+	if err := apimv1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	apimAPI := &apimv1.APIMAPI{
+		ObjectMeta: metav1.ObjectMeta{Name: "orders", Namespace: "team-a", UID: "uid-1"},
+		Spec:       apimv1.APIMAPISpec{APIMService: "my-apim", APIID: "orders"},
+	}
+	hub := fake.NewClientBuilder().WithScheme(scheme).WithObjects(&apimv1.APIMService{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-apim", Namespace: "apim-system"},
+		Spec:       apimv1.APIMServiceSpec{Subscription: "sub-1", ResourceGroup: "rg-1"},
+	}).Build()
+	spoke := fake.NewClientBuilder().WithScheme(scheme).WithObjects(apimAPI).Build()
+
+	deployment, err := ensureAPIMAPIDeployment(context.Background(), spoke, hubClient(spoke, hub), apimAPI)
+	if err != nil {
+		t.Fatalf("ensureAPIMAPIDeployment() error = %v", err)
+	}
+	if deployment.Spec.Subscription != "sub-1" || deployment.Spec.ResourceGroup != "rg-1" {
+		t.Errorf("deployment location = %s/%s, want the APIMService of the hub", deployment.Spec.Subscription, deployment.Spec.ResourceGroup)
+	}
+	var created apimv1.APIMAPIDeployment
+	if err := spoke.Get(context.Background(), client.ObjectKeyFromObject(apimAPI), &created); err != nil {
+		t.Errorf("APIMAPIDeployment was not created in the spoke: %v", err)
+	}
+}
+
+func TestSpokeBookkeeping(t *testing.T) {
+	r := &APIMClusterReconciler{spokes: map[string]*spokeCluster{}}
+	hash := kubeconfigHash([]byte("kubeconfig-a"))
+	if hash == kubeconfigHash([]byte("kubeconfig-b")) {
+		t.Fatal("kubeconfigHash() is equal for different kubeconfigs")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	spoke := &spokeCluster{kubeconfigHash: hash, cancel: cancel}
+	r.spokes["west"] = spoke
+	if !r.spokeRunning("west", hash) {
+		t.Error("spokeRunning() = false for the running kubeconfig")
+	}
+	if r.spokeRunning("west", kubeconfigHash([]byte("kubeconfig-b"))) {
+		t.Error("spokeRunning() = true for a changed kubeconfig")
+	}
+
+	r.forgetSpoke("west", &spokeCluster{})
+	if ctx.Err() != nil || !r.spokeRunning("west", hash) {
+		t.Error("forgetSpoke() stopped a newer connection")
+	}
+	r.stopSpoke("west")
+	if ctx.Err() == nil || r.spokeRunning("west", hash) {
+		t.Error("stopSpoke() left the connection running")
+	}
+}
+
+func TestAPIMClusterReconcileMissingSecret(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	if err := apimv1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	apimCluster := &apimv1.APIMCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "west", Namespace: "apim-system"},
+		Spec: apimv1.APIMClusterSpec{
+			KubeconfigSecretRef: apimv1.SecretKeyRef{Name: "west-kubeconfig", Key: "kubeconfig"},
+		},
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).
+		WithObjects(apimCluster, &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "west-kubeconfig", Namespace: "apim-system"}}).
+		WithStatusSubresource(apimCluster).
+		Build()
+	r := &APIMClusterReconciler{Client: c, APIReader: c, Scheme: scheme, spokes: map[string]*spokeCluster{}}
+
+	if _, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: client.ObjectKeyFromObject(apimCluster)}); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	var got apimv1.APIMCluster
+	if err := c.Get(context.Background(), client.ObjectKeyFromObject(apimCluster), &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.Status.Phase != phaseError {
+		t.Errorf("phase = %q, want %q", got.Status.Phase, phaseError)
+	}
+	if len(got.Status.Conditions) != 1 || got.Status.Conditions[0].Reason != apimv1.ReasonSecretReadFailed {
+		t.Errorf("conditions = %+v, want Ready False with reason %s", got.Status.Conditions, apimv1.ReasonSecretReadFailed)
+	}
+}
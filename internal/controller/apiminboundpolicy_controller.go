@@ -18,27 +18,44 @@ package controller
 
 import (
 	"context"
+	stderrors "errors"
 	"fmt"
 	"os"
 	"time"
 
+	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
 
 	apimv1 "github.com/hedinit/azure-apim-operator/api/v1"
 	"github.com/hedinit/azure-apim-operator/internal/apim"
+	"github.com/hedinit/azure-apim-operator/internal/cloudevents"
 	"github.com/hedinit/azure-apim-operator/internal/identity"
+	"github.com/hedinit/azure-apim-operator/internal/redact"
 )
 
 // APIMInboundPolicyReconciler reconciles a APIMInboundPolicy object
 type APIMInboundPolicyReconciler struct {
 	client.Client
-	Scheme *runtime.Scheme
+	// APIReader reads the ConfigMaps and Secrets of policy templates directly from the API server, so the
+	// operator doesn't cache every ConfigMap and Secret of the cluster.
+	APIReader client.Reader
+	Scheme    *runtime.Scheme
+	// Recorder records Warning Events for failed syncs. Nil disables them.
+	Recorder record.EventRecorder
+	// APIM sends the Azure Management API requests. Nil sends them to the public endpoint.
+	APIM *apim.Client
+	// Tokens provides the Azure Management API token shared by all reconcilers. Nil asks Azure AD on
+	// every reconcile.
+	Tokens identity.TokenProvider
 }
 
 // +kubebuilder:rbac:groups=apim.operator.io,resources=apiminboundpolicies,verbs=get;list;watch;create;update;patch;delete
@@ -61,11 +78,25 @@ func (r *APIMInboundPolicyReconciler) Reconcile(ctx context.Context, req ctrl.Re
 	if err := r.Get(ctx, req.NamespacedName, &policy); err != nil {
 		if errors.IsNotFound(err) {
 			logger.Info("🧹 APIMInboundPolicy deleted, skipping", "name", req.NamespacedName)
+			forgetSyncMetrics(kindAPIMInboundPolicy, req.Namespace, req.Name)
 			return ctrl.Result{}, nil
 		}
 		logger.Error(err, "❌ Failed to get APIMInboundPolicy")
 		return ctrl.Result{}, err
 	}
+	setSpanAPIAttributes(ctx, policy.Spec.APIMService, policy.Spec.APIID)
+
+	if !policy.DeletionTimestamp.IsZero() {
+		return r.finalize(ctx, &policy)
+	}
+	if !controllerutil.ContainsFinalizer(&policy, apimInboundPolicyFinalizer) {
+		patch := client.MergeFrom(policy.DeepCopy())
+		controllerutil.AddFinalizer(&policy, apimInboundPolicyFinalizer)
+		if err := r.Patch(ctx, &policy, patch); err != nil {
+			logger.Error(err, "❌ Failed to add APIMInboundPolicy finalizer", "apiID", policy.Spec.APIID)
+			return ctrl.Result{}, err
+		}
+	}
 
 	operatorNamespace, err := getOperatorNamespace()
 	if err != nil {
@@ -78,6 +109,44 @@ func (r *APIMInboundPolicyReconciler) Reconcile(ctx context.Context, req ctrl.Re
 		logger.Error(err, "❌ Failed to get APIMService", "name", policy.Spec.APIMService, "apiID", policy.Spec.APIID)
 		return ctrl.Result{}, client.IgnoreNotFound(err)
 	}
+	observeAPIMService(ctx, &apimService)
+
+	policyContent, err := renderPolicyContent(ctx, r.Client, r.APIReader, &policy)
+	if err != nil {
+		reason := apimv1.ReasonTemplateFailed
+		var tmplErr *templateError
+		if stderrors.As(err, &tmplErr) {
+			reason = tmplErr.reason
+		}
+		logger.Error(err, "❌ Failed to render policy template", "apiID", policy.Spec.APIID)
+		// Use Patch to update only status without touching spec fields.
+		statusPatch := client.MergeFrom(policy.DeepCopy())
+		policy.Status.Phase = phaseError
+		recordSyncFailure(kindAPIMInboundPolicy, &policy)
+		policy.Status.Message = redact.String(err.Error())
+		invalidateRejectedToken(r.Tokens, err)
+		warnNotReady(r.Recorder, &policy, &policy.Status.Conditions, &policy.Status.ObservedGeneration, reason, failureMessage("Failed to render policy template", err))
+		if err := r.Status().Patch(ctx, &policy, statusPatch); err != nil {
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{RequeueAfter: 30 * time.Second}, nil
+	}
+
+	// Fail before calling Azure, which rejects such a policy with an opaque ValidationError. The service is
+	// read again every resync interval, so the policy is applied once the service moves to another tier.
+	if message := unsupportedPolicyMessage(&apimService, policyContent); message != "" {
+		logger.Info("⚠️ Policy is not supported on the APIM service's SKU", "apiID", policy.Spec.APIID, "sku", apimService.Status.SKU)
+		// Use Patch to update only status without touching spec fields.
+		statusPatch := client.MergeFrom(policy.DeepCopy())
+		policy.Status.Phase = phaseError
+		recordSyncFailure(kindAPIMInboundPolicy, &policy)
+		policy.Status.Message = message
+		warnNotReady(r.Recorder, &policy, &policy.Status.Conditions, &policy.Status.ObservedGeneration, apimv1.ReasonNotSupportedOnSKU, message)
+		if err := r.Status().Patch(ctx, &policy, statusPatch); err != nil {
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{RequeueAfter: hostRefreshInterval()}, nil
+	}
 
 	clientID := os.Getenv("AZURE_CLIENT_ID")
 	tenantID := os.Getenv("AZURE_TENANT_ID")
@@ -86,18 +155,22 @@ func (r *APIMInboundPolicyReconciler) Reconcile(ctx context.Context, req ctrl.Re
 		// Use Patch to update only status without touching spec fields.
 		statusPatch := client.MergeFrom(policy.DeepCopy())
 		policy.Status.Phase = phaseError
+		recordSyncFailure(kindAPIMInboundPolicy, &policy)
 		policy.Status.Message = "missing AZURE_CLIENT_ID or AZURE_TENANT_ID"
+		warnNotReady(r.Recorder, &policy, &policy.Status.Conditions, &policy.Status.ObservedGeneration, apimv1.ReasonAuthFailed, policy.Status.Message)
 		_ = r.Status().Patch(ctx, &policy, statusPatch)
 		return ctrl.Result{RequeueAfter: 30 * time.Second}, nil
 	}
 
-	token, err := identity.GetManagementToken(ctx, clientID, tenantID)
+	token, err := managementToken(ctx, r.Tokens, clientID, tenantID)
 	if err != nil {
 		logger.Error(err, "❌ Failed to get Azure token", "apiID", policy.Spec.APIID)
 		// Use Patch to update only status without touching spec fields.
 		statusPatch := client.MergeFrom(policy.DeepCopy())
 		policy.Status.Phase = phaseError
+		recordSyncFailure(kindAPIMInboundPolicy, &policy)
 		policy.Status.Message = errMsgFailedToGetAzureToken
+		warnNotReady(r.Recorder, &policy, &policy.Status.Conditions, &policy.Status.ObservedGeneration, apimv1.ReasonAuthFailed, failureMessage(policy.Status.Message, err))
 		_ = r.Status().Patch(ctx, &policy, statusPatch)
 		return ctrl.Result{RequeueAfter: 30 * time.Second}, nil
 	}
@@ -108,18 +181,40 @@ func (r *APIMInboundPolicyReconciler) Reconcile(ctx context.Context, req ctrl.Re
 		ServiceName:    policy.Spec.APIMService,
 		APIID:          policy.Spec.APIID,
 		OperationID:    policy.Spec.OperationID,
-		PolicyContent:  policy.Spec.PolicyContent,
+		PolicyContent:  policyContent,
 		BearerToken:    token,
+		ETag:           policy.Status.ETag,
+		OverwriteDrift: overwritesDrift(&policy),
 	}
 
-	if err := apim.UpsertInboundPolicy(ctx, cfg); err != nil {
+	subject := policyEventSubject(cfg.ServiceName, cfg.APIID, cfg.OperationID)
+	eventData := cloudevents.Data{
+		APIMService:     cfg.ServiceName,
+		Kind:            "APIMInboundPolicy",
+		Namespace:       policy.Namespace,
+		Name:            policy.Name,
+		APIID:           cfg.APIID,
+		OperationID:     cfg.OperationID,
+		AzureResourceID: apim.PolicyResourceID(cfg.SubscriptionID, cfg.ResourceGroup, cfg.ServiceName, cfg.APIID, cfg.OperationID),
+	}
+
+	// Use Patch to update only status without touching spec fields.
+	statusPatch := client.MergeFrom(policy.DeepCopy())
+	if etag, err := r.APIM.UpsertInboundPolicy(withDriftEvents(ctx, subject, eventData), cfg); err != nil {
 		if cfg.OperationID != "" {
 			logger.Error(err, "❌ Failed to upsert APIM Inbound Policy", "apiID", cfg.APIID, "operationID", cfg.OperationID)
 		} else {
 			logger.Error(err, "❌ Failed to upsert APIM Inbound Policy", "apiID", cfg.APIID)
 		}
 		policy.Status.Phase = phaseError
-		policy.Status.Message = err.Error()
+		recordSyncFailure(kindAPIMInboundPolicy, &policy)
+		policy.Status.Message = redact.String(err.Error())
+		invalidateRejectedToken(r.Tokens, err)
+		warnNotReady(r.Recorder, &policy, &policy.Status.Conditions, &policy.Status.ObservedGeneration, policyFailureReason(err), failureMessage("Failed to upsert inbound policy in APIM", err))
+		if apim.IsPreconditionFailed(err) {
+			// Forget the stale ETag so the next reconcile writes the declared policy over the edit.
+			policy.Status.ETag = ""
+		}
 	} else {
 		if cfg.OperationID != "" {
 			logger.Info("✅ Successfully upserted APIM Inbound Policy", "apiID", cfg.APIID, "operationID", cfg.OperationID)
@@ -129,15 +224,30 @@ func (r *APIMInboundPolicyReconciler) Reconcile(ctx context.Context, req ctrl.Re
 			policy.Status.Message = "APIM Inbound Policy created or updated"
 		}
 		policy.Status.Phase = phaseCreated
+		setReady(&policy.Status.Conditions, &policy.Status.ObservedGeneration, policy.Generation, metav1.ConditionTrue, apimv1.ReasonSynced, "Inbound policy is synced to APIM")
+		recordSyncSuccess(kindAPIMInboundPolicy, &policy)
+		policy.Status.AzureResourceID = eventData.AzureResourceID
+		policy.Status.TerraformImports = policyTerraformImports(cfg.SubscriptionID, cfg.ResourceGroup, cfg.ServiceName, cfg.APIID, cfg.OperationID)
+		policy.Status.ETag = etag
+		if apim.IsDryRun() {
+			policy.Status.Phase = phaseDryRun
+			policy.Status.Message = "Dry-run: policy changes were logged but not sent to APIM"
+			setReady(&policy.Status.Conditions, &policy.Status.ObservedGeneration, policy.Generation, metav1.ConditionTrue, apimv1.ReasonSynced, policy.Status.Message)
+		} else {
+			cloudEventSink.Emit(cloudevents.TypePolicyApplied, subject, eventData)
+		}
 	}
 
-	// Use Patch to update only status without touching spec fields.
-	statusPatch := client.MergeFrom(policy.DeepCopy())
 	if err := r.Status().Patch(ctx, &policy, statusPatch); err != nil {
 		logger.Error(err, "❌ Failed to patch APIMInboundPolicy status", "apiID", cfg.APIID)
 		return ctrl.Result{}, err
 	}
 
+	// ConfigMaps and Secrets are not watched, so templates are rendered again every resync interval to pick
+	// up changed values.
+	if len(policy.Spec.TemplateValues) > 0 {
+		return ctrl.Result{RequeueAfter: hostRefreshInterval()}, nil
+	}
 	return ctrl.Result{}, nil
 }
 
@@ -158,15 +268,21 @@ func (r *APIMInboundPolicyReconciler) SetupWithManager(mgr ctrl.Manager) error {
 				if !ok {
 					return false
 				}
-				// Reconcile if any spec field changed
-				return oldPolicy.Spec.APIMService != newPolicy.Spec.APIMService ||
+				// Reconcile if any spec field changed, a named value the policy references changed, or the
+				// policy is being deleted and its finalizer has to delete it from APIM
+				return newPolicy.DeletionTimestamp != nil ||
+					oldPolicy.Spec.APIMService != newPolicy.Spec.APIMService ||
 					oldPolicy.Spec.APIID != newPolicy.Spec.APIID ||
 					oldPolicy.Spec.OperationID != newPolicy.Spec.OperationID ||
-					oldPolicy.Spec.PolicyContent != newPolicy.Spec.PolicyContent
+					oldPolicy.Spec.PolicyContent != newPolicy.Spec.PolicyContent ||
+					!equality.Semantic.DeepEqual(oldPolicy.Spec.TemplateValues, newPolicy.Spec.TemplateValues) ||
+					oldPolicy.Annotations[namedValueChangedAnnotation] != newPolicy.Annotations[namedValueChangedAnnotation]
 			},
+			// Deletes are seen as the update that sets the deletion timestamp while the finalizer holds the policy.
 			DeleteFunc:  func(e event.DeleteEvent) bool { return false },
 			GenericFunc: func(e event.GenericEvent) bool { return false },
 		}).
+		WithEventFilter(shardPredicate()).
 		Named("apiminboundpolicy").
-		Complete(r)
+		Complete(withTracing("APIMInboundPolicy", r))
 }
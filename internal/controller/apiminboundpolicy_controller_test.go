@@ -24,12 +24,14 @@ import (
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	"k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	apimv1 "github.com/hedinit/azure-apim-operator/api/v1"
+	"github.com/hedinit/azure-apim-operator/internal/apim"
 )
 
 var _ = Describe("APIMInboundPolicy Controller", func() {
@@ -90,6 +92,9 @@ var _ = Describe("APIMInboundPolicy Controller", func() {
 		resource := &apimv1.APIMInboundPolicy{}
 		err := k8sClient.Get(ctx, typeNamespacedName, resource)
 		if err == nil {
+			// Leave the fake policy in place instead of running the cleanup finalizer against APIM.
+			resource.Finalizers = nil
+			Expect(k8sClient.Update(ctx, resource)).To(Succeed())
 			Expect(k8sClient.Delete(ctx, resource)).To(Succeed())
 		}
 
@@ -142,6 +147,35 @@ var _ = Describe("APIMInboundPolicy Controller", func() {
 			Expect(policy.Status.Message).To(ContainSubstring("missing AZURE_CLIENT_ID or AZURE_TENANT_ID"))
 		})
 
+		It("should fail fast when the SKU of the APIMService rejects the policy", func() {
+			By("recording a Consumption SKU on the APIMService")
+			var apimService apimv1.APIMService
+			Expect(k8sClient.Get(ctx, apimServiceNamespacedName, &apimService)).To(Succeed())
+			apimService.Status.SKU = apim.SKUConsumption
+			apimService.Status.Capabilities = &apimv1.APIMServiceCapabilities{UnsupportedPolicies: []string{"quota-by-key", "rate-limit-by-key"}}
+			Expect(k8sClient.Status().Update(ctx, &apimService)).To(Succeed())
+
+			By("using quota-by-key in the policy")
+			var policy apimv1.APIMInboundPolicy
+			Expect(k8sClient.Get(ctx, typeNamespacedName, &policy)).To(Succeed())
+			policy.Spec.PolicyContent = `<policies><inbound><quota-by-key calls="100" renewal-period="3600" counter-key="@(context.Subscription.Id)" /></inbound></policies>`
+			Expect(k8sClient.Update(ctx, &policy)).To(Succeed())
+
+			By("reconciling without Azure credentials, which the SKU check comes before")
+			controllerReconciler := &APIMInboundPolicyReconciler{Client: k8sClient, Scheme: k8sClient.Scheme()}
+			result, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(Equal(hostRefreshInterval()))
+
+			Expect(k8sClient.Get(ctx, typeNamespacedName, &policy)).To(Succeed())
+			Expect(policy.Status.Phase).To(Equal("Error"))
+			Expect(policy.Status.Message).To(Equal("Policy element <quota-by-key> is not supported on this SKU (Consumption)"))
+			ready := apimeta.FindStatusCondition(policy.Status.Conditions, "Ready")
+			Expect(ready).NotTo(BeNil())
+			Expect(ready.Status).To(Equal(metav1.ConditionFalse))
+			Expect(ready.Reason).To(Equal(apimv1.ReasonNotSupportedOnSKU))
+		})
+
 		It("should handle missing APIMService gracefully", func() {
 			By("creating a policy with a non-existent APIMService")
 			invalidPolicyName := types.NamespacedName{
@@ -0,0 +1,92 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	apimv1 "github.com/hedinit/azure-apim-operator/api/v1"
+	"github.com/hedinit/azure-apim-operator/internal/apim"
+)
+
+const (
+	// apimInboundPolicyFinalizer keeps an APIMInboundPolicy until its policy is deleted from APIM.
+	apimInboundPolicyFinalizer = "apim.operator.io/policy-cleanup"
+
+	// eventReasonPolicyDeletionFailed is the Warning Event reason for a policy that couldn't be deleted from APIM.
+	eventReasonPolicyDeletionFailed = "PolicyDeletionFailed"
+)
+
+// finalize deletes the policy of a deleted APIMInboundPolicy from APIM and removes the finalizer. Policies the
+// operator never applied are left in APIM. A failed deletion keeps the finalizer and is retried.
+func (r *APIMInboundPolicyReconciler) finalize(ctx context.Context, policy *apimv1.APIMInboundPolicy) (ctrl.Result, error) {
+	logger := ctrl.Log.WithName("apiminboundpolicy_controller")
+	if !controllerutil.ContainsFinalizer(policy, apimInboundPolicyFinalizer) {
+		return ctrl.Result{}, nil
+	}
+
+	// The resource ID is recorded with the first successful upsert.
+	if policy.Status.AzureResourceID == "" {
+		logger.Info("ℹ️ Policy was never applied by the operator, leaving APIM unchanged", "apiID", policy.Spec.APIID)
+		return ctrl.Result{}, r.removeFinalizer(ctx, policy)
+	}
+
+	operatorNamespace, err := getOperatorNamespace()
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("get operator namespace: %w", err)
+	}
+	var apimService apimv1.APIMService
+	if err := r.Get(ctx, client.ObjectKey{Name: policy.Spec.APIMService, Namespace: operatorNamespace}, &apimService); err != nil {
+		if errors.IsNotFound(err) {
+			// Without the service there is nothing to delete the policy from.
+			logger.Info("ℹ️ APIMService not found, leaving the policy", "apiID", policy.Spec.APIID, "apimService", policy.Spec.APIMService)
+			return ctrl.Result{}, r.removeFinalizer(ctx, policy)
+		}
+		return ctrl.Result{}, err
+	}
+
+	clientID := os.Getenv("AZURE_CLIENT_ID")
+	tenantID := os.Getenv("AZURE_TENANT_ID")
+	if clientID == "" || tenantID == "" {
+		logger.Error(fmt.Errorf("missing identity env vars"), "❌ AZURE_CLIENT_ID or AZURE_TENANT_ID not set")
+		recordWarningEvent(r.Recorder, policy, eventReasonPolicyDeletionFailed, "Failed to delete inbound policy from APIM: missing AZURE_CLIENT_ID or AZURE_TENANT_ID")
+		return ctrl.Result{RequeueAfter: 30 * time.Second}, nil
+	}
+	token, err := managementToken(ctx, r.Tokens, clientID, tenantID)
+	if err != nil {
+		logger.Error(err, "❌ Failed to get Azure token")
+		recordWarningEvent(r.Recorder, policy, eventReasonPolicyDeletionFailed, failureMessage("Failed to delete inbound policy from APIM", err))
+		return ctrl.Result{RequeueAfter: 30 * time.Second}, nil
+	}
+
+	// An API deleted together with its APIMAPI took the policy with it; DeleteInboundPolicy treats that as deleted.
+	err = r.APIM.DeleteInboundPolicy(ctx, apim.APIMInboundPolicyConfig{
+		SubscriptionID: apimService.Spec.Subscription,
+		ResourceGroup:  apimService.Spec.ResourceGroup,
+		ServiceName:    policy.Spec.APIMService,
+		APIID:          policy.Spec.APIID,
+		OperationID:    policy.Spec.OperationID,
+		BearerToken:    token,
+	})
+	if err != nil {
+		logger.Error(err, "❌ Failed to delete inbound policy from APIM", "apiID", policy.Spec.APIID, "operationID", policy.Spec.OperationID)
+		invalidateRejectedToken(r.Tokens, err)
+		recordWarningEvent(r.Recorder, policy, eventReasonPolicyDeletionFailed, failureMessage("Failed to delete inbound policy from APIM", err))
+		return ctrl.Result{RequeueAfter: 30 * time.Second}, nil
+	}
+	logger.Info("🗑️ Deleted inbound policy from APIM", "apiID", policy.Spec.APIID, "operationID", policy.Spec.OperationID)
+	return ctrl.Result{}, r.removeFinalizer(ctx, policy)
+}
+
+// removeFinalizer removes the finalizer of policy, so Kubernetes deletes it.
+func (r *APIMInboundPolicyReconciler) removeFinalizer(ctx context.Context, policy *apimv1.APIMInboundPolicy) error {
+	patch := client.MergeFrom(policy.DeepCopy())
+	controllerutil.RemoveFinalizer(policy, apimInboundPolicyFinalizer)
+	return r.Patch(ctx, policy, patch)
+}
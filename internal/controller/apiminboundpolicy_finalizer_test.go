@@ -0,0 +1,186 @@
+package controller
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	apimv1 "github.com/hedinit/azure-apim-operator/api/v1"
+	"github.com/hedinit/azure-apim-operator/internal/apimtest"
+	"github.com/hedinit/azure-apim-operator/internal/identity"
+)
+
+func TestAPIMInboundPolicyFinalize(t *testing.T) {
+	const apiPath = "/subscriptions/sub/resourceGroups/rg/providers/Microsoft.ApiManagement/service/apim-test/apis/orders"
+	tests := []struct {
+		name        string
+		operationID string
+		applied     bool
+		wantPolicy  string
+		keptPolicy  string
+	}{
+		{name: "API policy is deleted", applied: true, wantPolicy: apiPath + "/policies/policy", keptPolicy: apiPath + "/operations/get-order/policies/policy"},
+		{name: "operation policy is deleted", operationID: "get-order", applied: true, wantPolicy: apiPath + "/operations/get-order/policies/policy", keptPolicy: apiPath + "/policies/policy"},
+		{name: "policy never applied is left", keptPolicy: apiPath + "/policies/policy"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fakeAPIM := apimtest.Start(t)
+			t.Setenv("AZURE_CLIENT_ID", "test-client-id")
+			t.Setenv("AZURE_TENANT_ID", "test-tenant-id")
+			identity.SetStaticToken("test-token")
+			t.Cleanup(func() { identity.SetStaticToken("") })
+			fakeAPIM.Put(apiPath+"/policies/policy", map[string]interface{}{"format": "xml", "value": "<policies />"})
+			fakeAPIM.Put(apiPath+"/operations/get-order/policies/policy", map[string]interface{}{"format": "xml", "value": "<policies />"})
+
+			scheme := runtime.NewScheme()
+			if err := apimv1.AddToScheme(scheme); err != nil {
+				t.Fatal(err)
+			}
+			now := metav1.NewTime(time.Now())
+			policy := &apimv1.APIMInboundPolicy{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "orders-policy", Namespace: "default", Finalizers: []string{apimInboundPolicyFinalizer}, DeletionTimestamp: &now,
+				},
+				Spec: apimv1.APIMInboundPolicySpec{APIMService: "apim-test", APIID: "orders", OperationID: tt.operationID},
+			}
+			if tt.applied {
+				policy.Status.AzureResourceID = "/subscriptions/sub/policies/policy"
+			}
+			service := &apimv1.APIMService{
+				ObjectMeta: metav1.ObjectMeta{Name: "apim-test", Namespace: "default"},
+				Spec:       apimv1.APIMServiceSpec{Subscription: "sub", ResourceGroup: "rg"},
+			}
+			r := &APIMInboundPolicyReconciler{
+				APIM:     fakeAPIM.Client(),
+				Client:   fake.NewClientBuilder().WithScheme(scheme).WithObjects(policy, service).Build(),
+				Scheme:   scheme,
+				Recorder: record.NewFakeRecorder(10),
+			}
+
+			key := types.NamespacedName{Name: "orders-policy", Namespace: "default"}
+			if _, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: key}); err != nil {
+				t.Fatalf("Reconcile() error = %v", err)
+			}
+			if err := r.Get(context.Background(), key, &apimv1.APIMInboundPolicy{}); !apierrors.IsNotFound(err) {
+				t.Errorf("Get() error = %v, want the APIMInboundPolicy deleted once the finalizer is removed", err)
+			}
+			if tt.wantPolicy != "" {
+				if _, ok := fakeAPIM.Properties(tt.wantPolicy); ok {
+					t.Errorf("%s still exists in APIM", tt.wantPolicy)
+				}
+			}
+			if _, ok := fakeAPIM.Properties(tt.keptPolicy); !ok {
+				t.Errorf("%s was deleted from APIM, want it kept", tt.keptPolicy)
+			}
+		})
+	}
+}
+
+func TestAPIMInboundPolicyFinalizeFailure(t *testing.T) {
+	fakeAPIM := apimtest.Start(t)
+	t.Setenv("AZURE_CLIENT_ID", "test-client-id")
+	t.Setenv("AZURE_TENANT_ID", "test-tenant-id")
+	identity.SetStaticToken("test-token")
+	t.Cleanup(func() { identity.SetStaticToken("") })
+	fakeAPIM.Inject(apimtest.Fault{Method: http.MethodDelete, PathSuffix: "/policies/policy", Status: http.StatusForbidden, Code: "AuthorizationFailed"})
+
+	scheme := runtime.NewScheme()
+	if err := apimv1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	now := metav1.NewTime(time.Now())
+	policy := &apimv1.APIMInboundPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "orders-policy", Namespace: "default", Finalizers: []string{apimInboundPolicyFinalizer}, DeletionTimestamp: &now},
+		Spec:       apimv1.APIMInboundPolicySpec{APIMService: "apim-test", APIID: "orders"},
+		Status:     apimv1.APIMInboundPolicyStatus{AzureResourceID: "/subscriptions/sub/policies/policy"},
+	}
+	service := &apimv1.APIMService{
+		ObjectMeta: metav1.ObjectMeta{Name: "apim-test", Namespace: "default"},
+		Spec:       apimv1.APIMServiceSpec{Subscription: "sub", ResourceGroup: "rg"},
+	}
+	recorder := record.NewFakeRecorder(10)
+	r := &APIMInboundPolicyReconciler{
+		APIM:     fakeAPIM.Client(),
+		Client:   fake.NewClientBuilder().WithScheme(scheme).WithObjects(policy, service).Build(),
+		Scheme:   scheme,
+		Recorder: recorder,
+	}
+
+	key := types.NamespacedName{Name: "orders-policy", Namespace: "default"}
+	result, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: key})
+	if err != nil || result.RequeueAfter == 0 {
+		t.Fatalf("Reconcile() = %+v, %v, want a requeue", result, err)
+	}
+	var got apimv1.APIMInboundPolicy
+	if err := r.Get(context.Background(), key, &got); err != nil || !controllerutil.ContainsFinalizer(&got, apimInboundPolicyFinalizer) {
+		t.Errorf("Get() = %v, %v, want the APIMInboundPolicy kept with its finalizer", got.Finalizers, err)
+	}
+	if event := <-recorder.Events; !strings.HasPrefix(event, "Warning PolicyDeletionFailed") {
+		t.Errorf("event = %q, want PolicyDeletionFailed", event)
+	}
+}
+
+// staticTokens is a TokenProvider that counts the tokens it hands out and how often it was invalidated.
+type staticTokens struct {
+	token         string
+	calls         int
+	invalidations int
+}
+
+func (s *staticTokens) ManagementToken(context.Context) (string, error) {
+	s.calls++
+	return s.token, nil
+}
+
+func (s *staticTokens) Invalidate() {
+	s.invalidations++
+}
+
+func TestAPIMInboundPolicyFinalizeUsesTokens(t *testing.T) {
+	fakeAPIM := apimtest.Start(t)
+	t.Setenv("AZURE_CLIENT_ID", "test-client-id")
+	t.Setenv("AZURE_TENANT_ID", "test-tenant-id")
+
+	scheme := runtime.NewScheme()
+	if err := apimv1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	now := metav1.NewTime(time.Now())
+	policy := &apimv1.APIMInboundPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "orders-policy", Namespace: "default", Finalizers: []string{apimInboundPolicyFinalizer}, DeletionTimestamp: &now},
+		Spec:       apimv1.APIMInboundPolicySpec{APIMService: "apim-test", APIID: "orders"},
+		Status:     apimv1.APIMInboundPolicyStatus{AzureResourceID: "/subscriptions/sub/policies/policy"},
+	}
+	service := &apimv1.APIMService{
+		ObjectMeta: metav1.ObjectMeta{Name: "apim-test", Namespace: "default"},
+		Spec:       apimv1.APIMServiceSpec{Subscription: "sub", ResourceGroup: "rg"},
+	}
+	tokens := &staticTokens{token: "shared-token"}
+	r := &APIMInboundPolicyReconciler{
+		APIM:   fakeAPIM.Client(),
+		Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(policy, service).Build(),
+		Scheme: scheme,
+		Tokens: tokens,
+	}
+
+	key := types.NamespacedName{Name: "orders-policy", Namespace: "default"}
+	if _, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: key}); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	requests := fakeAPIM.Requests()
+	if tokens.calls != 1 || len(requests) != 1 || requests[0].Token != "shared-token" {
+		t.Errorf("got %d tokens and requests %+v, want one request with the shared token", tokens.calls, requests)
+	}
+}
@@ -0,0 +1,92 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apimv1 "github.com/hedinit/azure-apim-operator/api/v1"
+	"github.com/hedinit/azure-apim-operator/internal/policytemplate"
+)
+
+// templateError is a failure to resolve or render a policy template. reason is the Ready condition reason.
+type templateError struct {
+	reason string
+	err    error
+}
+
+func (e *templateError) Error() string { return e.err.Error() }
+
+func (e *templateError) Unwrap() error { return e.err }
+
+// renderPolicyContent returns the policy content of policy with its placeholders replaced by the template
+// values. ConfigMaps and Secrets are read with reader, APIMNamedValues with c. A policy without template
+// values is returned as written.
+func renderPolicyContent(ctx context.Context, c, reader client.Reader, policy *apimv1.APIMInboundPolicy) (string, error) {
+	if len(policy.Spec.TemplateValues) == 0 {
+		return policy.Spec.PolicyContent, nil
+	}
+
+	values := make(map[string]string, len(policy.Spec.TemplateValues))
+	for _, tv := range policy.Spec.TemplateValues {
+		value, err := resolveTemplateValue(ctx, c, reader, policy.Namespace, &tv)
+		if err != nil {
+			return "", err
+		}
+		values[tv.Name] = value
+	}
+
+	rendered, err := policytemplate.Render(policy.Name, policy.Spec.PolicyContent, values)
+	if err != nil {
+		return "", &templateError{reason: apimv1.ReasonTemplateFailed, err: err}
+	}
+	return rendered, nil
+}
+
+// resolveTemplateValue reads a template value from its source.
+func resolveTemplateValue(ctx context.Context, c, reader client.Reader, namespace string, tv *apimv1.APIMInboundPolicyTemplateValue) (string, error) {
+	switch {
+	case tv.ConfigMapKeyRef != nil:
+		ref := tv.ConfigMapKeyRef
+		var configMap corev1.ConfigMap
+		if err := reader.Get(ctx, client.ObjectKey{Name: ref.Name, Namespace: namespace}, &configMap); err != nil {
+			return "", &templateError{reason: apimv1.ReasonTemplateFailed, err: fmt.Errorf("read ConfigMap %s of template value %s: %w", ref.Name, tv.Name, err)}
+		}
+		value, ok := configMap.Data[ref.Key]
+		if !ok {
+			return "", &templateError{reason: apimv1.ReasonTemplateFailed, err: fmt.Errorf("no key %s in ConfigMap %s", ref.Key, ref.Name)}
+		}
+		return value, nil
+	case tv.SecretKeyRef != nil:
+		ref := tv.SecretKeyRef
+		var secret corev1.Secret
+		if err := reader.Get(ctx, client.ObjectKey{Name: ref.Name, Namespace: namespace}, &secret); err != nil {
+			return "", &templateError{reason: apimv1.ReasonSecretReadFailed, err: fmt.Errorf("read Secret %s of template value %s: %w", ref.Name, tv.Name, err)}
+		}
+		value, ok := secret.Data[ref.Key]
+		if !ok {
+			return "", &templateError{reason: apimv1.ReasonSecretReadFailed, err: fmt.Errorf("no key %s in Secret %s", ref.Key, ref.Name)}
+		}
+		return string(value), nil
+	case tv.NamedValue != "":
+		var nv apimv1.APIMNamedValue
+		if err := c.Get(ctx, client.ObjectKey{Name: tv.NamedValue, Namespace: namespace}, &nv); err != nil {
+			return "", &templateError{reason: apimv1.ReasonTemplateFailed, err: fmt.Errorf("read APIMNamedValue %s of template value %s: %w", tv.NamedValue, tv.Name, err)}
+		}
+		return "{{" + namedValueDisplayName(&nv.Spec) + "}}", nil
+	}
+	return tv.Value, nil
+}
+
+// policyReferencesTemplateNamedValue reports whether a template value of policy refers to the APIMNamedValue
+// called name.
+func policyReferencesTemplateNamedValue(policy *apimv1.APIMInboundPolicy, name string) bool {
+	for _, tv := range policy.Spec.TemplateValues {
+		if tv.NamedValue == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,102 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	apimv1 "github.com/hedinit/azure-apim-operator/api/v1"
+)
+
+func TestRenderPolicyContent(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	if err := apimv1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "env", Namespace: "apim-system"},
+			Data:       map[string]string{"tenantId": "contoso-tenant"},
+		},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "oauth", Namespace: "apim-system"},
+			Data:       map[string][]byte{"audience": []byte("api://orders")},
+		},
+		&apimv1.APIMNamedValue{
+			ObjectMeta: metav1.ObjectMeta{Name: "backend-key", Namespace: "apim-system"},
+			Spec:       apimv1.APIMNamedValueSpec{NamedValueID: "backend-key", DisplayName: "BackendKey"},
+		},
+	).Build()
+
+	values := []apimv1.APIMInboundPolicyTemplateValue{
+		{Name: "env", Value: "prod"},
+		{Name: "tenantId", ConfigMapKeyRef: &apimv1.ConfigMapKeyRef{Name: "env", Key: "tenantId"}},
+		{Name: "audience", SecretKeyRef: &apimv1.SecretKeyRef{Name: "oauth", Key: "audience"}},
+		{Name: "key", NamedValue: "backend-key"},
+	}
+	tests := []struct {
+		name       string
+		content    string
+		values     []apimv1.APIMInboundPolicyTemplateValue
+		want       string
+		wantReason string
+	}{
+		{
+			name:    "without template values the content is sent as written",
+			content: `<policies><inbound><value>${{ .env }}</value></inbound></policies>`,
+			want:    `<policies><inbound><value>${{ .env }}</value></inbound></policies>`,
+		},
+		{
+			name:    "placeholders are replaced and named values are left to APIM",
+			content: `<policies><inbound><value>${{ .env }}</value><value>${{.tenantId}}</value><value>${{ .audience }}</value><value>${{ .key }}</value><value>{{Other}}</value></inbound></policies>`,
+			values:  values,
+			want:    `<policies><inbound><value>prod</value><value>contoso-tenant</value><value>api://orders</value><value>{{BackendKey}}</value><value>{{Other}}</value></inbound></policies>`,
+		},
+		{
+			name:       "a placeholder without a value fails",
+			content:    `<policies><inbound><value>${{ .missing }}</value></inbound></policies>`,
+			values:     values,
+			wantReason: apimv1.ReasonTemplateFailed,
+		},
+		{
+			name:       "a missing Secret key fails",
+			content:    `<policies />`,
+			values:     []apimv1.APIMInboundPolicyTemplateValue{{Name: "a", SecretKeyRef: &apimv1.SecretKeyRef{Name: "oauth", Key: "missing"}}},
+			wantReason: apimv1.ReasonSecretReadFailed,
+		},
+		{
+			name:       "a missing APIMNamedValue fails",
+			content:    `<policies />`,
+			values:     []apimv1.APIMInboundPolicyTemplateValue{{Name: "a", NamedValue: "missing"}},
+			wantReason: apimv1.ReasonTemplateFailed,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy := &apimv1.APIMInboundPolicy{
+				ObjectMeta: metav1.ObjectMeta{Name: "orders", Namespace: "apim-system"},
+				Spec:       apimv1.APIMInboundPolicySpec{PolicyContent: tt.content, TemplateValues: tt.values},
+			}
+			got, err := renderPolicyContent(context.Background(), c, c, policy)
+			if tt.wantReason != "" {
+				var tmplErr *templateError
+				if !errors.As(err, &tmplErr) || tmplErr.reason != tt.wantReason {
+					t.Fatalf("renderPolicyContent() error = %v, want reason %s", err, tt.wantReason)
+				}
+				return
+			}
+			if err != nil || got != tt.want {
+				t.Errorf("renderPolicyContent() = %q, %v, want %q", got, err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,286 @@
+package controller
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	apimv1 "github.com/hedinit/azure-apim-operator/api/v1"
+	"github.com/hedinit/azure-apim-operator/internal/apim"
+	"github.com/hedinit/azure-apim-operator/internal/identity"
+	"github.com/hedinit/azure-apim-operator/internal/redact"
+)
+
+const (
+	// namedValueChangedAnnotation records on an APIMInboundPolicy when a named value it references last
+	// changed. Changing it makes the policy controller apply the policy again, so the gateway drops values
+	// it cached with the old policy.
+	namedValueChangedAnnotation = "apim.operator.io/named-value-changed"
+
+	// defaultNamedValueRefreshInterval applies when refreshInterval is unset.
+	defaultNamedValueRefreshInterval = time.Hour
+)
+
+// APIMNamedValueReconciler reconciles APIMNamedValue custom resources.
+// This controller manages named values in Azure API Management and keeps them in sync with their source.
+type APIMNamedValueReconciler struct {
+	client.Client
+	// APIReader reads referenced Secrets directly from the API server, so the operator doesn't cache
+	// every Secret of the cluster.
+	APIReader client.Reader
+	Scheme    *runtime.Scheme
+	// Recorder records Warning Events for failed syncs. Nil disables them.
+	Recorder record.EventRecorder
+	// APIM sends the Azure Management API requests. Nil sends them to the public endpoint.
+	APIM *apim.Client
+	// Tokens provides the Azure Management API token shared by all reconcilers. Nil asks Azure AD on
+	// every reconcile.
+	Tokens identity.TokenProvider
+}
+
+// +kubebuilder:rbac:groups=apim.operator.io,resources=apimnamedvalues,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=apim.operator.io,resources=apimnamedvalues/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=apim.operator.io,resources=apimnamedvalues/finalizers,verbs=update
+// +kubebuilder:rbac:groups="",resources=secrets,verbs=get
+
+// Reconcile writes the named value to APIM when its spec or its value changed. Every refresh interval it
+// reads the source again: a Kubernetes Secret is read by the operator, a Key Vault secret is refreshed by
+// APIM. When the value changed, the inbound policies that reference the named value are applied again.
+func (r *APIMNamedValueReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	var nv apimv1.APIMNamedValue
+	if err := r.Get(ctx, req.NamespacedName, &nv); err != nil {
+		if errors.IsNotFound(err) {
+			logger.Info("🧹 APIMNamedValue deleted, skipping", "name", req.NamespacedName)
+			forgetSyncMetrics(kindAPIMNamedValue, req.Namespace, req.Name)
+			return ctrl.Result{}, nil
+		}
+		logger.Error(err, "❌ Failed to get APIMNamedValue")
+		return ctrl.Result{}, err
+	}
+	setSpanAPIAttributes(ctx, nv.Spec.APIMService, "")
+
+	operatorNamespace, err := getOperatorNamespace()
+	if err != nil {
+		logger.Error(err, "❌ Failed to get operator namespace")
+		return ctrl.Result{}, fmt.Errorf("get operator namespace: %w", err)
+	}
+
+	var apimService apimv1.APIMService
+	if err := r.Get(ctx, client.ObjectKey{Name: nv.Spec.APIMService, Namespace: operatorNamespace}, &apimService); err != nil {
+		logger.Error(err, "❌ Failed to get APIMService", "name", nv.Spec.APIMService)
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	observeAPIMService(ctx, &apimService)
+
+	clientID := os.Getenv("AZURE_CLIENT_ID")
+	tenantID := os.Getenv("AZURE_TENANT_ID")
+	if clientID == "" || tenantID == "" {
+		logger.Error(fmt.Errorf("missing identity env vars"), "❌ AZURE_CLIENT_ID or AZURE_TENANT_ID not set")
+		r.markFailed(ctx, &nv, apimv1.ReasonAuthFailed, "missing AZURE_CLIENT_ID or AZURE_TENANT_ID", nil)
+		return ctrl.Result{RequeueAfter: 30 * time.Second}, nil
+	}
+	token, err := managementToken(ctx, r.Tokens, clientID, tenantID)
+	if err != nil {
+		logger.Error(err, "❌ Failed to get Azure token")
+		r.markFailed(ctx, &nv, apimv1.ReasonAuthFailed, errMsgFailedToGetAzureToken, err)
+		return ctrl.Result{RequeueAfter: 30 * time.Second}, nil
+	}
+
+	cfg := apim.APIMNamedValueConfig{
+		SubscriptionID: apimService.Spec.Subscription,
+		ResourceGroup:  apimService.Spec.ResourceGroup,
+		ServiceName:    nv.Spec.APIMService,
+		NamedValueID:   nv.Spec.NamedValueID,
+		DisplayName:    namedValueDisplayName(&nv.Spec),
+		Secret:         nv.Spec.Secret || nv.Spec.SecretRef != nil || nv.Spec.KeyVault != nil,
+		Value:          nv.Spec.Value,
+		BearerToken:    token,
+	}
+	if nv.Spec.KeyVault != nil {
+		cfg.KeyVaultSecretIdentifier = nv.Spec.KeyVault.SecretIdentifier
+		cfg.KeyVaultIdentityClientID = nv.Spec.KeyVault.IdentityClientID
+	}
+	if ref := nv.Spec.SecretRef; ref != nil {
+		var secret corev1.Secret
+		if err := r.APIReader.Get(ctx, client.ObjectKey{Name: ref.Name, Namespace: nv.Namespace}, &secret); err != nil {
+			logger.Error(err, "❌ Failed to read Secret of named value", "secret", ref.Name)
+			r.markFailed(ctx, &nv, apimv1.ReasonSecretReadFailed, fmt.Sprintf("Failed to read Secret %s", ref.Name), err)
+			return ctrl.Result{RequeueAfter: 30 * time.Second}, nil
+		}
+		value, ok := secret.Data[ref.Key]
+		if !ok {
+			r.markFailed(ctx, &nv, apimv1.ReasonSecretReadFailed, fmt.Sprintf("Secret %s has no key %s", ref.Name, ref.Key), nil)
+			return ctrl.Result{RequeueAfter: 30 * time.Second}, nil
+		}
+		cfg.Value = string(value)
+	}
+
+	// A Key Vault value is only known to APIM, so it is read back after APIM refreshed it.
+	specChanged := nv.Status.ObservedGeneration != nv.Generation || nv.Status.Phase != phaseCreated
+	valueHash := ""
+	if nv.Spec.KeyVault == nil {
+		valueHash = namedValueHash(&nv, cfg.Value)
+	}
+	switch {
+	case specChanged || (nv.Spec.KeyVault == nil && valueHash != nv.Status.ValueHash):
+		if err := r.APIM.UpsertNamedValue(ctx, cfg); err != nil {
+			logger.Error(err, "❌ Failed to upsert APIM named value", "namedValue", cfg.NamedValueID)
+			r.markFailed(ctx, &nv, azureFailureReason(err), "Failed to upsert named value in APIM", err)
+			return ctrl.Result{RequeueAfter: 30 * time.Second}, nil
+		}
+	case nv.Spec.KeyVault != nil:
+		if err := r.APIM.RefreshNamedValueSecret(ctx, cfg); err != nil {
+			logger.Error(err, "❌ Failed to refresh APIM named value from Key Vault", "namedValue", cfg.NamedValueID)
+			r.markFailed(ctx, &nv, azureFailureReason(err), "Failed to refresh named value from Key Vault", err)
+			return ctrl.Result{RequeueAfter: 30 * time.Second}, nil
+		}
+	}
+	if nv.Spec.KeyVault != nil && !apim.IsDryRun() {
+		value, err := r.APIM.GetNamedValueValue(ctx, cfg)
+		if err != nil {
+			logger.Error(err, "❌ Failed to read APIM named value", "namedValue", cfg.NamedValueID)
+			r.markFailed(ctx, &nv, azureFailureReason(err), "Failed to read named value from APIM", err)
+			return ctrl.Result{RequeueAfter: 30 * time.Second}, nil
+		}
+		valueHash = namedValueHash(&nv, value)
+	}
+
+	statusPatch := client.MergeFrom(nv.DeepCopy())
+	now := time.Now().UTC().Format(time.RFC3339)
+	if valueHash != "" && valueHash != nv.Status.ValueHash {
+		// The first value seen is not a change: no policy can have cached an older one from this resource.
+		if nv.Status.ValueHash != "" && !apim.IsDryRun() {
+			logger.Info("🔁 Named value changed, applying dependent policies again", "namedValue", cfg.NamedValueID)
+			if err := r.signalDependentPolicies(ctx, operatorNamespace, &nv, cfg.DisplayName); err != nil {
+				logger.Error(err, "❌ Failed to signal policies that reference the named value", "namedValue", cfg.NamedValueID)
+				return ctrl.Result{}, err
+			}
+		}
+		nv.Status.ValueHash = valueHash
+		nv.Status.ValueChangedAt = now
+	}
+	nv.Status.LastRefreshedAt = now
+	nv.Status.Phase = phaseCreated
+	nv.Status.Message = "Named value is synced to APIM"
+	nv.Status.AzureResourceID = apim.NamedValueResourceID(cfg.SubscriptionID, cfg.ResourceGroup, cfg.ServiceName, cfg.NamedValueID)
+	nv.Status.TerraformImports = resourceTerraformImports("azurerm_api_management_named_value", "Microsoft.ApiManagement/service/namedValues", nv.Status.AzureResourceID)
+	setReady(&nv.Status.Conditions, &nv.Status.ObservedGeneration, nv.Generation, metav1.ConditionTrue, apimv1.ReasonSynced, nv.Status.Message)
+	if apim.IsDryRun() {
+		nv.Status.Phase = phaseDryRun
+		nv.Status.Message = "Dry-run: named value changes were logged but not sent to APIM"
+		setReady(&nv.Status.Conditions, &nv.Status.ObservedGeneration, nv.Generation, metav1.ConditionTrue, apimv1.ReasonSynced, nv.Status.Message)
+	}
+	recordSyncSuccess(kindAPIMNamedValue, &nv)
+	if err := r.Status().Patch(ctx, &nv, statusPatch); err != nil {
+		logger.Error(err, "❌ Failed to patch APIMNamedValue status")
+		return ctrl.Result{}, err
+	}
+	logger.Info("✅ APIM named value synced", "namedValue", cfg.NamedValueID)
+
+	return ctrl.Result{RequeueAfter: namedValueRefreshInterval(&nv.Spec)}, nil
+}
+
+// markFailed sets the Error phase and a False Ready condition, records a Warning Event and patches the status.
+func (r *APIMNamedValueReconciler) markFailed(ctx context.Context, nv *apimv1.APIMNamedValue, reason, message string, err error) {
+	statusPatch := client.MergeFrom(nv.DeepCopy())
+	nv.Status.Phase = phaseError
+	nv.Status.Message = message
+	if err != nil {
+		nv.Status.Message = redact.String(err.Error())
+		message = failureMessage(message, err)
+		invalidateRejectedToken(r.Tokens, err)
+	}
+	recordSyncFailure(kindAPIMNamedValue, nv)
+	warnNotReady(r.Recorder, nv, &nv.Status.Conditions, &nv.Status.ObservedGeneration, reason, message)
+	if patchErr := r.Status().Patch(ctx, nv, statusPatch); patchErr != nil {
+		log.FromContext(ctx).Error(patchErr, "❌ Failed to patch APIMNamedValue status")
+	}
+}
+
+// signalDependentPolicies annotates every APIMInboundPolicy of the same APIM service that references the
+// named value by displayName or in a template value, which makes the policy controller apply them again.
+func (r *APIMNamedValueReconciler) signalDependentPolicies(ctx context.Context, namespace string, nv *apimv1.APIMNamedValue, displayName string) error {
+	var policies apimv1.APIMInboundPolicyList
+	if err := r.List(ctx, &policies, client.InNamespace(namespace)); err != nil {
+		return fmt.Errorf("list APIMInboundPolicies: %w", err)
+	}
+	changedAt := time.Now().UTC().Format(time.RFC3339Nano)
+	for i := range policies.Items {
+		policy := &policies.Items[i]
+		if policy.Spec.APIMService != nv.Spec.APIMService ||
+			(!policyReferencesNamedValue(policy.Spec.PolicyContent, displayName) && !policyReferencesTemplateNamedValue(policy, nv.Name)) {
+			continue
+		}
+		patch := client.MergeFrom(policy.DeepCopy())
+		if policy.Annotations == nil {
+			policy.Annotations = map[string]string{}
+		}
+		policy.Annotations[namedValueChangedAnnotation] = changedAt
+		if err := r.Patch(ctx, policy, patch); err != nil && !errors.IsNotFound(err) {
+			return fmt.Errorf("signal APIMInboundPolicy %s: %w", policy.Name, err)
+		}
+	}
+	return nil
+}
+
+// policyReferencesNamedValue reports whether a policy document references the named value {{displayName}}.
+func policyReferencesNamedValue(policyContent, displayName string) bool {
+	return strings.Contains(policyContent, "{{"+displayName+"}}")
+}
+
+// namedValueDisplayName returns the display name of a named value, which defaults to its ID.
+func namedValueDisplayName(spec *apimv1.APIMNamedValueSpec) string {
+	if spec.DisplayName != "" {
+		return spec.DisplayName
+	}
+	return spec.NamedValueID
+}
+
+// namedValueRefreshInterval returns how often the source of a named value is read again.
+func namedValueRefreshInterval(spec *apimv1.APIMNamedValueSpec) time.Duration {
+	if spec.RefreshInterval != nil && spec.RefreshInterval.Duration > 0 {
+		return spec.RefreshInterval.Duration
+	}
+	return defaultNamedValueRefreshInterval
+}
+
+// namedValueHash returns the hash of value recorded in the status of nv. It is salted with the UID of nv, so
+// the status doesn't reveal short secrets to readers who can guess them.
+func namedValueHash(nv *apimv1.APIMNamedValue, value string) string {
+	sum := sha256.Sum256([]byte(string(nv.UID) + "\x00" + value))
+	return hex.EncodeToString(sum[:])
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *APIMNamedValueReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&apimv1.APIMNamedValue{}).
+		WithEventFilter(predicate.Funcs{
+			CreateFunc: func(e event.CreateEvent) bool { return true },
+			UpdateFunc: func(e event.UpdateEvent) bool {
+				return e.ObjectOld.GetGeneration() != e.ObjectNew.GetGeneration()
+			},
+			DeleteFunc:  func(e event.DeleteEvent) bool { return false },
+			GenericFunc: func(e event.GenericEvent) bool { return false },
+		}).
+		WithEventFilter(shardPredicate()).
+		Named("apimnamedvalue").
+		Complete(withTracing("APIMNamedValue", r))
+}
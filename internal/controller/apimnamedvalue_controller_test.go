@@ -0,0 +1,87 @@
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	apimv1 "github.com/hedinit/azure-apim-operator/api/v1"
+)
+
+func TestNamedValueHash(t *testing.T) {
+	nv := &apimv1.APIMNamedValue{ObjectMeta: metav1.ObjectMeta{UID: types.UID("a")}}
+	other := &apimv1.APIMNamedValue{ObjectMeta: metav1.ObjectMeta{UID: types.UID("b")}}
+	if namedValueHash(nv, "secret") != namedValueHash(nv, "secret") {
+		t.Error("namedValueHash() differs for the same value")
+	}
+	if namedValueHash(nv, "secret") == namedValueHash(nv, "rotated") {
+		t.Error("namedValueHash() is equal for different values")
+	}
+	if namedValueHash(nv, "secret") == namedValueHash(other, "secret") {
+		t.Error("namedValueHash() is not salted with the UID")
+	}
+}
+
+func TestNamedValueDefaults(t *testing.T) {
+	spec := &apimv1.APIMNamedValueSpec{NamedValueID: "backend-key"}
+	if got := namedValueDisplayName(spec); got != "backend-key" {
+		t.Errorf("namedValueDisplayName() = %q, want the ID", got)
+	}
+	if got := namedValueRefreshInterval(spec); got != defaultNamedValueRefreshInterval {
+		t.Errorf("namedValueRefreshInterval() = %s, want the default", got)
+	}
+
+	spec.DisplayName = "BackendKey"
+	spec.RefreshInterval = &metav1.Duration{Duration: 15 * time.Minute}
+	if got := namedValueDisplayName(spec); got != "BackendKey" {
+		t.Errorf("namedValueDisplayName() = %q, want BackendKey", got)
+	}
+	if got := namedValueRefreshInterval(spec); got != 15*time.Minute {
+		t.Errorf("namedValueRefreshInterval() = %s, want 15m", got)
+	}
+}
+
+func TestSignalDependentPolicies(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := apimv1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	policy := func(name, service, content string) *apimv1.APIMInboundPolicy {
+		return &apimv1.APIMInboundPolicy{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "apim-system"},
+			Spec:       apimv1.APIMInboundPolicySpec{APIMService: service, APIID: "orders", PolicyContent: content},
+		}
+	}
+	templated := policy("uses-key-in-template", "my-apim", `<value>${{ .key }}</value>`)
+	templated.Spec.TemplateValues = []apimv1.APIMInboundPolicyTemplateValue{{Name: "key", NamedValue: "backend-key"}}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		policy("uses-key", "my-apim", `<set-header name="x-key"><value>{{BackendKey}}</value></set-header>`),
+		policy("other-key", "my-apim", `<set-header name="x-key"><value>{{OtherKey}}</value></set-header>`),
+		policy("other-service", "other-apim", `<value>{{BackendKey}}</value>`),
+		templated,
+	).Build()
+	r := &APIMNamedValueReconciler{Client: c}
+	nv := &apimv1.APIMNamedValue{
+		ObjectMeta: metav1.ObjectMeta{Name: "backend-key", Namespace: "apim-system"},
+		Spec:       apimv1.APIMNamedValueSpec{APIMService: "my-apim"},
+	}
+
+	if err := r.signalDependentPolicies(context.Background(), "apim-system", nv, "BackendKey"); err != nil {
+		t.Fatalf("signalDependentPolicies() error = %v", err)
+	}
+	for name, want := range map[string]bool{"uses-key": true, "uses-key-in-template": true, "other-key": false, "other-service": false} {
+		var got apimv1.APIMInboundPolicy
+		if err := c.Get(context.Background(), client.ObjectKey{Name: name, Namespace: "apim-system"}, &got); err != nil {
+			t.Fatal(err)
+		}
+		if _, signaled := got.Annotations[namedValueChangedAnnotation]; signaled != want {
+			t.Errorf("policy %s signaled = %t, want %t", name, signaled, want)
+		}
+	}
+}
@@ -23,7 +23,9 @@ import (
 	"time"
 
 	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/event"
@@ -32,7 +34,9 @@ import (
 
 	apimv1 "github.com/hedinit/azure-apim-operator/api/v1"
 	"github.com/hedinit/azure-apim-operator/internal/apim"
+	"github.com/hedinit/azure-apim-operator/internal/cloudevents"
 	"github.com/hedinit/azure-apim-operator/internal/identity"
+	"github.com/hedinit/azure-apim-operator/internal/redact"
 )
 
 // APIMProductReconciler reconciles APIMProduct custom resources.
@@ -42,6 +46,13 @@ import (
 type APIMProductReconciler struct {
 	client.Client
 	Scheme *runtime.Scheme
+	// Recorder records Warning Events for failed syncs. Nil disables them.
+	Recorder record.EventRecorder
+	// APIM sends the Azure Management API requests. Nil sends them to the public endpoint.
+	APIM *apim.Client
+	// Tokens provides the Azure Management API token shared by all reconcilers. Nil asks Azure AD on
+	// every reconcile.
+	Tokens identity.TokenProvider
 }
 
 // +kubebuilder:rbac:groups=apim.operator.io,resources=apimproducts,verbs=get;list;watch;create;update;patch;delete
@@ -64,11 +75,13 @@ func (r *APIMProductReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 	if err := r.Get(ctx, req.NamespacedName, &product); err != nil {
 		if errors.IsNotFound(err) {
 			logger.Info("🧹 APIMProduct deleted, skipping", "name", req.NamespacedName)
+			forgetSyncMetrics(kindAPIMProduct, req.Namespace, req.Name)
 			return ctrl.Result{}, nil
 		}
 		logger.Error(err, "❌ Failed to get APIMProduct")
 		return ctrl.Result{}, err
 	}
+	setSpanAPIAttributes(ctx, product.Spec.APIMService, product.Spec.APIID)
 
 	operatorNamespace, err := getOperatorNamespace()
 	if err != nil {
@@ -81,6 +94,7 @@ func (r *APIMProductReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 		logger.Error(err, "❌ Failed to get APIMService", "name", product.Spec.APIMService)
 		return ctrl.Result{}, client.IgnoreNotFound(err)
 	}
+	observeAPIMService(ctx, &apimService)
 
 	logger.Info("🔗 Found APIMService", "name", apimService.Name)
 
@@ -92,72 +106,123 @@ func (r *APIMProductReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 		// Use Patch to update only status without touching spec fields.
 		statusPatch := client.MergeFrom(product.DeepCopy())
 		product.Status.Phase = phaseError
+		recordSyncFailure(kindAPIMProduct, &product)
 		product.Status.Message = "missing AZURE_CLIENT_ID or AZURE_TENANT_ID"
+		warnNotReady(r.Recorder, &product, &product.Status.Conditions, &product.Status.ObservedGeneration, apimv1.ReasonAuthFailed, product.Status.Message)
 		_ = r.Status().Patch(ctx, &product, statusPatch)
 		return ctrl.Result{RequeueAfter: 30 * time.Second}, nil
 	}
 
-	token, err := identity.GetManagementToken(ctx, clientID, tenantID)
+	token, err := managementToken(ctx, r.Tokens, clientID, tenantID)
 	if err != nil {
 		logger.Error(err, "❌ Failed to get Azure token")
 		// Use Patch to update only status without touching spec fields.
 		statusPatch := client.MergeFrom(product.DeepCopy())
 		product.Status.Phase = phaseError
+		recordSyncFailure(kindAPIMProduct, &product)
 		product.Status.Message = errMsgFailedToGetAzureToken
+		warnNotReady(r.Recorder, &product, &product.Status.Conditions, &product.Status.ObservedGeneration, apimv1.ReasonAuthFailed, failureMessage(product.Status.Message, err))
 		_ = r.Status().Patch(ctx, &product, statusPatch)
 		return ctrl.Result{RequeueAfter: 30 * time.Second}, nil
 	}
 
 	// 📦 Construct product config
 	cfg := apim.APIMProductConfig{
-		SubscriptionID: apimService.Spec.Subscription,
-		ResourceGroup:  apimService.Spec.ResourceGroup,
-		ServiceName:    product.Spec.APIMService,
-		ProductID:      product.Spec.ProductID,
-		DisplayName:    product.Spec.DisplayName,
-		Description:    product.Spec.Description,
-		Published:      product.Spec.Published,
-		BearerToken:    token,
+		SubscriptionID:   apimService.Spec.Subscription,
+		ResourceGroup:    apimService.Spec.ResourceGroup,
+		ServiceName:      product.Spec.APIMService,
+		ProductID:        product.Spec.ProductID,
+		DisplayName:      product.Spec.DisplayName,
+		Description:      product.Spec.Description,
+		Published:        product.Spec.Published,
+		ApprovalRequired: product.Spec.ApprovalRequired,
+		BearerToken:      token,
+		ETag:             product.Status.ETag,
+		OverwriteDrift:   overwritesDrift(&product),
 	}
 
 	// Check if the product is being deleted
 	if !product.DeletionTimestamp.IsZero() {
 		logger.Info("🗑️ APIMProduct is being deleted", "name", req.NamespacedName, "productId", cfg.ProductID)
-		if err := apim.DeleteProduct(ctx, cfg); err != nil {
+		if err := r.APIM.DeleteProduct(ctx, cfg); err != nil {
 			logger.Error(err, "❌ Failed to delete product in APIM", "productId", cfg.ProductID)
 			// Use Patch to update only status without touching spec fields.
 			statusPatch := client.MergeFrom(product.DeepCopy())
 			product.Status.Phase = phaseError
-			product.Status.Message = err.Error()
+			recordSyncFailure(kindAPIMProduct, &product)
+			product.Status.Message = redact.String(err.Error())
+			invalidateRejectedToken(r.Tokens, err)
+			warnNotReady(r.Recorder, &product, &product.Status.Conditions, &product.Status.ObservedGeneration, azureFailureReason(err), failureMessage("Failed to delete product in APIM", err))
 			if updateErr := r.Status().Patch(ctx, &product, statusPatch); updateErr != nil {
 				logger.Error(updateErr, "❌ Failed to patch APIMProduct status")
 			}
 			return ctrl.Result{RequeueAfter: 30 * time.Second}, nil
 		}
 		logger.Info("✅ Successfully deleted APIM product", "productId", cfg.ProductID)
+		if err := signalPortalContentChanged(ctx, r.Client, &apimService); err != nil {
+			logger.Error(err, "⚠️ Failed to signal developer portal publish", "productId", cfg.ProductID)
+		}
+		forgetSyncMetrics(kindAPIMProduct, product.Namespace, product.Name)
 		return ctrl.Result{}, nil
 	} else {
 		// Handle creation/update
-		if err := apim.UpsertProduct(ctx, cfg); err != nil {
+		upsertCtx := withDriftEvents(ctx, productEventSubject(cfg.ServiceName, cfg.ProductID), cloudevents.Data{
+			APIMService:     cfg.ServiceName,
+			Kind:            "APIMProduct",
+			Namespace:       product.Namespace,
+			Name:            product.Name,
+			ProductID:       cfg.ProductID,
+			AzureResourceID: product.Status.AzureResourceID,
+		})
+		etag, err := r.APIM.UpsertProduct(upsertCtx, cfg)
+		if err != nil {
 			logger.Error(err, "❌ Failed to create product in APIM", "productId", cfg.ProductID)
 			// Use Patch to update only status without touching spec fields.
 			statusPatch := client.MergeFrom(product.DeepCopy())
 			product.Status.Phase = phaseError
-			product.Status.Message = err.Error()
+			recordSyncFailure(kindAPIMProduct, &product)
+			product.Status.Message = redact.String(err.Error())
+			invalidateRejectedToken(r.Tokens, err)
+			warnNotReady(r.Recorder, &product, &product.Status.Conditions, &product.Status.ObservedGeneration, azureFailureReason(err), failureMessage("Failed to create product in APIM", err))
+			modifiedOutside := apim.IsPreconditionFailed(err)
+			if modifiedOutside {
+				// Forget the stale ETag so the next reconcile writes the declared state over the edit.
+				product.Status.ETag = ""
+			}
 			if updateErr := r.Status().Patch(ctx, &product, statusPatch); updateErr != nil {
 				logger.Error(updateErr, "❌ Failed to patch APIMProduct status")
 			}
+			if modifiedOutside {
+				return ctrl.Result{}, nil
+			}
 			return ctrl.Result{RequeueAfter: 30 * time.Second}, nil
 		}
 		logger.Info("✅ Successfully created APIM product", "productId", cfg.ProductID)
+		// Restarts re-sync every product; only a spec that wasn't synced yet changes the portal.
+		portalChanged := product.Status.ObservedGeneration != product.Generation
 		// Use Patch to update only status without touching spec fields.
 		statusPatch := client.MergeFrom(product.DeepCopy())
 		product.Status.Phase = phaseCreated
+		setReady(&product.Status.Conditions, &product.Status.ObservedGeneration, product.Generation, metav1.ConditionTrue, apimv1.ReasonSynced, "Product is synced to APIM")
+		recordSyncSuccess(kindAPIMProduct, &product)
 		product.Status.Message = "Product created successfully"
+		product.Status.AzureResourceID = apim.ProductResourceID(cfg.SubscriptionID, cfg.ResourceGroup, cfg.ServiceName, cfg.ProductID)
+		product.Status.TerraformImports = resourceTerraformImports("azurerm_api_management_product", "Microsoft.ApiManagement/service/products", product.Status.AzureResourceID)
+		product.Status.ETag = etag
+		if apim.IsDryRun() {
+			product.Status.Phase = phaseDryRun
+			product.Status.Message = "Dry-run: product changes were logged but not sent to APIM"
+			setReady(&product.Status.Conditions, &product.Status.ObservedGeneration, product.Generation, metav1.ConditionTrue, apimv1.ReasonSynced, product.Status.Message)
+		}
 		if err := r.Status().Patch(ctx, &product, statusPatch); err != nil {
 			logger.Error(err, "❌ Failed to patch APIMProduct status")
 			return ctrl.Result{}, err
 		}
+		if portalChanged {
+			if err := signalPortalContentChanged(ctx, r.Client, &apimService); err != nil {
+				logger.Error(err, "⚠️ Failed to signal developer portal publish", "productId", cfg.ProductID)
+			}
+		}
 		return ctrl.Result{}, nil
 	}
 }
@@ -168,10 +233,11 @@ func (r *APIMProductReconciler) SetupWithManager(mgr ctrl.Manager) error {
 		For(&apimv1.APIMProduct{}).
 		WithEventFilter(predicate.Funcs{
 			CreateFunc:  func(e event.CreateEvent) bool { return true },
-			UpdateFunc:  func(e event.UpdateEvent) bool { return false },
+			UpdateFunc:  func(e event.UpdateEvent) bool { return e.ObjectOld.GetGeneration() != e.ObjectNew.GetGeneration() },
 			DeleteFunc:  func(e event.DeleteEvent) bool { return true },
 			GenericFunc: func(e event.GenericEvent) bool { return false },
 		}).
+		WithEventFilter(shardPredicate()).
 		Named("apimproduct").
-		Complete(r)
+		Complete(withTracing("APIMProduct", r))
 }
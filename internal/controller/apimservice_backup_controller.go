@@ -0,0 +1,246 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"reflect"
+	"time"
+
+	"github.com/robfig/cron/v3"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	apimv1 "github.com/hedinit/azure-apim-operator/api/v1"
+	"github.com/hedinit/azure-apim-operator/internal/apim"
+	"github.com/hedinit/azure-apim-operator/internal/identity"
+)
+
+const (
+	// backupPollInterval is how often a running backup is checked. APIM takes minutes to an hour per backup.
+	backupPollInterval = time.Minute
+
+	// eventReasonBackupSucceeded is the Normal Event reason for a backup APIM completed.
+	eventReasonBackupSucceeded = "BackupSucceeded"
+	// eventReasonBackupFailed is the Warning Event reason for a backup that couldn't be started or failed in APIM.
+	eventReasonBackupFailed = "BackupFailed"
+	// eventReasonInvalidBackupSchedule is the Warning Event reason for a spec.backup.schedule that is no cron expression.
+	eventReasonInvalidBackupSchedule = "InvalidBackupSchedule"
+)
+
+// APIMServiceBackupReconciler backs up APIMServices with spec.backup to Azure Storage on their schedule and
+// tracks the backups in status.backup.
+type APIMServiceBackupReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+	// Recorder records the outcome of backups as Events. Nil disables them.
+	Recorder record.EventRecorder
+	// APIM sends the Azure Management API requests. Nil sends them to the public endpoint.
+	APIM *apim.Client
+	// Tokens provides the Azure Management API token shared by all reconcilers. Nil asks Azure AD on
+	// every reconcile.
+	Tokens identity.TokenProvider
+}
+
+// +kubebuilder:rbac:groups=apim.operator.io,resources=apimservices,verbs=get;list;watch
+// +kubebuilder:rbac:groups=apim.operator.io,resources=apimservices/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
+
+// Reconcile starts the backup of an APIMService when it is due and checks a running backup until APIM
+// completes it. A backup that is due while the previous one still runs is skipped.
+func (r *APIMServiceBackupReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	var svc apimv1.APIMService
+	if err := r.Get(ctx, req.NamespacedName, &svc); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	if !svc.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, nil
+	}
+	setSpanAPIAttributes(ctx, svc.Name, "")
+	statusPatch := client.MergeFrom(svc.DeepCopy())
+
+	if svc.Spec.Backup == nil {
+		if svc.Status.Backup == nil {
+			return ctrl.Result{}, nil
+		}
+		svc.Status.Backup = nil
+		return ctrl.Result{}, r.Status().Patch(ctx, &svc, statusPatch)
+	}
+	// The APIMService controller reports a tier without backups on the Ready condition.
+	if !backupSupported(&svc) {
+		return ctrl.Result{}, nil
+	}
+
+	status := &apimv1.APIMServiceBackupStatus{}
+	if svc.Status.Backup != nil {
+		status = svc.Status.Backup.DeepCopy()
+	}
+	schedule, err := cron.ParseStandard(svc.Spec.Backup.Schedule)
+	if err != nil {
+		logger.Error(err, "❌ Invalid backup schedule", "schedule", svc.Spec.Backup.Schedule)
+		message := fmt.Sprintf("Invalid backup schedule %q: %v", svc.Spec.Backup.Schedule, err)
+		recordWarningEvent(r.Recorder, &svc, eventReasonInvalidBackupSchedule, message)
+		status.Schedule, status.NextBackupAt, status.LastError = svc.Spec.Backup.Schedule, "", message
+		return ctrl.Result{}, r.patchBackupStatus(ctx, &svc, status, statusPatch)
+	}
+
+	now := time.Now().UTC()
+	next := nextBackupTime(status, svc.Spec.Backup.Schedule, schedule, now)
+	if status.InProgress != nil || !now.Before(next) {
+		clientID := os.Getenv("AZURE_CLIENT_ID")
+		tenantID := os.Getenv("AZURE_TENANT_ID")
+		if clientID == "" || tenantID == "" {
+			logger.Error(fmt.Errorf("missing identity env vars"), "❌ AZURE_CLIENT_ID or AZURE_TENANT_ID not set")
+			return ctrl.Result{RequeueAfter: backupPollInterval}, nil
+		}
+		token, err := managementToken(ctx, r.Tokens, clientID, tenantID)
+		if err != nil {
+			logger.Error(err, "❌ Failed to get Azure token")
+			return ctrl.Result{RequeueAfter: backupPollInterval}, nil
+		}
+
+		if status.InProgress != nil {
+			r.checkBackup(ctx, &svc, status, token)
+		}
+		if !now.Before(next) {
+			if status.InProgress != nil {
+				logger.Info("⏭️ Backup skipped, the previous one still runs", "backupName", status.InProgress.Name)
+			} else {
+				r.startBackup(ctx, &svc, status, token, now)
+			}
+			next = schedule.Next(now)
+		}
+	}
+
+	status.Schedule = svc.Spec.Backup.Schedule
+	status.NextBackupAt = next.Format(time.RFC3339)
+	if err := r.patchBackupStatus(ctx, &svc, status, statusPatch); err != nil {
+		return ctrl.Result{}, err
+	}
+	requeueAfter := next.Sub(now)
+	if status.InProgress != nil {
+		requeueAfter = min(requeueAfter, backupPollInterval)
+	}
+	return ctrl.Result{RequeueAfter: requeueAfter}, nil
+}
+
+// nextBackupTime returns the time of the next backup: the one in status if it was computed from the
+// current schedule, or else the next time of schedule after now.
+func nextBackupTime(status *apimv1.APIMServiceBackupStatus, specSchedule string, schedule cron.Schedule, now time.Time) time.Time {
+	if status.Schedule == specSchedule {
+		if next, err := time.Parse(time.RFC3339, status.NextBackupAt); err == nil {
+			return next
+		}
+	}
+	return schedule.Next(now)
+}
+
+// startBackup starts a backup of svc and records it in status.
+func (r *APIMServiceBackupReconciler) startBackup(ctx context.Context, svc *apimv1.APIMService, status *apimv1.APIMServiceBackupStatus, token string, now time.Time) {
+	logger := log.FromContext(ctx)
+
+	name := fmt.Sprintf("%s-%s.apimbackup", svc.Name, now.Format("20060102150405"))
+	if apim.IsDryRun() {
+		logger.Info("🧪 Dry-run: backup not started", "backupName", name)
+		return
+	}
+	operationURL, err := r.APIM.StartBackup(ctx, apim.APIMBackupConfig{
+		SubscriptionID:   svc.Spec.Subscription,
+		ResourceGroup:    svc.Spec.ResourceGroup,
+		ServiceName:      svc.Name,
+		BearerToken:      token,
+		StorageAccount:   svc.Spec.Backup.StorageAccount,
+		Container:        svc.Spec.Backup.Container,
+		BackupName:       name,
+		IdentityClientID: svc.Spec.Backup.IdentityClientID,
+	})
+	if err != nil {
+		logger.Error(err, "❌ Failed to start backup", "backupName", name)
+		invalidateRejectedToken(r.Tokens, err)
+		status.LastError = failureMessage(fmt.Sprintf("Failed to start backup %s", name), err)
+		recordWarningEvent(r.Recorder, svc, eventReasonBackupFailed, status.LastError)
+		return
+	}
+	operation := apimv1.APIMServiceBackupOperation{Name: name, StartedAt: now.Format(time.RFC3339), OperationURL: operationURL}
+	if operationURL == "" {
+		r.backupSucceeded(svc, status, operation)
+		return
+	}
+	status.InProgress = &operation
+}
+
+// checkBackup reads the state of the running backup in status and records its outcome once APIM completed
+// it. A backup whose state can't be read is checked again on the next poll.
+func (r *APIMServiceBackupReconciler) checkBackup(ctx context.Context, svc *apimv1.APIMService, status *apimv1.APIMServiceBackupStatus, token string) {
+	logger := log.FromContext(ctx)
+	operation := *status.InProgress
+
+	state, err := r.APIM.GetBackupState(ctx, token, operation.OperationURL)
+	var respErr *apim.ResponseError
+	if state == "" && errors.As(err, &respErr) && respErr.StatusCode == http.StatusNotFound {
+		// Azure keeps operation results for a limited time only.
+		state = apim.BackupFailed
+		err = fmt.Errorf("the backup operation no longer exists")
+	}
+	switch state {
+	case apim.BackupSucceeded:
+		status.InProgress = nil
+		r.backupSucceeded(svc, status, operation)
+	case apim.BackupFailed:
+		logger.Error(err, "❌ Backup failed", "backupName", operation.Name)
+		invalidateRejectedToken(r.Tokens, err)
+		status.InProgress = nil
+		status.LastError = failureMessage(fmt.Sprintf("Backup %s failed", operation.Name), err)
+		recordWarningEvent(r.Recorder, svc, eventReasonBackupFailed, status.LastError)
+	case apim.BackupInProgress:
+		logger.Info("⏳ Backup still running", "backupName", operation.Name)
+	default:
+		logger.Error(err, "⚠️ Failed to read backup state", "backupName", operation.Name)
+	}
+}
+
+// backupSucceeded records the completed backup operation in status.
+func (r *APIMServiceBackupReconciler) backupSucceeded(svc *apimv1.APIMService, status *apimv1.APIMServiceBackupStatus, operation apimv1.APIMServiceBackupOperation) {
+	status.LastSuccessfulBackupAt = operation.StartedAt
+	status.LastSuccessfulBackupName = operation.Name
+	status.LastError = ""
+	recordEvent(r.Recorder, svc, corev1.EventTypeNormal, eventReasonBackupSucceeded,
+		fmt.Sprintf("Backup %s written to %s/%s", operation.Name, svc.Spec.Backup.StorageAccount, svc.Spec.Backup.Container))
+}
+
+// patchBackupStatus sets status.backup of svc to status, unless it is unchanged.
+func (r *APIMServiceBackupReconciler) patchBackupStatus(ctx context.Context, svc *apimv1.APIMService, status *apimv1.APIMServiceBackupStatus, statusPatch client.Patch) error {
+	if reflect.DeepEqual(svc.Status.Backup, status) {
+		return nil
+	}
+	svc.Status.Backup = status
+	if err := r.Status().Patch(ctx, svc, statusPatch); err != nil {
+		return fmt.Errorf("patch backup status: %w", err)
+	}
+	return nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *APIMServiceBackupReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&apimv1.APIMService{}).
+		WithEventFilter(predicate.Funcs{
+			CreateFunc:  func(e event.CreateEvent) bool { return true },
+			UpdateFunc:  func(e event.UpdateEvent) bool { return e.ObjectOld.GetGeneration() != e.ObjectNew.GetGeneration() },
+			DeleteFunc:  func(e event.DeleteEvent) bool { return false },
+			GenericFunc: func(e event.GenericEvent) bool { return false },
+		}).
+		WithEventFilter(shardPredicate()).
+		Named("apimservicebackup").
+		Complete(withTracing("APIMServiceBackup", r))
+}
@@ -0,0 +1,154 @@
+package controller
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/robfig/cron/v3"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	apimv1 "github.com/hedinit/azure-apim-operator/api/v1"
+	"github.com/hedinit/azure-apim-operator/internal/apimtest"
+	"github.com/hedinit/azure-apim-operator/internal/identity"
+)
+
+func TestNextBackupTime(t *testing.T) {
+	schedule, err := cron.ParseStandard("0 2 * * *")
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	tomorrow := time.Date(2026, 3, 2, 2, 0, 0, 0, time.UTC)
+	stored := &apimv1.APIMServiceBackupStatus{Schedule: "0 2 * * *", NextBackupAt: "2026-03-01T02:00:00Z"}
+
+	if got := nextBackupTime(stored, "0 2 * * *", schedule, now); !got.Equal(time.Date(2026, 3, 1, 2, 0, 0, 0, time.UTC)) {
+		t.Errorf("nextBackupTime() = %v, want the stored time of the same schedule", got)
+	}
+	if got := nextBackupTime(stored, "0 3 * * *", schedule, now); !got.Equal(tomorrow) {
+		t.Errorf("nextBackupTime() = %v, want %v for a changed schedule", got, tomorrow)
+	}
+	if got := nextBackupTime(&apimv1.APIMServiceBackupStatus{}, "0 2 * * *", schedule, now); !got.Equal(tomorrow) {
+		t.Errorf("nextBackupTime() = %v, want %v without a stored time", got, tomorrow)
+	}
+}
+
+func TestAPIMServiceBackupReconcile(t *testing.T) {
+	fakeAPIM := apimtest.Start(t)
+	t.Setenv("AZURE_CLIENT_ID", "test-client-id")
+	t.Setenv("AZURE_TENANT_ID", "test-tenant-id")
+	identity.SetStaticToken("test-token")
+	t.Cleanup(func() { identity.SetStaticToken("") })
+
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	if err := apimv1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	service := &apimv1.APIMService{
+		ObjectMeta: metav1.ObjectMeta{Name: "apim-test", Namespace: "default"},
+		Spec: apimv1.APIMServiceSpec{
+			Subscription:  "sub",
+			ResourceGroup: "rg",
+			Backup:        &apimv1.APIMServiceBackup{StorageAccount: "backups", Container: "apim", Schedule: "0 2 * * *"},
+		},
+		Status: apimv1.APIMServiceStatus{
+			Backup: &apimv1.APIMServiceBackupStatus{Schedule: "0 2 * * *", NextBackupAt: time.Now().Add(-time.Minute).UTC().Format(time.RFC3339)},
+		},
+	}
+	recorder := record.NewFakeRecorder(10)
+	r := &APIMServiceBackupReconciler{
+		APIM:     fakeAPIM.Client(),
+		Client:   fake.NewClientBuilder().WithScheme(scheme).WithObjects(service).WithStatusSubresource(service).Build(),
+		Scheme:   scheme,
+		Recorder: recorder,
+	}
+	key := types.NamespacedName{Name: "apim-test", Namespace: "default"}
+	reconcile := func() (*apimv1.APIMServiceBackupStatus, ctrl.Result) {
+		t.Helper()
+		result, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: key})
+		if err != nil {
+			t.Fatalf("Reconcile() error = %v", err)
+		}
+		var got apimv1.APIMService
+		if err := r.Get(context.Background(), key, &got); err != nil {
+			t.Fatal(err)
+		}
+		return got.Status.Backup, result
+	}
+
+	status, result := reconcile()
+	if status == nil || status.InProgress == nil || !strings.HasPrefix(status.InProgress.Name, "apim-test-") {
+		t.Fatalf("status.backup = %+v, want a backup in progress", status)
+	}
+	if result.RequeueAfter != backupPollInterval {
+		t.Errorf("RequeueAfter = %v, want %v while the backup runs", result.RequeueAfter, backupPollInterval)
+	}
+	if next, err := time.Parse(time.RFC3339, status.NextBackupAt); err != nil || !next.After(time.Now()) {
+		t.Errorf("nextBackupAt = %q, want the next run of the schedule", status.NextBackupAt)
+	}
+	name := status.InProgress.Name
+
+	status, result = reconcile()
+	if status.InProgress != nil || status.LastSuccessfulBackupName != name || status.LastSuccessfulBackupAt == "" || status.LastError != "" {
+		t.Errorf("status.backup = %+v, want the successful backup %s", status, name)
+	}
+	if result.RequeueAfter <= backupPollInterval {
+		t.Errorf("RequeueAfter = %v, want the time until the next backup", result.RequeueAfter)
+	}
+	if got := <-recorder.Events; !strings.HasPrefix(got, "Normal BackupSucceeded") || !strings.Contains(got, "backups/apim") {
+		t.Errorf("event = %q, want the successful backup", got)
+	}
+	backups := 0
+	for _, req := range fakeAPIM.Requests() {
+		if req.Method == "POST" && strings.HasSuffix(req.Path, "/service/apim-test/backup") {
+			backups++
+		}
+	}
+	if backups != 1 {
+		t.Errorf("started %d backups, want 1", backups)
+	}
+}
+
+func TestAPIMServiceBackupInvalidSchedule(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := apimv1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	service := &apimv1.APIMService{
+		ObjectMeta: metav1.ObjectMeta{Name: "apim-test", Namespace: "default"},
+		Spec: apimv1.APIMServiceSpec{
+			Backup: &apimv1.APIMServiceBackup{StorageAccount: "backups", Container: "apim", Schedule: "every night"},
+		},
+	}
+	recorder := record.NewFakeRecorder(10)
+	r := &APIMServiceBackupReconciler{
+		Client:   fake.NewClientBuilder().WithScheme(scheme).WithObjects(service).WithStatusSubresource(service).Build(),
+		Scheme:   scheme,
+		Recorder: recorder,
+	}
+	key := types.NamespacedName{Name: "apim-test", Namespace: "default"}
+	result, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: key})
+	if err != nil || result.RequeueAfter != 0 {
+		t.Fatalf("Reconcile() = %+v, %v, want no requeue", result, err)
+	}
+	if got := <-recorder.Events; !strings.HasPrefix(got, "Warning InvalidBackupSchedule") {
+		t.Errorf("event = %q, want the invalid schedule", got)
+	}
+	var got apimv1.APIMService
+	if err := r.Get(context.Background(), key, &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.Status.Backup == nil || !strings.Contains(got.Status.Backup.LastError, "every night") {
+		t.Errorf("status.backup = %+v, want the invalid schedule as lastError", got.Status.Backup)
+	}
+}
@@ -18,53 +18,142 @@ package controller
 
 import (
 	"context"
+	"fmt"
+	"os"
+	"time"
 
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	apimv1 "github.com/hedinit/azure-apim-operator/api/v1"
+	"github.com/hedinit/azure-apim-operator/internal/apim"
+	"github.com/hedinit/azure-apim-operator/internal/identity"
 )
 
 // APIMServiceReconciler reconciles a APIMService object
 type APIMServiceReconciler struct {
 	client.Client
 	Scheme *runtime.Scheme
+	// Recorder records Warning Events when the hostnames cannot be read. Nil disables them.
+	Recorder record.EventRecorder
+	// APIM sends the Azure Management API requests. Nil sends them to the public endpoint.
+	APIM *apim.Client
+	// Tokens provides the Azure Management API token shared by all reconcilers. Nil asks Azure AD on
+	// every reconcile.
+	Tokens identity.TokenProvider
 }
 
 // +kubebuilder:rbac:groups=apim.operator.io,resources=apimservices,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=apim.operator.io,resources=apimservices/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=apim.operator.io,resources=apimservices/finalizers,verbs=update
 
-// Reconcile is part of the main kubernetes reconciliation loop which aims to
-// move the current state of the cluster closer to the desired state.
-// TODO(user): Modify the Reconcile function to compare the state specified by
-// the APIMService object against the actual cluster state, and then
-// perform operations to make the cluster state reflect the state specified by
-// the user.
+// Reconcile reads the gateway and developer portal hostnames and the pricing tier of the APIM service from
+// Azure into its status, and refreshes them every resync interval (see SetResyncInterval). The
+// APIMAPIDeployment controller builds API URLs from the hostnames, so a rollout doesn't have to ask Azure for
+// them again, and other controllers consult the capabilities of the tier before they use a feature it lacks.
+// With portal publishing configured, it also publishes the developer portal once the debounce window after
+// the last content change has passed.
 //
 // For more details, check Reconcile and its Result here:
 // - https://pkg.go.dev/sigs.k8s.io/controller-runtime@v0.20.4/pkg/reconcile
 func (r *APIMServiceReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
-	// var logger = ctrl.Log.WithName("apimservice_controller")
+	logger := ctrl.Log.WithName("apimservice_controller")
 
-	// logger.Info("🔍 Reconciling APIMService", "name", req.Name, "namespace", req.Namespace)
+	var svc apimv1.APIMService
+	if err := r.Get(ctx, req.NamespacedName, &svc); err != nil {
+		logger.Info("ℹ️ Failed to fetch APIMService", "name", req.NamespacedName)
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	observeAPIMService(ctx, &svc)
 
-	// var svc apimv1.APIMService
-	// if err := r.Get(ctx, req.NamespacedName, &svc); err != nil {
-	// 	logger.Info("ℹ️ Failed to fetch APIMService", "name", req.NamespacedName)
-	// 	return ctrl.Result{}, client.IgnoreNotFound(err)
-	// }
+	now := time.Now()
+	hostWait := hostRefreshRemaining(&svc, now)
+	publishWait, publishPending := portalPublishRemaining(&svc, now)
+	publishDue := publishPending && publishWait == 0
+	if hostWait > 0 && !publishDue {
+		if publishPending {
+			return ctrl.Result{RequeueAfter: min(hostWait, publishWait)}, nil
+		}
+		return ctrl.Result{RequeueAfter: hostWait}, nil
+	}
 
-	// logger.Info("✅ APIMService resource reconciled", "name", svc.Name, "namespace", svc.Namespace)
+	clientID := os.Getenv("AZURE_CLIENT_ID")
+	tenantID := os.Getenv("AZURE_TENANT_ID")
+	if clientID == "" || tenantID == "" {
+		logger.Error(fmt.Errorf("missing identity env vars"), "❌ AZURE_CLIENT_ID or AZURE_TENANT_ID not set", "name", svc.Name)
+		r.markNotReady(ctx, &svc, apimv1.ReasonAuthFailed, "missing AZURE_CLIENT_ID or AZURE_TENANT_ID")
+		return ctrl.Result{RequeueAfter: 30 * time.Second}, nil
+	}
+	token, err := managementToken(ctx, r.Tokens, clientID, tenantID)
+	if err != nil {
+		logger.Error(err, "❌ Failed to get Azure token", "name", svc.Name)
+		r.markNotReady(ctx, &svc, apimv1.ReasonAuthFailed, failureMessage(errMsgFailedToGetAzureToken, err))
+		return ctrl.Result{RequeueAfter: 30 * time.Second}, nil
+	}
 
-	return ctrl.Result{}, nil
+	if publishDue {
+		if err := r.publishPortal(ctx, &svc, token, now); err != nil {
+			logger.Error(err, "⚠️ Failed to publish developer portal", "name", svc.Name)
+			return ctrl.Result{RequeueAfter: 60 * time.Second}, nil
+		}
+		if hostWait > 0 {
+			return ctrl.Result{RequeueAfter: hostWait}, nil
+		}
+	}
+
+	// Like every other APIM call, the service is addressed by the name of the APIMService resource.
+	details, err := r.APIM.GetAPIMService(ctx, apim.APIMDeploymentConfig{
+		SubscriptionID: svc.Spec.Subscription,
+		ResourceGroup:  svc.Spec.ResourceGroup,
+		ServiceName:    svc.Name,
+		BearerToken:    token,
+	})
+	if err != nil {
+		logger.Error(err, "⚠️ Failed to fetch APIM details", "name", svc.Name)
+		invalidateRejectedToken(r.Tokens, err)
+		r.markNotReady(ctx, &svc, azureFailureReason(err), failureMessage("Failed to fetch APIM service details", err))
+		return ctrl.Result{RequeueAfter: 60 * time.Second}, nil
+	}
+
+	statusPatch := client.MergeFrom(svc.DeepCopy())
+	svc.Status.Host = details.Host
+	svc.Status.DeveloperPortalHost = details.DeveloperPortalHost
+	svc.Status.HostsRefreshedAt = time.Now().UTC().Format(time.RFC3339)
+	setSKU(&svc, details.SKU)
+	if message := unsupportedServiceFeatures(&svc); message != "" {
+		// The hostnames are recorded anyway, so APIs can still be deployed to the service.
+		warnNotReady(r.Recorder, &svc, &svc.Status.Conditions, &svc.Status.ObservedGeneration, apimv1.ReasonNotSupportedOnSKU, message)
+	} else {
+		setReady(&svc.Status.Conditions, &svc.Status.ObservedGeneration, svc.Generation, metav1.ConditionTrue, apimv1.ReasonSynced, "Hostnames were read from Azure")
+	}
+	if err := r.Status().Patch(ctx, &svc, statusPatch); err != nil {
+		logger.Error(err, "❌ Failed to patch APIMService status", "name", svc.Name)
+		return ctrl.Result{}, err
+	}
+	logger.Info("✅ APIM service hostnames refreshed", "name", svc.Name, "host", details.Host,
+		"developerPortalHost", details.DeveloperPortalHost, "sku", details.SKU)
+
+	return ctrl.Result{RequeueAfter: hostRefreshInterval()}, nil
+}
+
+// markNotReady sets the Ready condition of svc to False, records a Warning Event and patches the status.
+// The hostnames read earlier are kept, so APIs can still be deployed with them.
+func (r *APIMServiceReconciler) markNotReady(ctx context.Context, svc *apimv1.APIMService, reason, message string) {
+	statusPatch := client.MergeFrom(svc.DeepCopy())
+	warnNotReady(r.Recorder, svc, &svc.Status.Conditions, &svc.Status.ObservedGeneration, reason, message)
+	if err := r.Status().Patch(ctx, svc, statusPatch); err != nil {
+		ctrl.Log.WithName("apimservice_controller").Error(err, "⚠️ Failed to patch APIMService status", "name", svc.Name)
+	}
 }
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *APIMServiceReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&apimv1.APIMService{}).
+		WithEventFilter(shardPredicate()).
 		Named("apimservice").
-		Complete(r)
+		Complete(withTracing("APIMService", r))
 }
@@ -0,0 +1,64 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	apimv1 "github.com/hedinit/azure-apim-operator/api/v1"
+	"github.com/hedinit/azure-apim-operator/internal/apim"
+)
+
+// DefaultResyncInterval is how often the hostnames in APIMService status are read from Azure again, so custom
+// domains added later reach the APIMAPI statuses without an operator restart, unless SetResyncInterval sets
+// another interval.
+const DefaultResyncInterval = time.Hour
+
+// resyncIntervalNanos holds the interval set with SetResyncInterval, or zero for the default.
+var resyncIntervalNanos atomic.Int64
+
+// SetResyncInterval sets how often APIMServices read their hostnames from Azure again. Zero restores
+// DefaultResyncInterval. It can be changed while the manager runs; a new interval applies from the next
+// reconcile of each APIMService.
+func SetResyncInterval(interval time.Duration) error {
+	if interval < 0 {
+		return fmt.Errorf("resync interval must not be negative, got %s", interval)
+	}
+	resyncIntervalNanos.Store(int64(interval))
+	return nil
+}
+
+// hostRefreshInterval returns the interval set with SetResyncInterval.
+func hostRefreshInterval() time.Duration {
+	if interval := time.Duration(resyncIntervalNanos.Load()); interval > 0 {
+		return interval
+	}
+	return DefaultResyncInterval
+}
+
+// hostRefreshRemaining returns how long the hostnames in the status of svc stay fresh, or zero if they
+// have to be read from Azure now, as after a spec change.
+func hostRefreshRemaining(svc *apimv1.APIMService, now time.Time) time.Duration {
+	if svc.Status.Host == "" || svc.Status.ObservedGeneration != svc.Generation {
+		return 0
+	}
+	refreshedAt, err := time.Parse(time.RFC3339, svc.Status.HostsRefreshedAt)
+	if err != nil {
+		return 0
+	}
+	if remaining := refreshedAt.Add(hostRefreshInterval()).Sub(now); remaining > 0 {
+		return remaining
+	}
+	return 0
+}
+
+// resolveAPIMServiceHosts returns the gateway and developer portal hostnames of an APIM service. It uses the
+// hostnames cached in the APIMService status and only asks Azure while the APIMService controller has not
+// filled them in yet.
+func resolveAPIMServiceHosts(ctx context.Context, c *apim.Client, svc *apimv1.APIMService, config apim.APIMDeploymentConfig) (string, string, error) {
+	if svc.Status.Host != "" {
+		return svc.Status.Host, svc.Status.DeveloperPortalHost, nil
+	}
+	return c.GetAPIMServiceDetails(ctx, config)
+}
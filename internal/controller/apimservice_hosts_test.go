@@ -0,0 +1,50 @@
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	apimv1 "github.com/hedinit/azure-apim-operator/api/v1"
+	"github.com/hedinit/azure-apim-operator/internal/apim"
+)
+
+func TestHostRefreshRemaining(t *testing.T) {
+	now := time.Date(2026, 1, 12, 10, 0, 0, 0, time.UTC)
+	svc := &apimv1.APIMService{Status: apimv1.APIMServiceStatus{
+		Host:             "contoso.azure-api.net",
+		HostsRefreshedAt: now.Add(-20 * time.Minute).Format(time.RFC3339),
+	}}
+	if got := hostRefreshRemaining(svc, now); got != 40*time.Minute {
+		t.Errorf("hostRefreshRemaining() = %s, want 40m", got)
+	}
+	if got := hostRefreshRemaining(svc, now.Add(time.Hour)); got != 0 {
+		t.Errorf("hostRefreshRemaining() after the interval = %s, want 0", got)
+	}
+
+	svc.Generation = 2
+	if got := hostRefreshRemaining(svc, now); got != 0 {
+		t.Errorf("hostRefreshRemaining() after a spec change = %s, want 0", got)
+	}
+
+	svc.Status.ObservedGeneration = 2
+	svc.Status.Host = ""
+	if got := hostRefreshRemaining(svc, now); got != 0 {
+		t.Errorf("hostRefreshRemaining() without hostnames = %s, want 0", got)
+	}
+}
+
+func TestResolveAPIMServiceHostsUsesStatus(t *testing.T) {
+	svc := &apimv1.APIMService{Status: apimv1.APIMServiceStatus{
+		Host:                "contoso.azure-api.net",
+		DeveloperPortalHost: "contoso.developer.azure-api.net",
+	}}
+	// The config carries no token: a call to Azure would fail.
+	apiHost, portalHost, err := resolveAPIMServiceHosts(context.Background(), nil, svc, apim.APIMDeploymentConfig{})
+	if err != nil {
+		t.Fatalf("resolveAPIMServiceHosts() error = %v", err)
+	}
+	if apiHost != svc.Status.Host || portalHost != svc.Status.DeveloperPortalHost {
+		t.Errorf("resolveAPIMServiceHosts() = %q, %q, want the hostnames from the status", apiHost, portalHost)
+	}
+}
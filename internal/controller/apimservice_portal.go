@@ -0,0 +1,101 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apimv1 "github.com/hedinit/azure-apim-operator/api/v1"
+	"github.com/hedinit/azure-apim-operator/internal/apim"
+)
+
+const (
+	// portalContentChangedAnnotation records on an APIMService when the operator last changed APIs or
+	// products of the service. Every change refreshes it, which restarts the publish debounce window.
+	portalContentChangedAnnotation = "apim.operator.io/portal-content-changed"
+
+	// defaultPortalPublishDebounce applies when portalPublishing sets no debounce.
+	defaultPortalPublishDebounce = 5 * time.Minute
+
+	// eventReasonPortalPublishFailed is the Warning Event reason for a failed developer portal publish.
+	eventReasonPortalPublishFailed = "PortalPublishFailed"
+)
+
+// signalPortalContentChanged marks the developer portal content of svc as changed, so the APIMService
+// controller publishes the portal once the debounce window has passed. It does nothing unless svc
+// configures portal publishing on a tier with a developer portal, or in dry-run mode, where APIM content
+// never changes.
+func signalPortalContentChanged(ctx context.Context, c client.Client, svc *apimv1.APIMService) error {
+	if svc.Spec.PortalPublishing == nil || !developerPortalSupported(svc) || apim.IsDryRun() {
+		return nil
+	}
+	updated := svc.DeepCopy()
+	if updated.Annotations == nil {
+		updated.Annotations = map[string]string{}
+	}
+	updated.Annotations[portalContentChangedAnnotation] = time.Now().UTC().Format(time.RFC3339Nano)
+	if equality.Semantic.DeepEqual(svc.Annotations, updated.Annotations) {
+		return nil
+	}
+	if err := c.Patch(ctx, updated, client.MergeFrom(svc)); err != nil {
+		return err
+	}
+	svc.Annotations = updated.Annotations
+	return nil
+}
+
+// portalPublishRemaining reports whether svc has content changes that were not published yet and how
+// long to wait before publishing them. A zero wait with pending set means the portal is due now. Tiers
+// without a developer portal never have changes to publish.
+func portalPublishRemaining(svc *apimv1.APIMService, now time.Time) (wait time.Duration, pending bool) {
+	if svc.Spec.PortalPublishing == nil || !developerPortalSupported(svc) {
+		return 0, false
+	}
+	changedAt, err := time.Parse(time.RFC3339Nano, svc.Annotations[portalContentChangedAnnotation])
+	if err != nil {
+		return 0, false
+	}
+	if publishedAt, err := time.Parse(time.RFC3339Nano, svc.Status.PortalPublishedAt); err == nil && !changedAt.After(publishedAt) {
+		return 0, false
+	}
+
+	window := defaultPortalPublishDebounce
+	if debounce := svc.Spec.PortalPublishing.Debounce; debounce != nil {
+		window = debounce.Duration
+	}
+	remaining := changedAt.Add(window).Sub(now)
+	if remaining <= 0 {
+		return 0, true
+	}
+	// Signals written by a replica with a skewed clock wait at most one window.
+	return min(remaining, window), true
+}
+
+// publishPortal publishes the developer portal of svc and records the publish in its status.
+// The publish time is taken before the call, so a change signaled while publishing is published again.
+func (r *APIMServiceReconciler) publishPortal(ctx context.Context, svc *apimv1.APIMService, token string, now time.Time) error {
+	revisionID := now.UTC().Format("20060102150405")
+	err := r.APIM.PublishDeveloperPortal(ctx, apim.APIMDeploymentConfig{
+		SubscriptionID: svc.Spec.Subscription,
+		ResourceGroup:  svc.Spec.ResourceGroup,
+		ServiceName:    svc.Name,
+		BearerToken:    token,
+	}, revisionID, "Published by azure-apim-operator after API or product changes")
+	if err != nil {
+		recordWarningEvent(r.Recorder, svc, eventReasonPortalPublishFailed, failureMessage("Failed to publish developer portal", err))
+		return err
+	}
+
+	statusPatch := client.MergeFrom(svc.DeepCopy())
+	svc.Status.PortalPublishedAt = now.UTC().Format(time.RFC3339Nano)
+	svc.Status.PortalRevision = revisionID
+	if err := r.Status().Patch(ctx, svc, statusPatch); err != nil {
+		return fmt.Errorf("record portal publish: %w", err)
+	}
+	ctrl.Log.WithName("apimservice_controller").Info("📰 Developer portal published", "name", svc.Name, "revision", revisionID)
+	return nil
+}
@@ -0,0 +1,74 @@
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	apimv1 "github.com/hedinit/azure-apim-operator/api/v1"
+)
+
+func TestPortalPublishRemaining(t *testing.T) {
+	now := time.Date(2026, 1, 12, 10, 0, 0, 0, time.UTC)
+	svc := &apimv1.APIMService{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{
+		portalContentChangedAnnotation: now.Add(-2 * time.Minute).Format(time.RFC3339Nano),
+	}}}
+	if _, pending := portalPublishRemaining(svc, now); pending {
+		t.Error("portalPublishRemaining() pending without portalPublishing")
+	}
+
+	svc.Spec.PortalPublishing = &apimv1.APIMServicePortalPublishing{}
+	if wait, pending := portalPublishRemaining(svc, now); !pending || wait != 3*time.Minute {
+		t.Errorf("portalPublishRemaining() = %s, %t, want 3m of the default window", wait, pending)
+	}
+
+	svc.Spec.PortalPublishing.Debounce = &metav1.Duration{Duration: time.Minute}
+	if wait, pending := portalPublishRemaining(svc, now); !pending || wait != 0 {
+		t.Errorf("portalPublishRemaining() after the window = %s, %t, want due now", wait, pending)
+	}
+
+	svc.Status.PortalPublishedAt = now.Add(-time.Minute).Format(time.RFC3339Nano)
+	if _, pending := portalPublishRemaining(svc, now); pending {
+		t.Error("portalPublishRemaining() pending for a change that was already published")
+	}
+
+	// A clock-skewed signal from the future waits at most one window.
+	svc.Annotations[portalContentChangedAnnotation] = now.Add(time.Hour).Format(time.RFC3339Nano)
+	if wait, _ := portalPublishRemaining(svc, now); wait != time.Minute {
+		t.Errorf("portalPublishRemaining() for a future signal = %s, want 1m", wait)
+	}
+}
+
+func TestSignalPortalContentChanged(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := apimv1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	svc := &apimv1.APIMService{ObjectMeta: metav1.ObjectMeta{Name: "apim-prod", Namespace: "azure-apim-operator-system"}}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(svc).Build()
+
+	if err := signalPortalContentChanged(ctx, c, svc); err != nil {
+		t.Fatalf("signalPortalContentChanged() error = %v", err)
+	}
+	if _, ok := svc.Annotations[portalContentChangedAnnotation]; ok {
+		t.Error("signalPortalContentChanged() annotated a service without portalPublishing")
+	}
+
+	svc.Spec.PortalPublishing = &apimv1.APIMServicePortalPublishing{}
+	if err := signalPortalContentChanged(ctx, c, svc); err != nil {
+		t.Fatalf("signalPortalContentChanged() error = %v", err)
+	}
+	var stored apimv1.APIMService
+	if err := c.Get(ctx, client.ObjectKeyFromObject(svc), &stored); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := time.Parse(time.RFC3339Nano, stored.Annotations[portalContentChangedAnnotation]); err != nil {
+		t.Errorf("stored %s = %q, want a timestamp", portalContentChangedAnnotation, stored.Annotations[portalContentChangedAnnotation])
+	}
+}
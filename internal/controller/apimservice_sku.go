@@ -0,0 +1,69 @@
+package controller
+
+import (
+	"fmt"
+	"strings"
+
+	apimv1 "github.com/hedinit/azure-apim-operator/api/v1"
+	"github.com/hedinit/azure-apim-operator/internal/apim"
+)
+
+// setSKU records the pricing tier of svc and the capabilities of the operator on it in its status. An empty
+// sku, as from an older fake or a response without it, leaves the capabilities unknown.
+func setSKU(svc *apimv1.APIMService, sku string) {
+	svc.Status.SKU = sku
+	if sku == "" {
+		svc.Status.Capabilities = nil
+		return
+	}
+	capabilities := apim.SKUCapabilities(sku)
+	svc.Status.Capabilities = &apimv1.APIMServiceCapabilities{
+		DeveloperPortal:     capabilities.DeveloperPortal,
+		Backup:              capabilities.Backup,
+		UnsupportedPolicies: capabilities.UnsupportedPolicies,
+	}
+}
+
+// notSupportedOnSKU returns the message of a NotSupportedOnSKU condition for feature on the tier of svc.
+func notSupportedOnSKU(svc *apimv1.APIMService, feature string) string {
+	return fmt.Sprintf("%s not supported on this SKU (%s)", feature, svc.Status.SKU)
+}
+
+// developerPortalSupported reports whether the tier of svc has a developer portal. A tier that was not read
+// yet is assumed to have one.
+func developerPortalSupported(svc *apimv1.APIMService) bool {
+	return svc.Status.Capabilities == nil || svc.Status.Capabilities.DeveloperPortal
+}
+
+// backupSupported reports whether the tier of svc can be backed up. A tier that was not read yet is assumed
+// to support backups.
+func backupSupported(svc *apimv1.APIMService) bool {
+	return svc.Status.Capabilities == nil || svc.Status.Capabilities.Backup
+}
+
+// unsupportedServiceFeatures returns why the spec of svc can't be served by its tier, or "" if it can.
+func unsupportedServiceFeatures(svc *apimv1.APIMService) string {
+	if svc.Spec.PortalPublishing != nil && !developerPortalSupported(svc) {
+		return notSupportedOnSKU(svc, "portalPublishing is") + ": the tier has no developer portal"
+	}
+	if svc.Spec.Backup != nil && !backupSupported(svc) {
+		return notSupportedOnSKU(svc, "backup is")
+	}
+	return ""
+}
+
+// unsupportedPolicyMessage returns why the policy document content can't be applied on the tier of svc, or
+// "" if it can.
+func unsupportedPolicyMessage(svc *apimv1.APIMService, content string) string {
+	if svc.Status.Capabilities == nil {
+		return ""
+	}
+	elements := apim.UnsupportedPolicyElements(content, svc.Status.Capabilities.UnsupportedPolicies)
+	switch len(elements) {
+	case 0:
+		return ""
+	case 1:
+		return notSupportedOnSKU(svc, "Policy element <"+elements[0]+"> is")
+	}
+	return notSupportedOnSKU(svc, "Policy elements <"+strings.Join(elements, ">, <")+"> are")
+}
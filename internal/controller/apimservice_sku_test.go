@@ -0,0 +1,89 @@
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	apimv1 "github.com/hedinit/azure-apim-operator/api/v1"
+	"github.com/hedinit/azure-apim-operator/internal/apim"
+)
+
+func TestSetSKU(t *testing.T) {
+	svc := &apimv1.APIMService{}
+	setSKU(svc, apim.SKUConsumption)
+	if svc.Status.SKU != apim.SKUConsumption || svc.Status.Capabilities == nil || svc.Status.Capabilities.DeveloperPortal {
+		t.Fatalf("status after setSKU(Consumption) = %+v, want no developer portal", svc.Status)
+	}
+
+	setSKU(svc, "")
+	if svc.Status.Capabilities != nil {
+		t.Errorf("capabilities without a SKU = %+v, want unknown", svc.Status.Capabilities)
+	}
+}
+
+func TestUnsupportedServiceFeatures(t *testing.T) {
+	svc := &apimv1.APIMService{Spec: apimv1.APIMServiceSpec{PortalPublishing: &apimv1.APIMServicePortalPublishing{}}}
+	if got := unsupportedServiceFeatures(svc); got != "" {
+		t.Errorf("unsupportedServiceFeatures() before the SKU is known = %q, want none", got)
+	}
+
+	setSKU(svc, apim.SKUStandardV2)
+	if got := unsupportedServiceFeatures(svc); got != "" {
+		t.Errorf("unsupportedServiceFeatures() on StandardV2 = %q, want none", got)
+	}
+
+	setSKU(svc, apim.SKUConsumption)
+	want := "portalPublishing is not supported on this SKU (Consumption): the tier has no developer portal"
+	if got := unsupportedServiceFeatures(svc); got != want {
+		t.Errorf("unsupportedServiceFeatures() on Consumption = %q, want %q", got, want)
+	}
+
+	// Content changes are neither signaled nor published on a tier without a developer portal.
+	now := time.Date(2026, 1, 12, 10, 0, 0, 0, time.UTC)
+	svc.Annotations = map[string]string{portalContentChangedAnnotation: now.Add(-time.Hour).Format(time.RFC3339Nano)}
+	if _, pending := portalPublishRemaining(svc, now); pending {
+		t.Error("portalPublishRemaining() pending on Consumption")
+	}
+	if err := signalPortalContentChanged(context.Background(), nil, svc); err != nil {
+		t.Errorf("signalPortalContentChanged() on Consumption error = %v, want it to do nothing", err)
+	}
+}
+
+func TestUnsupportedPolicyMessage(t *testing.T) {
+	const quota = `<policies><inbound><quota-by-key calls="100" renewal-period="3600" counter-key="a" /></inbound></policies>`
+	const both = `<policies><inbound><quota-by-key calls="100" renewal-period="3600" counter-key="a" />` +
+		`<rate-limit-by-key calls="10" renewal-period="60" counter-key="a" /></inbound></policies>`
+	svc := &apimv1.APIMService{}
+	if got := unsupportedPolicyMessage(svc, quota); got != "" {
+		t.Errorf("unsupportedPolicyMessage() before the SKU is known = %q, want none", got)
+	}
+
+	setSKU(svc, apim.SKUBasicV2)
+	if got, want := unsupportedPolicyMessage(svc, quota), "Policy element <quota-by-key> is not supported on this SKU (BasicV2)"; got != want {
+		t.Errorf("unsupportedPolicyMessage() = %q, want %q", got, want)
+	}
+	if got := unsupportedPolicyMessage(svc, "<policies><inbound><base /></inbound></policies>"); got != "" {
+		t.Errorf("unsupportedPolicyMessage() for a supported policy = %q, want none", got)
+	}
+
+	setSKU(svc, apim.SKUConsumption)
+	want := "Policy elements <quota-by-key>, <rate-limit-by-key> are not supported on this SKU (Consumption)"
+	if got := unsupportedPolicyMessage(svc, both); got != want {
+		t.Errorf("unsupportedPolicyMessage() = %q, want %q", got, want)
+	}
+}
+
+func TestUnsupportedBackup(t *testing.T) {
+	svc := &apimv1.APIMService{Spec: apimv1.APIMServiceSpec{Backup: &apimv1.APIMServiceBackup{Schedule: "0 2 * * *"}}}
+	setSKU(svc, apim.SKUPremium)
+	if got := unsupportedServiceFeatures(svc); got != "" {
+		t.Errorf("unsupportedServiceFeatures() on Premium = %q, want none", got)
+	}
+
+	setSKU(svc, apim.SKUStandardV2)
+	want := "backup is not supported on this SKU (StandardV2)"
+	if got := unsupportedServiceFeatures(svc); got != want {
+		t.Errorf("unsupportedServiceFeatures() on StandardV2 = %q, want %q", got, want)
+	}
+}
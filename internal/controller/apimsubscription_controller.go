@@ -0,0 +1,356 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"slices"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	apimv1 "github.com/hedinit/azure-apim-operator/api/v1"
+	"github.com/hedinit/azure-apim-operator/internal/apim"
+	"github.com/hedinit/azure-apim-operator/internal/identity"
+	"github.com/hedinit/azure-apim-operator/internal/redact"
+)
+
+const (
+	// apimSubscriptionFinalizer keeps an APIMSubscription until its APIM subscription and key Secrets are deleted.
+	apimSubscriptionFinalizer = "apim.operator.io/subscription-cleanup"
+	// subscriptionSecretSourceAnnotation names the APIMSubscription a key Secret was written for, as namespace/name.
+	subscriptionSecretSourceAnnotation = "apim.operator.io/subscription"
+
+	subscriptionKeyPrimary   = "primary"
+	subscriptionKeySecondary = "secondary"
+
+	// apimSubscriptionResyncInterval is how often key Secrets are rewritten, so deleted or edited copies are restored.
+	apimSubscriptionResyncInterval = time.Hour
+)
+
+// APIMSubscriptionReconciler reconciles APIMSubscription custom resources.
+// This controller manages subscriptions in Azure API Management and writes their keys to Secrets in
+// the namespaces of the consumers, regenerating one key per rotation interval.
+type APIMSubscriptionReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+	// Recorder records Warning Events for failed syncs. Nil disables them.
+	Recorder record.EventRecorder
+	// APIM sends the Azure Management API requests. Nil sends them to the public endpoint.
+	APIM *apim.Client
+	// Tokens provides the Azure Management API token shared by all reconcilers. Nil asks Azure AD on
+	// every reconcile.
+	Tokens identity.TokenProvider
+}
+
+// +kubebuilder:rbac:groups=apim.operator.io,resources=apimsubscriptions,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=apim.operator.io,resources=apimsubscriptions/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=apim.operator.io,resources=apimsubscriptions/finalizers,verbs=update
+// +kubebuilder:rbac:groups="",resources=secrets,verbs=create;patch;delete
+
+// Reconcile creates or updates the subscription in APIM, regenerates a key when the rotation interval has
+// passed and writes the keys to a Secret in every target namespace. Deleting the APIMSubscription deletes
+// the subscription in APIM and the Secrets.
+func (r *APIMSubscriptionReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	var sub apimv1.APIMSubscription
+	if err := r.Get(ctx, req.NamespacedName, &sub); err != nil {
+		if errors.IsNotFound(err) {
+			logger.Info("🧹 APIMSubscription deleted, skipping", "name", req.NamespacedName)
+			forgetSyncMetrics(kindAPIMSubscription, req.Namespace, req.Name)
+			return ctrl.Result{}, nil
+		}
+		logger.Error(err, "❌ Failed to get APIMSubscription")
+		return ctrl.Result{}, err
+	}
+	setSpanAPIAttributes(ctx, sub.Spec.APIMService, sub.Spec.APIID)
+
+	if sub.DeletionTimestamp.IsZero() && !controllerutil.ContainsFinalizer(&sub, apimSubscriptionFinalizer) {
+		patch := client.MergeFrom(sub.DeepCopy())
+		controllerutil.AddFinalizer(&sub, apimSubscriptionFinalizer)
+		if err := r.Patch(ctx, &sub, patch); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	operatorNamespace, err := getOperatorNamespace()
+	if err != nil {
+		logger.Error(err, "❌ Failed to get operator namespace")
+		return ctrl.Result{}, fmt.Errorf("get operator namespace: %w", err)
+	}
+
+	var apimService apimv1.APIMService
+	if err := r.Get(ctx, client.ObjectKey{Name: sub.Spec.APIMService, Namespace: operatorNamespace}, &apimService); err != nil {
+		logger.Error(err, "❌ Failed to get APIMService", "name", sub.Spec.APIMService)
+		if errors.IsNotFound(err) && !sub.DeletionTimestamp.IsZero() {
+			// Without the service there is nothing to delete in APIM; the Secrets are still removed.
+			return ctrl.Result{}, r.finalize(ctx, &sub)
+		}
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	observeAPIMService(ctx, &apimService)
+
+	clientID := os.Getenv("AZURE_CLIENT_ID")
+	tenantID := os.Getenv("AZURE_TENANT_ID")
+	if clientID == "" || tenantID == "" {
+		logger.Error(fmt.Errorf("missing identity env vars"), "❌ AZURE_CLIENT_ID or AZURE_TENANT_ID not set")
+		r.markFailed(ctx, &sub, apimv1.ReasonAuthFailed, "missing AZURE_CLIENT_ID or AZURE_TENANT_ID", nil)
+		return ctrl.Result{RequeueAfter: 30 * time.Second}, nil
+	}
+	token, err := managementToken(ctx, r.Tokens, clientID, tenantID)
+	if err != nil {
+		logger.Error(err, "❌ Failed to get Azure token")
+		r.markFailed(ctx, &sub, apimv1.ReasonAuthFailed, errMsgFailedToGetAzureToken, err)
+		return ctrl.Result{RequeueAfter: 30 * time.Second}, nil
+	}
+
+	cfg := apim.APIMSubscriptionConfig{
+		SubscriptionID: apimService.Spec.Subscription,
+		ResourceGroup:  apimService.Spec.ResourceGroup,
+		ServiceName:    sub.Spec.APIMService,
+		Name:           sub.Spec.SubscriptionID,
+		DisplayName:    sub.Spec.DisplayName,
+		Scope:          subscriptionScope(&sub.Spec),
+		BearerToken:    token,
+	}
+	if cfg.DisplayName == "" {
+		cfg.DisplayName = sub.Spec.SubscriptionID
+	}
+
+	if !sub.DeletionTimestamp.IsZero() {
+		if err := r.APIM.DeleteSubscription(ctx, cfg); err != nil {
+			logger.Error(err, "❌ Failed to delete APIM subscription", "subscription", cfg.Name)
+			r.markFailed(ctx, &sub, azureFailureReason(err), "Failed to delete subscription in APIM", err)
+			return ctrl.Result{RequeueAfter: 30 * time.Second}, nil
+		}
+		return ctrl.Result{}, r.finalize(ctx, &sub)
+	}
+
+	// The subscription is only written when its spec changed, so the periodic resync doesn't call APIM.
+	if sub.Status.ObservedGeneration != sub.Generation || sub.Status.Phase != phaseCreated {
+		if err := r.APIM.UpsertSubscription(ctx, cfg); err != nil {
+			logger.Error(err, "❌ Failed to upsert APIM subscription", "subscription", cfg.Name)
+			r.markFailed(ctx, &sub, azureFailureReason(err), "Failed to upsert subscription in APIM", err)
+			return ctrl.Result{RequeueAfter: 30 * time.Second}, nil
+		}
+	}
+
+	statusPatch := client.MergeFrom(sub.DeepCopy())
+	now := time.Now()
+	if sub.Status.ActiveKey == "" {
+		sub.Status.ActiveKey = subscriptionKeyPrimary
+	}
+	rotationWait, rotationDue := subscriptionKeyRotationRemaining(&sub, now)
+	switch {
+	case rotationDue:
+		// Regenerate the key consumers were moved off at the last rotation, then move them onto it.
+		next := otherSubscriptionKey(sub.Status.ActiveKey)
+		if err := r.APIM.RegenerateSubscriptionKey(ctx, cfg, next == subscriptionKeyPrimary); err != nil {
+			logger.Error(err, "❌ Failed to regenerate APIM subscription key", "subscription", cfg.Name, "key", next)
+			r.markFailed(ctx, &sub, azureFailureReason(err), "Failed to regenerate subscription key in APIM", err)
+			return ctrl.Result{RequeueAfter: 30 * time.Second}, nil
+		}
+		sub.Status.ActiveKey = next
+		sub.Status.KeyRotatedAt = now.UTC().Format(time.RFC3339)
+		rotationWait = sub.Spec.RotationInterval.Duration
+	case sub.Status.KeyRotatedAt == "":
+		// The keys of a new subscription, or one that just enabled rotation, count as freshly rotated.
+		sub.Status.KeyRotatedAt = now.UTC().Format(time.RFC3339)
+	}
+
+	if !apim.IsDryRun() {
+		keys, err := r.APIM.ListSubscriptionKeys(ctx, cfg)
+		if err != nil {
+			logger.Error(err, "❌ Failed to list APIM subscription keys", "subscription", cfg.Name)
+			r.markFailed(ctx, &sub, azureFailureReason(err), "Failed to list subscription keys in APIM", err)
+			return ctrl.Result{RequeueAfter: 30 * time.Second}, nil
+		}
+		namespaces := subscriptionSecretNamespaces(&sub)
+		for _, namespace := range namespaces {
+			secret := subscriptionSecret(&sub, namespace, keys)
+			if err := r.Patch(ctx, secret, client.Apply, client.FieldOwner(apimFieldManager), client.ForceOwnership); err != nil {
+				logger.Error(err, "❌ Failed to write subscription key Secret", "namespace", namespace, "secret", secret.Name)
+				r.markFailed(ctx, &sub, apimv1.ReasonSecretWriteFailed, fmt.Sprintf("Failed to write Secret %s/%s", namespace, secret.Name), err)
+				return ctrl.Result{RequeueAfter: 30 * time.Second}, nil
+			}
+		}
+		for _, namespace := range sub.Status.SecretNamespaces {
+			if !slices.Contains(namespaces, namespace) {
+				if err := r.deleteSecret(ctx, &sub, namespace); err != nil {
+					return ctrl.Result{}, err
+				}
+			}
+		}
+		sub.Status.SecretNamespaces = namespaces
+	}
+
+	sub.Status.Phase = phaseCreated
+	sub.Status.Message = "Subscription is synced to APIM and its keys are written to Secrets"
+	sub.Status.AzureResourceID = apim.SubscriptionResourceID(cfg.SubscriptionID, cfg.ResourceGroup, cfg.ServiceName, cfg.Name)
+	sub.Status.TerraformImports = resourceTerraformImports("azurerm_api_management_subscription", "Microsoft.ApiManagement/service/subscriptions", sub.Status.AzureResourceID)
+	setReady(&sub.Status.Conditions, &sub.Status.ObservedGeneration, sub.Generation, metav1.ConditionTrue, apimv1.ReasonSynced, sub.Status.Message)
+	if apim.IsDryRun() {
+		sub.Status.Phase = phaseDryRun
+		sub.Status.Message = "Dry-run: subscription changes were logged but not sent to APIM; no Secrets were written"
+		setReady(&sub.Status.Conditions, &sub.Status.ObservedGeneration, sub.Generation, metav1.ConditionTrue, apimv1.ReasonSynced, sub.Status.Message)
+	}
+	recordSyncSuccess(kindAPIMSubscription, &sub)
+	if err := r.Status().Patch(ctx, &sub, statusPatch); err != nil {
+		logger.Error(err, "❌ Failed to patch APIMSubscription status")
+		return ctrl.Result{}, err
+	}
+	logger.Info("✅ APIM subscription synced", "subscription", cfg.Name, "activeKey", sub.Status.ActiveKey, "namespaces", sub.Status.SecretNamespaces)
+
+	requeueAfter := apimSubscriptionResyncInterval
+	if sub.Spec.RotationInterval != nil && rotationWait < requeueAfter {
+		requeueAfter = rotationWait
+	}
+	return ctrl.Result{RequeueAfter: requeueAfter}, nil
+}
+
+// markFailed sets the Error phase and a False Ready condition, records a Warning Event and patches the status.
+func (r *APIMSubscriptionReconciler) markFailed(ctx context.Context, sub *apimv1.APIMSubscription, reason, message string, err error) {
+	statusPatch := client.MergeFrom(sub.DeepCopy())
+	sub.Status.Phase = phaseError
+	sub.Status.Message = message
+	if err != nil {
+		sub.Status.Message = redact.String(err.Error())
+		message = failureMessage(message, err)
+		invalidateRejectedToken(r.Tokens, err)
+	}
+	recordSyncFailure(kindAPIMSubscription, sub)
+	warnNotReady(r.Recorder, sub, &sub.Status.Conditions, &sub.Status.ObservedGeneration, reason, message)
+	if patchErr := r.Status().Patch(ctx, sub, statusPatch); patchErr != nil {
+		log.FromContext(ctx).Error(patchErr, "❌ Failed to patch APIMSubscription status")
+	}
+}
+
+// finalize deletes the key Secrets of sub and removes its finalizer.
+func (r *APIMSubscriptionReconciler) finalize(ctx context.Context, sub *apimv1.APIMSubscription) error {
+	for _, namespace := range subscriptionSecretNamespaces(sub) {
+		if err := r.deleteSecret(ctx, sub, namespace); err != nil {
+			return err
+		}
+	}
+	for _, namespace := range sub.Status.SecretNamespaces {
+		if err := r.deleteSecret(ctx, sub, namespace); err != nil {
+			return err
+		}
+	}
+	patch := client.MergeFrom(sub.DeepCopy())
+	controllerutil.RemoveFinalizer(sub, apimSubscriptionFinalizer)
+	return r.Patch(ctx, sub, patch)
+}
+
+// deleteSecret deletes the key Secret of sub in namespace, if it exists.
+func (r *APIMSubscriptionReconciler) deleteSecret(ctx context.Context, sub *apimv1.APIMSubscription, namespace string) error {
+	secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: subscriptionSecretName(sub), Namespace: namespace}}
+	if err := r.Delete(ctx, secret); err != nil && !errors.IsNotFound(err) {
+		return fmt.Errorf("delete Secret %s/%s: %w", namespace, secret.Name, err)
+	}
+	return nil
+}
+
+// subscriptionScope returns the APIM scope of a subscription: its API, all APIs or its product.
+func subscriptionScope(spec *apimv1.APIMSubscriptionSpec) string {
+	if spec.APIID != "" {
+		return "/apis/" + spec.APIID
+	}
+	if spec.AllAPIs {
+		return "/apis"
+	}
+	return "/products/" + spec.ProductID
+}
+
+// subscriptionSecretName returns the name of the key Secrets of sub.
+func subscriptionSecretName(sub *apimv1.APIMSubscription) string {
+	if sub.Spec.SecretName != "" {
+		return sub.Spec.SecretName
+	}
+	return sub.Name
+}
+
+// subscriptionSecretNamespaces returns the sorted, deduplicated namespaces that get a key Secret,
+// always including the namespace of sub.
+func subscriptionSecretNamespaces(sub *apimv1.APIMSubscription) []string {
+	namespaces := append([]string{sub.Namespace}, sub.Spec.TargetNamespaces...)
+	slices.Sort(namespaces)
+	return slices.Compact(namespaces)
+}
+
+// subscriptionSecret returns the key Secret of sub for namespace as a server-side apply configuration.
+// subscriptionKey holds the active key, which consumers should use.
+func subscriptionSecret(sub *apimv1.APIMSubscription, namespace string, keys apim.SubscriptionKeys) *corev1.Secret {
+	active := keys.PrimaryKey
+	if sub.Status.ActiveKey == subscriptionKeySecondary {
+		active = keys.SecondaryKey
+	}
+	return &corev1.Secret{
+		TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "Secret"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        subscriptionSecretName(sub),
+			Namespace:   namespace,
+			Labels:      map[string]string{"app.kubernetes.io/managed-by": "azure-apim-operator"},
+			Annotations: map[string]string{subscriptionSecretSourceAnnotation: sub.Namespace + "/" + sub.Name},
+		},
+		Type: corev1.SecretTypeOpaque,
+		StringData: map[string]string{
+			"primaryKey":      keys.PrimaryKey,
+			"secondaryKey":    keys.SecondaryKey,
+			"subscriptionKey": active,
+		},
+	}
+}
+
+// subscriptionKeyRotationRemaining reports whether a key of sub is due for regeneration and, if not, how
+// long until it is. Subscriptions without a rotation interval or a recorded rotation are never due.
+func subscriptionKeyRotationRemaining(sub *apimv1.APIMSubscription, now time.Time) (time.Duration, bool) {
+	if sub.Spec.RotationInterval == nil || sub.Spec.RotationInterval.Duration <= 0 {
+		return 0, false
+	}
+	rotatedAt, err := time.Parse(time.RFC3339, sub.Status.KeyRotatedAt)
+	if err != nil {
+		return sub.Spec.RotationInterval.Duration, false
+	}
+	remaining := rotatedAt.Add(sub.Spec.RotationInterval.Duration).Sub(now)
+	if remaining <= 0 {
+		return 0, true
+	}
+	return remaining, false
+}
+
+// otherSubscriptionKey returns "secondary" for "primary" and the other way round.
+func otherSubscriptionKey(key string) string {
+	if key == subscriptionKeyPrimary {
+		return subscriptionKeySecondary
+	}
+	return subscriptionKeyPrimary
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *APIMSubscriptionReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&apimv1.APIMSubscription{}).
+		WithEventFilter(predicate.Funcs{
+			CreateFunc: func(e event.CreateEvent) bool { return true },
+			UpdateFunc: func(e event.UpdateEvent) bool {
+				return e.ObjectOld.GetGeneration() != e.ObjectNew.GetGeneration() || e.ObjectNew.GetDeletionTimestamp() != nil
+			},
+			DeleteFunc:  func(e event.DeleteEvent) bool { return false },
+			GenericFunc: func(e event.GenericEvent) bool { return false },
+		}).
+		WithEventFilter(shardPredicate()).
+		Named("apimsubscription").
+		Complete(withTracing("APIMSubscription", r))
+}
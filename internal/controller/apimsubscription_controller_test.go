@@ -0,0 +1,95 @@
+package controller
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apimv1 "github.com/hedinit/azure-apim-operator/api/v1"
+	"github.com/hedinit/azure-apim-operator/internal/apim"
+)
+
+func TestSubscriptionScope(t *testing.T) {
+	if got := subscriptionScope(&apimv1.APIMSubscriptionSpec{APIID: "orders"}); got != "/apis/orders" {
+		t.Errorf("subscriptionScope() = %q, want /apis/orders", got)
+	}
+	if got := subscriptionScope(&apimv1.APIMSubscriptionSpec{ProductID: "starter"}); got != "/products/starter" {
+		t.Errorf("subscriptionScope() = %q, want /products/starter", got)
+	}
+	if got := subscriptionScope(&apimv1.APIMSubscriptionSpec{AllAPIs: true}); got != "/apis" {
+		t.Errorf("subscriptionScope() = %q, want /apis", got)
+	}
+}
+
+func TestSubscriptionSecretNamespaces(t *testing.T) {
+	sub := &apimv1.APIMSubscription{
+		ObjectMeta: metav1.ObjectMeta{Name: "orders-client", Namespace: "checkout"},
+		Spec:       apimv1.APIMSubscriptionSpec{TargetNamespaces: []string{"jobs", "checkout", "api", "jobs"}},
+	}
+	want := []string{"api", "checkout", "jobs"}
+	if got := subscriptionSecretNamespaces(sub); !slices.Equal(got, want) {
+		t.Errorf("subscriptionSecretNamespaces() = %v, want %v", got, want)
+	}
+}
+
+func TestSubscriptionSecret(t *testing.T) {
+	sub := &apimv1.APIMSubscription{
+		ObjectMeta: metav1.ObjectMeta{Name: "orders-client", Namespace: "checkout"},
+		Status:     apimv1.APIMSubscriptionStatus{ActiveKey: subscriptionKeySecondary},
+	}
+	keys := apim.SubscriptionKeys{PrimaryKey: "p", SecondaryKey: "s"}
+
+	secret := subscriptionSecret(sub, "jobs", keys)
+	if secret.Name != "orders-client" || secret.Namespace != "jobs" {
+		t.Errorf("secret = %s/%s, want jobs/orders-client", secret.Namespace, secret.Name)
+	}
+	if secret.Annotations[subscriptionSecretSourceAnnotation] != "checkout/orders-client" {
+		t.Errorf("source annotation = %q, want checkout/orders-client", secret.Annotations[subscriptionSecretSourceAnnotation])
+	}
+	if secret.StringData["subscriptionKey"] != "s" || secret.StringData["primaryKey"] != "p" || secret.StringData["secondaryKey"] != "s" {
+		t.Errorf("data = %v, want the secondary key active", secret.StringData)
+	}
+
+	sub.Spec.SecretName = "orders-key"
+	sub.Status.ActiveKey = subscriptionKeyPrimary
+	secret = subscriptionSecret(sub, "checkout", keys)
+	if secret.Name != "orders-key" || secret.StringData["subscriptionKey"] != "p" {
+		t.Errorf("secret %s has subscriptionKey %q, want orders-key with the primary key", secret.Name, secret.StringData["subscriptionKey"])
+	}
+}
+
+func TestSubscriptionKeyRotationRemaining(t *testing.T) {
+	now := time.Date(2026, 2, 1, 12, 0, 0, 0, time.UTC)
+	sub := &apimv1.APIMSubscription{Status: apimv1.APIMSubscriptionStatus{
+		KeyRotatedAt: now.Add(-10 * time.Hour).Format(time.RFC3339),
+	}}
+	if _, due := subscriptionKeyRotationRemaining(sub, now); due {
+		t.Error("subscriptionKeyRotationRemaining() due without rotationInterval")
+	}
+
+	sub.Spec.RotationInterval = &metav1.Duration{Duration: 24 * time.Hour}
+	if wait, due := subscriptionKeyRotationRemaining(sub, now); due || wait != 14*time.Hour {
+		t.Errorf("subscriptionKeyRotationRemaining() = %s, %t, want 14h", wait, due)
+	}
+
+	sub.Spec.RotationInterval.Duration = 8 * time.Hour
+	if _, due := subscriptionKeyRotationRemaining(sub, now); !due {
+		t.Error("subscriptionKeyRotationRemaining() not due after the interval passed")
+	}
+
+	sub.Status.KeyRotatedAt = ""
+	if _, due := subscriptionKeyRotationRemaining(sub, now); due {
+		t.Error("subscriptionKeyRotationRemaining() due before the first rotation was recorded")
+	}
+}
+
+func TestOtherSubscriptionKey(t *testing.T) {
+	if got := otherSubscriptionKey(subscriptionKeyPrimary); got != subscriptionKeySecondary {
+		t.Errorf("otherSubscriptionKey(primary) = %q", got)
+	}
+	if got := otherSubscriptionKey(subscriptionKeySecondary); got != subscriptionKeyPrimary {
+		t.Errorf("otherSubscriptionKey(secondary) = %q", got)
+	}
+}
@@ -23,7 +23,9 @@ import (
 	"time"
 
 	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/event"
@@ -33,6 +35,7 @@ import (
 	apimv1 "github.com/hedinit/azure-apim-operator/api/v1"
 	"github.com/hedinit/azure-apim-operator/internal/apim"
 	"github.com/hedinit/azure-apim-operator/internal/identity"
+	"github.com/hedinit/azure-apim-operator/internal/redact"
 )
 
 // APIMTagReconciler reconciles APIMTag custom resources.
@@ -42,6 +45,13 @@ import (
 type APIMTagReconciler struct {
 	client.Client
 	Scheme *runtime.Scheme
+	// Recorder records Warning Events for failed syncs. Nil disables them.
+	Recorder record.EventRecorder
+	// APIM sends the Azure Management API requests. Nil sends them to the public endpoint.
+	APIM *apim.Client
+	// Tokens provides the Azure Management API token shared by all reconcilers. Nil asks Azure AD on
+	// every reconcile.
+	Tokens identity.TokenProvider
 }
 
 // +kubebuilder:rbac:groups=apim.operator.io,resources=apimtags,verbs=get;list;watch;create;update;patch;delete
@@ -64,11 +74,13 @@ func (r *APIMTagReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ct
 	if err := r.Get(ctx, req.NamespacedName, &tag); err != nil {
 		if errors.IsNotFound(err) {
 			logger.Info("🧹 APIMTag deleted, skipping", "name", req.NamespacedName)
+			forgetSyncMetrics(kindAPIMTag, req.Namespace, req.Name)
 			return ctrl.Result{}, nil
 		}
 		logger.Error(err, "❌ Failed to get APIMTag")
 		return ctrl.Result{}, err
 	}
+	setSpanAPIAttributes(ctx, tag.Spec.APIMService, "")
 
 	operatorNamespace, err := getOperatorNamespace()
 	if err != nil {
@@ -81,6 +93,7 @@ func (r *APIMTagReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ct
 		logger.Error(err, "❌ Failed to get APIMService", "name", tag.Spec.APIMService)
 		return ctrl.Result{}, client.IgnoreNotFound(err)
 	}
+	observeAPIMService(ctx, &apimService)
 
 	clientID := os.Getenv("AZURE_CLIENT_ID")
 	tenantID := os.Getenv("AZURE_TENANT_ID")
@@ -89,18 +102,22 @@ func (r *APIMTagReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ct
 		// Use Patch to update only status without touching spec fields.
 		statusPatch := client.MergeFrom(tag.DeepCopy())
 		tag.Status.Phase = phaseError
+		recordSyncFailure(kindAPIMTag, &tag)
 		tag.Status.Message = "missing AZURE_CLIENT_ID or AZURE_TENANT_ID"
+		warnNotReady(r.Recorder, &tag, &tag.Status.Conditions, &tag.Status.ObservedGeneration, apimv1.ReasonAuthFailed, tag.Status.Message)
 		_ = r.Status().Patch(ctx, &tag, statusPatch)
 		return ctrl.Result{RequeueAfter: 30 * time.Second}, nil
 	}
 
-	token, err := identity.GetManagementToken(ctx, clientID, tenantID)
+	token, err := managementToken(ctx, r.Tokens, clientID, tenantID)
 	if err != nil {
 		logger.Error(err, "❌ Failed to get Azure token")
 		// Use Patch to update only status without touching spec fields.
 		statusPatch := client.MergeFrom(tag.DeepCopy())
 		tag.Status.Phase = phaseError
+		recordSyncFailure(kindAPIMTag, &tag)
 		tag.Status.Message = errMsgFailedToGetAzureToken
+		warnNotReady(r.Recorder, &tag, &tag.Status.Conditions, &tag.Status.ObservedGeneration, apimv1.ReasonAuthFailed, failureMessage(tag.Status.Message, err))
 		_ = r.Status().Patch(ctx, &tag, statusPatch)
 		return ctrl.Result{RequeueAfter: 30 * time.Second}, nil
 	}
@@ -114,18 +131,30 @@ func (r *APIMTagReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ct
 		BearerToken:    token,
 	}
 
-	if err := apim.UpsertTag(ctx, cfg); err != nil {
+	// Use Patch to update only status without touching spec fields.
+	statusPatch := client.MergeFrom(tag.DeepCopy())
+	if err := r.APIM.UpsertTag(ctx, cfg); err != nil {
 		logger.Error(err, "❌ Failed to upsert APIM tag", "tagID", cfg.TagID)
 		tag.Status.Phase = phaseError
-		tag.Status.Message = err.Error()
+		recordSyncFailure(kindAPIMTag, &tag)
+		tag.Status.Message = redact.String(err.Error())
+		invalidateRejectedToken(r.Tokens, err)
+		warnNotReady(r.Recorder, &tag, &tag.Status.Conditions, &tag.Status.ObservedGeneration, azureFailureReason(err), failureMessage("Failed to upsert tag in APIM", err))
 	} else {
 		logger.Info("✅ Successfully upserted APIM tag", "tagID", cfg.TagID)
 		tag.Status.Phase = phaseCreated
+		setReady(&tag.Status.Conditions, &tag.Status.ObservedGeneration, tag.Generation, metav1.ConditionTrue, apimv1.ReasonSynced, "Tag is synced to APIM")
+		recordSyncSuccess(kindAPIMTag, &tag)
 		tag.Status.Message = "Tag created or updated"
+		tag.Status.AzureResourceID = apim.TagResourceID(cfg.SubscriptionID, cfg.ResourceGroup, cfg.ServiceName, cfg.TagID)
+		tag.Status.TerraformImports = resourceTerraformImports("azurerm_api_management_tag", "Microsoft.ApiManagement/service/tags", tag.Status.AzureResourceID)
+		if apim.IsDryRun() {
+			tag.Status.Phase = phaseDryRun
+			tag.Status.Message = "Dry-run: tag changes were logged but not sent to APIM"
+			setReady(&tag.Status.Conditions, &tag.Status.ObservedGeneration, tag.Generation, metav1.ConditionTrue, apimv1.ReasonSynced, tag.Status.Message)
+		}
 	}
 
-	// Use Patch to update only status without touching spec fields.
-	statusPatch := client.MergeFrom(tag.DeepCopy())
 	if err := r.Status().Patch(ctx, &tag, statusPatch); err != nil {
 		logger.Error(err, "❌ Failed to patch APIMTag status")
 		return ctrl.Result{}, err
@@ -140,10 +169,11 @@ func (r *APIMTagReconciler) SetupWithManager(mgr ctrl.Manager) error {
 		For(&apimv1.APIMTag{}).
 		WithEventFilter(predicate.Funcs{
 			CreateFunc:  func(e event.CreateEvent) bool { return true },
-			UpdateFunc:  func(e event.UpdateEvent) bool { return false },
+			UpdateFunc:  func(e event.UpdateEvent) bool { return e.ObjectOld.GetGeneration() != e.ObjectNew.GetGeneration() },
 			DeleteFunc:  func(e event.DeleteEvent) bool { return false },
 			GenericFunc: func(e event.GenericEvent) bool { return false },
 		}).
+		WithEventFilter(shardPredicate()).
 		Named("apimtag").
-		Complete(r)
+		Complete(withTracing("APIMTag", r))
 }
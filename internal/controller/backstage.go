@@ -0,0 +1,65 @@
+package controller
+
+import (
+	"strings"
+
+	apimv1 "github.com/hedinit/azure-apim-operator/api/v1"
+)
+
+const (
+	// argoCDExternalLinkAnnotation makes Argo CD show a link to the API gateway URL in its UI.
+	argoCDExternalLinkAnnotation = "link.argocd.argoproj.io/external-link"
+
+	// backstageAnnotationPrefix prefixes the annotations that describe an APIMAPI as a Backstage API entity.
+	// A catalog provider that ingests Kubernetes objects can map them onto the entity without reading status.
+	backstageAnnotationPrefix = "backstage.apim.operator.io/"
+	// backstageNameAnnotation is the APIM API ID, a stable entity name across clusters and namespaces.
+	backstageNameAnnotation = backstageAnnotationPrefix + "name"
+	// backstageOwnerAnnotation is the entity reference from spec.owner.
+	backstageOwnerAnnotation = backstageAnnotationPrefix + "owner"
+	// backstageDefinitionURLAnnotation is the URL of the OpenAPI definition that was last imported.
+	backstageDefinitionURLAnnotation = backstageAnnotationPrefix + "definition-url"
+	// backstageGatewayURLAnnotation is the URL of the API on the APIM gateway.
+	backstageGatewayURLAnnotation = backstageAnnotationPrefix + "gateway-url"
+	// backstageDeveloperPortalURLAnnotation is the URL of the APIM developer portal.
+	backstageDeveloperPortalURLAnnotation = backstageAnnotationPrefix + "developer-portal-url"
+)
+
+// desiredAPIMAPIAnnotations returns the annotations the operator owns on an APIMAPI. The Backstage annotations are
+// only published for APIs with a spec.owner, and only with the values that are known, so an API that was never
+// imported carries its name and owner only.
+func desiredAPIMAPIAnnotations(apimApi *apimv1.APIMAPI) map[string]string {
+	annotations := map[string]string{argoCDExternalLinkAnnotation: apimApi.Status.ApiHost}
+	if apimApi.Spec.Owner == "" {
+		return annotations
+	}
+
+	annotations[backstageNameAnnotation] = apimApi.Spec.APIID
+	annotations[backstageOwnerAnnotation] = apimApi.Spec.Owner
+	for key, value := range map[string]string{
+		backstageDefinitionURLAnnotation:      apimApi.Status.DefinitionURL,
+		backstageGatewayURLAnnotation:         apimApi.Status.ApiHost,
+		backstageDeveloperPortalURLAnnotation: apimApi.Status.DeveloperPortalHost,
+	} {
+		if value != "" {
+			annotations[key] = value
+		}
+	}
+	return annotations
+}
+
+// apimAPIAnnotationsInSync reports whether apimApi carries exactly the desired operator-owned annotations:
+// each desired value is set and no Backstage annotation is left over from an earlier owner or status.
+func apimAPIAnnotationsInSync(apimApi *apimv1.APIMAPI, desired map[string]string) bool {
+	for key, value := range desired {
+		if apimApi.Annotations[key] != value {
+			return false
+		}
+	}
+	for key := range apimApi.Annotations {
+		if _, ok := desired[key]; !ok && strings.HasPrefix(key, backstageAnnotationPrefix) {
+			return false
+		}
+	}
+	return true
+}
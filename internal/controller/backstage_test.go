@@ -0,0 +1,67 @@
+package controller
+
+import (
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apimv1 "github.com/hedinit/azure-apim-operator/api/v1"
+)
+
+func TestDesiredAPIMAPIAnnotations(t *testing.T) {
+	apimApi := &apimv1.APIMAPI{
+		ObjectMeta: metav1.ObjectMeta{Name: "payments", Namespace: "integrations"},
+		Spec:       apimv1.APIMAPISpec{APIID: "payments-api"},
+		Status:     apimv1.APIMAPIStatus{ApiHost: "https://apim.azure-api.net/payments"},
+	}
+
+	got := desiredAPIMAPIAnnotations(apimApi)
+	if len(got) != 1 || got[argoCDExternalLinkAnnotation] != apimApi.Status.ApiHost {
+		t.Errorf("without owner = %v, want only the external link", got)
+	}
+
+	apimApi.Spec.Owner = "group:default/payments"
+	got = desiredAPIMAPIAnnotations(apimApi)
+	want := map[string]string{
+		argoCDExternalLinkAnnotation:  "https://apim.azure-api.net/payments",
+		backstageNameAnnotation:       "payments-api",
+		backstageOwnerAnnotation:      "group:default/payments",
+		backstageGatewayURLAnnotation: "https://apim.azure-api.net/payments",
+	}
+	if len(got) != len(want) {
+		t.Fatalf("with owner = %v, want %v", got, want)
+	}
+	for key, value := range want {
+		if got[key] != value {
+			t.Errorf("%s = %q, want %q", key, got[key], value)
+		}
+	}
+}
+
+func TestAPIMAPIAnnotationsInSync(t *testing.T) {
+	desired := map[string]string{
+		argoCDExternalLinkAnnotation: "",
+		backstageOwnerAnnotation:     "group:default/payments",
+	}
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		want        bool
+	}{
+		{name: "in sync", annotations: map[string]string{backstageOwnerAnnotation: "group:default/payments", "team": "a"}, want: true},
+		{name: "owner changed", annotations: map[string]string{backstageOwnerAnnotation: "group:default/billing"}, want: false},
+		{name: "missing owner", annotations: nil, want: false},
+		{name: "stale annotation", annotations: map[string]string{
+			backstageOwnerAnnotation:         "group:default/payments",
+			backstageDefinitionURLAnnotation: "https://payments/swagger.json",
+		}, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			apimApi := &apimv1.APIMAPI{ObjectMeta: metav1.ObjectMeta{Annotations: tt.annotations}}
+			if got := apimAPIAnnotationsInSync(apimApi, desired); got != tt.want {
+				t.Errorf("apimAPIAnnotationsInSync() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
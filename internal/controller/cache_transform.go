@@ -0,0 +1,88 @@
+package controller
+
+import (
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// maxCachedAnnotationBytes is the largest annotation value kept on cached Pods and ReplicaSets.
+	// Larger values, such as kubectl.kubernetes.io/last-applied-configuration, are never read by the operator.
+	maxCachedAnnotationBytes = 1024
+
+	// cachedRolloutHashAnnotation carries the rollout hash of a cached ReplicaSet, computed before its
+	// pod template is stripped. It only exists in the operator's cache and is never written to the cluster.
+	cachedRolloutHashAnnotation = "apim.operator.io/cached-rollout-hash"
+)
+
+// CacheOptions returns the manager cache options. The operator watches every Pod and ReplicaSet in the
+// cluster but reads only a few fields of them, so the rest is dropped before objects enter the cache.
+func CacheOptions() cache.Options {
+	return cache.Options{
+		DefaultTransform: cache.TransformStripManagedFields(),
+		ByObject: map[client.Object]cache.ByObject{
+			&corev1.Pod{}:        {Transform: transformPod},
+			&appsv1.ReplicaSet{}: {Transform: transformReplicaSet},
+			&appsv1.Deployment{}: {Transform: transformDeployment},
+		},
+	}
+}
+
+// transformPod keeps the metadata, service account, phase and conditions of a Pod: what the ready-pod
+// lookup and the workload identity discovery read.
+func transformPod(obj interface{}) (interface{}, error) {
+	pod, ok := obj.(*corev1.Pod)
+	if !ok {
+		return obj, nil
+	}
+	stripObjectMeta(pod)
+	pod.Spec = corev1.PodSpec{ServiceAccountName: pod.Spec.ServiceAccountName}
+	pod.Status = corev1.PodStatus{Phase: pod.Status.Phase, Conditions: pod.Status.Conditions}
+	return pod, nil
+}
+
+// transformReplicaSet drops the pod template of a ReplicaSet, its largest part, after recording the rollout
+// hash that the ReplicaSet watcher would compute from it. Replicas, selector and status are kept.
+func transformReplicaSet(obj interface{}) (interface{}, error) {
+	rs, ok := obj.(*appsv1.ReplicaSet)
+	if !ok {
+		return obj, nil
+	}
+	hash, err := replicaSetRolloutHash(rs)
+	if err != nil {
+		return nil, err
+	}
+	stripObjectMeta(rs)
+	if rs.Annotations == nil {
+		rs.Annotations = map[string]string{}
+	}
+	rs.Annotations[cachedRolloutHashAnnotation] = hash
+	rs.Spec.Template = corev1.PodTemplateSpec{}
+	return rs, nil
+}
+
+// transformDeployment keeps the metadata and selector of a Deployment: what APIMAPI generation from
+// Deployment annotations reads. Only watched when that generation is enabled.
+func transformDeployment(obj interface{}) (interface{}, error) {
+	deployment, ok := obj.(*appsv1.Deployment)
+	if !ok {
+		return obj, nil
+	}
+	stripObjectMeta(deployment)
+	deployment.Spec = appsv1.DeploymentSpec{Selector: deployment.Spec.Selector}
+	deployment.Status = appsv1.DeploymentStatus{}
+	return deployment, nil
+}
+
+// stripObjectMeta drops the managed fields and large annotations of a cached object.
+func stripObjectMeta(obj client.Object) {
+	obj.SetManagedFields(nil)
+	annotations := obj.GetAnnotations()
+	for key, value := range annotations {
+		if len(value) > maxCachedAnnotationBytes {
+			delete(annotations, key)
+		}
+	}
+}
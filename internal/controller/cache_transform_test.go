@@ -0,0 +1,116 @@
+package controller
+
+import (
+	"strings"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestTransformPod(t *testing.T) {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:          "web-1",
+			Labels:        map[string]string{"app": "web"},
+			Annotations:   map[string]string{"small": "x", "large": strings.Repeat("x", maxCachedAnnotationBytes+1)},
+			ManagedFields: []metav1.ManagedFieldsEntry{{Manager: "kubectl"}},
+		},
+		Spec: corev1.PodSpec{
+			ServiceAccountName: "web",
+			Containers:         []corev1.Container{{Name: "web", Env: []corev1.EnvVar{{Name: "A", Value: "1"}}}},
+		},
+		Status: corev1.PodStatus{
+			Phase:      corev1.PodRunning,
+			Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}},
+			PodIP:      "10.0.0.1",
+		},
+	}
+
+	out, err := transformPod(pod)
+	if err != nil {
+		t.Fatalf("transformPod() error = %v", err)
+	}
+	got := out.(*corev1.Pod)
+	if got.ManagedFields != nil || len(got.Spec.Containers) != 0 || got.Status.PodIP != "" {
+		t.Errorf("transformPod() kept stripped fields: %+v", got)
+	}
+	if _, ok := got.Annotations["large"]; ok {
+		t.Error("transformPod() kept a large annotation")
+	}
+	if got.Annotations["small"] != "x" || got.Labels["app"] != "web" || got.Spec.ServiceAccountName != "web" ||
+		got.Status.Phase != corev1.PodRunning || len(got.Status.Conditions) != 1 {
+		t.Errorf("transformPod() dropped fields the operator reads: %+v", got)
+	}
+}
+
+func TestTransformReplicaSetKeepsRolloutHash(t *testing.T) {
+	replicaSet := func(env string) *appsv1.ReplicaSet {
+		rs := &appsv1.ReplicaSet{ObjectMeta: metav1.ObjectMeta{Name: "web-aaa"}}
+		rs.Spec.Template.Spec.Containers = []corev1.Container{{
+			Name: "web", Image: "web:1", Env: []corev1.EnvVar{{Name: "MODE", Value: env}},
+		}}
+		return rs
+	}
+
+	want, err := replicaSetRolloutHash(replicaSet("a"))
+	if err != nil {
+		t.Fatalf("replicaSetRolloutHash() error = %v", err)
+	}
+	out, err := transformReplicaSet(replicaSet("a"))
+	if err != nil {
+		t.Fatalf("transformReplicaSet() error = %v", err)
+	}
+	cached := out.(*appsv1.ReplicaSet)
+	if len(cached.Spec.Template.Spec.Containers) != 0 {
+		t.Error("transformReplicaSet() kept the pod template")
+	}
+	if got, _ := replicaSetRolloutHash(cached); got != want {
+		t.Errorf("rollout hash of cached ReplicaSet = %s, want %s", got, want)
+	}
+
+	// A second transform, as on a relist, must not rehash the stripped template.
+	again, err := transformReplicaSet(cached)
+	if err != nil {
+		t.Fatalf("transformReplicaSet() error = %v", err)
+	}
+	if got, _ := replicaSetRolloutHash(again.(*appsv1.ReplicaSet)); got != want {
+		t.Errorf("rollout hash after second transform = %s, want %s", got, want)
+	}
+
+	changed, err := transformReplicaSet(replicaSet("b"))
+	if err != nil {
+		t.Fatalf("transformReplicaSet() error = %v", err)
+	}
+	if got, _ := replicaSetRolloutHash(changed.(*appsv1.ReplicaSet)); got == want {
+		t.Error("env change kept the rollout hash of the cached ReplicaSet")
+	}
+}
+
+func TestTransformDeployment(t *testing.T) {
+	deployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:          "orders",
+			Annotations:   map[string]string{deploymentAPIIDAnnotation: "shop-orders"},
+			ManagedFields: []metav1.ManagedFieldsEntry{{Manager: "kubectl"}},
+		},
+		Spec: appsv1.DeploymentSpec{
+			Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "orders"}},
+			Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "orders"}}}},
+		},
+		Status: appsv1.DeploymentStatus{Replicas: 3},
+	}
+
+	out, err := transformDeployment(deployment)
+	if err != nil {
+		t.Fatalf("transformDeployment() error = %v", err)
+	}
+	cached := out.(*appsv1.Deployment)
+	if cached.ManagedFields != nil || len(cached.Spec.Template.Spec.Containers) != 0 || cached.Status.Replicas != 0 {
+		t.Errorf("transformDeployment() kept managed fields, template or status: %+v", cached)
+	}
+	if cached.Annotations[deploymentAPIIDAnnotation] != "shop-orders" || cached.Spec.Selector.MatchLabels["app"] != "orders" {
+		t.Errorf("transformDeployment() dropped the annotations or selector: %+v", cached)
+	}
+}
@@ -0,0 +1,74 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	apimv1 "github.com/hedinit/azure-apim-operator/api/v1"
+	"github.com/hedinit/azure-apim-operator/internal/apim"
+	"github.com/hedinit/azure-apim-operator/internal/cloudevents"
+	"github.com/hedinit/azure-apim-operator/internal/redact"
+)
+
+const (
+	driftChangeModified = "modified"
+	driftChangeDeleted  = "deleted"
+)
+
+// cloudEventSink receives the lifecycle events of managed APIs. Nil disables them. See SetCloudEventSink.
+var cloudEventSink *cloudevents.Sink
+
+// SetCloudEventSink sets the sink lifecycle events are published to. It must be called before the manager starts.
+func SetCloudEventSink(sink *cloudevents.Sink) {
+	cloudEventSink = sink
+}
+
+// apiEventSubject returns the CloudEvents subject of an API. Subjects start with the APIM service, so
+// subscribers can filter by service or API with a subject prefix.
+func apiEventSubject(apimService, apiID string) string {
+	return fmt.Sprintf("apimservices/%s/apis/%s", apimService, apiID)
+}
+
+// policyEventSubject returns the CloudEvents subject of the policy of an API, or of one of its operations.
+func policyEventSubject(apimService, apiID, operationID string) string {
+	if operationID != "" {
+		return fmt.Sprintf("%s/operations/%s/policy", apiEventSubject(apimService, apiID), operationID)
+	}
+	return apiEventSubject(apimService, apiID) + "/policy"
+}
+
+// productEventSubject returns the CloudEvents subject of a product.
+func productEventSubject(apimService, productID string) string {
+	return fmt.Sprintf("apimservices/%s/products/%s", apimService, productID)
+}
+
+// apiEventData returns the event data of the API of deployment.
+func apiEventData(apimApi *apimv1.APIMAPI, deployment *apimv1.APIMAPIDeployment) cloudevents.Data {
+	return cloudevents.Data{
+		APIMService:     deployment.Spec.APIMService,
+		Kind:            "APIMAPI",
+		Namespace:       apimApi.Namespace,
+		Name:            apimApi.Name,
+		APIID:           deployment.Spec.APIID,
+		Revision:        deployment.Spec.Revision,
+		GatewayURL:      apimApi.Status.ApiHost,
+		DefinitionURL:   redact.String(deployment.Spec.OpenAPIDefinitionURL),
+		AzureResourceID: apimApi.Status.AzureResourceID,
+	}
+}
+
+// withDriftEvents returns a context whose conditional writes emit a DriftDetected event about subject
+// when they find the entity changed or deleted outside the operator.
+func withDriftEvents(ctx context.Context, subject string, data cloudevents.Data) context.Context {
+	if cloudEventSink == nil {
+		return ctx
+	}
+	return apim.WithDriftHandler(ctx, func(deleted bool) {
+		data := data
+		data.Change = driftChangeModified
+		if deleted {
+			data.Change = driftChangeDeleted
+		}
+		cloudEventSink.Emit(cloudevents.TypeDriftDetected, subject, data)
+	})
+}
@@ -0,0 +1,19 @@
+package controller
+
+import "testing"
+
+func TestEventSubjects(t *testing.T) {
+	tests := []struct {
+		got, want string
+	}{
+		{apiEventSubject("apim-prod", "orders"), "apimservices/apim-prod/apis/orders"},
+		{policyEventSubject("apim-prod", "orders", ""), "apimservices/apim-prod/apis/orders/policy"},
+		{policyEventSubject("apim-prod", "orders", "get-order"), "apimservices/apim-prod/apis/orders/operations/get-order/policy"},
+		{productEventSubject("apim-prod", "starter"), "apimservices/apim-prod/products/starter"},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("subject = %q, want %q", tt.got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,186 @@
+package controller
+
+import (
+	"context"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	apimv1 "github.com/hedinit/azure-apim-operator/api/v1"
+)
+
+const (
+	kindAPIMAPI = "APIMAPI"
+
+	// conditionTypeReady is the Ready condition. APIMAPIs set it themselves; for the other kinds the
+	// collector derives it from the phase.
+	conditionTypeReady = "Ready"
+)
+
+// conditionStatuses are the values of the status label. Like kube-state-metrics, each condition gets one
+// series per status, set to 1 for the current status and 0 for the others.
+var conditionStatuses = []metav1.ConditionStatus{metav1.ConditionTrue, metav1.ConditionFalse, metav1.ConditionUnknown}
+
+// resourceConditionDesc describes apim_resource_status_condition.
+var resourceConditionDesc = prometheus.NewDesc(
+	"apim_resource_status_condition",
+	"The current status conditions of a managed resource, in the style of kube-state-metrics.",
+	[]string{"kind", "namespace", "name", "condition", "status"}, nil,
+)
+
+// conditionCollector exports the conditions of the managed resources from the manager's cache on
+// every scrape, so clusters without a kube-state-metrics custom resource config can alert on them.
+type conditionCollector struct {
+	reader client.Reader
+}
+
+// RegisterConditionCollector registers the apim_resource_status_condition gauge, read through reader on
+// each scrape. reader should be the manager's cached client.
+func RegisterConditionCollector(reader client.Reader) error {
+	return metrics.Registry.Register(&conditionCollector{reader: reader})
+}
+
+// Describe implements prometheus.Collector.
+func (c *conditionCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- resourceConditionDesc
+}
+
+// Collect implements prometheus.Collector. It reports a Ready condition derived from the phase of every
+// APIMAPIDeployment, APIMProduct, APIMTag and APIMInboundPolicy, and the conditions set on APIMAPIs.
+func (c *conditionCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), cacheCollectTimeout)
+	defer cancel()
+
+	var deployments apimv1.APIMAPIDeploymentList
+	if err := c.reader.List(ctx, &deployments); err != nil {
+		ch <- prometheus.NewInvalidMetric(resourceConditionDesc, err)
+		return
+	}
+	for i := range deployments.Items {
+		d := &deployments.Items[i]
+		collectCondition(ch, kindAPIMAPIDeployment, d, conditionTypeReady, phaseReadyStatus(d.Status.Phase))
+	}
+
+	var products apimv1.APIMProductList
+	if err := c.reader.List(ctx, &products); err != nil {
+		ch <- prometheus.NewInvalidMetric(resourceConditionDesc, err)
+		return
+	}
+	for i := range products.Items {
+		p := &products.Items[i]
+		collectCondition(ch, kindAPIMProduct, p, conditionTypeReady, phaseReadyStatus(p.Status.Phase))
+	}
+
+	var tags apimv1.APIMTagList
+	if err := c.reader.List(ctx, &tags); err != nil {
+		ch <- prometheus.NewInvalidMetric(resourceConditionDesc, err)
+		return
+	}
+	for i := range tags.Items {
+		t := &tags.Items[i]
+		collectCondition(ch, kindAPIMTag, t, conditionTypeReady, phaseReadyStatus(t.Status.Phase))
+	}
+
+	var subscriptions apimv1.APIMSubscriptionList
+	if err := c.reader.List(ctx, &subscriptions); err != nil {
+		ch <- prometheus.NewInvalidMetric(resourceConditionDesc, err)
+		return
+	}
+	for i := range subscriptions.Items {
+		s := &subscriptions.Items[i]
+		collectCondition(ch, kindAPIMSubscription, s, conditionTypeReady, phaseReadyStatus(s.Status.Phase))
+	}
+
+	var namedValues apimv1.APIMNamedValueList
+	if err := c.reader.List(ctx, &namedValues); err != nil {
+		ch <- prometheus.NewInvalidMetric(resourceConditionDesc, err)
+		return
+	}
+	for i := range namedValues.Items {
+		nv := &namedValues.Items[i]
+		collectCondition(ch, kindAPIMNamedValue, nv, conditionTypeReady, phaseReadyStatus(nv.Status.Phase))
+	}
+
+	var backends apimv1.APIMBackendList
+	if err := c.reader.List(ctx, &backends); err != nil {
+		ch <- prometheus.NewInvalidMetric(resourceConditionDesc, err)
+		return
+	}
+	for i := range backends.Items {
+		b := &backends.Items[i]
+		collectCondition(ch, kindAPIMBackend, b, conditionTypeReady, phaseReadyStatus(b.Status.Phase))
+	}
+
+	var versionSets apimv1.APIMApiVersionSetList
+	if err := c.reader.List(ctx, &versionSets); err != nil {
+		ch <- prometheus.NewInvalidMetric(resourceConditionDesc, err)
+		return
+	}
+	for i := range versionSets.Items {
+		vs := &versionSets.Items[i]
+		collectCondition(ch, kindAPIMApiVersionSet, vs, conditionTypeReady, phaseReadyStatus(vs.Status.Phase))
+	}
+
+	var clusters apimv1.APIMClusterList
+	if err := c.reader.List(ctx, &clusters); err != nil {
+		ch <- prometheus.NewInvalidMetric(resourceConditionDesc, err)
+		return
+	}
+	for i := range clusters.Items {
+		cl := &clusters.Items[i]
+		collectCondition(ch, kindAPIMCluster, cl, conditionTypeReady, phaseReadyStatus(cl.Status.Phase))
+	}
+
+	var policies apimv1.APIMInboundPolicyList
+	if err := c.reader.List(ctx, &policies); err != nil {
+		ch <- prometheus.NewInvalidMetric(resourceConditionDesc, err)
+		return
+	}
+	for i := range policies.Items {
+		p := &policies.Items[i]
+		collectCondition(ch, kindAPIMInboundPolicy, p, conditionTypeReady, phaseReadyStatus(p.Status.Phase))
+	}
+
+	var apis apimv1.APIMAPIList
+	if err := c.reader.List(ctx, &apis); err != nil {
+		ch <- prometheus.NewInvalidMetric(resourceConditionDesc, err)
+		return
+	}
+	for i := range apis.Items {
+		api := &apis.Items[i]
+		for _, condition := range api.Status.Conditions {
+			collectCondition(ch, kindAPIMAPI, api, condition.Type, condition.Status)
+		}
+	}
+}
+
+// collectCondition sends one series per condition status for obj, skipping objects of other shards.
+func collectCondition(ch chan<- prometheus.Metric, kind string, obj client.Object, conditionType string, status metav1.ConditionStatus) {
+	if !ownsNamespace(obj.GetNamespace()) {
+		return
+	}
+	for _, s := range conditionStatuses {
+		value := 0.0
+		if s == status {
+			value = 1
+		}
+		ch <- prometheus.MustNewConstMetric(resourceConditionDesc, prometheus.GaugeValue, value,
+			kind, obj.GetNamespace(), obj.GetName(), conditionType, strings.ToLower(string(s)))
+	}
+}
+
+// phaseReadyStatus maps a resource phase to the status of its Ready condition. Resources that are
+// still waiting, e.g. for a matching ReplicaSet or an approval, are Unknown.
+func phaseReadyStatus(phase string) metav1.ConditionStatus {
+	switch phase {
+	case phaseCreated, phaseDryRun, phaseConnected, apimDeploymentPhaseSucceeded, apimDeploymentPhaseAdopted, apimDeploymentPhaseNoChange:
+		return metav1.ConditionTrue
+	case phaseError:
+		return metav1.ConditionFalse
+	default:
+		return metav1.ConditionUnknown
+	}
+}
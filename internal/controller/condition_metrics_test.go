@@ -0,0 +1,61 @@
+package controller
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	apimv1 "github.com/hedinit/azure-apim-operator/api/v1"
+)
+
+func TestConditionCollector(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := apimv1.AddToScheme(scheme); err != nil {
+		t.Fatalf("AddToScheme() error = %v", err)
+	}
+	meta := func(name string) metav1.ObjectMeta { return metav1.ObjectMeta{Name: name, Namespace: "payments"} }
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		&apimv1.APIMAPIDeployment{ObjectMeta: meta("orders"), Status: apimv1.APIMAPIDeploymentStatus{Phase: phaseError}},
+		&apimv1.APIMProduct{ObjectMeta: meta("gold"), Status: apimv1.APIMProductStatus{Phase: phaseCreated}},
+		&apimv1.APIMAPI{ObjectMeta: meta("orders"), Status: apimv1.APIMAPIStatus{Conditions: []metav1.Condition{
+			{Type: conditionTypeAdopted, Status: metav1.ConditionTrue},
+		}}},
+	).Build()
+
+	want := `
+# HELP apim_resource_status_condition The current status conditions of a managed resource, in the style of kube-state-metrics.
+# TYPE apim_resource_status_condition gauge
+apim_resource_status_condition{condition="Adopted",kind="APIMAPI",name="orders",namespace="payments",status="false"} 0
+apim_resource_status_condition{condition="Adopted",kind="APIMAPI",name="orders",namespace="payments",status="true"} 1
+apim_resource_status_condition{condition="Adopted",kind="APIMAPI",name="orders",namespace="payments",status="unknown"} 0
+apim_resource_status_condition{condition="Ready",kind="APIMAPIDeployment",name="orders",namespace="payments",status="false"} 1
+apim_resource_status_condition{condition="Ready",kind="APIMAPIDeployment",name="orders",namespace="payments",status="true"} 0
+apim_resource_status_condition{condition="Ready",kind="APIMAPIDeployment",name="orders",namespace="payments",status="unknown"} 0
+apim_resource_status_condition{condition="Ready",kind="APIMProduct",name="gold",namespace="payments",status="false"} 0
+apim_resource_status_condition{condition="Ready",kind="APIMProduct",name="gold",namespace="payments",status="true"} 1
+apim_resource_status_condition{condition="Ready",kind="APIMProduct",name="gold",namespace="payments",status="unknown"} 0
+`
+	if err := testutil.CollectAndCompare(&conditionCollector{reader: c}, strings.NewReader(want)); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestPhaseReadyStatus(t *testing.T) {
+	tests := map[string]metav1.ConditionStatus{
+		phaseCreated:                          metav1.ConditionTrue,
+		apimDeploymentPhaseSucceeded:          metav1.ConditionTrue,
+		apimDeploymentPhaseNoChange:           metav1.ConditionTrue,
+		phaseError:                            metav1.ConditionFalse,
+		apimDeploymentPhaseWaitingForReadyPod: metav1.ConditionUnknown,
+		"":                                    metav1.ConditionUnknown,
+	}
+	for phase, want := range tests {
+		if got := phaseReadyStatus(phase); got != want {
+			t.Errorf("phaseReadyStatus(%q) = %s, want %s", phase, got, want)
+		}
+	}
+}
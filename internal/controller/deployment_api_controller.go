@@ -0,0 +1,247 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	apimv1 "github.com/hedinit/azure-apim-operator/api/v1"
+)
+
+const (
+	// deploymentAPIIDAnnotation opts a Deployment into APIMAPI generation and sets spec.APIID.
+	deploymentAPIIDAnnotation = "apim.operator.io/api-id"
+	// deploymentAPIMServiceAnnotation sets spec.apimService. It is required together with the API ID.
+	deploymentAPIMServiceAnnotation = "apim.operator.io/apim-service"
+	// deploymentRoutePrefixAnnotation sets spec.routePrefix. It defaults to /<deployment name>.
+	deploymentRoutePrefixAnnotation = "apim.operator.io/route-prefix"
+	// deploymentServiceURLAnnotation sets spec.serviceUrl. It defaults to the Service named like the Deployment.
+	deploymentServiceURLAnnotation = "apim.operator.io/service-url"
+	// deploymentSwaggerPathAnnotation is the path of the OpenAPI definition below the service URL.
+	deploymentSwaggerPathAnnotation = "apim.operator.io/swagger-path"
+	// deploymentProductsAnnotation sets spec.productIds from a comma-separated list.
+	deploymentProductsAnnotation = "apim.operator.io/products"
+	// deploymentTagsAnnotation sets spec.tagIds from a comma-separated list.
+	deploymentTagsAnnotation = "apim.operator.io/tags"
+	// deploymentSubscriptionRequiredAnnotation sets spec.subscriptionRequired. It defaults to true.
+	deploymentSubscriptionRequiredAnnotation = "apim.operator.io/subscription-required"
+
+	// generatedFromLabel marks APIMAPIs that the operator generated, with the kind of their source.
+	generatedFromLabel = "apim.operator.io/generated-from"
+
+	// eventReasonInvalidAPIAnnotations is the Warning Event reason for a Deployment whose annotations can't be turned into an APIMAPI.
+	eventReasonInvalidAPIAnnotations = "InvalidAPIAnnotations"
+	// eventReasonAPIMAPIConflict is the Warning Event reason for a Deployment whose APIMAPI name is taken by another APIMAPI.
+	eventReasonAPIMAPIConflict = "APIMAPIConflict"
+)
+
+// DeploymentAPIReconciler generates APIMAPIs from apim.operator.io/* annotations on Deployments, so teams can
+// publish an API without writing the custom resource. The generated APIMAPI has the Deployment's name and
+// namespace, targets its ReplicaSets by the Deployment's selector and is owned by the Deployment, so it is
+// garbage-collected with it. Removing the API ID annotation deletes the generated APIMAPI.
+type DeploymentAPIReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+	// Recorder records Warning Events for annotations that can't be applied. Nil disables them.
+	Recorder record.EventRecorder
+}
+
+// +kubebuilder:rbac:groups=apps,resources=deployments,verbs=get;list;watch
+// +kubebuilder:rbac:groups=apim.operator.io,resources=apimapis,verbs=get;list;watch;create;update;patch;delete
+
+func (r *DeploymentAPIReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := ctrl.Log.WithName("deployment_api_controller")
+
+	var deployment appsv1.Deployment
+	if err := r.Get(ctx, req.NamespacedName, &deployment); err != nil {
+		// The generated APIMAPI is garbage-collected through its owner reference.
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	var existing apimv1.APIMAPI
+	err := r.Get(ctx, req.NamespacedName, &existing)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return ctrl.Result{}, err
+	}
+	found := err == nil
+	generated := found && metav1.IsControlledBy(&existing, &deployment)
+
+	if deployment.Annotations[deploymentAPIIDAnnotation] == "" {
+		if generated {
+			logger.Info("🧹 API annotations removed; deleting generated APIMAPI", "name", req.Name, "namespace", req.Namespace)
+			return ctrl.Result{}, client.IgnoreNotFound(r.Delete(ctx, &existing))
+		}
+		return ctrl.Result{}, nil
+	}
+
+	desired, err := apimAPIFromDeployment(&deployment)
+	if err != nil {
+		logger.Info("⚠️ Invalid API annotations on Deployment", "name", req.Name, "namespace", req.Namespace, "error", err.Error())
+		recordWarningEvent(r.Recorder, &deployment, eventReasonInvalidAPIAnnotations, err.Error())
+		return ctrl.Result{}, nil
+	}
+
+	if !found {
+		if err := ctrl.SetControllerReference(&deployment, desired, r.Scheme); err != nil {
+			return ctrl.Result{}, err
+		}
+		if err := r.Create(ctx, desired); err != nil {
+			return ctrl.Result{}, err
+		}
+		logger.Info("🆕 Generated APIMAPI from Deployment annotations", "name", desired.Name, "namespace", desired.Namespace, "apiID", desired.Spec.APIID)
+		return ctrl.Result{}, nil
+	}
+
+	if !generated {
+		message := fmt.Sprintf("APIMAPI %s already exists and was not generated from this Deployment; remove the %s annotation or the APIMAPI",
+			existing.Name, deploymentAPIIDAnnotation)
+		logger.Info("⚠️ "+message, "namespace", req.Namespace)
+		recordWarningEvent(r.Recorder, &deployment, eventReasonAPIMAPIConflict, message)
+		return ctrl.Result{}, nil
+	}
+
+	// APIID and apimService are immutable, so a change to either replaces the APIMAPI. The next reconcile,
+	// triggered by the deletion, creates it again.
+	if existing.Spec.APIID != desired.Spec.APIID || existing.Spec.APIMService != desired.Spec.APIMService {
+		logger.Info("♻️ API ID or APIM service annotation changed; replacing generated APIMAPI",
+			"name", existing.Name, "namespace", existing.Namespace, "oldAPIID", existing.Spec.APIID, "apiID", desired.Spec.APIID)
+		return ctrl.Result{}, client.IgnoreNotFound(r.Delete(ctx, &existing))
+	}
+
+	// Only the fields the annotations control are patched; others, such as adoptionPolicy or owner, may be
+	// set on the generated APIMAPI directly.
+	updated := existing.DeepCopy()
+	updated.Labels = desired.Labels
+	updated.Spec.RoutePrefix = desired.Spec.RoutePrefix
+	updated.Spec.ServiceURL = desired.Spec.ServiceURL
+	updated.Spec.OpenAPIDefinitionURL = desired.Spec.OpenAPIDefinitionURL
+	updated.Spec.Target = desired.Spec.Target
+	updated.Spec.ProductIDs = desired.Spec.ProductIDs
+	updated.Spec.TagIDs = desired.Spec.TagIDs
+	updated.Spec.SubscriptionRequired = desired.Spec.SubscriptionRequired
+	if equality.Semantic.DeepEqual(existing.Spec, updated.Spec) && equality.Semantic.DeepEqual(existing.Labels, updated.Labels) {
+		return ctrl.Result{}, nil
+	}
+	if err := r.Patch(ctx, updated, client.MergeFrom(&existing)); err != nil {
+		return ctrl.Result{}, err
+	}
+	logger.Info("🔄 Updated generated APIMAPI from Deployment annotations", "name", updated.Name, "namespace", updated.Namespace, "apiID", updated.Spec.APIID)
+	return ctrl.Result{}, nil
+}
+
+// apimAPIFromDeployment builds the APIMAPI described by the annotations of deployment. The defaults follow the
+// conventions of the defaulting webhook, since the Helm chart does not deploy it.
+func apimAPIFromDeployment(deployment *appsv1.Deployment) (*apimv1.APIMAPI, error) {
+	annotations := deployment.Annotations
+	apimService := annotations[deploymentAPIMServiceAnnotation]
+	if apimService == "" {
+		return nil, fmt.Errorf("%s is required together with %s", deploymentAPIMServiceAnnotation, deploymentAPIIDAnnotation)
+	}
+
+	subscriptionRequired := true
+	if value, ok := annotations[deploymentSubscriptionRequiredAnnotation]; ok {
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, fmt.Errorf("%s must be true or false, got %q", deploymentSubscriptionRequiredAnnotation, value)
+		}
+		subscriptionRequired = parsed
+	}
+
+	routePrefix := annotations[deploymentRoutePrefixAnnotation]
+	if routePrefix == "" {
+		routePrefix = "/" + deployment.Name
+	}
+	serviceURL := annotations[deploymentServiceURLAnnotation]
+	if serviceURL == "" {
+		serviceURL = fmt.Sprintf("http://%s.%s.svc", deployment.Name, deployment.Namespace)
+	}
+	swaggerPath := annotations[deploymentSwaggerPathAnnotation]
+	if swaggerPath == "" {
+		swaggerPath = "/swagger/v1/swagger.json"
+	}
+
+	var target *apimv1.APIMAPITarget
+	if deployment.Spec.Selector != nil {
+		target = &apimv1.APIMAPITarget{Selector: deployment.Spec.Selector.DeepCopy()}
+	}
+
+	return &apimv1.APIMAPI{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      deployment.Name,
+			Namespace: deployment.Namespace,
+			Labels:    map[string]string{generatedFromLabel: "Deployment"},
+		},
+		Spec: apimv1.APIMAPISpec{
+			APIID:                annotations[deploymentAPIIDAnnotation],
+			APIMService:          apimService,
+			RoutePrefix:          routePrefix,
+			ServiceURL:           serviceURL,
+			OpenAPIDefinitionURL: strings.TrimSuffix(serviceURL, "/") + "/" + strings.TrimPrefix(swaggerPath, "/"),
+			Target:               target,
+			ProductIDs:           splitAnnotationList(annotations[deploymentProductsAnnotation]),
+			TagIDs:               splitAnnotationList(annotations[deploymentTagsAnnotation]),
+			SubscriptionRequired: subscriptionRequired,
+		},
+	}, nil
+}
+
+// splitAnnotationList splits a comma-separated annotation value, dropping blanks.
+func splitAnnotationList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// hasAPIAnnotations reports whether any apim.operator.io/* generation annotation is set on obj.
+func hasAPIAnnotations(obj client.Object) bool {
+	for key := range obj.GetAnnotations() {
+		switch key {
+		case deploymentAPIIDAnnotation, deploymentAPIMServiceAnnotation, deploymentRoutePrefixAnnotation,
+			deploymentServiceURLAnnotation, deploymentSwaggerPathAnnotation, deploymentProductsAnnotation,
+			deploymentTagsAnnotation, deploymentSubscriptionRequiredAnnotation:
+			return true
+		}
+	}
+	return false
+}
+
+func (r *DeploymentAPIReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	// Deployments without annotations are filtered out, except on the update that removes them, so
+	// the generated APIMAPI can be deleted. Changes of the generated APIMAPI itself are watched to undo
+	// edits of the fields the annotations control and to recreate it after a replacement.
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&appsv1.Deployment{}, builder.WithPredicates(predicate.Funcs{
+			CreateFunc: func(e event.CreateEvent) bool {
+				return hasAPIAnnotations(e.Object)
+			},
+			UpdateFunc: func(e event.UpdateEvent) bool {
+				return hasAPIAnnotations(e.ObjectOld) || hasAPIAnnotations(e.ObjectNew)
+			},
+			DeleteFunc: func(e event.DeleteEvent) bool {
+				return false
+			},
+			GenericFunc: func(e event.GenericEvent) bool {
+				return false
+			},
+		}, shardPredicate())).
+		Owns(&apimv1.APIMAPI{}).
+		Named("deploymentapi").
+		Complete(withTracing("Deployment", r))
+}
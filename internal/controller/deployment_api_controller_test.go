@@ -0,0 +1,176 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	apimv1 "github.com/hedinit/azure-apim-operator/api/v1"
+)
+
+func annotatedDeployment(annotations map[string]string) *appsv1.Deployment {
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "orders", Namespace: "shop", UID: types.UID("orders-uid"), Annotations: annotations},
+		Spec:       appsv1.DeploymentSpec{Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "orders"}}},
+	}
+}
+
+func TestAPIMAPIFromDeployment(t *testing.T) {
+	apimApi, err := apimAPIFromDeployment(annotatedDeployment(map[string]string{
+		deploymentAPIIDAnnotation:       "shop-orders",
+		deploymentAPIMServiceAnnotation: "apim-prod",
+		deploymentProductsAnnotation:    "starter, gold,,",
+	}))
+	if err != nil {
+		t.Fatalf("apimAPIFromDeployment() error = %v", err)
+	}
+	spec := apimApi.Spec
+	if spec.APIID != "shop-orders" || spec.APIMService != "apim-prod" || spec.RoutePrefix != "/orders" ||
+		spec.ServiceURL != "http://orders.shop.svc" ||
+		spec.OpenAPIDefinitionURL != "http://orders.shop.svc/swagger/v1/swagger.json" || !spec.SubscriptionRequired {
+		t.Errorf("defaults = %+v", spec)
+	}
+	if len(spec.ProductIDs) != 2 || spec.ProductIDs[0] != "starter" || spec.ProductIDs[1] != "gold" || spec.TagIDs != nil {
+		t.Errorf("productIds = %q, tagIds = %q", spec.ProductIDs, spec.TagIDs)
+	}
+	if spec.Target == nil || spec.Target.Selector.MatchLabels["app"] != "orders" {
+		t.Errorf("target = %+v, want the Deployment selector", spec.Target)
+	}
+
+	apimApi, err = apimAPIFromDeployment(annotatedDeployment(map[string]string{
+		deploymentAPIIDAnnotation:                "shop-orders",
+		deploymentAPIMServiceAnnotation:          "apim-prod",
+		deploymentServiceURLAnnotation:           "https://orders.example.com/",
+		deploymentSwaggerPathAnnotation:          "openapi.json",
+		deploymentRoutePrefixAnnotation:          "/shop/orders",
+		deploymentSubscriptionRequiredAnnotation: "false",
+	}))
+	if err != nil {
+		t.Fatalf("apimAPIFromDeployment() error = %v", err)
+	}
+	if apimApi.Spec.OpenAPIDefinitionURL != "https://orders.example.com/openapi.json" ||
+		apimApi.Spec.RoutePrefix != "/shop/orders" || apimApi.Spec.SubscriptionRequired {
+		t.Errorf("overrides = %+v", apimApi.Spec)
+	}
+
+	for name, annotations := range map[string]map[string]string{
+		"missing APIM service":         {deploymentAPIIDAnnotation: "shop-orders"},
+		"invalid subscription setting": {deploymentAPIIDAnnotation: "shop-orders", deploymentAPIMServiceAnnotation: "apim-prod", deploymentSubscriptionRequiredAnnotation: "maybe"},
+	} {
+		if _, err := apimAPIFromDeployment(annotatedDeployment(annotations)); err == nil {
+			t.Errorf("%s: apimAPIFromDeployment() error = nil", name)
+		}
+	}
+}
+
+func TestDeploymentAPIReconcile(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	if err := apimv1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	key := types.NamespacedName{Name: "orders", Namespace: "shop"}
+	annotations := map[string]string{deploymentAPIIDAnnotation: "shop-orders", deploymentAPIMServiceAnnotation: "apim-prod"}
+
+	reconcile := func(r *DeploymentAPIReconciler) {
+		t.Helper()
+		if _, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: key}); err != nil {
+			t.Fatalf("Reconcile() error = %v", err)
+		}
+	}
+	getAPI := func(c client.Client) (*apimv1.APIMAPI, error) {
+		var apimApi apimv1.APIMAPI
+		err := c.Get(ctx, key, &apimApi)
+		return &apimApi, err
+	}
+
+	t.Run("generates, updates and deletes", func(t *testing.T) {
+		deployment := annotatedDeployment(annotations)
+		c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(deployment).Build()
+		r := &DeploymentAPIReconciler{Client: c, Scheme: scheme}
+
+		reconcile(r)
+		apimApi, err := getAPI(c)
+		if err != nil {
+			t.Fatalf("generated APIMAPI: %v", err)
+		}
+		if !metav1.IsControlledBy(apimApi, deployment) || apimApi.Labels[generatedFromLabel] != "Deployment" {
+			t.Errorf("generated APIMAPI owners = %v, labels = %v", apimApi.OwnerReferences, apimApi.Labels)
+		}
+
+		// Fields the annotations don't control are kept.
+		apimApi.Spec.Owner = "group:default/shop"
+		if err := c.Update(ctx, apimApi); err != nil {
+			t.Fatal(err)
+		}
+		deployment.Annotations[deploymentTagsAnnotation] = "public"
+		if err := c.Update(ctx, deployment); err != nil {
+			t.Fatal(err)
+		}
+		reconcile(r)
+		apimApi, _ = getAPI(c)
+		if len(apimApi.Spec.TagIDs) != 1 || apimApi.Spec.Owner != "group:default/shop" {
+			t.Errorf("updated spec = %+v, want the new tag and the kept owner", apimApi.Spec)
+		}
+
+		deployment.Annotations = nil
+		if err := c.Update(ctx, deployment); err != nil {
+			t.Fatal(err)
+		}
+		reconcile(r)
+		if _, err := getAPI(c); !apierrors.IsNotFound(err) {
+			t.Errorf("APIMAPI after removing the annotations: err = %v, want NotFound", err)
+		}
+	})
+
+	t.Run("leaves a hand-written APIMAPI alone", func(t *testing.T) {
+		existing := &apimv1.APIMAPI{
+			ObjectMeta: metav1.ObjectMeta{Name: "orders", Namespace: "shop"},
+			Spec:       apimv1.APIMAPISpec{APIID: "orders-v1", APIMService: "apim-prod"},
+		}
+		c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(annotatedDeployment(annotations), existing).Build()
+		recorder := record.NewFakeRecorder(1)
+		reconcile(&DeploymentAPIReconciler{Client: c, Scheme: scheme, Recorder: recorder})
+
+		apimApi, _ := getAPI(c)
+		if apimApi.Spec.APIID != "orders-v1" {
+			t.Errorf("APIID = %q, want the hand-written APIMAPI untouched", apimApi.Spec.APIID)
+		}
+		if len(recorder.Events) != 1 {
+			t.Errorf("events = %d, want one %s warning", len(recorder.Events), eventReasonAPIMAPIConflict)
+		}
+	})
+
+	t.Run("replaces the APIMAPI when the API ID changes", func(t *testing.T) {
+		deployment := annotatedDeployment(annotations)
+		c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(deployment).Build()
+		r := &DeploymentAPIReconciler{Client: c, Scheme: scheme}
+		reconcile(r)
+
+		deployment.Annotations[deploymentAPIIDAnnotation] = "shop-orders-v2"
+		if err := c.Update(ctx, deployment); err != nil {
+			t.Fatal(err)
+		}
+		reconcile(r)
+		if _, err := getAPI(c); !apierrors.IsNotFound(err) {
+			t.Fatalf("APIMAPI after API ID change: err = %v, want NotFound", err)
+		}
+		reconcile(r)
+		if apimApi, err := getAPI(c); err != nil || apimApi.Spec.APIID != "shop-orders-v2" {
+			t.Errorf("recreated APIMAPI = %+v, err = %v", apimApi.Spec, err)
+		}
+	})
+}
@@ -0,0 +1,56 @@
+package controller
+
+import (
+	"fmt"
+	"sort"
+
+	"k8s.io/client-go/discovery"
+
+	apimv1 "github.com/hedinit/azure-apim-operator/api/v1"
+)
+
+// legacyGroup is the API group the CRDs were served under before they moved to apim.operator.io.
+const legacyGroup = "apim.hedinit.io"
+
+// legacyKinds were scaffolded in the apim.operator.io group but are not reconciled by any controller.
+var legacyKinds = map[string]struct{}{
+	"ImportAPI": {},
+	"DeployAPI": {},
+}
+
+// FindLegacyAPIs returns a migration hint for every deprecated API group or kind the cluster still serves.
+// Resources of these kinds never reach the operator's webhooks, so this startup check is the only place
+// their users can be told to migrate before the next API version removes them.
+func FindLegacyAPIs(dc discovery.DiscoveryInterface) ([]string, error) {
+	groups, err := dc.ServerGroups()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list API groups: %w", err)
+	}
+
+	var messages []string
+	for _, group := range groups.Groups {
+		switch group.Name {
+		case legacyGroup:
+			messages = append(messages, fmt.Sprintf(
+				"API group %s is deprecated and no longer reconciled; recreate its resources in %s and delete the old CRDs",
+				legacyGroup, apimv1.GroupVersion.Group))
+		case apimv1.GroupVersion.Group:
+			for _, version := range group.Versions {
+				resources, err := dc.ServerResourcesForGroupVersion(version.GroupVersion)
+				if err != nil {
+					return nil, fmt.Errorf("failed to list resources of %s: %w", version.GroupVersion, err)
+				}
+				for _, resource := range resources.APIResources {
+					if _, ok := legacyKinds[resource.Kind]; ok {
+						messages = append(messages, fmt.Sprintf(
+							"kind %s in %s is deprecated and not reconciled; use APIMAPI instead and delete the %s CRD",
+							resource.Kind, version.GroupVersion, resource.Name))
+					}
+				}
+			}
+		}
+	}
+	// Discovery does not guarantee the order of groups, so sort to log the same warnings on every start.
+	sort.Strings(messages)
+	return messages, nil
+}
@@ -0,0 +1,56 @@
+package controller
+
+import (
+	"strings"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	clienttesting "k8s.io/client-go/testing"
+)
+
+func TestFindLegacyAPIs(t *testing.T) {
+	tests := []struct {
+		name      string
+		resources []*metav1.APIResourceList
+		want      []string
+	}{
+		{
+			name: "current APIs only",
+			resources: []*metav1.APIResourceList{
+				{GroupVersion: "apim.operator.io/v1", APIResources: []metav1.APIResource{{Name: "apimapis", Kind: "APIMAPI"}}},
+			},
+		},
+		{
+			name: "legacy group and kinds",
+			resources: []*metav1.APIResourceList{
+				{GroupVersion: "apim.hedinit.io/v1", APIResources: []metav1.APIResource{{Name: "apimapis", Kind: "APIMAPI"}}},
+				{GroupVersion: "apim.operator.io/v1", APIResources: []metav1.APIResource{
+					{Name: "apimapis", Kind: "APIMAPI"},
+					{Name: "importapis", Kind: "ImportAPI"},
+					{Name: "deployapis", Kind: "DeployAPI"},
+				}},
+			},
+			want: []string{"apim.hedinit.io", "DeployAPI", "ImportAPI"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dc := &fakediscovery.FakeDiscovery{Fake: &clienttesting.Fake{Resources: tt.resources}}
+
+			messages, err := FindLegacyAPIs(dc)
+			if err != nil {
+				t.Fatalf("FindLegacyAPIs() error = %v", err)
+			}
+			if len(messages) != len(tt.want) {
+				t.Fatalf("FindLegacyAPIs() = %v, want %d messages", messages, len(tt.want))
+			}
+			for i, want := range tt.want {
+				if !strings.Contains(messages[i], want) {
+					t.Errorf("message %d = %q, want it to mention %s", i, messages[i], want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,74 @@
+package controller
+
+import (
+	"context"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	apimv1 "github.com/hedinit/azure-apim-operator/api/v1"
+)
+
+// cacheCollectTimeout bounds the cache reads of a single scrape of the cache-backed collectors.
+const cacheCollectTimeout = 5 * time.Second
+
+// driftedResourcesDesc describes apim_drifted_resources.
+var driftedResourcesDesc = prometheus.NewDesc(
+	"apim_drifted_resources",
+	"Number of managed resources whose desired state differs from what was last applied to Azure APIM.",
+	[]string{"kind", "apim_service"}, nil,
+)
+
+// driftCollector counts drifted resources from the manager's cache on every scrape, so the gauge
+// can't go stale when resources are deleted or the operator restarts.
+type driftCollector struct {
+	reader client.Reader
+}
+
+// RegisterDriftCollector registers the apim_drifted_resources gauge, read through reader on each scrape.
+// reader should be the manager's cached client.
+func RegisterDriftCollector(reader client.Reader) error {
+	return metrics.Registry.Register(&driftCollector{reader: reader})
+}
+
+// Describe implements prometheus.Collector.
+func (c *driftCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- driftedResourcesDesc
+}
+
+// Collect implements prometheus.Collector. Every APIM service with managed APIs gets a series,
+// so dashboards show zero rather than no data when nothing has drifted.
+func (c *driftCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), cacheCollectTimeout)
+	defer cancel()
+
+	var deployments apimv1.APIMAPIDeploymentList
+	if err := c.reader.List(ctx, &deployments); err != nil {
+		ch <- prometheus.NewInvalidMetric(driftedResourcesDesc, err)
+		return
+	}
+
+	drifted := map[string]int{}
+	for i := range deployments.Items {
+		deployment := &deployments.Items[i]
+		if !ownsNamespace(deployment.Namespace) {
+			continue
+		}
+		drifted[deployment.Spec.APIMService] += 0
+		if isDrifted(deployment) {
+			drifted[deployment.Spec.APIMService]++
+		}
+	}
+	for service, count := range drifted {
+		ch <- prometheus.MustNewConstMetric(driftedResourcesDesc, prometheus.GaugeValue, float64(count), kindAPIMAPIDeployment, service)
+	}
+}
+
+// isDrifted reports whether the desired state of a deployment has not been applied to APIM, e.g. because
+// the import keeps failing, awaits approval or only ran in dry-run mode.
+// Deployments that have not computed a desired state yet are not counted.
+func isDrifted(deployment *apimv1.APIMAPIDeployment) bool {
+	return deployment.Status.DesiredHash != "" && deployment.Status.DesiredHash != deployment.Status.AppliedHash
+}
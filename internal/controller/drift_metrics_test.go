@@ -0,0 +1,45 @@
+package controller
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	apimv1 "github.com/hedinit/azure-apim-operator/api/v1"
+)
+
+func TestDriftCollector(t *testing.T) {
+	deployment := func(name, service, desired, applied string) client.Object {
+		return &apimv1.APIMAPIDeployment{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "payments"},
+			Spec:       apimv1.APIMAPIDeploymentSpec{APIMService: service},
+			Status:     apimv1.APIMAPIDeploymentStatus{DesiredHash: desired, AppliedHash: applied},
+		}
+	}
+	scheme := runtime.NewScheme()
+	if err := apimv1.AddToScheme(scheme); err != nil {
+		t.Fatalf("AddToScheme() error = %v", err)
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		deployment("in-sync", "apim-a", "h1", "h1"),
+		deployment("pending", "apim-a", "h2", "h1"),
+		deployment("never-applied", "apim-a", "h3", ""),
+		deployment("new", "apim-a", "", ""),
+		deployment("other-service", "apim-b", "h4", "h4"),
+	).Build()
+
+	want := `
+# HELP apim_drifted_resources Number of managed resources whose desired state differs from what was last applied to Azure APIM.
+# TYPE apim_drifted_resources gauge
+apim_drifted_resources{apim_service="apim-a",kind="APIMAPIDeployment"} 2
+apim_drifted_resources{apim_service="apim-b",kind="APIMAPIDeployment"} 0
+`
+	if err := testutil.CollectAndCompare(&driftCollector{reader: c}, strings.NewReader(want)); err != nil {
+		t.Error(err)
+	}
+}
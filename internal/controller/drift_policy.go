@@ -0,0 +1,19 @@
+package controller
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// driftPolicyAnnotation on an APIMAPI, APIMProduct or APIMInboundPolicy set to driftPolicyOverwrite makes
+	// writes overwrite changes made to the APIM entity outside the operator. Without it, such a change fails the
+	// write with the ModifiedOutsideOperator reason, and the entity is only written again by a later reconcile.
+	driftPolicyAnnotation = "apim.operator.io/drift-policy"
+	driftPolicyOverwrite  = "Overwrite"
+)
+
+// overwritesDrift reports whether obj asks for changes made to its APIM entity outside the operator to be
+// overwritten right away.
+func overwritesDrift(obj metav1.Object) bool {
+	return obj.GetAnnotations()[driftPolicyAnnotation] == driftPolicyOverwrite
+}
@@ -0,0 +1,39 @@
+package controller
+
+import (
+	"fmt"
+	"time"
+
+	"k8s.io/client-go/tools/record"
+)
+
+const (
+	// DefaultEventBurst is the number of Events an object can emit before it is rate limited.
+	DefaultEventBurst = 10
+	// DefaultEventRefillInterval is how often a rate-limited object regains one Event.
+	DefaultEventRefillInterval = 5 * time.Minute
+
+	// eventAggregateThreshold is the number of similar Events of an object, differing only in their message,
+	// after which later ones are combined into a single Event.
+	eventAggregateThreshold = 5
+	// eventAggregateWindow is how long similar Events count towards eventAggregateThreshold.
+	eventAggregateWindow = 10 * time.Minute
+)
+
+// NewEventBroadcaster returns an event broadcaster that keeps Event volume bounded when resources flap.
+// Repeated Events of an object are aggregated, and each object may emit burst Events before it is limited
+// to one per refillInterval. Events over the limit are dropped by the client instead of reaching the API server.
+func NewEventBroadcaster(burst int, refillInterval time.Duration) (record.EventBroadcaster, error) {
+	if burst <= 0 {
+		return nil, fmt.Errorf("event burst must be positive, got %d", burst)
+	}
+	if refillInterval <= 0 {
+		return nil, fmt.Errorf("event refill interval must be positive, got %s", refillInterval)
+	}
+	return record.NewBroadcasterWithCorrelatorOptions(record.CorrelatorOptions{
+		BurstSize:            burst,
+		QPS:                  float32(1 / refillInterval.Seconds()),
+		MaxEvents:            eventAggregateThreshold,
+		MaxIntervalInSeconds: int(eventAggregateWindow.Seconds()),
+	}), nil
+}
@@ -0,0 +1,21 @@
+package controller
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNewEventBroadcaster(t *testing.T) {
+	broadcaster, err := NewEventBroadcaster(DefaultEventBurst, DefaultEventRefillInterval)
+	if err != nil {
+		t.Fatalf("NewEventBroadcaster() with defaults: %v", err)
+	}
+	broadcaster.Shutdown()
+
+	if _, err := NewEventBroadcaster(0, time.Minute); err == nil {
+		t.Error("NewEventBroadcaster() accepted a zero burst")
+	}
+	if _, err := NewEventBroadcaster(1, 0); err == nil {
+		t.Error("NewEventBroadcaster() accepted a zero refill interval")
+	}
+}
@@ -0,0 +1,28 @@
+package controller
+
+import (
+	"context"
+
+	apimv1 "github.com/hedinit/azure-apim-operator/api/v1"
+	"github.com/hedinit/azure-apim-operator/internal/apim"
+	"github.com/hedinit/azure-apim-operator/internal/logger"
+)
+
+// emitImportEvent exports the outcome of an import as an OTLP log event, together with the
+// Azure request ID of the last APIM call made with ctx.
+func emitImportEvent(ctx context.Context, deployment *apimv1.APIMAPIDeployment, result, reason string, err error) {
+	tags := telemetryTagsFor(deployment.Spec.APIMService)
+	logger.EmitImportEvent(ctx, logger.ImportEvent{
+		Namespace:      deployment.Namespace,
+		Name:           deployment.Name,
+		APIMService:    deployment.Spec.APIMService,
+		APIID:          deployment.Spec.APIID,
+		Result:         result,
+		Reason:         reason,
+		Env:            tags.Env,
+		Team:           tags.Team,
+		Service:        tags.Service,
+		AzureRequestID: apim.LastRequestID(ctx),
+		Err:            err,
+	})
+}
@@ -0,0 +1,191 @@
+package controller
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	apimv1 "github.com/hedinit/azure-apim-operator/api/v1"
+)
+
+const (
+	importResultSuccess = "success"
+	importResultFailure = "failure"
+	importResultDryRun  = "dry_run"
+
+	// importReasonNone is the reason label of imports that did not fail.
+	importReasonNone = "none"
+
+	importReasonMissingIdentity      = "missing_identity"
+	importReasonToken                = "token"
+	importReasonAdoptionCheck        = "adoption_check"
+	importReasonImport               = "import"
+	importReasonServiceURL           = "service_url"
+	importReasonSubscriptionRequired = "subscription_required"
+	importReasonDocumentation        = "documentation"
+	importReasonProductAssignment    = "product_assignment"
+	importReasonTagAssignment        = "tag_assignment"
+	importReasonServiceDetails       = "service_details"
+	importReasonAPICenter            = "api_center"
+	importReasonRevision             = "revision"
+	importReasonSmokeCheck           = "smoke_check"
+	importReasonRelease              = "release"
+
+	assignmentKindProduct = "product"
+	assignmentKindTag     = "tag"
+
+	// Values of the kind label of the per-resource sync metrics.
+	kindAPIMAPIDeployment = "APIMAPIDeployment"
+	kindAPIMApiVersionSet = "APIMApiVersionSet"
+	kindAPIMProduct       = "APIMProduct"
+	kindAPIMTag           = "APIMTag"
+	kindAPIMInboundPolicy = "APIMInboundPolicy"
+	kindAPIMSubscription  = "APIMSubscription"
+	kindAPIMNamedValue    = "APIMNamedValue"
+	kindAPIMBackend       = "APIMBackend"
+	kindAPIMCluster       = "APIMCluster"
+
+	// apiIDLabelOverflow replaces API IDs beyond the cardinality limit.
+	apiIDLabelOverflow = "other"
+)
+
+var (
+	// apiImportTotal counts import attempts by outcome. reason names the APIM step that failed.
+	apiImportTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "apim_api_import_total",
+		Help: "Number of API import attempts against Azure APIM, by result and failure reason.",
+	}, []string{"apim_service", "api_id", "env", "team", "service", "result", "reason"})
+
+	// apiImportDuration measures how long the APIM calls of a successful import took, from acquiring
+	// the token until the API was fully configured.
+	apiImportDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "apim_api_import_duration_seconds",
+		Help:    "Duration of successful API imports into Azure APIM in seconds.",
+		Buckets: prometheus.ExponentialBuckets(0.5, 2, 12),
+	}, []string{"apim_service", "api_id", "env", "team", "service"})
+
+	// assignmentFailuresTotal counts failed product and tag assignments of imported APIs.
+	assignmentFailuresTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "apim_assignment_failures_total",
+		Help: "Number of failed assignments of APIs to APIM products and tags, by kind.",
+	}, []string{"apim_service", "api_id", "env", "team", "service", "kind"})
+
+	// resourceLastSuccessfulSync holds when each managed object was last synced to APIM successfully.
+	resourceLastSuccessfulSync = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "apim_resource_last_successful_sync_timestamp",
+		Help: "Unix time of the last successful sync of a managed resource to Azure APIM.",
+	}, []string{"kind", "namespace", "name"})
+
+	// resourceConsecutiveFailures counts the failed syncs of each managed object since its last success.
+	resourceConsecutiveFailures = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "apim_resource_consecutive_failures",
+		Help: "Number of failed syncs of a managed resource to Azure APIM since its last successful sync.",
+	}, []string{"kind", "namespace", "name"})
+)
+
+var (
+	apiIDLabelMu sync.Mutex
+	// apiIDLabelLimit is the number of distinct API IDs that get their own api_id label value.
+	// Zero leaves api_id empty, which Prometheus treats as an absent label.
+	apiIDLabelLimit int
+	apiIDLabelSeen  = map[string]struct{}{}
+)
+
+func init() {
+	metrics.Registry.MustRegister(apiImportTotal, apiImportDuration, assignmentFailuresTotal,
+		resourceLastSuccessfulSync, resourceConsecutiveFailures)
+}
+
+// SetAPIIDMetricLabelLimit enables the api_id label on the import metrics for up to limit distinct
+// APIs per process. Later APIs share the value "other", so a large fleet can't blow up the series count.
+// A limit of zero disables the label.
+func SetAPIIDMetricLabelLimit(limit int) {
+	apiIDLabelMu.Lock()
+	defer apiIDLabelMu.Unlock()
+	apiIDLabelLimit = limit
+	apiIDLabelSeen = map[string]struct{}{}
+}
+
+// apiIDLabel returns the api_id label value for an API, applying the cardinality limit.
+// API IDs are only unique per APIM service, so the limit counts service and ID pairs.
+func apiIDLabel(apimService, apiID string) string {
+	apiIDLabelMu.Lock()
+	defer apiIDLabelMu.Unlock()
+
+	if apiIDLabelLimit <= 0 {
+		return ""
+	}
+	key := apimService + "/" + apiID
+	if _, ok := apiIDLabelSeen[key]; ok {
+		return apiID
+	}
+	if len(apiIDLabelSeen) >= apiIDLabelLimit {
+		return apiIDLabelOverflow
+	}
+	apiIDLabelSeen[key] = struct{}{}
+	return apiID
+}
+
+// metricLabels returns the apim_service, api_id, env, team and service label values for a deployment.
+// The last three are the telemetry tags of its APIMService.
+func metricLabels(deployment *apimv1.APIMAPIDeployment) []string {
+	tags := telemetryTagsFor(deployment.Spec.APIMService)
+	return []string{
+		deployment.Spec.APIMService,
+		apiIDLabel(deployment.Spec.APIMService, deployment.Spec.APIID),
+		tags.Env,
+		tags.Team,
+		tags.Service,
+	}
+}
+
+// recordImportFailure counts an import attempt that failed at the step named by reason
+// and emits it as an import event.
+func recordImportFailure(ctx context.Context, deployment *apimv1.APIMAPIDeployment, reason string, err error) {
+	labels := metricLabels(deployment)
+	apiImportTotal.WithLabelValues(append(labels, importResultFailure, reason)...).Inc()
+	recordSyncFailure(kindAPIMAPIDeployment, deployment)
+	emitImportEvent(ctx, deployment, importResultFailure, reason, err)
+}
+
+// recordImportSuccess counts a completed import and observes its duration. Dry runs are counted
+// separately and left out of the histogram, since they make no real APIM calls. Both emit an import event.
+func recordImportSuccess(ctx context.Context, deployment *apimv1.APIMAPIDeployment, dryRun bool, started time.Time) {
+	labels := metricLabels(deployment)
+	recordSyncSuccess(kindAPIMAPIDeployment, deployment)
+	if dryRun {
+		apiImportTotal.WithLabelValues(append(labels, importResultDryRun, importReasonNone)...).Inc()
+		emitImportEvent(ctx, deployment, importResultDryRun, importReasonNone, nil)
+		return
+	}
+	apiImportTotal.WithLabelValues(append(labels, importResultSuccess, importReasonNone)...).Inc()
+	emitImportEvent(ctx, deployment, importResultSuccess, importReasonNone, nil)
+	apiImportDuration.WithLabelValues(labels...).Observe(time.Since(started).Seconds())
+}
+
+// recordAssignmentFailure counts a failed product or tag assignment.
+func recordAssignmentFailure(deployment *apimv1.APIMAPIDeployment, kind string) {
+	assignmentFailuresTotal.WithLabelValues(append(metricLabels(deployment), kind)...).Inc()
+}
+
+// recordSyncSuccess marks obj as synced now and resets its consecutive failures.
+func recordSyncSuccess(kind string, obj client.Object) {
+	resourceLastSuccessfulSync.WithLabelValues(kind, obj.GetNamespace(), obj.GetName()).SetToCurrentTime()
+	resourceConsecutiveFailures.WithLabelValues(kind, obj.GetNamespace(), obj.GetName()).Set(0)
+}
+
+// recordSyncFailure counts a failed sync of obj. Objects that never synced get no timestamp series,
+// so alerts on them have to use the failure count.
+func recordSyncFailure(kind string, obj client.Object) {
+	resourceConsecutiveFailures.WithLabelValues(kind, obj.GetNamespace(), obj.GetName()).Inc()
+}
+
+// forgetSyncMetrics drops the sync series of a deleted object, so it doesn't keep alerting.
+func forgetSyncMetrics(kind, namespace, name string) {
+	resourceLastSuccessfulSync.DeleteLabelValues(kind, namespace, name)
+	resourceConsecutiveFailures.DeleteLabelValues(kind, namespace, name)
+}
@@ -0,0 +1,109 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	dto "github.com/prometheus/client_model/go"
+
+	apimv1 "github.com/hedinit/azure-apim-operator/api/v1"
+)
+
+// importDurationSamples returns the number of observations in the import duration histogram for the given labels.
+func importDurationSamples(t *testing.T, apimService, apiID string) uint64 {
+	t.Helper()
+	var m dto.Metric
+	observer, err := apiImportDuration.GetMetricWithLabelValues(apimService, apiID, "", "", "")
+	if err != nil {
+		t.Fatalf("GetMetricWithLabelValues() error = %v", err)
+	}
+	if err := observer.(prometheus.Metric).Write(&m); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	return m.GetHistogram().GetSampleCount()
+}
+
+func TestRecordImportMetrics(t *testing.T) {
+	SetAPIIDMetricLabelLimit(0)
+	deployment := &apimv1.APIMAPIDeployment{Spec: apimv1.APIMAPIDeploymentSpec{APIMService: "metrics-apim", APIID: "orders"}}
+
+	success := testutil.ToFloat64(apiImportTotal.WithLabelValues("metrics-apim", "", "", "", "", importResultSuccess, importReasonNone))
+	dryRun := testutil.ToFloat64(apiImportTotal.WithLabelValues("metrics-apim", "", "", "", "", importResultDryRun, importReasonNone))
+	failure := testutil.ToFloat64(apiImportTotal.WithLabelValues("metrics-apim", "", "", "", "", importResultFailure, importReasonImport))
+	samples := importDurationSamples(t, "metrics-apim", "")
+
+	recordImportSuccess(context.Background(), deployment, false, time.Now().Add(-time.Second))
+	recordImportSuccess(context.Background(), deployment, true, time.Now())
+	recordImportFailure(context.Background(), deployment, importReasonImport, errors.New("import failed"))
+
+	if got := testutil.ToFloat64(apiImportTotal.WithLabelValues("metrics-apim", "", "", "", "", importResultSuccess, importReasonNone)); got != success+1 {
+		t.Errorf("success count = %v, want %v", got, success+1)
+	}
+	if got := testutil.ToFloat64(apiImportTotal.WithLabelValues("metrics-apim", "", "", "", "", importResultDryRun, importReasonNone)); got != dryRun+1 {
+		t.Errorf("dry-run count = %v, want %v", got, dryRun+1)
+	}
+	if got := testutil.ToFloat64(apiImportTotal.WithLabelValues("metrics-apim", "", "", "", "", importResultFailure, importReasonImport)); got != failure+1 {
+		t.Errorf("failure count = %v, want %v", got, failure+1)
+	}
+	if got := importDurationSamples(t, "metrics-apim", ""); got != samples+1 {
+		t.Errorf("duration samples = %d, want %d; dry runs must not be observed", got, samples+1)
+	}
+}
+
+func TestRecordSyncMetrics(t *testing.T) {
+	product := &apimv1.APIMProduct{}
+	product.Namespace, product.Name = "payments", "gold"
+	failures := resourceConsecutiveFailures.WithLabelValues(kindAPIMProduct, "payments", "gold")
+
+	recordSyncFailure(kindAPIMProduct, product)
+	recordSyncFailure(kindAPIMProduct, product)
+	if got := testutil.ToFloat64(failures); got != 2 {
+		t.Errorf("consecutive failures = %v, want 2", got)
+	}
+	if resourceLastSuccessfulSync.DeleteLabelValues(kindAPIMProduct, "payments", "gold") {
+		t.Error("last successful sync was set before the first success")
+	}
+
+	before := float64(time.Now().Unix())
+	recordSyncSuccess(kindAPIMProduct, product)
+	if got := testutil.ToFloat64(failures); got != 0 {
+		t.Errorf("consecutive failures after success = %v, want 0", got)
+	}
+	if got := testutil.ToFloat64(resourceLastSuccessfulSync.WithLabelValues(kindAPIMProduct, "payments", "gold")); got < before {
+		t.Errorf("last successful sync = %v, want at least %v", got, before)
+	}
+
+	forgetSyncMetrics(kindAPIMProduct, "payments", "gold")
+	if resourceConsecutiveFailures.DeleteLabelValues(kindAPIMProduct, "payments", "gold") {
+		t.Error("consecutive failures series survived forgetSyncMetrics")
+	}
+}
+
+func TestAPIIDLabel(t *testing.T) {
+	t.Cleanup(func() { SetAPIIDMetricLabelLimit(0) })
+
+	SetAPIIDMetricLabelLimit(0)
+	if got := apiIDLabel("apim-a", "orders"); got != "" {
+		t.Errorf("apiIDLabel() with label disabled = %q, want empty", got)
+	}
+
+	SetAPIIDMetricLabelLimit(2)
+	steps := []struct {
+		apimService, apiID, want string
+	}{
+		{"apim-a", "orders", "orders"},
+		{"apim-b", "orders", "orders"},
+		{"apim-a", "payments", apiIDLabelOverflow},
+		{"apim-a", "orders", "orders"},
+		{"apim-b", "orders", "orders"},
+	}
+	for _, step := range steps {
+		if got := apiIDLabel(step.apimService, step.apiID); got != step.want {
+			t.Errorf("apiIDLabel(%q, %q) = %q, want %q", step.apimService, step.apiID, got, step.want)
+		}
+	}
+}
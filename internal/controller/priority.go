@@ -0,0 +1,94 @@
+package controller
+
+import (
+	"context"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/priorityqueue"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	// priorityLabel marks a resource as high priority when set to priorityHigh.
+	priorityLabel = "apim.operator.io/priority"
+	priorityHigh  = "high"
+
+	// highPriority is the work queue priority for high-priority resources. Everything else is queued
+	// with the default priority 0, so high-priority items are picked first when the queue is deep.
+	highPriority = 100
+)
+
+var (
+	highPriorityNamespacesMu sync.RWMutex
+	highPriorityNamespaces   = map[string]struct{}{}
+)
+
+// SetHighPriorityNamespaces configures the namespaces whose resources are reconciled ahead of others.
+// It only has an effect when the manager runs its controllers with a priority queue.
+func SetHighPriorityNamespaces(namespaces []string) {
+	set := make(map[string]struct{}, len(namespaces))
+	for _, namespace := range namespaces {
+		if namespace != "" {
+			set[namespace] = struct{}{}
+		}
+	}
+
+	highPriorityNamespacesMu.Lock()
+	defer highPriorityNamespacesMu.Unlock()
+	highPriorityNamespaces = set
+}
+
+// isHighPriority reports whether obj lives in a high-priority namespace or carries the priority label.
+func isHighPriority(obj client.Object) bool {
+	if obj.GetLabels()[priorityLabel] == priorityHigh {
+		return true
+	}
+
+	highPriorityNamespacesMu.RLock()
+	defer highPriorityNamespacesMu.RUnlock()
+	_, ok := highPriorityNamespaces[obj.GetNamespace()]
+	return ok
+}
+
+// priorityEnqueueHandler enqueues a request for the event object, like handler.EnqueueRequestForObject,
+// but adds high-priority objects ahead of the rest when the controller uses a priority queue.
+func priorityEnqueueHandler() handler.EventHandler {
+	return handler.Funcs{
+		CreateFunc: func(_ context.Context, e event.CreateEvent, q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+			enqueueWithPriority(q, e.Object)
+		},
+		UpdateFunc: func(_ context.Context, e event.UpdateEvent, q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+			enqueueWithPriority(q, e.ObjectNew)
+		},
+		DeleteFunc: func(_ context.Context, e event.DeleteEvent, q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+			enqueueWithPriority(q, e.Object)
+		},
+		GenericFunc: func(_ context.Context, e event.GenericEvent, q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+			enqueueWithPriority(q, e.Object)
+		},
+	}
+}
+
+func enqueueWithPriority(q workqueue.TypedRateLimitingInterface[reconcile.Request], obj client.Object) {
+	if obj == nil {
+		return
+	}
+	req := reconcile.Request{NamespacedName: types.NamespacedName{Namespace: obj.GetNamespace(), Name: obj.GetName()}}
+
+	priorityQueue, ok := q.(priorityqueue.PriorityQueue[reconcile.Request])
+	if !ok {
+		q.Add(req)
+		return
+	}
+
+	var priority int
+	if isHighPriority(obj) {
+		priority = highPriority
+	}
+	priorityQueue.AddWithOpts(priorityqueue.AddOpts{Priority: priority}, req)
+}
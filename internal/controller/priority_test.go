@@ -0,0 +1,59 @@
+package controller
+
+import (
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/controller/priorityqueue"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	apimv1 "github.com/hedinit/azure-apim-operator/api/v1"
+)
+
+func TestIsHighPriority(t *testing.T) {
+	SetHighPriorityNamespaces([]string{"prod", ""})
+	t.Cleanup(func() { SetHighPriorityNamespaces(nil) })
+
+	tests := []struct {
+		name string
+		meta metav1.ObjectMeta
+		want bool
+	}{
+		{name: "high-priority namespace", meta: metav1.ObjectMeta{Namespace: "prod", Name: "api"}, want: true},
+		{name: "other namespace", meta: metav1.ObjectMeta{Namespace: "dev", Name: "api"}, want: false},
+		{
+			name: "priority label",
+			meta: metav1.ObjectMeta{Namespace: "dev", Name: "api", Labels: map[string]string{priorityLabel: priorityHigh}},
+			want: true,
+		},
+		{
+			name: "unknown label value",
+			meta: metav1.ObjectMeta{Namespace: "dev", Name: "api", Labels: map[string]string{priorityLabel: "low"}},
+			want: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isHighPriority(&apimv1.APIMAPI{ObjectMeta: tt.meta}); got != tt.want {
+				t.Fatalf("isHighPriority() = %t, want %t", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEnqueueWithPriorityOrdersHighPriorityFirst(t *testing.T) {
+	SetHighPriorityNamespaces([]string{"prod"})
+	t.Cleanup(func() { SetHighPriorityNamespaces(nil) })
+
+	q := priorityqueue.New[reconcile.Request]("test")
+	defer q.ShutDown()
+
+	enqueueWithPriority(q, &apimv1.APIMAPI{ObjectMeta: metav1.ObjectMeta{Namespace: "dev", Name: "first"}})
+	enqueueWithPriority(q, &apimv1.APIMAPI{ObjectMeta: metav1.ObjectMeta{Namespace: "prod", Name: "second"}})
+
+	item, priority, _ := q.GetWithPriority()
+	if item.Name != "second" || priority != highPriority {
+		t.Fatalf("expected prod item first with priority %d, got %s with priority %d", highPriority, item.Name, priority)
+	}
+}
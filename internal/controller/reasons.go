@@ -0,0 +1,207 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apimv1 "github.com/hedinit/azure-apim-operator/api/v1"
+	"github.com/hedinit/azure-apim-operator/internal/apim"
+	"github.com/hedinit/azure-apim-operator/internal/identity"
+	"github.com/hedinit/azure-apim-operator/internal/redact"
+)
+
+// failureReason classifies a failed import step as one of the apimv1 Reason values.
+func failureReason(step string, err error) string {
+	switch step {
+	case importReasonMissingIdentity, importReasonToken:
+		return apimv1.ReasonAuthFailed
+	case importReasonSmokeCheck:
+		return apimv1.ReasonSmokeCheckFailed
+	}
+	var respErr *apim.ResponseError
+	if step == importReasonImport && errors.As(err, &respErr) && respErr.StatusCode == http.StatusBadRequest {
+		// APIM answers definitions it can't parse with 400 ValidationError.
+		return apimv1.ReasonSpecInvalid
+	}
+	return azureFailureReason(err)
+}
+
+// policyFailureReason classifies a failed policy upsert. APIM answers a policy that is being changed
+// concurrently with 409.
+func policyFailureReason(err error) string {
+	var respErr *apim.ResponseError
+	if errors.As(err, &respErr) && respErr.StatusCode == http.StatusConflict {
+		return apimv1.ReasonConflictingPolicy
+	}
+	return azureFailureReason(err)
+}
+
+// azureFailureReason classifies a failed call to the Azure management API by its response status.
+func azureFailureReason(err error) string {
+	if apim.IsPreconditionFailed(err) {
+		return apimv1.ReasonModifiedOutsideOperator
+	}
+	var respErr *apim.ResponseError
+	if errors.As(err, &respErr) {
+		switch respErr.StatusCode {
+		case http.StatusUnauthorized, http.StatusForbidden:
+			return apimv1.ReasonAuthFailed
+		case http.StatusTooManyRequests:
+			return apimv1.ReasonThrottled
+		}
+	}
+	return apimv1.ReasonAzureError
+}
+
+// invalidateRejectedToken makes tokens drop its token when Azure answered err with 401, which azureFailureReason
+// classifies as AuthFailed. The token expired early, the clocks are skewed or it was revoked, and resending it
+// would fail until it is due for refresh. A 403 is a missing role assignment that a new token doesn't fix.
+func invalidateRejectedToken(tokens identity.TokenProvider, err error) {
+	if tokens == nil || azureFailureReason(err) != apimv1.ReasonAuthFailed {
+		return
+	}
+	var respErr *apim.ResponseError
+	if errors.As(err, &respErr) && respErr.StatusCode == http.StatusUnauthorized {
+		tokens.Invalidate()
+	}
+}
+
+// DefaultAzureRetryInterval is how long a deployment waits to be retried after a failed call to the Azure
+// management API, unless SetAzureRetryInterval sets another interval.
+const DefaultAzureRetryInterval = 60 * time.Second
+
+// azureRetryIntervalNanos holds the interval set with SetAzureRetryInterval, or zero for the default.
+var azureRetryIntervalNanos atomic.Int64
+
+// SetAzureRetryInterval sets how long a deployment waits to be retried after a failed call to the Azure
+// management API that did not say when to retry. Zero restores DefaultAzureRetryInterval. It can be changed
+// while the manager runs.
+func SetAzureRetryInterval(interval time.Duration) error {
+	if interval < 0 {
+		return fmt.Errorf("azure retry interval must not be negative, got %s", interval)
+	}
+	azureRetryIntervalNanos.Store(int64(interval))
+	return nil
+}
+
+// azureRetryInterval returns the interval set with SetAzureRetryInterval.
+func azureRetryInterval() time.Duration {
+	if interval := time.Duration(azureRetryIntervalNanos.Load()); interval > 0 {
+		return interval
+	}
+	return DefaultAzureRetryInterval
+}
+
+// azureRetryDelay returns how long to wait before retrying after err: the Retry-After Azure sent with a
+// throttled or unavailable response, or azureRetryInterval. A token Azure rejected was already dropped by
+// invalidateRejectedToken, so the retry gets a new one.
+func azureRetryDelay(err error) time.Duration {
+	var respErr *apim.ResponseError
+	if errors.As(err, &respErr) && respErr.RetryAfter > 0 {
+		return respErr.RetryAfter
+	}
+	return azureRetryInterval()
+}
+
+// setReadyCondition sets the Ready condition of an APIMAPI in memory. Callers persist it with applyAPIMAPIStatus.
+func setReadyCondition(apimApi *apimv1.APIMAPI, status metav1.ConditionStatus, reason, message string) {
+	setReady(&apimApi.Status.Conditions, &apimApi.Status.ObservedGeneration, apimApi.Generation, status, reason, message)
+}
+
+// setReady sets the Ready condition in the conditions of an object at generation. Every kind reports
+// Ready the same way, so GitOps tools can assess their health with one rule.
+// A True or False condition is the outcome of syncing generation, which is then recorded in
+// observedGeneration; kstatus and Flux treat the object as in progress until it matches metadata.generation.
+func setReady(conditions *[]metav1.Condition, observedGeneration *int64, generation int64, status metav1.ConditionStatus, reason, message string) {
+	apimeta.SetStatusCondition(conditions, metav1.Condition{
+		Type:               conditionTypeReady,
+		Status:             status,
+		Reason:             reason,
+		Message:            message,
+		ObservedGeneration: generation,
+	})
+	if status != metav1.ConditionUnknown {
+		*observedGeneration = generation
+	}
+}
+
+// warnNotReady sets the Ready condition of obj to False and records the failure as a Warning Event.
+func warnNotReady(recorder record.EventRecorder, obj client.Object, conditions *[]metav1.Condition, observedGeneration *int64, reason, message string) {
+	setReady(conditions, observedGeneration, obj.GetGeneration(), metav1.ConditionFalse, reason, message)
+	recordWarningEvent(recorder, obj, reason, message)
+}
+
+// recordWarningEvent records a Warning Event with reason on obj, annotated with the telemetry tags of the
+// APIMService obj targets. Reconcilers built without a recorder, as in tests, record nothing.
+func recordWarningEvent(recorder record.EventRecorder, obj runtime.Object, reason, message string) {
+	recordEvent(recorder, obj, corev1.EventTypeWarning, reason, message)
+}
+
+// recordEvent records an Event of eventType like recordWarningEvent.
+func recordEvent(recorder record.EventRecorder, obj runtime.Object, eventType, reason, message string) {
+	if recorder == nil {
+		return
+	}
+	if annotations := eventAnnotations(obj); annotations != nil {
+		recorder.AnnotatedEventf(obj, annotations, eventType, reason, "%s", message)
+		return
+	}
+	recorder.Event(obj, eventType, reason, message)
+}
+
+// failureMessage appends the first line of err to message. Azure response bodies follow on later
+// lines and are left to the APIMAPIDeployment lastError.
+func failureMessage(message string, err error) string {
+	if err == nil {
+		return message
+	}
+	detail, _, _ := strings.Cut(redact.String(err.Error()), "\n")
+	return message + ": " + detail
+}
+
+// markNotReady sets the APIMAPI Ready condition to False with reason, records a matching Warning Event
+// and applies the status. Failing to apply is logged but does not change the outcome of the reconcile.
+func (r *APIMAPIDeploymentReconciler) markNotReady(ctx context.Context, apimApi *apimv1.APIMAPI, reason, message string) {
+	setReadyCondition(apimApi, metav1.ConditionFalse, reason, message)
+	recordWarningEvent(r.Recorder, apimApi, reason, message)
+	if err := applyAPIMAPIStatus(ctx, r.Client, apimApi); err != nil {
+		ctrl.Log.WithName("apimapideployment_controller").Error(err, "⚠️ Failed to patch APIMAPI status", "apimApiName", apimApi.Name)
+	}
+}
+
+// markProgressing sets the Ready condition of apimApi to Unknown when it was computed for an older
+// generation, so kubectl wait and GitOps tools block until the latest spec reaches APIM.
+func (r *APIMAPIDeploymentReconciler) markProgressing(ctx context.Context, apimApi *apimv1.APIMAPI) {
+	if ready := apimeta.FindStatusCondition(apimApi.Status.Conditions, conditionTypeReady); ready != nil && ready.ObservedGeneration == apimApi.Generation {
+		return
+	}
+	setReadyCondition(apimApi, metav1.ConditionUnknown, apimv1.ReasonProgressing, "Syncing the latest spec to APIM")
+	if err := applyAPIMAPIStatus(ctx, r.Client, apimApi); err != nil {
+		ctrl.Log.WithName("apimapideployment_controller").Error(err, "⚠️ Failed to patch APIMAPI status", "apimApiName", apimApi.Name)
+	}
+}
+
+// markSynced sets the Ready condition of apimApi to True when it is not already, for reconciles that
+// find APIM in sync without importing. A definition blocked for breaking changes is no longer pending then.
+func (r *APIMAPIDeploymentReconciler) markSynced(ctx context.Context, apimApi *apimv1.APIMAPI) error {
+	ready := apimeta.FindStatusCondition(apimApi.Status.Conditions, conditionTypeReady)
+	if ready != nil && ready.Status == metav1.ConditionTrue && ready.ObservedGeneration == apimApi.Generation {
+		return nil
+	}
+	clearBreakingChange(apimApi)
+	setReadyCondition(apimApi, metav1.ConditionTrue, apimv1.ReasonSynced, "API is imported and configured in APIM")
+	return applyAPIMAPIStatus(ctx, r.Client, apimApi)
+}
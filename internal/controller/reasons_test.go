@@ -0,0 +1,159 @@
+package controller
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+
+	apimv1 "github.com/hedinit/azure-apim-operator/api/v1"
+	"github.com/hedinit/azure-apim-operator/internal/apim"
+)
+
+func TestFailureReason(t *testing.T) {
+	respErr := func(status int) error {
+		return fmt.Errorf("wrapped: %w", &apim.ResponseError{StatusCode: status})
+	}
+	tests := []struct {
+		name string
+		step string
+		err  error
+		want string
+	}{
+		{"missing identity", importReasonMissingIdentity, errors.New("missing identity env vars"), apimv1.ReasonAuthFailed},
+		{"token", importReasonToken, errors.New("token expired"), apimv1.ReasonAuthFailed},
+		{"forbidden", importReasonServiceURL, respErr(403), apimv1.ReasonAuthFailed},
+		{"throttled", importReasonImport, respErr(429), apimv1.ReasonThrottled},
+		{"rejected definition", importReasonImport, respErr(400), apimv1.ReasonSpecInvalid},
+		{"bad request outside import", importReasonTagAssignment, respErr(400), apimv1.ReasonAzureError},
+		{"server error", importReasonImport, respErr(500), apimv1.ReasonAzureError},
+		{"network error", importReasonImport, errors.New("connection reset"), apimv1.ReasonAzureError},
+		{"modified outside the operator", importReasonImport, fmt.Errorf("import: %w: %w", apim.ErrModifiedOutsideOperator, &apim.ResponseError{StatusCode: 404}), apimv1.ReasonModifiedOutsideOperator},
+		{"smoke check", importReasonSmokeCheck, errors.New("GET answered 502"), apimv1.ReasonSmokeCheckFailed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := failureReason(tt.step, tt.err); got != tt.want {
+				t.Errorf("failureReason(%q) = %q, want %q", tt.step, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPolicyFailureReason(t *testing.T) {
+	for status, want := range map[int]string{
+		409: apimv1.ReasonConflictingPolicy,
+		412: apimv1.ReasonModifiedOutsideOperator,
+		429: apimv1.ReasonThrottled,
+		400: apimv1.ReasonAzureError,
+	} {
+		if got := policyFailureReason(&apim.ResponseError{StatusCode: status}); got != want {
+			t.Errorf("policyFailureReason(%d) = %q, want %q", status, got, want)
+		}
+	}
+}
+
+func TestFailureMessage(t *testing.T) {
+	err := &apim.ResponseError{Operation: "failed to import API", Status: "400 Bad Request", Body: `{"error":{}}`}
+	if got, want := failureMessage("Failed to import API into APIM", err), "Failed to import API into APIM: failed to import API: 400 Bad Request"; got != want {
+		t.Errorf("failureMessage() = %q, want %q", got, want)
+	}
+	if got := failureMessage("Invalid revision", nil); got != "Invalid revision" {
+		t.Errorf("failureMessage() without error = %q", got)
+	}
+}
+
+func TestSetReadyConditionAndWarningEvent(t *testing.T) {
+	apimApi := &apimv1.APIMAPI{ObjectMeta: metav1.ObjectMeta{Name: "orders", Generation: 3}}
+	setReadyCondition(apimApi, metav1.ConditionFalse, apimv1.ReasonThrottled, "Failed to import API into APIM")
+
+	ready := apimeta.FindStatusCondition(apimApi.Status.Conditions, conditionTypeReady)
+	if ready == nil || ready.Status != metav1.ConditionFalse || ready.Reason != apimv1.ReasonThrottled || ready.ObservedGeneration != 3 {
+		t.Fatalf("Ready condition = %+v, want False with reason Throttled at generation 3", ready)
+	}
+
+	recorder := record.NewFakeRecorder(1)
+	recordWarningEvent(recorder, apimApi, ready.Reason, ready.Message)
+	if got, want := <-recorder.Events, corev1.EventTypeWarning+" Throttled Failed to import API into APIM"; got != want {
+		t.Errorf("event = %q, want %q", got, want)
+	}
+	// A reconciler without a recorder must not panic.
+	recordWarningEvent(nil, apimApi, ready.Reason, ready.Message)
+}
+
+func TestWarnNotReady(t *testing.T) {
+	product := &apimv1.APIMProduct{ObjectMeta: metav1.ObjectMeta{Name: "gold", Generation: 2}}
+	recorder := record.NewFakeRecorder(1)
+	warnNotReady(recorder, product, &product.Status.Conditions, &product.Status.ObservedGeneration, apimv1.ReasonAuthFailed, "Failed to get Azure token")
+
+	ready := apimeta.FindStatusCondition(product.Status.Conditions, conditionTypeReady)
+	if ready == nil || ready.Status != metav1.ConditionFalse || ready.Reason != apimv1.ReasonAuthFailed || ready.ObservedGeneration != 2 {
+		t.Fatalf("Ready condition = %+v, want False with reason AuthFailed at generation 2", ready)
+	}
+	if got, want := <-recorder.Events, corev1.EventTypeWarning+" AuthFailed Failed to get Azure token"; got != want {
+		t.Errorf("event = %q, want %q", got, want)
+	}
+
+	setReady(&product.Status.Conditions, &product.Status.ObservedGeneration, product.Generation, metav1.ConditionTrue, apimv1.ReasonSynced, "Product is synced to APIM")
+	if !apimeta.IsStatusConditionTrue(product.Status.Conditions, conditionTypeReady) || len(product.Status.Conditions) != 1 {
+		t.Errorf("conditions = %+v, want a single True Ready condition", product.Status.Conditions)
+	}
+	if product.Status.ObservedGeneration != 2 {
+		t.Errorf("observedGeneration = %d, want 2", product.Status.ObservedGeneration)
+	}
+
+	// A new generation that is still being synced must not be reported as observed.
+	product.Generation = 3
+	setReady(&product.Status.Conditions, &product.Status.ObservedGeneration, product.Generation, metav1.ConditionUnknown, apimv1.ReasonProgressing, "Syncing")
+	if product.Status.ObservedGeneration != 2 {
+		t.Errorf("observedGeneration while progressing = %d, want 2", product.Status.ObservedGeneration)
+	}
+}
+
+func TestAzureRetryDelay(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want time.Duration
+	}{
+		{"throttled with Retry-After", fmt.Errorf("wrapped: %w", &apim.ResponseError{StatusCode: 429, RetryAfter: 7 * time.Second}), 7 * time.Second},
+		{"throttled without Retry-After", &apim.ResponseError{StatusCode: 429}, DefaultAzureRetryInterval},
+		{"expired token", &apim.ResponseError{StatusCode: 401}, DefaultAzureRetryInterval},
+		{"network error", errors.New("connection reset"), DefaultAzureRetryInterval},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := azureRetryDelay(tt.err); got != tt.want {
+				t.Errorf("azureRetryDelay() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestInvalidateRejectedToken(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"expired token", fmt.Errorf("wrapped: %w", &apim.ResponseError{StatusCode: 401}), 1},
+		{"missing role assignment", &apim.ResponseError{StatusCode: 403}, 0},
+		{"throttled", &apim.ResponseError{StatusCode: 429}, 0},
+		{"network error", errors.New("connection reset"), 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tokens := &staticTokens{token: "token"}
+			invalidateRejectedToken(tokens, tt.err)
+			if tokens.invalidations != tt.want {
+				t.Errorf("invalidations = %d, want %d", tokens.invalidations, tt.want)
+			}
+		})
+	}
+	invalidateRejectedToken(nil, &apim.ResponseError{StatusCode: 401})
+}
@@ -25,6 +25,9 @@ import (
 // custom resources.
 type ReplicaSetWatcherReconciler struct {
 	client.Client
+	// Hub is the client of the management cluster when the reconciler runs for a remote cluster
+	// registered with an APIMCluster. Nil means Client is the management cluster.
+	Hub    client.Client
 	Scheme *runtime.Scheme
 }
 
@@ -81,9 +84,15 @@ func (r *ReplicaSetWatcherReconciler) Reconcile(ctx context.Context, req ctrl.Re
 		"matchCount", len(apimApis),
 	)
 
+	rolloutHash, err := replicaSetRolloutHash(&rs)
+	if err != nil {
+		logger.Error(err, "❌ Failed to hash ReplicaSet pod template", "replicaSet", rs.Name, "namespace", rs.Namespace)
+		return ctrl.Result{}, err
+	}
+
 	var reconcileErrs []error
 	for _, apimApi := range apimApis {
-		apiDeployment, err := ensureAPIMAPIDeployment(ctx, r.Client, &apimApi)
+		apiDeployment, err := ensureAPIMAPIDeployment(ctx, r.Client, hubClient(r.Client, r.Hub), &apimApi)
 		if err != nil {
 			logger.Error(err, "❌ Failed to ensure APIMAPIDeployment", "apimapi", apimApi.Name, "apiID", apimApi.Spec.APIID)
 			reconcileErrs = append(reconcileErrs, err)
@@ -102,7 +111,20 @@ func (r *ReplicaSetWatcherReconciler) Reconcile(ctx context.Context, req ctrl.Re
 			"subscriptionRequired", apimApi.Spec.SubscriptionRequired,
 		)
 
-		if err := touchAPIMAPIDeployment(ctx, r.Client, apiDeployment, rs.Name); err != nil {
+		// Restarts and rollouts of an unchanged pod template serve the same definition; skip them
+		// instead of fetching it and calling Azure again.
+		if isUnchangedRollout(apiDeployment, &apimApi, rolloutHash) {
+			if err := recordNoChange(ctx, r.Client, apiDeployment, rs.Name); err != nil {
+				logger.Error(err, "❌ Failed to update APIMAPIDeployment status", "name", apiDeployment.Name, "apiID", apimApi.Spec.APIID)
+				reconcileErrs = append(reconcileErrs, err)
+				continue
+			}
+			logger.Info("⏭️ Pod template unchanged since last import; skipping deployment",
+				"replicaSet", rs.Name, "name", apiDeployment.Name, "apiID", apimApi.Spec.APIID)
+			continue
+		}
+
+		if err := touchAPIMAPIDeployment(ctx, r.Client, apiDeployment, rs.Name, rolloutHash); err != nil {
 			logger.Error(err, "❌ Failed to signal APIMAPIDeployment", "name", apiDeployment.Name, "apiID", apimApi.Spec.APIID)
 			reconcileErrs = append(reconcileErrs, err)
 			continue
@@ -187,57 +209,64 @@ func appendUniqueAPIMAPI(matches []apimv1.APIMAPI, apimApi apimv1.APIMAPI) []api
 }
 
 func (r *ReplicaSetWatcherReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	// Watch via the priority-aware handler instead of For so high-priority resources jump the queue.
 	return ctrl.NewControllerManagedBy(mgr).
-		For(&appsv1.ReplicaSet{}).
-		WithEventFilter(predicate.Funcs{
-			CreateFunc: func(e event.CreateEvent) bool {
-				// Skip creates for old ReplicaSet revisions that have been scaled down to 0.
-				// When a Deployment is updated, old ReplicaSets may be recreated or watched
-				// with 0 replicas, and we should not process these old revisions.
-				rs, ok := e.Object.(*appsv1.ReplicaSet)
-				if !ok {
-					return false
-				}
-				if rs.Spec.Replicas != nil && *rs.Spec.Replicas == 0 {
-					return false
-				}
-				// Only process Create events if pods are already ready.
-				// If pods aren't ready yet, we'll wait for the Update event when ReadyReplicas
-				// changes from 0 to >0. This prevents duplicate deployments from both Create
-				// and Update events when a ReplicaSet is created with ready pods.
-				return rs.Status.ReadyReplicas > 0
-			},
-			UpdateFunc: func(e event.UpdateEvent) bool {
-				// Only reconcile on ReplicaSet updates when the status changes
-				// Specifically, when ReadyReplicas changes, indicating pods becoming ready
-				oldRS, ok := e.ObjectOld.(*appsv1.ReplicaSet)
-				if !ok {
-					return false
-				}
-				newRS, ok := e.ObjectNew.(*appsv1.ReplicaSet)
-				if !ok {
-					return false
-				}
-
-				// Skip updates on old ReplicaSet revisions that have been scaled down to 0.
-				// When a Deployment is updated, the old ReplicaSet is scaled down to 0,
-				// and we should not process these old revisions even if ReadyReplicas changes.
-				if newRS.Spec.Replicas != nil && *newRS.Spec.Replicas == 0 {
-					return false
-				}
-
-				// Reconcile only when ReadyReplicas changes from 0 to greater than 0.
-				// This ensures we only trigger APIM deployments when pods actually become ready,
-				// not when they decrease or change in other ways.
-				// This handles the case where a ReplicaSet is created with ReadyReplicas = 0,
-				// and then pods become ready later.
-				return oldRS.Status.ReadyReplicas == 0 && newRS.Status.ReadyReplicas > 0
-			},
-			DeleteFunc:  func(e event.DeleteEvent) bool { return false },
-			GenericFunc: func(e event.GenericEvent) bool { return false },
-		}).
+		Watches(&appsv1.ReplicaSet{}, priorityEnqueueHandler()).
+		WithEventFilter(replicaSetReadyPredicate()).
+		WithEventFilter(shardPredicate()).
 		Named("replicasetwatcher").
-		Complete(r)
+		Complete(withTracing("ReplicaSetWatcher", r))
+}
+
+// replicaSetReadyPredicate selects the ReplicaSet events that mean pods of a current revision became ready.
+func replicaSetReadyPredicate() predicate.Predicate {
+	return predicate.Funcs{
+		CreateFunc: func(e event.CreateEvent) bool {
+			// Skip creates for old ReplicaSet revisions that have been scaled down to 0.
+			// When a Deployment is updated, old ReplicaSets may be recreated or watched
+			// with 0 replicas, and we should not process these old revisions.
+			rs, ok := e.Object.(*appsv1.ReplicaSet)
+			if !ok {
+				return false
+			}
+			if rs.Spec.Replicas != nil && *rs.Spec.Replicas == 0 {
+				return false
+			}
+			// Only process Create events if pods are already ready.
+			// If pods aren't ready yet, we'll wait for the Update event when ReadyReplicas
+			// changes from 0 to >0. This prevents duplicate deployments from both Create
+			// and Update events when a ReplicaSet is created with ready pods.
+			return rs.Status.ReadyReplicas > 0
+		},
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			// Only reconcile on ReplicaSet updates when the status changes
+			// Specifically, when ReadyReplicas changes, indicating pods becoming ready
+			oldRS, ok := e.ObjectOld.(*appsv1.ReplicaSet)
+			if !ok {
+				return false
+			}
+			newRS, ok := e.ObjectNew.(*appsv1.ReplicaSet)
+			if !ok {
+				return false
+			}
+
+			// Skip updates on old ReplicaSet revisions that have been scaled down to 0.
+			// When a Deployment is updated, the old ReplicaSet is scaled down to 0,
+			// and we should not process these old revisions even if ReadyReplicas changes.
+			if newRS.Spec.Replicas != nil && *newRS.Spec.Replicas == 0 {
+				return false
+			}
+
+			// Reconcile only when ReadyReplicas changes from 0 to greater than 0.
+			// This ensures we only trigger APIM deployments when pods actually become ready,
+			// not when they decrease or change in other ways.
+			// This handles the case where a ReplicaSet is created with ReadyReplicas = 0,
+			// and then pods become ready later.
+			return oldRS.Status.ReadyReplicas == 0 && newRS.Status.ReadyReplicas > 0
+		},
+		DeleteFunc:  func(e event.DeleteEvent) bool { return false },
+		GenericFunc: func(e event.GenericEvent) bool { return false },
+	}
 }
 
 // isPodReady checks if a pod is in the Ready condition.
@@ -82,6 +82,7 @@ var _ = Describe("ReplicaSetWatcher Controller", func() {
 				Spec: apimv1.APIMAPISpec{
 					APIID:       "test-api-id",
 					APIMService: apimServiceName,
+					RoutePrefix: "/test-api",
 				},
 			}
 			Expect(k8sClient.Create(ctx, apimAPI)).To(Succeed())
@@ -352,6 +353,7 @@ var _ = Describe("ReplicaSetWatcher Controller", func() {
 				Spec: apimv1.APIMAPISpec{
 					APIID:       "selector-api-id",
 					APIMService: apimServiceName,
+					RoutePrefix: "/selector-api",
 					Target: &apimv1.APIMAPITarget{
 						Selector: &metav1.LabelSelector{
 							MatchLabels: map[string]string{"team": "platform"},
@@ -400,6 +402,7 @@ var _ = Describe("ReplicaSetWatcher Controller", func() {
 				Spec: apimv1.APIMAPISpec{
 					APIID:       "selector-api-id",
 					APIMService: apimServiceName,
+					RoutePrefix: "/selector-api",
 					Target: &apimv1.APIMAPITarget{
 						Selector: &metav1.LabelSelector{
 							MatchLabels: map[string]string{"app.kubernetes.io/name": appName},
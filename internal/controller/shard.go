@@ -0,0 +1,58 @@
+package controller
+
+import (
+	"fmt"
+	"hash/fnv"
+	"sync"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+)
+
+var (
+	shardMu    sync.RWMutex
+	shardIndex = 0
+	shardCount = 1
+)
+
+// SetShard configures this operator instance to reconcile only the namespaces that hash to index
+// out of count shards. Every replica must use the same count and a distinct index.
+func SetShard(index, count int) error {
+	if count < 1 {
+		return fmt.Errorf("shard count must be at least 1, got %d", count)
+	}
+	if index < 0 || index >= count {
+		return fmt.Errorf("shard index must be in [0, %d), got %d", count, index)
+	}
+
+	shardMu.Lock()
+	defer shardMu.Unlock()
+	shardIndex = index
+	shardCount = count
+	return nil
+}
+
+// namespaceShard returns the shard a namespace belongs to using a stable FNV-1a hash,
+// so every replica agrees on ownership without coordination.
+func namespaceShard(namespace string, count int) int {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(namespace))
+	return int(h.Sum32() % uint32(count))
+}
+
+// ownsNamespace reports whether this operator instance is responsible for the namespace.
+func ownsNamespace(namespace string) bool {
+	shardMu.RLock()
+	defer shardMu.RUnlock()
+	if shardCount <= 1 {
+		return true
+	}
+	return namespaceShard(namespace, shardCount) == shardIndex
+}
+
+// shardPredicate drops events for objects in namespaces owned by another shard.
+func shardPredicate() predicate.Predicate {
+	return predicate.NewPredicateFuncs(func(obj client.Object) bool {
+		return ownsNamespace(obj.GetNamespace())
+	})
+}
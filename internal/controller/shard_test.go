@@ -0,0 +1,53 @@
+package controller
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestOwnsNamespaceSplitsNamespacesAcrossShards(t *testing.T) {
+	t.Cleanup(func() { _ = SetShard(0, 1) })
+
+	const count = 3
+	namespaces := make([]string, 0, 50)
+	for i := range 50 {
+		namespaces = append(namespaces, fmt.Sprintf("team-%d", i))
+	}
+
+	owners := map[string]int{}
+	for index := range count {
+		if err := SetShard(index, count); err != nil {
+			t.Fatalf("SetShard(%d, %d) returned error: %v", index, count, err)
+		}
+		for _, namespace := range namespaces {
+			if ownsNamespace(namespace) {
+				owners[namespace]++
+			}
+		}
+	}
+
+	for _, namespace := range namespaces {
+		if owners[namespace] != 1 {
+			t.Fatalf("namespace %s is owned by %d shards, want exactly 1", namespace, owners[namespace])
+		}
+	}
+}
+
+func TestSetShardRejectsInvalidConfiguration(t *testing.T) {
+	t.Cleanup(func() { _ = SetShard(0, 1) })
+
+	for _, tt := range []struct{ index, count int }{{0, 0}, {-1, 2}, {2, 2}} {
+		if err := SetShard(tt.index, tt.count); err == nil {
+			t.Fatalf("SetShard(%d, %d) succeeded, want error", tt.index, tt.count)
+		}
+	}
+}
+
+func TestSingleShardOwnsEverything(t *testing.T) {
+	if err := SetShard(0, 1); err != nil {
+		t.Fatalf("SetShard(0, 1) returned error: %v", err)
+	}
+	if !ownsNamespace("anything") {
+		t.Fatalf("expected a single shard to own every namespace")
+	}
+}
@@ -0,0 +1,223 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	apimv1 "github.com/hedinit/azure-apim-operator/api/v1"
+	"github.com/hedinit/azure-apim-operator/internal/apim"
+	"github.com/hedinit/azure-apim-operator/internal/identity"
+)
+
+const (
+	// subscriptionApprovalInterval is how often the pending subscriptions of a product are checked. APIM
+	// doesn't notify about new subscription requests, so they are polled.
+	subscriptionApprovalInterval = time.Minute
+
+	// eventReasonSubscriptionApproved is the Normal Event reason for a subscription the operator approved.
+	eventReasonSubscriptionApproved = "SubscriptionApproved"
+	// eventReasonSubscriptionRejected is the Normal Event reason for a subscription the operator rejected.
+	eventReasonSubscriptionRejected = "SubscriptionRejected"
+	// eventReasonSubscriptionApprovalFailed is the Warning Event reason for pending subscriptions that couldn't be read or decided.
+	eventReasonSubscriptionApprovalFailed = "SubscriptionApprovalFailed"
+)
+
+// SubscriptionApprovalRules decide which pending subscriptions the SubscriptionApprovalReconciler approves or rejects.
+type SubscriptionApprovalRules struct {
+	// AllowedDomains are the lower-case email domains, such as contoso.com, of the owners whose subscriptions
+	// are approved. Subdomains must be listed separately.
+	AllowedDomains []string
+	// Products are the IDs of the products whose subscriptions are decided. Empty means every product with
+	// approvalRequired.
+	Products []string
+	// Reject rejects the subscriptions of other owners. Otherwise they are left for an administrator.
+	Reject bool
+}
+
+// NewSubscriptionApprovalRules returns the rules for comma-separated lists of allowed email domains and product IDs.
+func NewSubscriptionApprovalRules(domains, products string, reject bool) (SubscriptionApprovalRules, error) {
+	rules := SubscriptionApprovalRules{Products: splitAnnotationList(products), Reject: reject}
+	for _, domain := range splitAnnotationList(domains) {
+		domain = strings.ToLower(strings.TrimPrefix(domain, "@"))
+		if strings.ContainsAny(domain, "@/ ") {
+			return SubscriptionApprovalRules{}, fmt.Errorf("invalid email domain %q", domain)
+		}
+		rules.AllowedDomains = append(rules.AllowedDomains, domain)
+	}
+	if len(rules.AllowedDomains) == 0 {
+		return SubscriptionApprovalRules{}, fmt.Errorf("at least one allowed email domain is required")
+	}
+	return rules, nil
+}
+
+// covers reports whether the subscriptions of the product are decided by the rules.
+func (r SubscriptionApprovalRules) covers(product *apimv1.APIMProduct) bool {
+	return product.Spec.ApprovalRequired && (len(r.Products) == 0 || slices.Contains(r.Products, product.Spec.ProductID))
+}
+
+// decide returns the state to move a subscription of the owner with email to, and the comment shown to the
+// subscriber. An empty state leaves the subscription pending.
+func (r SubscriptionApprovalRules) decide(email string) (state, comment string) {
+	domain := ""
+	if i := strings.LastIndex(email, "@"); i >= 0 {
+		domain = strings.ToLower(email[i+1:])
+	}
+	switch {
+	case domain != "" && slices.Contains(r.AllowedDomains, domain):
+		return apim.SubscriptionStateActive, fmt.Sprintf("Approved automatically: %s is an allowed domain", domain)
+	case r.Reject:
+		return apim.SubscriptionStateRejected, "Rejected automatically: the email domain of the owner is not allowed"
+	}
+	return "", ""
+}
+
+// SubscriptionApprovalReconciler approves or rejects the subscriptions that wait for approval on products with
+// approvalRequired, by the email domain of their owner, and records every decision as an Event on the APIMProduct.
+type SubscriptionApprovalReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+	// Recorder records the decisions as Events. Nil disables them.
+	Recorder record.EventRecorder
+	// APIM sends the Azure Management API requests. Nil sends them to the public endpoint.
+	APIM *apim.Client
+	// Tokens provides the Azure Management API token shared by all reconcilers. Nil asks Azure AD on
+	// every reconcile.
+	Tokens identity.TokenProvider
+	// Rules decide the pending subscriptions.
+	Rules SubscriptionApprovalRules
+}
+
+// +kubebuilder:rbac:groups=apim.operator.io,resources=apimproducts,verbs=get;list;watch
+// +kubebuilder:rbac:groups=apim.operator.io,resources=apimservices,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
+
+// Reconcile decides the pending subscriptions of an APIMProduct the rules cover and checks again after
+// subscriptionApprovalInterval. Subscriptions that can't be decided are retried on the next check.
+func (r *SubscriptionApprovalReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	var product apimv1.APIMProduct
+	if err := r.Get(ctx, req.NamespacedName, &product); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	if !product.DeletionTimestamp.IsZero() || !r.Rules.covers(&product) {
+		return ctrl.Result{}, nil
+	}
+	setSpanAPIAttributes(ctx, product.Spec.APIMService, "")
+
+	operatorNamespace, err := getOperatorNamespace()
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("get operator namespace: %w", err)
+	}
+	var apimService apimv1.APIMService
+	if err := r.Get(ctx, client.ObjectKey{Name: product.Spec.APIMService, Namespace: operatorNamespace}, &apimService); err != nil {
+		logger.Error(err, "❌ Failed to get APIMService", "name", product.Spec.APIMService)
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	// The APIMProduct controller reports missing credentials on the product; this only retries.
+	clientID := os.Getenv("AZURE_CLIENT_ID")
+	tenantID := os.Getenv("AZURE_TENANT_ID")
+	if clientID == "" || tenantID == "" {
+		logger.Error(fmt.Errorf("missing identity env vars"), "❌ AZURE_CLIENT_ID or AZURE_TENANT_ID not set")
+		return ctrl.Result{RequeueAfter: subscriptionApprovalInterval}, nil
+	}
+	token, err := managementToken(ctx, r.Tokens, clientID, tenantID)
+	if err != nil {
+		logger.Error(err, "❌ Failed to get Azure token")
+		return ctrl.Result{RequeueAfter: subscriptionApprovalInterval}, nil
+	}
+
+	service := apim.ExportConfig{
+		SubscriptionID: apimService.Spec.Subscription,
+		ResourceGroup:  apimService.Spec.ResourceGroup,
+		ServiceName:    product.Spec.APIMService,
+		BearerToken:    token,
+	}
+	pending, err := r.APIM.ListPendingSubscriptions(ctx, service, product.Spec.ProductID)
+	if err != nil {
+		logger.Error(err, "❌ Failed to list pending subscriptions", "productId", product.Spec.ProductID)
+		invalidateRejectedToken(r.Tokens, err)
+		recordWarningEvent(r.Recorder, &product, eventReasonSubscriptionApprovalFailed, failureMessage("Failed to list pending subscriptions", err))
+		return ctrl.Result{RequeueAfter: subscriptionApprovalInterval}, nil
+	}
+
+	for _, subscription := range pending {
+		email := ""
+		if subscription.OwnerID != "" {
+			if email, err = r.APIM.GetUserEmail(ctx, service, subscription.OwnerID); err != nil {
+				logger.Error(err, "❌ Failed to get subscription owner", "subscription", subscription.Name, "owner", subscription.OwnerID)
+				invalidateRejectedToken(r.Tokens, err)
+				recordWarningEvent(r.Recorder, &product, eventReasonSubscriptionApprovalFailed,
+					failureMessage(fmt.Sprintf("Failed to get the owner of subscription %q", subscription.Name), err))
+				continue
+			}
+		}
+		state, comment := r.Rules.decide(email)
+		if state == "" {
+			logger.Info("⏸️ Subscription left for an administrator", "subscription", subscription.Name, "owner", email)
+			continue
+		}
+		if apim.IsDryRun() {
+			logger.Info("🧪 Dry-run: subscription state not changed", "subscription", subscription.Name, "owner", email, "state", state)
+			continue
+		}
+		err := r.APIM.SetSubscriptionState(ctx, apim.APIMSubscriptionConfig{
+			SubscriptionID: service.SubscriptionID,
+			ResourceGroup:  service.ResourceGroup,
+			ServiceName:    service.ServiceName,
+			Name:           subscription.Name,
+			BearerToken:    token,
+		}, state, comment)
+		if err != nil {
+			logger.Error(err, "❌ Failed to set subscription state", "subscription", subscription.Name, "state", state)
+			invalidateRejectedToken(r.Tokens, err)
+			recordWarningEvent(r.Recorder, &product, eventReasonSubscriptionApprovalFailed,
+				failureMessage(fmt.Sprintf("Failed to set subscription %q to %s", subscription.Name, state), err))
+			continue
+		}
+		r.recordDecision(&product, subscription, email, state, comment)
+	}
+	return ctrl.Result{RequeueAfter: subscriptionApprovalInterval}, nil
+}
+
+// recordDecision records the approval or rejection of subscription as a Normal Event on product.
+func (r *SubscriptionApprovalReconciler) recordDecision(product *apimv1.APIMProduct, subscription apim.PendingSubscription, email, state, comment string) {
+	reason := eventReasonSubscriptionApproved
+	if state == apim.SubscriptionStateRejected {
+		reason = eventReasonSubscriptionRejected
+	}
+	owner := email
+	if owner == "" {
+		owner = "no owner"
+	}
+	recordEvent(r.Recorder, product, corev1.EventTypeNormal, reason, fmt.Sprintf("Subscription %q (%s): %s", subscription.Name, owner, comment))
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *SubscriptionApprovalReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&apimv1.APIMProduct{}).
+		WithEventFilter(predicate.Funcs{
+			CreateFunc:  func(e event.CreateEvent) bool { return true },
+			UpdateFunc:  func(e event.UpdateEvent) bool { return e.ObjectOld.GetGeneration() != e.ObjectNew.GetGeneration() },
+			DeleteFunc:  func(e event.DeleteEvent) bool { return false },
+			GenericFunc: func(e event.GenericEvent) bool { return false },
+		}).
+		WithEventFilter(shardPredicate()).
+		Named("subscriptionapproval").
+		Complete(withTracing("SubscriptionApproval", r))
+}
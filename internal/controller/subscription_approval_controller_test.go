@@ -0,0 +1,152 @@
+package controller
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	apimv1 "github.com/hedinit/azure-apim-operator/api/v1"
+	"github.com/hedinit/azure-apim-operator/internal/apim"
+	"github.com/hedinit/azure-apim-operator/internal/apimtest"
+	"github.com/hedinit/azure-apim-operator/internal/identity"
+)
+
+func TestNewSubscriptionApprovalRules(t *testing.T) {
+	rules, err := NewSubscriptionApprovalRules(" Contoso.com, @fabrikam.com,,", "gold", true)
+	if err != nil {
+		t.Fatalf("NewSubscriptionApprovalRules() error = %v", err)
+	}
+	if strings.Join(rules.AllowedDomains, ",") != "contoso.com,fabrikam.com" || strings.Join(rules.Products, ",") != "gold" || !rules.Reject {
+		t.Errorf("NewSubscriptionApprovalRules() = %+v, want both domains in lower case, product gold and reject", rules)
+	}
+
+	for _, domains := range []string{"", " , ", "alice@contoso.com"} {
+		if _, err := NewSubscriptionApprovalRules(domains, "", false); err == nil {
+			t.Errorf("NewSubscriptionApprovalRules(%q) error = nil, want an error", domains)
+		}
+	}
+}
+
+func TestSubscriptionApprovalDecide(t *testing.T) {
+	tests := []struct {
+		name   string
+		email  string
+		reject bool
+		want   string
+	}{
+		{name: "allowed domain", email: "alice@Contoso.com", want: apim.SubscriptionStateActive},
+		{name: "subdomain", email: "bob@eu.contoso.com", want: ""},
+		{name: "other domain", email: "mallory@example.com", want: ""},
+		{name: "other domain rejected", email: "mallory@example.com", reject: true, want: apim.SubscriptionStateRejected},
+		{name: "no owner rejected", reject: true, want: apim.SubscriptionStateRejected},
+		{name: "domain in the local part", email: "contoso.com@example.com", want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rules := SubscriptionApprovalRules{AllowedDomains: []string{"contoso.com"}, Reject: tt.reject}
+			state, comment := rules.decide(tt.email)
+			if state != tt.want {
+				t.Errorf("decide(%q) state = %q, want %q", tt.email, state, tt.want)
+			}
+			if (state == "") != (comment == "") {
+				t.Errorf("decide(%q) comment = %q, want one exactly with a decision", tt.email, comment)
+			}
+		})
+	}
+}
+
+func TestSubscriptionApprovalCovers(t *testing.T) {
+	product := &apimv1.APIMProduct{Spec: apimv1.APIMProductSpec{ProductID: "gold", ApprovalRequired: true}}
+	if !(SubscriptionApprovalRules{}).covers(product) {
+		t.Error("covers() = false without a product allowlist, want true")
+	}
+	if (SubscriptionApprovalRules{Products: []string{"starter"}}).covers(product) {
+		t.Error("covers() = true for a product outside the allowlist, want false")
+	}
+	product.Spec.ApprovalRequired = false
+	if (SubscriptionApprovalRules{}).covers(product) {
+		t.Error("covers() = true for a product without approvalRequired, want false")
+	}
+}
+
+func TestSubscriptionApprovalReconcile(t *testing.T) {
+	fakeAPIM := apimtest.Start(t)
+	t.Setenv("AZURE_CLIENT_ID", "test-client-id")
+	t.Setenv("AZURE_TENANT_ID", "test-tenant-id")
+	identity.SetStaticToken("test-token")
+	t.Cleanup(func() { identity.SetStaticToken("") })
+
+	const servicePath = "/subscriptions/sub/resourceGroups/rg/providers/Microsoft.ApiManagement/service/apim-test"
+	pending := func(name, owner string) {
+		fakeAPIM.Put(servicePath+"/subscriptions/"+name, map[string]interface{}{
+			"displayName": name, "scope": servicePath + "/products/gold", "state": "submitted", "ownerId": servicePath + "/users/" + owner,
+		})
+	}
+	fakeAPIM.Put(servicePath+"/users/alice", map[string]interface{}{"email": "alice@contoso.com"})
+	fakeAPIM.Put(servicePath+"/users/mallory", map[string]interface{}{"email": "mallory@example.com"})
+	pending("alice-gold", "alice")
+	pending("mallory-gold", "mallory")
+
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	if err := apimv1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	product := &apimv1.APIMProduct{
+		ObjectMeta: metav1.ObjectMeta{Name: "gold", Namespace: "default"},
+		Spec:       apimv1.APIMProductSpec{ProductID: "gold", DisplayName: "Gold", APIMService: "apim-test", ApprovalRequired: true},
+	}
+	service := &apimv1.APIMService{
+		ObjectMeta: metav1.ObjectMeta{Name: "apim-test", Namespace: "default"},
+		Spec:       apimv1.APIMServiceSpec{Subscription: "sub", ResourceGroup: "rg"},
+	}
+	recorder := record.NewFakeRecorder(10)
+	r := &SubscriptionApprovalReconciler{
+		APIM:     fakeAPIM.Client(),
+		Client:   fake.NewClientBuilder().WithScheme(scheme).WithObjects(product, service).Build(),
+		Scheme:   scheme,
+		Recorder: recorder,
+		Rules:    SubscriptionApprovalRules{AllowedDomains: []string{"contoso.com"}},
+	}
+
+	result, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: types.NamespacedName{Name: "gold", Namespace: "default"}})
+	if err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	if result.RequeueAfter != subscriptionApprovalInterval {
+		t.Errorf("RequeueAfter = %v, want %v", result.RequeueAfter, subscriptionApprovalInterval)
+	}
+	if properties, _ := fakeAPIM.Properties(servicePath + "/subscriptions/alice-gold"); properties["state"] != apim.SubscriptionStateActive {
+		t.Errorf("state of alice-gold = %v, want active", properties["state"])
+	}
+	if properties, _ := fakeAPIM.Properties(servicePath + "/subscriptions/mallory-gold"); properties["state"] != "submitted" {
+		t.Errorf("state of mallory-gold = %v, want it left submitted", properties["state"])
+	}
+	if got := <-recorder.Events; !strings.HasPrefix(got, "Normal SubscriptionApproved") || !strings.Contains(got, "alice@contoso.com") {
+		t.Errorf("event = %q, want the approval of alice-gold", got)
+	}
+	if len(recorder.Events) != 0 {
+		t.Errorf("recorded %d more events, want only the approval", len(recorder.Events))
+	}
+
+	r.Rules.Reject = true
+	if _, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: types.NamespacedName{Name: "gold", Namespace: "default"}}); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	if properties, _ := fakeAPIM.Properties(servicePath + "/subscriptions/mallory-gold"); properties["state"] != apim.SubscriptionStateRejected {
+		t.Errorf("state of mallory-gold = %v, want rejected", properties["state"])
+	}
+	if got := <-recorder.Events; !strings.HasPrefix(got, "Normal SubscriptionRejected") {
+		t.Errorf("event = %q, want the rejection of mallory-gold", got)
+	}
+}
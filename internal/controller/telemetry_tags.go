@@ -0,0 +1,100 @@
+package controller
+
+import (
+	"context"
+	"sync"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	apimv1 "github.com/hedinit/azure-apim-operator/api/v1"
+)
+
+var (
+	// Span attributes holding the telemetry tags of the targeted APIMService.
+	attrTagEnv     = attribute.Key("apim.tag.env")
+	attrTagTeam    = attribute.Key("apim.tag.team")
+	attrTagService = attribute.Key("apim.tag.service")
+
+	// Kubernetes Event annotations holding the telemetry tags of the targeted APIMService.
+	eventAnnotationEnv     = "apim.operator.io/env"
+	eventAnnotationTeam    = "apim.operator.io/team"
+	eventAnnotationService = "apim.operator.io/service"
+)
+
+// serviceTelemetryTags holds the telemetry tags of every APIMService a reconcile of this process has read,
+// by name. Metrics and Events are recorded without the APIMService at hand, so they look the tags up here.
+var serviceTelemetryTags sync.Map
+
+// observeAPIMService remembers the telemetry tags of svc and adds them to the reconcile span in ctx. Every
+// controller calls it after reading the APIMService its resource targets, so the tags follow spec changes.
+func observeAPIMService(ctx context.Context, svc *apimv1.APIMService) {
+	var tags apimv1.APIMServiceTelemetryTags
+	if svc.Spec.TelemetryTags != nil {
+		tags = *svc.Spec.TelemetryTags
+	}
+	if tags == (apimv1.APIMServiceTelemetryTags{}) {
+		serviceTelemetryTags.Delete(svc.Name)
+		return
+	}
+	serviceTelemetryTags.Store(svc.Name, tags)
+
+	span := trace.SpanFromContext(ctx)
+	for key, value := range map[attribute.Key]string{attrTagEnv: tags.Env, attrTagTeam: tags.Team, attrTagService: tags.Service} {
+		if value != "" {
+			span.SetAttributes(key.String(value))
+		}
+	}
+}
+
+// telemetryTagsFor returns the telemetry tags last seen for an APIMService, or no tags.
+func telemetryTagsFor(apimService string) apimv1.APIMServiceTelemetryTags {
+	if tags, ok := serviceTelemetryTags.Load(apimService); ok {
+		return tags.(apimv1.APIMServiceTelemetryTags)
+	}
+	return apimv1.APIMServiceTelemetryTags{}
+}
+
+// eventAnnotations returns the telemetry tags of the APIMService obj targets as Event annotations,
+// or nil if there are none.
+func eventAnnotations(obj runtime.Object) map[string]string {
+	tags := telemetryTagsFor(targetAPIMService(obj))
+	annotations := map[string]string{}
+	for key, value := range map[string]string{eventAnnotationEnv: tags.Env, eventAnnotationTeam: tags.Team, eventAnnotationService: tags.Service} {
+		if value != "" {
+			annotations[key] = value
+		}
+	}
+	if len(annotations) == 0 {
+		return nil
+	}
+	return annotations
+}
+
+// targetAPIMService returns the name of the APIMService obj targets, or "" for kinds that target none.
+func targetAPIMService(obj runtime.Object) string {
+	switch o := obj.(type) {
+	case *apimv1.APIMService:
+		return o.Name
+	case *apimv1.APIMAPI:
+		return o.Spec.APIMService
+	case *apimv1.APIMAPIDeployment:
+		return o.Spec.APIMService
+	case *apimv1.APIMProduct:
+		return o.Spec.APIMService
+	case *apimv1.APIMTag:
+		return o.Spec.APIMService
+	case *apimv1.APIMInboundPolicy:
+		return o.Spec.APIMService
+	case *apimv1.APIMSubscription:
+		return o.Spec.APIMService
+	case *apimv1.APIMNamedValue:
+		return o.Spec.APIMService
+	case *apimv1.APIMBackend:
+		return o.Spec.APIMService
+	case *apimv1.APIMApiVersionSet:
+		return o.Spec.APIMService
+	}
+	return ""
+}
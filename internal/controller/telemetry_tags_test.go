@@ -0,0 +1,56 @@
+package controller
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+
+	apimv1 "github.com/hedinit/azure-apim-operator/api/v1"
+)
+
+func TestObserveAPIMService(t *testing.T) {
+	svc := &apimv1.APIMService{
+		ObjectMeta: metav1.ObjectMeta{Name: "tagged-apim"},
+		Spec: apimv1.APIMServiceSpec{TelemetryTags: &apimv1.APIMServiceTelemetryTags{
+			Env:  "prod",
+			Team: "payments",
+		}},
+	}
+	observeAPIMService(context.Background(), svc)
+	if got := telemetryTagsFor("tagged-apim"); got.Env != "prod" || got.Team != "payments" || got.Service != "" {
+		t.Errorf("telemetryTagsFor() = %+v, want env prod and team payments", got)
+	}
+
+	product := &apimv1.APIMProduct{Spec: apimv1.APIMProductSpec{APIMService: "tagged-apim"}}
+	want := map[string]string{eventAnnotationEnv: "prod", eventAnnotationTeam: "payments"}
+	if got := eventAnnotations(product); len(got) != len(want) || got[eventAnnotationEnv] != "prod" || got[eventAnnotationTeam] != "payments" {
+		t.Errorf("eventAnnotations() = %v, want %v", got, want)
+	}
+
+	SetAPIIDMetricLabelLimit(0)
+	deployment := &apimv1.APIMAPIDeployment{Spec: apimv1.APIMAPIDeploymentSpec{APIMService: "tagged-apim", APIID: "orders"}}
+	before := testutil.ToFloat64(apiImportTotal.WithLabelValues("tagged-apim", "", "prod", "payments", "", importResultFailure, importReasonToken))
+	recordImportFailure(context.Background(), deployment, importReasonToken, nil)
+	if got := testutil.ToFloat64(apiImportTotal.WithLabelValues("tagged-apim", "", "prod", "payments", "", importResultFailure, importReasonToken)); got != before+1 {
+		t.Errorf("tagged failure count = %v, want %v", got, before+1)
+	}
+
+	recorder := record.NewFakeRecorder(1)
+	recordWarningEvent(recorder, product, apimv1.ReasonAzureError, "Failed to upsert product in APIM")
+	if event := <-recorder.Events; !strings.Contains(event, "Failed to upsert product in APIM") {
+		t.Errorf("event = %q, want the failure message", event)
+	}
+
+	svc.Spec.TelemetryTags = nil
+	observeAPIMService(context.Background(), svc)
+	if got := telemetryTagsFor("tagged-apim"); got != (apimv1.APIMServiceTelemetryTags{}) {
+		t.Errorf("telemetryTagsFor() = %+v after the tags were removed, want none", got)
+	}
+	if got := eventAnnotations(product); got != nil {
+		t.Errorf("eventAnnotations() = %v after the tags were removed, want nil", got)
+	}
+}
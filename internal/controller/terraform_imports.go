@@ -0,0 +1,77 @@
+package controller
+
+import (
+	"strings"
+
+	apimv1 "github.com/hedinit/azure-apim-operator/api/v1"
+	"github.com/hedinit/azure-apim-operator/internal/apim"
+)
+
+const (
+	// azapiAPIVersion is the APIM API version of azapi_resource types. It is the version the operator calls.
+	azapiAPIVersion = "2021-08-01"
+
+	terraformAPIResource     = "azurerm_api_management_api"
+	terraformProductAPI      = "azurerm_api_management_product_api"
+	terraformAPITag          = "azurerm_api_management_api_tag"
+	terraformRevisionSuffix  = ";rev="
+	defaultAPIRevisionNumber = "1"
+)
+
+// terraformImport describes an entity whose azurerm ID is id and whose ARM ID is armID.
+func terraformImport(resource, azapiType, id, armID string) apimv1.TerraformImport {
+	return apimv1.TerraformImport{Resource: resource, ID: id, AzapiType: azapiType + "@" + azapiAPIVersion, AzapiID: armID}
+}
+
+// apiTerraformImports describes an API in its current revision and its links to products and tags.
+// azurerm imports an API by its ARM ID with the revision appended.
+func apiTerraformImports(subscriptionID, resourceGroup, serviceName, apiID, revision string, productIDs, tagIDs []string) []apimv1.TerraformImport {
+	apiResourceID := apim.APIResourceID(subscriptionID, resourceGroup, serviceName, apiID)
+	imports := []apimv1.TerraformImport{
+		terraformImport(terraformAPIResource, "Microsoft.ApiManagement/service/apis", apiResourceID+terraformRevisionSuffix+revision, apiResourceID),
+	}
+	for _, productID := range productIDs {
+		id := apim.ProductAPIResourceID(subscriptionID, resourceGroup, serviceName, productID, apiID)
+		imports = append(imports, terraformImport(terraformProductAPI, "Microsoft.ApiManagement/service/products/apis", id, id))
+	}
+	for _, tagID := range tagIDs {
+		id := apim.APITagResourceID(subscriptionID, resourceGroup, serviceName, apiID, tagID)
+		imports = append(imports, terraformImport(terraformAPITag, "Microsoft.ApiManagement/service/apis/tags", id, id))
+	}
+	return imports
+}
+
+// currentAPIRevision returns the current revision of the API of apimApi after an import into revision.
+// An import without a revision goes into the current one, which is kept from the last import; APIM starts
+// APIs at revision 1.
+func currentAPIRevision(apimApi *apimv1.APIMAPI, revision string) string {
+	if revision != "" {
+		return revision
+	}
+	for _, hint := range apimApi.Status.TerraformImports {
+		if hint.Resource != terraformAPIResource {
+			continue
+		}
+		if _, current, ok := strings.Cut(hint.ID, terraformRevisionSuffix); ok && current != "" {
+			return current
+		}
+	}
+	return defaultAPIRevisionNumber
+}
+
+// policyTerraformImports describes an API or operation policy. azurerm imports a policy by the ARM ID of
+// its API or operation, since each has exactly one.
+func policyTerraformImports(subscriptionID, resourceGroup, serviceName, apiID, operationID string) []apimv1.TerraformImport {
+	policyResourceID := apim.PolicyResourceID(subscriptionID, resourceGroup, serviceName, apiID, operationID)
+	if operationID != "" {
+		return []apimv1.TerraformImport{terraformImport("azurerm_api_management_api_operation_policy", "Microsoft.ApiManagement/service/apis/operations/policies",
+			apim.OperationResourceID(subscriptionID, resourceGroup, serviceName, apiID, operationID), policyResourceID)}
+	}
+	return []apimv1.TerraformImport{terraformImport("azurerm_api_management_api_policy", "Microsoft.ApiManagement/service/apis/policies",
+		apim.APIResourceID(subscriptionID, resourceGroup, serviceName, apiID), policyResourceID)}
+}
+
+// resourceTerraformImports describes an entity that azurerm imports by its ARM ID.
+func resourceTerraformImports(resource, azapiType, armID string) []apimv1.TerraformImport {
+	return []apimv1.TerraformImport{terraformImport(resource, azapiType, armID, armID)}
+}
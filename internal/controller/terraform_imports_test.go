@@ -0,0 +1,65 @@
+package controller
+
+import (
+	"reflect"
+	"testing"
+
+	apimv1 "github.com/hedinit/azure-apim-operator/api/v1"
+)
+
+const testServiceID = "/subscriptions/sub/resourceGroups/rg/providers/Microsoft.ApiManagement/service/apim"
+
+func TestAPITerraformImports(t *testing.T) {
+	got := apiTerraformImports("sub", "rg", "apim", "orders", "3", []string{"public"}, []string{"sales"})
+	want := []apimv1.TerraformImport{
+		{
+			Resource:  "azurerm_api_management_api",
+			ID:        testServiceID + "/apis/orders;rev=3",
+			AzapiType: "Microsoft.ApiManagement/service/apis@2021-08-01",
+			AzapiID:   testServiceID + "/apis/orders",
+		},
+		{
+			Resource:  "azurerm_api_management_product_api",
+			ID:        testServiceID + "/products/public/apis/orders",
+			AzapiType: "Microsoft.ApiManagement/service/products/apis@2021-08-01",
+			AzapiID:   testServiceID + "/products/public/apis/orders",
+		},
+		{
+			Resource:  "azurerm_api_management_api_tag",
+			ID:        testServiceID + "/apis/orders/tags/sales",
+			AzapiType: "Microsoft.ApiManagement/service/apis/tags@2021-08-01",
+			AzapiID:   testServiceID + "/apis/orders/tags/sales",
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("apiTerraformImports() = %+v, want %+v", got, want)
+	}
+}
+
+func TestCurrentAPIRevision(t *testing.T) {
+	apimApi := &apimv1.APIMAPI{}
+	if got := currentAPIRevision(apimApi, ""); got != "1" {
+		t.Errorf("first import: currentAPIRevision() = %q, want 1", got)
+	}
+	apimApi.Status.TerraformImports = apiTerraformImports("sub", "rg", "apim", "orders", "4", nil, nil)
+	if got := currentAPIRevision(apimApi, ""); got != "4" {
+		t.Errorf("import into the current revision: currentAPIRevision() = %q, want 4", got)
+	}
+	if got := currentAPIRevision(apimApi, "5"); got != "5" {
+		t.Errorf("import into revision 5: currentAPIRevision() = %q, want 5", got)
+	}
+}
+
+func TestPolicyTerraformImports(t *testing.T) {
+	api := policyTerraformImports("sub", "rg", "apim", "orders", "")
+	if len(api) != 1 || api[0].Resource != "azurerm_api_management_api_policy" || api[0].ID != testServiceID+"/apis/orders" ||
+		api[0].AzapiID != testServiceID+"/apis/orders/policies/policy" {
+		t.Errorf("API policy = %+v, want the API ID for azurerm and the policy ID for azapi", api)
+	}
+	operation := policyTerraformImports("sub", "rg", "apim", "orders", "list")
+	if len(operation) != 1 || operation[0].Resource != "azurerm_api_management_api_operation_policy" ||
+		operation[0].ID != testServiceID+"/apis/orders/operations/list" ||
+		operation[0].AzapiID != testServiceID+"/apis/orders/operations/list/policies/policy" {
+		t.Errorf("operation policy = %+v, want the operation ID for azurerm and the policy ID for azapi", operation)
+	}
+}
@@ -0,0 +1,66 @@
+package controller
+
+import (
+	"context"
+	"errors"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+	"go.opentelemetry.io/otel/trace"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/hedinit/azure-apim-operator/internal/apim"
+	"github.com/hedinit/azure-apim-operator/internal/redact"
+)
+
+// tracer creates the reconcile spans. The APIM calls made during a reconcile become its child spans.
+var tracer = otel.Tracer("github.com/hedinit/azure-apim-operator/internal/controller")
+
+// tracedReconciler runs every reconcile of the wrapped reconciler in its own span named "<kind>.Reconcile".
+type tracedReconciler struct {
+	kind string
+	reconcile.Reconciler
+}
+
+// withTracing wraps r so that each reconcile of a kind can be followed end-to-end in a trace.
+func withTracing(kind string, r reconcile.Reconciler) reconcile.Reconciler {
+	return &tracedReconciler{kind: kind, Reconciler: r}
+}
+
+// Reconcile implements reconcile.Reconciler.
+func (t *tracedReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	ctx, span := tracer.Start(ctx, t.kind+".Reconcile", trace.WithAttributes(
+		semconv.K8SNamespaceName(req.Namespace),
+		attribute.String("k8s.object.name", req.Name),
+	))
+	defer span.End()
+
+	result, err := t.Reconciler.Reconcile(ctx, req)
+	recordSpanError(span, err)
+	return result, err
+}
+
+// recordSpanError marks span as failed with the redacted message of err. It does nothing if err is nil.
+func recordSpanError(span trace.Span, err error) {
+	if err == nil {
+		return
+	}
+	message := redact.String(err.Error())
+	span.RecordError(errors.New(message))
+	span.SetStatus(codes.Error, message)
+}
+
+// setSpanAPIAttributes adds the APIM service and API ID of the reconciled resource to the reconcile span.
+// Empty values are skipped, since not every kind targets a single API.
+func setSpanAPIAttributes(ctx context.Context, apimService, apiID string) {
+	span := trace.SpanFromContext(ctx)
+	if apimService != "" {
+		span.SetAttributes(apim.AttrAPIMService.String(apimService))
+	}
+	if apiID != "" {
+		span.SetAttributes(apim.AttrAPIID.String(apiID))
+	}
+}
@@ -17,14 +17,20 @@ limitations under the License.
 package controller
 
 import (
+	"context"
 	"os"
 	"strings"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/hedinit/azure-apim-operator/internal/identity"
 )
 
 // Phase constants for status tracking across controllers.
 const (
 	phaseError   = "Error"   // Indicates an error occurred during resource creation/update.
 	phaseCreated = "Created" // Indicates the resource was successfully created or updated.
+	phaseDryRun  = "DryRun"  // Indicates Azure-mutating calls were logged but not sent (--dry-run).
 )
 
 // Error message constants shared across controllers.
@@ -32,15 +38,44 @@ const (
 	errMsgFailedToGetAzureToken = "Failed to get Azure token"
 )
 
+// OperatorNamespace returns the namespace where the operator is running and APIMService resources live.
+func OperatorNamespace() (string, error) {
+	return getOperatorNamespace()
+}
+
+// hubClient returns hub, the client of the management cluster, or c when a reconciler runs in the
+// management cluster itself. APIMServices and their Secrets always live in the management cluster.
+func hubClient(c, hub client.Client) client.Client {
+	if hub != nil {
+		return hub
+	}
+	return c
+}
+
+// managementToken returns a token for the Azure Management API from tokens, the provider shared by the
+// reconcilers, or asks the Azure AD of the public cloud with the workload identity of clientID and tenantID
+// when none was injected.
+func managementToken(ctx context.Context, tokens identity.TokenProvider, clientID, tenantID string) (string, error) {
+	if tokens != nil {
+		return tokens.ManagementToken(ctx)
+	}
+	return identity.GetManagementToken(ctx, clientID, tenantID, identity.AzurePublic)
+}
+
+// serviceAccountNamespaceFile holds the namespace of the pod the operator runs in. It does not exist when the
+// operator runs outside a cluster, e.g. with make run or in envtest.
+var serviceAccountNamespaceFile = "/var/run/secrets/kubernetes.io/serviceaccount/namespace"
+
 // getOperatorNamespace returns the namespace where the operator is running.
 // It first tries to read from the service account namespace file (production),
 // then falls back to the OPERATOR_NAMESPACE environment variable (for testing),
 // and finally defaults to "default" if neither is available.
 func getOperatorNamespace() (string, error) {
-	// First, try to read from the service account namespace file (production)
-	nsBytes, err := os.ReadFile("/var/run/secrets/kubernetes.io/serviceaccount/namespace")
-	if err == nil {
-		return strings.TrimSpace(string(nsBytes)), nil
+	// First, try to read from the service account namespace file (production).
+	// An empty file is treated like a missing one.
+	nsBytes, err := os.ReadFile(serviceAccountNamespaceFile)
+	if ns := strings.TrimSpace(string(nsBytes)); err == nil && ns != "" {
+		return ns, nil
 	}
 
 	// Fall back to environment variable (useful for testing)
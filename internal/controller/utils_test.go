@@ -0,0 +1,117 @@
+package controller
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	apimv1 "github.com/hedinit/azure-apim-operator/api/v1"
+)
+
+var _ = Describe("Operator namespace", func() {
+	// useServiceAccountNamespaceFile points getOperatorNamespace at a file with content, or at a missing file
+	// for a nil content, as when the operator runs outside a cluster.
+	useServiceAccountNamespaceFile := func(content []byte) {
+		path := filepath.Join(GinkgoT().TempDir(), "namespace")
+		if content != nil {
+			Expect(os.WriteFile(path, content, 0o600)).To(Succeed())
+		}
+		original := serviceAccountNamespaceFile
+		serviceAccountNamespaceFile = path
+		DeferCleanup(func() { serviceAccountNamespaceFile = original })
+	}
+
+	It("should read the namespace of the service account in a cluster", func() {
+		useServiceAccountNamespaceFile([]byte("apim-system\n"))
+		GinkgoT().Setenv("OPERATOR_NAMESPACE", "ignored")
+		Expect(getOperatorNamespace()).To(Equal("apim-system"))
+	})
+
+	It("should fall back to OPERATOR_NAMESPACE outside a cluster", func() {
+		useServiceAccountNamespaceFile(nil)
+		GinkgoT().Setenv("OPERATOR_NAMESPACE", "apim-local")
+		Expect(getOperatorNamespace()).To(Equal("apim-local"))
+	})
+
+	It("should fall back to OPERATOR_NAMESPACE when the service account file is empty", func() {
+		useServiceAccountNamespaceFile([]byte("  \n"))
+		GinkgoT().Setenv("OPERATOR_NAMESPACE", "apim-local")
+		Expect(getOperatorNamespace()).To(Equal("apim-local"))
+	})
+
+	It("should default to the default namespace without a service account file or OPERATOR_NAMESPACE", func() {
+		useServiceAccountNamespaceFile(nil)
+		GinkgoT().Setenv("OPERATOR_NAMESPACE", "")
+		Expect(OperatorNamespace()).To(Equal("default"))
+	})
+
+	Context("When a reconciler runs outside a cluster", func() {
+		const operatorNamespace = "apim-operator-outside"
+		const apimServiceName = "outside-apim-service"
+		ctx := context.Background()
+		tagName := types.NamespacedName{Name: "outside-tag", Namespace: "default"}
+
+		BeforeEach(func() {
+			useServiceAccountNamespaceFile(nil)
+			GinkgoT().Setenv("AZURE_CLIENT_ID", "")
+			GinkgoT().Setenv("AZURE_TENANT_ID", "")
+
+			By("creating the APIMService in the operator namespace")
+			ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: operatorNamespace}}
+			if err := k8sClient.Create(ctx, ns); err != nil && !errors.IsAlreadyExists(err) {
+				Expect(err).NotTo(HaveOccurred())
+			}
+			apimService := &apimv1.APIMService{
+				ObjectMeta: metav1.ObjectMeta{Name: apimServiceName, Namespace: operatorNamespace},
+				Spec:       apimv1.APIMServiceSpec{Name: "outside-apim", ResourceGroup: "rg", Subscription: "sub"},
+			}
+			Expect(k8sClient.Create(ctx, apimService)).To(Succeed())
+			DeferCleanup(func() { Expect(client.IgnoreNotFound(k8sClient.Delete(ctx, apimService))).To(Succeed()) })
+
+			By("creating an APIMTag that uses it")
+			tag := &apimv1.APIMTag{
+				ObjectMeta: metav1.ObjectMeta{Name: tagName.Name, Namespace: tagName.Namespace},
+				Spec:       apimv1.APIMTagSpec{APIMService: apimServiceName, TagID: "outside", DisplayName: "Outside"},
+			}
+			Expect(k8sClient.Create(ctx, tag)).To(Succeed())
+			DeferCleanup(func() { Expect(client.IgnoreNotFound(k8sClient.Delete(ctx, tag))).To(Succeed()) })
+		})
+
+		reconcileTag := func() reconcile.Result {
+			controllerReconciler := &APIMTagReconciler{Client: k8sClient, Scheme: k8sClient.Scheme()}
+			result, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: tagName})
+			Expect(err).NotTo(HaveOccurred())
+			return result
+		}
+
+		It("should find the APIMService in OPERATOR_NAMESPACE", func() {
+			GinkgoT().Setenv("OPERATOR_NAMESPACE", operatorNamespace)
+
+			By("reconciling past the APIMService lookup up to the missing Azure identity")
+			Expect(reconcileTag().RequeueAfter).To(Equal(30 * time.Second))
+			var tag apimv1.APIMTag
+			Expect(k8sClient.Get(ctx, tagName, &tag)).To(Succeed())
+			Expect(tag.Status.Message).To(ContainSubstring("missing AZURE_CLIENT_ID or AZURE_TENANT_ID"))
+		})
+
+		It("should look for the APIMService in the default namespace without OPERATOR_NAMESPACE", func() {
+			GinkgoT().Setenv("OPERATOR_NAMESPACE", "")
+
+			By("stopping at the APIMService lookup without an error or a retry")
+			Expect(reconcileTag()).To(Equal(reconcile.Result{}))
+			var tag apimv1.APIMTag
+			Expect(k8sClient.Get(ctx, tagName, &tag)).To(Succeed())
+			Expect(tag.Status.Phase).To(BeEmpty())
+		})
+	})
+})
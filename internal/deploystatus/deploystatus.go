@@ -0,0 +1,175 @@
+// Package deploystatus reports API deployments back to the repository of the commit they were built from,
+// as GitHub Deployments or Azure Repos commit statuses, so release dashboards show when a change went live.
+package deploystatus
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Providers a deployment can be reported to.
+const (
+	// ProviderGitHub creates a GitHub Deployment of the commit with a deployment status.
+	ProviderGitHub = "github"
+	// ProviderAzureDevOps posts an Azure Repos commit status named after the environment. Azure DevOps
+	// Environments only record deployments of pipeline runs, so the commit status is what reaches dashboards.
+	ProviderAzureDevOps = "azureDevOps"
+)
+
+// States of a reported deployment.
+const (
+	StateSuccess = "success"
+	StateFailure = "failure"
+)
+
+const (
+	defaultGitHubURL      = "https://api.github.com"
+	defaultAzureDevOpsURL = "https://dev.azure.com"
+
+	// maxDescriptionLength is the longest description GitHub accepts.
+	maxDescriptionLength = 140
+	// statusGenre groups the commit statuses of the operator in Azure Repos.
+	statusGenre = "azure-apim-operator"
+)
+
+// httpClient reports deployments. The timeout keeps a slow provider from holding up the reconcile.
+var httpClient = &http.Client{Timeout: 10 * time.Second}
+
+// Deployment is a deployment of a commit to an environment.
+type Deployment struct {
+	// Repository is "<owner>/<repo>" on GitHub and "<organization>/<project>/<repository>" on Azure DevOps.
+	Repository string
+	// Commit is the SHA of the deployed commit.
+	Commit string
+	// Environment is the name of the environment, e.g. "production".
+	Environment string
+	// State is StateSuccess or StateFailure.
+	State string
+	// Description is a one-line summary of the outcome.
+	Description string
+	// EnvironmentURL is the URL of the deployed API. It can be empty.
+	EnvironmentURL string
+}
+
+// Report reports d to provider, authenticating with token. An empty baseURL selects the public service.
+func Report(ctx context.Context, provider, baseURL, token string, d Deployment) error {
+	if len(d.Description) > maxDescriptionLength {
+		d.Description = d.Description[:maxDescriptionLength-3] + "..."
+	}
+	switch provider {
+	case ProviderGitHub:
+		return reportGitHub(ctx, withDefault(baseURL, defaultGitHubURL), token, d)
+	case ProviderAzureDevOps:
+		return reportAzureDevOps(ctx, withDefault(baseURL, defaultAzureDevOpsURL), token, d)
+	default:
+		return fmt.Errorf("unknown deployment status provider %q", provider)
+	}
+}
+
+// reportGitHub creates a deployment of the commit and sets its status.
+func reportGitHub(ctx context.Context, baseURL, token string, d Deployment) error {
+	owner, repo, ok := strings.Cut(d.Repository, "/")
+	if !ok || owner == "" || repo == "" || strings.Contains(repo, "/") {
+		return fmt.Errorf("repository %q is not <owner>/<repo>", d.Repository)
+	}
+	deploymentsURL := fmt.Sprintf("%s/repos/%s/%s/deployments", baseURL, url.PathEscape(owner), url.PathEscape(repo))
+	auth := "Bearer " + token
+
+	// The commit was already checked by CI, so commit status checks and merging the default branch are skipped.
+	var created struct {
+		ID int64 `json:"id"`
+	}
+	err := post(ctx, deploymentsURL, auth, map[string]interface{}{
+		"ref":               d.Commit,
+		"environment":       d.Environment,
+		"description":       d.Description,
+		"auto_merge":        false,
+		"required_contexts": []string{},
+	}, &created)
+	if err != nil {
+		return fmt.Errorf("create GitHub deployment: %w", err)
+	}
+	err = post(ctx, fmt.Sprintf("%s/%d/statuses", deploymentsURL, created.ID), auth, map[string]interface{}{
+		"state":           d.State,
+		"description":     d.Description,
+		"environment_url": d.EnvironmentURL,
+	}, nil)
+	if err != nil {
+		return fmt.Errorf("set status of GitHub deployment %d: %w", created.ID, err)
+	}
+	return nil
+}
+
+// reportAzureDevOps posts a commit status named after the environment.
+func reportAzureDevOps(ctx context.Context, baseURL, token string, d Deployment) error {
+	parts := strings.Split(d.Repository, "/")
+	if len(parts) != 3 || parts[0] == "" || parts[1] == "" || parts[2] == "" {
+		return fmt.Errorf("repository %q is not <organization>/<project>/<repository>", d.Repository)
+	}
+	statusesURL := fmt.Sprintf("%s/%s/%s/_apis/git/repositories/%s/commits/%s/statuses?api-version=7.1",
+		baseURL, url.PathEscape(parts[0]), url.PathEscape(parts[1]), url.PathEscape(parts[2]), url.PathEscape(d.Commit))
+	state := "succeeded"
+	if d.State == StateFailure {
+		state = "failed"
+	}
+	err := post(ctx, statusesURL, "Basic "+base64.StdEncoding.EncodeToString([]byte(":"+token)), map[string]interface{}{
+		"state":       state,
+		"description": d.Description,
+		"targetUrl":   d.EnvironmentURL,
+		"context":     map[string]string{"name": d.Environment, "genre": statusGenre},
+	}, nil)
+	if err != nil {
+		return fmt.Errorf("post Azure DevOps commit status: %w", err)
+	}
+	return nil
+}
+
+// post sends body as JSON to target and decodes the answer into result, if it is not nil.
+func post(ctx context.Context, target, authorization string, body interface{}, result interface{}) error {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, target, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Authorization", authorization)
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode >= 300 {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		return fmt.Errorf("%s answered %s", req.URL.Host, resp.Status)
+	}
+	if result == nil {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(result)
+}
+
+func withDefault(value, fallback string) string {
+	if value == "" {
+		return fallback
+	}
+	return strings.TrimSuffix(value, "/")
+}
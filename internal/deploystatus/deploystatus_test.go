@@ -0,0 +1,112 @@
+package deploystatus
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestReportGitHub(t *testing.T) {
+	var statusBody map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Authorization"); got != "Bearer gh-token" {
+			t.Errorf("Authorization = %q, want the bearer token", got)
+		}
+		switch r.URL.Path {
+		case "/repos/contoso/orders/deployments":
+			var body map[string]interface{}
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			if body["ref"] != "abc123" || body["environment"] != "production" {
+				t.Errorf("deployment = %v, want ref abc123 in production", body)
+			}
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"id": 42}`))
+		case "/repos/contoso/orders/deployments/42/statuses":
+			_ = json.NewDecoder(r.Body).Decode(&statusBody)
+			w.WriteHeader(http.StatusCreated)
+		default:
+			t.Errorf("unexpected request to %s", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	err := Report(context.Background(), ProviderGitHub, server.URL+"/", "gh-token", Deployment{
+		Repository:     "contoso/orders",
+		Commit:         "abc123",
+		Environment:    "production",
+		State:          StateSuccess,
+		Description:    strings.Repeat("x", 200),
+		EnvironmentURL: "https://apim.example.com/orders",
+	})
+	if err != nil {
+		t.Fatalf("Report() error = %v", err)
+	}
+	if statusBody["state"] != StateSuccess || statusBody["environment_url"] != "https://apim.example.com/orders" {
+		t.Errorf("deployment status = %v, want success with the gateway URL", statusBody)
+	}
+	if description, _ := statusBody["description"].(string); len(description) != maxDescriptionLength {
+		t.Errorf("description has %d characters, want it cut to %d", len(description), maxDescriptionLength)
+	}
+}
+
+func TestReportAzureDevOps(t *testing.T) {
+	var body map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		wantAuth := "Basic " + base64.StdEncoding.EncodeToString([]byte(":pat"))
+		if got := r.Header.Get("Authorization"); got != wantAuth {
+			t.Errorf("Authorization = %q, want %q", got, wantAuth)
+		}
+		if want := "/contoso/Shop/_apis/git/repositories/orders-api/commits/abc123/statuses"; r.URL.Path != want {
+			t.Errorf("path = %s, want %s", r.URL.Path, want)
+		}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+
+	err := Report(context.Background(), ProviderAzureDevOps, server.URL, "pat", Deployment{
+		Repository:  "contoso/Shop/orders-api",
+		Commit:      "abc123",
+		Environment: "production",
+		State:       StateFailure,
+		Description: "Import failed",
+	})
+	if err != nil {
+		t.Fatalf("Report() error = %v", err)
+	}
+	context, _ := body["context"].(map[string]interface{})
+	if body["state"] != "failed" || context["name"] != "production" {
+		t.Errorf("commit status = %v, want failed in context production", body)
+	}
+}
+
+func TestReportErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name     string
+		provider string
+		repo     string
+	}{
+		{name: "unknown provider", provider: "gitlab", repo: "contoso/orders"},
+		{name: "GitHub repository without owner", provider: ProviderGitHub, repo: "orders"},
+		{name: "Azure DevOps repository without project", provider: ProviderAzureDevOps, repo: "contoso/orders"},
+		{name: "rejected token", provider: ProviderGitHub, repo: "contoso/orders"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Report(context.Background(), tt.provider, server.URL, "secret-token", Deployment{Repository: tt.repo, Commit: "abc123"})
+			if err == nil || strings.Contains(err.Error(), "secret-token") {
+				t.Errorf("Report() error = %v, want an error without the token", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,314 @@
+package export
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apimv1 "github.com/hedinit/azure-apim-operator/api/v1"
+	"github.com/hedinit/azure-apim-operator/internal/apim"
+)
+
+// Files and folders of the artifacts layout used by the APIOps extractor and publisher
+// (https://github.com/Azure/apiops). Each entity has a folder named after its APIM ID, holding an
+// information file with the entity's ARM properties under "properties".
+const (
+	apiOpsAPIsDir            = "apis"
+	apiOpsProductsDir        = "products"
+	apiOpsTagsDir            = "tags"
+	apiOpsOperationsDir      = "operations"
+	apiOpsAPIInformation     = "apiInformation.json"
+	apiOpsProductInformation = "productInformation.json"
+	apiOpsTagInformation     = "tagInformation.json"
+	apiOpsPolicy             = "policy.xml"
+	// apiOpsLinkedAPIs lists the APIs of a product or tag.
+	apiOpsLinkedAPIs = "apis.json"
+)
+
+// apiOpsLink is an entry of an apis.json file.
+type apiOpsLink struct {
+	Name string `json:"name"`
+}
+
+// WriteAPIOps writes the APIMAPI, APIMProduct, APIMTag and APIMInboundPolicy objects to dir in the APIOps
+// artifacts layout, so the APIOps publisher can deploy them. Other objects, such as the APIMService, have no
+// APIOps equivalent and are skipped. Definitions are not written, because the operator imports them from
+// the running workload.
+func WriteAPIOps(dir string, objects []client.Object) error {
+	products, productAPIs := map[string]bool{}, map[string][]string{}
+	tags, tagAPIs := map[string]bool{}, map[string][]string{}
+	for _, obj := range objects {
+		switch o := obj.(type) {
+		case *apimv1.APIMTag:
+			if err := writeAPIOpsJSON(filepath.Join(dir, apiOpsTagsDir, o.Spec.TagID, apiOpsTagInformation), map[string]interface{}{
+				"displayName": o.Spec.DisplayName,
+			}); err != nil {
+				return err
+			}
+			tags[o.Spec.TagID] = true
+		case *apimv1.APIMProduct:
+			state := "notPublished"
+			if o.Spec.Published {
+				state = "published"
+			}
+			if err := writeAPIOpsJSON(filepath.Join(dir, apiOpsProductsDir, o.Spec.ProductID, apiOpsProductInformation), map[string]interface{}{
+				"displayName": o.Spec.DisplayName,
+				"description": o.Spec.Description,
+				"state":       state,
+			}); err != nil {
+				return err
+			}
+			products[o.Spec.ProductID] = true
+		case *apimv1.APIMAPI:
+			if err := writeAPIOpsJSON(filepath.Join(dir, apiOpsAPIsDir, o.Spec.APIID, apiOpsAPIInformation), map[string]interface{}{
+				"displayName":          o.Spec.APIID,
+				"path":                 strings.TrimPrefix(o.Spec.RoutePrefix, "/"),
+				"serviceUrl":           o.Spec.ServiceURL,
+				"subscriptionRequired": o.Spec.SubscriptionRequired,
+				"protocols":            []string{"https"},
+			}); err != nil {
+				return err
+			}
+			for _, productID := range o.Spec.ProductIDs {
+				productAPIs[productID] = append(productAPIs[productID], o.Spec.APIID)
+			}
+			for _, tagID := range o.Spec.TagIDs {
+				tagAPIs[tagID] = append(tagAPIs[tagID], o.Spec.APIID)
+			}
+		case *apimv1.APIMInboundPolicy:
+			if o.Spec.APIID == "" || o.Spec.PolicyContent == "" {
+				continue
+			}
+			path := filepath.Join(dir, apiOpsAPIsDir, o.Spec.APIID, apiOpsPolicy)
+			if o.Spec.OperationID != "" {
+				path = filepath.Join(dir, apiOpsAPIsDir, o.Spec.APIID, apiOpsOperationsDir, o.Spec.OperationID, apiOpsPolicy)
+			}
+			if err := writeAPIOpsFile(path, []byte(o.Spec.PolicyContent)); err != nil {
+				return err
+			}
+		}
+	}
+
+	// Every exported product and tag gets an apis.json, even an empty one, so the publisher unlinks removed APIs.
+	// Assignments to products and tags that are not among the objects are left to the APIM instance.
+	if err := writeAPIOpsLinks(filepath.Join(dir, apiOpsProductsDir), products, productAPIs); err != nil {
+		return err
+	}
+	return writeAPIOpsLinks(filepath.Join(dir, apiOpsTagsDir), tags, tagAPIs)
+}
+
+// writeAPIOpsLinks writes the apis.json file of each of the ids in dir.
+func writeAPIOpsLinks(dir string, ids map[string]bool, apis map[string][]string) error {
+	for id := range ids {
+		apiIDs := apis[id]
+		sort.Strings(apiIDs)
+		entries := make([]apiOpsLink, 0, len(apiIDs))
+		for _, apiID := range apiIDs {
+			entries = append(entries, apiOpsLink{Name: apiID})
+		}
+		if err := writeAPIOpsJSON(filepath.Join(dir, id, apiOpsLinkedAPIs), entries); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ReadAPIOps reads an APIOps artifacts folder written by the APIOps extractor and returns the same resources
+// Export returns for a live instance: the APIMService, when opts names the Azure service, then tags, products,
+// APIs and API and operation policies. Specifications, the service policy and other entities in the folder
+// are ignored.
+func ReadAPIOps(dir string, opts Options) ([]client.Object, error) {
+	if opts.APIMServiceName == "" {
+		opts.APIMServiceName = opts.Service.ServiceName
+	}
+	if opts.APIMServiceNamespace == "" {
+		opts.APIMServiceNamespace = opts.Namespace
+	}
+	if opts.APIMServiceName == "" {
+		return nil, errors.New("the APIMService name is required")
+	}
+
+	var objects []client.Object
+	if opts.Service.SubscriptionID != "" && opts.Service.ResourceGroup != "" && opts.Service.ServiceName != "" {
+		objects = append(objects, serviceObject(opts))
+	}
+
+	tagNames, err := apiOpsEntities(dir, apiOpsTagsDir)
+	if err != nil {
+		return nil, err
+	}
+	apiTags := map[string][]string{}
+	for _, name := range tagNames {
+		var tag apim.TagDetails
+		if err := readAPIOpsJSON(filepath.Join(dir, apiOpsTagsDir, name, apiOpsTagInformation), &tag); err != nil {
+			return nil, err
+		}
+		tag.Name = name
+		objects = append(objects, tagObject(opts, tag))
+		if err := readAPIOpsLinks(filepath.Join(dir, apiOpsTagsDir, name, apiOpsLinkedAPIs), name, apiTags); err != nil {
+			return nil, err
+		}
+	}
+
+	productNames, err := apiOpsEntities(dir, apiOpsProductsDir)
+	if err != nil {
+		return nil, err
+	}
+	apiProducts := map[string][]string{}
+	for _, name := range productNames {
+		var product apim.ProductDetails
+		if err := readAPIOpsJSON(filepath.Join(dir, apiOpsProductsDir, name, apiOpsProductInformation), &product); err != nil {
+			return nil, err
+		}
+		product.Name = name
+		objects = append(objects, productObject(opts, product))
+		if err := readAPIOpsLinks(filepath.Join(dir, apiOpsProductsDir, name, apiOpsLinkedAPIs), name, apiProducts); err != nil {
+			return nil, err
+		}
+	}
+
+	apiNames, err := apiOpsEntities(dir, apiOpsAPIsDir)
+	if err != nil {
+		return nil, err
+	}
+	var policies []client.Object
+	for _, name := range apiNames {
+		// Non-current revisions are extracted to "<api>;rev=<n>" folders; like Export, only current ones are read.
+		if strings.Contains(name, ";rev=") {
+			continue
+		}
+		var api apim.APIDetails
+		if err := readAPIOpsJSON(filepath.Join(dir, apiOpsAPIsDir, name, apiOpsAPIInformation), &api); err != nil {
+			return nil, err
+		}
+		api.Name = name
+		objects = append(objects, apiObject(opts, api, apiProducts[name], apiTags[name]))
+
+		policy, err := readAPIOpsPolicy(filepath.Join(dir, apiOpsAPIsDir, name, apiOpsPolicy))
+		if err != nil {
+			return nil, err
+		}
+		if policy != "" {
+			policies = append(policies, policyObject(opts, name, policy))
+		}
+
+		operations, err := apiOpsEntities(filepath.Join(dir, apiOpsAPIsDir, name), apiOpsOperationsDir)
+		if err != nil {
+			return nil, err
+		}
+		for _, operation := range operations {
+			policy, err := readAPIOpsPolicy(filepath.Join(dir, apiOpsAPIsDir, name, apiOpsOperationsDir, operation, apiOpsPolicy))
+			if err != nil {
+				return nil, err
+			}
+			if policy != "" {
+				obj := policyObject(opts, name, policy)
+				obj.Spec.OperationID = operation
+				obj.Name = objectName(name + "-" + operation)
+				policies = append(policies, obj)
+			}
+		}
+	}
+
+	return append(objects, policies...), nil
+}
+
+// apiOpsEntities returns the sorted names of the entity folders in dir/kind. A missing kind folder has no entities.
+func apiOpsEntities(dir, kind string) ([]string, error) {
+	entries, err := os.ReadDir(filepath.Join(dir, kind))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, entry := range entries {
+		if entry.IsDir() {
+			names = append(names, entry.Name())
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// readAPIOpsLinks adds id to the entry of every API listed in the apis.json file at path. Entries are objects
+// with a name in current APIOps versions and plain API names in older ones. A missing file lists no APIs.
+func readAPIOpsLinks(path, id string, byAPI map[string][]string) error {
+	content, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	var entries []json.RawMessage
+	if err := json.Unmarshal(content, &entries); err != nil {
+		return fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	for _, entry := range entries {
+		var link apiOpsLink
+		if err := json.Unmarshal(entry, &link); err != nil {
+			if err := json.Unmarshal(entry, &link.Name); err != nil {
+				return fmt.Errorf("failed to parse %s: %w", path, err)
+			}
+		}
+		if link.Name != "" {
+			byAPI[link.Name] = append(byAPI[link.Name], id)
+		}
+	}
+	return nil
+}
+
+// readAPIOpsPolicy returns the policy at path, or "" when there is none or it is APIM's default policy.
+func readAPIOpsPolicy(path string) (string, error) {
+	policy, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	if isDefaultPolicy(string(policy)) {
+		return "", nil
+	}
+	return string(policy), nil
+}
+
+// readAPIOpsJSON decodes the information file at path into v, whose properties sit under "properties".
+func readAPIOpsJSON(path string, v interface{}) error {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(content, v); err != nil {
+		return fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return nil
+}
+
+// writeAPIOpsJSON writes an information file. Properties maps are wrapped in "properties" like the extractor does.
+func writeAPIOpsJSON(path string, v interface{}) error {
+	if properties, ok := v.(map[string]interface{}); ok {
+		v = map[string]interface{}{"properties": properties}
+	}
+	content, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeAPIOpsFile(path, append(content, '\n'))
+}
+
+func writeAPIOpsFile(path string, content []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, content, 0o644)
+}
@@ -0,0 +1,122 @@
+package export
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apimv1 "github.com/hedinit/azure-apim-operator/api/v1"
+	"github.com/hedinit/azure-apim-operator/internal/apim"
+)
+
+const ratePolicy = "<policies><inbound><base /><rate-limit calls=\"10\" renewal-period=\"60\" /></inbound></policies>"
+
+func TestAPIOpsRoundTrip(t *testing.T) {
+	opts := Options{
+		Service:              apim.ExportConfig{SubscriptionID: "sub", ResourceGroup: "rg", ServiceName: "apim-prod"},
+		Namespace:            "payments",
+		APIMServiceName:      "apim-prod",
+		APIMServiceNamespace: "payments",
+	}
+	var tag apim.TagDetails
+	tag.Name = "internal"
+	tag.Properties.DisplayName = "Internal"
+	var product apim.ProductDetails
+	product.Name = "starter"
+	product.Properties.DisplayName = "Starter"
+	product.Properties.State = "published"
+	var api apim.APIDetails
+	api.Name = "Orders_API"
+	api.Properties.Path = "orders"
+	api.Properties.ServiceURL = "https://orders.example.com"
+	api.Properties.SubscriptionRequired = true
+	operationPolicy := policyObject(opts, "Orders_API", ratePolicy)
+	operationPolicy.Spec.OperationID = "get-order"
+	operationPolicy.Name = "orders-api-get-order"
+
+	objects := []client.Object{
+		serviceObject(opts),
+		tagObject(opts, tag),
+		productObject(opts, product),
+		apiObject(opts, api, []string{"starter"}, []string{"internal"}),
+		policyObject(opts, "Orders_API", ratePolicy),
+		operationPolicy,
+	}
+	dir := t.TempDir()
+	if err := WriteAPIOps(dir, objects); err != nil {
+		t.Fatalf("WriteAPIOps() error = %v", err)
+	}
+	for _, file := range []string{
+		"apis/Orders_API/apiInformation.json",
+		"apis/Orders_API/policy.xml",
+		"apis/Orders_API/operations/get-order/policy.xml",
+		"products/starter/productInformation.json",
+		"products/starter/apis.json",
+		"tags/internal/tagInformation.json",
+		"tags/internal/apis.json",
+	} {
+		if _, err := os.Stat(filepath.Join(dir, file)); err != nil {
+			t.Errorf("%s: %v", file, err)
+		}
+	}
+
+	got, err := ReadAPIOps(dir, opts)
+	if err != nil {
+		t.Fatalf("ReadAPIOps() error = %v", err)
+	}
+	var gotYAML, wantYAML bytes.Buffer
+	if err := WriteYAML(&gotYAML, got); err != nil {
+		t.Fatal(err)
+	}
+	if err := WriteYAML(&wantYAML, objects); err != nil {
+		t.Fatal(err)
+	}
+	if gotYAML.String() != wantYAML.String() {
+		t.Errorf("ReadAPIOps() =\n%s\nwant\n%s", gotYAML.String(), wantYAML.String())
+	}
+}
+
+func TestReadAPIOps(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"apis/echo-api/apiInformation.json":          `{"properties":{"displayName":"Echo","path":"echo","serviceUrl":"https://echo.example.com"}}`,
+		"apis/echo-api/policy.xml":                   "<policies>\n  <inbound>\n    <base />\n  </inbound>\n  <backend>\n    <base />\n  </backend>\n  <outbound>\n    <base />\n  </outbound>\n  <on-error>\n    <base />\n  </on-error>\n</policies>",
+		"apis/echo-api;rev=2/apiInformation.json":    `{"properties":{"displayName":"Echo","path":"echo"}}`,
+		"products/unlimited/productInformation.json": `{"properties":{"displayName":"Unlimited","state":"notPublished"}}`,
+		"products/unlimited/apis.json":               `["echo-api"]`,
+		"policy.xml":                                 ratePolicy,
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	objects, err := ReadAPIOps(dir, Options{Namespace: "payments", APIMServiceName: "apim-prod"})
+	if err != nil {
+		t.Fatalf("ReadAPIOps() error = %v", err)
+	}
+	if len(objects) != 2 {
+		t.Fatalf("ReadAPIOps() returned %d objects, want the product and the current API revision: %+v", len(objects), objects)
+	}
+	product, ok := objects[0].(*apimv1.APIMProduct)
+	if !ok || product.Spec.ProductID != "unlimited" || product.Spec.Published {
+		t.Errorf("objects[0] = %+v, want unpublished product unlimited", objects[0])
+	}
+	api, ok := objects[1].(*apimv1.APIMAPI)
+	if !ok || api.Spec.APIID != "echo-api" || api.Spec.RoutePrefix != "/echo" || !reflect.DeepEqual(api.Spec.ProductIDs, []string{"unlimited"}) {
+		t.Errorf("objects[1] = %+v, want API echo-api at /echo in product unlimited", objects[1])
+	}
+
+	if _, err := ReadAPIOps(dir, Options{Namespace: "payments"}); err == nil {
+		t.Error("ReadAPIOps() without an APIMService name: error = nil")
+	}
+}
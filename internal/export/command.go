@@ -0,0 +1,94 @@
+package export
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/hedinit/azure-apim-operator/internal/identity"
+)
+
+// Output formats of the export subcommand.
+const (
+	// FormatManifests writes the operator's custom resources as YAML.
+	FormatManifests = "manifests"
+	// FormatAPIOps writes the artifacts folder of the APIOps publisher.
+	FormatAPIOps = "apiops"
+)
+
+// Run implements the export subcommand: it parses args, exports the APIM instance they name, or reads the
+// APIOps artifacts folder given with --from-apiops, and writes the resources as YAML to stdout or to the file
+// given with --output, or with --format apiops as an APIOps artifacts folder to the --output directory.
+// The management token is acquired with DefaultAzureCredential, so it works with `az login` locally
+// and with workload identity in a pod.
+func Run(ctx context.Context, args []string, stdout io.Writer) error {
+	var opts Options
+	var output, format, fromAPIOps string
+	fs := flag.NewFlagSet("export", flag.ContinueOnError)
+	fs.StringVar(&opts.Service.SubscriptionID, "subscription", "", "Azure subscription ID of the APIM service.")
+	fs.StringVar(&opts.Service.ResourceGroup, "resource-group", "", "Azure resource group of the APIM service.")
+	fs.StringVar(&opts.Service.ServiceName, "service", "", "Name of the APIM service in Azure.")
+	fs.StringVar(&opts.Namespace, "namespace", "default", "Namespace of the generated resources.")
+	fs.StringVar(&opts.APIMServiceName, "apimservice-name", "",
+		"Name of the generated APIMService resource. Defaults to --service.")
+	fs.StringVar(&opts.APIMServiceNamespace, "apimservice-namespace", "",
+		"Namespace of the generated APIMService resource. Defaults to --namespace.")
+	fs.StringVar(&output, "output", "",
+		"File to write the manifests to, or directory to write the APIOps artifacts to. Defaults to standard output.")
+	fs.StringVar(&format, "format", FormatManifests, "Output format: manifests or apiops.")
+	fs.StringVar(&fromAPIOps, "from-apiops", "",
+		"APIOps artifacts folder to read instead of the APIM instance. --subscription and --resource-group are optional.")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if format != FormatManifests && format != FormatAPIOps {
+		return fmt.Errorf("unknown --format %q, want %s or %s", format, FormatManifests, FormatAPIOps)
+	}
+	if format == FormatAPIOps && output == "" {
+		return errors.New("--output is required with --format apiops")
+	}
+
+	var objects []client.Object
+	var err error
+	if fromAPIOps != "" {
+		if opts.Service.ServiceName == "" && opts.APIMServiceName == "" {
+			return errors.New("--service or --apimservice-name is required")
+		}
+		objects, err = ReadAPIOps(fromAPIOps, opts)
+	} else {
+		if opts.Service.SubscriptionID == "" || opts.Service.ResourceGroup == "" || opts.Service.ServiceName == "" {
+			return errors.New("--subscription, --resource-group and --service are required")
+		}
+		var token string
+		token, err = identity.GetManagementToken3(ctx, identity.AzurePublic)
+		if err != nil {
+			return fmt.Errorf("failed to acquire management token: %w", err)
+		}
+		opts.Service.BearerToken = token
+		objects, err = Export(ctx, opts)
+	}
+	if err != nil {
+		return err
+	}
+
+	if format == FormatAPIOps {
+		return WriteAPIOps(output, objects)
+	}
+	if output == "" {
+		return WriteYAML(stdout, objects)
+	}
+	f, err := os.Create(output)
+	if err != nil {
+		return err
+	}
+	if err := WriteYAML(f, objects); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
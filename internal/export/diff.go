@@ -0,0 +1,198 @@
+package export
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apimv1 "github.com/hedinit/azure-apim-operator/api/v1"
+)
+
+// Difference is one way in which the cluster resources of an APIM service differ from the live instance.
+type Difference struct {
+	// Kind is the resource kind, e.g. APIMAPI.
+	Kind string
+	// ID is the APIM ID of the entity: the API, product or tag ID, or the API ID of a policy.
+	ID string
+	// Field is the differing spec field. It is empty when the entity exists on one side only.
+	Field string
+	// Cluster is the value in the cluster, or empty when the entity only exists in APIM.
+	Cluster string
+	// APIM is the value in APIM, or empty when the entity only exists in the cluster.
+	APIM string
+}
+
+// String formats the difference as one line, e.g. `APIMAPI orders: serviceUrl: cluster "a", APIM "b"`.
+func (d Difference) String() string {
+	switch {
+	case d.Field != "":
+		return fmt.Sprintf("%s %s: %s: cluster %q, APIM %q", d.Kind, d.ID, d.Field, d.Cluster, d.APIM)
+	case d.APIM == "":
+		return fmt.Sprintf("%s %s: only in cluster", d.Kind, d.ID)
+	default:
+		return fmt.Sprintf("%s %s: only in APIM", d.Kind, d.ID)
+	}
+}
+
+// ClusterObjects lists the APIMAPI, APIMProduct, APIMTag and API-level APIMInboundPolicy resources that
+// refer to the APIMService apimServiceName. An empty namespace lists all namespaces.
+func ClusterObjects(ctx context.Context, c client.Reader, apimServiceName, namespace string) ([]client.Object, error) {
+	var objects []client.Object
+
+	var apis apimv1.APIMAPIList
+	if err := c.List(ctx, &apis, client.InNamespace(namespace)); err != nil {
+		return nil, fmt.Errorf("failed to list APIMAPIs: %w", err)
+	}
+	for i := range apis.Items {
+		if apis.Items[i].Spec.APIMService == apimServiceName {
+			objects = append(objects, &apis.Items[i])
+		}
+	}
+
+	var products apimv1.APIMProductList
+	if err := c.List(ctx, &products, client.InNamespace(namespace)); err != nil {
+		return nil, fmt.Errorf("failed to list APIMProducts: %w", err)
+	}
+	for i := range products.Items {
+		if products.Items[i].Spec.APIMService == apimServiceName {
+			objects = append(objects, &products.Items[i])
+		}
+	}
+
+	var tags apimv1.APIMTagList
+	if err := c.List(ctx, &tags, client.InNamespace(namespace)); err != nil {
+		return nil, fmt.Errorf("failed to list APIMTags: %w", err)
+	}
+	for i := range tags.Items {
+		if tags.Items[i].Spec.APIMService == apimServiceName {
+			objects = append(objects, &tags.Items[i])
+		}
+	}
+
+	// Operation-level policies are skipped, since Export does not read them.
+	var policies apimv1.APIMInboundPolicyList
+	if err := c.List(ctx, &policies, client.InNamespace(namespace)); err != nil {
+		return nil, fmt.Errorf("failed to list APIMInboundPolicies: %w", err)
+	}
+	for i := range policies.Items {
+		if policies.Items[i].Spec.APIMService == apimServiceName && policies.Items[i].Spec.OperationID == "" {
+			objects = append(objects, &policies.Items[i])
+		}
+	}
+
+	return objects, nil
+}
+
+// Diff compares cluster resources with the resources Export generated from the live APIM instance.
+// Entities are matched by kind and case-insensitive APIM ID; APIMService resources are ignored.
+// The differences are sorted by kind and ID.
+func Diff(cluster, live []client.Object) []Difference {
+	clusterEntities := entitiesByKey(cluster)
+	liveEntities := entitiesByKey(live)
+
+	var diffs []Difference
+	for key, c := range clusterEntities {
+		l, ok := liveEntities[key]
+		if !ok {
+			diffs = append(diffs, Difference{Kind: c.kind, ID: c.id, Cluster: c.id})
+			continue
+		}
+		for i, f := range c.fields {
+			if f.value != l.fields[i].value {
+				diffs = append(diffs, Difference{Kind: c.kind, ID: c.id, Field: f.name, Cluster: f.value, APIM: l.fields[i].value})
+			}
+		}
+	}
+	for key, l := range liveEntities {
+		if _, ok := clusterEntities[key]; !ok {
+			diffs = append(diffs, Difference{Kind: l.kind, ID: l.id, APIM: l.id})
+		}
+	}
+
+	sort.Slice(diffs, func(i, j int) bool {
+		if diffs[i].Kind != diffs[j].Kind {
+			return diffs[i].Kind < diffs[j].Kind
+		}
+		if diffs[i].ID != diffs[j].ID {
+			return diffs[i].ID < diffs[j].ID
+		}
+		return diffs[i].Field < diffs[j].Field
+	})
+	return diffs
+}
+
+// entity is the comparable part of a resource. Both sides of a kind list the same fields in the same order.
+type entity struct {
+	kind   string
+	id     string
+	fields []entityField
+}
+
+type entityField struct {
+	name  string
+	value string
+}
+
+func entitiesByKey(objects []client.Object) map[string]entity {
+	entities := map[string]entity{}
+	for _, obj := range objects {
+		e, ok := toEntity(obj)
+		if ok {
+			entities[e.kind+"/"+strings.ToLower(e.id)] = e
+		}
+	}
+	return entities
+}
+
+// toEntity normalizes the fields that APIM stores differently from the spec: route prefixes lose a trailing
+// slash, ID lists are sorted and policies are compared without the whitespace between elements.
+func toEntity(obj client.Object) (entity, bool) {
+	switch o := obj.(type) {
+	case *apimv1.APIMAPI:
+		return entity{kind: "APIMAPI", id: o.Spec.APIID, fields: []entityField{
+			{"routePrefix", "/" + strings.Trim(o.Spec.RoutePrefix, "/")},
+			{"serviceUrl", o.Spec.ActiveServiceURL()},
+			{"subscriptionRequired", strconv.FormatBool(o.Spec.SubscriptionRequired)},
+			{"productIds", sortedList(o.Spec.ProductIDs)},
+			{"tagIds", sortedList(o.Spec.TagIDs)},
+		}}, true
+	case *apimv1.APIMProduct:
+		return entity{kind: "APIMProduct", id: o.Spec.ProductID, fields: []entityField{
+			{"displayName", o.Spec.DisplayName},
+			{"description", o.Spec.Description},
+			{"published", strconv.FormatBool(o.Spec.Published)},
+		}}, true
+	case *apimv1.APIMTag:
+		return entity{kind: "APIMTag", id: o.Spec.TagID, fields: []entityField{
+			{"displayName", o.Spec.DisplayName},
+		}}, true
+	case *apimv1.APIMInboundPolicy:
+		return entity{kind: "APIMInboundPolicy", id: o.Spec.APIID, fields: []entityField{
+			{"policyContent", compactXML(o.Spec.PolicyContent)},
+		}}, true
+	}
+	return entity{}, false
+}
+
+func sortedList(values []string) string {
+	sorted := make([]string, len(values))
+	for i, v := range values {
+		sorted[i] = strings.ToLower(v)
+	}
+	sort.Strings(sorted)
+	return strings.Join(sorted, ",")
+}
+
+// interElementSpace matches the whitespace between two XML tags.
+var interElementSpace = regexp.MustCompile(`>\s+<`)
+
+// compactXML removes the whitespace between elements and collapses the remaining whitespace runs.
+func compactXML(content string) string {
+	content = interElementSpace.ReplaceAllString(strings.TrimSpace(content), "><")
+	return strings.Join(strings.Fields(content), " ")
+}
@@ -0,0 +1,87 @@
+package export
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	apimv1 "github.com/hedinit/azure-apim-operator/api/v1"
+)
+
+func TestDiff(t *testing.T) {
+	clusterAPI := &apimv1.APIMAPI{Spec: apimv1.APIMAPISpec{
+		APIID: "orders", RoutePrefix: "/orders/", ServiceURL: "https://orders.example.com",
+		SubscriptionRequired: true, ProductIDs: []string{"Starter", "gold"},
+	}}
+	liveAPI := &apimv1.APIMAPI{Spec: apimv1.APIMAPISpec{
+		APIID: "Orders", RoutePrefix: "/orders", ServiceURL: "https://orders-v2.example.com",
+		SubscriptionRequired: true, ProductIDs: []string{"gold", "starter"},
+	}}
+	clusterPolicy := &apimv1.APIMInboundPolicy{Spec: apimv1.APIMInboundPolicySpec{
+		APIID: "orders", PolicyContent: "<policies>\n  <inbound><base /></inbound>\n</policies>",
+	}}
+	livePolicy := &apimv1.APIMInboundPolicy{Spec: apimv1.APIMInboundPolicySpec{
+		APIID: "orders", PolicyContent: "<policies>\r\n\t<inbound>\r\n\t\t<base />\r\n\t</inbound>\r\n</policies>",
+	}}
+	clusterOnly := &apimv1.APIMTag{Spec: apimv1.APIMTagSpec{TagID: "internal"}}
+	liveOnly := &apimv1.APIMProduct{Spec: apimv1.APIMProductSpec{ProductID: "unlimited"}}
+	service := &apimv1.APIMService{}
+
+	diffs := Diff(
+		[]client.Object{clusterAPI, clusterPolicy, clusterOnly},
+		[]client.Object{service, liveAPI, livePolicy, liveOnly},
+	)
+
+	want := []string{
+		`APIMAPI orders: serviceUrl: cluster "https://orders.example.com", APIM "https://orders-v2.example.com"`,
+		"APIMProduct unlimited: only in APIM",
+		"APIMTag internal: only in cluster",
+	}
+	if len(diffs) != len(want) {
+		t.Fatalf("Diff() = %v, want %v", diffs, want)
+	}
+	for i := range want {
+		if got := diffs[i].String(); got != want[i] {
+			t.Errorf("Diff()[%d] = %s, want %s", i, got, want[i])
+		}
+	}
+}
+
+func TestClusterObjects(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := apimv1.AddToScheme(scheme); err != nil {
+		t.Fatalf("AddToScheme() error = %v", err)
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		&apimv1.APIMAPI{ObjectMeta: metav1.ObjectMeta{Name: "orders", Namespace: "shop"}, Spec: apimv1.APIMAPISpec{APIMService: "prod"}},
+		&apimv1.APIMAPI{ObjectMeta: metav1.ObjectMeta{Name: "orders", Namespace: "test"}, Spec: apimv1.APIMAPISpec{APIMService: "test"}},
+		&apimv1.APIMTag{ObjectMeta: metav1.ObjectMeta{Name: "internal", Namespace: "other"}, Spec: apimv1.APIMTagSpec{APIMService: "prod"}},
+		&apimv1.APIMInboundPolicy{ObjectMeta: metav1.ObjectMeta{Name: "orders", Namespace: "shop"},
+			Spec: apimv1.APIMInboundPolicySpec{APIMService: "prod", APIID: "orders"}},
+		&apimv1.APIMInboundPolicy{ObjectMeta: metav1.ObjectMeta{Name: "orders-get", Namespace: "shop"},
+			Spec: apimv1.APIMInboundPolicySpec{APIMService: "prod", APIID: "orders", OperationID: "get"}},
+	).Build()
+
+	tests := map[string][]string{
+		"":     {"shop/orders", "other/internal", "shop/orders"},
+		"shop": {"shop/orders", "shop/orders"},
+	}
+	for namespace, want := range tests {
+		objects, err := ClusterObjects(context.Background(), c, "prod", namespace)
+		if err != nil {
+			t.Fatalf("ClusterObjects(%q) error = %v", namespace, err)
+		}
+		var got []string
+		for _, obj := range objects {
+			got = append(got, obj.GetNamespace()+"/"+obj.GetName())
+		}
+		if fmt.Sprint(got) != fmt.Sprint(want) {
+			t.Errorf("ClusterObjects(%q) = %v, want %v", namespace, got, want)
+		}
+	}
+}
@@ -0,0 +1,227 @@
+// Package export reads the APIs, products, tags and policies of an existing Azure API Management
+// instance and turns them into operator custom resources, so an instance that was built by hand
+// can be brought under GitOps without writing every manifest.
+package export
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+
+	apimv1 "github.com/hedinit/azure-apim-operator/api/v1"
+	"github.com/hedinit/azure-apim-operator/internal/apim"
+)
+
+// Options selects the APIM instance to export and where the generated resources go.
+type Options struct {
+	// Service identifies the APIM instance and carries the management token.
+	Service apim.ExportConfig
+	// Client reads the APIM instance. Nil reads it from the public Azure Management API.
+	Client *apim.Client
+	// Namespace is the namespace of the generated APIMAPI, APIMProduct, APIMTag and APIMInboundPolicy resources.
+	Namespace string
+	// APIMServiceName is the name of the generated APIMService resource the others refer to.
+	// It defaults to the Azure service name.
+	APIMServiceName string
+	// APIMServiceNamespace is the namespace of the generated APIMService resource. It defaults to Namespace.
+	APIMServiceNamespace string
+}
+
+// Export reads the APIM instance and returns one resource per service, tag, product, API and
+// API-level policy, in that order. APIs are exported with adoptionPolicy Adopt, so applying the
+// output takes over the existing APIs instead of failing on them. APIM's default policy, which only
+// inherits from the parent scopes, is not exported.
+func Export(ctx context.Context, opts Options) ([]client.Object, error) {
+	if opts.APIMServiceName == "" {
+		opts.APIMServiceName = opts.Service.ServiceName
+	}
+	if opts.APIMServiceNamespace == "" {
+		opts.APIMServiceNamespace = opts.Namespace
+	}
+
+	objects := []client.Object{serviceObject(opts)}
+
+	tags, err := opts.Client.ListTags(ctx, opts.Service)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(tags, func(i, j int) bool { return tags[i].Name < tags[j].Name })
+	for _, tag := range tags {
+		objects = append(objects, tagObject(opts, tag))
+	}
+
+	products, err := opts.Client.ListProducts(ctx, opts.Service)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(products, func(i, j int) bool { return products[i].Name < products[j].Name })
+	for _, product := range products {
+		objects = append(objects, productObject(opts, product))
+	}
+
+	apis, err := opts.Client.ListAPIs(ctx, opts.Service)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(apis, func(i, j int) bool { return apis[i].Name < apis[j].Name })
+	var policies []client.Object
+	for _, api := range apis {
+		productIDs, err := opts.Client.ListAPIProductIDs(ctx, opts.Service, api.Name)
+		if err != nil {
+			return nil, err
+		}
+		tagIDs, err := opts.Client.ListAPITagIDs(ctx, opts.Service, api.Name)
+		if err != nil {
+			return nil, err
+		}
+		objects = append(objects, apiObject(opts, api, productIDs, tagIDs))
+
+		policy, err := opts.Client.GetAPIPolicy(ctx, opts.Service, api.Name)
+		if err != nil {
+			return nil, err
+		}
+		if !isDefaultPolicy(policy) {
+			policies = append(policies, policyObject(opts, api.Name, policy))
+		}
+	}
+
+	return append(objects, policies...), nil
+}
+
+// WriteYAML writes the objects as a multi-document YAML stream, without status and server-populated metadata.
+func WriteYAML(w io.Writer, objects []client.Object) error {
+	for _, obj := range objects {
+		content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+		if err != nil {
+			return fmt.Errorf("failed to convert %s %s: %w", obj.GetObjectKind().GroupVersionKind().Kind, obj.GetName(), err)
+		}
+		delete(content, "status")
+		if metadata, ok := content["metadata"].(map[string]interface{}); ok {
+			delete(metadata, "creationTimestamp")
+		}
+		out, err := yaml.Marshal(content)
+		if err != nil {
+			return fmt.Errorf("failed to marshal %s %s: %w", obj.GetObjectKind().GroupVersionKind().Kind, obj.GetName(), err)
+		}
+		if _, err := fmt.Fprintf(w, "---\n%s", out); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func serviceObject(opts Options) *apimv1.APIMService {
+	service := &apimv1.APIMService{
+		Spec: apimv1.APIMServiceSpec{
+			Name:          opts.Service.ServiceName,
+			ResourceGroup: opts.Service.ResourceGroup,
+			Subscription:  opts.Service.SubscriptionID,
+		},
+	}
+	service.SetGroupVersionKind(apimv1.GroupVersion.WithKind("APIMService"))
+	service.Name = opts.APIMServiceName
+	service.Namespace = opts.APIMServiceNamespace
+	return service
+}
+
+func tagObject(opts Options, tag apim.TagDetails) *apimv1.APIMTag {
+	obj := &apimv1.APIMTag{
+		Spec: apimv1.APIMTagSpec{
+			APIMService: opts.APIMServiceName,
+			TagID:       tag.Name,
+			DisplayName: tag.Properties.DisplayName,
+		},
+	}
+	obj.SetGroupVersionKind(apimv1.GroupVersion.WithKind("APIMTag"))
+	obj.Name = objectName(tag.Name)
+	obj.Namespace = opts.Namespace
+	return obj
+}
+
+func productObject(opts Options, product apim.ProductDetails) *apimv1.APIMProduct {
+	obj := &apimv1.APIMProduct{
+		Spec: apimv1.APIMProductSpec{
+			ProductID:   product.Name,
+			DisplayName: product.Properties.DisplayName,
+			Description: product.Properties.Description,
+			Published:   product.Properties.State == "published",
+			APIMService: opts.APIMServiceName,
+		},
+	}
+	obj.SetGroupVersionKind(apimv1.GroupVersion.WithKind("APIMProduct"))
+	obj.Name = objectName(product.Name)
+	obj.Namespace = opts.Namespace
+	return obj
+}
+
+// apiObject leaves openApiDefinitionUrl empty: APIM does not keep the URL an API was imported from,
+// and the defaulting webhook derives it from serviceUrl.
+func apiObject(opts Options, api apim.APIDetails, productIDs, tagIDs []string) *apimv1.APIMAPI {
+	sort.Strings(productIDs)
+	sort.Strings(tagIDs)
+	obj := &apimv1.APIMAPI{
+		Spec: apimv1.APIMAPISpec{
+			APIID:                api.Name,
+			APIMService:          opts.APIMServiceName,
+			RoutePrefix:          "/" + strings.TrimPrefix(api.Properties.Path, "/"),
+			ServiceURL:           api.Properties.ServiceURL,
+			SubscriptionRequired: api.Properties.SubscriptionRequired,
+			ProductIDs:           productIDs,
+			TagIDs:               tagIDs,
+			AdoptionPolicy:       apimv1.AdoptionPolicyAdopt,
+		},
+	}
+	obj.SetGroupVersionKind(apimv1.GroupVersion.WithKind("APIMAPI"))
+	obj.Name = objectName(api.Name)
+	obj.Namespace = opts.Namespace
+	return obj
+}
+
+func policyObject(opts Options, apiID, policy string) *apimv1.APIMInboundPolicy {
+	obj := &apimv1.APIMInboundPolicy{
+		Spec: apimv1.APIMInboundPolicySpec{
+			APIMService:   opts.APIMServiceName,
+			APIID:         apiID,
+			PolicyContent: policy,
+		},
+	}
+	obj.SetGroupVersionKind(apimv1.GroupVersion.WithKind("APIMInboundPolicy"))
+	obj.Name = objectName(apiID)
+	obj.Namespace = opts.Namespace
+	return obj
+}
+
+// defaultPolicy is the policy APIM gives every new API, with whitespace removed.
+const defaultPolicy = "<policies><inbound><base/></inbound><backend><base/></backend>" +
+	"<outbound><base/></outbound><on-error><base/></on-error></policies>"
+
+// isDefaultPolicy reports whether a policy is empty or APIM's default policy.
+func isDefaultPolicy(policy string) bool {
+	compact := strings.Join(strings.Fields(policy), "")
+	return compact == "" || compact == defaultPolicy
+}
+
+// objectName turns an APIM entity ID into a valid Kubernetes object name: lowercase alphanumerics,
+// '-' and '.', starting and ending with an alphanumeric character.
+func objectName(id string) string {
+	name := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9', r == '-', r == '.':
+			return r
+		case r >= 'A' && r <= 'Z':
+			return r + ('a' - 'A')
+		default:
+			return '-'
+		}
+	}, id)
+	if len(name) > 253 {
+		name = name[:253]
+	}
+	return strings.Trim(name, "-.")
+}
@@ -0,0 +1,84 @@
+package export
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apimv1 "github.com/hedinit/azure-apim-operator/api/v1"
+	"github.com/hedinit/azure-apim-operator/internal/apim"
+)
+
+func TestObjectName(t *testing.T) {
+	tests := map[string]string{
+		"orders-api":     "orders-api",
+		"Orders_API":     "orders-api",
+		"starter":        "starter",
+		"_internal.v2_":  "internal.v2",
+		"echo api (old)": "echo-api--old",
+	}
+	for in, want := range tests {
+		if got := objectName(in); got != want {
+			t.Errorf("objectName(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestIsDefaultPolicy(t *testing.T) {
+	tests := map[string]bool{
+		"": true,
+		"<policies>\r\n\t<inbound>\r\n\t\t<base />\r\n\t</inbound>\r\n\t<backend>\r\n\t\t<base />\r\n\t</backend>\r\n" +
+			"\t<outbound>\r\n\t\t<base />\r\n\t</outbound>\r\n\t<on-error>\r\n\t\t<base />\r\n\t</on-error>\r\n</policies>": true,
+		"<policies><inbound><base /><rate-limit calls=\"10\" renewal-period=\"60\" /></inbound>" +
+			"<backend><base /></backend><outbound><base /></outbound><on-error><base /></on-error></policies>": false,
+	}
+	for policy, want := range tests {
+		if got := isDefaultPolicy(policy); got != want {
+			t.Errorf("isDefaultPolicy(%q) = %v, want %v", policy, got, want)
+		}
+	}
+}
+
+func TestWriteYAML(t *testing.T) {
+	opts := Options{
+		Service:         apim.ExportConfig{SubscriptionID: "sub", ResourceGroup: "rg", ServiceName: "apim-prod"},
+		Namespace:       "payments",
+		APIMServiceName: "apim-prod",
+	}
+	var api apim.APIDetails
+	api.Name = "Orders_API"
+	api.Properties.Path = "orders"
+	api.Properties.ServiceURL = "https://orders.example.com"
+	api.Properties.SubscriptionRequired = true
+
+	var buf bytes.Buffer
+	objects := []client.Object{serviceObject(opts), apiObject(opts, api, []string{"starter"}, nil)}
+	if err := WriteYAML(&buf, objects); err != nil {
+		t.Fatalf("WriteYAML() error = %v", err)
+	}
+	out := buf.String()
+
+	if got := strings.Count(out, "---\n"); got != 2 {
+		t.Errorf("documents = %d, want 2\n%s", got, out)
+	}
+	for _, want := range []string{
+		"apiVersion: apim.operator.io/v1\nkind: APIMService\n",
+		"kind: APIMAPI\n",
+		"  name: orders-api\n  namespace: payments\n",
+		"  APIID: Orders_API\n",
+		"  adoptionPolicy: " + string(apimv1.AdoptionPolicyAdopt) + "\n",
+		"  routePrefix: /orders\n",
+		"  productIds:\n  - starter\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output is missing %q\n%s", want, out)
+		}
+	}
+	for _, unwanted := range []string{"status:", "creationTimestamp"} {
+		if strings.Contains(out, unwanted) {
+			t.Errorf("output contains %q\n%s", unwanted, out)
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package identity
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/cloud"
+)
+
+// Cloud is an Azure cloud: the Azure AD authority tokens are requested from and the Azure Resource Manager
+// endpoint they are for.
+type Cloud struct {
+	// Name is the name the cloud is selected by, e.g. with the --azure-cloud flag.
+	Name string
+	// Configuration holds the Azure AD authority host of the cloud.
+	Configuration cloud.Configuration
+	// ResourceManagerEndpoint is the Azure Resource Manager endpoint of the cloud. It is also the audience of
+	// management tokens.
+	ResourceManagerEndpoint string
+}
+
+var (
+	// AzurePublic is the global Azure cloud.
+	AzurePublic = Cloud{Name: "AzurePublic", Configuration: cloud.AzurePublic, ResourceManagerEndpoint: "https://management.azure.com"}
+	// AzureUSGovernment is Azure Government.
+	AzureUSGovernment = Cloud{Name: "AzureUSGovernment", Configuration: cloud.AzureGovernment, ResourceManagerEndpoint: "https://management.usgovcloudapi.net"}
+	// AzureChina is Azure operated by 21Vianet.
+	AzureChina = Cloud{Name: "AzureChina", Configuration: cloud.AzureChina, ResourceManagerEndpoint: "https://management.chinacloudapi.cn"}
+)
+
+// Clouds are the clouds CloudByName knows.
+var Clouds = []Cloud{AzurePublic, AzureUSGovernment, AzureChina}
+
+// CloudByName returns the cloud called name, ignoring case. An empty name selects AzurePublic.
+func CloudByName(name string) (Cloud, error) {
+	if name == "" {
+		return AzurePublic, nil
+	}
+	names := make([]string, 0, len(Clouds))
+	for _, c := range Clouds {
+		if strings.EqualFold(c.Name, name) {
+			return c, nil
+		}
+		names = append(names, c.Name)
+	}
+	return Cloud{}, fmt.Errorf("unknown Azure cloud %q: want one of %s", name, strings.Join(names, ", "))
+}
+
+// managementScope returns the scope of tokens for the Azure Management API of c.
+func (c Cloud) managementScope() string {
+	return c.ResourceManagerEndpoint + "/.default"
+}
+
+// clientOptions returns the options that point credentials at the Azure AD authority of c.
+func (c Cloud) clientOptions() azcore.ClientOptions {
+	return azcore.ClientOptions{Cloud: c.Configuration}
+}
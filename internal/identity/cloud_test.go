@@ -0,0 +1,36 @@
+package identity
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestCloudByName(t *testing.T) {
+	tests := map[string]Cloud{
+		"":                  AzurePublic,
+		"AzurePublic":       AzurePublic,
+		"azureusgovernment": AzureUSGovernment,
+		"AzureChina":        AzureChina,
+	}
+	for name, want := range tests {
+		got, err := CloudByName(name)
+		if err != nil || got.Name != want.Name {
+			t.Errorf("CloudByName(%q) = %q, %v, want %q", name, got.Name, err, want.Name)
+		}
+	}
+	if _, err := CloudByName("AzureGermany"); err == nil {
+		t.Error("CloudByName(AzureGermany) error = nil, want an unknown cloud")
+	}
+}
+
+func TestTokenCacheCloud(t *testing.T) {
+	cred := &fakeCredential{scope: "https://management.usgovcloudapi.net/.default", now: time.Now}
+	cache := newTokenCache(cred, AzureUSGovernment)
+	if _, err := cache.ManagementToken(context.Background()); err != nil {
+		t.Errorf("ManagementToken() error = %v, want a token for the Azure Government scope", err)
+	}
+	if got := AzureUSGovernment.clientOptions().Cloud.ActiveDirectoryAuthorityHost; got != "https://login.microsoftonline.us/" {
+		t.Errorf("authority host = %q, want the Azure Government authority", got)
+	}
+}
@@ -16,20 +16,34 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
+// staticToken is returned instead of an Azure AD token when set. See SetStaticToken.
+var staticToken string
+
+// SetStaticToken makes the token functions return token instead of asking Azure AD, for running against a
+// fake management endpoint that accepts any token. It must be called before the functions are used.
+// An empty token restores Azure AD.
+func SetStaticToken(token string) {
+	staticToken = token
+}
+
 // GetManagementToken obtains an Azure AD access token for the Azure Management API
-// using Azure Workload Identity. This method requires the client ID and tenant ID
+// of cloud using Azure Workload Identity. This method requires the client ID and tenant ID
 // to be provided, and reads the service account token from the standard Kubernetes
 // service account token path.
 //
 // This is the primary authentication method used in Kubernetes environments with
 // workload identity configured.
-func GetManagementToken(ctx context.Context, clientId string, tenantId string) (string, error) {
+func GetManagementToken(ctx context.Context, clientId string, tenantId string, cloud Cloud) (string, error) {
+	if staticToken != "" {
+		return staticToken, nil
+	}
 	logger := ctrl.Log.WithName("identity")
 
 	// Create a workload identity credential using the provided client ID and tenant ID.
 	// The token file path is the standard location where Kubernetes injects the
 	// service account token for workload identity authentication.
 	cred, err := azidentity.NewWorkloadIdentityCredential(&azidentity.WorkloadIdentityCredentialOptions{
+		ClientOptions: cloud.clientOptions(),
 		ClientID:      clientId,
 		TenantID:      tenantId,
 		TokenFilePath: "/var/run/secrets/azure/tokens/azure-identity-token",
@@ -39,11 +53,10 @@ func GetManagementToken(ctx context.Context, clientId string, tenantId string) (
 		return "", err
 	}
 
-	// Request a token with the Azure Management API scope.
+	// Request a token with the Azure Management API scope of the cloud.
 	// This scope provides access to Azure Resource Manager APIs.
-	const scope = "https://management.azure.com/.default"
 	token, err := cred.GetToken(ctx, policy.TokenRequestOptions{
-		Scopes: []string{scope},
+		Scopes: []string{cloud.managementScope()},
 	})
 	if err != nil {
 		logger.Error(err, "❌ Failed to get Azure access token")
@@ -54,14 +67,17 @@ func GetManagementToken(ctx context.Context, clientId string, tenantId string) (
 	return token.Token, nil
 }
 
-// GetManagementToken2 obtains an Azure AD access token by dynamically discovering
+// GetManagementToken2 obtains an Azure AD access token for cloud by dynamically discovering
 // the workload identity client ID from the Kubernetes ServiceAccount annotation.
 // This method reads the current pod's service account and extracts the client ID
 // from the "azure.workload.identity/client-id" annotation.
 //
 // This is an alternative to GetManagementToken that doesn't require the client ID
 // to be passed as a parameter, but requires Kubernetes API access to read the ServiceAccount.
-func GetManagementToken2(ctx context.Context, kubeClient client.Client) (string, error) {
+func GetManagementToken2(ctx context.Context, kubeClient client.Client, cloud Cloud) (string, error) {
+	if staticToken != "" {
+		return staticToken, nil
+	}
 	logger := ctrl.Log.WithName("identity")
 
 	// Step 1: Get current pod and namespace from environment.
@@ -100,7 +116,8 @@ func GetManagementToken2(ctx context.Context, kubeClient client.Client) (string,
 
 	// Step 4: Create credential using the client ID discovered from the ServiceAccount.
 	cred, err := azidentity.NewWorkloadIdentityCredential(&azidentity.WorkloadIdentityCredentialOptions{
-		ClientID: clientID,
+		ClientOptions: cloud.clientOptions(),
+		ClientID:      clientID,
 	})
 	if err != nil {
 		logger.Error(err, "failed to create workload identity credential")
@@ -109,7 +126,7 @@ func GetManagementToken2(ctx context.Context, kubeClient client.Client) (string,
 
 	// Step 5: Get token with Azure Management API scope.
 	token, err := cred.GetToken(ctx, policy.TokenRequestOptions{
-		Scopes: []string{"https://management.azure.com/.default"},
+		Scopes: []string{cloud.managementScope()},
 	})
 	if err != nil {
 		logger.Error(err, "failed to get token")
@@ -120,7 +137,7 @@ func GetManagementToken2(ctx context.Context, kubeClient client.Client) (string,
 	return token.Token, nil
 }
 
-// GetManagementToken3 obtains an Azure AD access token using DefaultAzureCredential.
+// GetManagementToken3 obtains an Azure AD access token for cloud using DefaultAzureCredential.
 // DefaultAzureCredential tries multiple authentication methods in order:
 // 1. Environment variables (AZURE_CLIENT_ID, AZURE_CLIENT_SECRET, etc.)
 // 2. Managed Identity (when running on Azure)
@@ -129,21 +146,22 @@ func GetManagementToken2(ctx context.Context, kubeClient client.Client) (string,
 //
 // This method is useful for local development and Azure-hosted environments
 // where managed identity is available.
-func GetManagementToken3(ctx context.Context) (string, error) {
+func GetManagementToken3(ctx context.Context, cloud Cloud) (string, error) {
+	if staticToken != "" {
+		return staticToken, nil
+	}
 	logger := ctrl.Log.WithName("identity")
 
 	// Create a default Azure credential that will try multiple authentication methods.
-	cred, err := azidentity.NewDefaultAzureCredential(nil)
+	cred, err := azidentity.NewDefaultAzureCredential(&azidentity.DefaultAzureCredentialOptions{ClientOptions: cloud.clientOptions()})
 	if err != nil {
 		logger.Error(err, "❌ Failed to create default Azure credential")
 		return "", err
 	}
 
-	// Request a token with the Azure Management API scope.
-	const scope = "https://management.azure.com/.default"
-
+	// Request a token with the Azure Management API scope of the cloud.
 	token, err := cred.GetToken(ctx, policy.TokenRequestOptions{
-		Scopes: []string{scope},
+		Scopes: []string{cloud.managementScope()},
 	})
 	if err != nil {
 		logger.Error(err, "❌ Failed to get Azure access token")
@@ -153,3 +171,28 @@ func GetManagementToken3(ctx context.Context) (string, error) {
 	logger.Info("✅ Successfully acquired Azure token", "expires", token.ExpiresOn.Format(time.RFC3339))
 	return token.Token, nil
 }
+
+// NewTokenSource returns a function that obtains Azure AD access tokens for scope from the Azure AD
+// of cloud using Azure Workload Identity, like GetManagementToken. The credential is created once and caches its
+// tokens until they expire, so the function can be called for every request.
+func NewTokenSource(clientId string, tenantId string, cloud Cloud, scope string) (func(ctx context.Context) (string, error), error) {
+	if staticToken != "" {
+		return func(context.Context) (string, error) { return staticToken, nil }, nil
+	}
+	cred, err := azidentity.NewWorkloadIdentityCredential(&azidentity.WorkloadIdentityCredentialOptions{
+		ClientOptions: cloud.clientOptions(),
+		ClientID:      clientId,
+		TenantID:      tenantId,
+		TokenFilePath: "/var/run/secrets/azure/tokens/azure-identity-token",
+	})
+	if err != nil {
+		return nil, fmt.Errorf("create workload identity credential: %w", err)
+	}
+	return func(ctx context.Context) (string, error) {
+		token, err := cred.GetToken(ctx, policy.TokenRequestOptions{Scopes: []string{scope}})
+		if err != nil {
+			return "", err
+		}
+		return token.Token, nil
+	}, nil
+}
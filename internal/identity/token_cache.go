@@ -0,0 +1,159 @@
+package identity
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
+	"golang.org/x/sync/singleflight"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+const (
+	// tokenRefreshBefore is how long before its expiry a cached token is replaced, unless Azure AD suggests
+	// another refresh time.
+	tokenRefreshBefore = 5 * time.Minute
+
+	// tokenRetryInterval is how long the background refresh waits after Azure AD failed to issue a token.
+	tokenRetryInterval = 30 * time.Second
+)
+
+// TokenProvider provides access tokens for the Azure Management API.
+type TokenProvider interface {
+	ManagementToken(ctx context.Context) (string, error)
+	// Invalidate drops the current token after Azure rejected it, so the next ManagementToken call
+	// obtains a new one.
+	Invalidate()
+}
+
+// TokenCache is a TokenProvider that shares one Azure AD access token between all its callers. The token is
+// replaced shortly before it expires, in the background when the cache is added to a manager and otherwise
+// by the first caller that needs it. Callers that need a new token at the same time share one request to
+// Azure AD. A cache is safe for concurrent use.
+type TokenCache struct {
+	cred    azcore.TokenCredential
+	scope   string
+	now     func() time.Time
+	refresh singleflight.Group
+
+	mu    sync.Mutex
+	token azcore.AccessToken
+}
+
+// NewTokenCache returns a TokenCache that obtains tokens for cloud using Azure Workload Identity, like
+// GetManagementToken.
+func NewTokenCache(clientId string, tenantId string, cloud Cloud) (*TokenCache, error) {
+	cred, err := azidentity.NewWorkloadIdentityCredential(&azidentity.WorkloadIdentityCredentialOptions{
+		ClientOptions: cloud.clientOptions(),
+		ClientID:      clientId,
+		TenantID:      tenantId,
+		TokenFilePath: "/var/run/secrets/azure/tokens/azure-identity-token",
+	})
+	if err != nil {
+		return nil, fmt.Errorf("create workload identity credential: %w", err)
+	}
+	return newTokenCache(cred, cloud), nil
+}
+
+func newTokenCache(cred azcore.TokenCredential, cloud Cloud) *TokenCache {
+	return &TokenCache{cred: cred, scope: cloud.managementScope(), now: time.Now}
+}
+
+// ManagementToken returns the cached token, and obtains a new one first if the cached token is due for refresh.
+// When Azure AD fails to issue a new token, the cached one is returned for as long as it is valid.
+func (c *TokenCache) ManagementToken(ctx context.Context) (string, error) {
+	if staticToken != "" {
+		return staticToken, nil
+	}
+	c.mu.Lock()
+	token, due := c.token.Token, c.token.Token == "" || !c.now().Before(c.refreshAt())
+	c.mu.Unlock()
+	if !due {
+		return token, nil
+	}
+
+	// Azure AD is asked without holding c.mu, so callers that still have a valid token are not blocked
+	// behind the request. The request is made with the context of the first caller.
+	fetched, err, _ := c.refresh.Do("", func() (any, error) {
+		return c.fetch(ctx)
+	})
+	if err != nil {
+		return "", err
+	}
+	return fetched.(string), nil
+}
+
+// fetch obtains a new token from Azure AD and caches it.
+func (c *TokenCache) fetch(ctx context.Context) (string, error) {
+	logger := ctrl.Log.WithName("identity")
+	token, err := c.cred.GetToken(ctx, policy.TokenRequestOptions{Scopes: []string{c.scope}})
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err != nil {
+		if c.token.Token != "" && c.now().Before(c.token.ExpiresOn) {
+			logger.Error(err, "⚠️ Failed to refresh Azure access token, using the cached one", "expires", c.token.ExpiresOn.Format(time.RFC3339))
+			return c.token.Token, nil
+		}
+		logger.Error(err, "❌ Failed to get Azure access token")
+		return "", err
+	}
+	c.token = token
+	logger.Info("✅ Successfully acquired Azure token", "expires", token.ExpiresOn.Format(time.RFC3339))
+	return token.Token, nil
+}
+
+// Invalidate drops the cached token, for example after Azure answered 401 because it expired early, the
+// clocks are skewed or it was revoked. The next ManagementToken call obtains a new one.
+func (c *TokenCache) Invalidate() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.token = azcore.AccessToken{}
+}
+
+// refreshAt returns when the cached token is replaced. c.mu must be held.
+func (c *TokenCache) refreshAt() time.Time {
+	if !c.token.RefreshOn.IsZero() && c.token.RefreshOn.Before(c.token.ExpiresOn) {
+		return c.token.RefreshOn
+	}
+	return c.token.ExpiresOn.Add(-tokenRefreshBefore)
+}
+
+// untilRefresh returns how long the background refresh waits for the next token.
+func (c *TokenCache) untilRefresh() time.Duration {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if wait := c.refreshAt().Sub(c.now()); wait > 0 {
+		return wait
+	}
+	// The token could not be refreshed in time; try again soon.
+	return tokenRetryInterval
+}
+
+// Start refreshes the token in the background until ctx is done, so reconciles don't wait for Azure AD.
+// It implements manager.Runnable.
+func (c *TokenCache) Start(ctx context.Context) error {
+	if staticToken != "" {
+		return nil
+	}
+	for {
+		wait := tokenRetryInterval
+		if _, err := c.ManagementToken(ctx); err == nil {
+			wait = c.untilRefresh()
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(wait):
+		}
+	}
+}
+
+// NeedLeaderElection reports that every replica keeps its token fresh, so a new leader starts with one.
+func (c *TokenCache) NeedLeaderElection() bool {
+	return false
+}
@@ -0,0 +1,151 @@
+package identity
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+)
+
+// fakeCredential issues numbered tokens for scope valid for an hour from now, or err when set.
+type fakeCredential struct {
+	scope string
+	now   func() time.Time
+	err   error
+	calls int
+}
+
+func (f *fakeCredential) GetToken(_ context.Context, options policy.TokenRequestOptions) (azcore.AccessToken, error) {
+	f.calls++
+	if len(options.Scopes) != 1 || options.Scopes[0] != f.scope {
+		return azcore.AccessToken{}, fmt.Errorf("unexpected scopes %v", options.Scopes)
+	}
+	if f.err != nil {
+		return azcore.AccessToken{}, f.err
+	}
+	return azcore.AccessToken{Token: fmt.Sprintf("token-%d", f.calls), ExpiresOn: f.now().Add(time.Hour)}, nil
+}
+
+func TestTokenCache(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	clock := func() time.Time { return now }
+	cred := &fakeCredential{scope: "https://management.azure.com/.default", now: clock}
+	cache := newTokenCache(cred, AzurePublic)
+	cache.now = clock
+	ctx := context.Background()
+
+	token := func() string {
+		t.Helper()
+		got, err := cache.ManagementToken(ctx)
+		if err != nil {
+			t.Fatalf("ManagementToken() error = %v", err)
+		}
+		return got
+	}
+
+	if got := token(); got != "token-1" {
+		t.Fatalf("ManagementToken() = %q, want token-1", got)
+	}
+	now = now.Add(50 * time.Minute)
+	if got := token(); got != "token-1" || cred.calls != 1 {
+		t.Errorf("ManagementToken() = %q after %d calls to Azure AD, want the cached token-1", got, cred.calls)
+	}
+	if got := cache.untilRefresh(); got != 5*time.Minute {
+		t.Errorf("untilRefresh() = %v, want 5m before expiry", got)
+	}
+
+	now = now.Add(6 * time.Minute)
+	if got := token(); got != "token-2" {
+		t.Errorf("ManagementToken() = %q, want token-2 within 5m of expiry", got)
+	}
+
+	cred.err = errors.New("AADSTS700024")
+	now = now.Add(56 * time.Minute)
+	if got := token(); got != "token-2" {
+		t.Errorf("ManagementToken() = %q, want the valid token-2 while Azure AD fails", got)
+	}
+	if got := cache.untilRefresh(); got != tokenRetryInterval {
+		t.Errorf("untilRefresh() = %v, want %v after a failed refresh", got, tokenRetryInterval)
+	}
+	now = now.Add(5 * time.Minute)
+	if _, err := cache.ManagementToken(ctx); err == nil {
+		t.Error("ManagementToken() error = nil, want the Azure AD error once the token expired")
+	}
+}
+
+func TestTokenCacheStaticToken(t *testing.T) {
+	SetStaticToken("test-token")
+	t.Cleanup(func() { SetStaticToken("") })
+	cred := &fakeCredential{now: time.Now}
+	cache := newTokenCache(cred, AzurePublic)
+
+	if got, err := cache.ManagementToken(context.Background()); err != nil || got != "test-token" {
+		t.Errorf("ManagementToken() = %q, %v, want the static token", got, err)
+	}
+	if cred.calls != 0 {
+		t.Errorf("Azure AD called %d times, want 0 with a static token", cred.calls)
+	}
+}
+
+func TestTokenCacheInvalidate(t *testing.T) {
+	cred := &fakeCredential{scope: "https://management.azure.com/.default", now: time.Now}
+	cache := newTokenCache(cred, AzurePublic)
+	ctx := context.Background()
+
+	if got, err := cache.ManagementToken(ctx); err != nil || got != "token-1" {
+		t.Fatalf("ManagementToken() = %q, %v, want token-1", got, err)
+	}
+	cache.Invalidate()
+	if got, err := cache.ManagementToken(ctx); err != nil || got != "token-2" {
+		t.Errorf("ManagementToken() after Invalidate() = %q, %v, want a new token-2", got, err)
+	}
+}
+
+// blockingCredential issues one token per call once release is closed.
+type blockingCredential struct {
+	release chan struct{}
+	calls   atomic.Int32
+}
+
+func (b *blockingCredential) GetToken(context.Context, policy.TokenRequestOptions) (azcore.AccessToken, error) {
+	b.calls.Add(1)
+	<-b.release
+	return azcore.AccessToken{Token: "shared", ExpiresOn: time.Now().Add(time.Hour)}, nil
+}
+
+func TestTokenCacheSharesRefresh(t *testing.T) {
+	cred := &blockingCredential{release: make(chan struct{})}
+	cache := newTokenCache(cred, AzurePublic)
+
+	var wg sync.WaitGroup
+	tokens := make([]string, 5)
+	for i := range tokens {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			tokens[i], _ = cache.ManagementToken(context.Background())
+		}()
+	}
+	// The cache is not locked while Azure AD is asked.
+	for cred.calls.Load() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	cache.Invalidate()
+	close(cred.release)
+	wg.Wait()
+
+	if got := cred.calls.Load(); got != 1 {
+		t.Errorf("Azure AD called %d times, want 1 for concurrent callers", got)
+	}
+	for _, token := range tokens {
+		if token != "shared" {
+			t.Errorf("ManagementToken() = %q, want the shared token", token)
+		}
+	}
+}
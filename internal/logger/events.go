@@ -0,0 +1,69 @@
+package logger
+
+import (
+	"context"
+
+	otellog "go.opentelemetry.io/otel/log"
+	"go.opentelemetry.io/otel/log/global"
+
+	"github.com/hedinit/azure-apim-operator/internal/redact"
+)
+
+// ImportEventName is the event name of the log records emitted by EmitImportEvent.
+const ImportEventName = "apim.api.import"
+
+// ImportEvent describes the outcome of importing an API into APIM.
+type ImportEvent struct {
+	// Namespace and Name identify the APIMAPIDeployment.
+	Namespace string
+	Name      string
+	// APIMService and APIID identify the API in APIM.
+	APIMService string
+	APIID       string
+	// Result is success, failure or dry_run, and Reason the step that failed.
+	Result string
+	Reason string
+	// Env, Team and Service are the telemetry tags of the APIMService. Empty tags are left out.
+	Env     string
+	Team    string
+	Service string
+	// AzureRequestID is the x-ms-request-id of the last APIM call, for Azure support requests.
+	AzureRequestID string
+	// Err is the error of a failed import.
+	Err error
+}
+
+// EmitImportEvent emits e as an OTLP log record through the global logger provider, correlated with
+// the span in ctx. Without a provider configured by InitTracer the event is dropped.
+func EmitImportEvent(ctx context.Context, e ImportEvent) {
+	l := global.GetLoggerProvider().Logger("github.com/hedinit/azure-apim-operator")
+
+	var record otellog.Record
+	record.SetEventName(ImportEventName)
+	record.SetBody(otellog.StringValue("API import " + e.Result))
+	record.SetSeverity(otellog.SeverityInfo)
+	record.SetSeverityText("INFO")
+	record.AddAttributes(
+		otellog.String("k8s.namespace.name", e.Namespace),
+		otellog.String("apim.deployment", e.Name),
+		otellog.String("apim.service", e.APIMService),
+		otellog.String("apim.api_id", e.APIID),
+		otellog.String("apim.import.result", e.Result),
+		otellog.String("apim.import.reason", e.Reason),
+	)
+	for key, value := range map[string]string{"apim.tag.env": e.Env, "apim.tag.team": e.Team, "apim.tag.service": e.Service} {
+		if value != "" {
+			record.AddAttributes(otellog.String(key, value))
+		}
+	}
+	if e.AzureRequestID != "" {
+		record.AddAttributes(otellog.String("azure.request_id", e.AzureRequestID))
+	}
+	if e.Err != nil {
+		record.SetSeverity(otellog.SeverityError)
+		record.SetSeverityText("ERROR")
+		record.AddAttributes(otellog.String("error.message", redact.String(e.Err.Error())))
+	}
+
+	l.Emit(ctx, record)
+}
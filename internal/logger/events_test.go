@@ -0,0 +1,77 @@
+package logger
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	otellog "go.opentelemetry.io/otel/log"
+	"go.opentelemetry.io/otel/log/global"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+)
+
+// recordingExporter keeps the exported log records in memory.
+type recordingExporter struct {
+	mu      sync.Mutex
+	records []sdklog.Record
+}
+
+func (e *recordingExporter) Export(_ context.Context, records []sdklog.Record) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	for _, r := range records {
+		e.records = append(e.records, r.Clone())
+	}
+	return nil
+}
+
+func (e *recordingExporter) Shutdown(context.Context) error   { return nil }
+func (e *recordingExporter) ForceFlush(context.Context) error { return nil }
+
+func TestEmitImportEvent(t *testing.T) {
+	exporter := &recordingExporter{}
+	global.SetLoggerProvider(sdklog.NewLoggerProvider(sdklog.WithProcessor(sdklog.NewSimpleProcessor(exporter))))
+	t.Cleanup(func() { global.SetLoggerProvider(nil) })
+
+	EmitImportEvent(context.Background(), ImportEvent{
+		Namespace:      "payments",
+		Name:           "payment-api",
+		APIMService:    "my-apim",
+		APIID:          "payment-api",
+		Result:         "failure",
+		Reason:         "import",
+		AzureRequestID: "4f1c2d3e-request",
+		Err:            errors.New(`APIM API failed: 400 {"password": "hunter2"}`),
+	})
+
+	if len(exporter.records) != 1 {
+		t.Fatalf("got %d records, want 1", len(exporter.records))
+	}
+	record := exporter.records[0]
+	if record.EventName() != ImportEventName {
+		t.Errorf("EventName() = %q, want %q", record.EventName(), ImportEventName)
+	}
+	if record.Severity() != otellog.SeverityError {
+		t.Errorf("Severity() = %v, want Error for a failed import", record.Severity())
+	}
+
+	attrs := map[string]string{}
+	record.WalkAttributes(func(kv otellog.KeyValue) bool {
+		attrs[kv.Key] = kv.Value.AsString()
+		return true
+	})
+	want := map[string]string{
+		"apim.api_id":        "payment-api",
+		"apim.service":       "my-apim",
+		"apim.import.result": "failure",
+		"apim.import.reason": "import",
+		"azure.request_id":   "4f1c2d3e-request",
+		"error.message":      `APIM API failed: 400 {"password": "[REDACTED]"}`,
+	}
+	for key, value := range want {
+		if attrs[key] != value {
+			t.Errorf("attribute %s = %q, want %q", key, attrs[key], value)
+		}
+	}
+}
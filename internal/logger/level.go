@@ -0,0 +1,87 @@
+package logger
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+// ParseLevel parses a log level the way the --zap-log-level flag does: "debug", "info", "warn",
+// "error", or a positive integer n for the more verbose logr level V(n).
+func ParseLevel(text string) (zapcore.Level, error) {
+	text = strings.ToLower(strings.TrimSpace(text))
+	switch text {
+	case "debug":
+		return zapcore.DebugLevel, nil
+	case "info":
+		return zapcore.InfoLevel, nil
+	case "warn":
+		return zapcore.WarnLevel, nil
+	case "error":
+		return zapcore.ErrorLevel, nil
+	}
+	n, err := strconv.Atoi(text)
+	if err != nil || n <= 0 || n > 127 {
+		return 0, fmt.Errorf("invalid log level %q: use debug, info, warn, error or a positive integer", text)
+	}
+	return zapcore.Level(-n), nil
+}
+
+// WatchLevelFile keeps level in sync with the log level stored in path until ctx is done.
+// The file is re-read on SIGHUP and every interval, so a level kept in a mounted ConfigMap
+// takes effect without a restart once the kubelet has synced the volume.
+// A missing or empty file leaves the current level unchanged.
+func WatchLevelFile(ctx context.Context, level zap.AtomicLevel, path string, interval time.Duration) {
+	log := ctrl.Log.WithName("logger")
+
+	reload := func() {
+		content, err := os.ReadFile(path)
+		if errors.Is(err, os.ErrNotExist) || (err == nil && strings.TrimSpace(string(content)) == "") {
+			return
+		}
+		if err != nil {
+			log.Error(err, "Failed to read log level file", "path", path)
+			return
+		}
+		newLevel, err := ParseLevel(string(content))
+		if err != nil {
+			log.Error(err, "Ignoring log level file", "path", path)
+			return
+		}
+		if newLevel != level.Level() {
+			log.Info("Changing log level", "from", level.Level().String(), "to", newLevel.String())
+			level.SetLevel(newLevel)
+		}
+	}
+
+	hangup := make(chan os.Signal, 1)
+	signal.Notify(hangup, syscall.SIGHUP)
+
+	go func() {
+		defer signal.Stop(hangup)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		reload()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-hangup:
+				reload()
+			case <-ticker.C:
+				reload()
+			}
+		}
+	}()
+}
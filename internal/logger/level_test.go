@@ -0,0 +1,64 @@
+package logger
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestParseLevel(t *testing.T) {
+	tests := []struct {
+		text    string
+		want    zapcore.Level
+		wantErr bool
+	}{
+		{text: "debug", want: zapcore.DebugLevel},
+		{text: " INFO\n", want: zapcore.InfoLevel},
+		{text: "warn", want: zapcore.WarnLevel},
+		{text: "error", want: zapcore.ErrorLevel},
+		{text: "3", want: zapcore.Level(-3)},
+		{text: "0", wantErr: true},
+		{text: "verbose", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			got, err := ParseLevel(tt.text)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseLevel(%q) error = %v, wantErr %t", tt.text, err, tt.wantErr)
+			}
+			if !tt.wantErr && got != tt.want {
+				t.Errorf("ParseLevel(%q) = %v, want %v", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWatchLevelFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "level")
+	level := zap.NewAtomicLevelAt(zapcore.InfoLevel)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	WatchLevelFile(ctx, level, path, 10*time.Millisecond)
+	time.Sleep(30 * time.Millisecond)
+	if level.Level() != zapcore.InfoLevel {
+		t.Fatalf("level = %v with a missing file, want info", level.Level())
+	}
+
+	if err := os.WriteFile(path, []byte("debug\n"), 0o600); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+	deadline := time.Now().Add(time.Second)
+	for level.Level() != zapcore.DebugLevel {
+		if time.Now().After(deadline) {
+			t.Fatalf("level = %v, want debug after the file changed", level.Level())
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
@@ -1,17 +1,22 @@
 // Package logger provides OpenTelemetry tracing initialization for distributed tracing.
-// This package configures the operator to send traces to an OpenTelemetry collector,
-// which can then forward them to observability platforms like Datadog.
+// This package configures the operator to send traces, and optionally reconcile events as OTLP logs,
+// to an OpenTelemetry collector, which can then forward them to observability platforms like Datadog.
 package logger
 
 import (
 	"context"
 	"crypto/tls"
+	"errors"
 	"log"
 	"os"
 	"strconv"
 
 	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlplog/otlploggrpc"
 	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/log/global"
+	"go.opentelemetry.io/otel/propagation"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
 	"go.opentelemetry.io/otel/sdk/resource"
 	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
@@ -24,12 +29,16 @@ import (
 // It sets up a gRPC connection to an OpenTelemetry collector and configures
 // the global tracer provider with resource attributes (service name, environment, version).
 //
+// When OTEL_LOGS_EXPORTER is "otlp", it also sets a global logger provider on the same connection,
+// so the reconcile events emitted by EmitImportEvent are exported as OTLP log records.
+//
 // The function returns a shutdown function that should be called when the application
-// exits to gracefully close the tracer provider. If telemetry is disabled, returns a no-op function.
+// exits to gracefully close the providers. If telemetry is disabled, returns a no-op function.
 //
 // Environment variables:
 //   - OTEL_EXPORTER_OTLP_ENDPOINT: The OTLP collector endpoint (optional, disables if not set)
 //   - OTEL_EXPORTER_OTLP_INSECURE: Set to "true" to use insecure credentials (default: "false")
+//   - OTEL_LOGS_EXPORTER: Set to "otlp" to export reconcile events as OTLP logs (default: disabled)
 //   - DD_ENV: Datadog environment name (used as deployment environment)
 //   - DD_VERSION: Service version (used for version tracking)
 func InitTracer(ctx context.Context) func(context.Context) error {
@@ -102,10 +111,31 @@ func InitTracer(ctx context.Context) func(context.Context) error {
 
 	// Set the global tracer provider so all tracing operations use this configuration.
 	otel.SetTracerProvider(tp)
+	// Propagate the trace context to Azure and OpenAPI endpoints as W3C traceparent/tracestate headers.
+	otel.SetTextMapPropagator(propagation.TraceContext{})
 
 	log.Println("✅ Tracer configured via OTLP gRPC")
 	log.Printf("ℹ️  Traces will be sent to %s with service: 'azure-apim-operator', env: '%s', version: '%s'\n",
 		endpoint, os.Getenv("DD_ENV"), os.Getenv("DD_VERSION"))
 
-	return tp.Shutdown
+	if os.Getenv("OTEL_LOGS_EXPORTER") != "otlp" {
+		return tp.Shutdown
+	}
+
+	// Create an OTLP log exporter on the same connection for the structured reconcile events.
+	logExporter, err := otlploggrpc.New(ctx, otlploggrpc.WithGRPCConn(conn))
+	if err != nil {
+		log.Fatalf("❌ Failed to create OTLP log exporter: %v", err)
+	}
+	lp := sdklog.NewLoggerProvider(
+		sdklog.WithProcessor(sdklog.NewBatchProcessor(logExporter)),
+		sdklog.WithResource(res),
+	)
+	global.SetLoggerProvider(lp)
+
+	log.Println("✅ Reconcile events exported as OTLP logs")
+
+	return func(ctx context.Context) error {
+		return errors.Join(tp.Shutdown(ctx), lp.Shutdown(ctx))
+	}
 }
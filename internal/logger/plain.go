@@ -0,0 +1,64 @@
+package logger
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/go-logr/logr"
+)
+
+// StripDecoration removes the emoji prefix from a log message, e.g. "✅ Successfully acquired Azure token"
+// becomes "Successfully acquired Azure token". Messages are plain ASCII after their prefix, so every leading
+// non-ASCII rune and the following whitespace are dropped.
+func StripDecoration(msg string) string {
+	return strings.TrimLeftFunc(msg, func(r rune) bool {
+		return r > unicode.MaxASCII || unicode.IsSpace(r)
+	})
+}
+
+// Plain returns a logger that writes through l with the emoji prefixes stripped from every message,
+// for log pipelines that mangle them or alert on exact messages.
+func Plain(l logr.Logger) logr.Logger {
+	return logr.New(plainSink{LogSink: l.GetSink()})
+}
+
+// plainSink strips message decorations before delegating to the wrapped sink.
+type plainSink struct {
+	logr.LogSink
+}
+
+var _ logr.CallDepthLogSink = plainSink{}
+
+// Init implements logr.LogSink. The wrapper adds a stack frame, so the caller depth is raised by one.
+func (s plainSink) Init(info logr.RuntimeInfo) {
+	info.CallDepth++
+	s.LogSink.Init(info)
+}
+
+// Info implements logr.LogSink.
+func (s plainSink) Info(level int, msg string, keysAndValues ...any) {
+	s.LogSink.Info(level, StripDecoration(msg), keysAndValues...)
+}
+
+// Error implements logr.LogSink.
+func (s plainSink) Error(err error, msg string, keysAndValues ...any) {
+	s.LogSink.Error(err, StripDecoration(msg), keysAndValues...)
+}
+
+// WithValues implements logr.LogSink.
+func (s plainSink) WithValues(keysAndValues ...any) logr.LogSink {
+	return plainSink{LogSink: s.LogSink.WithValues(keysAndValues...)}
+}
+
+// WithName implements logr.LogSink.
+func (s plainSink) WithName(name string) logr.LogSink {
+	return plainSink{LogSink: s.LogSink.WithName(name)}
+}
+
+// WithCallDepth implements logr.CallDepthLogSink.
+func (s plainSink) WithCallDepth(depth int) logr.LogSink {
+	if sink, ok := s.LogSink.(logr.CallDepthLogSink); ok {
+		return plainSink{LogSink: sink.WithCallDepth(depth)}
+	}
+	return s
+}
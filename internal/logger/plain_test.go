@@ -0,0 +1,47 @@
+package logger
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/go-logr/logr/funcr"
+)
+
+func TestStripDecoration(t *testing.T) {
+	tests := map[string]string{
+		"✅ Successfully acquired Azure token": "Successfully acquired Azure token",
+		"⚠️ Failed to patch APIMAPI status":   "Failed to patch APIMAPI status",
+		"🛑  Refusing to overwrite":            "Refusing to overwrite",
+		"WARNING: deprecated API in use":      "WARNING: deprecated API in use",
+		"":                                    "",
+	}
+	for msg, want := range tests {
+		if got := StripDecoration(msg); got != want {
+			t.Errorf("StripDecoration(%q) = %q, want %q", msg, got, want)
+		}
+	}
+}
+
+func TestPlain(t *testing.T) {
+	var lines []string
+	base := funcr.New(func(prefix, args string) {
+		lines = append(lines, prefix+" "+args)
+	}, funcr.Options{})
+
+	log := Plain(base).WithName("apim").WithValues("apiID", "orders")
+	log.Info("✅ API imported")
+	log.Error(errors.New("boom"), "⚠️ Import failed")
+
+	want := []string{
+		`apim "level"=0 "msg"="API imported" "apiID"="orders"`,
+		`apim "msg"="Import failed" "error"="boom" "apiID"="orders"`,
+	}
+	if len(lines) != len(want) {
+		t.Fatalf("got %d lines, want %d: %q", len(lines), len(want), lines)
+	}
+	for i := range want {
+		if lines[i] != want[i] {
+			t.Errorf("line %d = %s, want %s", i, lines[i], want[i])
+		}
+	}
+}
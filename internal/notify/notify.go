@@ -0,0 +1,126 @@
+// Package notify posts the outcome of API imports to webhooks, such as Slack or Microsoft Teams channels,
+// so application teams learn about a failed rollout without watching their resources.
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// Message formats a notification can be posted in.
+const (
+	// FormatWebhook posts the ImportOutcome as a JSON document.
+	FormatWebhook = "webhook"
+	// FormatSlack posts a Slack incoming webhook message.
+	FormatSlack = "slack"
+	// FormatTeams posts an Adaptive Card, as accepted by Microsoft Teams workflow webhooks.
+	FormatTeams = "teams"
+)
+
+// Import results a notification can be sent for.
+const (
+	ResultSuccess = "success"
+	ResultFailure = "failure"
+)
+
+// httpClient posts notifications. The timeout keeps a slow webhook from holding up the reconcile.
+var httpClient = &http.Client{Timeout: 10 * time.Second}
+
+// ImportOutcome describes the outcome of an API import. It is the body of FormatWebhook notifications.
+type ImportOutcome struct {
+	// Result is success or failure.
+	Result string `json:"result"`
+	// APIMService is the name of the targeted APIMService.
+	APIMService string `json:"apimService"`
+	// Namespace and Name identify the APIMAPI.
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	// APIID is the API ID in APIM.
+	APIID string `json:"apiId"`
+	// GatewayURL is the URL of the API on the gateway. It can be empty for an API that was never imported.
+	GatewayURL string `json:"gatewayUrl,omitempty"`
+	// Step is the failed step, as in the reason label of apim_api_import_total, and Reason the
+	// Ready condition reason of a failure.
+	Step   string `json:"step,omitempty"`
+	Reason string `json:"reason,omitempty"`
+	// Error is the first line of the redacted error of a failure.
+	Error string `json:"error,omitempty"`
+	// Time is when the import finished, in RFC 3339.
+	Time string `json:"time"`
+}
+
+// Send posts outcome to webhookURL in format.
+func Send(ctx context.Context, format, webhookURL string, outcome ImportOutcome) error {
+	body, err := messageBody(format, outcome)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhookURL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("build notification request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		// The URL is a credential, so only the cause is returned, not the *url.Error that quotes it.
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return fmt.Errorf("post notification: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	_, _ = io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("post notification: webhook answered %s", resp.Status)
+	}
+	return nil
+}
+
+// messageBody returns the JSON body of a notification in format.
+func messageBody(format string, outcome ImportOutcome) ([]byte, error) {
+	switch format {
+	case FormatWebhook, "":
+		return json.Marshal(outcome)
+	case FormatSlack:
+		return json.Marshal(map[string]string{"text": summary(outcome)})
+	case FormatTeams:
+		return json.Marshal(map[string]interface{}{
+			"type": "message",
+			"attachments": []map[string]interface{}{{
+				"contentType": "application/vnd.microsoft.card.adaptive",
+				"content": map[string]interface{}{
+					"$schema": "http://adaptivecards.io/schemas/adaptive-card.json",
+					"type":    "AdaptiveCard",
+					"version": "1.4",
+					"body": []map[string]interface{}{
+						{"type": "TextBlock", "text": summary(outcome), "wrap": true},
+					},
+				},
+			}},
+		})
+	default:
+		return nil, fmt.Errorf("unknown notification format %q", format)
+	}
+}
+
+// summary returns a one-paragraph description of outcome for chat messages.
+func summary(outcome ImportOutcome) string {
+	if outcome.Result == ResultSuccess {
+		text := fmt.Sprintf("✅ API %s (%s/%s) was imported into %s.", outcome.APIID, outcome.Namespace, outcome.Name, outcome.APIMService)
+		if outcome.GatewayURL != "" {
+			text += " Gateway URL: " + outcome.GatewayURL
+		}
+		return text
+	}
+	return fmt.Sprintf("❌ Import of API %s (%s/%s) into %s failed at step %s: %s",
+		outcome.APIID, outcome.Namespace, outcome.Name, outcome.APIMService, outcome.Step, outcome.Error)
+}
@@ -0,0 +1,69 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestSend(t *testing.T) {
+	var got map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Errorf("decode body: %v", err)
+		}
+		if r.URL.Query().Get("fail") != "" {
+			w.WriteHeader(http.StatusForbidden)
+		}
+	}))
+	defer server.Close()
+
+	outcome := ImportOutcome{
+		Result:      ResultFailure,
+		APIMService: "my-apim",
+		Namespace:   "shop",
+		Name:        "orders",
+		APIID:       "shop-orders",
+		Step:        "import",
+		Reason:      "SpecInvalid",
+		Error:       "definition rejected",
+	}
+	if err := Send(context.Background(), FormatWebhook, server.URL, outcome); err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+	if got["apiId"] != "shop-orders" || got["step"] != "import" || got["error"] != "definition rejected" {
+		t.Errorf("webhook body = %v, want the outcome", got)
+	}
+
+	if err := Send(context.Background(), FormatSlack, server.URL, outcome); err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+	if text, _ := got["text"].(string); !strings.Contains(text, "shop-orders") || !strings.Contains(text, "definition rejected") {
+		t.Errorf("slack text = %q, want the API ID and error", text)
+	}
+
+	if err := Send(context.Background(), FormatTeams, server.URL, outcome); err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+	if got["type"] != "message" {
+		t.Errorf("teams body = %v, want a message with an Adaptive Card", got)
+	}
+
+	err := Send(context.Background(), FormatWebhook, server.URL+"?fail=1&token=secret", outcome)
+	if err == nil || strings.Contains(err.Error(), "secret") {
+		t.Errorf("Send() error = %v, want a failure that doesn't quote the URL", err)
+	}
+	if err := Send(context.Background(), "email", server.URL, outcome); err == nil {
+		t.Error("Send() accepted an unknown format")
+	}
+}
+
+func TestSendDoesNotQuoteURL(t *testing.T) {
+	err := Send(context.Background(), FormatSlack, "http://127.0.0.1:1/services/T000/B000/secret", ImportOutcome{Result: ResultSuccess})
+	if err == nil || strings.Contains(err.Error(), "secret") {
+		t.Errorf("Send() error = %v, want a connection error without the URL", err)
+	}
+}
@@ -0,0 +1,207 @@
+// Package openapi reads OpenAPI 3 and Swagger 2 definitions: it detects breaking changes between two versions
+// of a definition and reads the documentation metadata shown to API consumers. Breaking changes are detected
+// in the paths, operations, parameters and request bodies of a definition; schemas are not compared.
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"sigs.k8s.io/yaml"
+)
+
+// methods are the path item keys that hold operations.
+var methods = []string{"get", "put", "post", "delete", "options", "head", "patch", "trace"}
+
+// templateParam matches a path template parameter such as {id}.
+var templateParam = regexp.MustCompile(`\{[^}]*\}`)
+
+// document is the part of a definition that breaking changes are detected in.
+type document struct {
+	Paths map[string]map[string]json.RawMessage `json:"paths"`
+	// Parameters holds the shared parameters of Swagger 2.
+	Parameters map[string]parameter `json:"parameters"`
+	// Components holds the shared parameters and request bodies of OpenAPI 3.
+	Components struct {
+		Parameters    map[string]parameter   `json:"parameters"`
+		RequestBodies map[string]requestBody `json:"requestBodies"`
+	} `json:"components"`
+}
+
+type parameter struct {
+	Ref      string `json:"$ref"`
+	Name     string `json:"name"`
+	In       string `json:"in"`
+	Required bool   `json:"required"`
+}
+
+type requestBody struct {
+	Ref      string `json:"$ref"`
+	Required bool   `json:"required"`
+}
+
+type operation struct {
+	Parameters  []parameter  `json:"parameters"`
+	RequestBody *requestBody `json:"requestBody"`
+}
+
+// endpoint is an operation with its query, header, cookie and body parameters resolved, keyed by "<in>:<name>".
+type endpoint struct {
+	path         string
+	method       string
+	parameters   map[string]bool
+	bodyRequired bool
+}
+
+// BreakingChanges lists the changes from previous to current that break existing clients: removed paths and
+// operations, new required parameters, parameters that became required and request bodies that became
+// required. Paths are compared by their template, so renaming a path parameter is not a change.
+// The list is sorted and empty when current is compatible with previous.
+func BreakingChanges(previous, current []byte) ([]string, error) {
+	before, err := parse(previous)
+	if err != nil {
+		return nil, fmt.Errorf("parse previous definition: %w", err)
+	}
+	after, err := parse(current)
+	if err != nil {
+		return nil, fmt.Errorf("parse current definition: %w", err)
+	}
+
+	var changes []string
+	for route, old := range before {
+		if _, ok := after[route]; !ok {
+			changes = append(changes, fmt.Sprintf("path %s removed", anyPath(old)))
+			continue
+		}
+		for method, oldEndpoint := range old {
+			newEndpoint, ok := after[route][method]
+			if !ok {
+				changes = append(changes, fmt.Sprintf("operation %s %s removed", method, oldEndpoint.path))
+				continue
+			}
+			changes = append(changes, compareEndpoints(oldEndpoint, newEndpoint)...)
+		}
+	}
+	sort.Strings(changes)
+	return changes, nil
+}
+
+// compareEndpoints lists the breaking changes between two versions of the same operation.
+func compareEndpoints(old, current endpoint) []string {
+	var changes []string
+	for id, required := range current.parameters {
+		if !required {
+			continue
+		}
+		wasRequired, existed := old.parameters[id]
+		switch {
+		case !existed:
+			changes = append(changes, fmt.Sprintf("operation %s %s: required parameter %s added", current.method, current.path, id))
+		case !wasRequired:
+			changes = append(changes, fmt.Sprintf("operation %s %s: parameter %s became required", current.method, current.path, id))
+		}
+	}
+	if current.bodyRequired && !old.bodyRequired {
+		changes = append(changes, fmt.Sprintf("operation %s %s: request body became required", current.method, current.path))
+	}
+	return changes
+}
+
+// parse reads the endpoints of a JSON or YAML definition, by path template and upper-case method.
+func parse(content []byte) (map[string]map[string]endpoint, error) {
+	raw, err := yaml.YAMLToJSON(content)
+	if err != nil {
+		return nil, err
+	}
+	var doc document
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		return nil, err
+	}
+
+	routes := make(map[string]map[string]endpoint, len(doc.Paths))
+	for path, item := range doc.Paths {
+		var shared []parameter
+		if params, ok := item["parameters"]; ok {
+			if err := json.Unmarshal(params, &shared); err != nil {
+				return nil, fmt.Errorf("parameters of path %s: %w", path, err)
+			}
+		}
+		endpoints := map[string]endpoint{}
+		for _, method := range methods {
+			body, ok := item[method]
+			if !ok {
+				continue
+			}
+			var op operation
+			if err := json.Unmarshal(body, &op); err != nil {
+				return nil, fmt.Errorf("operation %s %s: %w", strings.ToUpper(method), path, err)
+			}
+			endpoints[strings.ToUpper(method)] = doc.endpoint(path, strings.ToUpper(method), shared, &op)
+		}
+		routes[templateParam.ReplaceAllString(path, "{}")] = endpoints
+	}
+	return routes, nil
+}
+
+// endpoint resolves the parameters of op, which override the shared parameters of its path.
+func (d *document) endpoint(path, method string, shared []parameter, op *operation) endpoint {
+	e := endpoint{path: path, method: method, parameters: map[string]bool{}}
+	for _, params := range [][]parameter{shared, op.Parameters} {
+		for _, p := range params {
+			// Path parameters are part of the path template and compared with it.
+			p = d.resolveParameter(p)
+			if p.In == "" || p.In == "path" {
+				continue
+			}
+			e.parameters[parameterID(p)] = p.Required
+		}
+	}
+	if op.RequestBody != nil {
+		e.bodyRequired = d.resolveRequestBody(*op.RequestBody).Required
+	}
+	return e
+}
+
+// parameterID identifies a parameter by location and name. A Swagger 2 operation has at most one body
+// parameter, so its name is ignored, and header names are case-insensitive.
+func parameterID(p parameter) string {
+	switch p.In {
+	case "body":
+		return "body"
+	case "header":
+		return "header:" + strings.ToLower(p.Name)
+	}
+	return p.In + ":" + p.Name
+}
+
+// resolveParameter follows a local $ref to a shared parameter. Other references resolve to an empty parameter.
+func (d *document) resolveParameter(p parameter) parameter {
+	switch {
+	case p.Ref == "":
+		return p
+	case strings.HasPrefix(p.Ref, "#/components/parameters/"):
+		return d.Components.Parameters[strings.TrimPrefix(p.Ref, "#/components/parameters/")]
+	case strings.HasPrefix(p.Ref, "#/parameters/"):
+		return d.Parameters[strings.TrimPrefix(p.Ref, "#/parameters/")]
+	}
+	return parameter{}
+}
+
+// resolveRequestBody follows a local $ref to a shared request body.
+func (d *document) resolveRequestBody(b requestBody) requestBody {
+	if name, ok := strings.CutPrefix(b.Ref, "#/components/requestBodies/"); ok {
+		return d.Components.RequestBodies[name]
+	}
+	return b
+}
+
+// anyPath returns the path of one of the endpoints of a path, or "(unknown)" for a path without operations.
+func anyPath(endpoints map[string]endpoint) string {
+	for _, e := range endpoints {
+		return e.path
+	}
+	return "(unknown)"
+}
@@ -0,0 +1,113 @@
+package openapi
+
+import (
+	"reflect"
+	"testing"
+)
+
+const ordersV1 = `
+openapi: 3.0.1
+paths:
+  /orders:
+    get:
+      parameters:
+        - name: limit
+          in: query
+    post:
+      requestBody:
+        $ref: '#/components/requestBodies/Order'
+  /orders/{id}:
+    parameters:
+      - name: id
+        in: path
+        required: true
+    get:
+      parameters:
+        - $ref: '#/components/parameters/Tenant'
+    delete: {}
+components:
+  parameters:
+    Tenant:
+      name: X-Tenant
+      in: header
+  requestBodies:
+    Order:
+      required: false
+`
+
+func TestBreakingChanges(t *testing.T) {
+	tests := []struct {
+		name    string
+		current string
+		want    []string
+	}{
+		{name: "unchanged", current: ordersV1},
+		{
+			name: "compatible additions and renamed path parameter",
+			current: `{"openapi": "3.0.1", "paths": {
+				"/orders": {"get": {"parameters": [{"name": "limit", "in": "query"}, {"name": "sort", "in": "query"}]},
+				            "post": {"requestBody": {"required": false}}},
+				"/orders/{orderId}": {"get": {"parameters": [{"name": "orderId", "in": "path", "required": true},
+				                                             {"name": "x-tenant", "in": "header"}]},
+				                      "delete": {}},
+				"/customers": {"get": {}}}}`,
+		},
+		{
+			name: "removed path and operation",
+			current: `{"openapi": "3.0.1", "paths": {
+				"/orders/{id}": {"get": {"parameters": [{"name": "id", "in": "path"}]}}}}`,
+			want: []string{
+				"operation DELETE /orders/{id} removed",
+				"path /orders removed",
+			},
+		},
+		{
+			name: "required parameters and request body",
+			current: `{"openapi": "3.0.1", "paths": {
+				"/orders": {"get": {"parameters": [{"name": "limit", "in": "query", "required": true}]},
+				            "post": {"requestBody": {"required": true}}},
+				"/orders/{id}": {"get": {"parameters": [{"name": "id", "in": "path"},
+				                                        {"name": "X-Tenant", "in": "header", "required": true},
+				                                        {"name": "expand", "in": "query", "required": true}]},
+				                 "delete": {}}}}`,
+			want: []string{
+				"operation GET /orders/{id}: parameter header:x-tenant became required",
+				"operation GET /orders/{id}: required parameter query:expand added",
+				"operation GET /orders: parameter query:limit became required",
+				"operation POST /orders: request body became required",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := BreakingChanges([]byte(ordersV1), []byte(tt.current))
+			if err != nil {
+				t.Fatalf("BreakingChanges() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("BreakingChanges() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBreakingChangesSwagger2(t *testing.T) {
+	previous := `{"swagger": "2.0", "paths": {"/orders": {"post": {"parameters": [{"$ref": "#/parameters/Order"}]}}},
+		"parameters": {"Order": {"name": "order", "in": "body"}}}`
+	current := `{"swagger": "2.0", "paths": {"/orders": {"post": {"parameters": [{"name": "payload", "in": "body", "required": true}]}}}}`
+
+	got, err := BreakingChanges([]byte(previous), []byte(current))
+	if err != nil {
+		t.Fatalf("BreakingChanges() error = %v", err)
+	}
+	want := []string{"operation POST /orders: parameter body became required"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("BreakingChanges() = %q, want %q", got, want)
+	}
+}
+
+func TestBreakingChangesInvalidDefinition(t *testing.T) {
+	if _, err := BreakingChanges([]byte(ordersV1), []byte("paths: [")); err == nil {
+		t.Error("BreakingChanges() error = nil, want a parse error")
+	}
+}
@@ -0,0 +1,111 @@
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"sigs.k8s.io/yaml"
+)
+
+// Documentation is the documentation metadata of a definition that is shown to API consumers.
+type Documentation struct {
+	// Title is info.title.
+	Title string
+	// Description is info.description, in CommonMark.
+	Description string
+	// ExternalDocs links to further documentation of the API, if the definition has any.
+	ExternalDocs *ExternalDocs
+	// Operations lists every operation in path order, with its summary.
+	Operations []OperationDoc
+}
+
+// ExternalDocs is the externalDocs object of a definition.
+type ExternalDocs struct {
+	URL         string `json:"url"`
+	Description string `json:"description"`
+}
+
+// OperationDoc is the documentation of one operation.
+type OperationDoc struct {
+	// Method is the upper-case HTTP method.
+	Method      string
+	Path        string
+	OperationID string
+	Summary     string
+}
+
+// Undocumented returns the operations without a summary, as "<method> <path>". APIM names them after their
+// operationId or their path in the developer portal.
+func (d Documentation) Undocumented() []string {
+	var undocumented []string
+	for _, op := range d.Operations {
+		if strings.TrimSpace(op.Summary) == "" {
+			undocumented = append(undocumented, op.Method+" "+op.Path)
+		}
+	}
+	return undocumented
+}
+
+// APIDescription returns the description of the API entity in APIM: info.description followed by a link
+// to the external documentation, which APIM has no field for. The developer portal renders it as Markdown.
+func (d Documentation) APIDescription() string {
+	if d.ExternalDocs == nil || d.ExternalDocs.URL == "" {
+		return d.Description
+	}
+	text := d.ExternalDocs.Description
+	if text == "" {
+		text = "Further documentation"
+	}
+	link := fmt.Sprintf("[%s](%s)", strings.ReplaceAll(text, "]", `\]`), d.ExternalDocs.URL)
+	if d.Description == "" {
+		return link
+	}
+	return strings.TrimRight(d.Description, "\n") + "\n\n" + link
+}
+
+// ReadDocumentation reads the documentation metadata of a JSON or YAML OpenAPI 3 or Swagger 2 definition.
+func ReadDocumentation(content []byte) (Documentation, error) {
+	raw, err := yaml.YAMLToJSON(content)
+	if err != nil {
+		return Documentation{}, err
+	}
+	var doc struct {
+		Info struct {
+			Title       string `json:"title"`
+			Description string `json:"description"`
+		} `json:"info"`
+		ExternalDocs *ExternalDocs                         `json:"externalDocs"`
+		Paths        map[string]map[string]json.RawMessage `json:"paths"`
+	}
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		return Documentation{}, err
+	}
+
+	docs := Documentation{Title: doc.Info.Title, Description: doc.Info.Description, ExternalDocs: doc.ExternalDocs}
+	paths := make([]string, 0, len(doc.Paths))
+	for path := range doc.Paths {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		for _, method := range methods {
+			body, ok := doc.Paths[path][method]
+			if !ok {
+				continue
+			}
+			var op struct {
+				OperationID string `json:"operationId"`
+				Summary     string `json:"summary"`
+			}
+			if err := json.Unmarshal(body, &op); err != nil {
+				return Documentation{}, fmt.Errorf("operation %s %s: %w", strings.ToUpper(method), path, err)
+			}
+			docs.Operations = append(docs.Operations, OperationDoc{
+				Method: strings.ToUpper(method), Path: path, OperationID: op.OperationID, Summary: op.Summary,
+			})
+		}
+	}
+	return docs, nil
+}
@@ -0,0 +1,91 @@
+package openapi
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestReadDocumentation(t *testing.T) {
+	const openAPI3 = `
+openapi: 3.0.1
+info:
+  title: Orders
+  description: |
+    Manage **orders**.
+externalDocs:
+  url: https://docs.example.com/orders
+  description: Orders guide
+paths:
+  /orders/{id}:
+    delete:
+      operationId: deleteOrder
+    get:
+      operationId: getOrder
+      summary: Get an order
+  /orders:
+    post:
+      summary: Create an order
+`
+	got, err := ReadDocumentation([]byte(openAPI3))
+	if err != nil {
+		t.Fatalf("ReadDocumentation() error = %v", err)
+	}
+	want := Documentation{
+		Title:        "Orders",
+		Description:  "Manage **orders**.\n",
+		ExternalDocs: &ExternalDocs{URL: "https://docs.example.com/orders", Description: "Orders guide"},
+		Operations: []OperationDoc{
+			{Method: "POST", Path: "/orders", Summary: "Create an order"},
+			{Method: "GET", Path: "/orders/{id}", OperationID: "getOrder", Summary: "Get an order"},
+			{Method: "DELETE", Path: "/orders/{id}", OperationID: "deleteOrder"},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ReadDocumentation() = %+v, want %+v", got, want)
+	}
+	if undocumented := got.Undocumented(); !reflect.DeepEqual(undocumented, []string{"DELETE /orders/{id}"}) {
+		t.Errorf("Undocumented() = %v, want [DELETE /orders/{id}]", undocumented)
+	}
+
+	swagger := `{"swagger":"2.0","info":{"title":"Legacy","version":"1"},"paths":{"/ping":{"get":{"summary":"Ping"}}}}`
+	got, err = ReadDocumentation([]byte(swagger))
+	if err != nil {
+		t.Fatalf("ReadDocumentation() of Swagger 2 error = %v", err)
+	}
+	if got.Title != "Legacy" || got.ExternalDocs != nil || len(got.Operations) != 1 || got.Undocumented() != nil {
+		t.Errorf("ReadDocumentation() of Swagger 2 = %+v, want one documented operation", got)
+	}
+
+	if _, err := ReadDocumentation([]byte("paths: [")); err == nil {
+		t.Error("ReadDocumentation() of invalid YAML succeeded")
+	}
+}
+
+func TestAPIDescription(t *testing.T) {
+	tests := []struct {
+		name string
+		docs Documentation
+		want string
+	}{
+		{name: "description only", docs: Documentation{Description: "Orders"}, want: "Orders"},
+		{
+			name: "external docs",
+			docs: Documentation{Description: "Orders\n", ExternalDocs: &ExternalDocs{URL: "https://docs.example.com", Description: "Guide [v2]"}},
+			want: "Orders\n\n[Guide [v2\\]](https://docs.example.com)",
+		},
+		{
+			name: "external docs without a description",
+			docs: Documentation{ExternalDocs: &ExternalDocs{URL: "https://docs.example.com"}},
+			want: "[Further documentation](https://docs.example.com)",
+		},
+		{name: "external docs without a URL", docs: Documentation{Description: "Orders", ExternalDocs: &ExternalDocs{}}, want: "Orders"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.docs.APIDescription(); got != tt.want {
+				t.Errorf("APIDescription() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
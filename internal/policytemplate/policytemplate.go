@@ -0,0 +1,32 @@
+// Package policytemplate renders the policy documents of APIMInboundPolicies that set templateValues.
+// Placeholders are written ${{ .name }}, so the {{name}} references of APIM named values pass through
+// unchanged for APIM to resolve.
+package policytemplate
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+)
+
+// Delimiters of policy template actions.
+const (
+	LeftDelim  = "${{"
+	RightDelim = "}}"
+)
+
+// Render replaces the placeholders in content with values. A placeholder without a value is an error.
+func Render(name, content string, values map[string]string) (string, error) {
+	tmpl, err := template.New(name).
+		Delims(LeftDelim, RightDelim).
+		Option("missingkey=error").
+		Parse(content)
+	if err != nil {
+		return "", fmt.Errorf("parse policy template: %w", err)
+	}
+	var rendered strings.Builder
+	if err := tmpl.Execute(&rendered, values); err != nil {
+		return "", fmt.Errorf("render policy template: %w", err)
+	}
+	return rendered.String(), nil
+}
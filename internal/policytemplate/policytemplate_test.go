@@ -0,0 +1,34 @@
+package policytemplate
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRender(t *testing.T) {
+	tests := []struct {
+		name      string
+		content   string
+		want      string
+		wantError string
+	}{
+		{name: "placeholders", content: `<value>${{ .env }}</value><value>${{.env}}</value>`, want: `<value>prod</value><value>prod</value>`},
+		{name: "named value references are kept", content: `<value>{{BackendKey}}</value>`, want: `<value>{{BackendKey}}</value>`},
+		{name: "missing value", content: `<value>${{ .region }}</value>`, wantError: "render policy template"},
+		{name: "syntax error", content: `<value>${{ .env </value>`, wantError: "parse policy template"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Render("policy", tt.content, map[string]string{"env": "prod"})
+			if tt.wantError != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantError) {
+					t.Fatalf("Render() error = %v, want %q", err, tt.wantError)
+				}
+				return
+			}
+			if err != nil || got != tt.want {
+				t.Errorf("Render() = %q, %v, want %q", got, err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,38 @@
+// Package redact removes credentials from text before it is logged or copied into a resource status.
+// Azure error bodies, OpenAPI documents and HTTP errors can echo bearer tokens, subscription keys
+// or SAS signatures back, so every such string passes through String first.
+package redact
+
+import "regexp"
+
+// Placeholder replaces every redacted value.
+const Placeholder = "[REDACTED]"
+
+// rule replaces the secret part of each match of pattern. Patterns capture the non-secret prefix
+// in group 1 so it is kept, e.g. the header name or JSON key.
+type rule struct {
+	pattern     *regexp.Regexp
+	replacement string
+}
+
+var rules = []rule{
+	// Authorization headers and error messages that quote them: "Bearer eyJ0eX...".
+	{regexp.MustCompile(`(?i)(bearer\s+)[A-Za-z0-9\-._~+/]+=*`), "${1}" + Placeholder},
+	// Bare JWTs, e.g. access tokens echoed in error bodies.
+	{regexp.MustCompile(`eyJ[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+\.[A-Za-z0-9_-]*`), Placeholder},
+	// JSON properties holding keys, secrets and tokens, e.g. APIM subscription "primaryKey".
+	{regexp.MustCompile(`(?i)("(?:primaryKey|secondaryKey|subscriptionKey|key|password|secret|clientSecret|client_secret|` +
+		`accessToken|access_token|refreshToken|refresh_token|token|sharedAccessSignature)"\s*:\s*)"[^"]*"`), `${1}"` + Placeholder + `"`},
+	// Subscription key and authorization headers in dumped requests.
+	{regexp.MustCompile(`(?i)((?:ocp-apim-subscription-key|authorization)\s*:\s*)[^\s",]+`), "${1}" + Placeholder},
+	// Credentials in URL query strings: SAS signatures, function keys and subscription keys.
+	{regexp.MustCompile(`(?i)([?&](?:sig|code|subscription-key|access_token|client_secret)=)[^&\s"]+`), "${1}" + Placeholder},
+}
+
+// String returns s with bearer tokens, JWTs, subscription keys and other credentials replaced by Placeholder.
+func String(s string) string {
+	for _, r := range rules {
+		s = r.pattern.ReplaceAllString(s, r.replacement)
+	}
+	return s
+}
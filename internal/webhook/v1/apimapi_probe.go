@@ -0,0 +1,53 @@
+package v1
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/hedinit/azure-apim-operator/internal/redact"
+)
+
+// openAPIProbeTimeout bounds the reachability probe well below the API server's webhook timeout.
+const openAPIProbeTimeout = 3 * time.Second
+
+// isDryRun reports whether the admission request in ctx is a server-side dry run.
+func isDryRun(ctx context.Context) bool {
+	req, err := admission.RequestFromContext(ctx)
+	return err == nil && req.DryRun != nil && *req.DryRun
+}
+
+// probeOpenAPIURL sends a HEAD request to the OpenAPI definition URL and returns a warning if it is unreachable.
+// It only runs for dry-run requests when the probe is enabled, so regular applies never wait on the network.
+// The probe runs from the operator pod, which is where the controller fetches the definition from as well.
+func (v *APIMAPICustomValidator) probeOpenAPIURL(ctx context.Context, rawURL string) admission.Warnings {
+	if !v.ProbeOpenAPIURLOnDryRun || !isDryRun(ctx) {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, openAPIProbeTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, rawURL, nil)
+	if err != nil {
+		return admission.Warnings{fmt.Sprintf("openApiDefinitionUrl %s is unreachable: %v", rawURL, err)}
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return admission.Warnings{fmt.Sprintf("openApiDefinitionUrl %s is unreachable: %v", rawURL, err)}
+	}
+	defer func() {
+		if closeErr := resp.Body.Close(); closeErr != nil {
+			apimapilog.Error(closeErr, "⚠️ Failed to close response body", "url", redact.String(rawURL))
+		}
+	}()
+
+	// Some servers don't implement HEAD; a 405 still proves the URL answers.
+	if resp.StatusCode >= 400 && resp.StatusCode != http.StatusMethodNotAllowed {
+		return admission.Warnings{fmt.Sprintf("openApiDefinitionUrl %s returned %s", rawURL, resp.Status)}
+	}
+	return nil
+}
@@ -0,0 +1,54 @@
+package v1
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+func TestProbeOpenAPIURL(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/swagger.json":
+			w.WriteHeader(http.StatusOK)
+		case "/get-only":
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	dryRunCtx := admission.NewContextWithRequest(context.Background(),
+		admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{DryRun: ptr.To(true)}})
+
+	tests := []struct {
+		name        string
+		disabled    bool
+		ctx         context.Context
+		url         string
+		wantWarning bool
+	}{
+		{name: "reachable", ctx: dryRunCtx, url: server.URL + "/swagger.json"},
+		{name: "HEAD not allowed", ctx: dryRunCtx, url: server.URL + "/get-only"},
+		{name: "not found", ctx: dryRunCtx, url: server.URL + "/missing.json", wantWarning: true},
+		{name: "connection refused", ctx: dryRunCtx, url: "http://127.0.0.1:1/swagger.json", wantWarning: true},
+		{name: "not a dry run", ctx: context.Background(), url: server.URL + "/missing.json"},
+		{name: "probe disabled", disabled: true, ctx: dryRunCtx, url: server.URL + "/missing.json"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := &APIMAPICustomValidator{APIMAPIWebhookOptions: APIMAPIWebhookOptions{ProbeOpenAPIURLOnDryRun: !tt.disabled}}
+			warnings := v.probeOpenAPIURL(tt.ctx, tt.url)
+			if tt.wantWarning != (len(warnings) > 0) {
+				t.Errorf("probeOpenAPIURL(%q) = %v, wantWarning %t", tt.url, warnings, tt.wantWarning)
+			}
+		})
+	}
+}
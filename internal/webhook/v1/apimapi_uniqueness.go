@@ -0,0 +1,134 @@
+package v1
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apimv1 "github.com/hedinit/azure-apim-operator/api/v1"
+)
+
+const (
+	// routeKeyField indexes APIMAPIs by the APIM service and route prefix they claim, so conflicting
+	// route prefixes can be found with a single cached lookup.
+	routeKeyField = "apimapi.routeKey"
+	// apiIDKeyField indexes APIMAPIs by the APIM service and API ID they claim.
+	apiIDKeyField = "apimapi.apiIDKey"
+)
+
+// routeKey identifies the path an APIMAPI occupies in an APIM service. APIM matches paths
+// case-insensitively and ignores a trailing slash, so both are normalized away. The versions of a version
+// set share their path, so a versioned API only occupies it for its version.
+func routeKey(spec *apimv1.APIMAPISpec) string {
+	if spec.APIMService == "" || spec.RoutePrefix == "" {
+		return ""
+	}
+	key := spec.APIMService + "/" + strings.ToLower(strings.TrimSuffix(spec.RoutePrefix, "/"))
+	if spec.VersionSetID != "" {
+		key += ";v=" + strings.ToLower(spec.APIVersion)
+	}
+	return key
+}
+
+// apiIDKey identifies the API an APIMAPI manages in an APIM service. API IDs are ARM resource names,
+// which are case-insensitive.
+func apiIDKey(spec *apimv1.APIMAPISpec) string {
+	if spec.APIMService == "" || spec.APIID == "" {
+		return ""
+	}
+	return spec.APIMService + "/" + strings.ToLower(spec.APIID)
+}
+
+// indexRouteKey is the field indexer for routeKeyField.
+func indexRouteKey(obj client.Object) []string {
+	return indexSpecKey(obj, routeKey)
+}
+
+// indexAPIIDKey is the field indexer for apiIDKeyField.
+func indexAPIIDKey(obj client.Object) []string {
+	return indexSpecKey(obj, apiIDKey)
+}
+
+func indexSpecKey(obj client.Object, keyFunc func(*apimv1.APIMAPISpec) string) []string {
+	apimApi, ok := obj.(*apimv1.APIMAPI)
+	if !ok {
+		return nil
+	}
+	if key := keyFunc(&apimApi.Spec); key != "" {
+		return []string{key}
+	}
+	return nil
+}
+
+// findConflict returns another APIMAPI whose indexed key equals that of apimApi, or nil if there is none.
+// On updates it only looks when the key changes, so pre-existing duplicates don't block unrelated edits.
+func (v *APIMAPICustomValidator) findConflict(
+	ctx context.Context,
+	apimApi, oldApi *apimv1.APIMAPI,
+	indexField string,
+	keyFunc func(*apimv1.APIMAPISpec) string,
+) (*apimv1.APIMAPI, error) {
+	key := keyFunc(&apimApi.Spec)
+	if key == "" || (oldApi != nil && keyFunc(&oldApi.Spec) == key) {
+		return nil, nil
+	}
+
+	var others apimv1.APIMAPIList
+	if err := v.Client.List(ctx, &others, client.MatchingFields{indexField: key}); err != nil {
+		return nil, fmt.Errorf("failed to list APIMAPIs by %s: %w", indexField, err)
+	}
+	for i := range others.Items {
+		other := &others.Items[i]
+		if other.Namespace == apimApi.Namespace && other.Name == apimApi.Name {
+			continue
+		}
+		return other, nil
+	}
+	return nil, nil
+}
+
+// validateUniqueness rejects a route prefix or API ID that another APIMAPI already uses on the same APIM service.
+// Both are flat namespaces in APIM, so a duplicate would silently overwrite the other team's API.
+func (v *APIMAPICustomValidator) validateUniqueness(
+	ctx context.Context,
+	apimApi, oldApi *apimv1.APIMAPI,
+	path *field.Path,
+) (field.ErrorList, error) {
+	var allErrs field.ErrorList
+
+	other, err := v.findConflict(ctx, apimApi, oldApi, routeKeyField, routeKey)
+	if err != nil {
+		return nil, err
+	}
+	if other != nil {
+		allErrs = append(allErrs, field.Invalid(path.Child("routePrefix"), apimApi.Spec.RoutePrefix,
+			fmt.Sprintf("already used by APIMAPI %s/%s on APIM service %q", other.Namespace, other.Name, apimApi.Spec.APIMService)))
+	}
+
+	other, err = v.findConflict(ctx, apimApi, oldApi, apiIDKeyField, apiIDKey)
+	if err != nil {
+		return nil, err
+	}
+	if other != nil {
+		allErrs = append(allErrs, field.Invalid(path.Child("APIID"), apimApi.Spec.APIID,
+			fmt.Sprintf("already used by APIMAPI %s/%s on APIM service %q", other.Namespace, other.Name, apimApi.Spec.APIMService)))
+	}
+
+	return allErrs, nil
+}
+
+// validateAPIIDNamespacePrefix enforces the optional policy that API IDs start with "<namespace>-",
+// which gives every namespace its own slice of the APIM API ID space.
+func (v *APIMAPICustomValidator) validateAPIIDNamespacePrefix(apimApi *apimv1.APIMAPI, path *field.Path) field.ErrorList {
+	if !v.RequireAPIIDNamespacePrefix || apimApi.Spec.APIID == "" {
+		return nil
+	}
+	prefix := apimApi.Namespace + "-"
+	if strings.HasPrefix(apimApi.Spec.APIID, prefix) {
+		return nil
+	}
+	return field.ErrorList{field.Invalid(path.Child("APIID"), apimApi.Spec.APIID, fmt.Sprintf("must start with %q", prefix))}
+}
@@ -0,0 +1,265 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package v1 contains the admission and conversion webhooks for the apim v1 API group.
+package v1
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	apimv1 "github.com/hedinit/azure-apim-operator/api/v1"
+)
+
+// apimapilog is for logging in this package.
+var apimapilog = logf.Log.WithName("apimapi-resource")
+
+const (
+	// defaultOpenAPIPath is appended to serviceUrl when openApiDefinitionUrl is omitted.
+	defaultOpenAPIPath = "/swagger/v1/swagger.json"
+)
+
+// routePrefixPattern matches an absolute URL path made of unreserved characters, e.g. "/payments/v1".
+var routePrefixPattern = regexp.MustCompile(`^/([A-Za-z0-9._~-]+/?)*$`)
+
+// apiVersionPattern matches a single URL path segment of unreserved characters, e.g. "v2" or "2024-01-01".
+// With the Segment versioning scheme, APIM serves the version under <routePrefix>/<apiVersion>.
+var apiVersionPattern = regexp.MustCompile(`^[A-Za-z0-9._~-]+$`)
+
+// APIMAPIWebhookOptions configures the optional APIMAPI admission policies.
+type APIMAPIWebhookOptions struct {
+	// OperatorNamespace is the namespace where APIMService resources live.
+	OperatorNamespace string
+	// RequireAPIIDNamespacePrefix rejects API IDs that don't start with "<namespace>-".
+	RequireAPIIDNamespacePrefix bool
+	// RejectMissingAPIMService rejects APIMAPIs that reference a missing APIMService instead of only warning.
+	RejectMissingAPIMService bool
+	// RequireHTTPSServiceURL only admits https and wss service URLs.
+	RequireHTTPSServiceURL bool
+	// ProbeOpenAPIURLOnDryRun sends a HEAD request to openApiDefinitionUrl on server-side dry runs
+	// and warns if it is unreachable.
+	ProbeOpenAPIURLOnDryRun bool
+}
+
+// SetupAPIMAPIWebhookWithManager registers the webhooks for APIMAPI in the manager.
+// v1 is the conversion hub, so registering it also serves /convert for the v2 spoke.
+func SetupAPIMAPIWebhookWithManager(mgr ctrl.Manager, opts APIMAPIWebhookOptions) error {
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &apimv1.APIMAPI{}, routeKeyField, indexRouteKey); err != nil {
+		return err
+	}
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &apimv1.APIMAPI{}, apiIDKeyField, indexAPIIDKey); err != nil {
+		return err
+	}
+
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(&apimv1.APIMAPI{}).
+		WithValidator(&APIMAPICustomValidator{Client: mgr.GetClient(), APIMAPIWebhookOptions: opts}).
+		WithDefaulter(&APIMAPICustomDefaulter{}).
+		Complete()
+}
+
+// +kubebuilder:webhook:path=/mutate-apim-operator-io-v1-apimapi,mutating=true,failurePolicy=fail,sideEffects=None,groups=apim.operator.io,resources=apimapis,verbs=create;update,versions=v1,name=mapimapi-v1.kb.io,admissionReviewVersions=v1
+
+// APIMAPICustomDefaulter fills in optional APIMAPI fields so application repos only have to set what differs
+// from the conventions. subscriptionRequired needs no webhook: the CRD schema already defaults it to true.
+type APIMAPICustomDefaulter struct{}
+
+var _ webhook.CustomDefaulter = &APIMAPICustomDefaulter{}
+
+// Default implements webhook.CustomDefaulter.
+func (d *APIMAPICustomDefaulter) Default(_ context.Context, obj runtime.Object) error {
+	apimApi, ok := obj.(*apimv1.APIMAPI)
+	if !ok {
+		return fmt.Errorf("expected an APIMAPI object but got %T", obj)
+	}
+	apimapilog.Info("Defaulting for APIMAPI", "name", apimApi.GetName())
+
+	defaultAPIMAPISpec(apimApi)
+	return nil
+}
+
+// defaultAPIMAPISpec defaults routePrefix to /<name> and openApiDefinitionUrl to the conventional
+// Swagger path on the active service URL. Objects created with generateName have no name yet and keep an empty routePrefix.
+func defaultAPIMAPISpec(apimApi *apimv1.APIMAPI) {
+	if apimApi.Spec.RoutePrefix == "" && apimApi.Name != "" {
+		apimApi.Spec.RoutePrefix = "/" + apimApi.Name
+	}
+	if serviceURL := apimApi.Spec.ActiveServiceURL(); apimApi.Spec.OpenAPIDefinitionURL == "" && serviceURL != "" {
+		apimApi.Spec.OpenAPIDefinitionURL = strings.TrimSuffix(serviceURL, "/") + defaultOpenAPIPath
+	}
+}
+
+// +kubebuilder:webhook:path=/validate-apim-operator-io-v1-apimapi,mutating=false,failurePolicy=fail,sideEffects=None,groups=apim.operator.io,resources=apimapis,verbs=create;update,versions=v1,name=vapimapi-v1.kb.io,admissionReviewVersions=v1
+
+// APIMAPICustomValidator rejects APIMAPI specs that APIM would refuse or that collide with other APIMAPIs,
+// so mistakes surface at apply time instead of as failed or clobbered imports.
+type APIMAPICustomValidator struct {
+	// Client reads other APIMAPIs and APIMServices from the manager cache.
+	Client client.Reader
+	APIMAPIWebhookOptions
+}
+
+var _ webhook.CustomValidator = &APIMAPICustomValidator{}
+
+// ValidateCreate implements webhook.CustomValidator.
+func (v *APIMAPICustomValidator) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	apimApi, ok := obj.(*apimv1.APIMAPI)
+	if !ok {
+		return nil, fmt.Errorf("expected an APIMAPI object but got %T", obj)
+	}
+	apimapilog.Info("Validation for APIMAPI upon creation", "name", apimApi.GetName())
+
+	return v.validateAPIMAPI(ctx, apimApi, nil)
+}
+
+// ValidateUpdate implements webhook.CustomValidator.
+func (v *APIMAPICustomValidator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	apimApi, ok := newObj.(*apimv1.APIMAPI)
+	if !ok {
+		return nil, fmt.Errorf("expected an APIMAPI object for the newObj but got %T", newObj)
+	}
+	oldApi, ok := oldObj.(*apimv1.APIMAPI)
+	if !ok {
+		return nil, fmt.Errorf("expected an APIMAPI object for the oldObj but got %T", oldObj)
+	}
+	apimapilog.Info("Validation for APIMAPI upon update", "name", apimApi.GetName())
+
+	return v.validateAPIMAPI(ctx, apimApi, oldApi)
+}
+
+// ValidateDelete implements webhook.CustomValidator. Deletes are always allowed.
+func (v *APIMAPICustomValidator) ValidateDelete(_ context.Context, _ runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+// validateAPIMAPI returns an Invalid error listing every spec field that violates APIM's rules
+// or conflicts with another APIMAPI, plus warnings for problems that don't block admission. oldApi is nil on create.
+func (v *APIMAPICustomValidator) validateAPIMAPI(ctx context.Context, apimApi, oldApi *apimv1.APIMAPI) (admission.Warnings, error) {
+	specPath := field.NewPath("spec")
+	allErrs := validateAPIMAPISpec(&apimApi.Spec, specPath)
+	allErrs = append(allErrs, v.validateServiceURLs(&apimApi.Spec, specPath)...)
+	allErrs = append(allErrs, v.validateAPIIDNamespacePrefix(apimApi, specPath)...)
+
+	conflictErrs, err := v.validateUniqueness(ctx, apimApi, oldApi, specPath)
+	if err != nil {
+		return nil, err
+	}
+	allErrs = append(allErrs, conflictErrs...)
+
+	quotaErrs, err := v.validateNamespaceQuota(ctx, apimApi, oldApi, specPath.Child("apimService"))
+	if err != nil {
+		return nil, err
+	}
+	allErrs = append(allErrs, quotaErrs...)
+
+	var oldService string
+	if oldApi != nil {
+		oldService = oldApi.Spec.APIMService
+	}
+	warnings, referenceErrs, err := validateAPIMServiceReference(ctx, v.Client, v.OperatorNamespace,
+		apimApi.Spec.APIMService, oldService, v.RejectMissingAPIMService, specPath.Child("apimService"))
+	if err != nil {
+		return nil, err
+	}
+	allErrs = append(allErrs, referenceErrs...)
+
+	if len(allErrs) == 0 {
+		return append(warnings, v.probeOpenAPIURL(ctx, apimApi.Spec.OpenAPIDefinitionURL)...), nil
+	}
+	return warnings, apierrors.NewInvalid(apimv1.GroupVersion.WithKind("APIMAPI").GroupKind(), apimApi.Name, allErrs)
+}
+
+func validateAPIMAPISpec(spec *apimv1.APIMAPISpec, path *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if !routePrefixPattern.MatchString(spec.RoutePrefix) {
+		allErrs = append(allErrs, field.Invalid(path.Child("routePrefix"), spec.RoutePrefix,
+			"must start with '/' and contain only letters, digits, '-', '.', '_', '~' and single '/' separators"))
+	}
+
+	allErrs = append(allErrs, validateAzureName(spec.APIID, maxAPIIDLength, path.Child("APIID"))...)
+	allErrs = append(allErrs, validateAzureNames(spec.ProductIDs, maxProductIDLength, path.Child("productIds"))...)
+	allErrs = append(allErrs, validateAzureNames(spec.TagIDs, maxTagIDLength, path.Child("tagIds"))...)
+
+	if spec.APIMService == "" {
+		allErrs = append(allErrs, field.Required(path.Child("apimService"), ""))
+	}
+
+	if spec.VersionSetID != "" || spec.APIVersion != "" {
+		allErrs = append(allErrs, validateAzureName(spec.VersionSetID, maxVersionSetIDLength, path.Child("versionSetId"))...)
+		if !apiVersionPattern.MatchString(spec.APIVersion) {
+			allErrs = append(allErrs, field.Invalid(path.Child("apiVersion"), spec.APIVersion,
+				"must be a single path segment of letters, digits, '-', '.', '_' and '~'"))
+		}
+	}
+
+	allErrs = append(allErrs, validateURL(spec.OpenAPIDefinitionURL, []string{"http", "https"}, path.Child("openApiDefinitionUrl"))...)
+
+	return allErrs
+}
+
+// validateServiceURLs validates serviceUrl, or both blue/green backends when they replace it.
+func (v *APIMAPICustomValidator) validateServiceURLs(spec *apimv1.APIMAPISpec, path *field.Path) field.ErrorList {
+	if spec.Backends == nil {
+		return validateURL(spec.ServiceURL, v.serviceURLSchemes(), path.Child("serviceUrl"))
+	}
+	var allErrs field.ErrorList
+	if spec.ServiceURL != "" {
+		allErrs = append(allErrs, field.Forbidden(path.Child("serviceUrl"), "must be empty when backends are set"))
+	}
+	if spec.ActiveBackend != apimv1.BackendBlue && spec.ActiveBackend != apimv1.BackendGreen {
+		allErrs = append(allErrs, field.NotSupported(path.Child("activeBackend"), spec.ActiveBackend,
+			[]apimv1.BackendColor{apimv1.BackendBlue, apimv1.BackendGreen}))
+	}
+	backendsPath := path.Child("backends")
+	allErrs = append(allErrs, validateURL(spec.Backends.Blue, v.serviceURLSchemes(), backendsPath.Child("blue"))...)
+	allErrs = append(allErrs, validateURL(spec.Backends.Green, v.serviceURLSchemes(), backendsPath.Child("green"))...)
+	return allErrs
+}
+
+// serviceURLSchemes returns the backend URL schemes APIM may proxy to under the configured policy.
+func (v *APIMAPICustomValidator) serviceURLSchemes() []string {
+	if v.RequireHTTPSServiceURL {
+		return []string{"https", "wss"}
+	}
+	return []string{"http", "https", "ws", "wss"}
+}
+
+// validateURL requires value to be an absolute URL with a host and one of the given schemes.
+func validateURL(value string, schemes []string, path *field.Path) field.ErrorList {
+	if value == "" {
+		return field.ErrorList{field.Required(path, "")}
+	}
+	u, err := url.Parse(value)
+	if err != nil || !slices.Contains(schemes, u.Scheme) || u.Host == "" {
+		return field.ErrorList{field.Invalid(path, value,
+			fmt.Sprintf("must be an absolute URL with scheme %s", strings.Join(schemes, ", ")))}
+	}
+	return nil
+}
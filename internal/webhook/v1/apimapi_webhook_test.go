@@ -0,0 +1,356 @@
+package v1
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	apimv1 "github.com/hedinit/azure-apim-operator/api/v1"
+)
+
+func validAPIMAPI() *apimv1.APIMAPI {
+	return &apimv1.APIMAPI{
+		ObjectMeta: metav1.ObjectMeta{Name: "payment-public", Namespace: "integrations"},
+		Spec: apimv1.APIMAPISpec{
+			APIID:                "payment-api",
+			APIMService:          "my-apim",
+			RoutePrefix:          "/payments/v1",
+			ServiceURL:           "https://payments.internal.example.com",
+			OpenAPIDefinitionURL: "http://payments.integrations.svc/swagger/v1/swagger.json",
+		},
+	}
+}
+
+// newTestValidator returns a validator backed by a fake client that holds objs and the webhook's field indexes.
+func newTestValidator(t *testing.T, objs ...client.Object) *APIMAPICustomValidator {
+	t.Helper()
+	scheme := runtime.NewScheme()
+	if err := apimv1.AddToScheme(scheme); err != nil {
+		t.Fatalf("AddToScheme() error = %v", err)
+	}
+	c := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(objs...).
+		WithIndex(&apimv1.APIMAPI{}, routeKeyField, indexRouteKey).
+		WithIndex(&apimv1.APIMAPI{}, apiIDKeyField, indexAPIIDKey).
+		Build()
+	return &APIMAPICustomValidator{Client: c, APIMAPIWebhookOptions: APIMAPIWebhookOptions{OperatorNamespace: "apim-system"}}
+}
+
+func TestValidateAPIMAPI(t *testing.T) {
+	tests := []struct {
+		name      string
+		mutate    func(*apimv1.APIMAPISpec)
+		wantField string
+	}{
+		{name: "valid", mutate: func(*apimv1.APIMAPISpec) {}},
+		{name: "root route prefix", mutate: func(s *apimv1.APIMAPISpec) { s.RoutePrefix = "/" }},
+		{name: "route prefix without leading slash", mutate: func(s *apimv1.APIMAPISpec) { s.RoutePrefix = "payments" }, wantField: "spec.routePrefix"},
+		{name: "route prefix with space", mutate: func(s *apimv1.APIMAPISpec) { s.RoutePrefix = "/pay ments" }, wantField: "spec.routePrefix"},
+		{name: "route prefix with double slash", mutate: func(s *apimv1.APIMAPISpec) { s.RoutePrefix = "/payments//v1" }, wantField: "spec.routePrefix"},
+		{name: "empty API ID", mutate: func(s *apimv1.APIMAPISpec) { s.APIID = "" }, wantField: "spec.APIID"},
+		{name: "API ID too long", mutate: func(s *apimv1.APIMAPISpec) { s.APIID = strings.Repeat("a", 81) }, wantField: "spec.APIID"},
+		{name: "API ID at max length", mutate: func(s *apimv1.APIMAPISpec) { s.APIID = strings.Repeat("a", 80) }},
+		{name: "API ID with forbidden character", mutate: func(s *apimv1.APIMAPISpec) { s.APIID = "payment:api" }, wantField: "spec.APIID"},
+		{name: "versioned API", mutate: func(s *apimv1.APIMAPISpec) { s.VersionSetID, s.APIVersion = "orders", "2024-01-01" }},
+		{name: "version set ID with forbidden character", mutate: func(s *apimv1.APIMAPISpec) { s.VersionSetID, s.APIVersion = "orders/v", "v2" }, wantField: "spec.versionSetId"},
+		{name: "version without version set", mutate: func(s *apimv1.APIMAPISpec) { s.APIVersion = "v2" }, wantField: "spec.versionSetId"},
+		{name: "version with slash", mutate: func(s *apimv1.APIMAPISpec) { s.VersionSetID, s.APIVersion = "orders", "v2/beta" }, wantField: "spec.apiVersion"},
+		{name: "version set without version", mutate: func(s *apimv1.APIMAPISpec) { s.VersionSetID = "orders" }, wantField: "spec.apiVersion"},
+		{name: "missing APIM service", mutate: func(s *apimv1.APIMAPISpec) { s.APIMService = "" }, wantField: "spec.apimService"},
+		{name: "websocket service URL", mutate: func(s *apimv1.APIMAPISpec) { s.ServiceURL = "wss://chat.internal.example.com" }},
+		{name: "misspelled service URL scheme", mutate: func(s *apimv1.APIMAPISpec) { s.ServiceURL = "htps://payments.internal.example.com" }, wantField: "spec.serviceUrl"},
+		{name: "service URL with space in host", mutate: func(s *apimv1.APIMAPISpec) { s.ServiceURL = "https://payments internal.example.com" }, wantField: "spec.serviceUrl"},
+		{name: "relative service URL", mutate: func(s *apimv1.APIMAPISpec) { s.ServiceURL = "payments.internal" }, wantField: "spec.serviceUrl"},
+		{name: "non-http OpenAPI URL", mutate: func(s *apimv1.APIMAPISpec) { s.OpenAPIDefinitionURL = "ftp://example.com/swagger.json" }, wantField: "spec.openApiDefinitionUrl"},
+		{name: "missing OpenAPI URL", mutate: func(s *apimv1.APIMAPISpec) { s.OpenAPIDefinitionURL = "" }, wantField: "spec.openApiDefinitionUrl"},
+		{name: "blue/green backends", mutate: func(s *apimv1.APIMAPISpec) { setBackends(s, "https://green.example.com", apimv1.BackendGreen) }},
+		{name: "backends with service URL", mutate: func(s *apimv1.APIMAPISpec) {
+			setBackends(s, "https://green.example.com", apimv1.BackendBlue)
+			s.ServiceURL = "https://payments.internal.example.com"
+		}, wantField: "spec.serviceUrl"},
+		{name: "backends without active backend", mutate: func(s *apimv1.APIMAPISpec) { setBackends(s, "https://green.example.com", "") }, wantField: "spec.activeBackend"},
+		{name: "invalid green backend", mutate: func(s *apimv1.APIMAPISpec) { setBackends(s, "green.internal", apimv1.BackendBlue) }, wantField: "spec.backends.green"},
+	}
+
+	validator := newTestValidator(t)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			apimApi := validAPIMAPI()
+			tt.mutate(&apimApi.Spec)
+
+			_, err := validator.ValidateCreate(context.Background(), apimApi)
+			if tt.wantField == "" {
+				if err != nil {
+					t.Fatalf("ValidateCreate() error = %v, want nil", err)
+				}
+				return
+			}
+			if !apierrors.IsInvalid(err) {
+				t.Fatalf("ValidateCreate() error = %v, want Invalid", err)
+			}
+			if !strings.Contains(err.Error(), tt.wantField) {
+				t.Errorf("ValidateCreate() error = %v, want it to mention %s", err, tt.wantField)
+			}
+		})
+	}
+}
+
+// setBackends replaces the service URL of s with blue/green backends.
+func setBackends(s *apimv1.APIMAPISpec, green string, active apimv1.BackendColor) {
+	s.ServiceURL = ""
+	s.Backends = &apimv1.APIMAPIBackends{Blue: "https://blue.example.com", Green: green}
+	s.ActiveBackend = active
+}
+
+func TestValidateUpdateChecksNewObject(t *testing.T) {
+	oldObj := validAPIMAPI()
+	newObj := validAPIMAPI()
+	newObj.Spec.RoutePrefix = "payments"
+
+	if _, err := newTestValidator(t).ValidateUpdate(context.Background(), oldObj, newObj); !apierrors.IsInvalid(err) {
+		t.Fatalf("ValidateUpdate() error = %v, want Invalid", err)
+	}
+}
+
+func TestDefaultAPIMAPI(t *testing.T) {
+	tests := []struct {
+		name            string
+		apimApi         *apimv1.APIMAPI
+		wantRoutePrefix string
+		wantOpenAPIURL  string
+	}{
+		{
+			name: "fills omitted fields",
+			apimApi: &apimv1.APIMAPI{
+				ObjectMeta: metav1.ObjectMeta{Name: "payment-service"},
+				Spec:       apimv1.APIMAPISpec{ServiceURL: "http://payment-service.integrations.svc/"},
+			},
+			wantRoutePrefix: "/payment-service",
+			wantOpenAPIURL:  "http://payment-service.integrations.svc/swagger/v1/swagger.json",
+		},
+		{
+			name:            "keeps explicit values",
+			apimApi:         validAPIMAPI(),
+			wantRoutePrefix: "/payments/v1",
+			wantOpenAPIURL:  "http://payments.integrations.svc/swagger/v1/swagger.json",
+		},
+		{
+			name: "derives OpenAPI URL from active backend",
+			apimApi: &apimv1.APIMAPI{
+				ObjectMeta: metav1.ObjectMeta{Name: "payment-service"},
+				Spec: apimv1.APIMAPISpec{
+					Backends:      &apimv1.APIMAPIBackends{Blue: "http://payment-blue.integrations.svc", Green: "http://payment-green.integrations.svc"},
+					ActiveBackend: apimv1.BackendGreen,
+				},
+			},
+			wantRoutePrefix: "/payment-service",
+			wantOpenAPIURL:  "http://payment-green.integrations.svc/swagger/v1/swagger.json",
+		},
+		{
+			name: "leaves fields empty without name or service URL",
+			apimApi: &apimv1.APIMAPI{
+				ObjectMeta: metav1.ObjectMeta{GenerateName: "payment-"},
+			},
+		},
+	}
+
+	defaulter := &APIMAPICustomDefaulter{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := defaulter.Default(context.Background(), tt.apimApi); err != nil {
+				t.Fatalf("Default() error = %v", err)
+			}
+			if tt.apimApi.Spec.RoutePrefix != tt.wantRoutePrefix {
+				t.Errorf("RoutePrefix = %q, want %q", tt.apimApi.Spec.RoutePrefix, tt.wantRoutePrefix)
+			}
+			if tt.apimApi.Spec.OpenAPIDefinitionURL != tt.wantOpenAPIURL {
+				t.Errorf("OpenAPIDefinitionURL = %q, want %q", tt.apimApi.Spec.OpenAPIDefinitionURL, tt.wantOpenAPIURL)
+			}
+		})
+	}
+}
+
+func TestValidateRoutePrefixUnique(t *testing.T) {
+	existing := validAPIMAPI()
+	existing.Name = "payment-internal"
+	existing.Namespace = "payments"
+	existing.Spec.APIID = "payment-internal-api"
+
+	tests := []struct {
+		name      string
+		mutate    func(*apimv1.APIMAPI)
+		wantError bool
+	}{
+		{name: "same route on same service", mutate: func(*apimv1.APIMAPI) {}, wantError: true},
+		{name: "same route with different case and trailing slash", mutate: func(a *apimv1.APIMAPI) { a.Spec.RoutePrefix = "/Payments/v1/" }, wantError: true},
+		{name: "same route on another service", mutate: func(a *apimv1.APIMAPI) { a.Spec.APIMService = "other-apim" }},
+		{name: "different route", mutate: func(a *apimv1.APIMAPI) { a.Spec.RoutePrefix = "/payments/v2" }},
+		{name: "same route as another version", mutate: func(a *apimv1.APIMAPI) { a.Spec.VersionSetID, a.Spec.APIVersion = "payments", "v2" }},
+		{name: "same object", mutate: func(a *apimv1.APIMAPI) { a.Name, a.Namespace = existing.Name, existing.Namespace }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			apimApi := validAPIMAPI()
+			tt.mutate(apimApi)
+
+			_, err := newTestValidator(t, existing.DeepCopy()).ValidateCreate(context.Background(), apimApi)
+			if tt.wantError != (err != nil) {
+				t.Fatalf("ValidateCreate() error = %v, wantError %t", err, tt.wantError)
+			}
+			if tt.wantError && !strings.Contains(err.Error(), "payments/payment-internal") {
+				t.Errorf("ValidateCreate() error = %v, want it to name the conflicting APIMAPI", err)
+			}
+		})
+	}
+}
+
+func TestValidateUpdateIgnoresUnchangedDuplicateRoute(t *testing.T) {
+	existing := validAPIMAPI()
+	existing.Name = "payment-internal"
+
+	oldObj := validAPIMAPI()
+	newObj := validAPIMAPI()
+	newObj.Spec.TagIDs = []string{"payments"}
+
+	if _, err := newTestValidator(t, existing, oldObj.DeepCopy()).ValidateUpdate(context.Background(), oldObj, newObj); err != nil {
+		t.Fatalf("ValidateUpdate() error = %v, want nil", err)
+	}
+}
+
+func TestValidateAPIIDUnique(t *testing.T) {
+	existing := validAPIMAPI()
+	existing.Name = "payment-team-b"
+	existing.Namespace = "team-b"
+	existing.Spec.RoutePrefix = "/team-b/payments"
+
+	tests := []struct {
+		name      string
+		mutate    func(*apimv1.APIMAPI)
+		wantError bool
+	}{
+		{name: "same API ID from another namespace", mutate: func(*apimv1.APIMAPI) {}, wantError: true},
+		{name: "same API ID with different case", mutate: func(a *apimv1.APIMAPI) { a.Spec.APIID = "Payment-API" }, wantError: true},
+		{name: "same API ID on another service", mutate: func(a *apimv1.APIMAPI) { a.Spec.APIMService = "other-apim" }},
+		{name: "different API ID", mutate: func(a *apimv1.APIMAPI) { a.Spec.APIID = "payment-api-v2" }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			apimApi := validAPIMAPI()
+			tt.mutate(apimApi)
+
+			_, err := newTestValidator(t, existing.DeepCopy()).ValidateCreate(context.Background(), apimApi)
+			if tt.wantError != (err != nil) {
+				t.Fatalf("ValidateCreate() error = %v, wantError %t", err, tt.wantError)
+			}
+			if tt.wantError && !strings.Contains(err.Error(), "spec.APIID") {
+				t.Errorf("ValidateCreate() error = %v, want it to mention spec.APIID", err)
+			}
+		})
+	}
+}
+
+func TestValidateAPIIDNamespacePrefix(t *testing.T) {
+	tests := []struct {
+		name      string
+		require   bool
+		apiID     string
+		wantError bool
+	}{
+		{name: "policy disabled", apiID: "payment-api"},
+		{name: "prefixed", require: true, apiID: "integrations-payment-api"},
+		{name: "missing prefix", require: true, apiID: "payment-api", wantError: true},
+		{name: "namespace without dash", require: true, apiID: "integrationspayment-api", wantError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			apimApi := validAPIMAPI()
+			apimApi.Spec.APIID = tt.apiID
+
+			validator := newTestValidator(t)
+			validator.RequireAPIIDNamespacePrefix = tt.require
+			_, err := validator.ValidateCreate(context.Background(), apimApi)
+			if tt.wantError != (err != nil) {
+				t.Fatalf("ValidateCreate() error = %v, wantError %t", err, tt.wantError)
+			}
+		})
+	}
+}
+
+func TestValidateAPIMServiceReference(t *testing.T) {
+	apimService := &apimv1.APIMService{ObjectMeta: metav1.ObjectMeta{Name: "my-apim", Namespace: "apim-system"}}
+
+	tests := []struct {
+		name         string
+		objs         []client.Object
+		reject       bool
+		oldService   string
+		wantWarnings bool
+		wantError    bool
+	}{
+		{name: "service exists", objs: []client.Object{apimService}},
+		{name: "service missing warns", wantWarnings: true},
+		{name: "service missing rejects", reject: true, wantError: true},
+		{name: "unchanged reference on update", reject: true, oldService: "my-apim"},
+		{name: "changed reference on update", reject: true, oldService: "old-apim", wantError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			validator := newTestValidator(t, tt.objs...)
+			validator.RejectMissingAPIMService = tt.reject
+			apimApi := validAPIMAPI()
+
+			var warnings []string
+			var err error
+			if tt.oldService == "" {
+				warnings, err = validator.ValidateCreate(context.Background(), apimApi)
+			} else {
+				oldApi := validAPIMAPI()
+				oldApi.Spec.APIMService = tt.oldService
+				warnings, err = validator.ValidateUpdate(context.Background(), oldApi, apimApi)
+			}
+			if tt.wantError != (err != nil) {
+				t.Fatalf("error = %v, wantError %t", err, tt.wantError)
+			}
+			if tt.wantWarnings != (len(warnings) > 0) {
+				t.Errorf("warnings = %v, wantWarnings %t", warnings, tt.wantWarnings)
+			}
+		})
+	}
+}
+
+func TestValidateRequireHTTPSServiceURL(t *testing.T) {
+	tests := []struct {
+		serviceURL string
+		wantError  bool
+	}{
+		{serviceURL: "https://payments.internal.example.com"},
+		{serviceURL: "wss://chat.internal.example.com"},
+		{serviceURL: "http://payments.internal.example.com", wantError: true},
+		{serviceURL: "ws://chat.internal.example.com", wantError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.serviceURL, func(t *testing.T) {
+			validator := newTestValidator(t)
+			validator.RequireHTTPSServiceURL = true
+			apimApi := validAPIMAPI()
+			apimApi.Spec.ServiceURL = tt.serviceURL
+
+			_, err := validator.ValidateCreate(context.Background(), apimApi)
+			if tt.wantError != (err != nil) {
+				t.Fatalf("ValidateCreate() error = %v, wantError %t", err, tt.wantError)
+			}
+		})
+	}
+}
@@ -0,0 +1,212 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	apimv1 "github.com/hedinit/azure-apim-operator/api/v1"
+	"github.com/hedinit/azure-apim-operator/internal/policytemplate"
+)
+
+// apiminboundpolicylog is for logging in this package.
+var apiminboundpolicylog = logf.Log.WithName("apiminboundpolicy-resource")
+
+// SetupAPIMInboundPolicyWebhookWithManager registers the webhook for APIMInboundPolicy in the manager.
+// APIMServices are looked up in operatorNamespace; see APIMAPIWebhookOptions.RejectMissingAPIMService.
+func SetupAPIMInboundPolicyWebhookWithManager(mgr ctrl.Manager, operatorNamespace string, rejectMissingAPIMService bool) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(&apimv1.APIMInboundPolicy{}).
+		WithValidator(&APIMInboundPolicyCustomValidator{
+			Client:                   mgr.GetClient(),
+			OperatorNamespace:        operatorNamespace,
+			RejectMissingAPIMService: rejectMissingAPIMService,
+		}).
+		Complete()
+}
+
+// +kubebuilder:webhook:path=/validate-apim-operator-io-v1-apiminboundpolicy,mutating=false,failurePolicy=fail,sideEffects=None,groups=apim.operator.io,resources=apiminboundpolicies,verbs=create;update,versions=v1,name=vapiminboundpolicy-v1.kb.io,admissionReviewVersions=v1
+
+// APIMInboundPolicyCustomValidator keeps policies with an invalid scope or malformed XML out of the cluster,
+// so they fail at apply time instead of as a 400 from APIM during reconcile.
+type APIMInboundPolicyCustomValidator struct {
+	// Client reads APIMServices from the manager cache.
+	Client client.Reader
+	// OperatorNamespace is the namespace where APIMService resources live.
+	OperatorNamespace string
+	// RejectMissingAPIMService rejects policies that reference a missing APIMService instead of only warning.
+	RejectMissingAPIMService bool
+}
+
+var _ webhook.CustomValidator = &APIMInboundPolicyCustomValidator{}
+
+// ValidateCreate implements webhook.CustomValidator.
+func (v *APIMInboundPolicyCustomValidator) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	policy, ok := obj.(*apimv1.APIMInboundPolicy)
+	if !ok {
+		return nil, fmt.Errorf("expected an APIMInboundPolicy object but got %T", obj)
+	}
+	apiminboundpolicylog.Info("Validation for APIMInboundPolicy upon creation", "name", policy.GetName())
+
+	return v.validateAPIMInboundPolicy(ctx, policy, nil)
+}
+
+// ValidateUpdate implements webhook.CustomValidator.
+func (v *APIMInboundPolicyCustomValidator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	policy, ok := newObj.(*apimv1.APIMInboundPolicy)
+	if !ok {
+		return nil, fmt.Errorf("expected an APIMInboundPolicy object for the newObj but got %T", newObj)
+	}
+	oldPolicy, ok := oldObj.(*apimv1.APIMInboundPolicy)
+	if !ok {
+		return nil, fmt.Errorf("expected an APIMInboundPolicy object for the oldObj but got %T", oldObj)
+	}
+	apiminboundpolicylog.Info("Validation for APIMInboundPolicy upon update", "name", policy.GetName())
+
+	return v.validateAPIMInboundPolicy(ctx, policy, oldPolicy)
+}
+
+// ValidateDelete implements webhook.CustomValidator. Deletes are always allowed.
+func (v *APIMInboundPolicyCustomValidator) ValidateDelete(_ context.Context, _ runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+// validateAPIMInboundPolicy checks the policy scope, the policy XML and the APIMService reference. oldPolicy is nil on create.
+func (v *APIMInboundPolicyCustomValidator) validateAPIMInboundPolicy(
+	ctx context.Context,
+	policy, oldPolicy *apimv1.APIMInboundPolicy,
+) (admission.Warnings, error) {
+	spec := &policy.Spec
+	path := field.NewPath("spec")
+	var allErrs field.ErrorList
+
+	if spec.APIID == "" {
+		if spec.OperationID != "" {
+			allErrs = append(allErrs, field.Forbidden(path.Child("operationId"), "operationId requires apiId to be set"))
+		}
+		allErrs = append(allErrs, field.Required(path.Child("apiId"), ""))
+	}
+
+	content, err := renderPolicyTemplate(policy)
+	if err == nil {
+		err = validatePolicyXML(content)
+	}
+	if err != nil {
+		allErrs = append(allErrs, field.Invalid(path.Child("policyContent"), truncate(spec.PolicyContent, 80), err.Error()))
+	}
+
+	var oldService string
+	if oldPolicy != nil {
+		oldService = oldPolicy.Spec.APIMService
+	}
+	warnings, referenceErrs, err := validateAPIMServiceReference(ctx, v.Client, v.OperatorNamespace,
+		spec.APIMService, oldService, v.RejectMissingAPIMService, path.Child("apimService"))
+	if err != nil {
+		return nil, err
+	}
+	allErrs = append(allErrs, referenceErrs...)
+
+	if len(allErrs) == 0 {
+		return warnings, nil
+	}
+	return warnings, apierrors.NewInvalid(apimv1.GroupVersion.WithKind("APIMInboundPolicy").GroupKind(), policy.Name, allErrs)
+}
+
+// validatePolicyXML requires a well-formed XML document whose root <policies> element has an <inbound> section.
+// The operator sends policies in APIM's "xml" format, which APIM also parses strictly.
+func validatePolicyXML(content string) error {
+	decoder := xml.NewDecoder(bytes.NewReader([]byte(content)))
+
+	depth := 0
+	sawRoot, sawInbound := false, false
+	for {
+		token, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("must be well-formed XML: %w", err)
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			if depth == 0 {
+				if sawRoot || t.Name.Local != "policies" {
+					return errors.New("must have a single <policies> root element")
+				}
+				sawRoot = true
+			}
+			if depth == 1 && t.Name.Local == "inbound" {
+				sawInbound = true
+			}
+			depth++
+		case xml.EndElement:
+			depth--
+		}
+	}
+
+	if !sawRoot {
+		return errors.New("must have a single <policies> root element")
+	}
+	if !sawInbound {
+		return errors.New("must contain an <inbound> section")
+	}
+	return nil
+}
+
+// templateValueStandIn replaces placeholders whose value is read from a ConfigMap, Secret or APIMNamedValue
+// when the controller applies the policy.
+const templateValueStandIn = "value"
+
+// renderPolicyTemplate renders the policy template of policy with its inline values, so the XML APIM would
+// receive is validated rather than the template. Values read at apply time are replaced by a stand-in.
+// Policies without template values are returned as written.
+func renderPolicyTemplate(policy *apimv1.APIMInboundPolicy) (string, error) {
+	if len(policy.Spec.TemplateValues) == 0 {
+		return policy.Spec.PolicyContent, nil
+	}
+	values := make(map[string]string, len(policy.Spec.TemplateValues))
+	for _, tv := range policy.Spec.TemplateValues {
+		values[tv.Name] = tv.Value
+		if tv.ConfigMapKeyRef != nil || tv.SecretKeyRef != nil || tv.NamedValue != "" {
+			values[tv.Name] = templateValueStandIn
+		}
+	}
+	return policytemplate.Render(policy.Name, policy.Spec.PolicyContent, values)
+}
+
+// truncate shortens long values such as policy documents for error messages.
+func truncate(value string, maxLength int) string {
+	if len(value) <= maxLength {
+		return value
+	}
+	return value[:maxLength] + "..."
+}
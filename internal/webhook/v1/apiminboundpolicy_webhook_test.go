@@ -0,0 +1,136 @@
+package v1
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	apimv1 "github.com/hedinit/azure-apim-operator/api/v1"
+)
+
+func TestValidatePolicyXML(t *testing.T) {
+	tests := []struct {
+		name      string
+		content   string
+		wantError string
+	}{
+		{name: "valid", content: `<policies><inbound><base /><set-header name="x" exists-action="override"><value>@(context.RequestId)</value></set-header></inbound><backend><base /></backend></policies>`},
+		{name: "with XML declaration and comments", content: "<?xml version=\"1.0\"?>\n<!-- rate limit -->\n<policies>\n  <inbound><base /></inbound>\n</policies>\n"},
+		{name: "malformed", content: `<policies><inbound></policies>`, wantError: "well-formed"},
+		{name: "wrong root", content: `<policy><inbound /></policy>`, wantError: "<policies> root"},
+		{name: "missing inbound", content: `<policies><outbound><base /></outbound></policies>`, wantError: "<inbound>"},
+		{name: "nested inbound only", content: `<policies><backend><inbound /></backend></policies>`, wantError: "<inbound>"},
+		{name: "empty", content: "", wantError: "<policies> root"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validatePolicyXML(tt.content)
+			if tt.wantError == "" {
+				if err != nil {
+					t.Fatalf("validatePolicyXML() error = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantError) {
+				t.Errorf("validatePolicyXML() error = %v, want it to contain %q", err, tt.wantError)
+			}
+		})
+	}
+}
+
+func TestValidateAPIMInboundPolicy(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := apimv1.AddToScheme(scheme); err != nil {
+		t.Fatalf("AddToScheme() error = %v", err)
+	}
+	apimService := &apimv1.APIMService{ObjectMeta: metav1.ObjectMeta{Name: "my-apim", Namespace: "apim-system"}}
+
+	tests := []struct {
+		name         string
+		spec         apimv1.APIMInboundPolicySpec
+		reject       bool
+		wantWarnings bool
+		wantField    string
+	}{
+		{
+			name: "valid operation policy",
+			spec: apimv1.APIMInboundPolicySpec{APIMService: "my-apim", APIID: "payment-api", OperationID: "get-payment",
+				PolicyContent: "<policies><inbound><base /></inbound></policies>"},
+		},
+		{
+			name: "operation without API",
+			spec: apimv1.APIMInboundPolicySpec{APIMService: "my-apim", OperationID: "get-payment",
+				PolicyContent: "<policies><inbound><base /></inbound></policies>"},
+			wantField: "spec.operationId",
+		},
+		{
+			name:      "invalid XML",
+			spec:      apimv1.APIMInboundPolicySpec{APIMService: "my-apim", APIID: "payment-api", PolicyContent: "<policies><inbound>"},
+			wantField: "spec.policyContent",
+		},
+		{
+			name: "template with values",
+			spec: apimv1.APIMInboundPolicySpec{APIMService: "my-apim", APIID: "payment-api",
+				PolicyContent:  `<policies><inbound><set-header name="X-Env" exists-action="override"><value>${{ .env }}</value></set-header><value>${{ .key }}</value></inbound></policies>`,
+				TemplateValues: []apimv1.APIMInboundPolicyTemplateValue{{Name: "env", Value: "prod"}, {Name: "key", NamedValue: "backend-key"}}},
+		},
+		{
+			name: "template placeholder without value",
+			spec: apimv1.APIMInboundPolicySpec{APIMService: "my-apim", APIID: "payment-api",
+				PolicyContent:  "<policies><inbound><value>${{ .region }}</value></inbound></policies>",
+				TemplateValues: []apimv1.APIMInboundPolicyTemplateValue{{Name: "env", Value: "prod"}}},
+			wantField: "spec.policyContent",
+		},
+		{
+			name: "template value breaks XML",
+			spec: apimv1.APIMInboundPolicySpec{APIMService: "my-apim", APIID: "payment-api",
+				PolicyContent:  "<policies><inbound><value>${{ .env }}</value></inbound></policies>",
+				TemplateValues: []apimv1.APIMInboundPolicyTemplateValue{{Name: "env", Value: "<prod"}}},
+			wantField: "spec.policyContent",
+		},
+		{
+			name: "missing APIM service warns",
+			spec: apimv1.APIMInboundPolicySpec{APIMService: "other-apim", APIID: "payment-api",
+				PolicyContent: "<policies><inbound><base /></inbound></policies>"},
+			wantWarnings: true,
+		},
+		{
+			name: "missing APIM service rejects",
+			spec: apimv1.APIMInboundPolicySpec{APIMService: "other-apim", APIID: "payment-api",
+				PolicyContent: "<policies><inbound><base /></inbound></policies>"},
+			reject:    true,
+			wantField: "spec.apimService",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			validator := &APIMInboundPolicyCustomValidator{
+				Client:                   fake.NewClientBuilder().WithScheme(scheme).WithObjects(apimService.DeepCopy()).Build(),
+				OperatorNamespace:        "apim-system",
+				RejectMissingAPIMService: tt.reject,
+			}
+			policy := &apimv1.APIMInboundPolicy{ObjectMeta: metav1.ObjectMeta{Name: "payment-policy"}, Spec: tt.spec}
+
+			warnings, err := validator.ValidateCreate(context.Background(), policy)
+			if tt.wantWarnings != (len(warnings) > 0) {
+				t.Errorf("warnings = %v, wantWarnings %t", warnings, tt.wantWarnings)
+			}
+			if tt.wantField == "" {
+				if err != nil {
+					t.Fatalf("ValidateCreate() error = %v, want nil", err)
+				}
+				return
+			}
+			if !apierrors.IsInvalid(err) || !strings.Contains(err.Error(), tt.wantField) {
+				t.Errorf("ValidateCreate() error = %v, want Invalid mentioning %s", err, tt.wantField)
+			}
+		})
+	}
+}
@@ -0,0 +1,115 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"context"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	apimv1 "github.com/hedinit/azure-apim-operator/api/v1"
+)
+
+// apimproductlog is for logging in this package.
+var apimproductlog = logf.Log.WithName("apimproduct-resource")
+
+// SetupAPIMProductWebhookWithManager registers the webhook for APIMProduct in the manager.
+// operatorNamespace is where the APIMService resources that carry namespace quotas live.
+func SetupAPIMProductWebhookWithManager(mgr ctrl.Manager, operatorNamespace string) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(&apimv1.APIMProduct{}).
+		WithValidator(&APIMProductCustomValidator{Client: mgr.GetClient(), OperatorNamespace: operatorNamespace}).
+		Complete()
+}
+
+// +kubebuilder:webhook:path=/validate-apim-operator-io-v1-apimproduct,mutating=false,failurePolicy=fail,sideEffects=None,groups=apim.operator.io,resources=apimproducts,verbs=create;update,versions=v1,name=vapimproduct-v1.kb.io,admissionReviewVersions=v1
+
+// APIMProductCustomValidator rejects APIMProduct specs with IDs that Azure APIM would refuse
+// and products beyond the namespace quota of their APIMService.
+type APIMProductCustomValidator struct {
+	// Client reads APIMServices and other APIMProducts from the manager cache.
+	Client client.Reader
+	// OperatorNamespace is the namespace where APIMService resources live.
+	OperatorNamespace string
+}
+
+var _ webhook.CustomValidator = &APIMProductCustomValidator{}
+
+// ValidateCreate implements webhook.CustomValidator.
+func (v *APIMProductCustomValidator) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	product, ok := obj.(*apimv1.APIMProduct)
+	if !ok {
+		return nil, fmt.Errorf("expected an APIMProduct object but got %T", obj)
+	}
+	apimproductlog.Info("Validation for APIMProduct upon creation", "name", product.GetName())
+
+	return nil, v.validateAPIMProduct(ctx, product, nil)
+}
+
+// ValidateUpdate implements webhook.CustomValidator.
+func (v *APIMProductCustomValidator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	product, ok := newObj.(*apimv1.APIMProduct)
+	if !ok {
+		return nil, fmt.Errorf("expected an APIMProduct object for the newObj but got %T", newObj)
+	}
+	oldProduct, ok := oldObj.(*apimv1.APIMProduct)
+	if !ok {
+		return nil, fmt.Errorf("expected an APIMProduct object for the oldObj but got %T", oldObj)
+	}
+	apimproductlog.Info("Validation for APIMProduct upon update", "name", product.GetName())
+
+	return nil, v.validateAPIMProduct(ctx, product, oldProduct)
+}
+
+// ValidateDelete implements webhook.CustomValidator. Deletes are always allowed.
+func (v *APIMProductCustomValidator) ValidateDelete(_ context.Context, _ runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+// validateAPIMProduct returns an Invalid error listing every spec field APIM would refuse
+// or that exceeds the namespace quota. oldProduct is nil on create.
+func (v *APIMProductCustomValidator) validateAPIMProduct(ctx context.Context, product, oldProduct *apimv1.APIMProduct) error {
+	spec := &product.Spec
+	path := field.NewPath("spec")
+
+	allErrs := validateAzureName(spec.ProductID, maxProductIDLength, path.Child("productId"))
+	if spec.APIID != "" {
+		allErrs = append(allErrs, validateAzureName(spec.APIID, maxAPIIDLength, path.Child("apiID"))...)
+	}
+	if spec.APIMService == "" {
+		allErrs = append(allErrs, field.Required(path.Child("apimService"), ""))
+	}
+
+	quotaErrs, err := v.validateNamespaceQuota(ctx, product, oldProduct, path.Child("apimService"))
+	if err != nil {
+		return err
+	}
+	allErrs = append(allErrs, quotaErrs...)
+
+	if len(allErrs) == 0 {
+		return nil
+	}
+	return apierrors.NewInvalid(apimv1.GroupVersion.WithKind("APIMProduct").GroupKind(), product.Name, allErrs)
+}
@@ -0,0 +1,44 @@
+package v1
+
+import (
+	"context"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	apimv1 "github.com/hedinit/azure-apim-operator/api/v1"
+)
+
+// validateAPIMServiceReference checks that the APIMService named by the resource exists in the operator namespace.
+// A missing service is only a warning unless reject is set, since GitOps tools often apply the APIMService and
+// the resources that reference it in the same sync. oldName is the previous reference on updates and empty on create;
+// the check only runs when the reference changes, so a deleted APIMService never blocks edits such as finalizer removal.
+func validateAPIMServiceReference(
+	ctx context.Context,
+	c client.Reader,
+	namespace, name, oldName string,
+	reject bool,
+	path *field.Path,
+) (admission.Warnings, field.ErrorList, error) {
+	if name == "" || name == oldName {
+		return nil, nil, nil
+	}
+
+	var apimService apimv1.APIMService
+	err := c.Get(ctx, client.ObjectKey{Name: name, Namespace: namespace}, &apimService)
+	if err == nil {
+		return nil, nil, nil
+	}
+	if !apierrors.IsNotFound(err) {
+		return nil, nil, fmt.Errorf("failed to get APIMService %q: %w", name, err)
+	}
+
+	message := fmt.Sprintf("APIMService %q does not exist in namespace %s", name, namespace)
+	if reject {
+		return nil, field.ErrorList{field.Invalid(path, name, message)}, nil
+	}
+	return admission.Warnings{message + "; nothing will be applied to APIM until it is created"}, nil, nil
+}
@@ -0,0 +1,165 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"context"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	apimv1 "github.com/hedinit/azure-apim-operator/api/v1"
+)
+
+// apimsubscriptionlog is for logging in this package.
+var apimsubscriptionlog = logf.Log.WithName("apimsubscription-resource")
+
+// SetupAPIMSubscriptionWebhookWithManager registers the webhook for APIMSubscription in the manager.
+// APIMServices are looked up in operatorNamespace; see APIMAPIWebhookOptions.RejectMissingAPIMService.
+func SetupAPIMSubscriptionWebhookWithManager(mgr ctrl.Manager, operatorNamespace string, rejectMissingAPIMService bool) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(&apimv1.APIMSubscription{}).
+		WithValidator(&APIMSubscriptionCustomValidator{
+			Client:                   mgr.GetClient(),
+			OperatorNamespace:        operatorNamespace,
+			RejectMissingAPIMService: rejectMissingAPIMService,
+		}).
+		Complete()
+}
+
+// +kubebuilder:webhook:path=/validate-apim-operator-io-v1-apimsubscription,mutating=false,failurePolicy=fail,sideEffects=None,groups=apim.operator.io,resources=apimsubscriptions,verbs=create;update,versions=v1,name=vapimsubscription-v1.kb.io,admissionReviewVersions=v1
+
+// APIMSubscriptionCustomValidator rejects subscriptions without a single scope and Secret or namespace names
+// Kubernetes would refuse, which the controller would otherwise only report when it writes the key Secrets.
+type APIMSubscriptionCustomValidator struct {
+	// Client reads APIMServices from the manager cache.
+	Client client.Reader
+	// OperatorNamespace is the namespace where APIMService resources live.
+	OperatorNamespace string
+	// RejectMissingAPIMService rejects subscriptions that reference a missing APIMService instead of only warning.
+	RejectMissingAPIMService bool
+}
+
+var _ webhook.CustomValidator = &APIMSubscriptionCustomValidator{}
+
+// ValidateCreate implements webhook.CustomValidator.
+func (v *APIMSubscriptionCustomValidator) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	sub, ok := obj.(*apimv1.APIMSubscription)
+	if !ok {
+		return nil, fmt.Errorf("expected an APIMSubscription object but got %T", obj)
+	}
+	apimsubscriptionlog.Info("Validation for APIMSubscription upon creation", "name", sub.GetName())
+
+	return v.validateAPIMSubscription(ctx, sub, nil)
+}
+
+// ValidateUpdate implements webhook.CustomValidator.
+func (v *APIMSubscriptionCustomValidator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	sub, ok := newObj.(*apimv1.APIMSubscription)
+	if !ok {
+		return nil, fmt.Errorf("expected an APIMSubscription object for the newObj but got %T", newObj)
+	}
+	oldSub, ok := oldObj.(*apimv1.APIMSubscription)
+	if !ok {
+		return nil, fmt.Errorf("expected an APIMSubscription object for the oldObj but got %T", oldObj)
+	}
+	apimsubscriptionlog.Info("Validation for APIMSubscription upon update", "name", sub.GetName())
+
+	return v.validateAPIMSubscription(ctx, sub, oldSub)
+}
+
+// ValidateDelete implements webhook.CustomValidator. Deletes are always allowed.
+func (v *APIMSubscriptionCustomValidator) ValidateDelete(_ context.Context, _ runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+// validateAPIMSubscription checks the scope, the Secret and namespace names and the APIMService reference.
+// oldSub is nil on create.
+func (v *APIMSubscriptionCustomValidator) validateAPIMSubscription(
+	ctx context.Context,
+	sub, oldSub *apimv1.APIMSubscription,
+) (admission.Warnings, error) {
+	spec := &sub.Spec
+	path := field.NewPath("spec")
+	allErrs := validateSubscriptionScope(spec, path)
+
+	if spec.APIMService == "" {
+		allErrs = append(allErrs, field.Required(path.Child("apimService"), ""))
+	}
+	if spec.SecretName != "" {
+		for _, msg := range validation.IsDNS1123Subdomain(spec.SecretName) {
+			allErrs = append(allErrs, field.Invalid(path.Child("secretName"), spec.SecretName, msg))
+		}
+	}
+	for i, namespace := range spec.TargetNamespaces {
+		for _, msg := range validation.IsDNS1123Label(namespace) {
+			allErrs = append(allErrs, field.Invalid(path.Child("targetNamespaces").Index(i), namespace, msg))
+		}
+	}
+
+	var oldService string
+	if oldSub != nil {
+		oldService = oldSub.Spec.APIMService
+	}
+	warnings, referenceErrs, err := validateAPIMServiceReference(ctx, v.Client, v.OperatorNamespace,
+		spec.APIMService, oldService, v.RejectMissingAPIMService, path.Child("apimService"))
+	if err != nil {
+		return nil, err
+	}
+	allErrs = append(allErrs, referenceErrs...)
+
+	if len(allErrs) == 0 {
+		return warnings, nil
+	}
+	return warnings, apierrors.NewInvalid(apimv1.GroupVersion.WithKind("APIMSubscription").GroupKind(), sub.Name, allErrs)
+}
+
+// validateSubscriptionScope requires exactly one of apiId, productId and allApis, and APIM names for the IDs.
+func validateSubscriptionScope(spec *apimv1.APIMSubscriptionSpec, path *field.Path) field.ErrorList {
+	const scopeMessage = "exactly one of apiId, productId and allApis must be set"
+	var allErrs field.ErrorList
+	var scopes []*field.Path
+	if spec.APIID != "" {
+		scopes = append(scopes, path.Child("apiId"))
+		allErrs = append(allErrs, validateAzureName(spec.APIID, maxAPIIDLength, path.Child("apiId"))...)
+	}
+	if spec.ProductID != "" {
+		scopes = append(scopes, path.Child("productId"))
+		allErrs = append(allErrs, validateAzureName(spec.ProductID, maxProductIDLength, path.Child("productId"))...)
+	}
+	if spec.AllAPIs {
+		scopes = append(scopes, path.Child("allApis"))
+	}
+
+	switch {
+	case len(scopes) == 0:
+		allErrs = append(allErrs, field.Required(path.Child("apiId"), scopeMessage))
+	case len(scopes) > 1:
+		for _, scope := range scopes[1:] {
+			allErrs = append(allErrs, field.Forbidden(scope, scopeMessage))
+		}
+	}
+	return allErrs
+}
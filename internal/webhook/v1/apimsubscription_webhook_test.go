@@ -0,0 +1,90 @@
+package v1
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	apimv1 "github.com/hedinit/azure-apim-operator/api/v1"
+)
+
+func TestValidateAPIMSubscription(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := apimv1.AddToScheme(scheme); err != nil {
+		t.Fatalf("AddToScheme() error = %v", err)
+	}
+	apimService := &apimv1.APIMService{ObjectMeta: metav1.ObjectMeta{Name: "my-apim", Namespace: "apim-system"}}
+
+	tests := []struct {
+		name         string
+		mutate       func(*apimv1.APIMSubscriptionSpec)
+		reject       bool
+		wantWarnings bool
+		wantField    string
+	}{
+		{name: "API scope", mutate: func(*apimv1.APIMSubscriptionSpec) {}},
+		{name: "product scope", mutate: func(s *apimv1.APIMSubscriptionSpec) { s.APIID, s.ProductID = "", "starter" }},
+		{name: "all APIs scope", mutate: func(s *apimv1.APIMSubscriptionSpec) { s.APIID, s.AllAPIs = "", true }},
+		{name: "no scope", mutate: func(s *apimv1.APIMSubscriptionSpec) { s.APIID = "" }, wantField: "spec.apiId"},
+		{name: "API and product scope", mutate: func(s *apimv1.APIMSubscriptionSpec) { s.ProductID = "starter" }, wantField: "spec.productId"},
+		{name: "API and all APIs scope", mutate: func(s *apimv1.APIMSubscriptionSpec) { s.AllAPIs = true }, wantField: "spec.allApis"},
+		{name: "invalid API ID", mutate: func(s *apimv1.APIMSubscriptionSpec) { s.APIID = "orders:v1" }, wantField: "spec.apiId"},
+		{name: "invalid product ID", mutate: func(s *apimv1.APIMSubscriptionSpec) { s.APIID, s.ProductID = "", "a/b" }, wantField: "spec.productId"},
+		{name: "missing APIM service", mutate: func(s *apimv1.APIMSubscriptionSpec) { s.APIMService = "" }, wantField: "spec.apimService"},
+		{name: "dotted secret name", mutate: func(s *apimv1.APIMSubscriptionSpec) { s.SecretName = "orders.client-key" }},
+		{name: "uppercase secret name", mutate: func(s *apimv1.APIMSubscriptionSpec) { s.SecretName = "Orders-Key" }, wantField: "spec.secretName"},
+		{name: "secret name with underscore", mutate: func(s *apimv1.APIMSubscriptionSpec) { s.SecretName = "orders_key" }, wantField: "spec.secretName"},
+		{name: "target namespaces", mutate: func(s *apimv1.APIMSubscriptionSpec) { s.TargetNamespaces = []string{"checkout-jobs", "billing"} }},
+		{
+			name:      "dotted target namespace",
+			mutate:    func(s *apimv1.APIMSubscriptionSpec) { s.TargetNamespaces = []string{"checkout-jobs", "checkout.jobs"} },
+			wantField: "spec.targetNamespaces[1]",
+		},
+		{
+			name:      "target namespace too long",
+			mutate:    func(s *apimv1.APIMSubscriptionSpec) { s.TargetNamespaces = []string{strings.Repeat("a", 64)} },
+			wantField: "spec.targetNamespaces[0]",
+		},
+		{name: "missing APIM service warns", mutate: func(s *apimv1.APIMSubscriptionSpec) { s.APIMService = "other-apim" }, wantWarnings: true},
+		{
+			name:      "missing APIM service rejects",
+			mutate:    func(s *apimv1.APIMSubscriptionSpec) { s.APIMService = "other-apim" },
+			reject:    true,
+			wantField: "spec.apimService",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			validator := &APIMSubscriptionCustomValidator{
+				Client:                   fake.NewClientBuilder().WithScheme(scheme).WithObjects(apimService.DeepCopy()).Build(),
+				OperatorNamespace:        "apim-system",
+				RejectMissingAPIMService: tt.reject,
+			}
+			sub := &apimv1.APIMSubscription{
+				ObjectMeta: metav1.ObjectMeta{Name: "orders-client", Namespace: "checkout"},
+				Spec:       apimv1.APIMSubscriptionSpec{APIMService: "my-apim", SubscriptionID: "checkout-orders", APIID: "orders-api"},
+			}
+			tt.mutate(&sub.Spec)
+
+			warnings, err := validator.ValidateCreate(context.Background(), sub)
+			if tt.wantWarnings != (len(warnings) > 0) {
+				t.Errorf("warnings = %v, wantWarnings %t", warnings, tt.wantWarnings)
+			}
+			if tt.wantField == "" {
+				if err != nil {
+					t.Fatalf("ValidateCreate() error = %v, want nil", err)
+				}
+				return
+			}
+			if !apierrors.IsInvalid(err) || !strings.Contains(err.Error(), tt.wantField) {
+				t.Errorf("ValidateCreate() error = %v, want Invalid mentioning %s", err, tt.wantField)
+			}
+		})
+	}
+}
@@ -0,0 +1,92 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"context"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	apimv1 "github.com/hedinit/azure-apim-operator/api/v1"
+)
+
+// apimtaglog is for logging in this package.
+var apimtaglog = logf.Log.WithName("apimtag-resource")
+
+// SetupAPIMTagWebhookWithManager registers the webhook for APIMTag in the manager.
+func SetupAPIMTagWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(&apimv1.APIMTag{}).
+		WithValidator(&APIMTagCustomValidator{}).
+		Complete()
+}
+
+// +kubebuilder:webhook:path=/validate-apim-operator-io-v1-apimtag,mutating=false,failurePolicy=fail,sideEffects=None,groups=apim.operator.io,resources=apimtags,verbs=create;update,versions=v1,name=vapimtag-v1.kb.io,admissionReviewVersions=v1
+
+// APIMTagCustomValidator rejects APIMTag specs with IDs that Azure APIM would refuse.
+type APIMTagCustomValidator struct{}
+
+var _ webhook.CustomValidator = &APIMTagCustomValidator{}
+
+// ValidateCreate implements webhook.CustomValidator.
+func (v *APIMTagCustomValidator) ValidateCreate(_ context.Context, obj runtime.Object) (admission.Warnings, error) {
+	tag, ok := obj.(*apimv1.APIMTag)
+	if !ok {
+		return nil, fmt.Errorf("expected an APIMTag object but got %T", obj)
+	}
+	apimtaglog.Info("Validation for APIMTag upon creation", "name", tag.GetName())
+
+	return nil, validateAPIMTag(tag)
+}
+
+// ValidateUpdate implements webhook.CustomValidator.
+func (v *APIMTagCustomValidator) ValidateUpdate(_ context.Context, _, newObj runtime.Object) (admission.Warnings, error) {
+	tag, ok := newObj.(*apimv1.APIMTag)
+	if !ok {
+		return nil, fmt.Errorf("expected an APIMTag object for the newObj but got %T", newObj)
+	}
+	apimtaglog.Info("Validation for APIMTag upon update", "name", tag.GetName())
+
+	return nil, validateAPIMTag(tag)
+}
+
+// ValidateDelete implements webhook.CustomValidator. Deletes are always allowed.
+func (v *APIMTagCustomValidator) ValidateDelete(_ context.Context, _ runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+func validateAPIMTag(tag *apimv1.APIMTag) error {
+	spec := &tag.Spec
+	path := field.NewPath("spec")
+
+	allErrs := validateAzureName(spec.TagID, maxTagIDLength, path.Child("tagId"))
+	if spec.APIMService == "" {
+		allErrs = append(allErrs, field.Required(path.Child("apimService"), ""))
+	}
+
+	if len(allErrs) == 0 {
+		return nil
+	}
+	return apierrors.NewInvalid(apimv1.GroupVersion.WithKind("APIMTag").GroupKind(), tag.Name, allErrs)
+}
@@ -0,0 +1,94 @@
+package v1
+
+import (
+	"context"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apimv1 "github.com/hedinit/azure-apim-operator/api/v1"
+)
+
+// getNamespaceQuota returns the per-namespace quota of the named APIMService in the operator namespace,
+// or nil if the service does not exist or sets no quota. A missing service is reported by
+// validateAPIMServiceReference, so it is not an error here.
+func getNamespaceQuota(ctx context.Context, c client.Reader, operatorNamespace, name string) (*apimv1.APIMServiceNamespaceQuota, error) {
+	var apimService apimv1.APIMService
+	err := c.Get(ctx, client.ObjectKey{Name: name, Namespace: operatorNamespace}, &apimService)
+	if apierrors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get APIMService %q: %w", name, err)
+	}
+	return apimService.Spec.NamespaceQuota, nil
+}
+
+// checkNamespaceQuota rejects admitting one more resource when used resources of the same kind already
+// reach the limit. used must not include the resource being admitted.
+func checkNamespaceQuota(used int, limit *int32, kind, namespace, service string, path *field.Path) field.ErrorList {
+	if limit == nil || used < int(*limit) {
+		return nil
+	}
+	return field.ErrorList{field.Forbidden(path,
+		fmt.Sprintf("namespace %s already has %d %s on APIM service %q, the quota is %d", namespace, used, kind, service, *limit))}
+}
+
+// validateNamespaceQuota enforces the APIMService maxAPIs quota. It only runs on create and when apimService
+// changes, so lowering a quota never blocks edits to APIMAPIs that already exist.
+func (v *APIMAPICustomValidator) validateNamespaceQuota(
+	ctx context.Context,
+	apimApi, oldApi *apimv1.APIMAPI,
+	path *field.Path,
+) (field.ErrorList, error) {
+	service := apimApi.Spec.APIMService
+	if service == "" || (oldApi != nil && oldApi.Spec.APIMService == service) {
+		return nil, nil
+	}
+	quota, err := getNamespaceQuota(ctx, v.Client, v.OperatorNamespace, service)
+	if err != nil || quota == nil || quota.MaxAPIs == nil {
+		return nil, err
+	}
+
+	var apis apimv1.APIMAPIList
+	if err := v.Client.List(ctx, &apis, client.InNamespace(apimApi.Namespace)); err != nil {
+		return nil, fmt.Errorf("failed to list APIMAPIs in namespace %s: %w", apimApi.Namespace, err)
+	}
+	used := 0
+	for i := range apis.Items {
+		if apis.Items[i].Name != apimApi.Name && apis.Items[i].Spec.APIMService == service {
+			used++
+		}
+	}
+	return checkNamespaceQuota(used, quota.MaxAPIs, "APIMAPIs", apimApi.Namespace, service, path), nil
+}
+
+// validateNamespaceQuota enforces the APIMService maxProducts quota, following the same rules as for APIMAPIs.
+func (v *APIMProductCustomValidator) validateNamespaceQuota(
+	ctx context.Context,
+	product, oldProduct *apimv1.APIMProduct,
+	path *field.Path,
+) (field.ErrorList, error) {
+	service := product.Spec.APIMService
+	if service == "" || (oldProduct != nil && oldProduct.Spec.APIMService == service) {
+		return nil, nil
+	}
+	quota, err := getNamespaceQuota(ctx, v.Client, v.OperatorNamespace, service)
+	if err != nil || quota == nil || quota.MaxProducts == nil {
+		return nil, err
+	}
+
+	var products apimv1.APIMProductList
+	if err := v.Client.List(ctx, &products, client.InNamespace(product.Namespace)); err != nil {
+		return nil, fmt.Errorf("failed to list APIMProducts in namespace %s: %w", product.Namespace, err)
+	}
+	used := 0
+	for i := range products.Items {
+		if products.Items[i].Name != product.Name && products.Items[i].Spec.APIMService == service {
+			used++
+		}
+	}
+	return checkNamespaceQuota(used, quota.MaxProducts, "APIMProducts", product.Namespace, service, path), nil
+}
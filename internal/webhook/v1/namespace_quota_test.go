@@ -0,0 +1,142 @@
+package v1
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	apimv1 "github.com/hedinit/azure-apim-operator/api/v1"
+)
+
+// newTestProductValidator returns an APIMProduct validator backed by a fake client that holds objs.
+func newTestProductValidator(t *testing.T, objs ...client.Object) *APIMProductCustomValidator {
+	t.Helper()
+	scheme := runtime.NewScheme()
+	if err := apimv1.AddToScheme(scheme); err != nil {
+		t.Fatalf("AddToScheme() error = %v", err)
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build()
+	return &APIMProductCustomValidator{Client: c, OperatorNamespace: "apim-system"}
+}
+
+func quotaService(quota *apimv1.APIMServiceNamespaceQuota) *apimv1.APIMService {
+	return &apimv1.APIMService{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-apim", Namespace: "apim-system"},
+		Spec:       apimv1.APIMServiceSpec{Name: "my-apim", NamespaceQuota: quota},
+	}
+}
+
+// existingAPIMAPI returns a valid APIMAPI named name in namespace that doesn't collide with validAPIMAPI.
+func existingAPIMAPI(namespace, name, service string) *apimv1.APIMAPI {
+	apimApi := validAPIMAPI()
+	apimApi.Namespace = namespace
+	apimApi.Name = name
+	apimApi.Spec.APIMService = service
+	apimApi.Spec.APIID = name
+	apimApi.Spec.RoutePrefix = "/" + name
+	return apimApi
+}
+
+func TestValidateAPIMAPINamespaceQuota(t *testing.T) {
+	tests := []struct {
+		name    string
+		quota   *apimv1.APIMServiceNamespaceQuota
+		objs    []client.Object
+		wantErr bool
+	}{
+		{
+			name: "no quota",
+			objs: []client.Object{existingAPIMAPI("integrations", "orders", "my-apim")},
+		},
+		{
+			name:  "below quota",
+			quota: &apimv1.APIMServiceNamespaceQuota{MaxAPIs: ptr.To[int32](2)},
+			objs:  []client.Object{existingAPIMAPI("integrations", "orders", "my-apim")},
+		},
+		{
+			name:    "quota reached",
+			quota:   &apimv1.APIMServiceNamespaceQuota{MaxAPIs: ptr.To[int32](1)},
+			objs:    []client.Object{existingAPIMAPI("integrations", "orders", "my-apim")},
+			wantErr: true,
+		},
+		{
+			name:    "zero quota",
+			quota:   &apimv1.APIMServiceNamespaceQuota{MaxAPIs: ptr.To[int32](0)},
+			wantErr: true,
+		},
+		{
+			name:  "only products limited",
+			quota: &apimv1.APIMServiceNamespaceQuota{MaxProducts: ptr.To[int32](0)},
+		},
+		{
+			name:  "other namespaces and services don't count",
+			quota: &apimv1.APIMServiceNamespaceQuota{MaxAPIs: ptr.To[int32](1)},
+			objs: []client.Object{
+				existingAPIMAPI("payments", "orders", "my-apim"),
+				existingAPIMAPI("integrations", "billing", "other-apim"),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			objs := append([]client.Object{quotaService(tt.quota)}, tt.objs...)
+			_, err := newTestValidator(t, objs...).ValidateCreate(context.Background(), validAPIMAPI())
+			if !tt.wantErr {
+				if err != nil {
+					t.Fatalf("ValidateCreate() error = %v, want nil", err)
+				}
+				return
+			}
+			if !apierrors.IsInvalid(err) {
+				t.Fatalf("ValidateCreate() error = %v, want Invalid", err)
+			}
+		})
+	}
+}
+
+func TestValidateAPIMAPINamespaceQuotaIgnoresUnchangedService(t *testing.T) {
+	oldApi := validAPIMAPI()
+	validator := newTestValidator(t,
+		quotaService(&apimv1.APIMServiceNamespaceQuota{MaxAPIs: ptr.To[int32](1)}),
+		existingAPIMAPI("integrations", "orders", "my-apim"),
+		oldApi,
+	)
+
+	newApi := oldApi.DeepCopy()
+	newApi.Spec.ServiceURL = "https://payments-v2.internal.example.com"
+	if _, err := validator.ValidateUpdate(context.Background(), oldApi, newApi); err != nil {
+		t.Fatalf("ValidateUpdate() error = %v, want nil", err)
+	}
+}
+
+func TestValidateAPIMProductNamespaceQuota(t *testing.T) {
+	product := func(name, service string) *apimv1.APIMProduct {
+		return &apimv1.APIMProduct{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "integrations"},
+			Spec:       apimv1.APIMProductSpec{ProductID: name, APIMService: service},
+		}
+	}
+	objs := []client.Object{quotaService(&apimv1.APIMServiceNamespaceQuota{MaxProducts: ptr.To[int32](2)})}
+	for i := range 2 {
+		objs = append(objs, product(fmt.Sprintf("existing-%d", i), "my-apim"))
+	}
+	validator := newTestProductValidator(t, objs...)
+
+	if _, err := validator.ValidateCreate(context.Background(), product("new", "my-apim")); !apierrors.IsInvalid(err) {
+		t.Fatalf("ValidateCreate() error = %v, want Invalid", err)
+	}
+	if _, err := validator.ValidateCreate(context.Background(), product("new", "other-apim")); err != nil {
+		t.Fatalf("ValidateCreate() on another service error = %v, want nil", err)
+	}
+	if _, err := validator.ValidateUpdate(context.Background(), product("new", "other-apim"), product("new", "my-apim")); !apierrors.IsInvalid(err) {
+		t.Fatalf("ValidateUpdate() moving to a full service error = %v, want Invalid", err)
+	}
+}
@@ -0,0 +1,46 @@
+package v1
+
+import (
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+const (
+	// maxAPIIDLength is the longest API name Azure APIM accepts.
+	maxAPIIDLength = 80
+	// maxProductIDLength is the longest product name Azure APIM accepts.
+	maxProductIDLength = 256
+	// maxTagIDLength is the longest tag name Azure APIM accepts.
+	maxTagIDLength = 80
+	// maxVersionSetIDLength is the longest version set name Azure APIM accepts.
+	maxVersionSetIDLength = 80
+	// azureNameForbiddenChars are characters Azure APIM rejects in API, product, tag and version set names,
+	// plus the path and escape characters that would break the ARM resource URL.
+	azureNameForbiddenChars = "*#&+:<>?/\\%"
+)
+
+// validateAzureName checks an APIM entity name against Azure's length and character rules,
+// which APIM otherwise only reports as a 400 during reconcile.
+func validateAzureName(value string, maxLength int, path *field.Path) field.ErrorList {
+	switch {
+	case value == "":
+		return field.ErrorList{field.Required(path, "")}
+	case len(value) > maxLength:
+		return field.ErrorList{field.TooLong(path, value, maxLength)}
+	case strings.ContainsAny(value, azureNameForbiddenChars) || strings.TrimSpace(value) != value:
+		return field.ErrorList{field.Invalid(path, value,
+			fmt.Sprintf("must not contain any of %q or leading/trailing whitespace", azureNameForbiddenChars))}
+	}
+	return nil
+}
+
+// validateAzureNames applies validateAzureName to every entry of a list of IDs.
+func validateAzureNames(values []string, maxLength int, path *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	for i, value := range values {
+		allErrs = append(allErrs, validateAzureName(value, maxLength, path.Index(i))...)
+	}
+	return allErrs
+}
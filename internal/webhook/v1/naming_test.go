@@ -0,0 +1,107 @@
+package v1
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+
+	apimv1 "github.com/hedinit/azure-apim-operator/api/v1"
+)
+
+func TestValidateAzureName(t *testing.T) {
+	tests := []struct {
+		name      string
+		value     string
+		maxLength int
+		wantError bool
+	}{
+		{name: "valid", value: "integrations-product", maxLength: maxProductIDLength},
+		{name: "at max length", value: strings.Repeat("t", maxTagIDLength), maxLength: maxTagIDLength},
+		{name: "empty", value: "", maxLength: maxTagIDLength, wantError: true},
+		{name: "too long", value: strings.Repeat("t", maxTagIDLength+1), maxLength: maxTagIDLength, wantError: true},
+		{name: "forbidden character", value: "team#a", maxLength: maxTagIDLength, wantError: true},
+		{name: "slash", value: "team/a", maxLength: maxTagIDLength, wantError: true},
+		{name: "trailing whitespace", value: "team-a ", maxLength: maxTagIDLength, wantError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := validateAzureName(tt.value, tt.maxLength, field.NewPath("spec", "tagId"))
+			if tt.wantError != (len(errs) > 0) {
+				t.Errorf("validateAzureName(%q) = %v, wantError %t", tt.value, errs, tt.wantError)
+			}
+		})
+	}
+}
+
+func TestValidateIDWebhooks(t *testing.T) {
+	tests := []struct {
+		name      string
+		validator webhook.CustomValidator
+		obj       runtime.Object
+		wantField string
+	}{
+		{
+			name:      "valid product",
+			validator: newTestProductValidator(t),
+			obj:       &apimv1.APIMProduct{Spec: apimv1.APIMProductSpec{ProductID: "integrations", APIMService: "my-apim"}},
+		},
+		{
+			name:      "product with invalid ID",
+			validator: newTestProductValidator(t),
+			obj:       &apimv1.APIMProduct{Spec: apimv1.APIMProductSpec{ProductID: "integrations:v1", APIMService: "my-apim"}},
+			wantField: "spec.productId",
+		},
+		{
+			name:      "product with invalid API ID",
+			validator: newTestProductValidator(t),
+			obj:       &apimv1.APIMProduct{Spec: apimv1.APIMProductSpec{ProductID: "integrations", APIMService: "my-apim", APIID: "a?b"}},
+			wantField: "spec.apiID",
+		},
+		{
+			name:      "valid tag",
+			validator: &APIMTagCustomValidator{},
+			obj:       &apimv1.APIMTag{Spec: apimv1.APIMTagSpec{TagID: "payments", APIMService: "my-apim"}},
+		},
+		{
+			name:      "tag without APIM service",
+			validator: &APIMTagCustomValidator{},
+			obj:       &apimv1.APIMTag{Spec: apimv1.APIMTagSpec{TagID: "payments"}},
+			wantField: "spec.apimService",
+		},
+		{
+			name:      "APIMAPI with invalid tag ID",
+			validator: newTestValidator(t),
+			obj: func() runtime.Object {
+				apimApi := validAPIMAPI()
+				apimApi.Spec.TagIDs = []string{"payments", "team&a"}
+				return apimApi
+			}(),
+			wantField: "spec.tagIds[1]",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if o, ok := tt.obj.(metav1.Object); ok && o.GetName() == "" {
+				o.SetName("test")
+			}
+			_, err := tt.validator.ValidateCreate(context.Background(), tt.obj)
+			if tt.wantField == "" {
+				if err != nil {
+					t.Fatalf("ValidateCreate() error = %v, want nil", err)
+				}
+				return
+			}
+			if !apierrors.IsInvalid(err) || !strings.Contains(err.Error(), tt.wantField) {
+				t.Errorf("ValidateCreate() error = %v, want Invalid mentioning %s", err, tt.wantField)
+			}
+		})
+	}
+}
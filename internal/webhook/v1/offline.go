@@ -0,0 +1,83 @@
+package v1
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	apimv1 "github.com/hedinit/azure-apim-operator/api/v1"
+)
+
+// ManifestResult is the admission outcome of one resource checked by ValidateManifests.
+type ManifestResult struct {
+	// Object is the resource after defaulting.
+	Object client.Object
+	// Warnings are the admission warnings the webhooks would return.
+	Warnings admission.Warnings
+	// Err is the error the webhooks would reject the resource with, or nil if it would be admitted.
+	Err error
+}
+
+// ValidateManifests runs the defaulting and validating webhooks against a set of resources without a cluster,
+// as if they were created together in a cluster that holds nothing else. Uniqueness, quota and APIMService
+// reference checks therefore only see the given resources. Kinds without a webhook are returned without
+// warnings or errors. scheme must have the apim v1 types registered.
+func ValidateManifests(
+	ctx context.Context,
+	scheme *runtime.Scheme,
+	objects []client.Object,
+	opts APIMAPIWebhookOptions,
+) ([]ManifestResult, error) {
+	defaulter := &APIMAPICustomDefaulter{}
+	defaulted := make([]client.Object, 0, len(objects))
+	for _, obj := range objects {
+		obj = obj.DeepCopyObject().(client.Object)
+		if apimApi, ok := obj.(*apimv1.APIMAPI); ok {
+			if err := defaulter.Default(ctx, apimApi); err != nil {
+				return nil, err
+			}
+		}
+		defaulted = append(defaulted, obj)
+	}
+
+	c := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(defaulted...).
+		WithIndex(&apimv1.APIMAPI{}, routeKeyField, indexRouteKey).
+		WithIndex(&apimv1.APIMAPI{}, apiIDKeyField, indexAPIIDKey).
+		Build()
+
+	validators := map[string]webhook.CustomValidator{
+		"APIMAPI":     &APIMAPICustomValidator{Client: c, APIMAPIWebhookOptions: opts},
+		"APIMProduct": &APIMProductCustomValidator{Client: c, OperatorNamespace: opts.OperatorNamespace},
+		"APIMTag":     &APIMTagCustomValidator{},
+		"APIMInboundPolicy": &APIMInboundPolicyCustomValidator{
+			Client:                   c,
+			OperatorNamespace:        opts.OperatorNamespace,
+			RejectMissingAPIMService: opts.RejectMissingAPIMService,
+		},
+		"APIMSubscription": &APIMSubscriptionCustomValidator{
+			Client:                   c,
+			OperatorNamespace:        opts.OperatorNamespace,
+			RejectMissingAPIMService: opts.RejectMissingAPIMService,
+		},
+	}
+
+	results := make([]ManifestResult, 0, len(defaulted))
+	for _, obj := range defaulted {
+		result := ManifestResult{Object: obj}
+		gvks, _, err := scheme.ObjectKinds(obj)
+		if err != nil {
+			return nil, err
+		}
+		if validator, ok := validators[gvks[0].Kind]; ok {
+			result.Warnings, result.Err = validator.ValidateCreate(ctx, obj)
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
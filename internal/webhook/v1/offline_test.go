@@ -0,0 +1,58 @@
+package v1
+
+import (
+	"context"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apimv1 "github.com/hedinit/azure-apim-operator/api/v1"
+)
+
+func TestValidateManifests(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := apimv1.AddToScheme(scheme); err != nil {
+		t.Fatalf("AddToScheme() error = %v", err)
+	}
+
+	service := &apimv1.APIMService{ObjectMeta: metav1.ObjectMeta{Name: "my-apim", Namespace: "apim-system"}}
+	api := validAPIMAPI()
+	api.Spec.OpenAPIDefinitionURL = ""
+	duplicate := validAPIMAPI()
+	duplicate.Name = "payment-copy"
+	duplicate.Spec.RoutePrefix = "/payments/v2"
+	policy := &apimv1.APIMInboundPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "payment", Namespace: "integrations"},
+		Spec:       apimv1.APIMInboundPolicySpec{APIMService: "other-apim", APIID: "payment-api", PolicyContent: "<policies><inbound/></policies>"},
+	}
+
+	results, err := ValidateManifests(context.Background(), scheme,
+		[]client.Object{service, api, duplicate, policy}, APIMAPIWebhookOptions{OperatorNamespace: "apim-system"})
+	if err != nil {
+		t.Fatalf("ValidateManifests() error = %v", err)
+	}
+	if len(results) != 4 {
+		t.Fatalf("results = %d, want 4", len(results))
+	}
+
+	if results[0].Err != nil || len(results[0].Warnings) != 0 {
+		t.Errorf("APIMService result = %+v, want no findings", results[0])
+	}
+	if got := results[1].Object.(*apimv1.APIMAPI).Spec.OpenAPIDefinitionURL; got != "https://payments.internal.example.com/swagger/v1/swagger.json" {
+		t.Errorf("defaulted openApiDefinitionUrl = %q", got)
+	}
+	if api.Spec.OpenAPIDefinitionURL != "" {
+		t.Error("ValidateManifests modified its input")
+	}
+	// Both APIMAPIs claim the API ID, so each sees the other as a conflict.
+	for _, i := range []int{1, 2} {
+		if results[i].Err == nil {
+			t.Errorf("APIMAPI %s admitted, want an API ID conflict", results[i].Object.GetName())
+		}
+	}
+	if results[3].Err != nil || len(results[3].Warnings) != 1 {
+		t.Errorf("APIMInboundPolicy result = %+v, want one missing APIMService warning", results[3])
+	}
+}
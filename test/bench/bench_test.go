@@ -0,0 +1,430 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package bench measures how fast the operator converges many APIMAPIs. It runs the APIMAPI, ReplicaSetWatcher
+// and APIMAPIDeployment controllers in a manager against envtest and the fake APIM of the apimtest package,
+// so the numbers reflect the operator and the API server, not Azure. Run it with `make bench`.
+package bench
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/config"
+	"sigs.k8s.io/controller-runtime/pkg/envtest"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
+
+	apimv1 "github.com/hedinit/azure-apim-operator/api/v1"
+	"github.com/hedinit/azure-apim-operator/internal/apimtest"
+	"github.com/hedinit/azure-apim-operator/internal/controller"
+	"github.com/hedinit/azure-apim-operator/internal/identity"
+)
+
+// Environment Variables:
+// - BENCH_APIS: the number of APIMAPIs created per iteration. Defaults to 200.
+// - BENCH_CONCURRENCY: MaxConcurrentReconciles of every controller. Defaults to controller-runtime's 1.
+// - BENCH_TIMEOUT: how long an iteration may take to converge. Defaults to 10m.
+const (
+	defaultAPIs    = 200
+	defaultTimeout = 10 * time.Minute
+	// operatorNamespace holds the APIMService, like the operator's namespace does in a cluster.
+	operatorNamespace = "default"
+	apimServiceName   = "bench-apim"
+	// sampleInterval is how often queue depth, heap size and readiness are sampled while converging.
+	sampleInterval = 100 * time.Millisecond
+)
+
+const openAPIDocument = `{"openapi":"3.0.1","info":{"title":"bench","version":"1"},` +
+	`"paths":{"/items":{"get":{"responses":{"200":{"description":"OK"}}}}}}`
+
+// BenchmarkConverge creates BENCH_APIS APIMAPIs, each with a ready ReplicaSet, in a new namespace per
+// iteration and waits until all of them are Ready. Besides ns/op it reports:
+// - s/converge: seconds from the first create until every APIMAPI is Ready.
+// - apis/s: APIMAPIs converged per second.
+// - max-queue-depth: the largest sum of the work queue depths of the controllers.
+// - peak-heap-MB: the largest heap in use, in MiB.
+// - azure-requests/api: management API requests the fake received per APIMAPI.
+func BenchmarkConverge(b *testing.B) {
+	apis := envInt(b, "BENCH_APIS", defaultAPIs)
+	timeout := defaultTimeout
+	if value := os.Getenv("BENCH_TIMEOUT"); value != "" {
+		var err error
+		if timeout, err = time.ParseDuration(value); err != nil {
+			b.Fatalf("BENCH_TIMEOUT: %v", err)
+		}
+	}
+
+	ctx, k8sClient, fakeAPIM := startOperator(b, envInt(b, "BENCH_CONCURRENCY", 0))
+	definitionServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = io.WriteString(w, openAPIDocument)
+	}))
+	b.Cleanup(definitionServer.Close)
+
+	var totalConverge time.Duration
+	var maxDepth float64
+	var peakHeap uint64
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		namespace := fmt.Sprintf("bench-%d", i)
+		requestsBefore := len(fakeAPIM.Requests())
+
+		start := time.Now()
+		sampler := startSampler()
+		createWorkloads(ctx, b, k8sClient, namespace, apis, definitionServer.URL)
+		waitForReady(ctx, b, k8sClient, namespace, apis, timeout)
+		converge := time.Since(start)
+		depth, heap := sampler.stop()
+
+		totalConverge += converge
+		maxDepth = max(maxDepth, depth)
+		peakHeap = max(peakHeap, heap)
+		b.Logf("iteration %d: %d APIMAPIs converged in %s, %d management API requests",
+			i, apis, converge.Round(time.Millisecond), len(fakeAPIM.Requests())-requestsBefore)
+	}
+	b.StopTimer()
+
+	seconds := totalConverge.Seconds() / float64(b.N)
+	b.ReportMetric(seconds, "s/converge")
+	b.ReportMetric(float64(apis)/seconds, "apis/s")
+	b.ReportMetric(maxDepth, "max-queue-depth")
+	b.ReportMetric(float64(peakHeap)/(1<<20), "peak-heap-MB")
+	b.ReportMetric(float64(len(fakeAPIM.Requests()))/float64(apis*b.N), "azure-requests/api")
+}
+
+// startOperator starts envtest, the fake APIM and a manager with the controllers that import APIs, and
+// returns a context that ends with the benchmark and a client of the API server.
+func startOperator(b *testing.B, concurrency int) (context.Context, client.Client, *apimtest.Server) {
+	b.Helper()
+	logf.SetLogger(zap.New(zap.WriteTo(io.Discard)))
+
+	testEnv := &envtest.Environment{
+		CRDDirectoryPaths:     []string{filepath.Join("..", "..", "config", "crd", "bases")},
+		ErrorIfCRDPathMissing: true,
+		BinaryAssetsDirectory: firstFoundEnvTestBinaryDir(),
+	}
+	cfg, err := testEnv.Start()
+	if err != nil {
+		b.Fatalf("starting envtest: %v", err)
+	}
+	b.Cleanup(func() { _ = testEnv.Stop() })
+	// The client of the benchmark itself must not be the bottleneck.
+	cfg.QPS, cfg.Burst = 1000, 2000
+
+	scheme := clientgoscheme.Scheme
+	if err := apimv1.AddToScheme(scheme); err != nil {
+		b.Fatal(err)
+	}
+
+	fakeAPIM := apimtest.Start(b)
+	identity.SetStaticToken("bench-token")
+	b.Cleanup(func() { identity.SetStaticToken("") })
+	b.Setenv("AZURE_CLIENT_ID", "bench")
+	b.Setenv("AZURE_TENANT_ID", "bench")
+	b.Setenv("OPERATOR_NAMESPACE", operatorNamespace)
+
+	// The testing package calls a benchmark more than once unless -benchtime is 1x, which registers the
+	// controllers again in a new manager.
+	controllerConfig := config.Controller{SkipNameValidation: ptr.To(true)}
+	if concurrency > 0 {
+		controllerConfig.MaxConcurrentReconciles = concurrency
+	}
+	mgr, err := ctrl.NewManager(cfg, ctrl.Options{
+		Scheme:     scheme,
+		Metrics:    metricsserver.Options{BindAddress: "0"},
+		Controller: controllerConfig,
+		Cache:      controller.CacheOptions(),
+	})
+	if err != nil {
+		b.Fatalf("creating manager: %v", err)
+	}
+	if err := (&controller.APIMAPIReconciler{
+		Client: mgr.GetClient(),
+		Scheme: mgr.GetScheme(),
+		APIM:   fakeAPIM.Client(),
+	}).SetupWithManager(mgr); err != nil {
+		b.Fatal(err)
+	}
+	if err := (&controller.ReplicaSetWatcherReconciler{
+		Client: mgr.GetClient(),
+		Scheme: mgr.GetScheme(),
+	}).SetupWithManager(mgr); err != nil {
+		b.Fatal(err)
+	}
+	if err := (&controller.APIMAPIDeploymentReconciler{
+		Client:    mgr.GetClient(),
+		APIReader: mgr.GetAPIReader(),
+		Scheme:    mgr.GetScheme(),
+		APIM:      fakeAPIM.Client(),
+	}).SetupWithManager(mgr); err != nil {
+		b.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		if err := mgr.Start(ctx); err != nil {
+			b.Errorf("manager: %v", err)
+		}
+	}()
+	b.Cleanup(func() {
+		cancel()
+		<-done
+	})
+
+	k8sClient, err := client.New(cfg, client.Options{Scheme: scheme})
+	if err != nil {
+		b.Fatal(err)
+	}
+	service := &apimv1.APIMService{
+		ObjectMeta: metav1.ObjectMeta{Name: apimServiceName, Namespace: operatorNamespace},
+		Spec: apimv1.APIMServiceSpec{
+			Name:          apimServiceName,
+			ResourceGroup: "bench-rg",
+			Subscription:  "bench-subscription",
+		},
+	}
+	if err := k8sClient.Create(ctx, service); err != nil {
+		b.Fatal(err)
+	}
+	return ctx, k8sClient, fakeAPIM
+}
+
+// createWorkloads creates n APIMAPIs in namespace, each with a ReplicaSet that has a ready Pod, the way a
+// Deployment rollout would leave them.
+func createWorkloads(
+	ctx context.Context, b *testing.B, c client.Client, namespace string, n int, definitionURL string,
+) {
+	b.Helper()
+	if err := c.Create(ctx, &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: namespace}}); err != nil {
+		b.Fatal(err)
+	}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, n)
+	// A few workers create the objects concurrently, like many teams deploying at once.
+	work := make(chan int)
+	for w := 0; w < 16; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range work {
+				if err := createWorkload(ctx, c, namespace, fmt.Sprintf("api-%04d", i), definitionURL); err != nil {
+					errs <- err
+				}
+			}
+		}()
+	}
+	for i := 0; i < n; i++ {
+		work <- i
+	}
+	close(work)
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		b.Fatal(err)
+	}
+}
+
+func createWorkload(ctx context.Context, c client.Client, namespace, name, definitionURL string) error {
+	api := &apimv1.APIMAPI{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+		Spec: apimv1.APIMAPISpec{
+			APIID:                namespace + "-" + name,
+			APIMService:          apimServiceName,
+			RoutePrefix:          "/" + namespace + "/" + name,
+			ServiceURL:           "http://" + name + "." + namespace + ".svc",
+			OpenAPIDefinitionURL: definitionURL,
+		},
+	}
+	if err := c.Create(ctx, api); err != nil {
+		return fmt.Errorf("creating APIMAPI %s: %w", name, err)
+	}
+
+	labels := map[string]string{"app.kubernetes.io/name": name}
+	rs := &appsv1.ReplicaSet{
+		ObjectMeta: metav1.ObjectMeta{Name: name + "-1", Namespace: namespace, Labels: labels},
+		Spec: appsv1.ReplicaSetSpec{
+			Replicas: ptr.To[int32](1),
+			Selector: &metav1.LabelSelector{MatchLabels: labels},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: labels},
+				Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "app", Image: "app:1"}}},
+			},
+		},
+	}
+	if err := c.Create(ctx, rs); err != nil {
+		return fmt.Errorf("creating ReplicaSet %s: %w", rs.Name, err)
+	}
+	owner := metav1.NewControllerRef(rs, appsv1.SchemeGroupVersion.WithKind("ReplicaSet"))
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            name + "-1-pod",
+			Namespace:       namespace,
+			Labels:          labels,
+			OwnerReferences: []metav1.OwnerReference{*owner},
+		},
+		Spec: rs.Spec.Template.Spec,
+	}
+	if err := c.Create(ctx, pod); err != nil {
+		return fmt.Errorf("creating Pod %s: %w", pod.Name, err)
+	}
+	pod.Status.Phase = corev1.PodRunning
+	pod.Status.Conditions = []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}}
+	if err := c.Status().Update(ctx, pod); err != nil {
+		return fmt.Errorf("marking Pod %s ready: %w", pod.Name, err)
+	}
+	// envtest runs no ReplicaSet controller, so the ready replica is reported by hand.
+	rs.Status = appsv1.ReplicaSetStatus{Replicas: 1, ReadyReplicas: 1, AvailableReplicas: 1}
+	if err := c.Status().Update(ctx, rs); err != nil {
+		return fmt.Errorf("marking ReplicaSet %s ready: %w", rs.Name, err)
+	}
+	return nil
+}
+
+// waitForReady waits until n APIMAPIs in namespace have a Ready condition that is True.
+func waitForReady(ctx context.Context, b *testing.B, c client.Client, namespace string, n int, timeout time.Duration) {
+	b.Helper()
+	deadline := time.Now().Add(timeout)
+	for {
+		var list apimv1.APIMAPIList
+		if err := c.List(ctx, &list, client.InNamespace(namespace)); err != nil {
+			b.Fatal(err)
+		}
+		ready := 0
+		for i := range list.Items {
+			if apimeta.IsStatusConditionTrue(list.Items[i].Status.Conditions, "Ready") {
+				ready++
+			}
+		}
+		if ready == n {
+			return
+		}
+		if time.Now().After(deadline) {
+			b.Fatalf("%d of %d APIMAPIs in %s are Ready after %s", ready, n, namespace, timeout)
+		}
+		time.Sleep(sampleInterval)
+	}
+}
+
+// sampler records the largest total work queue depth and heap in use until it is stopped.
+type sampler struct {
+	stopCh chan struct{}
+	done   chan struct{}
+	depth  float64
+	heap   uint64
+}
+
+func startSampler() *sampler {
+	s := &sampler{stopCh: make(chan struct{}), done: make(chan struct{})}
+	go func() {
+		defer close(s.done)
+		ticker := time.NewTicker(sampleInterval)
+		defer ticker.Stop()
+		for {
+			s.sample()
+			select {
+			case <-s.stopCh:
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+	return s
+}
+
+func (s *sampler) sample() {
+	s.depth = max(s.depth, queueDepth())
+	var mem runtime.MemStats
+	runtime.ReadMemStats(&mem)
+	s.heap = max(s.heap, mem.HeapInuse)
+}
+
+// stop ends sampling and returns the largest queue depth and heap in use.
+func (s *sampler) stop() (float64, uint64) {
+	close(s.stopCh)
+	<-s.done
+	return s.depth, s.heap
+}
+
+// queueDepth returns the sum of the depths of the controllers' work queues, from the workqueue_depth
+// gauge controller-runtime registers.
+func queueDepth() float64 {
+	families, err := metrics.Registry.Gather()
+	if err != nil {
+		return 0
+	}
+	var depth float64
+	for _, family := range families {
+		if family.GetName() != "workqueue_depth" {
+			continue
+		}
+		for _, metric := range family.GetMetric() {
+			depth += metric.GetGauge().GetValue()
+		}
+	}
+	return depth
+}
+
+func envInt(b *testing.B, name string, fallback int) int {
+	value := os.Getenv(name)
+	if value == "" {
+		return fallback
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 0 {
+		b.Fatalf("%s=%q is not a non-negative number", name, value)
+	}
+	return n
+}
+
+// firstFoundEnvTestBinaryDir returns the first directory of envtest binaries installed by
+// `make setup-envtest`, for running the benchmark without KUBEBUILDER_ASSETS, e.g. from an IDE.
+func firstFoundEnvTestBinaryDir() string {
+	basePath := filepath.Join("..", "..", "bin", "k8s")
+	entries, err := os.ReadDir(basePath)
+	if err != nil {
+		return ""
+	}
+	for _, entry := range entries {
+		if entry.IsDir() {
+			return filepath.Join(basePath, entry.Name())
+		}
+	}
+	return ""
+}
@@ -0,0 +1,355 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package e2e
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/hedinit/azure-apim-operator/test/utils"
+)
+
+// azureLabel labels the specs that run against a live APIM instance. `make test-e2e` leaves them out and
+// `make test-e2e-azure` runs only them.
+const azureLabel = "azure"
+
+// azureCredentialsSecret holds the management token the operator sends to Azure in the live suite.
+const azureCredentialsSecret = "azure-e2e-credentials"
+
+// azureManagementURL is the public Azure Management API, which the live suite checks the results against.
+const azureManagementURL = "https://management.azure.com"
+
+// Environment Variables of the live suite, which is skipped unless the first three are set:
+// - AZURE_SUBSCRIPTION_ID, AZURE_RESOURCE_GROUP, AZURE_APIM_SERVICE: the disposable APIM instance to import into.
+// - Credentials for azidentity.DefaultAzureCredential, e.g. AZURE_CLIENT_ID, AZURE_TENANT_ID and
+// AZURE_CLIENT_SECRET, or an Azure CLI login. The identity needs the API Management Service Contributor role.
+var (
+	azureSubscriptionID = os.Getenv("AZURE_SUBSCRIPTION_ID")
+	azureResourceGroup  = os.Getenv("AZURE_RESOURCE_GROUP")
+	azureAPIMService    = os.Getenv("AZURE_APIM_SERVICE")
+)
+
+var _ = Describe("Live Azure APIM", Ordered, Label(azureLabel), func() {
+	// Every run uses its own IDs, so runs against the same instance don't collide and leftovers are recognizable.
+	suffix := fmt.Sprintf("%d", time.Now().Unix())
+	apiID := "e2e-api-" + suffix
+	productID := "e2e-product-" + suffix
+	tagID := "e2e-tag-" + suffix
+	routePrefix := "/e2e-" + suffix
+	const apimServiceName = "e2e-live-apim-service"
+
+	var managementToken string
+	servicePath := fmt.Sprintf("/subscriptions/%s/resourceGroups/%s/providers/Microsoft.ApiManagement/service/%s",
+		azureSubscriptionID, azureResourceGroup, azureAPIMService)
+
+	// Before running the tests, get a token for the Azure Management API, deploy the controller with it
+	// and deploy the workload whose API is imported.
+	BeforeAll(func() {
+		if azureSubscriptionID == "" || azureResourceGroup == "" || azureAPIMService == "" {
+			Skip("AZURE_SUBSCRIPTION_ID, AZURE_RESOURCE_GROUP and AZURE_APIM_SERVICE must be set to run against Azure")
+		}
+
+		By("getting an Azure Management API token")
+		cred, err := azidentity.NewDefaultAzureCredential(nil)
+		Expect(err).NotTo(HaveOccurred(), "Failed to create an Azure credential")
+		token, err := cred.GetToken(context.Background(), policy.TokenRequestOptions{
+			Scopes: []string{azureManagementURL + "/.default"},
+		})
+		Expect(err).NotTo(HaveOccurred(), "Failed to get an Azure Management API token")
+		managementToken = token.Token
+
+		By("creating manager namespace")
+		cmd := exec.Command("kubectl", "create", "ns", namespace)
+		_, err = utils.Run(cmd)
+		Expect(err).NotTo(HaveOccurred(), "Failed to create namespace")
+
+		By("installing CRDs")
+		cmd = exec.Command("make", "install")
+		_, err = utils.Run(cmd)
+		Expect(err).NotTo(HaveOccurred(), "Failed to install CRDs")
+
+		By("deploying the controller-manager")
+		cmd = exec.Command("make", "deploy", fmt.Sprintf("IMG=%s", projectImage))
+		_, err = utils.Run(cmd)
+		Expect(err).NotTo(HaveOccurred(), "Failed to deploy the controller-manager")
+
+		By("deploying the workload that serves the OpenAPI definition")
+		// The fake APIM server serves a sample definition; here it is only the workload, not the management API.
+		cmd = exec.Command("kubectl", "create", "ns", fakeAPIMNamespace)
+		_, err = utils.Run(cmd)
+		Expect(err).NotTo(HaveOccurred(), "Failed to create the workload namespace")
+		cmd = exec.Command("kubectl", "apply", "-f", "-")
+		cmd.Stdin = strings.NewReader(fakeAPIMManifest)
+		_, err = utils.Run(cmd)
+		Expect(err).NotTo(HaveOccurred(), "Failed to deploy the workload")
+		cmd = exec.Command("kubectl", "rollout", "status", "deployment/"+fakeAPIMName,
+			"-n", fakeAPIMNamespace, "--timeout=2m")
+		_, err = utils.Run(cmd)
+		Expect(err).NotTo(HaveOccurred(), "Workload did not become ready")
+
+		By("handing the token to the controller-manager")
+		// Kind has no workload identity, so the token is sent as a static token to the public endpoint.
+		cmd = exec.Command("kubectl", "create", "secret", "generic", azureCredentialsSecret, "-n", namespace,
+			"--from-literal=AZURE_MANAGEMENT_TOKEN="+managementToken)
+		_, err = utils.Run(cmd)
+		Expect(err).NotTo(HaveOccurred(), "Failed to create the credentials Secret")
+		cmd = exec.Command("kubectl", "set", "env", "deployment/azure-apim-operator-controller-manager", "-n", namespace,
+			"--from=secret/"+azureCredentialsSecret)
+		_, err = utils.Run(cmd)
+		Expect(err).NotTo(HaveOccurred(), "Failed to pass the token to the controller-manager")
+		cmd = exec.Command("kubectl", "set", "env", "deployment/azure-apim-operator-controller-manager", "-n", namespace,
+			"AZURE_MANAGEMENT_ENDPOINT="+azureManagementURL, "AZURE_CLIENT_ID=e2e", "AZURE_TENANT_ID=e2e")
+		_, err = utils.Run(cmd)
+		Expect(err).NotTo(HaveOccurred(), "Failed to configure the controller-manager")
+		cmd = exec.Command("kubectl", "rollout", "status", "deployment/azure-apim-operator-controller-manager",
+			"-n", namespace, "--timeout=2m")
+		_, err = utils.Run(cmd)
+		Expect(err).NotTo(HaveOccurred(), "Controller-manager did not roll out")
+	})
+
+	// After all tests have been executed, remove what the tests created in APIM, since the operator leaves
+	// products and tags in place when their custom resources are deleted, and tear down the controller. The
+	// APIMAPI finalizer deletes the API; it is deleted here as well in case the finalizer couldn't.
+	AfterAll(func() {
+		if managementToken == "" {
+			return
+		}
+
+		By("deleting the custom resources")
+		for _, kind := range []string{"apimapi", "apimproduct", "apimtag"} {
+			cmd := exec.Command("kubectl", "delete", kind, "--all", "-n", fakeAPIMNamespace)
+			_, _ = utils.Run(cmd)
+		}
+		cmd := exec.Command("kubectl", "delete", "apimservice", apimServiceName, "-n", namespace)
+		_, _ = utils.Run(cmd)
+
+		By("deleting the API, product and tag from APIM")
+		for _, path := range []string{
+			servicePath + "/apis/" + apiID + "?deleteRevisions=true",
+			servicePath + "/products/" + productID + "?deleteSubscriptions=true",
+			servicePath + "/tags/" + tagID,
+		} {
+			status, body, err := azureManagementRequest(http.MethodDelete, path, managementToken)
+			if err != nil || (status != http.StatusOK && status != http.StatusNoContent && status != http.StatusNotFound) {
+				_, _ = fmt.Fprintf(GinkgoWriter, "WARNING: failed to delete %s: %d %s %v\n", path, status, body, err)
+			}
+		}
+
+		By("removing the workload")
+		cmd = exec.Command("kubectl", "delete", "ns", fakeAPIMNamespace)
+		_, _ = utils.Run(cmd)
+
+		By("undeploying the controller-manager")
+		cmd = exec.Command("make", "undeploy")
+		_, _ = utils.Run(cmd)
+
+		By("uninstalling CRDs")
+		cmd = exec.Command("make", "uninstall")
+		_, _ = utils.Run(cmd)
+
+		By("removing manager namespace")
+		cmd = exec.Command("kubectl", "delete", "ns", namespace)
+		_, _ = utils.Run(cmd)
+	})
+
+	// After a failed test, collect the controller logs for debugging.
+	AfterEach(func() {
+		if CurrentSpecReport().Failed() {
+			By("Fetching controller manager logs")
+			cmd := exec.Command("kubectl", "logs", "deployment/azure-apim-operator-controller-manager", "-n", namespace)
+			controllerLogs, err := utils.Run(cmd)
+			if err == nil {
+				_, _ = fmt.Fprintf(GinkgoWriter, "Controller logs:\n %s", controllerLogs)
+			} else {
+				_, _ = fmt.Fprintf(GinkgoWriter, "Failed to get Controller logs: %s", err)
+			}
+		}
+	})
+
+	// APIM applies changes more slowly than the fake, especially product and tag assignments.
+	SetDefaultEventuallyTimeout(10 * time.Minute)
+	SetDefaultEventuallyPollingInterval(5 * time.Second)
+
+	It("should create the product and tag in APIM", func() {
+		By("creating the APIMService, APIMProduct and APIMTag")
+		manifests := fmt.Sprintf(`apiVersion: apim.operator.io/v1
+kind: APIMService
+metadata:
+  name: %[1]s
+  namespace: %[2]s
+spec:
+  name: %[3]s
+  resourceGroup: %[4]s
+  subscription: %[5]s
+---
+apiVersion: apim.operator.io/v1
+kind: APIMProduct
+metadata:
+  name: %[7]s
+  namespace: %[6]s
+spec:
+  apimService: %[1]s
+  productId: %[7]s
+  displayName: E2E Product %[9]s
+  published: true
+---
+apiVersion: apim.operator.io/v1
+kind: APIMTag
+metadata:
+  name: %[8]s
+  namespace: %[6]s
+spec:
+  apimService: %[1]s
+  tagId: %[8]s
+  displayName: E2E Tag %[9]s
+`, apimServiceName, namespace, azureAPIMService, azureResourceGroup, azureSubscriptionID,
+			fakeAPIMNamespace, productID, tagID, suffix)
+		cmd := exec.Command("kubectl", "apply", "-f", "-")
+		cmd.Stdin = strings.NewReader(manifests)
+		_, err := utils.Run(cmd)
+		Expect(err).NotTo(HaveOccurred(), "Failed to create the APIM resources")
+
+		By("waiting for the product and tag to exist in APIM")
+		for _, path := range []string{servicePath + "/products/" + productID, servicePath + "/tags/" + tagID} {
+			verifyExists := func(g Gomega) {
+				status, body, err := azureManagementRequest(http.MethodGet, path, managementToken)
+				g.Expect(err).NotTo(HaveOccurred())
+				g.Expect(status).To(Equal(http.StatusOK), body)
+			}
+			Eventually(verifyExists).Should(Succeed())
+		}
+	})
+
+	It("should import the API, assign its product and tag and report it ready", func() {
+		By("creating an APIMAPI that targets the workload")
+		apimAPIYAML := fmt.Sprintf(`apiVersion: apim.operator.io/v1
+kind: APIMAPI
+metadata:
+  name: %[1]s
+  namespace: %[2]s
+spec:
+  APIID: %[3]s
+  apimService: %[4]s
+  routePrefix: %[5]s
+  serviceUrl: %[6]s
+  openApiDefinitionUrl: %[6]s/swagger/v1/swagger.json
+  subscriptionRequired: true
+  productIds:
+  - %[7]s
+  tagIds:
+  - %[8]s
+`, fakeAPIMName, fakeAPIMNamespace, apiID, apimServiceName, routePrefix, fakeAPIMURL, productID, tagID)
+		cmd := exec.Command("kubectl", "apply", "-f", "-")
+		cmd.Stdin = strings.NewReader(apimAPIYAML)
+		_, err := utils.Run(cmd)
+		Expect(err).NotTo(HaveOccurred(), "Failed to create APIMAPI")
+
+		By("waiting for the APIMAPI to become Ready")
+		verifyReady := func(g Gomega) {
+			status, reason, err := readyCondition("apimapi", fakeAPIMName, fakeAPIMNamespace)
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(status + "/" + reason).To(Equal("True/Synced"))
+		}
+		Eventually(verifyReady).Should(Succeed())
+
+		By("verifying APIM holds the imported API with the service URL")
+		status, api, err := azureManagementRequest(http.MethodGet, servicePath+"/apis/"+apiID, managementToken)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(status).To(Equal(http.StatusOK), api)
+		var apiDetails struct {
+			Properties struct {
+				Path                 string `json:"path"`
+				ServiceURL           string `json:"serviceUrl"`
+				SubscriptionRequired bool   `json:"subscriptionRequired"`
+			} `json:"properties"`
+		}
+		Expect(json.Unmarshal([]byte(api), &apiDetails)).To(Succeed())
+		Expect(apiDetails.Properties.Path).To(Equal(strings.TrimPrefix(routePrefix, "/")))
+		Expect(apiDetails.Properties.ServiceURL).To(Equal(fakeAPIMURL))
+		Expect(apiDetails.Properties.SubscriptionRequired).To(BeTrue())
+
+		By("verifying the product and tag are assigned to the API")
+		apiPath := servicePath + "/apis/" + apiID
+		status, products, err := azureManagementRequest(http.MethodGet, apiPath+"/products", managementToken)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(status).To(Equal(http.StatusOK), products)
+		Expect(collectionNames(products)).To(ContainElement(productID))
+		status, tags, err := azureManagementRequest(http.MethodGet, apiPath+"/tags", managementToken)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(status).To(Equal(http.StatusOK), tags)
+		Expect(collectionNames(tags)).To(ContainElement(tagID))
+
+		By("verifying the APIMAPI reports the gateway URL")
+		output, err := utils.KubectlJSONPath("{.status.apiHost}", "apimapi", fakeAPIMName, "-n", fakeAPIMNamespace)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(output).To(HavePrefix("https://"))
+		Expect(output).To(HaveSuffix(routePrefix))
+	})
+})
+
+// azureManagementRequest sends a request for an ARM path of the live APIM instance and returns the status
+// code and body. Deletes are unconditional.
+func azureManagementRequest(method, path, token string) (int, string, error) {
+	separator := "?"
+	if strings.Contains(path, "?") {
+		separator = "&"
+	}
+	req, err := http.NewRequest(method, azureManagementURL+path+separator+"api-version=2021-08-01", nil)
+	if err != nil {
+		return 0, "", err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	if method == http.MethodDelete {
+		req.Header.Set("If-Match", "*")
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return 0, "", err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	body, err := io.ReadAll(resp.Body)
+	return resp.StatusCode, string(body), err
+}
+
+// collectionNames returns the names of the entities in an ARM list response, or nil if it isn't one.
+func collectionNames(body string) []string {
+	var list struct {
+		Value []struct {
+			Name string `json:"name"`
+		} `json:"value"`
+	}
+	if err := json.Unmarshal([]byte(body), &list); err != nil {
+		return nil
+	}
+	names := make([]string, 0, len(list.Value))
+	for _, entity := range list.Value {
+		names = append(names, entity.Name)
+	}
+	return names
+}
@@ -40,6 +40,10 @@ var (
 	// projectImage is the name of the image which will be build and loaded
 	// with the code source changes to be tested.
 	projectImage = "example.com/azure-apim-operator:v0.0.1"
+
+	// fakeAPIMImage is the image of the fake Azure Management API the operator is pointed at,
+	// so the import flow runs without Azure credentials.
+	fakeAPIMImage = "example.com/fake-apim:v0.0.1"
 )
 
 // TestE2E runs the end-to-end (e2e) test suite for the project. These tests execute in an isolated,
@@ -58,6 +62,15 @@ var _ = BeforeSuite(func() {
 	_, err := utils.Run(cmd)
 	ExpectWithOffset(1, err).NotTo(HaveOccurred(), "Failed to build the manager(Operator) image")
 
+	By("building the fake APIM image")
+	cmd = exec.Command("docker", "build", "-f", "test/fakeapim/Dockerfile", "-t", fakeAPIMImage, ".")
+	_, err = utils.Run(cmd)
+	ExpectWithOffset(1, err).NotTo(HaveOccurred(), "Failed to build the fake APIM image")
+
+	By("loading the fake APIM image on Kind")
+	err = utils.LoadImageToKindClusterWithName(fakeAPIMImage)
+	ExpectWithOffset(1, err).NotTo(HaveOccurred(), "Failed to load the fake APIM image into Kind")
+
 	// TODO(user): If you want to change the e2e test vendor from Kind, ensure the image is
 	// built and available before running the tests. Also, remove the following block.
 	By("loading the manager(Operator) image on Kind")
@@ -17,7 +17,6 @@ limitations under the License.
 package e2e
 
 import (
-	"encoding/json"
 	"fmt"
 	"os"
 	"os/exec"
@@ -43,6 +42,63 @@ const metricsServiceName = "azure-apim-operator-controller-manager-metrics-servi
 // metricsRoleBindingName is the name of the RBAC that will be created to allow get the metrics data
 const metricsRoleBindingName = "azure-apim-operator-metrics-binding"
 
+// fakeAPIMNamespace is the namespace of the fake APIM server, which doubles as the workload whose API is imported
+const fakeAPIMNamespace = "e2e-fake-apim"
+
+// fakeAPIMName names the fake APIM Deployment and Service, and the APIMAPI that targets them
+const fakeAPIMName = "fake-apim"
+
+// fakeAPIMURL is the in-cluster URL of the fake APIM server
+const fakeAPIMURL = "http://" + fakeAPIMName + "." + fakeAPIMNamespace + ".svc:8080"
+
+// fakeAPIMManifest deploys the fake APIM server. Its ReplicaSets carry the label the operator matches APIMAPIs by.
+var fakeAPIMManifest = fmt.Sprintf(`apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: %[1]s
+  namespace: %[2]s
+spec:
+  replicas: 1
+  selector:
+    matchLabels:
+      app.kubernetes.io/name: %[1]s
+  template:
+    metadata:
+      labels:
+        app.kubernetes.io/name: %[1]s
+    spec:
+      securityContext:
+        runAsNonRoot: true
+        seccompProfile:
+          type: RuntimeDefault
+      containers:
+      - name: fake-apim
+        image: %[3]s
+        imagePullPolicy: IfNotPresent
+        ports:
+        - containerPort: 8080
+        readinessProbe:
+          httpGet:
+            path: /healthz
+            port: 8080
+        securityContext:
+          allowPrivilegeEscalation: false
+          capabilities:
+            drop: ["ALL"]
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: %[1]s
+  namespace: %[2]s
+spec:
+  selector:
+    app.kubernetes.io/name: %[1]s
+  ports:
+  - port: 8080
+    targetPort: 8080
+`, fakeAPIMName, fakeAPIMNamespace, fakeAPIMImage)
+
 var _ = Describe("Manager", Ordered, func() {
 	var controllerPodName string
 
@@ -70,13 +126,43 @@ var _ = Describe("Manager", Ordered, func() {
 		cmd = exec.Command("make", "deploy", fmt.Sprintf("IMG=%s", projectImage))
 		_, err = utils.Run(cmd)
 		Expect(err).NotTo(HaveOccurred(), "Failed to deploy the controller-manager")
+
+		By("deploying the fake APIM server")
+		cmd = exec.Command("kubectl", "create", "ns", fakeAPIMNamespace)
+		_, err = utils.Run(cmd)
+		Expect(err).NotTo(HaveOccurred(), "Failed to create the fake APIM namespace")
+		cmd = exec.Command("kubectl", "apply", "-f", "-")
+		cmd.Stdin = strings.NewReader(fakeAPIMManifest)
+		_, err = utils.Run(cmd)
+		Expect(err).NotTo(HaveOccurred(), "Failed to deploy the fake APIM server")
+		cmd = exec.Command("kubectl", "rollout", "status", "deployment/"+fakeAPIMName,
+			"-n", fakeAPIMNamespace, "--timeout=2m")
+		_, err = utils.Run(cmd)
+		Expect(err).NotTo(HaveOccurred(), "Fake APIM server did not become ready")
+
+		By("pointing the controller-manager at the fake APIM server")
+		// The fake accepts any token, so the identity variables only have to be set.
+		cmd = exec.Command("kubectl", "set", "env", "deployment/azure-apim-operator-controller-manager", "-n", namespace,
+			"AZURE_MANAGEMENT_ENDPOINT="+fakeAPIMURL, "AZURE_MANAGEMENT_TOKEN=e2e",
+			"AZURE_CLIENT_ID=e2e", "AZURE_TENANT_ID=e2e")
+		_, err = utils.Run(cmd)
+		Expect(err).NotTo(HaveOccurred(), "Failed to configure the controller-manager")
+		cmd = exec.Command("kubectl", "rollout", "status", "deployment/azure-apim-operator-controller-manager",
+			"-n", namespace, "--timeout=2m")
+		_, err = utils.Run(cmd)
+		Expect(err).NotTo(HaveOccurred(), "Controller-manager did not roll out")
 	})
 
 	// After all tests have been executed, clean up by undeploying the controller, uninstalling CRDs,
 	// and deleting the namespace.
 	AfterAll(func() {
-		By("cleaning up the curl pod for metrics")
-		cmd := exec.Command("kubectl", "delete", "pod", "curl-metrics", "-n", namespace)
+		By("cleaning up the curl pods for metrics")
+		cmd := exec.Command("kubectl", "delete", "pod", "curl-metrics", "curl-metrics-import", "-n", namespace,
+			"--ignore-not-found")
+		_, _ = utils.Run(cmd)
+
+		By("removing the fake APIM server")
+		cmd = exec.Command("kubectl", "delete", "ns", fakeAPIMNamespace)
 		_, _ = utils.Run(cmd)
 
 		By("undeploying the controller-manager")
@@ -142,31 +228,32 @@ var _ = Describe("Manager", Ordered, func() {
 		It("should run successfully", func() {
 			By("validating that the controller-manager pod is running as expected")
 			verifyControllerUp := func(g Gomega) {
-				// Get the name of the controller-manager pod
-				cmd := exec.Command("kubectl", "get",
-					"pods", "-l", "control-plane=controller-manager",
-					"-o", "go-template={{ range .items }}"+
-						"{{ if not .metadata.deletionTimestamp }}"+
-						"{{ .metadata.name }}"+
-						"{{ \"\\n\" }}{{ end }}{{ end }}",
-					"-n", namespace,
-				)
-
-				podOutput, err := utils.Run(cmd)
+				// Get the controller-manager pod that is not being deleted
+				var pods struct {
+					Items []struct {
+						Metadata struct {
+							Name              string  `json:"name"`
+							DeletionTimestamp *string `json:"deletionTimestamp"`
+						} `json:"metadata"`
+						Status struct {
+							Phase string `json:"phase"`
+						} `json:"status"`
+					} `json:"items"`
+				}
+				err := utils.KubectlGetJSON(&pods, "pods", "-l", "control-plane=controller-manager", "-n", namespace)
 				g.Expect(err).NotTo(HaveOccurred(), "Failed to retrieve controller-manager pod information")
-				podNames := utils.GetNonEmptyLines(podOutput)
-				g.Expect(podNames).To(HaveLen(1), "expected 1 controller pod running")
-				controllerPodName = podNames[0]
+				var phases []string
+				for _, pod := range pods.Items {
+					if pod.Metadata.DeletionTimestamp == nil {
+						controllerPodName = pod.Metadata.Name
+						phases = append(phases, pod.Status.Phase)
+					}
+				}
+				g.Expect(phases).To(HaveLen(1), "expected 1 controller pod running")
 				g.Expect(controllerPodName).To(ContainSubstring("controller-manager"))
 
 				// Validate the pod's status
-				cmd = exec.Command("kubectl", "get",
-					"pods", controllerPodName, "-o", "jsonpath={.status.phase}",
-					"-n", namespace,
-				)
-				output, err := utils.Run(cmd)
-				g.Expect(err).NotTo(HaveOccurred())
-				g.Expect(output).To(Equal("Running"), "Incorrect controller-manager pod status")
+				g.Expect(phases[0]).To(Equal("Running"), "Incorrect controller-manager pod status")
 			}
 			Eventually(verifyControllerUp).Should(Succeed())
 		})
@@ -210,49 +297,7 @@ var _ = Describe("Manager", Ordered, func() {
 			}
 			Eventually(verifyMetricsServerStarted).Should(Succeed())
 
-			By("creating the curl-metrics pod to access the metrics endpoint")
-			cmd = exec.Command("kubectl", "run", "curl-metrics", "--restart=Never",
-				"--namespace", namespace,
-				"--image=curlimages/curl:latest",
-				"--overrides",
-				fmt.Sprintf(`{
-					"spec": {
-						"containers": [{
-							"name": "curl",
-							"image": "curlimages/curl:latest",
-							"command": ["/bin/sh", "-c"],
-							"args": ["curl -v -k -H 'Authorization: Bearer %s' https://%s.%s.svc.cluster.local:8443/metrics"],
-							"securityContext": {
-								"allowPrivilegeEscalation": false,
-								"capabilities": {
-									"drop": ["ALL"]
-								},
-								"runAsNonRoot": true,
-								"runAsUser": 1000,
-								"seccompProfile": {
-									"type": "RuntimeDefault"
-								}
-							}
-						}],
-						"serviceAccount": "%s"
-					}
-				}`, token, metricsServiceName, namespace, serviceAccountName))
-			_, err = utils.Run(cmd)
-			Expect(err).NotTo(HaveOccurred(), "Failed to create curl-metrics pod")
-
-			By("waiting for the curl-metrics pod to complete.")
-			verifyCurlUp := func(g Gomega) {
-				cmd := exec.Command("kubectl", "get", "pods", "curl-metrics",
-					"-o", "jsonpath={.status.phase}",
-					"-n", namespace)
-				output, err := utils.Run(cmd)
-				g.Expect(err).NotTo(HaveOccurred())
-				g.Expect(output).To(Equal("Succeeded"), "curl pod in wrong status")
-			}
-			Eventually(verifyCurlUp, 5*time.Minute).Should(Succeed())
-
-			By("getting the metrics by checking curl-metrics logs")
-			metricsOutput := getMetricsOutput()
+			metricsOutput := scrapeMetrics("curl-metrics", token)
 			Expect(metricsOutput).To(ContainSubstring(
 				"controller_runtime_reconcile_total",
 			))
@@ -379,13 +424,11 @@ spec:
 			}
 			Eventually(verifyResourceExists).Should(Succeed())
 
-			By("verifying status is updated (will be Error due to missing Azure credentials)")
+			By("verifying the tag is created in the fake APIM")
 			verifyStatusUpdated := func(g Gomega) {
-				cmd := exec.Command("kubectl", "get", "apimtag", apimTagName, "-n", testNamespace, "-o", "jsonpath={.status.phase}")
-				output, err := utils.Run(cmd)
+				output, err := utils.KubectlJSONPath("{.status.phase}", "apimtag", apimTagName, "-n", testNamespace)
 				g.Expect(err).NotTo(HaveOccurred())
-				// Status should be set (either Error or Created)
-				g.Expect(output).NotTo(BeEmpty())
+				g.Expect(output).To(Equal("Created"))
 			}
 			Eventually(verifyStatusUpdated, 30*time.Second).Should(Succeed())
 		})
@@ -490,13 +533,11 @@ spec:
 			}
 			Eventually(verifyResourceExists).Should(Succeed())
 
-			By("verifying status is updated")
+			By("verifying the product is created in the fake APIM")
 			verifyStatusUpdated := func(g Gomega) {
-				cmd := exec.Command("kubectl", "get", "apimproduct", apimProductName,
-				"-n", testNamespace, "-o", "jsonpath={.status.phase}")
-				output, err := utils.Run(cmd)
+				output, err := utils.KubectlJSONPath("{.status.phase}", "apimproduct", apimProductName, "-n", testNamespace)
 				g.Expect(err).NotTo(HaveOccurred())
-				g.Expect(output).NotTo(BeEmpty())
+				g.Expect(output).To(Equal("Created"))
 			}
 			Eventually(verifyStatusUpdated, 30*time.Second).Should(Succeed())
 		})
@@ -530,7 +571,7 @@ spec:
 
 			By("deleting the APIMProduct resource")
 			cmd = exec.Command("kubectl", "delete", "apimproduct",
-			fmt.Sprintf("%s-delete", apimProductName), "-n", testNamespace)
+				fmt.Sprintf("%s-delete", apimProductName), "-n", testNamespace)
 			_, err = utils.Run(cmd)
 			Expect(err).NotTo(HaveOccurred(), "Failed to delete APIMProduct")
 
@@ -620,9 +661,7 @@ spec:
 
 			By("verifying status is updated")
 			verifyStatusUpdated := func(g Gomega) {
-				cmd := exec.Command("kubectl", "get", "apiminboundpolicy", apimPolicyName,
-				"-n", testNamespace, "-o", "jsonpath={.status.phase}")
-				output, err := utils.Run(cmd)
+				output, err := utils.KubectlJSONPath("{.status.phase}", "apiminboundpolicy", apimPolicyName, "-n", testNamespace)
 				g.Expect(err).NotTo(HaveOccurred())
 				g.Expect(output).NotTo(BeEmpty())
 			}
@@ -656,7 +695,7 @@ spec:
 			By("verifying the resource exists")
 			verifyResourceExists := func(g Gomega) {
 				cmd := exec.Command("kubectl", "get", "apiminboundpolicy",
-				fmt.Sprintf("%s-operation", apimPolicyName), "-n", testNamespace)
+					fmt.Sprintf("%s-operation", apimPolicyName), "-n", testNamespace)
 				output, err := utils.Run(cmd)
 				g.Expect(err).NotTo(HaveOccurred())
 				g.Expect(output).To(ContainSubstring(apimPolicyName))
@@ -664,8 +703,173 @@ spec:
 			Eventually(verifyResourceExists).Should(Succeed())
 		})
 	})
+
+	Context("APIMAPI import against the fake APIM", func() {
+		const apimServiceName = "e2e-apim-service-api"
+		const apiID = "e2e-orders"
+		servicePath := "/subscriptions/e2e-subscription/resourceGroups/e2e-rg" +
+			"/providers/Microsoft.ApiManagement/service/e2e-apim"
+
+		BeforeEach(func() {
+			By("creating the APIMService, APIMProduct and APIMTag the API refers to")
+			manifests := fmt.Sprintf(`apiVersion: apim.operator.io/v1
+kind: APIMService
+metadata:
+  name: %[1]s
+  namespace: %[2]s
+spec:
+  name: e2e-apim
+  resourceGroup: e2e-rg
+  subscription: e2e-subscription
+---
+apiVersion: apim.operator.io/v1
+kind: APIMProduct
+metadata:
+  name: e2e-starter
+  namespace: %[3]s
+spec:
+  apimService: %[1]s
+  productId: e2e-starter
+  displayName: E2E Starter
+  published: true
+---
+apiVersion: apim.operator.io/v1
+kind: APIMTag
+metadata:
+  name: e2e-internal
+  namespace: %[3]s
+spec:
+  apimService: %[1]s
+  tagId: e2e-internal
+  displayName: E2E Internal
+`, apimServiceName, namespace, fakeAPIMNamespace)
+			cmd := exec.Command("kubectl", "apply", "-f", "-")
+			cmd.Stdin = strings.NewReader(manifests)
+			_, err := utils.Run(cmd)
+			Expect(err).NotTo(HaveOccurred(), "Failed to create the APIM dependencies")
+		})
+
+		AfterEach(func() {
+			By("cleaning up the APIMAPI, APIMProduct and APIMTag")
+			for _, kind := range []string{"apimapi", "apimproduct", "apimtag"} {
+				cmd := exec.Command("kubectl", "delete", kind, "--all", "-n", fakeAPIMNamespace)
+				_, _ = utils.Run(cmd)
+			}
+
+			By("cleaning up the APIMService from the operator namespace")
+			cmd := exec.Command("kubectl", "delete", "apimservice", apimServiceName, "-n", namespace)
+			_, _ = utils.Run(cmd)
+		})
+
+		It("should import the API, assign its product and tag and report it ready", func() {
+			By("creating an APIMAPI that targets the fake APIM workload")
+			apimAPIYAML := fmt.Sprintf(`apiVersion: apim.operator.io/v1
+kind: APIMAPI
+metadata:
+  name: %[1]s
+  namespace: %[2]s
+spec:
+  APIID: %[3]s
+  apimService: %[4]s
+  routePrefix: /orders
+  serviceUrl: %[5]s
+  openApiDefinitionUrl: %[5]s/swagger/v1/swagger.json
+  subscriptionRequired: true
+  productIds:
+  - e2e-starter
+  tagIds:
+  - e2e-internal
+`, fakeAPIMName, fakeAPIMNamespace, apiID, apimServiceName, fakeAPIMURL)
+			cmd := exec.Command("kubectl", "apply", "-f", "-")
+			cmd.Stdin = strings.NewReader(apimAPIYAML)
+			_, err := utils.Run(cmd)
+			Expect(err).NotTo(HaveOccurred(), "Failed to create APIMAPI")
+
+			By("waiting for the APIMAPI to become Ready")
+			verifyReady := func(g Gomega) {
+				status, reason, err := readyCondition("apimapi", fakeAPIMName, fakeAPIMNamespace)
+				g.Expect(err).NotTo(HaveOccurred())
+				g.Expect(status + "/" + reason).To(Equal("True/Synced"))
+			}
+			Eventually(verifyReady, 3*time.Minute).Should(Succeed())
+
+			By("verifying the fake APIM holds the imported API with the service URL")
+			api, err := fakeAPIMGet(servicePath + "/apis/" + apiID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(api).To(ContainSubstring(`"path":"orders"`))
+			Expect(api).To(ContainSubstring(`"serviceUrl":"` + fakeAPIMURL + `"`))
+			Expect(api).To(ContainSubstring(`"subscriptionRequired":true`))
+
+			By("verifying the product and tag are assigned to the API")
+			products, err := fakeAPIMGet(servicePath + "/apis/" + apiID + "/products")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(products).To(ContainSubstring(`"name":"e2e-starter"`))
+			tags, err := fakeAPIMGet(servicePath + "/apis/" + apiID + "/tags")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(tags).To(ContainSubstring(`"name":"e2e-internal"`))
+
+			By("verifying the status of the APIMAPI and its APIMAPIDeployment")
+			output, err := utils.KubectlJSONPath("{.status.apiHost}", "apimapi", fakeAPIMName, "-n", fakeAPIMNamespace)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(output).To(ContainSubstring("e2e-apim.azure-api.net"))
+			output, err = utils.KubectlJSONPath("{.items[*].status.phase}", "apimapideployment", "-n", fakeAPIMNamespace)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(output).NotTo(ContainSubstring("Error"))
+
+			By("verifying the import and Azure client metrics report the import")
+			token, err := serviceAccountToken()
+			Expect(err).NotTo(HaveOccurred())
+			metricsOutput := scrapeMetrics("curl-metrics-import", token)
+			service := `apim_service="` + apimServiceName + `"`
+			Expect(metricSamples(metricsOutput, "apim_api_import_total")).To(ContainElement(SatisfyAll(
+				ContainSubstring(service), ContainSubstring(`result="success"`), ContainSubstring(`reason="none"`),
+			)), "no successful import counted")
+			Expect(metricSamples(metricsOutput, "apim_api_import_duration_seconds_count")).To(ContainElement(
+				ContainSubstring(service),
+			), "no import duration observed")
+			Expect(metricSamples(metricsOutput, "apim_resource_last_successful_sync_timestamp")).To(ContainElement(SatisfyAll(
+				ContainSubstring(`kind="APIMAPIDeployment"`), ContainSubstring(`namespace="`+fakeAPIMNamespace+`"`),
+			)), "no successful sync recorded for the APIMAPIDeployment")
+			Expect(metricSamples(metricsOutput, "apim_management_request_duration_seconds_count")).To(ContainElement(SatisfyAll(
+				ContainSubstring(`method="PUT"`), ContainSubstring(`resource_type="service/apis"`),
+			)), "no Azure Management API latency recorded for the import")
+			Expect(metricSamples(metricsOutput, "apim_management_request_duration_seconds_count")).To(ContainElement(SatisfyAll(
+				ContainSubstring(`method="PUT"`), ContainSubstring(`resource_type="service/products/apis"`),
+			)), "no Azure Management API latency recorded for the product assignment")
+		})
+	})
 })
 
+// fakeAPIMGet reads an ARM path from the fake APIM server through the API server's service proxy.
+func fakeAPIMGet(path string) (string, error) {
+	cmd := exec.Command("kubectl", "get", "--raw", fmt.Sprintf("/api/v1/namespaces/%s/services/%s:8080/proxy%s",
+		fakeAPIMNamespace, fakeAPIMName, path))
+	return utils.Run(cmd)
+}
+
+// readyCondition returns the status and reason of the Ready condition of a resource, or empty strings while it has
+// none.
+func readyCondition(resource, name, namespace string) (status, reason string, err error) {
+	var object struct {
+		Status struct {
+			Conditions []struct {
+				Type   string `json:"type"`
+				Status string `json:"status"`
+				Reason string `json:"reason"`
+			} `json:"conditions"`
+		} `json:"status"`
+	}
+	if err := utils.KubectlGetJSON(&object, resource, name, "-n", namespace); err != nil {
+		return "", "", err
+	}
+	for _, condition := range object.Status.Conditions {
+		if condition.Type == "Ready" {
+			return condition.Status, condition.Reason, nil
+		}
+	}
+	return "", "", nil
+}
+
 // serviceAccountToken returns a token for the specified service account in the given namespace.
 // It uses the Kubernetes TokenRequest API to generate a token by directly sending a request
 // and parsing the resulting token from the API response.
@@ -692,13 +896,12 @@ func serviceAccountToken() (string, error) {
 			serviceAccountName,
 		), "-f", tokenRequestFile)
 
-		output, err := cmd.CombinedOutput()
+		result, err := utils.RunCommand(cmd, utils.WithTimeout(30*time.Second))
 		g.Expect(err).NotTo(HaveOccurred())
 
 		// Parse the JSON output to extract the token
 		var token tokenRequest
-		err = json.Unmarshal(output, &token)
-		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(result.JSON(&token)).To(Succeed())
 
 		out = token.Status.Token
 	}
@@ -707,14 +910,64 @@ func serviceAccountToken() (string, error) {
 	return out, err
 }
 
-// getMetricsOutput retrieves and returns the logs from the curl pod used to access the metrics endpoint.
-func getMetricsOutput() string {
-	By("getting the curl-metrics logs")
-	cmd := exec.Command("kubectl", "logs", "curl-metrics", "-n", namespace)
-	metricsOutput, err := utils.Run(cmd)
+// scrapeMetrics runs a curl pod named podName that reads the metrics endpoint with token, and returns the
+// pod's logs, which hold the verbose curl output and the metrics.
+func scrapeMetrics(podName, token string) string {
+	By("creating the " + podName + " pod to access the metrics endpoint")
+	cmd := exec.Command("kubectl", "run", podName, "--restart=Never",
+		"--namespace", namespace,
+		"--image=curlimages/curl:latest",
+		"--overrides",
+		fmt.Sprintf(`{
+			"spec": {
+				"containers": [{
+					"name": "curl",
+					"image": "curlimages/curl:latest",
+					"command": ["/bin/sh", "-c"],
+					"args": ["curl -v -k -H 'Authorization: Bearer %s' https://%s.%s.svc.cluster.local:8443/metrics"],
+					"securityContext": {
+						"allowPrivilegeEscalation": false,
+						"capabilities": {
+							"drop": ["ALL"]
+						},
+						"runAsNonRoot": true,
+						"runAsUser": 1000,
+						"seccompProfile": {
+							"type": "RuntimeDefault"
+						}
+					}
+				}],
+				"serviceAccount": "%s"
+			}
+		}`, token, metricsServiceName, namespace, serviceAccountName))
+	_, err := utils.Run(cmd)
+	Expect(err).NotTo(HaveOccurred(), "Failed to create the "+podName+" pod")
+
+	By("waiting for the " + podName + " pod to complete")
+	verifyCurlUp := func(g Gomega) {
+		output, err := utils.KubectlJSONPath("{.status.phase}", "pods", podName, "-n", namespace)
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(output).To(Equal("Succeeded"), "curl pod in wrong status")
+	}
+	Eventually(verifyCurlUp, 5*time.Minute).Should(Succeed())
+
+	By("getting the " + podName + " logs")
+	result, err := utils.Kubectl("logs", podName, "-n", namespace)
 	Expect(err).NotTo(HaveOccurred(), "Failed to retrieve logs from curl pod")
-	Expect(metricsOutput).To(ContainSubstring("< HTTP/1.1 200 OK"))
-	return metricsOutput
+	Expect(result.Stdout).To(ContainSubstring("< HTTP/1.1 200 OK"))
+	return result.Stdout
+}
+
+// metricSamples returns the sample lines of the metric name in the Prometheus text output, e.g.
+// `apim_api_import_total{apim_service="a",...} 1`.
+func metricSamples(output, name string) []string {
+	var samples []string
+	for _, line := range utils.GetNonEmptyLines(output) {
+		if strings.HasPrefix(line, name+"{") || strings.HasPrefix(line, name+" ") {
+			samples = append(samples, line)
+		}
+	}
+	return samples
 }
 
 // tokenRequest is a simplified representation of the Kubernetes TokenRequest API response,
@@ -0,0 +1,68 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package main is the fake APIM server of the e2e tests. It serves the fake Azure Management API of the
+// apimtest package, which the operator is pointed at with --azure-management-endpoint, and doubles as the
+// workload whose OpenAPI definition the operator imports.
+package main
+
+import (
+	"flag"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/hedinit/azure-apim-operator/internal/apimtest"
+)
+
+// definition is the OpenAPI definition the fake serves as its own.
+const definition = `{
+  "openapi": "3.0.1",
+  "info": {"title": "Fake APIM e2e", "version": "1.0"},
+  "paths": {
+    "/orders/{id}": {
+      "get": {
+        "operationId": "get-order",
+        "parameters": [{"name": "id", "in": "path", "required": true, "schema": {"type": "string"}}],
+        "responses": {"200": {"description": "The order"}}
+      }
+    }
+  }
+}
+`
+
+func main() {
+	addr := flag.String("addr", ":8080", "Address to listen on.")
+	sku := flag.String("sku", "", "Pricing tier reported for every APIM service, e.g. Consumption. Defaults to Developer.")
+	flag.Parse()
+
+	fake := apimtest.NewServer()
+	fake.SetSKU(*sku)
+	mux := http.NewServeMux()
+	mux.Handle("/subscriptions", fake)
+	mux.Handle("/subscriptions/", fake)
+	mux.HandleFunc("/swagger/v1/swagger.json", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(definition))
+	})
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	server := &http.Server{Addr: *addr, Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+	log.Printf("fake APIM listening on %s", *addr)
+	log.Fatal(server.ListenAndServe())
+}
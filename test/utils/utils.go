@@ -19,10 +19,13 @@ package utils
 import (
 	"bufio"
 	"bytes"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
 	"strings"
+	"time"
 
 	. "github.com/onsi/ginkgo/v2" //nolint:golint,revive
 )
@@ -40,8 +43,111 @@ func warnError(err error) {
 	_, _ = fmt.Fprintf(GinkgoWriter, "warning: %v\n", err)
 }
 
+// DefaultTimeout is how long a command may run before it is killed, unless WithTimeout sets another limit.
+// It keeps a stuck kubectl or kind from hanging the suite until the go test timeout.
+const DefaultTimeout = 5 * time.Minute
+
+// Result is the outcome of a command run with RunCommand.
+type Result struct {
+	// Stdout is what the command wrote to its standard output.
+	Stdout string
+	// Stderr is what the command wrote to its standard error.
+	Stderr string
+	// ExitCode is the exit code of the command, or -1 if it did not exit on its own.
+	ExitCode int
+}
+
+// JSON decodes the standard output of the command into v.
+func (r Result) JSON(v interface{}) error {
+	if err := json.Unmarshal([]byte(r.Stdout), v); err != nil {
+		return fmt.Errorf("decoding the command output: %w: %s", err, r.Stdout)
+	}
+	return nil
+}
+
+// Lines returns the non-empty lines of the standard output of the command.
+func (r Result) Lines() []string {
+	return GetNonEmptyLines(r.Stdout)
+}
+
+// RunOption configures how RunCommand runs a command.
+type RunOption func(*runOptions)
+
+type runOptions struct {
+	timeout time.Duration
+	env     []string
+}
+
+// WithTimeout kills the command if it runs longer than timeout instead of DefaultTimeout.
+func WithTimeout(timeout time.Duration) RunOption {
+	return func(o *runOptions) { o.timeout = timeout }
+}
+
+// WithEnv adds environment variables in the form KEY=value to the environment of the command.
+func WithEnv(env ...string) RunOption {
+	return func(o *runOptions) { o.env = append(o.env, env...) }
+}
+
 // Run executes the provided command within this context
 func Run(cmd *exec.Cmd) (string, error) {
+	var output bytes.Buffer
+	cmd.Stdout = &output
+	cmd.Stderr = &output
+	command := prepare(cmd, runOptions{})
+	if err := runWithTimeout(cmd, DefaultTimeout); err != nil {
+		return output.String(), fmt.Errorf("%s failed with error: (%v) %s", command, err, output.String())
+	}
+
+	return output.String(), nil
+}
+
+// RunCommand executes the provided command like Run, but keeps its standard output and standard error apart so
+// that the output can be parsed, e.g. with Result.JSON. The error of a failed command includes its standard error.
+func RunCommand(cmd *exec.Cmd, opts ...RunOption) (Result, error) {
+	options := runOptions{timeout: DefaultTimeout}
+	for _, opt := range opts {
+		opt(&options)
+	}
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	command := prepare(cmd, options)
+	err := runWithTimeout(cmd, options.timeout)
+
+	result := Result{Stdout: stdout.String(), Stderr: stderr.String(), ExitCode: -1}
+	if cmd.ProcessState != nil {
+		result.ExitCode = cmd.ProcessState.ExitCode()
+	}
+	if err != nil {
+		return result, fmt.Errorf("%s failed with error: (%v) %s", command, err, result.Stderr)
+	}
+	return result, nil
+}
+
+// Kubectl runs kubectl with args.
+func Kubectl(args ...string) (Result, error) {
+	return RunCommand(exec.Command("kubectl", args...))
+}
+
+// KubectlGetJSON runs kubectl get with args and decodes the resources it prints as JSON into v.
+func KubectlGetJSON(v interface{}, args ...string) error {
+	result, err := Kubectl(append(append([]string{"get"}, args...), "-o", "json")...)
+	if err != nil {
+		return err
+	}
+	return result.JSON(v)
+}
+
+// KubectlJSONPath runs kubectl get with args and returns the output of the JSONPath expression, e.g.
+// "{.status.phase}", without surrounding whitespace.
+func KubectlJSONPath(expression string, args ...string) (string, error) {
+	result, err := Kubectl(append(append([]string{"get"}, args...), "-o", "jsonpath="+expression)...)
+	return strings.TrimSpace(result.Stdout), err
+}
+
+// prepare runs cmd in the project directory with the environment of the tests and options.env, and logs it.
+// It returns the command line.
+func prepare(cmd *exec.Cmd, options runOptions) string {
 	dir, _ := GetProjectDir()
 	cmd.Dir = dir
 
@@ -49,15 +155,32 @@ func Run(cmd *exec.Cmd) (string, error) {
 		_, _ = fmt.Fprintf(GinkgoWriter, "chdir dir: %s\n", err)
 	}
 
-	cmd.Env = append(os.Environ(), "GO111MODULE=on")
+	cmd.Env = append(append(os.Environ(), "GO111MODULE=on"), options.env...)
 	command := strings.Join(cmd.Args, " ")
 	_, _ = fmt.Fprintf(GinkgoWriter, "running: %s\n", command)
-	output, err := cmd.CombinedOutput()
-	if err != nil {
-		return string(output), fmt.Errorf("%s failed with error: (%v) %s", command, err, string(output))
+	return command
+}
+
+// runWithTimeout runs cmd and kills it once timeout has passed.
+func runWithTimeout(cmd *exec.Cmd, timeout time.Duration) error {
+	// A killed kubectl may leave children holding its output open; don't wait for them.
+	cmd.WaitDelay = 10 * time.Second
+	if err := cmd.Start(); err != nil {
+		return err
 	}
+	done := make(chan error, 1)
+	go func() { done <- cmd.Wait() }()
 
-	return string(output), nil
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case err := <-done:
+		return err
+	case <-timer.C:
+		_ = cmd.Process.Kill()
+		<-done
+		return errors.New("timed out after " + timeout.String())
+	}
 }
 
 // InstallPrometheusOperator installs the prometheus Operator to be used to export the enabled metrics.
@@ -0,0 +1,36 @@
+package utils
+
+import (
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRunCommand(t *testing.T) {
+	result, err := RunCommand(exec.Command("sh", "-c", `echo "{\"name\":\"$NAME\"}"; echo warning >&2`),
+		WithEnv("NAME=orders"))
+	if err != nil {
+		t.Fatalf("RunCommand() error = %v", err)
+	}
+	if result.Stderr != "warning\n" || result.ExitCode != 0 {
+		t.Errorf("RunCommand() = %+v, want the warning on stderr and exit code 0", result)
+	}
+	var object struct {
+		Name string `json:"name"`
+	}
+	if err := result.JSON(&object); err != nil || object.Name != "orders" {
+		t.Errorf("JSON() = %+v, %v, want name orders", object, err)
+	}
+
+	result, err = RunCommand(exec.Command("sh", "-c", "echo broken >&2; exit 3"))
+	if err == nil || !strings.Contains(err.Error(), "broken") || result.ExitCode != 3 {
+		t.Errorf("failing command: RunCommand() = %+v, %v, want exit code 3 and stderr in the error", result, err)
+	}
+
+	start := time.Now()
+	_, err = RunCommand(exec.Command("sleep", "30"), WithTimeout(100*time.Millisecond))
+	if err == nil || !strings.Contains(err.Error(), "timed out") || time.Since(start) > 10*time.Second {
+		t.Errorf("stuck command: RunCommand() error = %v after %s, want a timeout", err, time.Since(start))
+	}
+}